@@ -90,6 +90,17 @@ func (c *Client) Cancel(arg params.Entities) (params.ActionResults, error) {
 	return results, err
 }
 
+// SetPinned pins or unpins the results of the given actions. Pinned
+// action results are never pruned.
+func (c *Client) SetPinned(arg params.ActionPins) (params.ErrorResults, error) {
+	results := params.ErrorResults{}
+	if c.BestAPIVersion() < 4 {
+		return results, errors.NotSupportedf("pinning action results")
+	}
+	err := c.facade.FacadeCall("SetPinned", arg, &results)
+	return results, err
+}
+
 // applicationsCharmActions is a batched query for the charm.Actions for a slice
 // of applications by Entity.
 func (c *Client) applicationsCharmActions(arg params.Entities) (params.ApplicationsCharmActionsResults, error) {
@@ -669,6 +669,38 @@ func (c *Client) GetConstraints(applications ...string) ([]constraints.Value, er
 	return allConstraints, nil
 }
 
+// UnitsState returns the key/value state persisted by the charm for
+// each of the given units.
+func (c *Client) UnitsState(units ...string) ([]map[string]string, error) {
+	if c.BestAPIVersion() < 9 {
+		return nil, errors.NotSupportedf("retrieving unit state")
+	}
+	var args params.Entities
+	for _, unit := range units {
+		if !names.IsValidUnit(unit) {
+			return nil, errors.NotValidf("unit name %q", unit)
+		}
+		args.Entities = append(args.Entities,
+			params.Entity{names.NewUnitTag(unit).String()})
+	}
+	var results params.UnitStateResults
+	err := c.facade.FacadeCall("UnitsState", args, &results)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != len(units) {
+		return nil, errors.Errorf("expected %d results, got %d", len(units), len(results.Results))
+	}
+	allState := make([]map[string]string, len(units))
+	for i, result := range results.Results {
+		if result.Error != nil {
+			return nil, errors.Annotatef(result.Error, "unable to get state for %q", units[i])
+		}
+		allState[i] = result.State
+	}
+	return allState, nil
+}
+
 // SetConstraints specifies the constraints for the given application.
 func (c *Client) SetConstraints(application string, constraints constraints.Value) error {
 	params := params.SetConstraints{
@@ -891,6 +891,43 @@ func (s *applicationSuite) TestGetConstraints(c *gc.C) {
 	})
 }
 
+func (s *applicationSuite) TestUnitsState(c *gc.C) {
+	client := application.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string, version int, id, request string, a, response interface{}) error {
+				c.Assert(request, gc.Equals, "UnitsState")
+				c.Assert(a, jc.DeepEquals, params.Entities{
+					Entities: []params.Entity{
+						{"unit-foo-0"}, {"unit-bar-1"},
+					}})
+
+				result, ok := response.(*params.UnitStateResults)
+				c.Assert(ok, jc.IsTrue)
+				result.Results = []params.UnitStateResult{
+					{State: map[string]string{"one": "two"}}, {},
+				}
+				return nil
+			},
+		),
+		BestVersion: 9,
+	})
+
+	results, err := client.UnitsState("foo/0", "bar/1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []map[string]string{
+		{"one": "two"}, nil,
+	})
+}
+
+func (s *applicationSuite) TestUnitsStateNotSupported(c *gc.C) {
+	client := newClient(func(objType string, version int, id, request string, a, response interface{}) error {
+		c.Fatalf("unexpected API call")
+		return nil
+	})
+	_, err := client.UnitsState("foo/0")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
 func (s *applicationSuite) TestGetConstraintsError(c *gc.C) {
 	fooConstraints := constraints.MustParse("mem=4G")
 
@@ -11,13 +11,18 @@ import (
 
 // Create sends a request to create a backup of juju's state.  It
 // returns the metadata associated with the resulting backup and a
-// filename for download.
-func (c *Client) Create(notes string, keepCopy, noDownload bool) (*params.BackupsMetadataResult, error) {
+// filename for download. If publicKey is not empty, the backup archive
+// is encrypted with that PEM-encoded RSA public key.
+func (c *Client) Create(notes string, keepCopy, noDownload bool, publicKey string) (*params.BackupsMetadataResult, error) {
+	if publicKey != "" && c.BestAPIVersion() < 3 {
+		return nil, errors.NotSupportedf("encrypting backups with a supplied key on this controller")
+	}
 	var result params.BackupsMetadataResult
 	args := params.BackupsCreateArgs{
 		Notes:      notes,
 		KeepCopy:   keepCopy,
 		NoDownload: noDownload,
+		PublicKey:  publicKey,
 	}
 
 	if err := c.facade.FacadeCall("Create", args, &result); err != nil {
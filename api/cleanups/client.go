@@ -0,0 +1,57 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package cleanups provides a client for the Cleanups facade, used to
+// list the cleanups queued in a model and to force or cancel them.
+package cleanups
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client provides access to the Cleanups API facade.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient returns a new Cleanups client.
+func NewClient(caller base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(caller, "Cleanups")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// ListCleanups returns the cleanups queued in the model.
+func (c *Client) ListCleanups() ([]params.ModelCleanup, error) {
+	var result params.ModelCleanups
+	if err := c.facade.FacadeCall("ListCleanups", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Results, nil
+}
+
+// ForceCleanups runs the cleanups with the specified ids immediately.
+func (c *Client) ForceCleanups(ids ...string) error {
+	return c.call("ForceCleanups", ids)
+}
+
+// CancelCleanups removes the cleanups with the specified ids without
+// running them.
+func (c *Client) CancelCleanups(ids ...string) error {
+	return c.call("CancelCleanups", ids)
+}
+
+func (c *Client) call(request string, ids []string) error {
+	args := params.CleanupIds{Ids: ids}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall(request, args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	if len(results.Results) != len(ids) {
+		return errors.Errorf("expected %d results, got %d", len(ids), len(results.Results))
+	}
+	return results.Combine()
+}
@@ -0,0 +1,83 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cleanups_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/cleanups"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type clientSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&clientSuite{})
+
+func (s *clientSuite) TestListCleanups(c *gc.C) {
+	var called bool
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, args, response interface{}) error {
+		called = true
+		c.Check(objType, gc.Equals, "Cleanups")
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "ListCleanups")
+		c.Check(args, gc.IsNil)
+		*(response.(*params.ModelCleanups)) = params.ModelCleanups{
+			Results: []params.ModelCleanup{{
+				Id:        "5c9d1e8b",
+				Kind:      "dyingMachine",
+				Target:    "3",
+				Attempts:  2,
+				LastError: "boom",
+			}},
+		}
+		return nil
+	})
+	results, err := cleanups.NewClient(apiCaller).ListCleanups()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+	c.Assert(results, jc.DeepEquals, []params.ModelCleanup{{
+		Id:        "5c9d1e8b",
+		Kind:      "dyingMachine",
+		Target:    "3",
+		Attempts:  2,
+		LastError: "boom",
+	}})
+}
+
+func (s *clientSuite) changeCaller(c *gc.C, expectRequest string, called *bool) basetesting.APICallerFunc {
+	return basetesting.APICallerFunc(func(objType string, version int, id, request string, args, response interface{}) error {
+		*called = true
+		c.Check(objType, gc.Equals, "Cleanups")
+		c.Check(request, gc.Equals, expectRequest)
+		c.Check(args, jc.DeepEquals, params.CleanupIds{Ids: []string{"5c9d1e8b", "bad"}})
+		*(response.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{
+				{},
+				{Error: &params.Error{Code: params.CodeNotFound, Message: `cleanup "bad" not found`}},
+			},
+		}
+		return nil
+	})
+}
+
+func (s *clientSuite) TestForceCleanups(c *gc.C) {
+	var called bool
+	client := cleanups.NewClient(s.changeCaller(c, "ForceCleanups", &called))
+	err := client.ForceCleanups("5c9d1e8b", "bad")
+	c.Assert(err, gc.ErrorMatches, `cleanup "bad" not found`)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *clientSuite) TestCancelCleanups(c *gc.C) {
+	var called bool
+	client := cleanups.NewClient(s.changeCaller(c, "CancelCleanups", &called))
+	err := client.CancelCleanups("5c9d1e8b", "bad")
+	c.Assert(err, gc.ErrorMatches, `cleanup "bad" not found`)
+	c.Assert(called, jc.IsTrue)
+}
@@ -11,17 +11,17 @@ package api
 // New facades should start at 1.
 // Facades that existed before versioning start at 0.
 var facadeVersions = map[string]int{
-	"Action":                       3,
+	"Action":                       4,
 	"ActionPruner":                 1,
 	"Agent":                        2,
 	"AgentTools":                   1,
 	"AllModelWatcher":              2,
 	"AllWatcher":                   1,
 	"Annotations":                  2,
-	"Application":                  8,
+	"Application":                  9,
 	"ApplicationOffers":            2,
 	"ApplicationScaler":            1,
-	"Backups":                      2,
+	"Backups":                      3,
 	"Block":                        2,
 	"Bundle":                       2,
 	"CAASAgent":                    1,
@@ -32,6 +32,7 @@ var facadeVersions = map[string]int{
 	"CharmRevisionUpdater":         2,
 	"Charms":                       2,
 	"Cleaner":                      2,
+	"Cleanups":                     1,
 	"Client":                       2,
 	"Cloud":                        3,
 	"Controller":                   5,
@@ -47,12 +48,13 @@ var facadeVersions = map[string]int{
 	"FilesystemAttachmentsWatcher": 2,
 	"Firewaller":                   5,
 	"FirewallRules":                1,
-	"HighAvailability":             2,
+	"HighAvailability":             3,
 	"HostKeyReporter":              1,
 	"ImageManager":                 2,
 	"ImageMetadata":                3,
 	"ImageMetadataManager":         1,
 	"InstancePoller":               3,
+	"InstanceTagger":               1,
 	"KeyManager":                   1,
 	"KeyUpdater":                   1,
 	"LeadershipService":            2,
@@ -72,7 +74,9 @@ var facadeVersions = map[string]int{
 	"MigrationMinion":              1,
 	"MigrationStatusWatcher":       1,
 	"MigrationTarget":              1,
+	"ModelBackups":                 1,
 	"ModelConfig":                  2,
+	"ModelConsistency":             1,
 	"ModelManager":                 5,
 	"ModelUpgrader":                1,
 	"NotifyWatcher":                1,
@@ -82,6 +86,7 @@ var facadeVersions = map[string]int{
 	"Pinger":                       1,
 	"Provisioner":                  7,
 	"ProxyUpdater":                 2,
+	"RaftCluster":                  1,
 	"Reboot":                       2,
 	"RelationStatusWatcher":        1,
 	"RelationUnitsWatcher":         1,
@@ -100,7 +105,7 @@ var facadeVersions = map[string]int{
 	"Subnets":                      2,
 	"Undertaker":                   1,
 	"UnitAssigner":                 1,
-	"Uniter":                       9,
+	"Uniter":                       10,
 	"Upgrader":                     1,
 	"UpgradeSeries":                1,
 	"UserManager":                  2,
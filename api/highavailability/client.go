@@ -55,6 +55,38 @@ func (c *Client) EnableHA(
 	return result.Result, nil
 }
 
+// EnableReadReplicas ensures the controller has the given number of
+// read replica controllers, which serve read-only API requests from a
+// mongo secondary.
+func (c *Client) EnableReadReplicas(
+	numReplicas int, cons constraints.Value, placement []string,
+) (params.ControllersChanges, error) {
+	if c.BestAPIVersion() < 3 {
+		return params.ControllersChanges{}, errors.NotSupportedf("read replicas on this controller")
+	}
+
+	var results params.ControllersChangeResults
+	arg := params.ReadReplicasSpecs{
+		Specs: []params.ReadReplicasSpec{{
+			NumReplicas: numReplicas,
+			Constraints: cons,
+			Placement:   placement,
+		}}}
+
+	err := c.facade.FacadeCall("EnableReadReplicas", arg, &results)
+	if err != nil {
+		return params.ControllersChanges{}, err
+	}
+	if len(results.Results) != 1 {
+		return params.ControllersChanges{}, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return params.ControllersChanges{}, result.Error
+	}
+	return result.Result, nil
+}
+
 // MongoUpgradeMode will make all Slave members of the HA
 // to shut down their mongo server.
 func (c *Client) MongoUpgradeMode(v mongo.Version) (params.MongoUpgradeResults, error) {
@@ -0,0 +1,113 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package instancetagger
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/api/common"
+	apiwatcher "github.com/juju/juju/api/watcher"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/watcher"
+	"github.com/juju/juju/instance"
+)
+
+// API makes calls to the InstanceTagger facade.
+type API struct {
+	*common.ModelWatcher
+	caller base.FacadeCaller
+}
+
+// NewAPI returns a new API using the supplied caller.
+func NewAPI(caller base.APICaller) *API {
+	facadeCaller := base.NewFacadeCaller(caller, "InstanceTagger")
+	return &API{
+		ModelWatcher: common.NewModelWatcher(facadeCaller),
+		caller:       facadeCaller,
+	}
+}
+
+// WatchModelMachines returns a StringsWatcher that notifies of
+// changes to the life cycles of the top level machines in the
+// model.
+func (api *API) WatchModelMachines() (watcher.StringsWatcher, error) {
+	var result params.StringsWatchResult
+	if err := api.caller.FacadeCall("WatchModelMachines", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return apiwatcher.NewStringsWatcher(api.caller.RawAPICaller(), result), nil
+}
+
+// WatchUnits returns a StringsWatcher that notifies of changes to
+// the units assigned to the given machine.
+func (api *API) WatchUnits(machine names.MachineTag) (watcher.StringsWatcher, error) {
+	var results params.StringsWatchResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: machine.String()}},
+	}
+	if err := api.caller.FacadeCall("WatchUnits", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return apiwatcher.NewStringsWatcher(api.caller.RawAPICaller(), result), nil
+}
+
+// Life returns the life cycle of the given machine.
+func (api *API) Life(machine names.MachineTag) (params.Life, error) {
+	return common.OneLife(api.caller, machine)
+}
+
+// InstanceTags holds the tags that should be set on the instance
+// of a machine, or the error that prevented them being determined.
+// InstanceId is empty if the machine has no instance for the
+// provider to tag.
+type InstanceTags struct {
+	Machine    names.MachineTag
+	InstanceId instance.Id
+	Tags       map[string]string
+	Error      error
+}
+
+// InstanceTags returns the tags that should be set on the instance
+// of each of the given machines.
+func (api *API) InstanceTags(machines ...names.MachineTag) ([]InstanceTags, error) {
+	args := params.Entities{
+		Entities: make([]params.Entity, len(machines)),
+	}
+	for i, machine := range machines {
+		args.Entities[i].Tag = machine.String()
+	}
+	var results params.InstanceTagsResults
+	if err := api.caller.FacadeCall("InstanceTags", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != len(machines) {
+		return nil, errors.Errorf("expected %d results, got %d", len(machines), len(results.Results))
+	}
+	instanceTags := make([]InstanceTags, len(machines))
+	for i, result := range results.Results {
+		instanceTags[i].Machine = machines[i]
+		if result.Error != nil {
+			instanceTags[i].Error = result.Error
+			continue
+		}
+		if result.Result == nil {
+			return nil, errors.New("missing result")
+		}
+		instanceTags[i].InstanceId = instance.Id(result.Result.InstanceId)
+		instanceTags[i].Tags = result.Result.Tags
+	}
+	return instanceTags, nil
+}
@@ -0,0 +1,148 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package instancetagger_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/base"
+	apitesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/instancetagger"
+	"github.com/juju/juju/apiserver/params"
+)
+
+type APISuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&APISuite{})
+
+func (s *APISuite) TestInstanceTags(c *gc.C) {
+	caller := apiCaller(c, func(request string, arg, result interface{}) error {
+		c.Check(request, gc.Equals, "InstanceTags")
+		c.Check(arg, jc.DeepEquals, params.Entities{Entities: []params.Entity{
+			{Tag: "machine-0"}, {Tag: "machine-1"}, {Tag: "machine-2"},
+		}})
+		c.Assert(result, gc.FitsTypeOf, &params.InstanceTagsResults{})
+		*(result.(*params.InstanceTagsResults)) = params.InstanceTagsResults{
+			Results: []params.InstanceTagsResult{{
+				Result: &params.InstanceTags{
+					MachineTag: "machine-0",
+					InstanceId: "i-0",
+					Tags:       map[string]string{"owner": "mysql/0"},
+				},
+			}, {
+				Error: &params.Error{Message: "boom"},
+			}, {
+				Result: &params.InstanceTags{MachineTag: "machine-2"},
+			}},
+		}
+		return nil
+	})
+	api := instancetagger.NewAPI(caller)
+
+	instanceTags, err := api.InstanceTags(
+		names.NewMachineTag("0"),
+		names.NewMachineTag("1"),
+		names.NewMachineTag("2"),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instanceTags, gc.HasLen, 3)
+	c.Check(instanceTags[0], jc.DeepEquals, instancetagger.InstanceTags{
+		Machine:    names.NewMachineTag("0"),
+		InstanceId: "i-0",
+		Tags:       map[string]string{"owner": "mysql/0"},
+	})
+	c.Check(instanceTags[1].Machine, gc.Equals, names.NewMachineTag("1"))
+	c.Check(instanceTags[1].Error, gc.ErrorMatches, "boom")
+	c.Check(instanceTags[2], jc.DeepEquals, instancetagger.InstanceTags{
+		Machine: names.NewMachineTag("2"),
+	})
+}
+
+func (s *APISuite) TestInstanceTagsWrongResultCount(c *gc.C) {
+	caller := apiCaller(c, func(_ string, _, _ interface{}) error {
+		return nil
+	})
+	api := instancetagger.NewAPI(caller)
+
+	_, err := api.InstanceTags(names.NewMachineTag("0"))
+	c.Assert(err, gc.ErrorMatches, "expected 1 results, got 0")
+}
+
+func (s *APISuite) TestInstanceTagsError(c *gc.C) {
+	caller := apiCaller(c, func(_ string, _, _ interface{}) error {
+		return errors.New("blam")
+	})
+	api := instancetagger.NewAPI(caller)
+
+	_, err := api.InstanceTags(names.NewMachineTag("0"))
+	c.Assert(err, gc.ErrorMatches, "blam")
+}
+
+func (s *APISuite) TestLife(c *gc.C) {
+	caller := apiCaller(c, func(request string, arg, result interface{}) error {
+		c.Check(request, gc.Equals, "Life")
+		c.Check(arg, jc.DeepEquals, params.Entities{Entities: []params.Entity{{Tag: "machine-0"}}})
+		c.Assert(result, gc.FitsTypeOf, &params.LifeResults{})
+		*(result.(*params.LifeResults)) = params.LifeResults{
+			Results: []params.LifeResult{{Life: params.Dying}},
+		}
+		return nil
+	})
+	api := instancetagger.NewAPI(caller)
+
+	life, err := api.Life(names.NewMachineTag("0"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(life, gc.Equals, params.Dying)
+}
+
+func (s *APISuite) TestWatchModelMachinesServerError(c *gc.C) {
+	caller := apiCaller(c, func(request string, arg, result interface{}) error {
+		c.Check(request, gc.Equals, "WatchModelMachines")
+		c.Check(arg, gc.IsNil)
+		c.Assert(result, gc.FitsTypeOf, &params.StringsWatchResult{})
+		*(result.(*params.StringsWatchResult)) = params.StringsWatchResult{
+			Error: &params.Error{Message: "boom"},
+		}
+		return nil
+	})
+	api := instancetagger.NewAPI(caller)
+
+	w, err := api.WatchModelMachines()
+	c.Assert(err, gc.ErrorMatches, "boom")
+	c.Assert(w, gc.IsNil)
+}
+
+func (s *APISuite) TestWatchUnitsServerError(c *gc.C) {
+	caller := apiCaller(c, func(request string, arg, result interface{}) error {
+		c.Check(request, gc.Equals, "WatchUnits")
+		c.Check(arg, jc.DeepEquals, params.Entities{Entities: []params.Entity{{Tag: "machine-0"}}})
+		c.Assert(result, gc.FitsTypeOf, &params.StringsWatchResults{})
+		*(result.(*params.StringsWatchResults)) = params.StringsWatchResults{
+			Results: []params.StringsWatchResult{{
+				Error: &params.Error{Message: "boom"},
+			}},
+		}
+		return nil
+	})
+	api := instancetagger.NewAPI(caller)
+
+	w, err := api.WatchUnits(names.NewMachineTag("0"))
+	c.Assert(err, gc.ErrorMatches, "boom")
+	c.Assert(w, gc.IsNil)
+}
+
+func apiCaller(c *gc.C, check func(request string, arg, result interface{}) error) base.APICaller {
+	return apitesting.APICallerFunc(func(facade string, version int, id, request string, arg, result interface{}) error {
+		c.Check(facade, gc.Equals, "InstanceTagger")
+		c.Check(version, gc.Equals, 0)
+		c.Check(id, gc.Equals, "")
+		return check(request, arg, result)
+	})
+}
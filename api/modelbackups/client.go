@@ -0,0 +1,66 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package modelbackups provides access to the ModelBackups API facade,
+// which exports individual models so that they can be restored into
+// the controller they were backed up from.
+package modelbackups
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v6"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client allows access to the ModelBackups API end point.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+	caller base.APICallCloser
+}
+
+// NewClient creates a new client for accessing the ModelBackups API.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "ModelBackups")
+	return &Client{
+		ClientFacade: frontend,
+		facade:       backend,
+		caller:       st,
+	}
+}
+
+// Export returns the serialized model, and the charms and resources
+// that it uses.
+func (c *Client) Export() (params.SerializedModel, error) {
+	var serialized params.SerializedModel
+	if err := c.facade.FacadeCall("Export", nil, &serialized); err != nil {
+		return params.SerializedModel{}, errors.Trace(err)
+	}
+	return serialized, nil
+}
+
+// OpenCharm streams out the identified charm archive.
+func (c *Client) OpenCharm(curl *charm.URL) (io.ReadCloser, error) {
+	return api.OpenCharm(c.caller, curl)
+}
+
+// OpenResource streams out the content of an application resource.
+func (c *Client) OpenResource(application, name string) (io.ReadCloser, error) {
+	httpClient, err := c.caller.HTTPClient()
+	if err != nil {
+		return nil, errors.Annotate(err, "unable to create HTTP client")
+	}
+	uri := fmt.Sprintf("/applications/%s/resources/%s", application, name)
+	var resp *http.Response
+	if err := httpClient.Get(uri, &resp); err != nil {
+		return nil, errors.Annotate(err, "unable to retrieve resource")
+	}
+	return resp.Body, nil
+}
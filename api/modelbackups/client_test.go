@@ -0,0 +1,62 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package modelbackups_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/modelbackups"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type clientSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&clientSuite{})
+
+func (s *clientSuite) TestExport(c *gc.C) {
+	var called bool
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, args, response interface{}) error {
+		called = true
+		c.Check(objType, gc.Equals, "ModelBackups")
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "Export")
+		c.Check(args, gc.IsNil)
+		*(response.(*params.SerializedModel)) = params.SerializedModel{
+			Bytes:  []byte("model"),
+			Charms: []string{"cs:foo-1"},
+			Resources: []params.SerializedModelResource{{
+				Application: "foo",
+				Name:        "bin",
+			}},
+		}
+		return nil
+	})
+	client := modelbackups.NewClient(apiCaller)
+	serialized, err := client.Export()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(called, jc.IsTrue)
+	c.Check(serialized, jc.DeepEquals, params.SerializedModel{
+		Bytes:  []byte("model"),
+		Charms: []string{"cs:foo-1"},
+		Resources: []params.SerializedModelResource{{
+			Application: "foo",
+			Name:        "bin",
+		}},
+	})
+}
+
+func (s *clientSuite) TestExportError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, args, response interface{}) error {
+		return errors.New("boom")
+	})
+	client := modelbackups.NewClient(apiCaller)
+	_, err := client.Export()
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
@@ -0,0 +1,44 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package modelconsistency provides a client for the ModelConsistency
+// facade, used to check a model's documents for inconsistencies.
+package modelconsistency
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client provides access to the ModelConsistency API facade.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient returns a new ModelConsistency client.
+func NewClient(caller base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(caller, "ModelConsistency")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// Check returns the inconsistencies found in the model's documents.
+func (c *Client) Check() ([]params.ModelInconsistency, error) {
+	return c.call("Check")
+}
+
+// Repair repairs the inconsistencies in the model's documents that
+// can be repaired safely, and returns all those found.
+func (c *Client) Repair() ([]params.ModelInconsistency, error) {
+	return c.call("Repair")
+}
+
+func (c *Client) call(request string) ([]params.ModelInconsistency, error) {
+	var result params.ModelInconsistencies
+	if err := c.facade.FacadeCall(request, nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Results, nil
+}
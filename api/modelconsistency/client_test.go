@@ -0,0 +1,66 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package modelconsistency_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/modelconsistency"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type clientSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&clientSuite{})
+
+func (s *clientSuite) apiCaller(c *gc.C, expectRequest string, called *bool) basetesting.APICallerFunc {
+	return basetesting.APICallerFunc(func(objType string, version int, id, request string, args, response interface{}) error {
+		*called = true
+		c.Check(objType, gc.Equals, "ModelConsistency")
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, expectRequest)
+		c.Check(args, gc.IsNil)
+		*(response.(*params.ModelInconsistencies)) = params.ModelInconsistencies{
+			Results: []params.ModelInconsistency{{
+				Check:      "unit-count",
+				Collection: "applications",
+				Id:         "mysql",
+				Message:    `application "mysql" has unit count 5 but 1 units`,
+				Repairable: true,
+				Repaired:   request == "Repair",
+			}},
+		}
+		return nil
+	})
+}
+
+func (s *clientSuite) TestCheck(c *gc.C) {
+	var called bool
+	client := modelconsistency.NewClient(s.apiCaller(c, "Check", &called))
+	results, err := client.Check()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+	c.Assert(results, jc.DeepEquals, []params.ModelInconsistency{{
+		Check:      "unit-count",
+		Collection: "applications",
+		Id:         "mysql",
+		Message:    `application "mysql" has unit count 5 but 1 units`,
+		Repairable: true,
+	}})
+}
+
+func (s *clientSuite) TestRepair(c *gc.C) {
+	var called bool
+	client := modelconsistency.NewClient(s.apiCaller(c, "Repair", &called))
+	results, err := client.Repair()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Repaired, jc.IsTrue)
+}
@@ -0,0 +1,54 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package raftcluster provides a client for the RaftCluster facade,
+// used to inspect and repair the controllers' raft cluster.
+package raftcluster
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client provides access to the RaftCluster API facade.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient returns a new RaftCluster client.
+func NewClient(caller base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(caller, "RaftCluster")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// Status returns the state of the raft node on each controller
+// machine.
+func (c *Client) Status() ([]params.RaftServerStatus, error) {
+	var result params.RaftClusterStatus
+	if err := c.facade.FacadeCall("Status", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Servers, nil
+}
+
+// RemoveServer removes the server with the given ID from the raft
+// configuration. Unless force is true, the controller refuses to
+// remove a server that belongs to a controller machine or that is
+// still running.
+func (c *Client) RemoveServer(id string, force bool) error {
+	args := params.RemoveRaftServerArgs{
+		ServerID: id,
+		Force:    force,
+	}
+	var result params.ErrorResult
+	if err := c.facade.FacadeCall("RemoveServer", args, &result); err != nil {
+		return errors.Trace(err)
+	}
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
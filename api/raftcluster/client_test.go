@@ -0,0 +1,78 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package raftcluster_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/raftcluster"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type clientSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&clientSuite{})
+
+func (s *clientSuite) TestStatus(c *gc.C) {
+	var called bool
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, args, response interface{}) error {
+		called = true
+		c.Check(objType, gc.Equals, "RaftCluster")
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "Status")
+		c.Check(args, gc.IsNil)
+		*(response.(*params.RaftClusterStatus)) = params.RaftClusterStatus{
+			Servers: []params.RaftServerStatus{{
+				ServerID: "0",
+				State:    "Leader",
+				Term:     3,
+			}},
+		}
+		return nil
+	})
+	client := raftcluster.NewClient(apiCaller)
+	servers, err := client.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+	c.Assert(servers, jc.DeepEquals, []params.RaftServerStatus{{
+		ServerID: "0",
+		State:    "Leader",
+		Term:     3,
+	}})
+}
+
+func (s *clientSuite) TestRemoveServer(c *gc.C) {
+	var called bool
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, args, response interface{}) error {
+		called = true
+		c.Check(objType, gc.Equals, "RaftCluster")
+		c.Check(request, gc.Equals, "RemoveServer")
+		c.Check(args, jc.DeepEquals, params.RemoveRaftServerArgs{
+			ServerID: "3",
+			Force:    true,
+		})
+		return nil
+	})
+	client := raftcluster.NewClient(apiCaller)
+	err := client.RemoveServer("3", true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *clientSuite) TestRemoveServerError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, args, response interface{}) error {
+		*(response.(*params.ErrorResult)) = params.ErrorResult{
+			Error: &params.Error{Message: `raft server "3" is still running`},
+		}
+		return nil
+	})
+	client := raftcluster.NewClient(apiCaller)
+	err := client.RemoveServer("3", false)
+	c.Assert(err, gc.ErrorMatches, `raft server "3" is still running`)
+}
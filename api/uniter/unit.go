@@ -798,3 +798,43 @@ func (u *Unit) NetworkInfo(bindings []string, relationId *int) (map[string]param
 
 	return results.Results, nil
 }
+
+// State returns the key/value pairs persisted for the unit by its charm.
+func (u *Unit) State() (map[string]string, error) {
+	if u.st.facade.BestAPIVersion() < 10 {
+		return nil, errors.NotImplementedf("State() (need V10+)")
+	}
+	var results params.UnitStateResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag.String()}},
+	}
+	err := u.st.facade.FacadeCall("State", args, &results)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return result.State, nil
+}
+
+// SetState replaces the key/value pairs persisted for the unit by
+// its charm.
+func (u *Unit) SetState(state map[string]string) error {
+	if u.st.facade.BestAPIVersion() < 10 {
+		return errors.NotImplementedf("SetState() (need V10+)")
+	}
+	var results params.ErrorResults
+	args := params.SetUnitStateArgs{
+		Args: []params.SetUnitStateArg{{Tag: u.tag.String(), State: state}},
+	}
+	err := u.st.facade.FacadeCall("SetState", args, &results)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
@@ -74,12 +74,12 @@ func newStateForVersionFn(version int) func(base.APICaller, names.UnitTag) *Stat
 	}
 }
 
-// newStateV9 creates a new client-side Uniter facade, version 9
-var newStateV9 = newStateForVersionFn(9)
+// newStateV10 creates a new client-side Uniter facade, version 10
+var newStateV10 = newStateForVersionFn(10)
 
 // NewState creates a new client-side Uniter facade.
 // Defined like this to allow patching during tests.
-var NewState = newStateV9
+var NewState = newStateV10
 
 // BestAPIVersion returns the API version that we were able to
 // determine is supported by both the client and the API Server.
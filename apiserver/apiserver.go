@@ -81,6 +81,7 @@ type Server struct {
 	getAuditConfig         func() auditlog.Config
 	upgradeComplete        func() bool
 	restoreStatus          func() state.RestoreStatus
+	readOnly               bool
 	mux                    *apiserverhttp.Mux
 
 	// mu guards the fields below it.
@@ -130,6 +131,12 @@ type ServerConfig struct {
 	// during a restore.
 	RestoreStatus func() state.RestoreStatus
 
+	// ReadOnly holds whether the API server is running on a read
+	// replica controller. A read-only server reads from a mongo
+	// secondary, so it only serves users, and only the API calls
+	// and HTTP requests that don't change anything.
+	ReadOnly bool
+
 	// PublicDNSName is reported to the API clients who connect.
 	PublicDNSName string
 
@@ -266,6 +273,7 @@ func newServer(cfg ServerConfig) (_ *Server, err error) {
 		loginRetryPause:               cfg.RateLimitConfig.LoginRetryPause,
 		upgradeComplete:               cfg.UpgradeComplete,
 		restoreStatus:                 cfg.RestoreStatus,
+		readOnly:                      cfg.ReadOnly,
 		facades:                       AllFacades(),
 		mux:                           cfg.Mux,
 		authenticator:                 cfg.Authenticator,
@@ -434,10 +442,17 @@ func (srv *Server) endpoints() []apihttp.Endpoint {
 		authorizer      httpcontext.Authorizer
 		tracked         bool
 		noModelUUID     bool
+		// writes is set for handlers that change the model or
+		// controller even when serving GET requests, such as the
+		// log sinks. They are not served by read replicas.
+		writes bool
 	}
 	var endpoints []apihttp.Endpoint
 	controllerModelUUID := srv.shared.statePool.SystemState().ModelUUID()
 	addHandler := func(handler handler) {
+		if srv.readOnly && handler.writes {
+			return
+		}
 		methods := handler.methods
 		if methods == nil {
 			methods = defaultHTTPMethods
@@ -467,6 +482,11 @@ func (srv *Server) endpoints() []apihttp.Endpoint {
 			}
 		}
 		for _, method := range methods {
+			if srv.readOnly && !readOnlyHTTPMethods.Contains(method) {
+				// Read replicas don't accept uploads or any
+				// other request that would change the model.
+				continue
+			}
 			endpoints = append(endpoints, apihttp.Endpoint{
 				Pattern: handler.pattern,
 				Method:  method,
@@ -612,6 +632,7 @@ func (srv *Server) endpoints() []apihttp.Endpoint {
 		handler:    logSinkHandler,
 		tracked:    true,
 		authorizer: logSinkAuthorizer,
+		writes:     true,
 	}, {
 		pattern:         modelRoutePrefix + "/api",
 		handler:         mainAPIHandler,
@@ -638,12 +659,18 @@ func (srv *Server) endpoints() []apihttp.Endpoint {
 		pattern:         modelRoutePrefix + "/tools/:version",
 		handler:         modelToolsDownloadHandler,
 		unauthenticated: true,
+		// Agent binaries missing from storage are fetched
+		// and cached.
+		writes: true,
 	}, {
 		pattern: modelRoutePrefix + "/applications/:application/resources/:resource",
 		handler: resourcesHandler,
 	}, {
 		pattern: modelRoutePrefix + "/units/:unit/resources/:resource",
 		handler: unitResourcesHandler,
+		// Resources missing from storage are fetched from the
+		// charm store, and the unit's download is recorded.
+		writes: true,
 	}, {
 		pattern: modelRoutePrefix + "/backups",
 		handler: backupHandler,
@@ -664,6 +691,7 @@ func (srv *Server) endpoints() []apihttp.Endpoint {
 		handler:    logTransferHandler,
 		tracked:    true,
 		authorizer: controllerAdminAuthorizer,
+		writes:     true,
 	}, {
 		pattern:         "/api",
 		handler:         mainAPIHandler,
@@ -691,6 +719,7 @@ func (srv *Server) endpoints() []apihttp.Endpoint {
 		pattern:         "/tools/:version",
 		handler:         modelToolsDownloadHandler,
 		unauthenticated: true,
+		writes:          true,
 	}, {
 		pattern: "/log",
 		handler: debugLogHandler,
@@ -724,6 +753,8 @@ func (srv *Server) endpoints() []apihttp.Endpoint {
 		pattern:         localOfferAccessLocationPath + "/discharge",
 		handler:         appOfferDischargeMux,
 		unauthenticated: true,
+		// Discharging stores macaroon root keys.
+		writes: true,
 	}, {
 		pattern:         localOfferAccessLocationPath + "/publickey",
 		handler:         appOfferDischargeMux,
@@ -269,3 +269,27 @@ func (s *apiserverSuite) TestRestartMessage(c *gc.C) {
 	err = workertest.CheckKilled(c, s.apiServer)
 	c.Assert(err, gc.Equals, dependency.ErrBounce)
 }
+
+func (s *apiserverSuite) TestReadOnlyOmitsWritingEndpoints(c *gc.C) {
+	config := s.config
+	config.ReadOnly = true
+	s.newServer(c, config)
+
+	for _, path := range []string{
+		"/model/" + s.State.ModelUUID() + "/logsink",
+		"/migrate/logtransfer",
+		"/tools/2.6.0-bionic-amd64",
+	} {
+		resp := s.sendHTTPRequest(c, apitesting.HTTPRequestParams{
+			Method: "GET",
+			URL:    s.URL(path, nil).String(),
+		})
+		c.Check(resp.StatusCode, gc.Equals, http.StatusNotFound, gc.Commentary(path))
+	}
+
+	resp := s.sendHTTPRequest(c, apitesting.HTTPRequestParams{
+		Method: "GET",
+		URL:    s.URL("/introspection/navel", nil).String(),
+	})
+	c.Check(resp.StatusCode, gc.Equals, http.StatusOK)
+}
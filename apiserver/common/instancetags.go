@@ -0,0 +1,57 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/cloudconfig/instancecfg"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/tags"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/multiwatcher"
+)
+
+// MachineInstanceTags returns the tags to set on the instance of the
+// given machine, reflecting the units currently deployed to it.
+func MachineInstanceTags(m *state.Machine, cfg *config.Config, controllerUUID string) (map[string]string, error) {
+	// Names of all principal units deployed to the machine,
+	// and of their applications.
+	units, err := m.Units()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	unitNames := make([]string, 0, len(units))
+	applicationNames := set.NewStrings()
+	for _, unit := range units {
+		if !unit.IsPrincipal() {
+			continue
+		}
+		unitNames = append(unitNames, unit.Name())
+		applicationNames.Add(unit.ApplicationName())
+	}
+	sort.Strings(unitNames)
+
+	var jobs []multiwatcher.MachineJob
+	for _, job := range m.Jobs() {
+		jobs = append(jobs, job.ToParams())
+	}
+	vars := tags.TemplateVars{
+		tags.TemplateMachine:      m.Id(),
+		tags.TemplateApplications: strings.Join(applicationNames.SortedValues(), " "),
+		tags.TemplateUnits:        strings.Join(unitNames, " "),
+	}
+	machineTags := instancecfg.InstanceTags(cfg.UUID(), controllerUUID, cfg, vars, jobs)
+	if len(unitNames) > 0 {
+		machineTags[tags.JujuUnitsDeployed] = strings.Join(unitNames, " ")
+	}
+	machineId := fmt.Sprintf("%s-%s", cfg.Name(), m.Tag().String())
+	machineTags[tags.JujuMachine] = machineId
+	return machineTags, nil
+}
@@ -266,9 +266,27 @@ func StorageTags(
 	modelUUID, controllerUUID string,
 	tagger tags.ResourceTagger,
 ) (map[string]string, error) {
-	storageTags := tags.ResourceTags(
+	vars := make(tags.TemplateVars)
+	if storageInstance != nil {
+		vars[tags.TemplateStorage] = storageInstance.Tag().Id()
+		if owner, ok := storageInstance.Owner(); ok {
+			switch owner := owner.(type) {
+			case names.ApplicationTag:
+				vars[tags.TemplateApplications] = owner.Id()
+			case names.UnitTag:
+				vars[tags.TemplateUnits] = owner.Id()
+				applicationName, err := names.UnitApplication(owner.Id())
+				if err != nil {
+					return nil, errors.Trace(err)
+				}
+				vars[tags.TemplateApplications] = applicationName
+			}
+		}
+	}
+	storageTags := tags.ResourceTagsWithVars(
 		names.NewModelTag(modelUUID),
 		names.NewControllerTag(controllerUUID),
+		vars,
 		tagger,
 	)
 	if storageInstance != nil {
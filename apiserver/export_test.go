@@ -116,6 +116,13 @@ func TestingCAASModelOnlyRoot() rpc.Root {
 	return restrictRoot(r, caasModelFacadesOnly)
 }
 
+// TestingReadOnlyRoot returns a restricted srvRoot as used by read
+// replica controllers.
+func TestingReadOnlyRoot() rpc.Root {
+	r := TestingAPIRoot(AllFacades())
+	return restrictRoot(r, readOnlyMethodsOnly)
+}
+
 // TestingRestrictedRoot returns a restricted srvRoot.
 func TestingRestrictedRoot(check func(string, string) error) rpc.Root {
 	r := TestingAPIRoot(AllFacades())
@@ -6,7 +6,6 @@ package provisioner
 import (
 	"fmt"
 	"sort"
-	"strings"
 
 	"github.com/juju/collections/set"
 	"github.com/juju/errors"
@@ -16,12 +15,10 @@ import (
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/common/storagecommon"
 	"github.com/juju/juju/apiserver/params"
-	"github.com/juju/juju/cloudconfig/instancecfg"
 	"github.com/juju/juju/core/lxdprofile"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/imagemetadata"
 	"github.com/juju/juju/environs/simplestreams"
-	"github.com/juju/juju/environs/tags"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/cloudimagemetadata"
 	"github.com/juju/juju/state/multiwatcher"
@@ -72,7 +69,7 @@ func (p *ProvisionerAPI) getProvisioningInfo(m *state.Machine, env environs.Envi
 		jobs = append(jobs, job.ToParams())
 	}
 
-	tags, err := p.machineTags(m, jobs)
+	tags, err := p.machineTags(m)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -211,25 +208,7 @@ func (p *ProvisionerAPI) machineVolumeParams(
 }
 
 // machineTags returns machine-specific tags to set on the instance.
-func (p *ProvisionerAPI) machineTags(m *state.Machine, jobs []multiwatcher.MachineJob) (map[string]string, error) {
-	// Names of all units deployed to the machine.
-	//
-	// TODO(axw) 2015-06-02 #1461358
-	// We need a worker that periodically updates
-	// instance tags with current deployment info.
-	units, err := m.Units()
-	if err != nil {
-		return nil, errors.Trace(err)
-	}
-	unitNames := make([]string, 0, len(units))
-	for _, unit := range units {
-		if !unit.IsPrincipal() {
-			continue
-		}
-		unitNames = append(unitNames, unit.Name())
-	}
-	sort.Strings(unitNames)
-
+func (p *ProvisionerAPI) machineTags(m *state.Machine) (map[string]string, error) {
 	cfg, err := p.m.ModelConfig()
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -238,13 +217,7 @@ func (p *ProvisionerAPI) machineTags(m *state.Machine, jobs []multiwatcher.Machi
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	machineTags := instancecfg.InstanceTags(cfg.UUID(), controllerCfg.ControllerUUID(), cfg, jobs)
-	if len(unitNames) > 0 {
-		machineTags[tags.JujuUnitsDeployed] = strings.Join(unitNames, " ")
-	}
-	machineId := fmt.Sprintf("%s-%s", cfg.Name(), m.Tag().String())
-	machineTags[tags.JujuMachine] = machineId
-	return machineTags, nil
+	return common.MachineInstanceTags(m, cfg, controllerCfg.ControllerUUID())
 }
 
 // machineSubnetsAndZones returns a map of subnet provider-specific id
@@ -241,6 +241,45 @@ func (s *withoutControllerSuite) TestProvisioningInfoWithEndpointBindings(c *gc.
 	c.Assert(result, jc.DeepEquals, expected)
 }
 
+func (s *withoutControllerSuite) TestProvisioningInfoWithTemplatedResourceTags(c *gc.C) {
+	err := s.Model.UpdateModelConfig(map[string]interface{}{
+		"resource-tags": "owner=${units} app=${applications} host=${model}-${machine} storage=${storage}",
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	machine, err := s.State.AddOneMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	wordpress := s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	for i := 0; i < 2; i++ {
+		unit, err := wordpress.AddUnit(state.AddUnitParams{})
+		c.Assert(err, jc.ErrorIsNil)
+		err = unit.AssignToMachine(machine)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: machine.Tag().String()},
+	}}
+	result, err := s.provisioner.ProvisioningInfo(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[0].Result.Tags, jc.DeepEquals, map[string]string{
+		tags.JujuController:    coretesting.ControllerTag.Id(),
+		tags.JujuModel:         coretesting.ModelTag.Id(),
+		tags.JujuMachine:       "controller-" + machine.Tag().String(),
+		tags.JujuUnitsDeployed: "wordpress/0 wordpress/1",
+		tags.JujuManagedTags:   "app host owner storage",
+		"owner":                "wordpress/0 wordpress/1",
+		"app":                  "wordpress",
+		"host":                 "controller-" + machine.Id(),
+		"storage":              "",
+	})
+}
+
 func (s *withoutControllerSuite) TestProvisioningInfoWithUnsuitableSpacesConstraints(c *gc.C) {
 	// Add an empty space.
 	_, err := s.State.AddSpace("empty", "", nil, true)
@@ -34,9 +34,8 @@ import (
 
 var logger = loggo.GetLogger("juju.apiserver.uniter")
 
-// UniterAPI implements the latest version (v9) of the Uniter API,
-// which adds WatchConfigSettingsHash, WatchTrustConfigSettingsHash
-// and WatchUnitAddressesHash.
+// UniterAPI implements the latest version (v10) of the Uniter API,
+// which adds State and SetState.
 type UniterAPI struct {
 	*common.LifeGetter
 	*StatusAPI
@@ -65,11 +64,17 @@ type UniterAPI struct {
 	cloudSpec       cloudspec.CloudSpecAPI
 }
 
+// UniterAPIV9 adds WatchConfigSettingsHash, WatchTrustConfigSettingsHash
+// and WatchUnitAddressesHash.
+type UniterAPIV9 struct {
+	UniterAPI
+}
+
 // UniterAPIV8 adds SetContainerSpec, GoalStates, CloudSpec,
 // WatchTrustConfigSettings, WatchActionNotifications,
 // UpgradeSeriesStatus, SetUpgradeSeriesStatus.
 type UniterAPIV8 struct {
-	UniterAPI
+	UniterAPIV9
 }
 
 // UniterAPIV7 adds CMR support to NetworkInfo.
@@ -261,14 +266,25 @@ func NewUniterAPI(context facade.Context) (*UniterAPI, error) {
 	}, nil
 }
 
+// NewUniterAPIV9 creates an instance of the V9 uniter API.
+func NewUniterAPIV9(context facade.Context) (*UniterAPIV9, error) {
+	uniterAPI, err := NewUniterAPI(context)
+	if err != nil {
+		return nil, err
+	}
+	return &UniterAPIV9{
+		UniterAPI: *uniterAPI,
+	}, nil
+}
+
 // NewUniterAPIV8 creates an instance of the V8 uniter API.
 func NewUniterAPIV8(context facade.Context) (*UniterAPIV8, error) {
-	uniterAPI, err := NewUniterAPI(context)
+	uniterAPI, err := NewUniterAPIV9(context)
 	if err != nil {
 		return nil, err
 	}
 	return &UniterAPIV8{
-		UniterAPI: *uniterAPI,
+		UniterAPIV9: *uniterAPI,
 	}, nil
 }
 
@@ -890,6 +906,75 @@ func (u *UniterAPI) SetWorkloadVersion(args params.EntityWorkloadVersions) (para
 	return result, nil
 }
 
+// State returns the state persisted by the charm for each given unit.
+func (u *UniterAPI) State(args params.Entities) (params.UnitStateResults, error) {
+	result := params.UnitStateResults{
+		Results: make([]params.UnitStateResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.UnitStateResults{}, err
+	}
+	for i, entity := range args.Entities {
+		resultItem := &result.Results[i]
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil {
+			resultItem.Error = common.ServerError(err)
+			continue
+		}
+		if !canAccess(tag) {
+			resultItem.Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		unit, err := u.getUnit(tag)
+		if err != nil {
+			resultItem.Error = common.ServerError(err)
+			continue
+		}
+		unitState, err := unit.State()
+		if err != nil {
+			resultItem.Error = common.ServerError(err)
+			continue
+		}
+		resultItem.State = unitState
+	}
+	return result, nil
+}
+
+// SetState replaces the state persisted by the charm for each given
+// unit. An error will be returned if a unit is dead, or if the state
+// exceeds the allowed size.
+func (u *UniterAPI) SetState(args params.SetUnitStateArgs) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Args)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, arg := range args.Args {
+		resultItem := &result.Results[i]
+		tag, err := names.ParseUnitTag(arg.Tag)
+		if err != nil {
+			resultItem.Error = common.ServerError(err)
+			continue
+		}
+		if !canAccess(tag) {
+			resultItem.Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		unit, err := u.getUnit(tag)
+		if err != nil {
+			resultItem.Error = common.ServerError(err)
+			continue
+		}
+		if err := unit.SetState(arg.State); err != nil {
+			resultItem.Error = common.ServerError(err)
+		}
+	}
+	return result, nil
+}
+
 // OpenPorts sets the policy of the port range with protocol to be
 // opened, for all given units.
 func (u *UniterAPI) OpenPorts(args params.EntitiesPortRanges) (params.ErrorResults, error) {
@@ -2764,6 +2849,12 @@ func (u *UniterAPIV8) WatchTrustConfigSettingsHash(_, _ struct{}) {}
 // WatchUnitAddressesHash isn't on the v8 API.
 func (u *UniterAPIV8) WatchUnitAddressesHash(_, _ struct{}) {}
 
+// State isn't on the v9 API.
+func (u *UniterAPIV9) State(_, _ struct{}) {}
+
+// SetState isn't on the v9 API.
+func (u *UniterAPIV9) SetState(_, _ struct{}) {}
+
 func (u *UniterAPI) watchHashes(args params.Entities, getWatcher func(u *state.Unit) (state.StringsWatcher, error)) (params.StringsWatchResults, error) {
 	result := params.StringsWatchResults{
 		Results: make([]params.StringsWatchResult, len(args.Entities)),
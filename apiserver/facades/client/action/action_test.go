@@ -648,6 +648,46 @@ func (s *actionSuite) TestCancel(c *gc.C) {
 	c.Assert(myActions[1].Status, gc.Equals, params.ActionCancelled)
 }
 
+func (s *actionSuite) TestSetPinned(c *gc.C) {
+	results, err := s.action.Enqueue(params.Actions{
+		Actions: []params.Action{{
+			Receiver: s.wordpressUnit.Tag().String(),
+			Name:     "fakeaction",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	actionTag := results.Results[0].Action.Tag
+
+	pinned, err := s.action.SetPinned(params.ActionPins{
+		Actions: []params.ActionPin{
+			{Tag: actionTag, Pinned: true},
+			{Tag: "action-00000000-0000-0000-0000-000000000000", Pinned: true},
+			{Tag: "invalid", Pinned: true},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pinned.Results, gc.HasLen, 3)
+	c.Assert(pinned.Results[0].Error, gc.IsNil)
+	c.Assert(pinned.Results[1].Error, jc.Satisfies, params.IsCodeNotFound)
+	c.Assert(pinned.Results[2].Error, gc.ErrorMatches, common.ErrBadId.Error())
+
+	tag, err := names.ParseActionTag(actionTag)
+	c.Assert(err, jc.ErrorIsNil)
+	model, err := s.State.Model()
+	c.Assert(err, jc.ErrorIsNil)
+	a, err := model.ActionByTag(tag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(a.Pinned(), jc.IsTrue)
+}
+
+func (s *actionSuite) TestBlockSetPinned(c *gc.C) {
+	s.BlockAllChanges(c, "SetPinned")
+	_, err := s.action.SetPinned(params.ActionPins{})
+	s.AssertBlocked(c, err, "SetPinned")
+}
+
 func (s *actionSuite) TestApplicationsCharmsActions(c *gc.C) {
 	actionSchemas := map[string]map[string]interface{}{
 		"snapshot": {
@@ -65,6 +65,11 @@ type APIv7 struct {
 
 // APIv8 provides the Application API facade for version 8.
 type APIv8 struct {
+	*APIv9
+}
+
+// APIv9 provides the Application API facade for version 9.
+type APIv9 struct {
 	*APIBase
 }
 
@@ -135,13 +140,21 @@ func NewFacadeV7(ctx facade.Context) (*APIv7, error) {
 }
 
 func NewFacadeV8(ctx facade.Context) (*APIv8, error) {
-	api, err := newFacadeBase(ctx)
+	api, err := NewFacadeV9(ctx)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
 	return &APIv8{api}, nil
 }
 
+func NewFacadeV9(ctx facade.Context) (*APIv9, error) {
+	api, err := newFacadeBase(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &APIv9{api}, nil
+}
+
 func newFacadeBase(ctx facade.Context) (*APIBase, error) {
 	model, err := ctx.State().Model()
 	if err != nil {
@@ -1632,6 +1645,38 @@ func (api *APIBase) ScaleApplications(args params.ScaleApplicationsParams) (para
 	return params.ScaleApplicationResults{results}, nil
 }
 
+// UnitsState returns the key/value state persisted by the charm for
+// each of the given units.
+func (api *APIBase) UnitsState(args params.Entities) (params.UnitStateResults, error) {
+	if err := api.checkCanRead(); err != nil {
+		return params.UnitStateResults{}, errors.Trace(err)
+	}
+	results := params.UnitStateResults{
+		Results: make([]params.UnitStateResult, len(args.Entities)),
+	}
+	for i, arg := range args.Entities {
+		unitState, err := api.unitState(arg.Tag)
+		results.Results[i].State = unitState
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func (api *APIBase) unitState(entity string) (map[string]string, error) {
+	tag, err := names.ParseUnitTag(entity)
+	if err != nil {
+		return nil, err
+	}
+	unit, err := api.backend.Unit(tag.Id())
+	if err != nil {
+		return nil, err
+	}
+	return unit.State()
+}
+
+// UnitsState isn't on the v8 API.
+func (u *APIv8) UnitsState(_, _ struct{}) {}
+
 // GetConstraints returns the constraints for a given application.
 func (api *APIBase) GetConstraints(args params.Entities) (params.ApplicationGetConstraintsResults, error) {
 	if err := api.checkCanRead(); err != nil {
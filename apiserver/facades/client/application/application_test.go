@@ -109,7 +109,7 @@ func (s *applicationSuite) makeAPI(c *gc.C) *application.APIv8 {
 		common.NewResources(),
 	)
 	c.Assert(err, jc.ErrorIsNil)
-	return &application.APIv8{api}
+	return &application.APIv8{&application.APIv9{api}}
 }
 
 func (s *applicationSuite) TestGetConfig(c *gc.C) {
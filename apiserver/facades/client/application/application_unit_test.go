@@ -68,7 +68,7 @@ func (s *ApplicationSuite) setAPIUser(c *gc.C, user names.UserTag) {
 		common.NewResources(),
 	)
 	c.Assert(err, jc.ErrorIsNil)
-	s.api = &application.APIv8{api}
+	s.api = &application.APIv8{&application.APIv9{api}}
 }
 
 func (s *ApplicationSuite) SetUpTest(c *gc.C) {
@@ -703,6 +703,21 @@ func (s *ApplicationSuite) TestScaleApplicationsIAASModel(c *gc.C) {
 	app.CheckNoCalls(c)
 }
 
+func (s *ApplicationSuite) TestUnitsState(c *gc.C) {
+	s.backend.applications["postgresql"].units[0].state = map[string]string{"foo": "bar"}
+	results, err := s.api.APIv9.UnitsState(params.Entities{Entities: []params.Entity{
+		{Tag: "unit-postgresql-0"},
+		{Tag: "application-postgresql"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.UnitStateResults{
+		Results: []params.UnitStateResult{
+			{State: map[string]string{"foo": "bar"}},
+			{Error: &params.Error{Message: `"application-postgresql" is not a valid unit tag`}},
+		},
+	})
+}
+
 func (s *ApplicationSuite) TestAddUnitsAttachStorage(c *gc.C) {
 	_, err := s.api.AddUnits(params.AddApplicationUnits{
 		ApplicationName: "postgresql",
@@ -626,6 +626,7 @@ type mockUnit struct {
 	tag       names.UnitTag
 	machineId string
 	name      string
+	state     map[string]string
 }
 
 func (u *mockUnit) Tag() names.Tag {
@@ -672,6 +673,11 @@ func (u *mockUnit) Name() string {
 	return u.name
 }
 
+func (u *mockUnit) State() (map[string]string, error) {
+	u.MethodCall(u, "State")
+	return u.state, u.NextErr()
+}
+
 type mockStorageAttachment struct {
 	state.StorageAttachment
 	jtesting.Stub
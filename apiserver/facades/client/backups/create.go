@@ -4,10 +4,13 @@
 package backups
 
 import (
+	"crypto/rsa"
+
 	"github.com/juju/errors"
 	"github.com/juju/replicaset"
 
 	"github.com/juju/juju/apiserver/params"
+	corebackups "github.com/juju/juju/core/backups"
 	"github.com/juju/juju/mongo"
 	"github.com/juju/juju/state/backups"
 )
@@ -22,24 +25,46 @@ func (a *API) Create(args params.BackupsCreateArgs) (params.BackupsMetadataResul
 	args.KeepCopy = true
 	args.NoDownload = true
 
-	apiv2 := APIv2{a}
-	result, err := apiv2.Create(args)
+	args.PublicKey = ""
+	result, err := a.create(args)
 	if err != nil {
 		return result, errors.Trace(err)
 	}
 	return result, nil
 }
 
+// Create is the API method that requests juju to create a new backup
+// of its state. It returns the metadata for that backup.
+//
+// NOTE: version 2 of the facade ignores any public key in the args.
 func (a *APIv2) Create(args params.BackupsCreateArgs) (params.BackupsMetadataResult, error) {
+	args.PublicKey = ""
+	return a.create(args)
+}
+
+// Create is the API method that requests juju to create a new backup
+// of its state. It returns the metadata for that backup. The backup
+// is encrypted with the public key in the args, if any, or else with
+// the controller's backup public key, if one is configured.
+func (a *APIv3) Create(args params.BackupsCreateArgs) (params.BackupsMetadataResult, error) {
+	return a.create(args)
+}
+
+func (a *API) create(args params.BackupsCreateArgs) (params.BackupsMetadataResult, error) {
+	result := params.BackupsMetadataResult{}
+	encryptionKey, err := a.encryptionKey(args.PublicKey)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+
 	backupsMethods, closer := newBackups(a.backend)
 	defer closer.Close()
 
 	session := a.backend.MongoSession().Copy()
 	defer session.Close()
 
-	result := params.BackupsMetadataResult{}
 	// Don't go if HA isn't ready.
-	err := waitUntilReady(session, 60)
+	err = waitUntilReady(session, 60)
 	if err != nil {
 		return result, errors.Annotatef(err, "HA not ready; try again later")
 	}
@@ -71,7 +96,7 @@ func (a *APIv2) Create(args params.BackupsCreateArgs) (params.BackupsMetadataRes
 	}
 	meta.Notes = args.Notes
 
-	fileName, err := backupsMethods.Create(meta, a.paths, dbInfo, args.KeepCopy, args.NoDownload)
+	fileName, err := backupsMethods.Create(meta, a.paths, dbInfo, args.KeepCopy, args.NoDownload, encryptionKey)
 	if err != nil {
 		return result, errors.Trace(err)
 	}
@@ -79,3 +104,22 @@ func (a *APIv2) Create(args params.BackupsCreateArgs) (params.BackupsMetadataRes
 	result = CreateResult(meta, fileName)
 	return result, nil
 }
+
+// encryptionKey returns the key with which to encrypt a new backup:
+// the supplied public key if there is one, or else the controller's
+// backup public key. It returns nil if the backup is not to be
+// encrypted.
+func (a *API) encryptionKey(publicKey string) (*rsa.PublicKey, error) {
+	if publicKey == "" {
+		cfg, err := a.backend.ControllerConfig()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		publicKey = cfg.BackupPublicKey()
+	}
+	if publicKey == "" {
+		return nil, nil
+	}
+	key, err := corebackups.ParsePublicKey([]byte(publicKey))
+	return key, errors.Trace(err)
+}
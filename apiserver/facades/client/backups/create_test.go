@@ -4,12 +4,18 @@
 package backups_test
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/mgo.v2"
 
 	"github.com/juju/juju/apiserver/facades/client/backups"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/controller"
 )
 
 func (s *backupsSuite) TestCreateOkay(c *gc.C) {
@@ -54,3 +60,53 @@ func (s *backupsSuite) TestCreateError(c *gc.C) {
 	c.Logf("%v", err)
 	c.Check(err, gc.ErrorMatches, "failed!")
 }
+
+func newPublicKey(c *gc.C) (*rsa.PublicKey, string) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	c.Assert(err, jc.ErrorIsNil)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	c.Assert(err, jc.ErrorIsNil)
+	return &key.PublicKey, string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+func (s *backupsSuite) TestCreateEncrypted(c *gc.C) {
+	s.PatchValue(backups.WaitUntilReady,
+		func(*mgo.Session, int) error { return nil },
+	)
+	fake := s.setBackups(c, s.meta, "")
+	key, keyPEM := newPublicKey(c)
+	api, err := backups.NewAPIv3(&stateShim{s.State, s.Model}, s.resources, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = api.Create(params.BackupsCreateArgs{PublicKey: keyPEM})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(fake.EncryptionKey, jc.DeepEquals, key)
+}
+
+func (s *backupsSuite) TestCreateEncryptedWithControllerKey(c *gc.C) {
+	s.PatchValue(backups.WaitUntilReady,
+		func(*mgo.Session, int) error { return nil },
+	)
+	fake := s.setBackups(c, s.meta, "")
+	key, keyPEM := newPublicKey(c)
+	err := s.State.UpdateControllerConfig(map[string]interface{}{
+		controller.BackupPublicKey: keyPEM,
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Version 2 of the facade ignores any key in the args, but still
+	// honours the controller's key.
+	_, otherKeyPEM := newPublicKey(c)
+	_, err = s.api.Create(params.BackupsCreateArgs{PublicKey: otherKeyPEM})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(fake.EncryptionKey, jc.DeepEquals, key)
+}
+
+func (s *backupsSuite) TestCreateInvalidPublicKey(c *gc.C) {
+	s.setBackups(c, s.meta, "")
+	api, err := backups.NewAPIv3(&stateShim{s.State, s.Model}, s.resources, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = api.Create(params.BackupsCreateArgs{PublicKey: "not a key"})
+	c.Assert(err, gc.ErrorMatches, "backup public key: no PEM data found not valid")
+}
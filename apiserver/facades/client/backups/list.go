@@ -21,9 +21,14 @@ func (a *API) List(args params.BackupsListArgs) (params.BackupsListResult, error
 		return result, errors.Trace(err)
 	}
 
-	result.List = make([]params.BackupsMetadataResult, len(metaList))
-	for i, meta := range metaList {
-		result.List[i] = CreateResult(meta, "")
+	result.List = make([]params.BackupsMetadataResult, 0, len(metaList))
+	for _, meta := range metaList {
+		// Failed scheduled backups are recorded to report the
+		// outcome of the schedule, but have no archive.
+		if meta.Failure != "" {
+			continue
+		}
+		result.List = append(result.List, CreateResult(meta, ""))
 	}
 
 	return result, nil
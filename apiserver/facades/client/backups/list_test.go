@@ -12,6 +12,7 @@ import (
 
 	"github.com/juju/juju/apiserver/facades/client/backups"
 	"github.com/juju/juju/apiserver/params"
+	statebackups "github.com/juju/juju/state/backups"
 )
 
 func (s *backupsSuite) TestListOkay(c *gc.C) {
@@ -29,6 +30,21 @@ func (s *backupsSuite) TestListOkay(c *gc.C) {
 	c.Check(result, gc.DeepEquals, expected)
 }
 
+func (s *backupsSuite) TestListSkipsFailed(c *gc.C) {
+	impl := s.setBackups(c, s.meta, "")
+	failed := statebackups.NewMetadata()
+	failed.SetID("failed")
+	failed.Scheduled = true
+	failed.Failure = "mongodump failed"
+	impl.MetaList = append(impl.MetaList, failed)
+	result, err := s.api.List(params.BackupsListArgs{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(result, gc.DeepEquals, params.BackupsListResult{
+		List: []params.BackupsMetadataResult{backups.CreateResult(s.meta, "")},
+	})
+}
+
 func (s *backupsSuite) TestListError(c *gc.C) {
 	s.setBackups(c, nil, "failed!")
 	args := params.BackupsListArgs{}
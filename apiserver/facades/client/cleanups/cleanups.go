@@ -0,0 +1,127 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package cleanups defines an API endpoint for listing the cleanups
+// queued in a model, and for forcing or cancelling those that are stuck.
+package cleanups
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/permission"
+	"github.com/juju/juju/state"
+)
+
+// Backend defines the state functionality required by the cleanups
+// facade.
+type Backend interface {
+	common.BlockGetter
+	ControllerTag() names.ControllerTag
+	ModelUUID() string
+	Cleanups() ([]state.CleanupInfo, error)
+	ForceCleanup(id string) error
+	CancelCleanup(id string) error
+}
+
+// API implements the Cleanups API facade.
+type API struct {
+	backend    Backend
+	authorizer facade.Authorizer
+	check      *common.BlockChecker
+}
+
+// NewFacade is used for API registration.
+func NewFacade(ctx facade.Context) (*API, error) {
+	return NewAPI(ctx.State(), ctx.Auth())
+}
+
+// NewAPI returns a new Cleanups API facade. Only model and controller
+// administrators can list a model's cleanups.
+func NewAPI(backend Backend, authorizer facade.Authorizer) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	isAdmin, err := authorizer.HasPermission(permission.SuperuserAccess, backend.ControllerTag())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !isAdmin {
+		modelTag := names.NewModelTag(backend.ModelUUID())
+		isAdmin, err = authorizer.HasPermission(permission.AdminAccess, modelTag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	if !isAdmin {
+		return nil, common.ErrPerm
+	}
+	return &API{
+		backend:    backend,
+		authorizer: authorizer,
+		check:      common.NewBlockChecker(backend),
+	}, nil
+}
+
+// ListCleanups returns the cleanups queued in the model, including
+// those that have failed and will be retried.
+func (api *API) ListCleanups() (params.ModelCleanups, error) {
+	cleanups, err := api.backend.Cleanups()
+	if err != nil {
+		return params.ModelCleanups{}, errors.Trace(err)
+	}
+	result := params.ModelCleanups{
+		Results: make([]params.ModelCleanup, len(cleanups)),
+	}
+	for i, cleanup := range cleanups {
+		result.Results[i] = params.ModelCleanup{
+			Id:        cleanup.Id,
+			Kind:      cleanup.Kind,
+			Target:    cleanup.Target,
+			Attempts:  cleanup.Attempts,
+			LastError: cleanup.LastError,
+		}
+		if !cleanup.LastAttempt.IsZero() {
+			lastAttempt := cleanup.LastAttempt
+			result.Results[i].LastAttempt = &lastAttempt
+		}
+	}
+	return result, nil
+}
+
+// ForceCleanups runs the specified cleanups immediately, forcefully
+// where the cleanup allows it.
+func (api *API) ForceCleanups(args params.CleanupIds) (params.ErrorResults, error) {
+	return api.changeCleanups(args, api.backend.ForceCleanup)
+}
+
+// CancelCleanups removes the specified cleanups without running them.
+func (api *API) CancelCleanups(args params.CleanupIds) (params.ErrorResults, error) {
+	return api.changeCleanups(args, api.backend.CancelCleanup)
+}
+
+// changeCleanups calls change for each of the cleanup ids. Forcing and
+// cancelling cleanups affects documents that the controller manages, so
+// only controller administrators can do so.
+func (api *API) changeCleanups(args params.CleanupIds, change func(string) error) (params.ErrorResults, error) {
+	isAdmin, err := api.authorizer.HasPermission(permission.SuperuserAccess, api.backend.ControllerTag())
+	if err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	if !isAdmin {
+		return params.ErrorResults{}, common.ErrPerm
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Ids)),
+	}
+	for i, id := range args.Ids {
+		result.Results[i].Error = common.ServerError(change(id))
+	}
+	return result, nil
+}
@@ -0,0 +1,189 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cleanups_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facades/client/cleanups"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type cleanupsSuite struct {
+	coretesting.BaseSuite
+	backend *mockBackend
+	auth    apiservertesting.FakeAuthorizer
+}
+
+var _ = gc.Suite(&cleanupsSuite{})
+
+var lastAttempt = time.Date(2019, 3, 14, 9, 26, 53, 0, time.UTC)
+
+func (s *cleanupsSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.backend = &mockBackend{
+		cleanups: []state.CleanupInfo{{
+			Id:          "5c9d1e8b",
+			Kind:        "dyingMachine",
+			Target:      "3",
+			Attempts:    12,
+			LastError:   "machine 3 has attachments [volume-3-0]",
+			LastAttempt: lastAttempt,
+		}, {
+			Id:     "5c9d1e8c",
+			Kind:   "units",
+			Target: "mysql",
+		}},
+	}
+	s.auth = apiservertesting.FakeAuthorizer{
+		Tag:      names.NewUserTag("admin"),
+		AdminTag: names.NewUserTag("admin"),
+	}
+}
+
+func (s *cleanupsSuite) newAPI(c *gc.C) *cleanups.API {
+	api, err := cleanups.NewAPI(s.backend, s.auth)
+	c.Assert(err, jc.ErrorIsNil)
+	return api
+}
+
+func (s *cleanupsSuite) TestNewAPINotClient(c *gc.C) {
+	s.auth.Tag = names.NewMachineTag("0")
+	_, err := cleanups.NewAPI(s.backend, s.auth)
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *cleanupsSuite) TestNewAPINotAdmin(c *gc.C) {
+	s.auth.Tag = names.NewUserTag("write")
+	_, err := cleanups.NewAPI(s.backend, s.auth)
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *cleanupsSuite) TestListCleanups(c *gc.C) {
+	s.auth.Tag = names.NewUserTag("admin-" + coretesting.ModelTag.String())
+	result, err := s.newAPI(c).ListCleanups()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ModelCleanups{
+		Results: []params.ModelCleanup{{
+			Id:          "5c9d1e8b",
+			Kind:        "dyingMachine",
+			Target:      "3",
+			Attempts:    12,
+			LastError:   "machine 3 has attachments [volume-3-0]",
+			LastAttempt: &lastAttempt,
+		}, {
+			Id:     "5c9d1e8c",
+			Kind:   "units",
+			Target: "mysql",
+		}},
+	})
+	s.backend.CheckCallNames(c, "Cleanups")
+}
+
+func (s *cleanupsSuite) TestForceCleanups(c *gc.C) {
+	s.backend.SetErrors(nil, nil, errors.NotFoundf(`cleanup "bad"`))
+	result, err := s.newAPI(c).ForceCleanups(params.CleanupIds{
+		Ids: []string{"5c9d1e8b", "bad"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{},
+			{Error: &params.Error{Code: params.CodeNotFound, Message: `cleanup "bad" not found`}},
+		},
+	})
+	s.backend.CheckCalls(c, []testing.StubCall{
+		{"GetBlockForType", []interface{}{state.ChangeBlock}},
+		{"ForceCleanup", []interface{}{"5c9d1e8b"}},
+		{"ForceCleanup", []interface{}{"bad"}},
+	})
+}
+
+func (s *cleanupsSuite) TestCancelCleanups(c *gc.C) {
+	result, err := s.newAPI(c).CancelCleanups(params.CleanupIds{
+		Ids: []string{"5c9d1e8b"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{{}},
+	})
+	s.backend.CheckCalls(c, []testing.StubCall{
+		{"GetBlockForType", []interface{}{state.ChangeBlock}},
+		{"CancelCleanup", []interface{}{"5c9d1e8b"}},
+	})
+}
+
+func (s *cleanupsSuite) TestCancelCleanupsModelAdmin(c *gc.C) {
+	s.auth.Tag = names.NewUserTag("admin-" + coretesting.ModelTag.String())
+	_, err := s.newAPI(c).CancelCleanups(params.CleanupIds{
+		Ids: []string{"5c9d1e8b"},
+	})
+	c.Assert(err, gc.Equals, common.ErrPerm)
+	s.backend.CheckNoCalls(c)
+}
+
+func (s *cleanupsSuite) TestForceCleanupsBlocked(c *gc.C) {
+	s.backend.changeBlocked = true
+	_, err := s.newAPI(c).ForceCleanups(params.CleanupIds{
+		Ids: []string{"5c9d1e8b"},
+	})
+	c.Assert(err, jc.Satisfies, params.IsCodeOperationBlocked)
+	s.backend.CheckCallNames(c, "GetBlockForType")
+}
+
+type mockBackend struct {
+	testing.Stub
+	cleanups      []state.CleanupInfo
+	changeBlocked bool
+}
+
+func (b *mockBackend) ControllerTag() names.ControllerTag {
+	return coretesting.ControllerTag
+}
+
+func (b *mockBackend) ModelUUID() string {
+	return coretesting.ModelTag.Id()
+}
+
+func (b *mockBackend) GetBlockForType(t state.BlockType) (state.Block, bool, error) {
+	b.MethodCall(b, "GetBlockForType", t)
+	if b.changeBlocked && t == state.ChangeBlock {
+		return &mockBlock{t: t}, true, nil
+	}
+	return nil, false, b.NextErr()
+}
+
+func (b *mockBackend) Cleanups() ([]state.CleanupInfo, error) {
+	b.MethodCall(b, "Cleanups")
+	return b.cleanups, b.NextErr()
+}
+
+func (b *mockBackend) ForceCleanup(id string) error {
+	b.MethodCall(b, "ForceCleanup", id)
+	return b.NextErr()
+}
+
+func (b *mockBackend) CancelCleanup(id string) error {
+	b.MethodCall(b, "CancelCleanup", id)
+	return b.NextErr()
+}
+
+type mockBlock struct {
+	state.Block
+	t state.BlockType
+}
+
+func (m mockBlock) Type() state.BlockType { return m.t }
+
+func (m mockBlock) Message() string { return "" }
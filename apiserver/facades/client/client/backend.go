@@ -20,6 +20,7 @@ import (
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/permission"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/backups"
 )
 
 // Backend contains the state.State methods used in this package,
@@ -40,6 +41,7 @@ type Backend interface {
 	AllLinkLayerDevices() ([]*state.LinkLayerDevice, error)
 	AllRelations() ([]*state.Relation, error)
 	AllSubnets() ([]*state.Subnet, error)
+	BackupScheduleStatus() (backups.ScheduleStatus, error)
 	Annotations(state.GlobalEntity) (map[string]string, error)
 	APIHostPortsForClients() ([][]network.HostPort, error)
 	Application(string) (*state.Application, error)
@@ -129,6 +131,10 @@ func (s *stateShim) Watch(params state.WatchParams) *state.Multiwatcher {
 	return s.State.Watch(params)
 }
 
+func (s *stateShim) BackupScheduleStatus() (backups.ScheduleStatus, error) {
+	return backups.GetScheduleStatus(s)
+}
+
 func (s *stateShim) AllApplicationOffers() ([]*crossmodel.ApplicationOffer, error) {
 	offers := state.NewApplicationOffers(s.State)
 	return offers.AllApplicationOffers()
@@ -4,7 +4,13 @@
 package client
 
 import (
+	"time"
+
+	"github.com/juju/errors"
+
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/backups"
 )
 
 // Filtering exports
@@ -13,6 +19,29 @@ var (
 	MatchSubnet     = matchSubnet
 )
 
+var FetchControllerWarnings = fetchControllerWarnings
+
+// AddFailedScheduledBackup records a scheduled backup that failed in
+// the backup metadata of the controller.
+func AddFailedScheduledBackup(st *state.State, started time.Time, failure string) error {
+	model, err := st.Model()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	db := &stateShim{st, model}
+	meta, err := backups.NewMetadataState(db, "0", "bionic")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	meta.Started = started
+	meta.Scheduled = true
+	meta.Failure = failure
+	stor := backups.NewStorage(db)
+	defer stor.Close()
+	_, err = stor.Add(meta, nil)
+	return errors.Trace(err)
+}
+
 func SetNewEnviron(c *Client, newEnviron func() (environs.Environ, error)) {
 	c.newEnviron = newEnviron
 }
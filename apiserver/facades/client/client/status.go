@@ -227,6 +227,12 @@ func (c *Client) FullStatus(args params.StatusParams) (params.FullStatus, error)
 	if context.controllerTimestamp, err = c.api.stateAccessor.ControllerTimestamp(); err != nil {
 		return noStatus, errors.Annotate(err, "could not fetch controller timestamp")
 	}
+	// Only admins of the controller model can see controller warnings.
+	if c.api.stateAccessor.IsController() {
+		if err := c.checkIsAdmin(); err == nil {
+			context.controllerWarnings = fetchControllerWarnings(c.api.stateAccessor)
+		}
+	}
 
 	logger.Tracef("Applications: %v", context.allAppsUnitsCharmBindings.applications)
 	logger.Tracef("Remote applications: %v", context.consumerRemoteApplications)
@@ -371,6 +377,7 @@ func (c *Client) FullStatus(args params.StatusParams) (params.FullStatus, error)
 		Offers:              context.processOffers(),
 		Relations:           context.processRelations(),
 		ControllerTimestamp: context.controllerTimestamp,
+		ControllerWarnings:  context.controllerWarnings,
 	}, nil
 }
 
@@ -471,6 +478,10 @@ type statusContext struct {
 	// controller current timestamp
 	controllerTimestamp *time.Time
 
+	// controllerWarnings holds problems with the controller that
+	// need the attention of an administrator.
+	controllerWarnings []string
+
 	allAppsUnitsCharmBindings applicationStatusInfo
 	relations                 map[string][]*state.Relation
 	relationsById             map[int]*state.Relation
@@ -761,6 +772,24 @@ func fetchRelations(st Backend) (map[string][]*state.Relation, map[int]*state.Re
 	return out, outById, nil
 }
 
+// fetchControllerWarnings returns any problems with the controller
+// that need the attention of an administrator. Problems determining
+// the warnings are logged rather than failing the status call.
+func fetchControllerWarnings(st Backend) []string {
+	var warnings []string
+	backupStatus, err := st.BackupScheduleStatus()
+	if err != nil {
+		logger.Warningf("cannot get scheduled backup status: %v", err)
+	} else if backupStatus.Failed() {
+		warnings = append(warnings, fmt.Sprintf(
+			"last scheduled backup failed at %s: %s",
+			backupStatus.LastAttempt.Format(time.RFC3339),
+			backupStatus.LastError,
+		))
+	}
+	return warnings
+}
+
 func (c *statusContext) processMachines() map[string]params.MachineStatus {
 	machinesMap := make(map[string]params.MachineStatus)
 	cache := make(map[string]params.MachineStatus)
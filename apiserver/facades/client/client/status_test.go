@@ -6,6 +6,7 @@ package client_test
 import (
 	"time"
 
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
 	gc "gopkg.in/check.v1"
@@ -13,6 +14,7 @@ import (
 
 	"github.com/juju/juju/api"
 	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facades/client/client"
 	"github.com/juju/juju/apiserver/facades/controller/charmrevisionupdater"
 	"github.com/juju/juju/apiserver/facades/controller/charmrevisionupdater/testing"
 	"github.com/juju/juju/apiserver/params"
@@ -21,6 +23,7 @@ import (
 	"github.com/juju/juju/instance"
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/backups"
 	"github.com/juju/juju/testing/factory"
 )
 
@@ -66,6 +69,34 @@ func (s *statusSuite) TestFullStatus(c *gc.C) {
 	c.Check(resultMachine.LXDProfiles, gc.HasLen, 0)
 }
 
+func (s *statusSuite) TestFullStatusFailedScheduledBackup(c *gc.C) {
+	apiClient := s.APIState.Client()
+	status, err := apiClient.Status(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(status.ControllerWarnings, gc.HasLen, 0)
+
+	err = client.AddFailedScheduledBackup(s.State, time.Date(2019, 5, 26, 2, 0, 0, 0, time.UTC), "mongodump failed")
+	c.Assert(err, jc.ErrorIsNil)
+	status, err = apiClient.Status(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(status.ControllerWarnings, jc.DeepEquals, []string{
+		"last scheduled backup failed at 2019-05-26T02:00:00Z: mongodump failed",
+	})
+}
+
+func (s *statusSuite) TestControllerWarningsIgnoreErrors(c *gc.C) {
+	warnings := client.FetchControllerWarnings(failingBackupStatusBackend{})
+	c.Assert(warnings, gc.HasLen, 0)
+}
+
+type failingBackupStatusBackend struct {
+	client.Backend
+}
+
+func (failingBackupStatusBackend) BackupScheduleStatus() (backups.ScheduleStatus, error) {
+	return backups.ScheduleStatus{}, errors.New("cannot list backups")
+}
+
 func (s *statusSuite) TestUnsupportedNoModelMeterStatus(c *gc.C) {
 	s.addMachine(c)
 	c.Assert(s.State.SetSLA("unsupported", "test-user", []byte("")), jc.ErrorIsNil)
@@ -9,8 +9,10 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/juju/collections/set"
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"github.com/juju/replicaset"
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/common"
@@ -18,11 +20,14 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/controller"
+	"github.com/juju/juju/environs"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/mongo"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/permission"
+	providercommon "github.com/juju/juju/provider/common"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/stateenvirons"
 )
 
 var logger = loggo.GetLogger("juju.apiserver.highavailability")
@@ -30,6 +35,7 @@ var logger = loggo.GetLogger("juju.apiserver.highavailability")
 // HighAvailability defines the methods on the highavailability API end point.
 type HighAvailability interface {
 	EnableHA(args params.ControllersSpecs) (params.ControllersChangeResults, error)
+	EnableReadReplicas(args params.ReadReplicasSpecs) (params.ControllersChangeResults, error)
 }
 
 // HighAvailabilityAPI implements the HighAvailability interface and is the concrete
@@ -40,6 +46,12 @@ type HighAvailabilityAPI struct {
 	authorizer facade.Authorizer
 }
 
+// HighAvailabilityAPIV2 implements v2 of the high availability facade,
+// which has no EnableReadReplicas method.
+type HighAvailabilityAPIV2 struct {
+	*HighAvailabilityAPI
+}
+
 var _ HighAvailability = (*HighAvailabilityAPI)(nil)
 
 // NewHighAvailabilityAPI creates a new server-side highavailability API end point.
@@ -55,6 +67,16 @@ func NewHighAvailabilityAPI(st *state.State, resources facade.Resources, authori
 	}, nil
 }
 
+// NewHighAvailabilityAPIV2 creates a new server-side highavailability
+// API end point for v2 of the facade.
+func NewHighAvailabilityAPIV2(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*HighAvailabilityAPIV2, error) {
+	api, err := NewHighAvailabilityAPI(st, resources, authorizer)
+	if err != nil {
+		return nil, err
+	}
+	return &HighAvailabilityAPIV2{api}, nil
+}
+
 // EnableHA adds controller machines as necessary to ensure the
 // controller has the number of machines specified.
 func (api *HighAvailabilityAPI) EnableHA(args params.ControllersSpecs) (params.ControllersChangeResults, error) {
@@ -101,21 +123,8 @@ func (api *HighAvailabilityAPI) enableHASingle(st *state.State, spec params.Cont
 
 	// If there were no supplied constraints, use the original bootstrap
 	// constraints.
-	if constraints.IsEmpty(&spec.Constraints) || spec.Series == "" {
-		referenceMachine, err := getReferenceController(st, cInfo.MachineIds)
-		if err != nil {
-			return params.ControllersChanges{}, errors.Trace(err)
-		}
-		if constraints.IsEmpty(&spec.Constraints) {
-			cons, err := referenceMachine.Constraints()
-			if err != nil {
-				return params.ControllersChanges{}, errors.Trace(err)
-			}
-			spec.Constraints = cons
-		}
-		if spec.Series == "" {
-			spec.Series = referenceMachine.Series()
-		}
+	if err := applyReferenceDefaults(st, cInfo.MachineIds, &spec.Constraints, &spec.Series); err != nil {
+		return params.ControllersChanges{}, errors.Trace(err)
 	}
 
 	// Retrieve the controller configuration and merge any implied space
@@ -133,6 +142,12 @@ func (api *HighAvailabilityAPI) enableHASingle(st *state.State, spec params.Cont
 		return params.ControllersChanges{}, errors.Trace(err)
 	}
 
+	// Spread any new controller machines across the availability
+	// zones of the cloud, unless we've been told where to put them.
+	if len(spec.Placement) == 0 {
+		spec.Placement = controllerZonePlacements(st, spec, cInfo.MachineIds)
+	}
+
 	// Might be nicer to pass the spec itself to this method.
 	changes, err := st.EnableHA(spec.NumControllers, spec.Constraints, spec.Series, spec.Placement)
 	if err != nil {
@@ -141,6 +156,99 @@ func (api *HighAvailabilityAPI) enableHASingle(st *state.State, spec params.Cont
 	return controllersChanges(changes), nil
 }
 
+// EnableReadReplicas adds read replica controller machines as necessary
+// to ensure the controller has the number of read replicas specified.
+// Read replicas never vote in the controllers' replica set, and their
+// API servers serve only read-only requests from a mongo secondary.
+func (api *HighAvailabilityAPI) EnableReadReplicas(args params.ReadReplicasSpecs) (params.ControllersChangeResults, error) {
+	results := params.ControllersChangeResults{}
+
+	admin, err := api.authorizer.HasPermission(permission.SuperuserAccess, api.state.ControllerTag())
+	if err != nil && !errors.IsNotFound(err) {
+		return results, errors.Trace(err)
+	}
+	if !admin {
+		return results, common.ServerError(common.ErrPerm)
+	}
+
+	if len(args.Specs) == 0 {
+		return results, nil
+	}
+	if len(args.Specs) > 1 {
+		return results, errors.New("only one read replica spec is supported")
+	}
+
+	result, err := api.enableReadReplicasSingle(api.state, args.Specs[0])
+	results.Results = make([]params.ControllersChangeResult, 1)
+	results.Results[0].Result = result
+	results.Results[0].Error = common.ServerError(err)
+	return results, nil
+}
+
+func (api *HighAvailabilityAPI) enableReadReplicasSingle(st *state.State, spec params.ReadReplicasSpec) (
+	params.ControllersChanges, error,
+) {
+	if !st.IsController() {
+		return params.ControllersChanges{}, errors.New("unsupported with hosted models")
+	}
+	blockChecker := common.NewBlockChecker(st)
+	if err := blockChecker.ChangeAllowed(); err != nil {
+		return params.ControllersChanges{}, errors.Trace(err)
+	}
+
+	cInfo, err := st.ControllerInfo()
+	if err != nil {
+		return params.ControllersChanges{}, err
+	}
+	if err := applyReferenceDefaults(st, cInfo.MachineIds, &spec.Constraints, &spec.Series); err != nil {
+		return params.ControllersChanges{}, errors.Trace(err)
+	}
+
+	cfg, err := st.ControllerConfig()
+	if err != nil {
+		return params.ControllersChanges{}, errors.Annotate(err, "retrieving controller config")
+	}
+	spec.Constraints.Spaces = cfg.AsSpaceConstraints(spec.Constraints.Spaces)
+
+	// Read replicas are spread across zones like the other
+	// controller machines.
+	if len(spec.Placement) == 0 {
+		spec.Placement = controllerZonePlacements(st, params.ControllersSpec{
+			Constraints: spec.Constraints,
+			Series:      spec.Series,
+		}, cInfo.MachineIds)
+	}
+
+	changes, err := st.EnableReadReplicas(spec.NumReplicas, spec.Constraints, spec.Series, spec.Placement)
+	if err != nil {
+		return params.ControllersChanges{}, err
+	}
+	return controllersChanges(changes), nil
+}
+
+// applyReferenceDefaults sets empty constraints and series to those of
+// the reference controller machine.
+func applyReferenceDefaults(st *state.State, machineIds []string, cons *constraints.Value, series *string) error {
+	if !constraints.IsEmpty(cons) && *series != "" {
+		return nil
+	}
+	referenceMachine, err := getReferenceController(st, machineIds)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if constraints.IsEmpty(cons) {
+		referenceCons, err := referenceMachine.Constraints()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		*cons = referenceCons
+	}
+	if *series == "" {
+		*series = referenceMachine.Series()
+	}
+	return nil
+}
+
 // getReferenceController looks up the ideal controller to use as a reference for Constraints and Series
 func getReferenceController(st *state.State, machineIds []string) (*state.Machine, error) {
 	// Sort the controller IDs from low to high and take the first.
@@ -168,6 +276,106 @@ func getReferenceController(st *state.State, machineIds []string) (*state.Machin
 	return controller, nil
 }
 
+// newEnviron returns the environ of the controller model. It is a
+// variable so that it can be replaced in tests.
+var newEnviron = func(st *state.State) (environs.Environ, error) {
+	return stateenvirons.GetNewEnvironFunc(environs.New)(st)
+}
+
+// controllerZonePlacements returns zone placement directives that spread
+// new controller machines across the availability zones of the cloud,
+// starting with the zones holding the fewest existing controllers.
+// No directives are returned if the provider does not support zones, or
+// if fewer than two zones can be used; new machines are then distributed
+// by the provisioner as usual.
+func controllerZonePlacements(st *state.State, spec params.ControllersSpec, machineIds []string) []string {
+	env, err := newEnviron(st)
+	if err != nil {
+		logger.Warningf("cannot spread controllers across zones: %v", err)
+		return nil
+	}
+	zonedEnv, ok := env.(providercommon.ZonedEnviron)
+	if !ok {
+		return nil
+	}
+	ctx := state.CallContext(st)
+	zones, err := zonedEnv.AvailabilityZones(ctx)
+	if err != nil {
+		logger.Warningf("cannot spread controllers across zones: %v", err)
+		return nil
+	}
+
+	var allowed set.Strings
+	if spec.Constraints.HasZones() {
+		allowed = set.NewStrings(*spec.Constraints.Zones...)
+	}
+	var usable []string
+	for _, zone := range zones {
+		if !zone.Available() || (allowed != nil && !allowed.Contains(zone.Name())) {
+			continue
+		}
+		// Only use zones that the provider will accept for
+		// the new machines.
+		if err := env.PrecheckInstance(ctx, environs.PrecheckInstanceParams{
+			Series:      spec.Series,
+			Constraints: spec.Constraints,
+			Placement:   zonePlacement(zone.Name()),
+		}); err != nil {
+			logger.Debugf("not placing controllers in zone %q: %v", zone.Name(), err)
+			continue
+		}
+		usable = append(usable, zone.Name())
+	}
+	if len(usable) < 2 {
+		return nil
+	}
+
+	used := make(map[string]int)
+	for _, id := range machineIds {
+		m, err := st.Machine(id)
+		if err != nil {
+			logger.Warningf("cannot spread controllers across zones: %v", err)
+			return nil
+		}
+		zone, err := m.AvailabilityZone()
+		if err != nil {
+			// The machine isn't provisioned yet.
+			continue
+		}
+		if zone != "" {
+			used[zone]++
+		}
+	}
+	return zonePlacements(usable, used, replicaset.MaxPeers)
+}
+
+// zonePlacements returns count zone placement directives. Each one is
+// for the zone holding the fewest machines, once the machines placed by
+// the preceding directives are taken into account; ties go to the zone
+// that comes first.
+func zonePlacements(zones []string, used map[string]int, count int) []string {
+	counts := make(map[string]int)
+	for _, zone := range zones {
+		counts[zone] = used[zone]
+	}
+	result := make([]string, count)
+	for i := range result {
+		best := zones[0]
+		for _, zone := range zones[1:] {
+			if counts[zone] < counts[best] {
+				best = zone
+			}
+		}
+		counts[best]++
+		result[i] = zonePlacement(best)
+	}
+	return result
+}
+
+func zonePlacement(zone string) string {
+	return "zone=" + zone
+}
+
 // validateCurrentControllers checks for a scenario where there is no HA space
 // in controller configuration and more than one machine-local address on any
 // of the controller machines. An error is returned if it is detected.
@@ -317,3 +525,10 @@ func (api *HighAvailabilityAPI) StopHAReplicationForUpgrade(args params.UpgradeM
 func (api *HighAvailabilityAPI) ResumeHAReplicationAfterUpgrade(args params.ResumeReplicationParams) error {
 	return api.state.ResumeReplication(args.Members)
 }
+
+// Mask out new methods from the old API versions. The API reflection
+// code in rpc/rpcreflect/type.go:newMethod skips 2-argument methods,
+// so this removes the method as far as the RPC machinery is concerned.
+//
+// EnableReadReplicas did not exist prior to v3.
+func (*HighAvailabilityAPIV2) EnableReadReplicas(_, _ struct{}) {}
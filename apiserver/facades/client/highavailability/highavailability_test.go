@@ -599,3 +599,57 @@ func (s *clientSuite) TestEnableHABootstrap(c *gc.C) {
 	c.Assert(enableHAResult.Converted, gc.HasLen, 0)
 	c.Assert(enableHAResult.Demoted, gc.HasLen, 0)
 }
+
+func (s *clientSuite) TestEnableReadReplicas(c *gc.C) {
+	results, err := s.haServer.EnableReadReplicas(params.ReadReplicasSpecs{
+		Specs: []params.ReadReplicasSpec{{NumReplicas: 2}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].Result.Added, gc.DeepEquals, []string{"machine-1", "machine-2"})
+	c.Assert(results.Results[0].Result.Maintained, gc.HasLen, 0)
+
+	for _, id := range []string{"1", "2"} {
+		m, err := s.State.Machine(id)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(m.IsReadReplica(), jc.IsTrue)
+		c.Check(m.WantsVote(), jc.IsFalse)
+		c.Check(m.Series(), gc.Equals, "quantal")
+		cons, err := m.Constraints()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(cons, gc.DeepEquals, controllerCons)
+	}
+}
+
+func (s *clientSuite) TestEnableReadReplicasMultipleSpecs(c *gc.C) {
+	results, err := s.haServer.EnableReadReplicas(params.ReadReplicasSpecs{
+		Specs: []params.ReadReplicasSpec{
+			{NumReplicas: 1},
+			{NumReplicas: 2},
+		},
+	})
+	c.Check(err, gc.ErrorMatches, "only one read replica spec is supported")
+	c.Check(results.Results, gc.HasLen, 0)
+}
+
+type zonePlacementsSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&zonePlacementsSuite{})
+
+func (s *zonePlacementsSuite) TestSpreadsAcrossZones(c *gc.C) {
+	placements := highavailability.ZonePlacements([]string{"az1", "az2", "az3"}, nil, 5)
+	c.Assert(placements, jc.DeepEquals, []string{
+		"zone=az1", "zone=az2", "zone=az3", "zone=az1", "zone=az2",
+	})
+}
+
+func (s *zonePlacementsSuite) TestFavoursLeastUsedZones(c *gc.C) {
+	used := map[string]int{"az1": 1, "az2": 2, "elsewhere": 5}
+	placements := highavailability.ZonePlacements([]string{"az1", "az2", "az3"}, used, 4)
+	c.Assert(placements, jc.DeepEquals, []string{
+		"zone=az3", "zone=az1", "zone=az3", "zone=az1",
+	})
+}
@@ -0,0 +1,71 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package modelbackups defines an API endpoint for backing up
+// individual models.
+package modelbackups
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/common/migrationcommon"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/migration"
+	"github.com/juju/juju/permission"
+)
+
+// Backend defines the state functionality required by the
+// modelbackups facade.
+type Backend interface {
+	migration.StateExporter
+	ControllerTag() names.ControllerTag
+}
+
+// API implements the ModelBackups API facade.
+type API struct {
+	backend Backend
+}
+
+// NewFacade is used for API registration.
+func NewFacade(ctx facade.Context) (*API, error) {
+	return NewAPI(ctx.State(), ctx.Auth())
+}
+
+// NewAPI returns a new ModelBackups API facade.
+func NewAPI(backend Backend, authorizer facade.Authorizer) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	// A model backup contains everything needed to recreate the
+	// model, including its cloud credential, so like a migration it
+	// is only available to controller administrators.
+	isAdmin, err := authorizer.HasPermission(permission.SuperuserAccess, backend.ControllerTag())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !isAdmin {
+		return nil, common.ErrPerm
+	}
+	return &API{backend: backend}, nil
+}
+
+// Export serializes the model associated with the API connection in
+// the model migration format, and lists the charms and resources the
+// model uses so that the client can download them too.
+func (api *API) Export() (params.SerializedModel, error) {
+	model, err := api.backend.Export()
+	if err != nil {
+		return params.SerializedModel{}, errors.Trace(err)
+	}
+	serialized, err := migrationcommon.SerializeModel(model)
+	if err != nil {
+		return params.SerializedModel{}, errors.Trace(err)
+	}
+	// The controller that a backup is restored into provides the
+	// agent binaries, so they are not included.
+	serialized.Tools = nil
+	return serialized, nil
+}
@@ -0,0 +1,116 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package modelbackups_test
+
+import (
+	"github.com/juju/description"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facades/client/modelbackups"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type modelBackupsSuite struct {
+	coretesting.BaseSuite
+	backend *mockBackend
+	auth    apiservertesting.FakeAuthorizer
+}
+
+var _ = gc.Suite(&modelBackupsSuite{})
+
+func (s *modelBackupsSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.backend = &mockBackend{
+		model: description.NewModel(description.ModelArgs{
+			Type:   description.IAAS,
+			Config: map[string]interface{}{"uuid": coretesting.ModelTag.Id(), "name": "foo"},
+			Owner:  names.NewUserTag("admin"),
+		}),
+	}
+	s.auth = apiservertesting.FakeAuthorizer{
+		Tag:      names.NewUserTag("admin"),
+		AdminTag: names.NewUserTag("admin"),
+	}
+}
+
+func (s *modelBackupsSuite) TestNewAPINotClient(c *gc.C) {
+	s.auth.Tag = names.NewMachineTag("0")
+	_, err := modelbackups.NewAPI(s.backend, s.auth)
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *modelBackupsSuite) TestNewAPINotSuperuser(c *gc.C) {
+	s.auth.Tag = names.NewUserTag("bob")
+	_, err := modelbackups.NewAPI(s.backend, s.auth)
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *modelBackupsSuite) TestExport(c *gc.C) {
+	app := s.backend.model.AddApplication(description.ApplicationArgs{
+		Tag:      names.NewApplicationTag("foo"),
+		CharmURL: "cs:foo-0",
+	})
+	res := app.AddResource(description.ResourceArgs{"bin"})
+	res.SetApplicationRevision(description.ResourceRevisionArgs{
+		Revision: 2,
+		Type:     "file",
+		Path:     "bin.tar.gz",
+		Origin:   "upload",
+	})
+	m := s.backend.model.AddMachine(description.MachineArgs{Id: names.NewMachineTag("0")})
+	m.SetTools(description.AgentToolsArgs{
+		Version: version.MustParseBinary("2.5.0-xenial-amd64"),
+	})
+
+	api, err := modelbackups.NewAPI(s.backend, s.auth)
+	c.Assert(err, jc.ErrorIsNil)
+	serialized, err := api.Export()
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.backend.CheckCallNames(c, "ControllerTag", "Export")
+	model, err := description.Deserialize(serialized.Bytes)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(model.Tag(), gc.Equals, coretesting.ModelTag)
+	c.Check(serialized.Charms, jc.DeepEquals, []string{"cs:foo-0"})
+	c.Assert(serialized.Resources, gc.HasLen, 1)
+	c.Check(serialized.Resources[0].Application, gc.Equals, "foo")
+	c.Check(serialized.Resources[0].Name, gc.Equals, "bin")
+	c.Check(serialized.Resources[0].ApplicationRevision.Revision, gc.Equals, 2)
+	// Agent binaries are left to the restoring controller.
+	c.Check(serialized.Tools, gc.HasLen, 0)
+}
+
+func (s *modelBackupsSuite) TestExportError(c *gc.C) {
+	s.backend.SetErrors(nil, errors.New("boom"))
+	api, err := modelbackups.NewAPI(s.backend, s.auth)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = api.Export()
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+type mockBackend struct {
+	testing.Stub
+	model description.Model
+}
+
+func (b *mockBackend) ControllerTag() names.ControllerTag {
+	b.MethodCall(b, "ControllerTag")
+	b.PopNoErr()
+	return coretesting.ControllerTag
+}
+
+func (b *mockBackend) Export() (description.Model, error) {
+	b.MethodCall(b, "Export")
+	if err := b.NextErr(); err != nil {
+		return nil, err
+	}
+	return b.model, nil
+}
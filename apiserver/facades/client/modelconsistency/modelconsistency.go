@@ -0,0 +1,116 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package modelconsistency defines an API endpoint for checking a
+// model's documents for inconsistencies, and repairing those that can
+// be repaired safely.
+package modelconsistency
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/permission"
+	"github.com/juju/juju/state"
+)
+
+// Backend defines the state functionality required by the
+// modelconsistency facade.
+type Backend interface {
+	common.BlockGetter
+	ControllerTag() names.ControllerTag
+	ModelUUID() string
+	CheckConsistency() ([]state.Inconsistency, error)
+	RepairConsistency() ([]state.Inconsistency, error)
+}
+
+// API implements the ModelConsistency API facade.
+type API struct {
+	backend    Backend
+	authorizer facade.Authorizer
+	check      *common.BlockChecker
+}
+
+// NewFacade is used for API registration.
+func NewFacade(ctx facade.Context) (*API, error) {
+	return NewAPI(ctx.State(), ctx.Auth())
+}
+
+// NewAPI returns a new ModelConsistency API facade. Only model and
+// controller administrators can check a model.
+func NewAPI(backend Backend, authorizer facade.Authorizer) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	isAdmin, err := authorizer.HasPermission(permission.SuperuserAccess, backend.ControllerTag())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !isAdmin {
+		modelTag := names.NewModelTag(backend.ModelUUID())
+		isAdmin, err = authorizer.HasPermission(permission.AdminAccess, modelTag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	if !isAdmin {
+		return nil, common.ErrPerm
+	}
+	return &API{
+		backend:    backend,
+		authorizer: authorizer,
+		check:      common.NewBlockChecker(backend),
+	}, nil
+}
+
+// Check returns the inconsistencies found in the model's documents.
+// Nothing is changed.
+func (api *API) Check() (params.ModelInconsistencies, error) {
+	results, err := api.backend.CheckConsistency()
+	if err != nil {
+		return params.ModelInconsistencies{}, errors.Trace(err)
+	}
+	return toParams(results), nil
+}
+
+// Repair checks the model's documents and repairs the inconsistencies
+// that can be repaired safely, returning all those found. Repairs
+// change documents that the controller manages, so only controller
+// administrators can make them.
+func (api *API) Repair() (params.ModelInconsistencies, error) {
+	isAdmin, err := api.authorizer.HasPermission(permission.SuperuserAccess, api.backend.ControllerTag())
+	if err != nil {
+		return params.ModelInconsistencies{}, errors.Trace(err)
+	}
+	if !isAdmin {
+		return params.ModelInconsistencies{}, common.ErrPerm
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return params.ModelInconsistencies{}, errors.Trace(err)
+	}
+	results, err := api.backend.RepairConsistency()
+	if err != nil {
+		return params.ModelInconsistencies{}, errors.Trace(err)
+	}
+	return toParams(results), nil
+}
+
+func toParams(results []state.Inconsistency) params.ModelInconsistencies {
+	out := params.ModelInconsistencies{
+		Results: make([]params.ModelInconsistency, len(results)),
+	}
+	for i, result := range results {
+		out.Results[i] = params.ModelInconsistency{
+			Check:      result.Check,
+			Collection: result.Collection,
+			Id:         result.Id,
+			Message:    result.Message,
+			Repairable: result.Repairable,
+			Repaired:   result.Repaired,
+		}
+	}
+	return out
+}
@@ -0,0 +1,157 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package modelconsistency_test
+
+import (
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facades/client/modelconsistency"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type modelConsistencySuite struct {
+	coretesting.BaseSuite
+	backend *mockBackend
+	auth    apiservertesting.FakeAuthorizer
+}
+
+var _ = gc.Suite(&modelConsistencySuite{})
+
+func (s *modelConsistencySuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.backend = &mockBackend{
+		results: []state.Inconsistency{{
+			Check:      state.CheckUnitCount,
+			Collection: "applications",
+			Id:         "mysql",
+			Message:    `application "mysql" has unit count 5 but 1 units`,
+			Repairable: true,
+		}, {
+			Check:      state.CheckPendingCleanups,
+			Collection: "cleanups",
+			Id:         "5c9d1e8b",
+			Message:    `units cleanup for "mysql" has not run`,
+		}},
+	}
+	s.auth = apiservertesting.FakeAuthorizer{
+		Tag:      names.NewUserTag("admin"),
+		AdminTag: names.NewUserTag("admin"),
+	}
+}
+
+func (s *modelConsistencySuite) newAPI(c *gc.C) *modelconsistency.API {
+	api, err := modelconsistency.NewAPI(s.backend, s.auth)
+	c.Assert(err, jc.ErrorIsNil)
+	return api
+}
+
+func (s *modelConsistencySuite) TestNewAPINotClient(c *gc.C) {
+	s.auth.Tag = names.NewMachineTag("0")
+	_, err := modelconsistency.NewAPI(s.backend, s.auth)
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *modelConsistencySuite) TestNewAPINotAdmin(c *gc.C) {
+	s.auth.Tag = names.NewUserTag("write")
+	_, err := modelconsistency.NewAPI(s.backend, s.auth)
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *modelConsistencySuite) TestNewAPIModelAdmin(c *gc.C) {
+	s.auth.Tag = names.NewUserTag("admin-" + coretesting.ModelTag.String())
+	_, err := modelconsistency.NewAPI(s.backend, s.auth)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *modelConsistencySuite) TestCheck(c *gc.C) {
+	result, err := s.newAPI(c).Check()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ModelInconsistencies{
+		Results: []params.ModelInconsistency{{
+			Check:      "unit-count",
+			Collection: "applications",
+			Id:         "mysql",
+			Message:    `application "mysql" has unit count 5 but 1 units`,
+			Repairable: true,
+		}, {
+			Check:      "pending-cleanups",
+			Collection: "cleanups",
+			Id:         "5c9d1e8b",
+			Message:    `units cleanup for "mysql" has not run`,
+		}},
+	})
+	s.backend.CheckCallNames(c, "CheckConsistency")
+}
+
+func (s *modelConsistencySuite) TestRepair(c *gc.C) {
+	s.backend.results[0].Repaired = true
+	result, err := s.newAPI(c).Repair()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 2)
+	c.Assert(result.Results[0].Repaired, jc.IsTrue)
+	c.Assert(result.Results[1].Repaired, jc.IsFalse)
+	s.backend.CheckCallNames(c, "GetBlockForType", "RepairConsistency")
+}
+
+func (s *modelConsistencySuite) TestRepairModelAdmin(c *gc.C) {
+	s.auth.Tag = names.NewUserTag("admin-" + coretesting.ModelTag.String())
+	_, err := s.newAPI(c).Repair()
+	c.Assert(err, gc.Equals, common.ErrPerm)
+	s.backend.CheckNoCalls(c)
+}
+
+func (s *modelConsistencySuite) TestRepairBlocked(c *gc.C) {
+	s.backend.changeBlocked = true
+	_, err := s.newAPI(c).Repair()
+	c.Assert(err, jc.Satisfies, params.IsCodeOperationBlocked)
+	s.backend.CheckCallNames(c, "GetBlockForType")
+}
+
+type mockBackend struct {
+	testing.Stub
+	results       []state.Inconsistency
+	changeBlocked bool
+}
+
+func (b *mockBackend) ControllerTag() names.ControllerTag {
+	return coretesting.ControllerTag
+}
+
+func (b *mockBackend) ModelUUID() string {
+	return coretesting.ModelTag.Id()
+}
+
+func (b *mockBackend) GetBlockForType(t state.BlockType) (state.Block, bool, error) {
+	b.MethodCall(b, "GetBlockForType", t)
+	if b.changeBlocked && t == state.ChangeBlock {
+		return &mockBlock{t: t}, true, nil
+	}
+	return nil, false, b.NextErr()
+}
+
+func (b *mockBackend) CheckConsistency() ([]state.Inconsistency, error) {
+	b.MethodCall(b, "CheckConsistency")
+	return b.results, b.NextErr()
+}
+
+func (b *mockBackend) RepairConsistency() ([]state.Inconsistency, error) {
+	b.MethodCall(b, "RepairConsistency")
+	return b.results, b.NextErr()
+}
+
+type mockBlock struct {
+	state.Block
+	t state.BlockType
+}
+
+func (m mockBlock) Type() state.BlockType { return m.t }
+
+func (m mockBlock) Message() string { return "" }
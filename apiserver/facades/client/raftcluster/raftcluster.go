@@ -0,0 +1,307 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package raftcluster defines an API endpoint for inspecting and
+// repairing the raft cluster that the controller machines use to
+// manage leases.
+package raftcluster
+
+import (
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/utils"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/permission"
+	raftpubsub "github.com/juju/juju/pubsub/raftcluster"
+	"github.com/juju/juju/state"
+)
+
+var logger = loggo.GetLogger("juju.apiserver.raftcluster")
+
+const (
+	// statusTimeout is how long Status waits for the controller
+	// machines to report the state of their raft nodes.
+	statusTimeout = 5 * time.Second
+
+	// removeServerTimeout is how long RemoveServer waits for the
+	// raft leader to remove the server.
+	removeServerTimeout = 15 * time.Second
+)
+
+// Backend defines the state functionality required by the
+// raftcluster facade.
+type Backend interface {
+	ControllerTag() names.ControllerTag
+	ControllerInfo() (*state.ControllerInfo, error)
+}
+
+// Hub is the part of the central hub used by the raftcluster facade
+// to talk to the raft workers on the controller machines.
+type Hub interface {
+	Publish(topic string, data interface{}) (<-chan struct{}, error)
+	Subscribe(topic string, handler interface{}) (func(), error)
+}
+
+// API implements the RaftCluster API facade.
+type API struct {
+	backend Backend
+	hub     Hub
+	clock   clock.Clock
+}
+
+// NewFacade is used for API registration.
+func NewFacade(ctx facade.Context) (*API, error) {
+	hub, ok := ctx.Hub().(Hub)
+	if !ok {
+		return nil, errors.Errorf("central hub %T does not support subscriptions", ctx.Hub())
+	}
+	return NewAPI(ctx.State(), ctx.Auth(), hub, clock.WallClock)
+}
+
+// NewAPI returns a new RaftCluster API facade.
+func NewAPI(backend Backend, authorizer facade.Authorizer, hub Hub, clock clock.Clock) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	isAdmin, err := authorizer.HasPermission(permission.SuperuserAccess, backend.ControllerTag())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !isAdmin {
+		return nil, common.ErrPerm
+	}
+	return &API{
+		backend: backend,
+		hub:     hub,
+		clock:   clock,
+	}, nil
+}
+
+// Status returns the state of the raft node on each controller
+// machine. Every server in the raft configuration is included; those
+// that did not respond are reported with an error.
+func (api *API) Status() (params.RaftClusterStatus, error) {
+	statuses, err := api.collectStatus()
+	if err != nil {
+		return params.RaftClusterStatus{}, errors.Trace(err)
+	}
+
+	// The leader's view of the configuration is authoritative, but
+	// fall back to any other reported view if there is no leader.
+	var config []raftpubsub.Server
+	for _, id := range set.NewStrings(keys(statuses)...).SortedValues() {
+		status := statuses[id]
+		if status.State == "Leader" {
+			config = status.Servers
+			break
+		}
+		if config == nil {
+			config = status.Servers
+		}
+	}
+	addressIDs := make(map[string]string)
+	for _, server := range config {
+		addressIDs[server.Address] = server.ID
+	}
+
+	var result params.RaftClusterStatus
+	seen := set.NewStrings()
+	for _, server := range config {
+		seen.Add(server.ID)
+		entry := params.RaftServerStatus{
+			ServerID: server.ID,
+			Error: common.ServerError(errors.Errorf(
+				"raft server %q did not report its status", server.ID,
+			)),
+		}
+		if status, ok := statuses[server.ID]; ok {
+			entry = serverStatus(status, addressIDs)
+		}
+		entry.Address = server.Address
+		entry.Suffrage = server.Suffrage
+		result.Servers = append(result.Servers, entry)
+	}
+	// Include any node that responded but isn't in the
+	// configuration, such as a controller that is being added.
+	for _, id := range set.NewStrings(keys(statuses)...).Difference(seen).SortedValues() {
+		result.Servers = append(result.Servers, serverStatus(statuses[id], addressIDs))
+	}
+	return result, nil
+}
+
+func serverStatus(status raftpubsub.Status, addressIDs map[string]string) params.RaftServerStatus {
+	result := params.RaftServerStatus{
+		ServerID:          status.ServerID,
+		State:             status.State,
+		LeaderID:          addressIDs[status.Leader],
+		Term:              status.Term,
+		AppliedIndex:      status.AppliedIndex,
+		LastIndex:         status.LastIndex,
+		LastSnapshotIndex: status.LastSnapshotIndex,
+		LastSnapshotTerm:  status.LastSnapshotTerm,
+	}
+	if status.LastContact != "" {
+		if t, err := time.Parse(time.RFC3339, status.LastContact); err == nil {
+			result.LastContact = &t
+		}
+	}
+	if status.Error != "" {
+		result.Error = &params.Error{Message: status.Error}
+	}
+	return result
+}
+
+func keys(statuses map[string]raftpubsub.Status) []string {
+	var result []string
+	for id := range statuses {
+		result = append(result, id)
+	}
+	return result
+}
+
+// collectStatus asks every controller machine for the state of its
+// raft node, and returns the responses received before all controller
+// machines have responded or the timeout expires.
+func (api *API) collectStatus() (map[string]raftpubsub.Status, error) {
+	info, err := api.backend.ControllerInfo()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	expected := set.NewStrings(info.MachineIds...)
+
+	responseTopic, err := newResponseTopic("status")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	responses := make(chan raftpubsub.Status)
+	done := make(chan struct{})
+	defer close(done)
+	unsubscribe, err := api.hub.Subscribe(
+		responseTopic,
+		func(_ string, status raftpubsub.Status, err error) {
+			if err != nil {
+				logger.Errorf("cannot read raft status: %v", err)
+				return
+			}
+			select {
+			case responses <- status:
+			case <-done:
+			}
+		},
+	)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer unsubscribe()
+
+	_, err = api.hub.Publish(raftpubsub.StatusRequestTopic, raftpubsub.StatusRequest{
+		ResponseTopic: responseTopic,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	statuses := make(map[string]raftpubsub.Status)
+	timeout := api.clock.After(statusTimeout)
+	for !expected.Difference(set.NewStrings(keys(statuses)...)).IsEmpty() {
+		select {
+		case status := <-responses:
+			statuses[status.ServerID] = status
+		case <-timeout:
+			logger.Debugf("timed out waiting for raft status; missing %v",
+				expected.Difference(set.NewStrings(keys(statuses)...)).SortedValues())
+			return statuses, nil
+		}
+	}
+	return statuses, nil
+}
+
+// RemoveServer removes a server from the raft configuration. Unless
+// forced, the server must not belong to a current controller machine,
+// and must not be responding to status requests: the raft membership
+// of live controller machines is maintained automatically, and any
+// such server would be added back.
+func (api *API) RemoveServer(args params.RemoveRaftServerArgs) (params.ErrorResult, error) {
+	err := api.removeServer(args)
+	return params.ErrorResult{Error: common.ServerError(err)}, nil
+}
+
+func (api *API) removeServer(args params.RemoveRaftServerArgs) error {
+	if args.ServerID == "" {
+		return errors.NotValidf("empty server ID")
+	}
+	if !args.Force {
+		info, err := api.backend.ControllerInfo()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if set.NewStrings(info.MachineIds...).Contains(args.ServerID) {
+			return errors.Errorf(
+				"raft server %q belongs to controller machine %s; remove the machine instead",
+				args.ServerID, args.ServerID,
+			)
+		}
+		statuses, err := api.collectStatus()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if _, ok := statuses[args.ServerID]; ok {
+			return errors.Errorf("raft server %q is still running", args.ServerID)
+		}
+	}
+
+	responseTopic, err := newResponseTopic("remove-server")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	responses := make(chan raftpubsub.RemoveServerResponse, 1)
+	unsubscribe, err := api.hub.Subscribe(
+		responseTopic,
+		func(_ string, resp raftpubsub.RemoveServerResponse, err error) {
+			if err != nil {
+				resp.Error = err.Error()
+			}
+			select {
+			case responses <- resp:
+			default:
+			}
+		},
+	)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer unsubscribe()
+
+	_, err = api.hub.Publish(raftpubsub.RemoveServerRequestTopic, raftpubsub.RemoveServerRequest{
+		ServerID:      args.ServerID,
+		ResponseTopic: responseTopic,
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	select {
+	case resp := <-responses:
+		if resp.Error != "" {
+			return errors.New(resp.Error)
+		}
+		return nil
+	case <-api.clock.After(removeServerTimeout):
+		return errors.New("timed out waiting for the raft leader")
+	}
+}
+
+func newResponseTopic(request string) (string, error) {
+	uuid, err := utils.NewUUID()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return "raft." + request + ".response." + uuid.String(), nil
+}
@@ -0,0 +1,277 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package raftcluster_test
+
+import (
+	"time"
+
+	"github.com/juju/clock/testclock"
+	"github.com/juju/pubsub"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facades/client/raftcluster"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/pubsub/centralhub"
+	raftpubsub "github.com/juju/juju/pubsub/raftcluster"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type raftClusterSuite struct {
+	coretesting.BaseSuite
+	backend *mockBackend
+	auth    apiservertesting.FakeAuthorizer
+	hub     *pubsub.StructuredHub
+	clock   *testclock.Clock
+
+	// statuses are the responses to status requests, by server ID.
+	statuses map[string]raftpubsub.Status
+	// removeRequests records the remove server requests received.
+	removeRequests chan raftpubsub.RemoveServerRequest
+	// removeError is returned in response to remove server requests.
+	removeError string
+}
+
+var _ = gc.Suite(&raftClusterSuite{})
+
+func (s *raftClusterSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.backend = &mockBackend{machineIds: []string{"0", "1", "2"}}
+	s.auth = apiservertesting.FakeAuthorizer{
+		Tag:      names.NewUserTag("admin"),
+		AdminTag: names.NewUserTag("admin"),
+	}
+	s.hub = centralhub.New(names.NewMachineTag("0"))
+	s.clock = testclock.NewClock(time.Now())
+	s.removeError = ""
+	s.removeRequests = make(chan raftpubsub.RemoveServerRequest, 1)
+
+	servers := []raftpubsub.Server{
+		{ID: "0", Address: "10.0.0.1:17070", Suffrage: "Voter"},
+		{ID: "1", Address: "10.0.0.2:17070", Suffrage: "Voter"},
+		{ID: "2", Address: "10.0.0.3:17070", Suffrage: "Voter"},
+		{ID: "3", Address: "10.0.0.4:17070", Suffrage: "Voter"},
+	}
+	s.statuses = map[string]raftpubsub.Status{
+		"0": {
+			ServerID:          "0",
+			State:             "Leader",
+			Leader:            "10.0.0.1:17070",
+			Term:              4,
+			AppliedIndex:      120,
+			LastIndex:         121,
+			LastSnapshotIndex: 100,
+			LastSnapshotTerm:  3,
+			Servers:           servers,
+		},
+		"1": {
+			ServerID:     "1",
+			State:        "Follower",
+			Leader:       "10.0.0.1:17070",
+			Term:         4,
+			AppliedIndex: 118,
+			LastIndex:    121,
+			LastContact:  "2019-06-01T10:00:00Z",
+			Servers:      servers,
+		},
+		"2": {
+			ServerID: "2",
+			State:    "Follower",
+			Term:     4,
+			Servers:  servers,
+		},
+	}
+
+	statuses := s.statuses
+	unsubscribe, err := s.hub.Subscribe(
+		raftpubsub.StatusRequestTopic,
+		func(_ string, req raftpubsub.StatusRequest, err error) {
+			c.Check(err, jc.ErrorIsNil)
+			for _, status := range statuses {
+				_, err := s.hub.Publish(req.ResponseTopic, status)
+				c.Check(err, jc.ErrorIsNil)
+			}
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(*gc.C) { unsubscribe() })
+
+	removeRequests := s.removeRequests
+	unsubscribe, err = s.hub.Subscribe(
+		raftpubsub.RemoveServerRequestTopic,
+		func(_ string, req raftpubsub.RemoveServerRequest, err error) {
+			c.Check(err, jc.ErrorIsNil)
+			removeRequests <- req
+			_, err = s.hub.Publish(req.ResponseTopic, raftpubsub.RemoveServerResponse{
+				Error: s.removeError,
+			})
+			c.Check(err, jc.ErrorIsNil)
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(*gc.C) { unsubscribe() })
+}
+
+func (s *raftClusterSuite) newAPI(c *gc.C) *raftcluster.API {
+	api, err := raftcluster.NewAPI(s.backend, s.auth, s.hub, s.clock)
+	c.Assert(err, jc.ErrorIsNil)
+	return api
+}
+
+func (s *raftClusterSuite) TestNewAPINotClient(c *gc.C) {
+	s.auth.Tag = names.NewMachineTag("0")
+	_, err := raftcluster.NewAPI(s.backend, s.auth, s.hub, s.clock)
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *raftClusterSuite) TestNewAPINotSuperuser(c *gc.C) {
+	s.auth.Tag = names.NewUserTag("bob")
+	_, err := raftcluster.NewAPI(s.backend, s.auth, s.hub, s.clock)
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *raftClusterSuite) TestStatus(c *gc.C) {
+	result, err := s.newAPI(c).Status()
+	c.Assert(err, jc.ErrorIsNil)
+
+	lastContact := time.Date(2019, 6, 1, 10, 0, 0, 0, time.UTC)
+	c.Assert(result, jc.DeepEquals, params.RaftClusterStatus{
+		Servers: []params.RaftServerStatus{{
+			ServerID:          "0",
+			Address:           "10.0.0.1:17070",
+			Suffrage:          "Voter",
+			State:             "Leader",
+			LeaderID:          "0",
+			Term:              4,
+			AppliedIndex:      120,
+			LastIndex:         121,
+			LastSnapshotIndex: 100,
+			LastSnapshotTerm:  3,
+		}, {
+			ServerID:     "1",
+			Address:      "10.0.0.2:17070",
+			Suffrage:     "Voter",
+			State:        "Follower",
+			LeaderID:     "0",
+			Term:         4,
+			AppliedIndex: 118,
+			LastIndex:    121,
+			LastContact:  &lastContact,
+		}, {
+			ServerID: "2",
+			Address:  "10.0.0.3:17070",
+			Suffrage: "Voter",
+			State:    "Follower",
+			Term:     4,
+		}, {
+			ServerID: "3",
+			Address:  "10.0.0.4:17070",
+			Suffrage: "Voter",
+			Error: &params.Error{
+				Message: `raft server "3" did not report its status`,
+			},
+		}},
+	})
+}
+
+func (s *raftClusterSuite) TestStatusTimeout(c *gc.C) {
+	delete(s.statuses, "2")
+
+	type result struct {
+		status params.RaftClusterStatus
+		err    error
+	}
+	results := make(chan result, 1)
+	api := s.newAPI(c)
+	go func() {
+		status, err := api.Status()
+		results <- result{status, err}
+	}()
+
+	err := s.clock.WaitAdvance(5*time.Second, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	select {
+	case r := <-results:
+		c.Assert(r.err, jc.ErrorIsNil)
+		c.Assert(r.status.Servers, gc.HasLen, 4)
+		c.Assert(r.status.Servers[2].ServerID, gc.Equals, "2")
+		c.Assert(r.status.Servers[2].Error, gc.ErrorMatches, `raft server "2" did not report its status`)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for status")
+	}
+}
+
+func (s *raftClusterSuite) TestRemoveServer(c *gc.C) {
+	result, err := s.newAPI(c).RemoveServer(params.RemoveRaftServerArgs{ServerID: "3"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.IsNil)
+	select {
+	case req := <-s.removeRequests:
+		c.Assert(req.ServerID, gc.Equals, "3")
+	default:
+		c.Fatalf("remove server request not published")
+	}
+}
+
+func (s *raftClusterSuite) TestRemoveServerError(c *gc.C) {
+	s.removeError = `cannot remove raft leader "3"`
+	result, err := s.newAPI(c).RemoveServer(params.RemoveRaftServerArgs{ServerID: "3"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.ErrorMatches, `cannot remove raft leader "3"`)
+}
+
+func (s *raftClusterSuite) TestRemoveServerControllerMachine(c *gc.C) {
+	result, err := s.newAPI(c).RemoveServer(params.RemoveRaftServerArgs{ServerID: "2"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.ErrorMatches,
+		`raft server "2" belongs to controller machine 2; remove the machine instead`)
+	c.Assert(s.removeRequests, gc.HasLen, 0)
+}
+
+func (s *raftClusterSuite) TestRemoveServerStillRunning(c *gc.C) {
+	s.statuses["3"] = raftpubsub.Status{ServerID: "3", State: "Follower"}
+	result, err := s.newAPI(c).RemoveServer(params.RemoveRaftServerArgs{ServerID: "3"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.ErrorMatches, `raft server "3" is still running`)
+	c.Assert(s.removeRequests, gc.HasLen, 0)
+}
+
+func (s *raftClusterSuite) TestRemoveServerForce(c *gc.C) {
+	result, err := s.newAPI(c).RemoveServer(params.RemoveRaftServerArgs{
+		ServerID: "2",
+		Force:    true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.IsNil)
+	c.Assert(s.removeRequests, gc.HasLen, 1)
+	s.backend.CheckNoCalls(c)
+}
+
+func (s *raftClusterSuite) TestRemoveServerEmptyID(c *gc.C) {
+	result, err := s.newAPI(c).RemoveServer(params.RemoveRaftServerArgs{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.ErrorMatches, "empty server ID not valid")
+}
+
+type mockBackend struct {
+	testing.Stub
+	machineIds []string
+}
+
+func (b *mockBackend) ControllerTag() names.ControllerTag {
+	return coretesting.ControllerTag
+}
+
+func (b *mockBackend) ControllerInfo() (*state.ControllerInfo, error) {
+	b.MethodCall(b, "ControllerInfo")
+	if err := b.NextErr(); err != nil {
+		return nil, err
+	}
+	return &state.ControllerInfo{MachineIds: b.machineIds}, nil
+}
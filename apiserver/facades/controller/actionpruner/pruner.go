@@ -4,6 +4,8 @@
 package actionpruner
 
 import (
+	"github.com/juju/errors"
+
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/params"
@@ -26,14 +28,23 @@ func NewAPI(st *state.State, r facade.Resources, auth facade.Authorizer) (*API,
 	return &API{
 		ModelWatcher: common.NewModelWatcher(m, r, auth),
 		st:           st,
+		model:        m,
 		authorizer:   auth,
 	}, nil
 }
 
+// Prune removes completed actions older than the supplied age, or the
+// age configured for the action's name in the model's
+// action-results-retention config, until the actions collection is
+// within the supplied size. Pinned actions are never removed.
 func (api *API) Prune(p params.ActionPruneArgs) error {
 	if !api.authorizer.AuthController() {
 		return common.ErrPerm
 	}
 
-	return state.PruneActions(api.st, p.MaxHistoryTime, p.MaxHistoryMB)
+	cfg, err := api.model.ModelConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return state.PruneActions(api.st, p.MaxHistoryTime, p.MaxHistoryMB, cfg.ActionResultsRetention())
 }
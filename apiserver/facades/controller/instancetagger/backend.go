@@ -0,0 +1,76 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package instancetagger
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state"
+)
+
+// Backend defines the methods the instance tagger facade needs
+// from state.
+type Backend interface {
+	state.ModelAccessor
+	state.ModelMachinesWatcher
+	state.EntityFinder
+
+	// ControllerConfig returns the controller's configuration.
+	ControllerConfig() (controller.Config, error)
+
+	// Machine returns the machine with the given id.
+	Machine(id string) (Machine, error)
+}
+
+// Machine defines the methods the instance tagger facade needs
+// from state.Machine.
+type Machine interface {
+	Id() string
+	Tag() names.Tag
+	Life() state.Life
+	IsManual() (bool, error)
+	InstanceId() (instance.Id, error)
+
+	// InstanceTags returns the tags that should be set on the
+	// machine's instance.
+	InstanceTags(cfg *config.Config, controllerUUID string) (map[string]string, error)
+}
+
+type backendShim struct {
+	*state.State
+	model *state.Model
+}
+
+// ModelConfig implements Backend.
+func (b *backendShim) ModelConfig() (*config.Config, error) {
+	return b.model.ModelConfig()
+}
+
+// WatchForModelConfigChanges implements Backend.
+func (b *backendShim) WatchForModelConfigChanges() state.NotifyWatcher {
+	return b.model.WatchForModelConfigChanges()
+}
+
+// Machine implements Backend.
+func (b *backendShim) Machine(id string) (Machine, error) {
+	m, err := b.State.Machine(id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return machineShim{m}, nil
+}
+
+type machineShim struct {
+	*state.Machine
+}
+
+// InstanceTags implements Machine.
+func (m machineShim) InstanceTags(cfg *config.Config, controllerUUID string) (map[string]string, error) {
+	return common.MachineInstanceTags(m.Machine, cfg, controllerUUID)
+}
@@ -0,0 +1,124 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package instancetagger provides the API facade used by the
+// instance tagger worker to keep the tags on machine instances
+// up to date with the model.
+package instancetagger
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
+)
+
+// API implements the API facade used by the instance tagger.
+type API struct {
+	*common.LifeGetter
+	*common.ModelWatcher
+	*common.ModelMachinesWatcher
+	*common.UnitsWatcher
+
+	backend       Backend
+	accessMachine common.GetAuthFunc
+}
+
+// NewAPI returns a new instance tagger API facade.
+func NewAPI(backend Backend, resources facade.Resources, authorizer facade.Authorizer) (*API, error) {
+	if !authorizer.AuthController() {
+		return nil, errors.Trace(common.ErrPerm)
+	}
+	accessMachine := common.AuthFuncForTagKind(names.MachineTagKind)
+	return &API{
+		// Life() and WatchUnits() are supported for machines.
+		LifeGetter:   common.NewLifeGetter(backend, accessMachine),
+		UnitsWatcher: common.NewUnitsWatcher(backend, resources, accessMachine),
+		// ModelConfig(), WatchForModelConfigChanges() and
+		// WatchModelMachines() are allowed with unrestricted access.
+		ModelWatcher:         common.NewModelWatcher(backend, resources, authorizer),
+		ModelMachinesWatcher: common.NewModelMachinesWatcher(backend, resources, authorizer),
+		backend:              backend,
+		accessMachine:        accessMachine,
+	}, nil
+}
+
+// NewFacade provides the signature required for facade registration.
+func NewFacade(st *state.State, resources facade.Resources, auth facade.Authorizer) (*API, error) {
+	model, err := st.Model()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return NewAPI(&backendShim{State: st, model: model}, resources, auth)
+}
+
+// InstanceTags returns the tags that should be set on the instance
+// of each of the given machines. No instance id is returned for
+// dead machines, containers and manually provisioned machines, as
+// there is no instance of theirs for the provider to tag; an error
+// satisfying params.IsCodeNotProvisioned is returned for machines
+// that have not yet been provisioned.
+func (api *API) InstanceTags(args params.Entities) (params.InstanceTagsResults, error) {
+	result := params.InstanceTagsResults{
+		Results: make([]params.InstanceTagsResult, len(args.Entities)),
+	}
+	if len(args.Entities) == 0 {
+		return result, nil
+	}
+	canAccess, err := api.accessMachine()
+	if err != nil {
+		return params.InstanceTagsResults{}, errors.Trace(err)
+	}
+	cfg, err := api.backend.ModelConfig()
+	if err != nil {
+		return params.InstanceTagsResults{}, errors.Trace(err)
+	}
+	controllerCfg, err := api.backend.ControllerConfig()
+	if err != nil {
+		return params.InstanceTagsResults{}, errors.Trace(err)
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseMachineTag(entity.Tag)
+		if err != nil || !canAccess(tag) {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		instanceTags, err := api.instanceTags(tag, cfg, controllerCfg.ControllerUUID())
+		result.Results[i].Result = instanceTags
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func (api *API) instanceTags(tag names.MachineTag, cfg *config.Config, controllerUUID string) (*params.InstanceTags, error) {
+	m, err := api.backend.Machine(tag.Id())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := &params.InstanceTags{MachineTag: tag.String()}
+	if m.Life() == state.Dead || names.IsContainerMachine(m.Id()) {
+		return result, nil
+	}
+	manual, err := m.IsManual()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if manual {
+		return result, nil
+	}
+	instanceId, err := m.InstanceId()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	tags, err := m.InstanceTags(cfg, controllerUUID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result.InstanceId = string(instanceId)
+	result.Tags = tags
+	return result, nil
+}
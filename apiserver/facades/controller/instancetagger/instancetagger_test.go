@@ -0,0 +1,196 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package instancetagger_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facades/controller/instancetagger"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type instanceTaggerSuite struct {
+	testing.IsolationSuite
+	backend   *mockBackend
+	resources *common.Resources
+	api       *instancetagger.API
+}
+
+var _ = gc.Suite(&instanceTaggerSuite{})
+
+func (s *instanceTaggerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.backend = &mockBackend{
+		cfg:             coretesting.CustomModelConfig(c, nil),
+		machines:        make(map[string]*mockMachine),
+		configChanges:   make(chan struct{}, 1),
+		machinesChanges: make(chan []string, 1),
+	}
+	s.resources = common.NewResources()
+	s.AddCleanup(func(*gc.C) { s.resources.StopAll() })
+	var err error
+	s.api, err = instancetagger.NewAPI(s.backend, s.resources, apiservertesting.FakeAuthorizer{Controller: true})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *instanceTaggerSuite) addMachine(m *mockMachine) {
+	s.backend.machines[m.id] = m
+}
+
+func (s *instanceTaggerSuite) TestRequiresController(c *gc.C) {
+	_, err := instancetagger.NewAPI(s.backend, s.resources, apiservertesting.FakeAuthorizer{Controller: false})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *instanceTaggerSuite) TestInstanceTags(c *gc.C) {
+	s.addMachine(&mockMachine{id: "0", life: state.Alive, instanceId: "i-0", tags: map[string]string{
+		"juju-units-deployed": "mysql/0",
+	}})
+	// Not yet provisioned.
+	s.addMachine(&mockMachine{id: "1", life: state.Alive})
+	// Dead.
+	s.addMachine(&mockMachine{id: "2", life: state.Dead, instanceId: "i-2"})
+	// Manually provisioned.
+	s.addMachine(&mockMachine{id: "3", life: state.Alive, instanceId: "manual:10.0.0.3", manual: true})
+	// Container.
+	s.addMachine(&mockMachine{id: "0/lxd/0", life: state.Alive, instanceId: "juju-f75cba-0-lxd-0"})
+	s.addMachine(&mockMachine{id: "4", life: state.Dying, instanceId: "i-4"})
+
+	results, err := s.api.InstanceTags(params.Entities{Entities: []params.Entity{
+		{Tag: "machine-0"},
+		{Tag: "machine-1"},
+		{Tag: "machine-2"},
+		{Tag: "machine-3"},
+		{Tag: "machine-0-lxd-0"},
+		{Tag: "machine-4"},
+		{Tag: "machine-5"},
+		{Tag: "unit-mysql-0"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	modelUUID := coretesting.ModelTag.Id()
+	controllerUUID := coretesting.ControllerTag.Id()
+	c.Assert(results, jc.DeepEquals, params.InstanceTagsResults{
+		Results: []params.InstanceTagsResult{{
+			Result: &params.InstanceTags{
+				MachineTag: "machine-0",
+				InstanceId: "i-0",
+				Tags: map[string]string{
+					"juju-model-uuid":      modelUUID,
+					"juju-controller-uuid": controllerUUID,
+					"juju-units-deployed":  "mysql/0",
+				},
+			},
+		}, {
+			Error: &params.Error{Code: params.CodeNotProvisioned, Message: "machine 1 not provisioned"},
+		}, {
+			Result: &params.InstanceTags{MachineTag: "machine-2"},
+		}, {
+			Result: &params.InstanceTags{MachineTag: "machine-3"},
+		}, {
+			Result: &params.InstanceTags{MachineTag: "machine-0-lxd-0"},
+		}, {
+			Result: &params.InstanceTags{
+				MachineTag: "machine-4",
+				InstanceId: "i-4",
+				Tags: map[string]string{
+					"juju-model-uuid":      modelUUID,
+					"juju-controller-uuid": controllerUUID,
+				},
+			},
+		}, {
+			Error: &params.Error{Code: params.CodeNotFound, Message: "machine 5 not found"},
+		}, {
+			Error: &params.Error{Code: params.CodeUnauthorized, Message: "permission denied"},
+		}},
+	})
+}
+
+func (s *instanceTaggerSuite) TestInstanceTagsMachineError(c *gc.C) {
+	s.addMachine(&mockMachine{id: "0", life: state.Alive, instanceId: "i-0", tagsErr: errors.New("boom")})
+
+	results, err := s.api.InstanceTags(params.Entities{Entities: []params.Entity{{Tag: "machine-0"}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, "boom")
+}
+
+func (s *instanceTaggerSuite) TestInstanceTagsError(c *gc.C) {
+	s.backend.SetErrors(errors.New("no config for you"))
+
+	_, err := s.api.InstanceTags(params.Entities{Entities: []params.Entity{{Tag: "machine-0"}}})
+	c.Assert(err, gc.ErrorMatches, "no config for you")
+}
+
+func (s *instanceTaggerSuite) TestInstanceTagsNoEntities(c *gc.C) {
+	results, err := s.api.InstanceTags(params.Entities{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 0)
+	s.backend.CheckNoCalls(c)
+}
+
+func (s *instanceTaggerSuite) TestWatchForModelConfigChanges(c *gc.C) {
+	s.backend.configChanges <- struct{}{}
+
+	result, err := s.api.WatchForModelConfigChanges()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.NotifyWatcherId, gc.Equals, "1")
+	c.Assert(s.resources.Get("1"), gc.NotNil)
+}
+
+func (s *instanceTaggerSuite) TestWatchModelMachines(c *gc.C) {
+	s.backend.machinesChanges <- []string{"0", "1"}
+
+	result, err := s.api.WatchModelMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.StringsWatchResult{
+		StringsWatcherId: "1",
+		Changes:          []string{"0", "1"},
+	})
+	c.Assert(s.resources.Get("1"), gc.NotNil)
+}
+
+func (s *instanceTaggerSuite) TestWatchUnits(c *gc.C) {
+	unitsChanges := make(chan []string, 1)
+	unitsChanges <- []string{"mysql/0"}
+	s.addMachine(&mockMachine{id: "0", unitsChanges: unitsChanges})
+
+	results, err := s.api.WatchUnits(params.Entities{Entities: []params.Entity{
+		{Tag: "machine-0"},
+		{Tag: "unit-mysql-0"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.StringsWatchResults{
+		Results: []params.StringsWatchResult{{
+			StringsWatcherId: "1",
+			Changes:          []string{"mysql/0"},
+		}, {
+			Error: &params.Error{Code: params.CodeUnauthorized, Message: "permission denied"},
+		}},
+	})
+	c.Assert(s.resources.Get("1"), gc.NotNil)
+}
+
+func (s *instanceTaggerSuite) TestLife(c *gc.C) {
+	s.addMachine(&mockMachine{id: "0", life: state.Dying})
+
+	results, err := s.api.Life(params.Entities{Entities: []params.Entity{
+		{Tag: "machine-0"},
+		{Tag: "machine-1"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.LifeResults{
+		Results: []params.LifeResult{{
+			Life: params.Dying,
+		}, {
+			Error: &params.Error{Code: params.CodeNotFound, Message: "machine 1 not found"},
+		}},
+	})
+}
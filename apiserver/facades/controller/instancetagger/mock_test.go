@@ -0,0 +1,121 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package instancetagger_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/facades/controller/instancetagger"
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type mockBackend struct {
+	testing.Stub
+	cfg             *config.Config
+	machines        map[string]*mockMachine
+	configChanges   chan struct{}
+	machinesChanges chan []string
+}
+
+func (b *mockBackend) ModelConfig() (*config.Config, error) {
+	b.MethodCall(b, "ModelConfig")
+	return b.cfg, b.NextErr()
+}
+
+func (b *mockBackend) WatchForModelConfigChanges() state.NotifyWatcher {
+	b.MethodCall(b, "WatchForModelConfigChanges")
+	return statetesting.NewMockNotifyWatcher(b.configChanges)
+}
+
+func (b *mockBackend) WatchModelMachines() state.StringsWatcher {
+	b.MethodCall(b, "WatchModelMachines")
+	return statetesting.NewMockStringsWatcher(b.machinesChanges)
+}
+
+func (b *mockBackend) ControllerConfig() (controller.Config, error) {
+	b.MethodCall(b, "ControllerConfig")
+	return coretesting.FakeControllerConfig(), b.NextErr()
+}
+
+func (b *mockBackend) FindEntity(tag names.Tag) (state.Entity, error) {
+	b.MethodCall(b, "FindEntity", tag)
+	if err := b.NextErr(); err != nil {
+		return nil, err
+	}
+	return b.machine(tag.Id())
+}
+
+func (b *mockBackend) Machine(id string) (instancetagger.Machine, error) {
+	b.MethodCall(b, "Machine", id)
+	if err := b.NextErr(); err != nil {
+		return nil, err
+	}
+	return b.machine(id)
+}
+
+func (b *mockBackend) machine(id string) (*mockMachine, error) {
+	m, ok := b.machines[id]
+	if !ok {
+		return nil, errors.NotFoundf("machine %s", id)
+	}
+	return m, nil
+}
+
+type mockMachine struct {
+	id           string
+	life         state.Life
+	manual       bool
+	instanceId   instance.Id
+	tags         map[string]string
+	tagsErr      error
+	unitsChanges chan []string
+}
+
+func (m *mockMachine) Id() string {
+	return m.id
+}
+
+func (m *mockMachine) Tag() names.Tag {
+	return names.NewMachineTag(m.id)
+}
+
+func (m *mockMachine) Life() state.Life {
+	return m.life
+}
+
+func (m *mockMachine) IsManual() (bool, error) {
+	return m.manual, nil
+}
+
+func (m *mockMachine) InstanceId() (instance.Id, error) {
+	if m.instanceId == "" {
+		return "", errors.NotProvisionedf("machine %v", m.id)
+	}
+	return m.instanceId, nil
+}
+
+func (m *mockMachine) WatchUnits() state.StringsWatcher {
+	return statetesting.NewMockStringsWatcher(m.unitsChanges)
+}
+
+func (m *mockMachine) InstanceTags(cfg *config.Config, controllerUUID string) (map[string]string, error) {
+	if m.tagsErr != nil {
+		return nil, m.tagsErr
+	}
+	tags := map[string]string{
+		"juju-model-uuid":      cfg.UUID(),
+		"juju-controller-uuid": controllerUUID,
+	}
+	for k, v := range m.tags {
+		tags[k] = v
+	}
+	return tags, nil
+}
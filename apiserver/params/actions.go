@@ -139,6 +139,18 @@ type ActionSpec struct {
 	Params      map[string]interface{} `json:"params"`
 }
 
+// ActionPin holds whether the results of an action should be protected
+// from pruning.
+type ActionPin struct {
+	Tag    string `json:"tag"`
+	Pinned bool   `json:"pinned"`
+}
+
+// ActionPins holds the arguments for pinning or unpinning action results.
+type ActionPins struct {
+	Actions []ActionPin `json:"actions"`
+}
+
 type ActionPruneArgs struct {
 	MaxHistoryTime time.Duration `json:"max-history-time"`
 	MaxHistoryMB   int           `json:"max-history-mb"`
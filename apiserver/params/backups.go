@@ -14,6 +14,10 @@ type BackupsCreateArgs struct {
 	Notes      string `json:"notes"`
 	KeepCopy   bool   `json:"keep-copy"`
 	NoDownload bool   `json:"no-download"`
+
+	// PublicKey, if set, is the PEM-encoded RSA public key with
+	// which to encrypt the backup archive.
+	PublicKey string `json:"public-key,omitempty"`
 }
 
 // BackupsInfoArgs holds the args for the API Info method.
@@ -70,6 +74,9 @@ type BackupsMetadataResult struct {
 	Version  version.Number `json:"version"`
 	Series   string         `json:"series"`
 
+	Scheduled bool `json:"scheduled,omitempty"`
+	Encrypted bool `json:"encrypted,omitempty"`
+
 	CACert       string `json:"ca-cert"`
 	CAPrivateKey string `json:"ca-private-key"`
 	Filename     string `json:"filename"`
@@ -3,6 +3,8 @@
 
 package params
 
+import "time"
+
 // DestroyControllerArgs holds the arguments for destroying a controller.
 type DestroyControllerArgs struct {
 	// DestroyModels specifies whether or not the hosted models
@@ -104,3 +106,34 @@ const (
 	GrantControllerAccess  ControllerAction = "grant"
 	RevokeControllerAccess ControllerAction = "revoke"
 )
+
+// RaftClusterStatus holds the state of the raft nodes on the
+// controller machines, as returned by RaftCluster.Status.
+type RaftClusterStatus struct {
+	Servers []RaftServerStatus `json:"servers"`
+}
+
+// RaftServerStatus holds the state of a single raft node. Servers in
+// the raft configuration that did not report their state have only
+// their ID, address and suffrage set, along with an error.
+type RaftServerStatus struct {
+	ServerID          string     `json:"server-id"`
+	Address           string     `json:"address,omitempty"`
+	Suffrage          string     `json:"suffrage,omitempty"`
+	State             string     `json:"state,omitempty"`
+	LeaderID          string     `json:"leader-id,omitempty"`
+	Term              uint64     `json:"term"`
+	AppliedIndex      uint64     `json:"applied-index"`
+	LastIndex         uint64     `json:"last-index"`
+	LastContact       *time.Time `json:"last-contact,omitempty"`
+	LastSnapshotIndex uint64     `json:"last-snapshot-index"`
+	LastSnapshotTerm  uint64     `json:"last-snapshot-term"`
+	Error             *Error     `json:"error,omitempty"`
+}
+
+// RemoveRaftServerArgs holds the arguments for
+// RaftCluster.RemoveServer.
+type RemoveRaftServerArgs struct {
+	ServerID string `json:"server-id"`
+	Force    bool   `json:"force,omitempty"`
+}
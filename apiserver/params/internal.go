@@ -366,6 +366,31 @@ type EntityWorkloadVersions struct {
 	Entities []EntityWorkloadVersion `json:"entities"`
 }
 
+// UnitStateResult holds the charm-persisted state of a single unit,
+// or an error indicating why it is not available.
+type UnitStateResult struct {
+	State map[string]string `json:"state,omitempty"`
+	Error *Error            `json:"error,omitempty"`
+}
+
+// UnitStateResults holds the results of a State or UnitsState call.
+type UnitStateResults struct {
+	Results []UnitStateResult `json:"results"`
+}
+
+// SetUnitStateArg holds the charm-persisted state to store for a
+// single unit.
+type SetUnitStateArg struct {
+	Tag   string            `json:"tag"`
+	State map[string]string `json:"state"`
+}
+
+// SetUnitStateArgs holds the parameters for setting the
+// charm-persisted state for a set of units.
+type SetUnitStateArgs struct {
+	Args []SetUnitStateArg `json:"args"`
+}
+
 // BytesResult holds the result of an API call that returns a slice
 // of bytes.
 type BytesResult struct {
@@ -862,3 +887,25 @@ type GoalState struct {
 	Units     UnitsGoalState            `json:"units"`
 	Relations map[string]UnitsGoalState `json:"relations"`
 }
+
+// InstanceTags holds the tags that should be set on the
+// instance of a machine. InstanceId is empty if the machine
+// has no instance for the provider to tag.
+type InstanceTags struct {
+	MachineTag string            `json:"machine-tag"`
+	InstanceId string            `json:"instance-id"`
+	Tags       map[string]string `json:"tags"`
+}
+
+// InstanceTagsResult holds the instance tags for a machine,
+// or an error.
+type InstanceTagsResult struct {
+	Result *InstanceTags `json:"result,omitempty"`
+	Error  *Error        `json:"error,omitempty"`
+}
+
+// InstanceTagsResults holds the instance tags for each
+// of a number of machines.
+type InstanceTagsResults struct {
+	Results []InstanceTagsResult `json:"results"`
+}
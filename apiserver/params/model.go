@@ -425,3 +425,63 @@ type ChangeModelCredentialParams struct {
 type ChangeModelCredentialsParams struct {
 	Models []ChangeModelCredentialParams `json:"model-credentials"`
 }
+
+// ModelInconsistency describes a problem found in a model's documents
+// by a consistency check.
+type ModelInconsistency struct {
+	// Check is the name of the check that found the problem.
+	Check string `json:"check"`
+
+	// Collection is the collection holding the inconsistent document.
+	Collection string `json:"collection"`
+
+	// Id is the id of the inconsistent document within the model.
+	Id string `json:"id"`
+
+	// Message describes the problem.
+	Message string `json:"message"`
+
+	// Repairable reports whether the problem can be repaired safely.
+	Repairable bool `json:"repairable,omitempty"`
+
+	// Repaired reports whether the problem was repaired.
+	Repaired bool `json:"repaired,omitempty"`
+}
+
+// ModelInconsistencies holds the problems found by a model
+// consistency check.
+type ModelInconsistencies struct {
+	Results []ModelInconsistency `json:"results"`
+}
+
+// ModelCleanup describes a cleanup queued in a model that has yet to
+// complete.
+type ModelCleanup struct {
+	// Id identifies the cleanup within the model.
+	Id string `json:"id"`
+
+	// Kind describes what the cleanup does.
+	Kind string `json:"kind"`
+
+	// Target identifies the entity the cleanup applies to, if any.
+	Target string `json:"target,omitempty"`
+
+	// Attempts is the number of times the cleanup has failed.
+	Attempts int `json:"attempts"`
+
+	// LastError is the error the cleanup last failed with.
+	LastError string `json:"last-error,omitempty"`
+
+	// LastAttempt is when the cleanup last failed.
+	LastAttempt *time.Time `json:"last-attempt,omitempty"`
+}
+
+// ModelCleanups holds the cleanups queued in a model.
+type ModelCleanups struct {
+	Results []ModelCleanup `json:"results"`
+}
+
+// CleanupIds holds the ids of cleanups to force or cancel.
+type CleanupIds struct {
+	Ids []string `json:"ids"`
+}
@@ -1027,6 +1027,24 @@ type ControllersSpecs struct {
 	Specs []ControllersSpec `json:"specs"`
 }
 
+// ReadReplicasSpec contains arguments for the
+// EnableReadReplicas client API call.
+type ReadReplicasSpec struct {
+	NumReplicas int               `json:"num-replicas"`
+	Constraints constraints.Value `json:"constraints,omitempty"`
+	// Series is the series to associate with new read replica machines.
+	// If this is empty, then the series of the controllers is used.
+	Series string `json:"series,omitempty"`
+	// Placement defines where to put new read replica machines.
+	Placement []string `json:"placement,omitempty"`
+}
+
+// ReadReplicasSpecs contains all the arguments
+// for the EnableReadReplicas API call.
+type ReadReplicasSpecs struct {
+	Specs []ReadReplicasSpec `json:"specs"`
+}
+
 // ControllersChangeResult contains the results
 // of a single EnableHA API call or
 // an error.
@@ -29,6 +29,7 @@ type FullStatus struct {
 	Offers              map[string]ApplicationOfferStatus  `json:"offers"`
 	Relations           []RelationStatus                   `json:"relations"`
 	ControllerTimestamp *time.Time                         `json:"controller-timestamp"`
+	ControllerWarnings  []string                           `json:"controller-warnings,omitempty"`
 }
 
 // IsEmpty checks all collections on FullStatus to determine if the status is empty.
@@ -0,0 +1,90 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"net/http"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+)
+
+var readOnlyAPIError = errors.New("this controller is a read replica - only read-only API calls are allowed")
+
+// readOnlyMethodsOnly can be used with restrictRoot to restrict the
+// API to the methods that are served by read replica controllers.
+// Those controllers read from a mongo secondary, so only calls that
+// don't write to the database are allowed. The all-watcher isn't
+// served either, as a lagging secondary would feed it stale deltas.
+func readOnlyMethodsOnly(facadeName string, methodName string) error {
+	fullName := facadeName + "." + methodName
+	if !allowedMethodsReadOnly.Contains(fullName) {
+		return readOnlyAPIError
+	}
+	return nil
+}
+
+var allowedMethodsReadOnly = set.NewStrings(
+	"Client.FullStatus",                       // for "juju status"
+	"Client.StatusHistory",                    // for "juju show-status-log"
+	"Client.PrivateAddress",                   // for "juju ssh"
+	"Client.PublicAddress",                    // for "juju ssh"
+	"Client.GetModelConstraints",              // for "juju get-model-constraints"
+	"Client.ModelInfo",                        // for "juju show-model"
+	"Client.ModelUserInfo",                    // for "juju show-model"
+	"Client.AgentVersion",                     // for version checks
+	"Pinger.Ping",                             // for connection health checks
+	"ModelManager.ListModels",                 // for "juju models"
+	"ModelManager.ListModelSummaries",         // for "juju models"
+	"ModelManager.ModelInfo",                  // for "juju show-model"
+	"ModelManager.ModelStatus",                // for "juju show-model"
+	"ModelManager.ModelDefaults",              // for "juju model-defaults"
+	"ModelConfig.ModelGet",                    // for "juju model-config"
+	"ModelConsistency.Check",                  // for "juju check-model"
+	"Cleanups.ListCleanups",                   // for "juju list-cleanups"
+	"Controller.AllModels",                    // for "juju models"
+	"Controller.ModelStatus",                  // for "juju show-controller"
+	"Controller.ControllerConfig",             // for "juju controller-config"
+	"Controller.GetControllerAccess",          // for "juju show-user"
+	"Controller.ListBlockedModels",            // for "juju disabled-commands"
+	"Application.Get",                         // for "juju config"
+	"Application.GetConstraints",              // for "juju get-constraints"
+	"Application.CharmConfig",                 // for "juju config"
+	"Action.Actions",                          // for "juju show-action-output"
+	"Action.FindActionTagsByPrefix",           // for "juju show-action-output"
+	"Action.FindActionsByNames",               // for "juju show-action-status"
+	"Action.ListAll",                          // for "juju show-action-status"
+	"Action.ApplicationsCharmsActions",        // for "juju actions"
+	"Storage.ListStorageDetails",              // for "juju storage"
+	"Storage.StorageDetails",                  // for "juju show-storage"
+	"Storage.ListPools",                       // for "juju storage-pools"
+	"Storage.ListVolumes",                     // for "juju storage --volume"
+	"Storage.ListFilesystems",                 // for "juju storage --filesystem"
+	"Spaces.ListSpaces",                       // for "juju spaces"
+	"Subnets.ListSubnets",                     // for "juju subnets"
+	"Cloud.Cloud",                             // for "juju show-cloud"
+	"Cloud.Clouds",                            // for "juju clouds"
+	"Cloud.DefaultCloud",                      // for "juju clouds"
+	"UserManager.UserInfo",                    // for "juju users" and "juju show-user"
+	"ApplicationOffers.ListApplicationOffers", // for "juju offers"
+	"ApplicationOffers.ApplicationOffers",     // for "juju show-offer"
+	"Annotations.Get",                         // for "juju get-annotations"
+	"Block.List",                              // for "juju disabled-commands"
+	"Backups.List",                            // for "juju backups"
+	"Backups.Info",                            // for "juju show-backup"
+	"SSHClient.PublicAddress",                 // for "juju ssh"
+	"SSHClient.PrivateAddress",                // for "juju ssh"
+	"SSHClient.AllAddresses",                  // for "juju ssh"
+	"SSHClient.PublicKeys",                    // for "juju ssh"
+	"SSHClient.Proxy",                         // for "juju ssh"
+	"RaftCluster.Status",                      // for "juju show-raft-status"
+)
+
+// readOnlyHTTPMethods are the HTTP methods served by read replica
+// controllers.
+var readOnlyHTTPMethods = set.NewStrings(
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodOptions,
+)
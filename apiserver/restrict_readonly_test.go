@@ -0,0 +1,40 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver"
+	"github.com/juju/juju/testing"
+)
+
+type restrictReadOnlySuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&restrictReadOnlySuite{})
+
+func (r *restrictReadOnlySuite) TestAllowed(c *gc.C) {
+	root := apiserver.TestingReadOnlyRoot()
+	caller, err := root.FindMethod("Client", 2, "FullStatus")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(caller, gc.NotNil)
+}
+
+func (r *restrictReadOnlySuite) TestNotAllowed(c *gc.C) {
+	root := apiserver.TestingReadOnlyRoot()
+	caller, err := root.FindMethod("Application", 1, "Deploy")
+	c.Assert(err, gc.ErrorMatches, "this controller is a read replica - only read-only API calls are allowed")
+	c.Assert(caller, gc.IsNil)
+}
+
+func (r *restrictReadOnlySuite) TestAllWatcherNotAllowed(c *gc.C) {
+	root := apiserver.TestingReadOnlyRoot()
+	_, err := root.FindMethod("Client", 2, "WatchAll")
+	c.Assert(err, gc.ErrorMatches, "this controller is a read replica - only read-only API calls are allowed")
+	_, err = root.FindMethod("AllWatcher", 1, "Next")
+	c.Assert(err, gc.ErrorMatches, "this controller is a read replica - only read-only API calls are allowed")
+}
@@ -202,6 +202,18 @@ func restrictAPIRoot(
 		}
 		apiRoot = restrictedRoot
 	}
+	if srv.readOnly && !auth.controllerMachineLogin {
+		// Only users may use a read replica's API server, and
+		// then only for calls that don't change anything.
+		if !auth.userLogin {
+			login := "anonymous login"
+			if !auth.anonymousLogin {
+				login = fmt.Sprintf("login for %s", names.ReadableString(auth.tag))
+			}
+			return nil, errors.Errorf("%s blocked because this controller is a read replica", login)
+		}
+		apiRoot = restrictRoot(apiRoot, readOnlyMethodsOnly)
+	}
 	if auth.controllerOnlyLogin {
 		apiRoot = restrictRoot(apiRoot, controllerFacadesOnly)
 	} else {
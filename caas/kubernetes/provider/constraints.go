@@ -17,6 +17,10 @@ var unsupportedConstraints = []string{
 	constraints.RootDisk,
 	constraints.InstanceType,
 	constraints.Spaces,
+	constraints.Spot,
+	constraints.SpotMaxPrice,
+	constraints.AntiAffinity,
+	constraints.RootDiskSource,
 }
 
 // ConstraintsValidator returns a Validator value which is used to
@@ -24,5 +28,6 @@ var unsupportedConstraints = []string{
 func (k *kubernetesClient) ConstraintsValidator(ctx context.ProviderCallContext) (constraints.Validator, error) {
 	validator := constraints.NewValidator()
 	validator.RegisterUnsupported(unsupportedConstraints)
+	validator.RegisterRejected([]string{constraints.InstanceRole})
 	return validator, nil
 }
@@ -854,15 +854,25 @@ func FinishInstanceConfig(icfg *InstanceConfig, cfg *config.Config) (err error)
 }
 
 // InstanceTags returns the minimum set of tags that should be set on a
-// machine instance, if the provider supports them.
-func InstanceTags(modelUUID, controllerUUID string, tagger tags.ResourceTagger, jobs []multiwatcher.MachineJob) map[string]string {
-	instanceTags := tags.ResourceTags(
+// machine instance, if the provider supports them. The values of any
+// template variables in user-defined tags are taken from vars, and the
+// names of the user-defined tags are recorded so that they can later be
+// removed from the instance.
+func InstanceTags(
+	modelUUID, controllerUUID string,
+	tagger tags.ResourceTagger,
+	vars tags.TemplateVars,
+	jobs []multiwatcher.MachineJob,
+) map[string]string {
+	instanceTags := tags.ResourceTagsWithVars(
 		names.NewModelTag(modelUUID),
 		names.NewControllerTag(controllerUUID),
+		vars,
 		tagger,
 	)
 	if multiwatcher.AnyJobNeedsState(jobs...) {
 		instanceTags[tags.JujuIsController] = "true"
 	}
+	tags.RecordManagedTags(instanceTags)
 	return instanceTags
 }
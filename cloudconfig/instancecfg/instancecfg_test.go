@@ -43,13 +43,14 @@ func (*instancecfgSuite) TestInstanceTagsUserSpecified(c *gc.C) {
 	testInstanceTags(c, cfg, nil, map[string]string{
 		"juju-model-uuid":      testing.ModelTag.Id(),
 		"juju-controller-uuid": testing.ControllerTag.Id(),
+		"juju-managed-tags":    "a c",
 		"a":                    "b",
 		"c":                    "",
 	})
 }
 
 func testInstanceTags(c *gc.C, cfg *config.Config, jobs []multiwatcher.MachineJob, expectTags map[string]string) {
-	tags := instancecfg.InstanceTags(testing.ModelTag.Id(), testing.ControllerTag.Id(), cfg, jobs)
+	tags := instancecfg.InstanceTags(testing.ModelTag.Id(), testing.ControllerTag.Id(), cfg, nil, jobs)
 	c.Assert(tags, jc.DeepEquals, expectTags)
 }
 
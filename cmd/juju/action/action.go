@@ -60,6 +60,9 @@ type APIClient interface {
 	// FindActionsByNames takes a list of names and finds a corresponding list of
 	// Actions for every name.
 	FindActionsByNames(params.FindActionsByNames) (params.ActionsByNames, error)
+
+	// SetPinned pins or unpins the results of the given actions.
+	SetPinned(params.ActionPins) (params.ErrorResults, error)
 }
 
 // ActionCommandBase is the base type for action sub-commands.
@@ -29,6 +29,10 @@ type CancelCommand struct {
 	*cancelCommand
 }
 
+type PinCommand struct {
+	*pinCommand
+}
+
 type RunCommand struct {
 	*runCommand
 }
@@ -83,6 +87,12 @@ func NewCancelCommandForTest(store jujuclient.ClientStore) (cmd.Command, *Cancel
 	return modelcmd.Wrap(c), &CancelCommand{c}
 }
 
+func NewPinCommandForTest(store jujuclient.ClientStore) (cmd.Command, *PinCommand) {
+	c := &pinCommand{}
+	c.SetClientStore(store)
+	return modelcmd.Wrap(c), &PinCommand{c}
+}
+
 func NewListCommandForTest(store jujuclient.ClientStore) (cmd.Command, *ListCommand) {
 	c := &listCommand{}
 	c.SetClientStore(store)
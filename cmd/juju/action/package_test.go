@@ -129,6 +129,7 @@ type fakeAPIClient struct {
 	actionsByReceivers []params.ActionsByReceiver
 	actionTagMatches   params.FindTagsResults
 	actionsByNames     params.ActionsByNames
+	pinnedActions      params.ActionPins
 	charmActions       map[string]params.ActionSpec
 	apiVersion         int
 	apiErr             error
@@ -217,3 +218,9 @@ func (c *fakeAPIClient) FindActionTagsByPrefix(arg params.FindTags) (params.Find
 func (c *fakeAPIClient) FindActionsByNames(args params.FindActionsByNames) (params.ActionsByNames, error) {
 	return c.actionsByNames, c.apiErr
 }
+
+func (c *fakeAPIClient) SetPinned(args params.ActionPins) (params.ErrorResults, error) {
+	c.pinnedActions = args
+	results := make([]params.ErrorResult, len(args.Actions))
+	return params.ErrorResults{Results: results}, c.apiErr
+}
@@ -0,0 +1,105 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+func NewPinCommand() cmd.Command {
+	return modelcmd.Wrap(&pinCommand{})
+}
+
+// pinCommand pins or unpins the results of an action.
+type pinCommand struct {
+	ActionCommandBase
+	requestedId string
+	unpin       bool
+}
+
+const pinDoc = `
+Pin the results of an action, so that they are kept regardless of the
+model's action results retention policy. Pinned results are never
+pruned; use --unpin to make them subject to pruning again.
+
+The action may be identified by its ID or a unique ID prefix.
+
+Examples:
+    juju pin-action-result 1234
+    juju pin-action-result --unpin 1234
+
+See also:
+    show-action-output
+`
+
+// SetFlags implements Command.
+func (c *pinCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ActionCommandBase.SetFlags(f)
+	f.BoolVar(&c.unpin, "unpin", false, "Unpin the action's results, allowing them to be pruned")
+}
+
+// Info implements Command.
+func (c *pinCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "pin-action-result",
+		Args:    "<action ID | action ID prefix>",
+		Purpose: "Protect the results of an action from pruning.",
+		Doc:     pinDoc,
+	}
+}
+
+// Init implements Command.
+func (c *pinCommand) Init(args []string) error {
+	switch len(args) {
+	case 0:
+		return errors.New("no action ID specified")
+	case 1:
+		c.requestedId = args[0]
+		return nil
+	default:
+		return cmd.CheckEmpty(args[1:])
+	}
+}
+
+// Run implements Command.
+func (c *pinCommand) Run(ctx *cmd.Context) error {
+	api, err := c.NewActionAPIClient()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	if api.BestAPIVersion() < 4 {
+		return errors.Errorf("pinning action results is not supported by this controller")
+	}
+
+	actionTag, err := getActionTagByPrefix(api, c.requestedId)
+	if err != nil {
+		return err
+	}
+
+	results, err := api.SetPinned(params.ActionPins{
+		Actions: []params.ActionPin{{
+			Tag:    actionTag.String(),
+			Pinned: !c.unpin,
+		}},
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := results.OneError(); err != nil {
+		return errors.Trace(err)
+	}
+	if c.unpin {
+		ctx.Infof("unpinned results of action %s", actionTag.Id())
+	} else {
+		ctx.Infof("pinned results of action %s", actionTag.Id())
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action_test
+
+import (
+	"time"
+
+	"github.com/juju/cmd/cmdtesting"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/action"
+)
+
+type PinSuite struct {
+	BaseActionSuite
+}
+
+var _ = gc.Suite(&PinSuite{})
+
+func (s *PinSuite) TestInit(c *gc.C) {
+	for i, t := range []struct {
+		args        []string
+		expectError string
+	}{{
+		expectError: "no action ID specified",
+	}, {
+		args:        []string{"foo", "bar"},
+		expectError: `unrecognized args: \["bar"\]`,
+	}, {
+		args: []string{"foo"},
+	}, {
+		args: []string{"--unpin", "foo"},
+	}} {
+		c.Logf("test %d: %v", i, t.args)
+		cmd, _ := action.NewPinCommandForTest(s.store)
+		err := cmdtesting.InitCommand(cmd, t.args)
+		if t.expectError == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, t.expectError)
+		}
+	}
+}
+
+func (s *PinSuite) TestRun(c *gc.C) {
+	prefix := "deadbeef"
+	faketag := "action-" + prefix + "-0000-4000-8000-feedfacebeef"
+
+	for i, t := range []struct {
+		args     []string
+		tags     params.FindTagsResults
+		expected []params.ActionPin
+		stderr   string
+		err      string
+	}{{
+		args: []string{prefix},
+		err:  `actions for identifier "deadbeef" not found`,
+	}, {
+		args:     []string{prefix},
+		tags:     tagsForIdPrefix(prefix, faketag),
+		expected: []params.ActionPin{{Tag: faketag, Pinned: true}},
+		stderr:   "pinned results of action deadbeef-0000-4000-8000-feedfacebeef\n",
+	}, {
+		args:     []string{"--unpin", prefix},
+		tags:     tagsForIdPrefix(prefix, faketag),
+		expected: []params.ActionPin{{Tag: faketag, Pinned: false}},
+		stderr:   "unpinned results of action deadbeef-0000-4000-8000-feedfacebeef\n",
+	}} {
+		c.Logf("test %d: %v", i, t.args)
+		fakeClient := makeFakeClient(0, 5*time.Second, t.tags, nil, params.ActionsByNames{}, "")
+		fakeClient.apiVersion = 4
+		restore := s.patchAPIClient(fakeClient)
+
+		cmd, _ := action.NewPinCommandForTest(s.store)
+		ctx, err := cmdtesting.RunCommand(c, cmd, append([]string{"-m", "admin"}, t.args...)...)
+		restore()
+		if t.err != "" {
+			c.Check(err, gc.ErrorMatches, t.err)
+			continue
+		}
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(fakeClient.pinnedActions.Actions, jc.DeepEquals, t.expected)
+		c.Check(cmdtesting.Stderr(ctx), gc.Equals, t.stderr)
+	}
+}
+
+func (s *PinSuite) TestRunNotSupported(c *gc.C) {
+	fakeClient := makeFakeClient(0, 5*time.Second, params.FindTagsResults{}, nil, params.ActionsByNames{}, "")
+	fakeClient.apiVersion = 3
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	cmd, _ := action.NewPinCommandForTest(s.store)
+	_, err := cmdtesting.RunCommand(c, cmd, "-m", "admin", "deadbeef")
+	c.Assert(err, gc.ErrorMatches, "pinning action results is not supported by this controller")
+}
@@ -38,6 +38,11 @@ to wait indefinitely.  If units are left off, seconds are assumed.
 The default behavior without --wait is to immediately check and return; if
 the results are "pending" then only the available information will be
 displayed.  This is also the behavior when any negative time is given.
+
+If the charm declares a "results" schema for the action in its actions.yaml,
+results declared as integers, numbers or booleans are recorded and shown as
+such rather than as strings, and an action whose results do not match the
+schema is marked as failed.
 `
 
 // Set up the output.
@@ -145,6 +145,14 @@ func NewScaleCommandForTest(api scaleApplicationAPI, store jujuclient.ClientStor
 	return modelcmd.Wrap(cmd)
 }
 
+func NewShowUnitStateCommandForTest(api showUnitStateAPI, store jujuclient.ClientStore) modelcmd.ModelCommand {
+	cmd := &showUnitStateCommand{newAPIFunc: func() (showUnitStateAPI, error) {
+		return api, nil
+	}}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
 func NewBundleDiffCommandForTest(api base.APICallCloser, charmStore BundleResolver, store jujuclient.ClientStore) modelcmd.ModelCommand {
 	cmd := &bundleDiffCommand{
 		_apiRoot:    api,
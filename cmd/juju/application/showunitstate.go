@@ -0,0 +1,106 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/application"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+const showUnitStateDoc = `
+Show the key/value state that a unit's charm has persisted with the
+state-set hook tool. The values are stored by the controller, and so
+survive the removal of the charm directory on the unit's machine.
+
+Examples:
+
+    juju show-unit-state mysql/0
+    juju show-unit-state mysql/0 --format json
+
+See also:
+    show-status-log
+`
+
+// NewShowUnitStateCommand returns a command which shows the
+// charm-persisted state of a unit.
+func NewShowUnitStateCommand() modelcmd.ModelCommand {
+	cmd := &showUnitStateCommand{}
+	cmd.newAPIFunc = func() (showUnitStateAPI, error) {
+		root, err := cmd.NewAPIRoot()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return application.NewClient(root), nil
+	}
+	return modelcmd.Wrap(cmd)
+}
+
+type showUnitStateAPI interface {
+	Close() error
+	UnitsState(...string) ([]map[string]string, error)
+}
+
+// showUnitStateCommand is responsible for showing a unit's state.
+type showUnitStateCommand struct {
+	modelcmd.ModelCommandBase
+
+	newAPIFunc func() (showUnitStateAPI, error)
+	unitName   string
+	out        cmd.Output
+}
+
+// Info implements cmd.Command.
+func (c *showUnitStateCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "show-unit-state",
+		Args:    "<unit>",
+		Purpose: "Displays the state persisted by a unit's charm.",
+		Doc:     showUnitStateDoc,
+	}
+}
+
+// SetFlags implements cmd.Command.
+func (c *showUnitStateCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+}
+
+// Init implements cmd.Command.
+func (c *showUnitStateCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.Errorf("no unit name specified")
+	}
+	if !names.IsValidUnit(args[0]) {
+		return errors.Errorf("invalid unit name %q", args[0])
+	}
+	c.unitName = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements cmd.Command.
+func (c *showUnitStateCommand) Run(ctx *cmd.Context) error {
+	client, err := c.newAPIFunc()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	results, err := client.UnitsState(c.unitName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	unitState := results[0]
+	if unitState == nil {
+		unitState = map[string]string{}
+	}
+	return c.out.Write(ctx, unitState)
+}
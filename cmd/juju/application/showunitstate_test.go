@@ -0,0 +1,89 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/jujuclient/jujuclienttesting"
+)
+
+type ShowUnitStateSuite struct {
+	testing.IsolationSuite
+
+	mockAPI *mockShowUnitStateAPI
+}
+
+var _ = gc.Suite(&ShowUnitStateSuite{})
+
+type mockShowUnitStateAPI struct {
+	*testing.Stub
+	state map[string]string
+}
+
+func (s mockShowUnitStateAPI) Close() error {
+	s.MethodCall(s, "Close")
+	return s.NextErr()
+}
+
+func (s mockShowUnitStateAPI) UnitsState(units ...string) ([]map[string]string, error) {
+	s.MethodCall(s, "UnitsState", units)
+	return []map[string]string{s.state}, s.NextErr()
+}
+
+func (s *ShowUnitStateSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.mockAPI = &mockShowUnitStateAPI{
+		Stub:  &testing.Stub{},
+		state: map[string]string{"foo": "bar", "baz": "qux"},
+	}
+}
+
+func (s *ShowUnitStateSuite) runShowUnitState(c *gc.C, args ...string) (string, error) {
+	store := jujuclienttesting.MinimalStore()
+	ctx, err := cmdtesting.RunCommand(c, NewShowUnitStateCommandForTest(s.mockAPI, store), args...)
+	if err != nil {
+		return "", err
+	}
+	return cmdtesting.Stdout(ctx), nil
+}
+
+func (s *ShowUnitStateSuite) TestShowUnitState(c *gc.C) {
+	out, err := s.runShowUnitState(c, "foo/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, "baz: qux\nfoo: bar\n")
+	s.mockAPI.CheckCall(c, 0, "UnitsState", []string{"foo/0"})
+}
+
+func (s *ShowUnitStateSuite) TestShowUnitStateJSON(c *gc.C) {
+	out, err := s.runShowUnitState(c, "foo/0", "--format", "json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, `{"baz":"qux","foo":"bar"}`+"\n")
+}
+
+func (s *ShowUnitStateSuite) TestShowUnitStateEmpty(c *gc.C) {
+	s.mockAPI.state = nil
+	out, err := s.runShowUnitState(c, "foo/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, "{}\n")
+}
+
+func (s *ShowUnitStateSuite) TestShowUnitStateError(c *gc.C) {
+	s.mockAPI.SetErrors(errors.New("boom"))
+	_, err := s.runShowUnitState(c, "foo/0")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *ShowUnitStateSuite) TestInitErrors(c *gc.C) {
+	_, err := s.runShowUnitState(c)
+	c.Assert(err, gc.ErrorMatches, "no unit name specified")
+	_, err = s.runShowUnitState(c, "foo")
+	c.Assert(err, gc.ErrorMatches, `invalid unit name "foo"`)
+	_, err = s.runShowUnitState(c, "foo/0", "bar")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["bar"\]`)
+}
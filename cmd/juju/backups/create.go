@@ -5,6 +5,7 @@ package backups
 
 import (
 	"io"
+	"io/ioutil"
 	"os"
 	"time"
 
@@ -14,6 +15,7 @@ import (
 
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/modelcmd"
+	corebackups "github.com/juju/juju/core/backups"
 	"github.com/juju/juju/state/backups"
 )
 
@@ -39,16 +41,34 @@ Use --verbose to see extra information about backup.
 
 To access remote backups stored on the controller, see 'juju download-backup'.
 
+The controller can also create backups on a schedule, keeping them remotely.
+Set the "backup-interval" controller configuration value to enable scheduled
+backups, and "backup-keep-daily" and "backup-keep-weekly" to control how many
+are retained. Backups created by users are never removed by the schedule.
+
+Backups kept on the controller are stored in its database by default. Set the
+"backup-storage" controller configuration value to "local" (with
+"backup-storage-path") or "s3" (with the "backup-s3-*" values) to store them in
+a directory, such as an NFS mount, or an S3-compatible object store instead.
+
+Use --public-key to encrypt the backup archive with an RSA public key, so that
+only the holder of the private key can use it. If the "backup-public-key"
+controller configuration value is set, backups are encrypted with that key by
+default. Use 'juju verify-backup' to check an archive, and to decrypt it before
+restoring.
+
 Examples:
     juju create-backup 
     juju create-backup --no-download
     juju create-backup --no-download --keep-copy=false // ignores --keep-copy
     juju create-backup --keep-copy
     juju create-backup --verbose
+    juju create-backup --public-key backup-key.pub.pem
 
 See also:
     backups
     download-backup
+    verify-backup
 `
 
 // NewCreateCommand returns a command used to create backups.
@@ -67,7 +87,10 @@ type createCommand struct {
 	Notes string
 	// KeepCopy means the backup archive should be stored in the controller db.
 	KeepCopy bool
-	fs       *gnuflag.FlagSet
+	// PublicKeyFile is the path to a PEM-encoded RSA public key with
+	// which to encrypt the backup archive.
+	PublicKeyFile string
+	fs            *gnuflag.FlagSet
 }
 
 // Info implements Command.Info.
@@ -86,6 +109,7 @@ func (c *createCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.BoolVar(&c.NoDownload, "no-download", false, "Do not download the archive, implies keep-copy")
 	f.BoolVar(&c.KeepCopy, "keep-copy", false, "Keep a copy of the archive on the controller")
 	f.StringVar(&c.Filename, "filename", notset, "Download to this file")
+	f.StringVar(&c.PublicKeyFile, "public-key", "", "Encrypt the archive with the RSA public key in this PEM file")
 	c.fs = f
 }
 
@@ -136,6 +160,21 @@ func (c *createCommand) Run(ctx *cmd.Context) error {
 	}
 	defer client.Close()
 
+	var publicKey string
+	if c.PublicKeyFile != "" {
+		if apiVersion < 3 {
+			return errors.New("--public-key is not supported by this controller")
+		}
+		data, err := ioutil.ReadFile(ctx.AbsPath(c.PublicKeyFile))
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if _, err := corebackups.ParsePublicKey(data); err != nil {
+			return errors.Trace(err)
+		}
+		publicKey = string(data)
+	}
+
 	if apiVersion < 2 {
 		if c.KeepCopy {
 			return errors.New("--keep-copy is not supported by this controller")
@@ -149,7 +188,7 @@ func (c *createCommand) Run(ctx *cmd.Context) error {
 		c.KeepCopy = true
 	}
 
-	metadataResult, copyFrom, err := c.create(client, apiVersion)
+	metadataResult, copyFrom, err := c.create(client, apiVersion, publicKey)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -206,8 +245,8 @@ func (c *createCommand) download(ctx *cmd.Context, client APIClient, copyFrom st
 	return nil
 }
 
-func (c *createCommand) create(client APIClient, apiVersion int) (*params.BackupsMetadataResult, string, error) {
-	result, err := client.Create(c.Notes, c.KeepCopy, c.NoDownload)
+func (c *createCommand) create(client APIClient, apiVersion int, publicKey string) (*params.BackupsMetadataResult, string, error) {
+	result, err := client.Create(c.Notes, c.KeepCopy, c.NoDownload, publicKey)
 	if err != nil {
 		return nil, "", errors.Trace(err)
 	}
@@ -0,0 +1,191 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/description"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/version"
+	"gopkg.in/juju/charm.v6"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/modelbackups"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+const modelBackupFilenameTemplate = "juju-model-backup-%s-%s.tar.gz"
+
+const createModelDoc = `
+This command creates a backup of a single model and downloads it as a local
+archive file. Unlike 'juju create-backup', which backs up the whole
+controller, a model backup can be restored with 'juju restore-model-backup',
+without affecting any other model on the controller.
+
+The archive contains the model's description, in the same format that is used
+for model migration, along with the charms and uploaded resources used by its
+applications. It does not include agent binaries, which are provided by the
+controller the backup is restored into, or the data held by the workloads
+themselves. Pins on action results, and the spot, spot-max-price,
+instance-role, anti-affinity and root-disk-source constraints, can't be
+represented in the model description yet, so they are left out with a warning
+in the controller's log.
+
+The archive includes the model's cloud credential, so it should be stored
+securely. Only controller administrators can back up models.
+
+Examples:
+    juju create-model-backup
+    juju create-model-backup -m prod --filename prod.tar.gz
+
+See also:
+    restore-model-backup
+    create-backup
+`
+
+// ModelBackupsAPI is the model backups API functionality used by the
+// create-model-backup command.
+type ModelBackupsAPI interface {
+	io.Closer
+	BestAPIVersion() int
+	ControllerTag() names.ControllerTag
+	ServerVersion() (version.Number, bool)
+	Export() (params.SerializedModel, error)
+	OpenCharm(*charm.URL) (io.ReadCloser, error)
+	OpenResource(application, name string) (io.ReadCloser, error)
+}
+
+// NewCreateModelCommand returns a command used to back up a model.
+func NewCreateModelCommand() cmd.Command {
+	c := &createModelCommand{}
+	c.newAPIFunc = func() (ModelBackupsAPI, error) {
+		root, err := c.NewAPIRoot()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return &modelBackupsClient{
+			Client: modelbackups.NewClient(root),
+			root:   root,
+		}, nil
+	}
+	return modelcmd.Wrap(c)
+}
+
+// modelBackupsClient adds the details of the controller to the
+// ModelBackups API client.
+type modelBackupsClient struct {
+	*modelbackups.Client
+	root api.Connection
+}
+
+// ControllerTag is part of ModelBackupsAPI.
+func (c *modelBackupsClient) ControllerTag() names.ControllerTag {
+	return c.root.ControllerTag()
+}
+
+// ServerVersion is part of ModelBackupsAPI.
+func (c *modelBackupsClient) ServerVersion() (version.Number, bool) {
+	return c.root.ServerVersion()
+}
+
+// createModelCommand is the sub-command for backing up a model.
+type createModelCommand struct {
+	modelcmd.ModelCommandBase
+	newAPIFunc func() (ModelBackupsAPI, error)
+
+	// Filename is where the backup is written.
+	Filename string
+}
+
+// Info implements Command.Info.
+func (c *createModelCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "create-model-backup",
+		Purpose: "Create a backup of a single model.",
+		Doc:     createModelDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *createModelCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.StringVar(&c.Filename, "filename", "", "Write the backup to this file")
+}
+
+// Init implements Command.Init.
+func (c *createModelCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements Command.Run.
+func (c *createModelCommand) Run(ctx *cmd.Context) error {
+	client, err := c.newAPIFunc()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+	if client.BestAPIVersion() < 1 {
+		return errors.New("model backups are not supported by this controller")
+	}
+	controllerVersion, ok := client.ServerVersion()
+	if !ok {
+		return errors.New("cannot determine controller version")
+	}
+
+	serialized, err := client.Export()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	model, err := description.Deserialize(serialized.Bytes)
+	if err != nil {
+		return errors.Annotate(err, "reading exported model")
+	}
+	modelName, _ := model.Config()["name"].(string)
+	agentVersion, _ := model.Config()["agent-version"].(string)
+	info := modelBackupInfo{
+		FormatVersion: modelBackupFormatVersion,
+		ModelUUID:     model.Tag().Id(),
+		ModelName:     modelName,
+		Owner:         model.Owner().Id(),
+		AgentVersion:  agentVersion,
+
+		ControllerUUID:         client.ControllerTag().Id(),
+		ControllerAgentVersion: controllerVersion.String(),
+
+		Created:   time.Now().UTC(),
+		Charms:    serialized.Charms,
+		Resources: serialized.Resources,
+	}
+
+	filename := c.Filename
+	if filename == "" {
+		filename = fmt.Sprintf(modelBackupFilenameTemplate, modelName, info.Created.Format("20060102-150405"))
+	}
+	filename = ctx.AbsPath(filename)
+	// The archive holds the model's cloud credential, so only the
+	// user may read it.
+	archive, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return errors.Annotatef(err, "while creating model backup file %v", filename)
+	}
+	err = writeModelBackup(archive, info, serialized.Bytes, client)
+	if closeErr := archive.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(filename)
+		return errors.Annotate(err, "while writing model backup")
+	}
+
+	ctx.Infof("Model %q backed up to %v.", modelName, filename)
+	return nil
+}
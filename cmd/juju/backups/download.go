@@ -21,6 +21,9 @@ download-backup retrieves a backup archive file.
 
 If --filename is not used, the archive is downloaded to a temporary
 location and the filename is printed to stdout.
+
+The archive is fetched from wherever the controller stored it: its database,
+a local directory or an S3-compatible object store.
 `
 
 // NewDownloadCommand returns a commant used to download backups.
@@ -87,3 +87,23 @@ func NewRestoreCommandForTest(
 func (r *RestoreCommand) AssignGetModelStatusAPI(apiFunc func() (ModelStatusAPI, error)) {
 	r.getModelStatusAPI = apiFunc
 }
+
+func NewCreateModelCommandForTest(store jujuclient.ClientStore, api ModelBackupsAPI) cmd.Command {
+	c := &createModelCommand{
+		newAPIFunc: func() (ModelBackupsAPI, error) {
+			return api, nil
+		},
+	}
+	c.SetClientStore(store)
+	return modelcmd.Wrap(c)
+}
+
+func NewRestoreModelCommandForTest(store jujuclient.ClientStore, api MigrationTargetAPI) cmd.Command {
+	c := &restoreModelCommand{
+		newAPIFunc: func() (MigrationTargetAPI, error) {
+			return api, nil
+		},
+	}
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
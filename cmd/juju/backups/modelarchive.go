@@ -0,0 +1,273 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v6"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+// A model backup archive is a gzipped tarball containing:
+//
+//	backup.json                    the modelBackupInfo
+//	model.yaml                     the model description
+//	charms/<escaped charm URL>     each charm archive the model uses
+//	resources/<application>/<name> each uploaded application resource
+const (
+	modelBackupFormatVersion = 1
+	modelBackupInfoFile      = "backup.json"
+	modelBackupModelFile     = "model.yaml"
+	modelBackupCharmsDir     = "charms"
+	modelBackupResourcesDir  = "resources"
+)
+
+// modelBackupInfo describes the content of a model backup archive.
+type modelBackupInfo struct {
+	FormatVersion int    `json:"format-version"`
+	ModelUUID     string `json:"model-uuid"`
+	ModelName     string `json:"model-name"`
+	Owner         string `json:"owner"`
+	AgentVersion  string `json:"agent-version"`
+
+	// ControllerUUID and ControllerAgentVersion describe the
+	// controller the backup was created on.
+	ControllerUUID         string `json:"controller-uuid"`
+	ControllerAgentVersion string `json:"controller-agent-version"`
+
+	Created   time.Time                        `json:"created"`
+	Charms    []string                         `json:"charms"`
+	Resources []params.SerializedModelResource `json:"resources,omitempty"`
+}
+
+// modelBinarySource provides the charms and resources of a model.
+type modelBinarySource interface {
+	OpenCharm(*charm.URL) (io.ReadCloser, error)
+	OpenResource(application, name string) (io.ReadCloser, error)
+}
+
+func charmArchivePath(curl string) string {
+	return path.Join(modelBackupCharmsDir, url.QueryEscape(curl))
+}
+
+func resourceArchivePath(application, name string) string {
+	return path.Join(modelBackupResourcesDir, application, name)
+}
+
+// writeModelBackup writes a model backup archive containing the
+// serialized model, and the charms and resources it uses read from
+// source, to w.
+func writeModelBackup(w io.Writer, info modelBackupInfo, model []byte, source modelBinarySource) error {
+	gzw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gzw)
+
+	infoData, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := addTarBytes(tw, modelBackupInfoFile, infoData, info.Created); err != nil {
+		return errors.Trace(err)
+	}
+	if err := addTarBytes(tw, modelBackupModelFile, model, info.Created); err != nil {
+		return errors.Trace(err)
+	}
+
+	for _, curlStr := range info.Charms {
+		curl, err := charm.ParseURL(curlStr)
+		if err != nil {
+			return errors.Annotate(err, "bad charm URL")
+		}
+		r, err := source.OpenCharm(curl)
+		if err != nil {
+			return errors.Annotatef(err, "cannot open charm %s", curl)
+		}
+		err = addTarStream(tw, charmArchivePath(curlStr), r, info.Created)
+		r.Close()
+		if err != nil {
+			return errors.Annotatef(err, "cannot back up charm %s", curl)
+		}
+	}
+
+	for _, res := range info.Resources {
+		// Placeholder resources have no content yet; they are
+		// recreated from the model description.
+		if res.ApplicationRevision.Timestamp.IsZero() {
+			continue
+		}
+		r, err := source.OpenResource(res.Application, res.Name)
+		if err != nil {
+			return errors.Annotatef(err, "cannot open resource %s/%s", res.Application, res.Name)
+		}
+		err = addTarStream(tw, resourceArchivePath(res.Application, res.Name), r, info.Created)
+		r.Close()
+		if err != nil {
+			return errors.Annotatef(err, "cannot back up resource %s/%s", res.Application, res.Name)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(gzw.Close())
+}
+
+func addTarBytes(tw *tar.Writer, name string, data []byte, modTime time.Time) error {
+	hdr := &tar.Header{
+		Name:    name,
+		Mode:    0600,
+		Size:    int64(len(data)),
+		ModTime: modTime,
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return errors.Trace(err)
+	}
+	_, err := tw.Write(data)
+	return errors.Trace(err)
+}
+
+// addTarStream adds the content read from r to the archive. The
+// content is spooled through a temporary file because the tar header
+// needs its size up front.
+func addTarStream(tw *tar.Writer, name string, r io.Reader, modTime time.Time) error {
+	tmp, err := ioutil.TempFile("", "juju-model-backup")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}()
+	size, err := io.Copy(tmp, r)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return errors.Trace(err)
+	}
+	hdr := &tar.Header{
+		Name:    name,
+		Mode:    0600,
+		Size:    size,
+		ModTime: modTime,
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return errors.Trace(err)
+	}
+	_, err = io.Copy(tw, tmp)
+	return errors.Trace(err)
+}
+
+// extractedModelBackup is a model backup archive extracted into a
+// directory. It provides the backed up charms and resources for
+// uploading into the controller the model is restored into.
+type extractedModelBackup struct {
+	dir   string
+	info  modelBackupInfo
+	model []byte
+}
+
+// extractModelBackup extracts the model backup archive read from r
+// into dir.
+func extractModelBackup(r io.Reader, dir string) (*extractedModelBackup, error) {
+	gzr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, errors.Annotate(err, "while uncompressing model backup")
+	}
+	defer gzr.Close()
+
+	tr := tar.NewReader(gzr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, errors.Annotate(err, "while reading model backup")
+		}
+		if hdr.Typeflag != tar.TypeReg && hdr.Typeflag != tar.TypeRegA {
+			continue
+		}
+		name := path.Clean(hdr.Name)
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return nil, errors.NotValidf("model backup entry %q", hdr.Name)
+		}
+		target := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(target), 0700); err != nil {
+			return nil, errors.Trace(err)
+		}
+		if err := writeFile(target, tr); err != nil {
+			return nil, errors.Annotatef(err, "while extracting %q", hdr.Name)
+		}
+	}
+
+	backup := &extractedModelBackup{dir: dir}
+	infoData, err := ioutil.ReadFile(filepath.Join(dir, modelBackupInfoFile))
+	if os.IsNotExist(err) {
+		return nil, errors.NotValidf("model backup without %s", modelBackupInfoFile)
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := json.Unmarshal(infoData, &backup.info); err != nil {
+		return nil, errors.Annotatef(err, "while reading %s", modelBackupInfoFile)
+	}
+	if backup.info.FormatVersion > modelBackupFormatVersion {
+		return nil, errors.NotSupportedf("model backup format version %d (latest supported is %d)",
+			backup.info.FormatVersion, modelBackupFormatVersion)
+	}
+	backup.model, err = ioutil.ReadFile(filepath.Join(dir, modelBackupModelFile))
+	if os.IsNotExist(err) {
+		return nil, errors.NotValidf("model backup without %s", modelBackupModelFile)
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return backup, nil
+}
+
+func writeFile(target string, r io.Reader) error {
+	f, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return errors.Trace(err)
+	}
+	return errors.Trace(f.Close())
+}
+
+// OpenCharm is part of migration.CharmDownloader.
+func (b *extractedModelBackup) OpenCharm(curl *charm.URL) (io.ReadCloser, error) {
+	f, err := os.Open(filepath.Join(b.dir, filepath.FromSlash(charmArchivePath(curl.String()))))
+	if os.IsNotExist(err) {
+		return nil, errors.NotFoundf("charm %s in model backup", curl)
+	}
+	return f, errors.Trace(err)
+}
+
+// OpenURI is part of migration.ToolsDownloader. Model backups do not
+// include agent binaries.
+func (b *extractedModelBackup) OpenURI(uri string, query url.Values) (io.ReadCloser, error) {
+	return nil, errors.NotSupportedf("agent binaries in model backup")
+}
+
+// OpenResource is part of migration.ResourceDownloader.
+func (b *extractedModelBackup) OpenResource(application, name string) (io.ReadCloser, error) {
+	f, err := os.Open(filepath.Join(b.dir, filepath.FromSlash(resourceArchivePath(application, name))))
+	if os.IsNotExist(err) {
+		return nil, errors.NotFoundf("resource %s/%s in model backup", application, name)
+	}
+	return f, errors.Trace(err)
+}
@@ -0,0 +1,331 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"time"
+
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/description"
+	"github.com/juju/errors"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v6"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/backups"
+	coremigration "github.com/juju/juju/core/migration"
+	"github.com/juju/juju/jujuclient/jujuclienttesting"
+	"github.com/juju/juju/resource"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/tools"
+)
+
+const modelBackupUUID = "deadbeef-0bad-400d-8000-4b1d0d06f00d"
+
+type modelBackupSuite struct {
+	testing.BaseSuite
+	dir      string
+	filename string
+	source   *fakeModelBackupsAPI
+	target   *fakeMigrationTargetAPI
+}
+
+var _ = gc.Suite(&modelBackupSuite{})
+
+func (s *modelBackupSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.dir = c.MkDir()
+	s.filename = filepath.Join(s.dir, "prod.tar.gz")
+
+	model := description.NewModel(description.ModelArgs{
+		Type:  description.IAAS,
+		Owner: names.NewUserTag("admin"),
+		Config: map[string]interface{}{
+			"name":          "prod",
+			"uuid":          modelBackupUUID,
+			"agent-version": "2.5.0",
+		},
+	})
+	model.AddApplication(description.ApplicationArgs{
+		Tag:      names.NewApplicationTag("foo"),
+		CharmURL: "cs:xenial/foo-1",
+	})
+	modelBytes, err := description.Serialize(model)
+	c.Assert(err, jc.ErrorIsNil)
+
+	revision := func(origin string) params.SerializedModelResourceRevision {
+		return params.SerializedModelResourceRevision{
+			Revision:  2,
+			Type:      "file",
+			Path:      "bin.tar.gz",
+			Origin:    origin,
+			Timestamp: time.Date(2019, 5, 26, 2, 0, 0, 0, time.UTC),
+		}
+	}
+	s.source = &fakeModelBackupsAPI{
+		version:           1,
+		controllerVersion: version.MustParse("2.6.1"),
+		serialized: params.SerializedModel{
+			Bytes:  modelBytes,
+			Charms: []string{"cs:xenial/foo-1"},
+			Resources: []params.SerializedModelResource{{
+				Application:         "foo",
+				Name:                "bin",
+				ApplicationRevision: revision("upload"),
+				CharmStoreRevision:  revision("store"),
+			}},
+		},
+		charms:    map[string]string{"cs:xenial/foo-1": "<charm archive>"},
+		resources: map[string]string{"foo/bin": "<resource content>"},
+	}
+	s.target = &fakeMigrationTargetAPI{
+		controllerTag: testing.ControllerTag,
+		charms:        make(map[string]string),
+		resources:     make(map[string]string),
+	}
+}
+
+func (s *modelBackupSuite) createBackup(c *gc.C) {
+	command := backups.NewCreateModelCommandForTest(jujuclienttesting.MinimalStore(), s.source)
+	ctx, err := cmdtesting.RunCommand(c, command, "--filename", s.filename)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stderr(ctx), gc.Equals, `Model "prod" backed up to `+s.filename+".\n")
+}
+
+func (s *modelBackupSuite) TestCreateNotSupported(c *gc.C) {
+	s.source.version = 0
+	command := backups.NewCreateModelCommandForTest(jujuclienttesting.MinimalStore(), s.source)
+	_, err := cmdtesting.RunCommand(c, command, "--filename", s.filename)
+	c.Assert(err, gc.ErrorMatches, "model backups are not supported by this controller")
+}
+
+func (s *modelBackupSuite) TestCreateExportError(c *gc.C) {
+	s.source.SetErrors(errors.New("boom"))
+	command := backups.NewCreateModelCommandForTest(jujuclienttesting.MinimalStore(), s.source)
+	_, err := cmdtesting.RunCommand(c, command, "--filename", s.filename)
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *modelBackupSuite) TestCreateAndRestore(c *gc.C) {
+	s.createBackup(c)
+	s.source.CheckCallNames(c, "Export", "OpenCharm", "OpenResource", "Close")
+
+	command := backups.NewRestoreModelCommandForTest(jujuclienttesting.MinimalStore(), s.target)
+	ctx, err := cmdtesting.RunCommand(c, command, s.filename)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stderr(ctx), gc.Equals, `Restoring model "prod" from `+s.filename+` as "prod".`+"\n"+
+		`Model "prod" restored.`+"\n")
+
+	s.target.CheckCallNames(c,
+		"Prechecks", "Import", "UploadCharm", "UploadResource", "AdoptResources", "Activate", "Close")
+	info := s.target.Calls()[0].Args[0].(coremigration.ModelInfo)
+	c.Check(info.UUID, gc.Equals, modelBackupUUID)
+	c.Check(info.Name, gc.Equals, "prod")
+	c.Check(info.Owner, gc.Equals, names.NewUserTag("admin"))
+	c.Check(info.AgentVersion, gc.Equals, version.MustParse("2.5.0"))
+	c.Check(info.ControllerAgentVersion, gc.Equals, version.MustParse("2.6.1"))
+
+	// The model is imported with its original UUID, so that its
+	// agents can connect to it.
+	model, err := description.Deserialize(s.target.Calls()[1].Args[0].([]byte))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(model.Tag().Id(), gc.Equals, modelBackupUUID)
+	for _, call := range s.target.Calls()[2:6] {
+		c.Check(call.Args[0], gc.Equals, modelBackupUUID)
+	}
+	c.Check(s.target.charms, jc.DeepEquals, map[string]string{"cs:xenial/foo-1": "<charm archive>"})
+	c.Check(s.target.resources, jc.DeepEquals, map[string]string{"foo/bin": "<resource content>"})
+}
+
+func (s *modelBackupSuite) TestRestoreWithName(c *gc.C) {
+	s.createBackup(c)
+
+	command := backups.NewRestoreModelCommandForTest(jujuclienttesting.MinimalStore(), s.target)
+	_, err := cmdtesting.RunCommand(c, command, "--name", "prod-restored", s.filename)
+	c.Assert(err, jc.ErrorIsNil)
+
+	info := s.target.Calls()[0].Args[0].(coremigration.ModelInfo)
+	c.Check(info.Name, gc.Equals, "prod-restored")
+	model, err := description.Deserialize(s.target.Calls()[1].Args[0].([]byte))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(model.Config()["name"], gc.Equals, "prod-restored")
+}
+
+func (s *modelBackupSuite) TestRestorePrecheckFails(c *gc.C) {
+	s.createBackup(c)
+	s.target.SetErrors(errors.New("model with same UUID already exists (" + modelBackupUUID + ")"))
+
+	command := backups.NewRestoreModelCommandForTest(jujuclienttesting.MinimalStore(), s.target)
+	_, err := cmdtesting.RunCommand(c, command, s.filename)
+	c.Assert(err, gc.ErrorMatches, `cannot restore model: model with same UUID already exists \(`+modelBackupUUID+`\)`)
+	s.target.CheckCallNames(c, "Prechecks", "Close")
+}
+
+func (s *modelBackupSuite) TestRestoreOtherController(c *gc.C) {
+	s.createBackup(c)
+	s.target.controllerTag = names.NewControllerTag("abcdef01-0bad-400d-8000-4b1d0d06f00d")
+
+	command := backups.NewRestoreModelCommandForTest(jujuclienttesting.MinimalStore(), s.target)
+	_, err := cmdtesting.RunCommand(c, command, s.filename)
+	c.Assert(err, gc.ErrorMatches, "cannot restore model: backup was created on controller "+
+		testing.ControllerTag.Id()+", not abcdef01-0bad-400d-8000-4b1d0d06f00d")
+	s.target.CheckCallNames(c, "Close")
+}
+
+func (s *modelBackupSuite) TestRestoreAbortsOnFailure(c *gc.C) {
+	s.createBackup(c)
+	s.target.SetErrors(nil, nil, errors.New("boom"))
+
+	command := backups.NewRestoreModelCommandForTest(jujuclienttesting.MinimalStore(), s.target)
+	_, err := cmdtesting.RunCommand(c, command, s.filename)
+	c.Assert(err, gc.ErrorMatches, "uploading charms and resources: cannot upload charm: boom")
+	s.target.CheckCallNames(c, "Prechecks", "Import", "UploadCharm", "Abort", "Close")
+}
+
+func (s *modelBackupSuite) TestRestoreNotAModelBackup(c *gc.C) {
+	err := ioutil.WriteFile(s.filename, []byte("not a backup"), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+
+	command := backups.NewRestoreModelCommandForTest(jujuclienttesting.MinimalStore(), s.target)
+	_, err = cmdtesting.RunCommand(c, command, s.filename)
+	c.Assert(err, gc.ErrorMatches, "while uncompressing model backup: .*")
+	s.target.CheckNoCalls(c)
+}
+
+func (s *modelBackupSuite) TestRestoreMissingFilename(c *gc.C) {
+	command := backups.NewRestoreModelCommandForTest(jujuclienttesting.MinimalStore(), s.target)
+	_, err := cmdtesting.RunCommand(c, command)
+	c.Assert(err, gc.ErrorMatches, "missing filename")
+}
+
+type fakeModelBackupsAPI struct {
+	jujutesting.Stub
+	version           int
+	controllerVersion version.Number
+	serialized        params.SerializedModel
+	charms            map[string]string
+	resources         map[string]string
+}
+
+func (f *fakeModelBackupsAPI) Close() error {
+	f.MethodCall(f, "Close")
+	return nil
+}
+
+func (f *fakeModelBackupsAPI) BestAPIVersion() int {
+	return f.version
+}
+
+func (f *fakeModelBackupsAPI) ControllerTag() names.ControllerTag {
+	return testing.ControllerTag
+}
+
+func (f *fakeModelBackupsAPI) ServerVersion() (version.Number, bool) {
+	return f.controllerVersion, true
+}
+
+func (f *fakeModelBackupsAPI) Export() (params.SerializedModel, error) {
+	f.MethodCall(f, "Export")
+	return f.serialized, f.NextErr()
+}
+
+func (f *fakeModelBackupsAPI) OpenCharm(curl *charm.URL) (io.ReadCloser, error) {
+	f.MethodCall(f, "OpenCharm", curl)
+	return ioutil.NopCloser(bytes.NewBufferString(f.charms[curl.String()])), f.NextErr()
+}
+
+func (f *fakeModelBackupsAPI) OpenResource(application, name string) (io.ReadCloser, error) {
+	f.MethodCall(f, "OpenResource", application, name)
+	return ioutil.NopCloser(bytes.NewBufferString(f.resources[application+"/"+name])), f.NextErr()
+}
+
+type fakeMigrationTargetAPI struct {
+	jujutesting.Stub
+	controllerTag names.ControllerTag
+	charms        map[string]string
+	resources     map[string]string
+}
+
+func (f *fakeMigrationTargetAPI) ControllerTag() names.ControllerTag {
+	return f.controllerTag
+}
+
+func (f *fakeMigrationTargetAPI) Close() error {
+	f.MethodCall(f, "Close")
+	return nil
+}
+
+func (f *fakeMigrationTargetAPI) Prechecks(info coremigration.ModelInfo) error {
+	f.MethodCall(f, "Prechecks", info)
+	return f.NextErr()
+}
+
+func (f *fakeMigrationTargetAPI) Import(bytes []byte) error {
+	f.MethodCall(f, "Import", bytes)
+	return f.NextErr()
+}
+
+func (f *fakeMigrationTargetAPI) Abort(modelUUID string) error {
+	f.MethodCall(f, "Abort", modelUUID)
+	return f.NextErr()
+}
+
+func (f *fakeMigrationTargetAPI) Activate(modelUUID string) error {
+	f.MethodCall(f, "Activate", modelUUID)
+	return f.NextErr()
+}
+
+func (f *fakeMigrationTargetAPI) AdoptResources(modelUUID string) error {
+	f.MethodCall(f, "AdoptResources", modelUUID)
+	return f.NextErr()
+}
+
+func (f *fakeMigrationTargetAPI) UploadCharm(modelUUID string, curl *charm.URL, content io.ReadSeeker) (*charm.URL, error) {
+	f.MethodCall(f, "UploadCharm", modelUUID, curl)
+	if err := f.NextErr(); err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadAll(content)
+	if err != nil {
+		return nil, err
+	}
+	f.charms[curl.String()] = string(data)
+	return curl, nil
+}
+
+func (f *fakeMigrationTargetAPI) UploadTools(modelUUID string, r io.ReadSeeker, vers version.Binary, additionalSeries ...string) (tools.List, error) {
+	f.MethodCall(f, "UploadTools", modelUUID, vers)
+	return nil, f.NextErr()
+}
+
+func (f *fakeMigrationTargetAPI) UploadResource(modelUUID string, res resource.Resource, r io.ReadSeeker) error {
+	f.MethodCall(f, "UploadResource", modelUUID, res)
+	if err := f.NextErr(); err != nil {
+		return err
+	}
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	f.resources[res.ApplicationID+"/"+res.Name] = string(data)
+	return nil
+}
+
+func (f *fakeMigrationTargetAPI) SetPlaceholderResource(modelUUID string, res resource.Resource) error {
+	f.MethodCall(f, "SetPlaceholderResource", modelUUID, res)
+	return f.NextErr()
+}
+
+func (f *fakeMigrationTargetAPI) SetUnitResource(modelUUID, unit string, res resource.Resource) error {
+	f.MethodCall(f, "SetUnitResource", modelUUID, unit, res)
+	return f.NextErr()
+}
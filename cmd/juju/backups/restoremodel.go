@@ -0,0 +1,285 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/juju/cmd"
+	"github.com/juju/description"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/version"
+	"gopkg.in/juju/charm.v6"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/common"
+	"github.com/juju/juju/api/migrationtarget"
+	"github.com/juju/juju/cmd/modelcmd"
+	coremigration "github.com/juju/juju/core/migration"
+	"github.com/juju/juju/migration"
+	"github.com/juju/juju/resource"
+	"github.com/juju/juju/tools"
+)
+
+const restoreModelDoc = `
+This command restores a backup created by 'juju create-model-backup' into the
+controller that the backup was created on. The charms and resources in the
+backup are uploaded to the controller; other models are not affected.
+
+The model is recreated with its original UUID, so that the agents on its
+machines, which are configured with that UUID and the controller's addresses,
+connect to the restored model. For the same reason the backup can't be
+restored into another controller, and the original model must no longer exist
+on the controller.
+
+By default the model keeps its original name; use --name to choose another.
+The model owner must be a user on the controller.
+
+Only controller administrators can restore models.
+
+Examples:
+    juju restore-model-backup juju-model-backup-prod-20190526-020000.tar.gz
+    juju restore-model-backup --name prod-restored prod.tar.gz
+
+See also:
+    create-model-backup
+    restore-backup
+`
+
+// MigrationTargetAPI is the controller API functionality used by the
+// restore-model-backup command to import a model.
+type MigrationTargetAPI interface {
+	io.Closer
+	ControllerTag() names.ControllerTag
+	Prechecks(coremigration.ModelInfo) error
+	Import([]byte) error
+	Abort(modelUUID string) error
+	Activate(modelUUID string) error
+	AdoptResources(modelUUID string) error
+	UploadCharm(modelUUID string, curl *charm.URL, content io.ReadSeeker) (*charm.URL, error)
+	UploadTools(modelUUID string, r io.ReadSeeker, vers version.Binary, additionalSeries ...string) (tools.List, error)
+	UploadResource(modelUUID string, res resource.Resource, r io.ReadSeeker) error
+	SetPlaceholderResource(modelUUID string, res resource.Resource) error
+	SetUnitResource(modelUUID, unit string, res resource.Resource) error
+}
+
+// NewRestoreModelCommand returns a command used to restore a model
+// from a model backup.
+func NewRestoreModelCommand() cmd.Command {
+	c := &restoreModelCommand{}
+	c.newAPIFunc = func() (MigrationTargetAPI, error) {
+		root, err := c.NewAPIRoot()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return &migrationTargetClient{
+			Client:        migrationtarget.NewClient(root),
+			Closer:        root,
+			controllerTag: root.ControllerTag(),
+		}, nil
+	}
+	return modelcmd.WrapController(c)
+}
+
+type migrationTargetClient struct {
+	*migrationtarget.Client
+	io.Closer
+	controllerTag names.ControllerTag
+}
+
+// ControllerTag is part of MigrationTargetAPI.
+func (c *migrationTargetClient) ControllerTag() names.ControllerTag {
+	return c.controllerTag
+}
+
+// restoreModelCommand is the sub-command for restoring a model backup.
+type restoreModelCommand struct {
+	modelcmd.ControllerCommandBase
+	newAPIFunc func() (MigrationTargetAPI, error)
+
+	// Filename is the model backup archive to restore.
+	Filename string
+	// Name is the name of the restored model, if not the original.
+	Name string
+}
+
+// Info implements Command.Info.
+func (c *restoreModelCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "restore-model-backup",
+		Args:    "<filename>",
+		Purpose: "Restore a model backup into a new model.",
+		Doc:     restoreModelDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *restoreModelCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ControllerCommandBase.SetFlags(f)
+	f.StringVar(&c.Name, "name", "", "Name of the restored model (default: the original name)")
+}
+
+// Init implements Command.Init.
+func (c *restoreModelCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("missing filename")
+	}
+	filename, args := args[0], args[1:]
+	if err := cmd.CheckEmpty(args); err != nil {
+		return errors.Trace(err)
+	}
+	c.Filename = filename
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *restoreModelCommand) Run(ctx *cmd.Context) error {
+	archive, err := os.Open(ctx.AbsPath(c.Filename))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer archive.Close()
+	dir, err := ioutil.TempDir("", "juju-model-restore")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer os.RemoveAll(dir)
+	backup, err := extractModelBackup(archive, dir)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	// The model is restored with its original UUID, so that the
+	// agents on its machines connect to it.
+	model, err := description.Deserialize(backup.model)
+	if err != nil {
+		return errors.Annotate(err, "reading model description")
+	}
+	modelUUID := model.Tag().Id()
+	name := c.Name
+	if name == "" {
+		name = backup.info.ModelName
+	}
+	model.UpdateConfig(map[string]interface{}{
+		"name": name,
+	})
+	modelBytes, err := description.Serialize(model)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	agentVersion, err := version.Parse(backup.info.AgentVersion)
+	if err != nil {
+		return errors.Annotate(err, "reading model agent version")
+	}
+	controllerVersion, err := version.Parse(backup.info.ControllerAgentVersion)
+	if err != nil {
+		return errors.Annotate(err, "reading controller agent version")
+	}
+	resources, err := common.ConvertSerializedResources(backup.info.Resources)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	client, err := c.newAPIFunc()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	// The model's agents only know the addresses of the controller
+	// the backup was created on.
+	if controllerUUID := client.ControllerTag().Id(); controllerUUID != backup.info.ControllerUUID {
+		return errors.Errorf(
+			"cannot restore model: backup was created on controller %s, not %s",
+			backup.info.ControllerUUID, controllerUUID,
+		)
+	}
+	err = client.Prechecks(coremigration.ModelInfo{
+		UUID:                   modelUUID,
+		Name:                   name,
+		Owner:                  model.Owner(),
+		AgentVersion:           agentVersion,
+		ControllerAgentVersion: controllerVersion,
+	})
+	if err != nil {
+		return errors.Annotate(err, "cannot restore model")
+	}
+	ctx.Infof("Restoring model %q from %v as %q.", backup.info.ModelName, c.Filename, name)
+	if err := client.Import(modelBytes); err != nil {
+		return errors.Annotate(err, "importing model")
+	}
+	if err := c.finishRestore(client, backup, modelUUID, resources); err != nil {
+		if abortErr := client.Abort(modelUUID); abortErr != nil {
+			ctx.Warningf("cannot remove partially restored model: %v", abortErr)
+		}
+		return errors.Trace(err)
+	}
+	ctx.Infof("Model %q restored.", name)
+	return nil
+}
+
+func (c *restoreModelCommand) finishRestore(
+	client MigrationTargetAPI,
+	backup *extractedModelBackup,
+	modelUUID string,
+	resources []coremigration.SerializedModelResource,
+) error {
+	uploader := &modelUploader{client: client, modelUUID: modelUUID}
+	err := migration.UploadBinaries(migration.UploadBinariesConfig{
+		Charms:          backup.info.Charms,
+		CharmDownloader: backup,
+		CharmUploader:   uploader,
+
+		ToolsDownloader: backup,
+		ToolsUploader:   uploader,
+
+		Resources:          resources,
+		ResourceDownloader: backup,
+		ResourceUploader:   uploader,
+	})
+	if err != nil {
+		return errors.Annotate(err, "uploading charms and resources")
+	}
+	// Make sure the cloud resources belong to this controller, in
+	// case the backup came from another one.
+	if err := client.AdoptResources(modelUUID); err != nil {
+		return errors.Annotate(err, "adopting cloud resources")
+	}
+	return errors.Annotate(client.Activate(modelUUID), "activating model")
+}
+
+// modelUploader adds the model UUID to the uploads made to the
+// controller by migration.UploadBinaries.
+type modelUploader struct {
+	client    MigrationTargetAPI
+	modelUUID string
+}
+
+// UploadCharm is part of migration.CharmUploader.
+func (u *modelUploader) UploadCharm(curl *charm.URL, content io.ReadSeeker) (*charm.URL, error) {
+	return u.client.UploadCharm(u.modelUUID, curl, content)
+}
+
+// UploadTools is part of migration.ToolsUploader.
+func (u *modelUploader) UploadTools(r io.ReadSeeker, vers version.Binary, additionalSeries ...string) (tools.List, error) {
+	return u.client.UploadTools(u.modelUUID, r, vers, additionalSeries...)
+}
+
+// UploadResource is part of migration.ResourceUploader.
+func (u *modelUploader) UploadResource(res resource.Resource, content io.ReadSeeker) error {
+	return u.client.UploadResource(u.modelUUID, res, content)
+}
+
+// SetPlaceholderResource is part of migration.ResourceUploader.
+func (u *modelUploader) SetPlaceholderResource(res resource.Resource) error {
+	return u.client.SetPlaceholderResource(u.modelUUID, res)
+}
+
+// SetUnitResource is part of migration.ResourceUploader.
+func (u *modelUploader) SetUnitResource(unitName string, res resource.Resource) error {
+	return u.client.SetUnitResource(u.modelUUID, unitName, res)
+}
@@ -0,0 +1,185 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+
+	corebackups "github.com/juju/juju/core/backups"
+	"github.com/juju/juju/state/backups"
+)
+
+const verifyDoc = `
+verify-backup checks a local backup archive file without restoring it.
+
+The archive's format version must be supported by this client, and every file
+in it must match the SHA-256 digest recorded in its manifest when the backup
+was created. Archives created by older versions of Juju have no manifest and
+cannot be verified.
+
+Encrypted archives are authenticated and decrypted with the RSA private key
+given by --private-key. Use --decrypt-to to keep the decrypted archive, for
+example to restore from it with 'juju restore-backup --file'.
+
+Examples:
+    juju verify-backup juju-backup-20190526-020000.tar.gz
+    juju verify-backup --private-key backup-key.pem --decrypt-to plain.tar.gz backup.tar.gz
+
+See also:
+    create-backup
+    restore-backup
+`
+
+// NewVerifyCommand returns a command used to verify backup archives.
+func NewVerifyCommand() cmd.Command {
+	return &verifyCommand{}
+}
+
+// verifyCommand is the sub-command for verifying a backup archive.
+type verifyCommand struct {
+	cmd.CommandBase
+	// Filename is the backup archive to verify.
+	Filename string
+	// PrivateKeyFile is the path to the PEM-encoded RSA private key
+	// used to decrypt an encrypted archive.
+	PrivateKeyFile string
+	// DecryptTo is where the decrypted archive is written, if set.
+	DecryptTo string
+}
+
+// Info implements Command.Info.
+func (c *verifyCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "verify-backup",
+		Args:    "<filename>",
+		Purpose: "Check the integrity of a backup archive.",
+		Doc:     verifyDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *verifyCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	f.StringVar(&c.PrivateKeyFile, "private-key", "", "Decrypt the archive with the RSA private key in this PEM file")
+	f.StringVar(&c.DecryptTo, "decrypt-to", "", "Write the decrypted archive to this file")
+}
+
+// Init implements Command.Init.
+func (c *verifyCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("missing filename")
+	}
+	filename, args := args[0], args[1:]
+	if err := cmd.CheckEmpty(args); err != nil {
+		return errors.Trace(err)
+	}
+	c.Filename = filename
+	if c.DecryptTo != "" && c.PrivateKeyFile == "" {
+		return errors.New("--decrypt-to requires --private-key")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *verifyCommand) Run(ctx *cmd.Context) error {
+	archive, err := os.Open(ctx.AbsPath(c.Filename))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer archive.Close()
+
+	br := bufio.NewReader(archive)
+	encrypted, err := backups.IsEncryptedArchive(br)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var plain io.Reader = br
+	if encrypted {
+		if c.PrivateKeyFile == "" {
+			return errors.New("backup archive is encrypted: use --private-key to verify it")
+		}
+		decrypted, err := c.decrypt(ctx, br)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		defer decrypted.Close()
+		plain = decrypted
+	} else if c.PrivateKeyFile != "" {
+		return errors.New("backup archive is not encrypted")
+	}
+
+	result, err := backups.VerifyArchive(plain)
+	if err != nil {
+		if c.DecryptTo != "" {
+			os.Remove(ctx.AbsPath(c.DecryptTo))
+		}
+		return errors.Trace(err)
+	}
+
+	meta := result.Metadata
+	fmt.Fprintf(ctx.Stdout, "format version:  %d\n", result.FormatVersion)
+	fmt.Fprintf(ctx.Stdout, "files verified:  %d\n", result.FileCount)
+	fmt.Fprintf(ctx.Stdout, "encrypted:       %v\n", encrypted)
+	fmt.Fprintf(ctx.Stdout, "started:         %v\n", meta.Started)
+	fmt.Fprintf(ctx.Stdout, "model ID:        %v\n", meta.Origin.Model)
+	fmt.Fprintf(ctx.Stdout, "machine ID:      %v\n", meta.Origin.Machine)
+	fmt.Fprintf(ctx.Stdout, "created on host: %v\n", meta.Origin.Hostname)
+	fmt.Fprintf(ctx.Stdout, "juju version:    %v\n", meta.Origin.Version)
+	ctx.Infof("Backup archive %v verified.", c.Filename)
+	if c.DecryptTo != "" {
+		ctx.Infof("Decrypted archive written to %v.", c.DecryptTo)
+	}
+	return nil
+}
+
+// decrypt decrypts the archive into the --decrypt-to file, or else a
+// temporary file, and returns the decrypted file ready for reading.
+func (c *verifyCommand) decrypt(ctx *cmd.Context, archive io.Reader) (_ *os.File, err error) {
+	data, err := ioutil.ReadFile(ctx.AbsPath(c.PrivateKeyFile))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	key, err := corebackups.ParsePrivateKey(data)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var out *os.File
+	if c.DecryptTo != "" {
+		out, err = os.OpenFile(ctx.AbsPath(c.DecryptTo), os.O_RDWR|os.O_CREATE|os.O_EXCL, 0600)
+	} else {
+		out, err = ioutil.TempFile("", "juju-backup-")
+		if err == nil {
+			// The file remains readable until closed.
+			os.Remove(out.Name())
+		}
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer func() {
+		if err != nil {
+			out.Close()
+			if c.DecryptTo != "" {
+				os.Remove(out.Name())
+			}
+		}
+	}()
+
+	if err := backups.DecryptArchive(out, archive, key); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if _, err := out.Seek(0, io.SeekStart); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return out, nil
+}
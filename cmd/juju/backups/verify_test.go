@@ -0,0 +1,122 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/juju/cmd/cmdtesting"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/backups"
+	statebackups "github.com/juju/juju/state/backups"
+	backupstesting "github.com/juju/juju/state/backups/testing"
+	"github.com/juju/juju/testing"
+)
+
+type verifySuite struct {
+	testing.BaseSuite
+	dir      string
+	filename string
+	key      *rsa.PrivateKey
+}
+
+var _ = gc.Suite(&verifySuite{})
+
+func (s *verifySuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.dir = c.MkDir()
+	s.filename = filepath.Join(s.dir, "backup.tar.gz")
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	s.key = key
+}
+
+func (s *verifySuite) writeArchive(c *gc.C, encrypt bool) {
+	meta := backupstesting.NewMetadata()
+	files := []backupstesting.File{{
+		Name:    "var/lib/juju/system-identity",
+		Content: "<an ssh key goes here>",
+	}}
+	archive, err := backupstesting.NewArchiveWithManifest(meta, files, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	data := archive.Bytes()
+	if encrypt {
+		var encrypted bytes.Buffer
+		err := statebackups.EncryptArchive(&encrypted, archive, &s.key.PublicKey)
+		c.Assert(err, jc.ErrorIsNil)
+		data = encrypted.Bytes()
+	}
+	err = ioutil.WriteFile(s.filename, data, 0600)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *verifySuite) writePrivateKey(c *gc.C) string {
+	keyFile := filepath.Join(s.dir, "backup-key.pem")
+	data := pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(s.key),
+	})
+	err := ioutil.WriteFile(keyFile, data, 0600)
+	c.Assert(err, jc.ErrorIsNil)
+	return keyFile
+}
+
+func (s *verifySuite) TestInitMissingFilename(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, backups.NewVerifyCommand())
+	c.Check(err, gc.ErrorMatches, "missing filename")
+}
+
+func (s *verifySuite) TestInitDecryptToWithoutKey(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, backups.NewVerifyCommand(), "--decrypt-to", "plain.tar.gz", s.filename)
+	c.Check(err, gc.ErrorMatches, "--decrypt-to requires --private-key")
+}
+
+func (s *verifySuite) TestVerify(c *gc.C) {
+	s.writeArchive(c, false)
+	ctx, err := cmdtesting.RunCommand(c, backups.NewVerifyCommand(), s.filename)
+	c.Assert(err, jc.ErrorIsNil)
+
+	stdout := cmdtesting.Stdout(ctx)
+	c.Check(stdout, jc.Contains, "format version:  1\n")
+	c.Check(stdout, jc.Contains, "files verified:  2\n")
+	c.Check(stdout, jc.Contains, "encrypted:       false\n")
+	c.Check(cmdtesting.Stderr(ctx), gc.Equals, "Backup archive "+s.filename+" verified.\n")
+}
+
+func (s *verifySuite) TestVerifyEncryptedNeedsKey(c *gc.C) {
+	s.writeArchive(c, true)
+	_, err := cmdtesting.RunCommand(c, backups.NewVerifyCommand(), s.filename)
+	c.Check(err, gc.ErrorMatches, "backup archive is encrypted: use --private-key to verify it")
+}
+
+func (s *verifySuite) TestVerifyNotEncrypted(c *gc.C) {
+	s.writeArchive(c, false)
+	keyFile := s.writePrivateKey(c)
+	_, err := cmdtesting.RunCommand(c, backups.NewVerifyCommand(), "--private-key", keyFile, s.filename)
+	c.Check(err, gc.ErrorMatches, "backup archive is not encrypted")
+}
+
+func (s *verifySuite) TestVerifyEncrypted(c *gc.C) {
+	s.writeArchive(c, true)
+	keyFile := s.writePrivateKey(c)
+	plain := filepath.Join(s.dir, "plain.tar.gz")
+	ctx, err := cmdtesting.RunCommand(c, backups.NewVerifyCommand(),
+		"--private-key", keyFile, "--decrypt-to", plain, s.filename)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stdout(ctx), jc.Contains, "encrypted:       true\n")
+
+	data, err := ioutil.ReadFile(plain)
+	c.Assert(err, jc.ErrorIsNil)
+	result, err := statebackups.VerifyArchive(bytes.NewReader(data))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result.FileCount, gc.Equals, 2)
+}
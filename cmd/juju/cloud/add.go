@@ -75,6 +75,7 @@ clouds:                           # mandatory
  - maas
  - manual
  - openstack
+ - proxmox
  - vsphere
 
 <cloud types> for public clouds:
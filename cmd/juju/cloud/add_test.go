@@ -324,6 +324,7 @@ func (*addSuite) TestInteractive(c *gc.C) {
 		"  maas\n"+
 		"  manual\n"+
 		"  openstack\n"+
+		"  proxmox\n"+
 		"  vsphere\n"+
 		"\n"+
 		"Select cloud type: \n",
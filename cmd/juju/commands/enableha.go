@@ -47,6 +47,10 @@ type enableHACommand struct {
 	// NumControllers specifies the number of controllers to make available.
 	NumControllers int
 
+	// ReadReplicas specifies the number of read replica controllers
+	// to make available. It is negative if not specified.
+	ReadReplicas int
+
 	// Constraints, if specified, will be merged with those already
 	// in the environment when creating new machines.
 	Constraints constraints.Value
@@ -72,6 +76,21 @@ controller.
 
 An odd number of controllers is required.
 
+When the cloud supports availability zones and no placement is given, new
+controller machines are spread across the available zones, favouring those
+holding the fewest controllers. Use 'juju show-controller' to see the zone
+of each controller machine.
+
+The --read-replicas option adds read replica controllers instead. Read
+replicas never vote in the controllers' replica set; their API servers read
+from the local Mongo secondary and serve only read-only requests, such as
+those made by 'juju status', the 'show-*' commands and 'juju debug-log'. Use
+them to take status polling load off the primary; the all-watcher used by the
+GUI and other dashboards is only served by the other controllers. Read
+replicas are not published to agents, so point the clients that should use
+them at their addresses directly. The number of read replicas can't be reduced with
+this command; remove read replica machines with 'juju remove-machine'.
+
 Examples:
     # Ensure that the controller is still in highly available mode. If
     # there is only 1 controller running, this will ensure there
@@ -90,6 +109,9 @@ Examples:
     # server2 used first, and if necessary, newly created controller
     # machines having at least 8GB RAM.
     juju enable-ha -n 7 --to server1,server2 --constraints mem=8G
+
+    # Ensure that 2 read replica controllers are available.
+    juju enable-ha --read-replicas 2
 `
 
 // formatSimple marshals value to a yaml-formatted []byte, unless value is nil.
@@ -151,6 +173,7 @@ func (c *enableHACommand) Info() *cmd.Info {
 func (c *enableHACommand) SetFlags(f *gnuflag.FlagSet) {
 	c.ControllerCommandBase.SetFlags(f)
 	f.IntVar(&c.NumControllers, "n", 0, "Number of controllers to make available")
+	f.IntVar(&c.ReadReplicas, "read-replicas", -1, "Number of read replica controllers to make available")
 	f.StringVar(&c.PlacementSpec, "to", "", "The machine(s) to become controllers, bypasses constraints")
 	f.StringVar(&c.ConstraintsStr, "constraints", "", "Additional machine constraints")
 	c.out.AddFlags(f, "simple", map[string]cmd.Formatter{
@@ -165,6 +188,9 @@ func (c *enableHACommand) Init(args []string) error {
 	if c.NumControllers < 0 || (c.NumControllers%2 != 1 && c.NumControllers != 0) {
 		return errors.Errorf("must specify a number of controllers odd and non-negative")
 	}
+	if c.ReadReplicas >= 0 && c.NumControllers != 0 {
+		return errors.New("cannot specify both -n and --read-replicas")
+	}
 	if c.PlacementSpec != "" {
 		placementSpecs := strings.Split(c.PlacementSpec, ",")
 		c.Placement = make([]string, len(placementSpecs))
@@ -174,6 +200,9 @@ func (c *enableHACommand) Init(args []string) error {
 				return errors.New("enable-ha cannot be used with container placement directives")
 			}
 			if err == nil && p.Scope == instance.MachineScope {
+				if c.ReadReplicas >= 0 {
+					return errors.New("read replicas cannot be placed on existing machines")
+				}
 				// Targeting machines is ok.
 				c.Placement[i] = p.String()
 				continue
@@ -204,10 +233,14 @@ type MakeHAClient interface {
 	EnableHA(
 		numControllers int, cons constraints.Value,
 		placement []string) (params.ControllersChanges, error)
+	EnableReadReplicas(
+		numReplicas int, cons constraints.Value,
+		placement []string) (params.ControllersChanges, error)
 }
 
 // Run connects to the environment specified on the command line
-// and calls EnableHA.
+// and calls EnableHA, or EnableReadReplicas if read replicas were
+// requested.
 func (c *enableHACommand) Run(ctx *cmd.Context) error {
 	var err error
 	c.Constraints, err = common.ParseConstraints(ctx, c.ConstraintsStr)
@@ -220,11 +253,20 @@ func (c *enableHACommand) Run(ctx *cmd.Context) error {
 	}
 
 	defer haClient.Close()
-	enableHAResult, err := haClient.EnableHA(
-		c.NumControllers,
-		c.Constraints,
-		c.Placement,
-	)
+	var enableHAResult params.ControllersChanges
+	if c.ReadReplicas >= 0 {
+		enableHAResult, err = haClient.EnableReadReplicas(
+			c.ReadReplicas,
+			c.Constraints,
+			c.Placement,
+		)
+	} else {
+		enableHAResult, err = haClient.EnableHA(
+			c.NumControllers,
+			c.Constraints,
+			c.Placement,
+		)
+	}
 	if err != nil {
 		return block.ProcessBlockedError(err, block.BlockChange)
 	}
@@ -44,11 +44,12 @@ func (s *EnableHASuite) SetUpTest(c *gc.C) {
 	// Initialize numControllers to an invalid number to validate
 	// that enable-ha doesn't call into the API when its
 	// pre-checks fail
-	s.fake = &fakeHAClient{numControllers: invalidNumServers}
+	s.fake = &fakeHAClient{numControllers: invalidNumServers, readReplicas: invalidNumServers}
 }
 
 type fakeHAClient struct {
 	numControllers int
+	readReplicas   int
 	cons           constraints.Value
 	err            error
 	placement      []string
@@ -97,6 +98,21 @@ func (f *fakeHAClient) EnableHA(numControllers int, cons constraints.Value, plac
 	return f.result, nil
 }
 
+func (f *fakeHAClient) EnableReadReplicas(numReplicas int, cons constraints.Value, placement []string) (
+	params.ControllersChanges, error,
+) {
+	f.readReplicas = numReplicas
+	f.cons = cons
+	f.placement = placement
+	if f.err != nil {
+		return f.result, f.err
+	}
+	for i := 0; i < numReplicas; i++ {
+		f.result.Added = append(f.result.Added, fmt.Sprintf("machine-%d", i+1))
+	}
+	return f.result, nil
+}
+
 var _ = gc.Suite(&EnableHASuite{})
 
 func (s *EnableHASuite) runEnableHA(c *gc.C, args ...string) (*cmd.Context, error) {
@@ -277,3 +293,28 @@ func (s *EnableHASuite) TestEnableHADisallowsSeries(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "flag provided but not defined: --series")
 	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "")
 }
+
+func (s *EnableHASuite) TestEnableReadReplicas(c *gc.C) {
+	ctx, err := s.runEnableHA(c, "--read-replicas", "2", "--constraints", "mem=8G")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, `
+adding machines: 1, 2
+
+`[1:])
+
+	c.Check(s.fake.readReplicas, gc.Equals, 2)
+	c.Check(s.fake.numControllers, gc.Equals, invalidNumServers)
+	c.Check(s.fake.cons, gc.DeepEquals, constraints.MustParse("mem=8G"))
+}
+
+func (s *EnableHASuite) TestEnableReadReplicasWithNumControllers(c *gc.C) {
+	_, err := s.runEnableHA(c, "-n", "3", "--read-replicas", "2")
+	c.Assert(err, gc.ErrorMatches, "cannot specify both -n and --read-replicas")
+	c.Check(s.fake.readReplicas, gc.Equals, invalidNumServers)
+}
+
+func (s *EnableHASuite) TestEnableReadReplicasToExisting(c *gc.C) {
+	_, err := s.runEnableHA(c, "--read-replicas", "2", "--to", "1")
+	c.Assert(err, gc.ErrorMatches, "read replicas cannot be placed on existing machines")
+	c.Check(s.fake.readReplicas, gc.Equals, invalidNumServers)
+}
@@ -53,6 +53,9 @@ Currently available charm hook tools are:
     relation-ids             list all relation ids with the given relation name
     relation-list            list relation units
     relation-set             set relation settings
+    state-delete             delete unit state
+    state-get                print unit state
+    state-set                set unit state
     status-get               print status information
     status-set               set status information
     storage-add              add storage instances
@@ -97,6 +100,9 @@ var expectedCommands = []string{
 	"relation-list",
 	"relation-set",
 	"resource-get",
+	"state-delete",
+	"state-get",
+	"state-set",
 	"status-get",
 	"status-set",
 	"storage-add",
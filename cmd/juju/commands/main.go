@@ -312,6 +312,7 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(application.NewResolvedCommand())
 	r.Register(newDebugLogCommand(nil))
 	r.Register(newDebugHooksCommand(nil))
+	r.Register(newReplayHookCommand(nil))
 
 	// Configuration commands.
 	r.Register(model.NewModelGetConstraintsCommand())
@@ -335,6 +336,9 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(backups.NewRemoveCommand())
 	r.Register(backups.NewRestoreCommand())
 	r.Register(backups.NewUploadCommand())
+	r.Register(backups.NewVerifyCommand())
+	r.Register(backups.NewCreateModelCommand())
+	r.Register(backups.NewRestoreModelCommand())
 
 	// Manage authorized ssh keys.
 	r.Register(NewAddKeysCommand())
@@ -374,6 +378,10 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(model.NewRevokeCommand())
 	r.Register(model.NewShowCommand())
 	r.Register(model.NewModelCredentialCommand())
+	r.Register(model.NewCheckModelCommand())
+	r.Register(model.NewListCleanupsCommand())
+	r.Register(model.NewForceCleanupCommand())
+	r.Register(model.NewCancelCleanupCommand())
 
 	r.Register(newMigrateCommand())
 	r.Register(model.NewExportBundleCommand())
@@ -389,6 +397,7 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(action.NewShowOutputCommand())
 	r.Register(action.NewListCommand())
 	r.Register(action.NewCancelCommand())
+	r.Register(action.NewPinCommand())
 
 	// Manage controller availability
 	r.Register(newEnableHACommand())
@@ -402,6 +411,7 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(application.NewApplicationGetConstraintsCommand())
 	r.Register(application.NewApplicationSetConstraintsCommand())
 	r.Register(application.NewBundleDiffCommand())
+	r.Register(application.NewShowUnitStateCommand())
 
 	// Operation protection commands
 	r.Register(block.NewDisableCommand())
@@ -447,6 +457,9 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(controller.NewUnregisterCommand(jujuclient.NewFileClientStore()))
 	r.Register(controller.NewEnableDestroyControllerCommand())
 	r.Register(controller.NewShowControllerCommand())
+	r.Register(controller.NewShowRaftStatusCommand())
+	r.Register(controller.NewRemoveRaftServerCommand())
+	r.Register(controller.NewRecoverControllerCommand())
 	r.Register(controller.NewConfigCommand())
 
 	// Debug Metrics
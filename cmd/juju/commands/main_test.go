@@ -414,9 +414,12 @@ var commandNames = []string{
 	"budget",
 	"cached-images",
 	"cancel-action",
+	"cancel-cleanup",
 	"change-user-password",
 	"charm",
 	"charm-resources",
+	"check-model",
+	"cleanups",
 	"clouds",
 	"collect-metrics",
 	"config",
@@ -424,6 +427,7 @@ var commandNames = []string{
 	"controller-config",
 	"controllers",
 	"create-backup",
+	"create-model-backup",
 	"create-storage-pool",
 	"create-wallet",
 	"credentials",
@@ -446,6 +450,7 @@ var commandNames = []string{
 	"expose",
 	"find-offers",
 	"firewall-rules",
+	"force-cleanup",
 	"get-constraints",
 	"get-model-constraints",
 	"grant",
@@ -463,6 +468,7 @@ var commandNames = []string{
 	"list-backups",
 	"list-cached-images",
 	"list-charm-resources",
+	"list-cleanups",
 	"list-clouds",
 	"list-controllers",
 	"list-credentials",
@@ -494,7 +500,9 @@ var commandNames = []string{
 	"offer",
 	"offers",
 	"payloads",
+	"pin-action-result",
 	"plans",
+	"recover-controller",
 	"regions",
 	"register",
 	"relate", //alias for add-relation
@@ -508,16 +516,19 @@ var commandNames = []string{
 	"remove-k8s",
 	"remove-machine",
 	"remove-offer",
+	"remove-raft-server",
 	"remove-relation",
 	"remove-saas",
 	"remove-ssh-key",
 	"remove-storage",
 	"remove-unit",
 	"remove-user",
+	"replay-hook",
 	"resolved",
 	"resolve",
 	"resources",
 	"restore-backup",
+	"restore-model-backup",
 	"resume-relation",
 	"retry-provisioning",
 	"revoke",
@@ -546,9 +557,11 @@ var commandNames = []string{
 	"show-machine",
 	"show-model",
 	"show-offer",
+	"show-raft-status",
 	"show-status",
 	"show-status-log",
 	"show-storage",
+	"show-unit-state",
 	"show-user",
 	"show-wallet",
 	"sla",
@@ -575,6 +588,7 @@ var commandNames = []string{
 	"upgrade-series",
 	"upload-backup",
 	"users",
+	"verify-backup",
 	"version",
 	"wallets",
 	"whoami",
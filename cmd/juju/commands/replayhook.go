@@ -0,0 +1,107 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/network/ssh"
+	"github.com/juju/juju/worker/uniter/runner/context"
+)
+
+func newReplayHookCommand(hostChecker ssh.ReachableChecker) cmd.Command {
+	c := new(replayHookCommand)
+	c.setHostChecker(hostChecker)
+	return modelcmd.Wrap(c)
+}
+
+// replayHookCommand re-runs a captured failed hook on a unit.
+type replayHookCommand struct {
+	sshCommand
+	hookId string
+	dryRun bool
+}
+
+const replayHookDoc = `
+Re-run a failed hook on a unit, in the context captured when it failed.
+
+When the "capture-failed-hooks" model config is enabled, the unit agent
+records the environment, config and remote relation settings seen by any
+hook that fails, under an id reported in the unit's log. The ten most
+recent failures are kept for each unit. This command runs the hook again
+against those captured inputs, without waiting for it to fire.
+
+With --dry-run, any relation or leader settings, statuses, workload
+version or pod spec the hook sets are printed rather than committed.
+
+See the "juju help ssh" for information about SSH related options
+accepted by the replay-hook command.
+
+Examples:
+
+    juju model-config capture-failed-hooks=true
+    juju replay-hook mysql/0 20190723-101500-db-relation-changed
+    juju replay-hook --dry-run mysql/0 20190723-101500-db-relation-changed
+
+See also:
+    debug-hooks
+    model-config
+`
+
+func (c *replayHookCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "replay-hook",
+		Args:    "<unit name> <hook id>",
+		Purpose: "Re-run a captured failed hook on a unit.",
+		Doc:     replayHookDoc,
+	}
+}
+
+func (c *replayHookCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.sshCommand.SetFlags(f)
+	f.BoolVar(&c.dryRun, "dry-run", false, "Print, rather than commit, changes made by the hook")
+}
+
+func (c *replayHookCommand) Init(args []string) error {
+	switch len(args) {
+	case 0:
+		return errors.Errorf("no unit name specified")
+	case 1:
+		return errors.Errorf("no hook id specified")
+	}
+	c.Target, c.hookId = args[0], args[1]
+	if !names.IsValidUnit(c.Target) {
+		return errors.Errorf("%q is not a valid unit name", c.Target)
+	}
+	if !context.IsValidHookCaptureId(c.hookId) {
+		return errors.Errorf("%q is not a valid hook id", c.hookId)
+	}
+	return cmd.CheckEmpty(args[2:])
+}
+
+// Run resolves the address of the unit's machine, and connects to
+// it via SSH to replay the hook with juju-run.
+func (c *replayHookCommand) Run(ctx *cmd.Context) error {
+	err := c.initRun()
+	if err != nil {
+		return err
+	}
+	defer c.cleanupRun()
+	c.Args = []string{c.remoteCommand()}
+	return c.sshCommand.Run(ctx)
+}
+
+func (c *replayHookCommand) remoteCommand() string {
+	command := fmt.Sprintf("sudo juju-run --replay-hook %s", c.hookId)
+	if c.dryRun {
+		command += " --dry-run"
+	}
+	return command + " " + c.Target
+}
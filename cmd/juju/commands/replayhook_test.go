@@ -0,0 +1,88 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"regexp"
+	"runtime"
+
+	"github.com/juju/cmd/cmdtesting"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	jujussh "github.com/juju/juju/network/ssh"
+)
+
+var _ = gc.Suite(&ReplayHookSuite{})
+
+type ReplayHookSuite struct {
+	SSHCommonSuite
+}
+
+var replayHookTests = []struct {
+	info        string
+	args        []string
+	hostChecker jujussh.ReachableChecker
+	error       string
+	expected    *argsSpec
+}{{
+	info:        "replay",
+	args:        []string{"mysql/0", "20190723-101500-server-relation-changed"},
+	hostChecker: validAddresses("0.private", "0.public", "0.1.2.3"),
+	expected: &argsSpec{
+		hostKeyChecking: "yes",
+		knownHosts:      "0",
+		argsMatch:       `ubuntu@0\.(private|public|1\.2\.3) sudo juju-run --replay-hook 20190723-101500-server-relation-changed mysql/0`,
+	},
+}, {
+	info:        "dry run",
+	args:        []string{"--dry-run", "mysql/0", "20190723-101500-config-changed"},
+	hostChecker: validAddresses("0.private", "0.public", "0.1.2.3"),
+	expected: &argsSpec{
+		hostKeyChecking: "yes",
+		knownHosts:      "0",
+		argsMatch:       `ubuntu@0\.(private|public|1\.2\.3) sudo juju-run --replay-hook 20190723-101500-config-changed --dry-run mysql/0`,
+	},
+}, {
+	info:  "no hook id",
+	args:  []string{"mysql/0"},
+	error: `no hook id specified`,
+}, {
+	info:  "invalid hook id",
+	args:  []string{"mysql/0", "config-changed; rm -rf /"},
+	error: `"config-changed; rm -rf /" is not a valid hook id`,
+}, {
+	info:  "invalid unit",
+	args:  []string{"mysql", "20190723-101500-config-changed"},
+	error: `"mysql" is not a valid unit name`,
+}, {
+	info:  "no args at all",
+	args:  nil,
+	error: `no unit name specified`,
+}}
+
+func (s *ReplayHookSuite) TestReplayHookCommand(c *gc.C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("juju-run hook replay is not supported on windows")
+	}
+
+	s.setupModel(c)
+
+	for i, t := range replayHookTests {
+		c.Logf("test %d: %s\n\t%s\n", i, t.info, t.args)
+
+		s.setHostChecker(t.hostChecker)
+		s.setForceAPIv1(false)
+
+		ctx, err := cmdtesting.RunCommand(c, newReplayHookCommand(s.hostChecker), t.args...)
+		if t.error != "" {
+			c.Check(err, gc.ErrorMatches, regexp.QuoteMeta(t.error))
+		} else {
+			c.Check(err, jc.ErrorIsNil)
+			if t.expected != nil {
+				t.expected.check(c, cmdtesting.Stdout(ctx))
+			}
+		}
+	}
+}
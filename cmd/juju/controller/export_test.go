@@ -8,6 +8,7 @@ import (
 
 	"github.com/juju/clock"
 	"github.com/juju/cmd"
+	"github.com/juju/utils/ssh"
 
 	"github.com/juju/juju/api"
 	"github.com/juju/juju/api/base"
@@ -93,6 +94,36 @@ func NewEnableDestroyControllerCommandForTest(api removeBlocksAPI, store jujucli
 	return modelcmd.WrapController(c)
 }
 
+// NewShowRaftStatusCommandForTest returns a showRaftStatusCommand with
+// the API mocked out.
+func NewShowRaftStatusCommandForTest(api RaftClusterAPI, store jujuclient.ClientStore) cmd.Command {
+	c := &showRaftStatusCommand{
+		api: api,
+	}
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
+
+// NewRemoveRaftServerCommandForTest returns a removeRaftServerCommand
+// with the API mocked out.
+func NewRemoveRaftServerCommandForTest(api RaftClusterAPI, store jujuclient.ClientStore) cmd.Command {
+	c := &removeRaftServerCommand{
+		api: api,
+	}
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
+
+// NewRecoverControllerCommandForTest returns a recoverControllerCommand
+// with the ssh connection mocked out.
+func NewRecoverControllerCommandForTest(
+	runSSH func(ctx *cmd.Context, host string, args []string, options *ssh.Options) error,
+) cmd.Command {
+	return &recoverControllerCommand{
+		runSSH: runSSH,
+	}
+}
+
 // NewDestroyCommandForTest returns a DestroyCommand with the controller and
 // client endpoints mocked out.
 func NewDestroyCommandForTest(
@@ -0,0 +1,288 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+
+	"github.com/juju/juju/api/raftcluster"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/common"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+)
+
+// RaftClusterAPI defines the API methods used by the raft cluster
+// commands.
+type RaftClusterAPI interface {
+	Close() error
+	Status() ([]params.RaftServerStatus, error)
+	RemoveServer(id string, force bool) error
+}
+
+func newRaftClusterAPI(c *modelcmd.ControllerCommandBase) (RaftClusterAPI, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	client := raftcluster.NewClient(root)
+	if client.BestAPIVersion() < 1 {
+		client.Close()
+		return nil, errors.New("raft cluster status is not supported by this controller")
+	}
+	return client, nil
+}
+
+var showRaftStatusDoc = `
+The controller machines use raft to agree on the holders of leases, such as
+application leadership. This command shows the state of the raft node on each
+controller machine: whether it is the leader or a follower, its current term,
+the last log index it has applied and the last one it has stored, when it last
+heard from the leader and the index and term of its latest snapshot.
+
+Every server in the raft configuration is listed. A server that did not
+report its state, such as one whose controller machine has been lost, is
+shown with an error; such servers can be removed from the configuration with
+'juju remove-raft-server'.
+
+Only controller administrators can see the raft status.
+
+Examples:
+    juju show-raft-status
+    juju show-raft-status -c prod --format yaml
+
+See also:
+    remove-raft-server
+    enable-ha
+    show-controller
+`
+
+// NewShowRaftStatusCommand returns a command that shows the state of
+// the controllers' raft cluster.
+func NewShowRaftStatusCommand() cmd.Command {
+	return modelcmd.WrapController(&showRaftStatusCommand{})
+}
+
+type showRaftStatusCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+	api RaftClusterAPI
+}
+
+// Info implements Command.Info.
+func (c *showRaftStatusCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "show-raft-status",
+		Purpose: "Shows the state of the controllers' raft cluster.",
+		Doc:     showRaftStatusDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *showRaftStatusCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ControllerCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatRaftStatusTabular,
+	})
+}
+
+// Init implements Command.Init.
+func (c *showRaftStatusCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+func (c *showRaftStatusCommand) getAPI() (RaftClusterAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	return newRaftClusterAPI(&c.ControllerCommandBase)
+}
+
+// Run implements Command.Run.
+func (c *showRaftStatusCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	servers, err := client.Status()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	result := make([]raftServerStatus, len(servers))
+	for i, server := range servers {
+		result[i] = raftServerStatus{
+			ServerID:          server.ServerID,
+			Address:           server.Address,
+			Suffrage:          server.Suffrage,
+			State:             server.State,
+			Leader:            server.LeaderID,
+			Term:              server.Term,
+			AppliedIndex:      server.AppliedIndex,
+			LastIndex:         server.LastIndex,
+			LastContact:       server.LastContact,
+			LastSnapshotIndex: server.LastSnapshotIndex,
+			LastSnapshotTerm:  server.LastSnapshotTerm,
+		}
+		if server.Error != nil {
+			result[i].Error = server.Error.Error()
+		}
+	}
+	return c.out.Write(ctx, result)
+}
+
+// raftServerStatus is the serialization of a raft server's state
+// for show-raft-status.
+type raftServerStatus struct {
+	ServerID          string     `yaml:"server-id" json:"server-id"`
+	Address           string     `yaml:"address,omitempty" json:"address,omitempty"`
+	Suffrage          string     `yaml:"suffrage,omitempty" json:"suffrage,omitempty"`
+	State             string     `yaml:"state,omitempty" json:"state,omitempty"`
+	Leader            string     `yaml:"leader,omitempty" json:"leader,omitempty"`
+	Term              uint64     `yaml:"term" json:"term"`
+	AppliedIndex      uint64     `yaml:"applied-index" json:"applied-index"`
+	LastIndex         uint64     `yaml:"last-index" json:"last-index"`
+	LastContact       *time.Time `yaml:"last-contact,omitempty" json:"last-contact,omitempty"`
+	LastSnapshotIndex uint64     `yaml:"last-snapshot-index" json:"last-snapshot-index"`
+	LastSnapshotTerm  uint64     `yaml:"last-snapshot-term" json:"last-snapshot-term"`
+	Error             string     `yaml:"error,omitempty" json:"error,omitempty"`
+}
+
+func formatRaftStatusTabular(writer io.Writer, value interface{}) error {
+	servers, ok := value.([]raftServerStatus)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", servers, value)
+	}
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{tw}
+	w.Println("Server", "Address", "Suffrage", "State", "Leader", "Term", "Applied", "Last index", "Last contact", "Snapshot", "Message")
+
+	now := time.Now()
+	for _, s := range servers {
+		if s.Error != "" && s.State == "" {
+			w.Println(s.ServerID, valueOrDash(s.Address), valueOrDash(s.Suffrage),
+				"unknown", noValueDisplay, noValueDisplay, noValueDisplay, noValueDisplay,
+				noValueDisplay, noValueDisplay, s.Error)
+			continue
+		}
+		lastContact := noValueDisplay
+		if s.LastContact != nil {
+			lastContact = common.UserFriendlyDuration(*s.LastContact, now)
+		} else if s.State != "Leader" {
+			lastContact = "never"
+		}
+		snapshot := noValueDisplay
+		if s.LastSnapshotIndex != 0 {
+			snapshot = fmt.Sprintf("%d (term %d)", s.LastSnapshotIndex, s.LastSnapshotTerm)
+		}
+		w.Println(s.ServerID, valueOrDash(s.Address), valueOrDash(s.Suffrage),
+			s.State, valueOrDash(s.Leader), s.Term, s.AppliedIndex, s.LastIndex,
+			lastContact, snapshot, s.Error)
+	}
+	tw.Flush()
+	return nil
+}
+
+func valueOrDash(value string) string {
+	if value == "" {
+		return noValueDisplay
+	}
+	return value
+}
+
+var removeRaftServerDoc = `
+This command removes a server from the raft configuration that the controller
+machines use to agree on lease holders. It is intended for repairing the
+cluster when a controller machine has been lost without being removed from
+the configuration, which stops the remaining servers from reaching a quorum
+as easily, or at all.
+
+Raft membership normally follows the controller machines: servers are added
+and removed as controller machines come and go, and a server that belongs to
+an existing controller machine would be added back. So, unless --force is
+given, the controller refuses to remove a server that belongs to a controller
+machine, or one that is still reporting its state. The raft leader can never
+be removed. Use 'juju show-raft-status' to find the servers that are not
+responding.
+
+Only controller administrators can remove raft servers.
+
+Examples:
+    juju remove-raft-server 2
+    juju remove-raft-server --force 2
+
+See also:
+    show-raft-status
+    remove-machine
+`
+
+// NewRemoveRaftServerCommand returns a command that removes a dead
+// server from the controllers' raft cluster.
+func NewRemoveRaftServerCommand() cmd.Command {
+	return modelcmd.WrapController(&removeRaftServerCommand{})
+}
+
+type removeRaftServerCommand struct {
+	modelcmd.ControllerCommandBase
+	api RaftClusterAPI
+
+	serverID string
+	force    bool
+}
+
+// Info implements Command.Info.
+func (c *removeRaftServerCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "remove-raft-server",
+		Args:    "<server id>",
+		Purpose: "Removes a dead server from the controllers' raft cluster.",
+		Doc:     removeRaftServerDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *removeRaftServerCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ControllerCommandBase.SetFlags(f)
+	f.BoolVar(&c.force, "force", false, "Remove the server even if it is still running or belongs to a controller machine")
+}
+
+// Init implements Command.Init.
+func (c *removeRaftServerCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no server ID specified")
+	}
+	c.serverID, args = args[0], args[1:]
+	return cmd.CheckEmpty(args)
+}
+
+func (c *removeRaftServerCommand) getAPI() (RaftClusterAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	return newRaftClusterAPI(&c.ControllerCommandBase)
+}
+
+// Run implements Command.Run.
+func (c *removeRaftServerCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	if err := client.RemoveServer(c.serverID, c.force); err != nil {
+		return errors.Trace(err)
+	}
+	ctx.Infof("Removed server %q from the raft cluster.", c.serverID)
+	return nil
+}
@@ -0,0 +1,182 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller_test
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/controller"
+	"github.com/juju/juju/jujuclient"
+)
+
+type raftStatusSuite struct {
+	baseControllerSuite
+	api   *fakeRaftClusterAPI
+	store *jujuclient.MemStore
+}
+
+var _ = gc.Suite(&raftStatusSuite{})
+
+func (s *raftStatusSuite) SetUpTest(c *gc.C) {
+	s.baseControllerSuite.SetUpTest(c)
+
+	lastContact := time.Date(2019, 6, 1, 10, 0, 0, 0, time.UTC)
+	s.api = &fakeRaftClusterAPI{
+		servers: []params.RaftServerStatus{{
+			ServerID:          "0",
+			Address:           "10.0.0.1:17070",
+			Suffrage:          "Voter",
+			State:             "Leader",
+			LeaderID:          "0",
+			Term:              4,
+			AppliedIndex:      120,
+			LastIndex:         121,
+			LastSnapshotIndex: 100,
+			LastSnapshotTerm:  3,
+		}, {
+			ServerID:     "1",
+			Address:      "10.0.0.2:17070",
+			Suffrage:     "Voter",
+			State:        "Follower",
+			LeaderID:     "0",
+			Term:         4,
+			AppliedIndex: 118,
+			LastIndex:    121,
+			LastContact:  &lastContact,
+		}, {
+			ServerID: "3",
+			Address:  "10.0.0.4:17070",
+			Suffrage: "Voter",
+			Error:    &params.Error{Message: `raft server "3" did not report its status`},
+		}},
+	}
+	s.store = jujuclient.NewMemStore()
+	s.store.CurrentControllerName = "fake"
+	s.store.Controllers["fake"] = jujuclient.ControllerDetails{}
+}
+
+func (s *raftStatusSuite) TestShowTabular(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, controller.NewShowRaftStatusCommandForTest(s.api, s.store))
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCallNames(c, "Status", "Close")
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+Server  Address         Suffrage  State     Leader  Term  Applied  Last index  Last contact  Snapshot      Message
+0       10.0.0.1:17070  Voter     Leader    0       4     120      121         -             100 (term 3)  
+1       10.0.0.2:17070  Voter     Follower  0       4     118      121         2019-06-01    -             
+3       10.0.0.4:17070  Voter     unknown   -       -     -        -           -             -             raft server "3" did not report its status
+`[1:])
+}
+
+func (s *raftStatusSuite) TestShowYAML(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, controller.NewShowRaftStatusCommandForTest(s.api, s.store), "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+- server-id: "0"
+  address: 10.0.0.1:17070
+  suffrage: Voter
+  state: Leader
+  leader: "0"
+  term: 4
+  applied-index: 120
+  last-index: 121
+  last-snapshot-index: 100
+  last-snapshot-term: 3
+- server-id: "1"
+  address: 10.0.0.2:17070
+  suffrage: Voter
+  state: Follower
+  leader: "0"
+  term: 4
+  applied-index: 118
+  last-index: 121
+  last-contact: 2019-06-01T10:00:00Z
+  last-snapshot-index: 0
+  last-snapshot-term: 0
+- server-id: "3"
+  address: 10.0.0.4:17070
+  suffrage: Voter
+  term: 0
+  applied-index: 0
+  last-index: 0
+  last-snapshot-index: 0
+  last-snapshot-term: 0
+  error: raft server "3" did not report its status
+`[1:])
+}
+
+func (s *raftStatusSuite) TestShowError(c *gc.C) {
+	s.api.SetErrors(errors.New("boom"))
+	_, err := cmdtesting.RunCommand(c, controller.NewShowRaftStatusCommandForTest(s.api, s.store))
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *raftStatusSuite) TestShowUnrecognizedArg(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, controller.NewShowRaftStatusCommandForTest(s.api, s.store), "whoops")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["whoops"\]`)
+}
+
+func (s *raftStatusSuite) newRemoveCommand() cmd.Command {
+	return controller.NewRemoveRaftServerCommandForTest(s.api, s.store)
+}
+
+func (s *raftStatusSuite) TestRemove(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, s.newRemoveCommand(), "3")
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCalls(c, []testing.StubCall{
+		{FuncName: "RemoveServer", Args: []interface{}{"3", false}},
+		{FuncName: "Close"},
+	})
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "Removed server \"3\" from the raft cluster.\n")
+}
+
+func (s *raftStatusSuite) TestRemoveForce(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, s.newRemoveCommand(), "--force", "2")
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCall(c, 0, "RemoveServer", "2", true)
+}
+
+func (s *raftStatusSuite) TestRemoveError(c *gc.C) {
+	s.api.SetErrors(errors.New(`raft server "2" is still running`))
+	_, err := cmdtesting.RunCommand(c, s.newRemoveCommand(), "2")
+	c.Assert(err, gc.ErrorMatches, `raft server "2" is still running`)
+}
+
+func (s *raftStatusSuite) TestRemoveInit(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, s.newRemoveCommand())
+	c.Assert(err, gc.ErrorMatches, "no server ID specified")
+	_, err = cmdtesting.RunCommand(c, s.newRemoveCommand(), "2", "3")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["3"\]`)
+	s.api.CheckNoCalls(c)
+}
+
+type fakeRaftClusterAPI struct {
+	testing.Stub
+	servers []params.RaftServerStatus
+}
+
+func (f *fakeRaftClusterAPI) Close() error {
+	f.MethodCall(f, "Close")
+	return nil
+}
+
+func (f *fakeRaftClusterAPI) Status() ([]params.RaftServerStatus, error) {
+	f.MethodCall(f, "Status")
+	if err := f.NextErr(); err != nil {
+		return nil, err
+	}
+	return f.servers, nil
+}
+
+func (f *fakeRaftClusterAPI) RemoveServer(id string, force bool) error {
+	f.MethodCall(f, "RemoveServer", id, force)
+	return f.NextErr()
+}
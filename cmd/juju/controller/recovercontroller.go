@@ -0,0 +1,128 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller
+
+import (
+	"os"
+	"path"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/utils/ssh"
+	"gopkg.in/juju/names.v2"
+)
+
+var recoverControllerDoc = `
+A controller with several controller machines keeps working as long as a
+majority of them are available. Once a majority is lost, the controller's
+database cannot elect a primary and the API stops serving requests. This
+command restores the controller from a single surviving controller machine,
+so that high availability can then be re-enabled.
+
+The command connects to the surviving machine with ssh, as the ubuntu user,
+and runs the recovery there. The machine agent is stopped while the recovery
+takes place. The Mongo replica set is forced to contain only the survivor,
+the lost machines are recorded as no longer voting, the survivor's API
+addresses are published to agents and the raft cluster used for leases is
+reset to the survivor. The machine agent is then restarted.
+
+The surviving machine is identified by its machine id in the controller
+model, and an address that it can be reached on. Because the controller's
+API is not available, the address cannot be looked up and must be given.
+
+Use --dry-run to print the recovery steps without making any changes. Once
+the controller is recovered, remove the lost machines with
+'juju remove-machine --force' and run 'juju enable-ha' to add new ones.
+
+Examples:
+    juju recover-controller --dry-run 1 10.0.0.2
+    juju recover-controller 1 10.0.0.2
+
+See also:
+    enable-ha
+    remove-machine
+    show-raft-status
+`
+
+// NewRecoverControllerCommand returns a command that recovers a
+// controller that has lost a majority of its machines.
+func NewRecoverControllerCommand() cmd.Command {
+	return &recoverControllerCommand{
+		runSSH: runSSHCommand,
+	}
+}
+
+type recoverControllerCommand struct {
+	cmd.CommandBase
+	runSSH func(ctx *cmd.Context, host string, args []string, options *ssh.Options) error
+
+	machineId       string
+	address         string
+	dryRun          bool
+	noHostKeyChecks bool
+}
+
+// Info implements Command.Info.
+func (c *recoverControllerCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "recover-controller",
+		Args:    "<machine id> <address>",
+		Purpose: "Recovers a controller that has lost a majority of its machines.",
+		Doc:     recoverControllerDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *recoverControllerCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	f.BoolVar(&c.dryRun, "dry-run", false, "Print the recovery steps without making any changes")
+	f.BoolVar(&c.noHostKeyChecks, "no-host-key-checks", false, "Skip host key checking (INSECURE)")
+}
+
+// Init implements Command.Init.
+func (c *recoverControllerCommand) Init(args []string) error {
+	switch len(args) {
+	case 0:
+		return errors.New("no machine id specified")
+	case 1:
+		return errors.New("no address specified")
+	}
+	c.machineId, c.address, args = args[0], args[1], args[2:]
+	if !names.IsValidMachine(c.machineId) {
+		return errors.NotValidf("machine id %q", c.machineId)
+	}
+	if names.IsContainerMachine(c.machineId) {
+		return errors.Errorf("machine %s is a container, not a controller machine", c.machineId)
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements Command.Run.
+func (c *recoverControllerCommand) Run(ctx *cmd.Context) error {
+	tag := names.NewMachineTag(c.machineId)
+	jujud := path.Join("/var/lib/juju/tools", tag.String(), "jujud")
+	args := []string{"sudo", jujud, "recover-controller", "--machine-id", c.machineId}
+	if c.dryRun {
+		args = append(args, "--dry-run")
+	}
+
+	var options ssh.Options
+	if c.noHostKeyChecks {
+		options.SetStrictHostKeyChecking(ssh.StrictHostChecksNo)
+		options.SetKnownHostsFile(os.DevNull)
+	}
+	if err := c.runSSH(ctx, "ubuntu@"+c.address, args, &options); err != nil {
+		return errors.Annotatef(err, "recovering controller machine %s", c.machineId)
+	}
+	return nil
+}
+
+func runSSHCommand(ctx *cmd.Context, host string, args []string, options *ssh.Options) error {
+	command := ssh.Command(host, args, options)
+	command.Stdin = ctx.Stdin
+	command.Stdout = ctx.Stdout
+	command.Stderr = ctx.Stderr
+	return command.Run()
+}
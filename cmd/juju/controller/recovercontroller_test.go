@@ -0,0 +1,105 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/ssh"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/controller"
+)
+
+type recoverControllerSuite struct {
+	baseControllerSuite
+	host    string
+	args    []string
+	options *ssh.Options
+	err     error
+}
+
+var _ = gc.Suite(&recoverControllerSuite{})
+
+func (s *recoverControllerSuite) SetUpTest(c *gc.C) {
+	s.baseControllerSuite.SetUpTest(c)
+	s.host = ""
+	s.args = nil
+	s.options = nil
+	s.err = nil
+}
+
+func (s *recoverControllerSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	command := controller.NewRecoverControllerCommandForTest(
+		func(_ *cmd.Context, host string, args []string, options *ssh.Options) error {
+			s.host = host
+			s.args = args
+			s.options = options
+			return s.err
+		},
+	)
+	return cmdtesting.RunCommand(c, command, args...)
+}
+
+func (s *recoverControllerSuite) TestInitErrors(c *gc.C) {
+	for i, test := range []struct {
+		args   []string
+		expect string
+	}{{
+		args:   nil,
+		expect: "no machine id specified",
+	}, {
+		args:   []string{"1"},
+		expect: "no address specified",
+	}, {
+		args:   []string{"foo", "10.0.0.2"},
+		expect: `machine id "foo" not valid`,
+	}, {
+		args:   []string{"1/lxd/0", "10.0.0.2"},
+		expect: "machine 1/lxd/0 is a container, not a controller machine",
+	}, {
+		args:   []string{"1", "10.0.0.2", "extra"},
+		expect: `unrecognized args: \["extra"\]`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		_, err := s.run(c, test.args...)
+		c.Check(err, gc.ErrorMatches, test.expect)
+	}
+}
+
+func (s *recoverControllerSuite) TestRun(c *gc.C) {
+	_, err := s.run(c, "1", "10.0.0.2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.host, gc.Equals, "ubuntu@10.0.0.2")
+	c.Assert(s.args, jc.DeepEquals, []string{
+		"sudo", "/var/lib/juju/tools/machine-1/jujud",
+		"recover-controller", "--machine-id", "1",
+	})
+}
+
+func (s *recoverControllerSuite) TestRunDryRun(c *gc.C) {
+	_, err := s.run(c, "--dry-run", "1", "10.0.0.2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.args, jc.DeepEquals, []string{
+		"sudo", "/var/lib/juju/tools/machine-1/jujud",
+		"recover-controller", "--machine-id", "1", "--dry-run",
+	})
+}
+
+func (s *recoverControllerSuite) TestRunNoHostKeyChecks(c *gc.C) {
+	_, err := s.run(c, "--no-host-key-checks", "1", "10.0.0.2")
+	c.Assert(err, jc.ErrorIsNil)
+	var expect ssh.Options
+	expect.SetStrictHostKeyChecking(ssh.StrictHostChecksNo)
+	expect.SetKnownHostsFile("/dev/null")
+	c.Assert(s.options, jc.DeepEquals, &expect)
+}
+
+func (s *recoverControllerSuite) TestRunError(c *gc.C) {
+	s.err = errors.New("exit status 1")
+	_, err := s.run(c, "1", "10.0.0.2")
+	c.Assert(err, gc.ErrorMatches, "recovering controller machine 1: exit status 1")
+}
@@ -5,6 +5,8 @@ package controller
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
 
 	"github.com/juju/cmd"
@@ -234,6 +236,11 @@ type ShowControllerDetails struct {
 	// Account is the account details for the user logged into this controller.
 	Account *AccountDetails `yaml:"account,omitempty" json:"account,omitempty"`
 
+	// Warnings is a collection of problems with the controller that
+	// the user should know about, such as its voting controller machines
+	// not being spread across availability zones.
+	Warnings []string `yaml:"warnings,omitempty" json:"warnings,omitempty"`
+
 	// Errors is a collection of errors related to accessing this controller details.
 	Errors []string `yaml:"errors,omitempty" json:"errors,omitempty"`
 }
@@ -274,6 +281,9 @@ type MachineDetails struct {
 	// InstanceID holds the cloud instance id of the machine.
 	InstanceID string `yaml:"instance-id,omitempty" json:"instance-id,omitempty"`
 
+	// AvailabilityZone holds the availability zone of the machine's instance.
+	AvailabilityZone string `yaml:"availability-zone,omitempty" json:"availability-zone,omitempty"`
+
 	// HAStatus holds information informing of the HA status of the machine.
 	HAStatus string `yaml:"ha-status,omitempty" json:"ha-status,omitempty"`
 }
@@ -428,11 +438,57 @@ func (c *showControllerCommand) convertMachinesForShow(
 			instId = "(unprovisioned)"
 		}
 		details := MachineDetails{InstanceID: instId}
+		if m.Hardware != nil && m.Hardware.AvailabilityZone != nil {
+			details.AvailabilityZone = *m.Hardware.AvailabilityZone
+		}
 		if numControllers > 1 {
 			details.HAStatus = haStatus(m.HasVote, m.WantsVote, m.Status)
 		}
 		controller.Machines[m.Id] = details
 	}
+	if warning := zoneDiversityWarning(controllerModel.Machines); warning != "" {
+		controller.Warnings = append(controller.Warnings, warning)
+	}
+}
+
+// zoneDiversityWarning returns a warning if a majority of the voting
+// controller machines are in the same availability zone, so that the
+// loss of that zone would leave the controller without a quorum.
+func zoneDiversityWarning(machines []base.Machine) string {
+	var voters int
+	zoneVoters := make(map[string][]string)
+	for _, m := range machines {
+		if !m.WantsVote || !m.HasVote {
+			continue
+		}
+		voters++
+		if m.Hardware != nil && m.Hardware.AvailabilityZone != nil && *m.Hardware.AvailabilityZone != "" {
+			zone := *m.Hardware.AvailabilityZone
+			zoneVoters[zone] = append(zoneVoters[zone], m.Id)
+		}
+	}
+	if voters < 2 {
+		return ""
+	}
+	for zone, ids := range zoneVoters {
+		if len(ids)*2 <= voters {
+			continue
+		}
+		sort.Strings(ids)
+		if len(ids) == voters {
+			return fmt.Sprintf(
+				"all voting controller machines (%s) are in availability zone %q; "+
+					"losing that zone would take down the controller",
+				strings.Join(ids, ", "), zone,
+			)
+		}
+		return fmt.Sprintf(
+			"%d of %d voting controller machines (%s) are in availability zone %q; "+
+				"losing that zone would take down the controller",
+			len(ids), voters, strings.Join(ids, ", "), zone,
+		)
+	}
+	return ""
 }
 
 func haStatus(hasVote bool, wantsVote bool, statusStr string) string {
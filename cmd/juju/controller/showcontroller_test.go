@@ -16,6 +16,7 @@ import (
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/cmd/juju/controller"
 	"github.com/juju/juju/core/model"
+	"github.com/juju/juju/instance"
 	"github.com/juju/juju/jujuclient"
 	"github.com/juju/juju/jujuclient/jujuclienttesting"
 	"github.com/juju/juju/permission"
@@ -219,6 +220,72 @@ aws-test:
 	s.assertShowController(c, "aws-test")
 }
 
+func (s *ShowControllerSuite) TestShowControllerZoneWarning(c *gc.C) {
+	s.createTestClientStore(c)
+	zone := func(name string) *instance.HardwareCharacteristics {
+		return &instance.HardwareCharacteristics{AvailabilityZone: &name}
+	}
+	s.fakeController.machines["ghi"] = []base.Machine{
+		{Id: "0", InstanceId: "id-0", HasVote: true, WantsVote: true, Status: "active", Hardware: zone("az1")},
+		{Id: "1", InstanceId: "id-1", HasVote: true, WantsVote: true, Status: "active", Hardware: zone("az2")},
+		{Id: "2", InstanceId: "id-2", HasVote: true, WantsVote: true, Status: "active", Hardware: zone("az1")},
+	}
+
+	s.expectedOutput = `
+aws-test:
+  details:
+    uuid: this-is-the-aws-test-uuid
+    controller-uuid: this-is-the-aws-test-uuid
+    api-endpoints: [this-is-aws-test-of-many-api-endpoints]
+    ca-cert: this-is-aws-test-ca-cert
+    cloud: aws
+    region: us-east-1
+    agent-version: 999.99.99
+  controller-machines:
+    "0":
+      instance-id: id-0
+      availability-zone: az1
+      ha-status: ha-enabled
+    "1":
+      instance-id: id-1
+      availability-zone: az2
+      ha-status: ha-enabled
+    "2":
+      instance-id: id-2
+      availability-zone: az1
+      ha-status: ha-enabled
+  models:
+    controller:
+      uuid: ghi
+      model-uuid: ghi
+      machine-count: 2
+      core-count: 4
+  current-model: admin/controller
+  account:
+    user: admin
+    access: superuser
+  warnings:
+  - 2 of 3 voting controller machines (0, 2) are in availability zone "az1"; losing
+    that zone would take down the controller
+`[1:]
+	s.assertShowController(c, "aws-test")
+}
+
+func (s *ShowControllerSuite) TestShowControllerNoZoneWarningWhenSpread(c *gc.C) {
+	s.createTestClientStore(c)
+	zone := func(name string) *instance.HardwareCharacteristics {
+		return &instance.HardwareCharacteristics{AvailabilityZone: &name}
+	}
+	s.fakeController.machines["ghi"] = []base.Machine{
+		{Id: "0", InstanceId: "id-0", HasVote: true, WantsVote: true, Status: "active", Hardware: zone("az1")},
+		{Id: "1", InstanceId: "id-1", HasVote: true, WantsVote: true, Status: "active", Hardware: zone("az2")},
+		{Id: "2", InstanceId: "id-2", HasVote: true, WantsVote: true, Status: "active", Hardware: zone("az3")},
+	}
+	context, err := s.runShowController(c, "aws-test")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(context), gc.Not(jc.Contains), "warnings:")
+}
+
 func (s *ShowControllerSuite) TestShowSomeControllerMoreInStore(c *gc.C) {
 	s.createTestClientStore(c)
 	s.expectedOutput = `
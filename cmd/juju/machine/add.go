@@ -57,6 +57,29 @@ and bringing it under Juju's management; currently this requires that the
 machine be running Ubuntu, that it be accessible via SSH, and be running on
 the same network as the API server.
 
+Many machines can be manually provisioned over SSH at once by listing them in
+an inventory file, given with --inventory. Up to --parallel machines are
+provisioned at a time, and a summary of the outcome for each is shown. Machines
+that have already been provisioned are skipped, so the command may be run again
+with the same inventory after fixing any failures. As the machines are
+provisioned concurrently, there can be no prompting for passwords: each machine
+must accept the SSH key given for it, and its user must be able to use sudo
+without a password. The inventory is a YAML file such as:
+
+    defaults:
+      user: admin
+      key: ~/.ssh/lab.pem
+      series: bionic
+    hosts:
+      - host: 10.10.0.3
+      - host: 10.10.0.4
+        series: xenial
+      - host: root@10.10.0.5
+
+Each host may override any of the defaults: the user to log in as to set up
+the machine, the SSH private key to log in with, and the series that the
+machine must be running.
+
 It is possible to override or augment constraints by passing provider-specific
 "placement directives" as an argument; these give the provider additional
 information about how to allocate the machine. For example, one can direct the
@@ -71,6 +94,7 @@ Examples:
    juju add-machine --constraints mem=8G (starts a machine with at least 8GB RAM)
    juju add-machine ssh:user@10.10.0.3   (manually provisions machine with ssh)
    juju add-machine winrm:user@10.10.0.3 (manually provisions machine with winrm)
+   juju add-machine --inventory hosts.yaml (manually provisions the machines in hosts.yaml)
    juju add-machine zone=us-east-1a      (start a machine in zone us-east-1a on AWS)
    juju add-machine maas2.name           (acquire machine maas2.name on MAAS)
 
@@ -114,12 +138,18 @@ type addCommand struct {
 	NumMachines int
 	// Disks describes disks that are to be attached to the machine.
 	Disks []storage.Constraints
+	// Inventory is the path of a file listing hosts to manually provision.
+	Inventory string
+	// Parallel is the number of hosts in the inventory to provision at once.
+	Parallel int
+	// parallelSet records whether --parallel was given.
+	parallelSet bool
 }
 
 func (c *addCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "add-machine",
-		Args:    "[<container>:machine | <container> | ssh:[user@]host | winrm:[user@]host | placement]",
+		Args:    "[<container>:machine | <container> | ssh:[user@]host | winrm:[user@]host | placement | --inventory <file>]",
 		Purpose: "Start a new, empty machine and optionally a container, or add a container to a machine.",
 		Doc:     addMachineDoc,
 	}
@@ -131,12 +161,21 @@ func (c *addCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.IntVar(&c.NumMachines, "n", 1, "The number of machines to add")
 	f.StringVar(&c.ConstraintsStr, "constraints", "", "Additional machine constraints")
 	f.Var(disksFlag{&c.Disks}, "disks", "Constraints for disks to attach to the machine")
+	f.StringVar(&c.Inventory, "inventory", "", "A YAML file listing hosts to manually provision over SSH")
+	c.Parallel = defaultInventoryParallel
+	f.Var(parallelFlag{&c.Parallel, &c.parallelSet}, "parallel", "The number of hosts in the inventory to provision at once")
 }
 
 func (c *addCommand) Init(args []string) error {
 	if c.Constraints.Container != nil {
 		return errors.Errorf("container constraint %q not allowed when adding a machine", *c.Constraints.Container)
 	}
+	if c.Inventory != "" {
+		return c.initInventory(args)
+	}
+	if c.parallelSet {
+		return errors.New("cannot use --parallel without --inventory")
+	}
 	placement, err := cmd.ZeroOrOneArgs(args)
 	if err != nil {
 		return err
@@ -155,6 +194,24 @@ func (c *addCommand) Init(args []string) error {
 	return nil
 }
 
+// initInventory checks that the arguments are compatible with
+// provisioning the hosts in an inventory.
+func (c *addCommand) initInventory(args []string) error {
+	if len(args) > 0 {
+		return errors.New("cannot specify a placement with --inventory")
+	}
+	if c.NumMachines != 1 {
+		return errors.New("cannot use -n with --inventory")
+	}
+	if c.ConstraintsStr != "" || len(c.Disks) > 0 {
+		return errors.New("cannot use --constraints or --disks with --inventory")
+	}
+	if c.Parallel < 1 {
+		return errors.New("--parallel must be at least 1")
+	}
+	return nil
+}
+
 type AddMachineAPI interface {
 	AddMachines([]params.AddMachineParams) ([]params.AddMachinesResult, error)
 	Close() error
@@ -260,6 +317,10 @@ func (c *addCommand) Run(ctx *cmd.Context) error {
 		return errors.Trace(err)
 	}
 
+	if c.Inventory != "" {
+		return c.provisionInventory(client, config, ctx)
+	}
+
 	if c.Placement != nil {
 		err := c.tryManualProvision(client, config, ctx)
 		if err != errNonManualScope {
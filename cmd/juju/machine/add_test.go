@@ -90,6 +90,30 @@ func (s *AddMachineSuite) TestInit(c *gc.C) {
 			args:      []string{"something:special"},
 			count:     1,
 			placement: "something:special",
+		}, {
+			args:  []string{"--inventory", "hosts.yaml"},
+			count: 1,
+		}, {
+			args:        []string{"--inventory", "hosts.yaml", "ssh:10.10.0.3"},
+			errorString: "cannot specify a placement with --inventory",
+		}, {
+			args:        []string{"--inventory", "hosts.yaml", "-n", "2"},
+			errorString: "cannot use -n with --inventory",
+		}, {
+			args:        []string{"--inventory", "hosts.yaml", "--constraints", "mem=8G"},
+			errorString: "cannot use --constraints or --disks with --inventory",
+		}, {
+			args:        []string{"--inventory", "hosts.yaml", "--parallel", "0"},
+			errorString: "--parallel must be at least 1",
+		}, {
+			args:  []string{"--inventory", "hosts.yaml", "--parallel", "2"},
+			count: 1,
+		}, {
+			args:        []string{"--parallel", "2"},
+			errorString: "cannot use --parallel without --inventory",
+		}, {
+			args:        []string{"ssh:10.10.0.3", "--parallel", "2"},
+			errorString: "cannot use --parallel without --inventory",
 		},
 	} {
 		c.Logf("test %d", i)
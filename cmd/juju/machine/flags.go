@@ -5,6 +5,7 @@ package machine
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/juju/errors"
@@ -36,3 +37,26 @@ func (f disksFlag) String() string {
 	}
 	return strings.Join(strs, " ")
 }
+
+// parallelFlag is the value of --parallel, which records whether
+// the flag was given so that it can be rejected without --inventory.
+type parallelFlag struct {
+	parallel *int
+	set      *bool
+}
+
+// Set implements gnuflag.Value.Set.
+func (f parallelFlag) Set(s string) error {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return errors.Errorf("expected integer, got %q", s)
+	}
+	*f.parallel = n
+	*f.set = true
+	return nil
+}
+
+// String implements gnuflag.Value.String.
+func (f parallelFlag) String() string {
+	return strconv.Itoa(*f.parallel)
+}
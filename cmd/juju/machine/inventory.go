@@ -0,0 +1,211 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"strings"
+	"sync"
+
+	"github.com/juju/cmd"
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	"gopkg.in/yaml.v2"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/common"
+	"github.com/juju/juju/cmd/output"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/manual"
+)
+
+// defaultInventoryParallel is the number of hosts in an inventory that
+// are provisioned at the same time, unless --parallel says otherwise.
+const defaultInventoryParallel = 5
+
+// inventory describes the hosts to manually provision with
+// add-machine --inventory.
+type inventory struct {
+	// Defaults holds the settings used for any host that
+	// doesn't specify its own.
+	Defaults inventoryHost `yaml:"defaults"`
+
+	// Hosts holds the hosts to provision.
+	Hosts []inventoryHost `yaml:"hosts"`
+}
+
+// inventoryHost describes a host to provision, and how.
+type inventoryHost struct {
+	// Host is the address of the host, optionally prefixed
+	// with the user to log in as and "@".
+	Host string `yaml:"host,omitempty"`
+
+	// User is the user to log in as to initialise the host.
+	User string `yaml:"user,omitempty"`
+
+	// Key is the path of the private key with which to log in.
+	Key string `yaml:"key,omitempty"`
+
+	// Series is the series the host is expected to be running.
+	Series string `yaml:"series,omitempty"`
+}
+
+// readInventory reads the inventory in the named file, and returns its
+// hosts with the inventory's defaults, and then defaultSeries, applied.
+func readInventory(path, defaultSeries string) ([]inventoryHost, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Annotate(err, "reading inventory")
+	}
+	var inv inventory
+	if err := yaml.UnmarshalStrict(data, &inv); err != nil {
+		return nil, errors.Annotatef(err, "parsing inventory %q", path)
+	}
+	if inv.Defaults.Host != "" {
+		return nil, errors.New("inventory defaults cannot specify a host")
+	}
+	if len(inv.Hosts) == 0 {
+		return nil, errors.Errorf("inventory %q has no hosts", path)
+	}
+	if inv.Defaults.Series == "" {
+		inv.Defaults.Series = defaultSeries
+	}
+	seen := set.NewStrings()
+	hosts := make([]inventoryHost, len(inv.Hosts))
+	for i, h := range inv.Hosts {
+		if user, host := splitUserHost(h.Host); user != "" {
+			if h.User != "" {
+				return nil, errors.Errorf("host %q: user specified twice", h.Host)
+			}
+			h.Host, h.User = host, user
+		}
+		if h.Host == "" {
+			return nil, errors.Errorf("host %d: no address specified", i+1)
+		}
+		if seen.Contains(h.Host) {
+			return nil, errors.Errorf("host %q listed more than once", h.Host)
+		}
+		seen.Add(h.Host)
+		if h.User == "" {
+			h.User = inv.Defaults.User
+		}
+		if h.Key == "" {
+			h.Key = inv.Defaults.Key
+		}
+		if h.Key != "" {
+			if h.Key, err = utils.NormalizePath(h.Key); err != nil {
+				return nil, errors.Annotatef(err, "host %q: key", h.Host)
+			}
+		}
+		if h.Series == "" {
+			h.Series = inv.Defaults.Series
+		}
+		hosts[i] = h
+	}
+	return hosts, nil
+}
+
+// inventoryResult records the outcome of provisioning a host.
+type inventoryResult struct {
+	host      string
+	machineId string
+	err       error
+}
+
+// provisionInventory provisions the hosts listed in the command's
+// inventory file, at most c.Parallel of them at a time, and writes a
+// summary of the outcome for each. Hosts that have already been
+// provisioned are skipped, so that the command can be run again after
+// fixing any failures.
+func (c *addCommand) provisionInventory(client AddMachineAPI, config *config.Config, ctx *cmd.Context) error {
+	hosts, err := readInventory(ctx.AbsPath(c.Inventory), c.Series)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	authKeys, err := common.ReadAuthorizedKeys(ctx, "")
+	if err != nil {
+		return errors.Annotate(err, "cannot read authorized-keys")
+	}
+	updateBehavior := &params.UpdateBehavior{
+		EnableOSRefreshUpdate: config.EnableOSRefreshUpdate(),
+		EnableOSUpgrade:       config.EnableOSUpgrade(),
+	}
+
+	results := make([]inventoryResult, len(hosts))
+	limit := make(chan struct{}, c.Parallel)
+	var wg sync.WaitGroup
+	for i, h := range hosts {
+		wg.Add(1)
+		go func(i int, h inventoryHost) {
+			defer wg.Done()
+			limit <- struct{}{}
+			defer func() { <-limit }()
+
+			ctx.Infof("provisioning %s", h.Host)
+			// Hosts are provisioned concurrently, so there
+			// can be no prompting for passwords, and the
+			// progress output is only logged.
+			var progress bytes.Buffer
+			machineId, err := sshProvisioner(manual.ProvisionMachineArgs{
+				Host:           h.Host,
+				User:           h.User,
+				IdentityFile:   h.Key,
+				NonInteractive: true,
+				Series:         h.Series,
+				Client:         client,
+				Stdin:          strings.NewReader(""),
+				Stdout:         &progress,
+				Stderr:         &progress,
+				AuthorizedKeys: authKeys,
+				UpdateBehavior: updateBehavior,
+			})
+			if err != nil && err != manual.ErrProvisioned {
+				logger.Debugf("provisioning %s failed:\n%s", h.Host, progress.String())
+			}
+			results[i] = inventoryResult{
+				host:      h.Host,
+				machineId: machineId,
+				err:       err,
+			}
+		}(i, h)
+	}
+	wg.Wait()
+
+	writeInventoryResults(ctx.Stdout, results)
+	var failed int
+	for _, r := range results {
+		if r.err != nil && r.err != manual.ErrProvisioned {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return errors.Errorf("failed to provision %d of %d hosts", failed, len(results))
+	}
+	return nil
+}
+
+// writeInventoryResults writes a table summarising the outcome of
+// provisioning each host in an inventory.
+func writeInventoryResults(writer io.Writer, results []inventoryResult) {
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{tw}
+	w.Println("Host", "Machine", "Status", "Message")
+	for _, r := range results {
+		machineId, status, message := r.machineId, "added", ""
+		switch {
+		case r.err == manual.ErrProvisioned:
+			status, message = "skipped", "already provisioned"
+		case r.err != nil:
+			status, message = "failed", r.err.Error()
+		}
+		if machineId == "" {
+			machineId = "-"
+		}
+		w.Println(r.host, machineId, status, message)
+	}
+	tw.Flush()
+}
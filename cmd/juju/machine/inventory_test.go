@@ -0,0 +1,154 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/machine"
+	"github.com/juju/juju/environs/manual"
+	"github.com/juju/juju/testing"
+)
+
+type InventorySuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	fakeAddMachine     *fakeAddMachineAPI
+	fakeMachineManager *fakeMachineManagerAPI
+
+	mu   sync.Mutex
+	args []manual.ProvisionMachineArgs
+}
+
+var _ = gc.Suite(&InventorySuite{})
+
+func (s *InventorySuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.fakeAddMachine = &fakeAddMachineAPI{}
+	s.fakeMachineManager = &fakeMachineManagerAPI{}
+	s.args = nil
+	s.PatchValue(machine.SSHProvisioner, func(args manual.ProvisionMachineArgs) (string, error) {
+		s.mu.Lock()
+		s.args = append(s.args, args)
+		s.mu.Unlock()
+		switch args.Host {
+		case "10.0.0.1":
+			return "42", nil
+		case "10.0.0.2":
+			return "", manual.ErrProvisioned
+		}
+		return "", errors.New("boom")
+	})
+}
+
+func (s *InventorySuite) writeInventory(c *gc.C, content string) string {
+	path := filepath.Join(c.MkDir(), "hosts.yaml")
+	err := ioutil.WriteFile(path, []byte(content), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	return path
+}
+
+func (s *InventorySuite) run(c *gc.C, args ...string) (string, error) {
+	add, _ := machine.NewAddCommandForTest(s.fakeAddMachine, s.fakeAddMachine, s.fakeMachineManager)
+	ctx, err := cmdtesting.RunCommand(c, add, args...)
+	return cmdtesting.Stdout(ctx), err
+}
+
+func (s *InventorySuite) TestProvisionInventory(c *gc.C) {
+	path := s.writeInventory(c, `
+defaults:
+  user: admin
+  key: ~/.ssh/lab.pem
+hosts:
+  - host: 10.0.0.1
+  - host: root@10.0.0.2
+    series: xenial
+  - host: 10.0.0.3
+    key: /keys/other.pem
+`)
+	stdout, err := s.run(c, "--inventory", path, "--series", "bionic")
+	c.Assert(err, gc.ErrorMatches, "failed to provision 1 of 3 hosts")
+	c.Assert(stdout, gc.Equals, ""+
+		"Host      Machine  Status   Message\n"+
+		"10.0.0.1  42       added    \n"+
+		"10.0.0.2  -        skipped  already provisioned\n"+
+		"10.0.0.3  -        failed   boom\n",
+	)
+
+	defaultKey, err := utils.NormalizePath("~/.ssh/lab.pem")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.args, gc.HasLen, 3)
+	sort.Slice(s.args, func(i, j int) bool {
+		return s.args[i].Host < s.args[j].Host
+	})
+	for i, expect := range []struct {
+		host, user, key, series string
+	}{
+		{"10.0.0.1", "admin", defaultKey, "bionic"},
+		{"10.0.0.2", "root", defaultKey, "xenial"},
+		{"10.0.0.3", "admin", "/keys/other.pem", "bionic"},
+	} {
+		args := s.args[i]
+		c.Check(args.Host, gc.Equals, expect.host)
+		c.Check(args.User, gc.Equals, expect.user)
+		c.Check(args.IdentityFile, gc.Equals, expect.key)
+		c.Check(args.NonInteractive, jc.IsTrue)
+		c.Check(args.Series, gc.Equals, expect.series)
+		c.Check(args.UpdateBehavior, gc.NotNil)
+	}
+}
+
+func (s *InventorySuite) TestProvisionInventorySuccess(c *gc.C) {
+	path := s.writeInventory(c, `
+hosts:
+  - host: 10.0.0.1
+  - host: 10.0.0.2
+`)
+	stdout, err := s.run(c, "--inventory", path, "--parallel", "1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stdout, gc.Equals, ""+
+		"Host      Machine  Status   Message\n"+
+		"10.0.0.1  42       added    \n"+
+		"10.0.0.2  -        skipped  already provisioned\n",
+	)
+}
+
+func (s *InventorySuite) TestProvisionInventoryInvalid(c *gc.C) {
+	for i, test := range []struct {
+		content     string
+		errorString string
+	}{{
+		content:     "hosts: []\n",
+		errorString: `inventory ".*" has no hosts`,
+	}, {
+		content:     "defaults:\n  host: 10.0.0.1\nhosts:\n  - host: 10.0.0.2\n",
+		errorString: "inventory defaults cannot specify a host",
+	}, {
+		content:     "hosts:\n  - user: admin\n",
+		errorString: "host 1: no address specified",
+	}, {
+		content:     "hosts:\n  - host: 10.0.0.1\n  - host: admin@10.0.0.1\n",
+		errorString: `host "10.0.0.1" listed more than once`,
+	}, {
+		content:     "hosts:\n  - host: admin@10.0.0.1\n    user: root\n",
+		errorString: `host "admin@10.0.0.1": user specified twice`,
+	}, {
+		content:     "hosts:\n  - host: 10.0.0.1\n    password: secret\n",
+		errorString: `(?s)parsing inventory ".*": .*field password not found.*`,
+	}} {
+		c.Logf("test %d", i)
+		path := s.writeInventory(c, test.content)
+		_, err := s.run(c, "--inventory", path)
+		c.Check(err, gc.ErrorMatches, test.errorString)
+	}
+	c.Assert(s.args, gc.HasLen, 0)
+}
@@ -0,0 +1,172 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model
+
+import (
+	"io"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+
+	"github.com/juju/juju/api/modelconsistency"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+)
+
+const checkModelHelpDoc = `
+Checks the controller's documents for the model for inconsistencies:
+reference counts and unit counts that don't match the references, settings
+left behind by removed applications and relations, relations and units that
+refer to removed entities, and cleanups that have failed or have not run
+within ten minutes.
+
+Nothing is changed unless --repair is given. With --repair, the inconsistencies
+that can be fixed safely are repaired: counts are set to match the references
+found, and orphaned settings are removed. Other inconsistencies are reported
+only. Checking a model requires admin access to it; repairing it requires
+superuser access to the controller.
+
+Examples:
+
+    juju check-model
+    juju check-model -m mymodel --format yaml
+    juju check-model --repair
+
+See also:
+    dump-model
+    show-model
+`
+
+// NewCheckModelCommand returns a fully constructed check-model command.
+func NewCheckModelCommand() cmd.Command {
+	return modelcmd.Wrap(&checkModelCommand{})
+}
+
+type checkModelCommand struct {
+	modelcmd.ModelCommandBase
+	out cmd.Output
+	api CheckModelAPI
+
+	repair bool
+}
+
+// CheckModelAPI specifies the used function calls of the
+// ModelConsistency facade.
+type CheckModelAPI interface {
+	Close() error
+	Check() ([]params.ModelInconsistency, error)
+	Repair() ([]params.ModelInconsistency, error)
+}
+
+// Info implements Command.
+func (c *checkModelCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "check-model",
+		Purpose: "Checks a model's documents for inconsistencies.",
+		Doc:     checkModelHelpDoc,
+	}
+}
+
+// SetFlags implements Command.
+func (c *checkModelCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatInconsistenciesTabular,
+	})
+	f.BoolVar(&c.repair, "repair", false, "Repair the inconsistencies that can be repaired safely")
+}
+
+// Init implements Command.
+func (c *checkModelCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+func (c *checkModelCommand) getAPI() (CheckModelAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	client := modelconsistency.NewClient(root)
+	if client.BestAPIVersion() < 1 {
+		client.Close()
+		return nil, errors.New("checking models is not supported by this controller")
+	}
+	return client, nil
+}
+
+// Run implements Command.
+func (c *checkModelCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	var results []params.ModelInconsistency
+	if c.repair {
+		results, err = client.Repair()
+	} else {
+		results, err = client.Check()
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(results) == 0 && c.out.Name() == "tabular" {
+		ctx.Infof("No inconsistencies found.")
+		return nil
+	}
+
+	out := make([]inconsistency, len(results))
+	for i, result := range results {
+		out[i] = inconsistency{
+			Check:      result.Check,
+			Collection: result.Collection,
+			Id:         result.Id,
+			Message:    result.Message,
+			Repairable: result.Repairable,
+			Repaired:   result.Repaired,
+		}
+	}
+	return c.out.Write(ctx, out)
+}
+
+// inconsistency is the serialization of a model inconsistency for
+// check-model.
+type inconsistency struct {
+	Check      string `yaml:"check" json:"check"`
+	Collection string `yaml:"collection" json:"collection"`
+	Id         string `yaml:"id" json:"id"`
+	Message    string `yaml:"message" json:"message"`
+	Repairable bool   `yaml:"repairable" json:"repairable"`
+	Repaired   bool   `yaml:"repaired,omitempty" json:"repaired,omitempty"`
+}
+
+func formatInconsistenciesTabular(writer io.Writer, value interface{}) error {
+	results, ok := value.([]inconsistency)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", results, value)
+	}
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{tw}
+	w.Println("Check", "Collection", "ID", "Repair", "Message")
+	for _, r := range results {
+		repair := "-"
+		switch {
+		case r.Repaired:
+			repair = "repaired"
+		case r.Repairable:
+			repair = "available"
+		}
+		w.Println(r.Check, r.Collection, r.Id, repair, r.Message)
+	}
+	tw.Flush()
+	return nil
+}
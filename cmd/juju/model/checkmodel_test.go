@@ -0,0 +1,135 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model_test
+
+import (
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/model"
+	coremodel "github.com/juju/juju/core/model"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/testing"
+)
+
+type CheckModelCommandSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	fake  fakeCheckModelClient
+	store *jujuclient.MemStore
+}
+
+var _ = gc.Suite(&CheckModelCommandSuite{})
+
+type fakeCheckModelClient struct {
+	gitjujutesting.Stub
+	results []params.ModelInconsistency
+}
+
+func (f *fakeCheckModelClient) Close() error {
+	f.MethodCall(f, "Close")
+	return f.NextErr()
+}
+
+func (f *fakeCheckModelClient) Check() ([]params.ModelInconsistency, error) {
+	f.MethodCall(f, "Check")
+	return f.results, f.NextErr()
+}
+
+func (f *fakeCheckModelClient) Repair() ([]params.ModelInconsistency, error) {
+	f.MethodCall(f, "Repair")
+	results := make([]params.ModelInconsistency, len(f.results))
+	for i, result := range f.results {
+		result.Repaired = result.Repairable
+		results[i] = result
+	}
+	return results, f.NextErr()
+}
+
+func (s *CheckModelCommandSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.fake = fakeCheckModelClient{
+		results: []params.ModelInconsistency{{
+			Check:      "unit-count",
+			Collection: "applications",
+			Id:         "mysql",
+			Message:    `application "mysql" has unit count 5 but 1 units`,
+			Repairable: true,
+		}, {
+			Check:      "unit-references",
+			Collection: "units",
+			Id:         "mysql/0",
+			Message:    `unit "mysql/0" refers to missing machine "42"`,
+		}},
+	}
+	s.store = jujuclient.NewMemStore()
+	s.store.CurrentControllerName = "testing"
+	s.store.Controllers["testing"] = jujuclient.ControllerDetails{}
+	s.store.Accounts["testing"] = jujuclient.AccountDetails{
+		User: "admin",
+	}
+	err := s.store.UpdateModel("testing", "admin/mymodel", jujuclient.ModelDetails{
+		ModelUUID: testing.ModelTag.Id(),
+		ModelType: coremodel.IAAS,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.store.Models["testing"].CurrentModel = "admin/mymodel"
+}
+
+func (s *CheckModelCommandSuite) TestCheck(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, model.NewCheckModelCommandForTest(&s.fake, s.store))
+	c.Assert(err, jc.ErrorIsNil)
+	s.fake.CheckCallNames(c, "Check", "Close")
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+Check            Collection    ID       Repair     Message
+unit-count       applications  mysql    available  application "mysql" has unit count 5 but 1 units
+unit-references  units         mysql/0  -          unit "mysql/0" refers to missing machine "42"
+`[1:])
+}
+
+func (s *CheckModelCommandSuite) TestCheckYAML(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, model.NewCheckModelCommandForTest(&s.fake, s.store), "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+- check: unit-count
+  collection: applications
+  id: mysql
+  message: application "mysql" has unit count 5 but 1 units
+  repairable: true
+- check: unit-references
+  collection: units
+  id: mysql/0
+  message: unit "mysql/0" refers to missing machine "42"
+  repairable: false
+`[1:])
+}
+
+func (s *CheckModelCommandSuite) TestCheckNoInconsistencies(c *gc.C) {
+	s.fake.results = nil
+	ctx, err := cmdtesting.RunCommand(c, model.NewCheckModelCommandForTest(&s.fake, s.store))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "")
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "No inconsistencies found.\n")
+}
+
+func (s *CheckModelCommandSuite) TestRepair(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, model.NewCheckModelCommandForTest(&s.fake, s.store), "--repair")
+	c.Assert(err, jc.ErrorIsNil)
+	s.fake.CheckCallNames(c, "Repair", "Close")
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+Check            Collection    ID       Repair    Message
+unit-count       applications  mysql    repaired  application "mysql" has unit count 5 but 1 units
+unit-references  units         mysql/0  -         unit "mysql/0" refers to missing machine "42"
+`[1:])
+}
+
+func (s *CheckModelCommandSuite) TestCheckError(c *gc.C) {
+	s.fake.SetErrors(errors.New("boom"))
+	_, err := cmdtesting.RunCommand(c, model.NewCheckModelCommandForTest(&s.fake, s.store))
+	c.Assert(err, gc.ErrorMatches, "boom")
+	s.fake.CheckCallNames(c, "Check", "Close")
+}
@@ -0,0 +1,261 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model
+
+import (
+	"io"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+
+	"github.com/juju/juju/api/cleanups"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+)
+
+// CleanupsAPI specifies the used function calls of the Cleanups facade.
+type CleanupsAPI interface {
+	Close() error
+	ListCleanups() ([]params.ModelCleanup, error)
+	ForceCleanups(ids ...string) error
+	CancelCleanups(ids ...string) error
+}
+
+// cleanupsCommandBase holds what is common to the commands that list,
+// force and cancel cleanups.
+type cleanupsCommandBase struct {
+	modelcmd.ModelCommandBase
+	api CleanupsAPI
+}
+
+func (c *cleanupsCommandBase) getAPI() (CleanupsAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	client := cleanups.NewClient(root)
+	if client.BestAPIVersion() < 1 {
+		client.Close()
+		return nil, errors.New("listing cleanups is not supported by this controller")
+	}
+	return client, nil
+}
+
+const listCleanupsHelpDoc = `
+Lists the cleanups queued in a model. When entities such as applications,
+units and machines are removed, the controller queues cleanups that remove
+whatever depends on them. A cleanup that fails is retried until it succeeds;
+the number of times it has failed and the error it last failed with are shown,
+so that cleanups that are stuck can be found.
+
+A stuck cleanup can be run forcefully with force-cleanup, or removed without
+running it with cancel-cleanup.
+
+Examples:
+
+    juju cleanups
+    juju cleanups -m mymodel --format yaml
+
+See also:
+    force-cleanup
+    cancel-cleanup
+    check-model
+`
+
+// NewListCleanupsCommand returns a fully constructed cleanups command.
+func NewListCleanupsCommand() cmd.Command {
+	return modelcmd.Wrap(&listCleanupsCommand{})
+}
+
+type listCleanupsCommand struct {
+	cleanupsCommandBase
+	out cmd.Output
+}
+
+// Info implements Command.
+func (c *listCleanupsCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "cleanups",
+		Purpose: "Lists the cleanups queued in a model.",
+		Doc:     listCleanupsHelpDoc,
+		Aliases: []string{"list-cleanups"},
+	}
+}
+
+// SetFlags implements Command.
+func (c *listCleanupsCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatCleanupsTabular,
+	})
+}
+
+// Init implements Command.
+func (c *listCleanupsCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements Command.
+func (c *listCleanupsCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	results, err := client.ListCleanups()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(results) == 0 && c.out.Name() == "tabular" {
+		ctx.Infof("No cleanups are queued.")
+		return nil
+	}
+
+	out := make([]cleanupInfo, len(results))
+	for i, result := range results {
+		out[i] = cleanupInfo{
+			Id:          result.Id,
+			Kind:        result.Kind,
+			Target:      result.Target,
+			Attempts:    result.Attempts,
+			LastError:   result.LastError,
+			LastAttempt: result.LastAttempt,
+		}
+	}
+	return c.out.Write(ctx, out)
+}
+
+// cleanupInfo is the serialization of a queued cleanup for cleanups.
+type cleanupInfo struct {
+	Id          string     `yaml:"id" json:"id"`
+	Kind        string     `yaml:"kind" json:"kind"`
+	Target      string     `yaml:"target,omitempty" json:"target,omitempty"`
+	Attempts    int        `yaml:"attempts" json:"attempts"`
+	LastError   string     `yaml:"last-error,omitempty" json:"last-error,omitempty"`
+	LastAttempt *time.Time `yaml:"last-attempt,omitempty" json:"last-attempt,omitempty"`
+}
+
+func formatCleanupsTabular(writer io.Writer, value interface{}) error {
+	results, ok := value.([]cleanupInfo)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", results, value)
+	}
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{tw}
+	w.Println("ID", "Kind", "Target", "Attempts", "Last error")
+	for _, r := range results {
+		target := r.Target
+		if target == "" {
+			target = "-"
+		}
+		w.Println(r.Id, r.Kind, target, r.Attempts, r.LastError)
+	}
+	tw.Flush()
+	return nil
+}
+
+const forceCleanupHelpDoc = `
+Runs the specified cleanups immediately, as listed by the cleanups command.
+Where a cleanup can be run forcefully it is: the cleanup for a dying machine
+removes the machine's units and storage attachments as if the machine had been
+removed with --force. A cleanup that succeeds is removed; one that fails is
+left queued, and the error it failed with is shown.
+
+Forcing cleanups requires superuser access to the controller.
+
+Examples:
+
+    juju force-cleanup 5c9d1e8b2a6f4c0a8e7d3b1f
+
+See also:
+    cleanups
+    cancel-cleanup
+    remove-machine
+`
+
+// NewForceCleanupCommand returns a fully constructed force-cleanup command.
+func NewForceCleanupCommand() cmd.Command {
+	return modelcmd.Wrap(&changeCleanupsCommand{
+		info: &cmd.Info{
+			Name:    "force-cleanup",
+			Args:    "<cleanup id> ...",
+			Purpose: "Runs queued cleanups immediately and forcefully.",
+			Doc:     forceCleanupHelpDoc,
+		},
+		change: CleanupsAPI.ForceCleanups,
+	})
+}
+
+const cancelCleanupHelpDoc = `
+Removes the specified cleanups, as listed by the cleanups command, without
+running them. Whatever a cancelled cleanup would have done is left undone:
+entities it would have removed remain, and may need to be removed by hand.
+
+Cancelling cleanups requires superuser access to the controller.
+
+Examples:
+
+    juju cancel-cleanup 5c9d1e8b2a6f4c0a8e7d3b1f
+
+See also:
+    cleanups
+    force-cleanup
+`
+
+// NewCancelCleanupCommand returns a fully constructed cancel-cleanup
+// command.
+func NewCancelCleanupCommand() cmd.Command {
+	return modelcmd.Wrap(&changeCleanupsCommand{
+		info: &cmd.Info{
+			Name:    "cancel-cleanup",
+			Args:    "<cleanup id> ...",
+			Purpose: "Removes queued cleanups without running them.",
+			Doc:     cancelCleanupHelpDoc,
+		},
+		change: CleanupsAPI.CancelCleanups,
+	})
+}
+
+// changeCleanupsCommand forces or cancels cleanups, as determined by
+// its info and change function.
+type changeCleanupsCommand struct {
+	cleanupsCommandBase
+	info   *cmd.Info
+	change func(CleanupsAPI, ...string) error
+
+	ids []string
+}
+
+// Info implements Command.
+func (c *changeCleanupsCommand) Info() *cmd.Info {
+	return c.info
+}
+
+// Init implements Command.
+func (c *changeCleanupsCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no cleanup ids specified")
+	}
+	c.ids = args
+	return nil
+}
+
+// Run implements Command.
+func (c *changeCleanupsCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	return errors.Trace(c.change(client, c.ids...))
+}
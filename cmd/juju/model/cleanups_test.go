@@ -0,0 +1,151 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model_test
+
+import (
+	"time"
+
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/model"
+	coremodel "github.com/juju/juju/core/model"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/testing"
+)
+
+type CleanupsCommandSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	fake  fakeCleanupsClient
+	store *jujuclient.MemStore
+}
+
+var _ = gc.Suite(&CleanupsCommandSuite{})
+
+type fakeCleanupsClient struct {
+	gitjujutesting.Stub
+	cleanups []params.ModelCleanup
+}
+
+func (f *fakeCleanupsClient) Close() error {
+	f.MethodCall(f, "Close")
+	return f.NextErr()
+}
+
+func (f *fakeCleanupsClient) ListCleanups() ([]params.ModelCleanup, error) {
+	f.MethodCall(f, "ListCleanups")
+	return f.cleanups, f.NextErr()
+}
+
+func (f *fakeCleanupsClient) ForceCleanups(ids ...string) error {
+	f.MethodCall(f, "ForceCleanups", ids)
+	return f.NextErr()
+}
+
+func (f *fakeCleanupsClient) CancelCleanups(ids ...string) error {
+	f.MethodCall(f, "CancelCleanups", ids)
+	return f.NextErr()
+}
+
+func (s *CleanupsCommandSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	lastAttempt := time.Date(2019, 3, 14, 9, 26, 53, 0, time.UTC)
+	s.fake = fakeCleanupsClient{
+		cleanups: []params.ModelCleanup{{
+			Id:          "5c9d1e8b",
+			Kind:        "dyingMachine",
+			Target:      "3",
+			Attempts:    12,
+			LastError:   "machine 3 has attachments [volume-3-0]",
+			LastAttempt: &lastAttempt,
+		}, {
+			Id:        "5c9d1e8c",
+			Kind:      "applications",
+			Attempts:  1,
+			LastError: "model not dying",
+		}},
+	}
+	s.store = jujuclient.NewMemStore()
+	s.store.CurrentControllerName = "testing"
+	s.store.Controllers["testing"] = jujuclient.ControllerDetails{}
+	s.store.Accounts["testing"] = jujuclient.AccountDetails{
+		User: "admin",
+	}
+	err := s.store.UpdateModel("testing", "admin/mymodel", jujuclient.ModelDetails{
+		ModelUUID: testing.ModelTag.Id(),
+		ModelType: coremodel.IAAS,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.store.Models["testing"].CurrentModel = "admin/mymodel"
+}
+
+func (s *CleanupsCommandSuite) TestListCleanups(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, model.NewListCleanupsCommandForTest(&s.fake, s.store))
+	c.Assert(err, jc.ErrorIsNil)
+	s.fake.CheckCallNames(c, "ListCleanups", "Close")
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+ID        Kind          Target  Attempts  Last error
+5c9d1e8b  dyingMachine  3       12        machine 3 has attachments [volume-3-0]
+5c9d1e8c  applications  -       1         model not dying
+`[1:])
+}
+
+func (s *CleanupsCommandSuite) TestListCleanupsYAML(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, model.NewListCleanupsCommandForTest(&s.fake, s.store), "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+- id: 5c9d1e8b
+  kind: dyingMachine
+  target: "3"
+  attempts: 12
+  last-error: machine 3 has attachments [volume-3-0]
+  last-attempt: 2019-03-14T09:26:53Z
+- id: 5c9d1e8c
+  kind: applications
+  attempts: 1
+  last-error: model not dying
+`[1:])
+}
+
+func (s *CleanupsCommandSuite) TestListCleanupsNone(c *gc.C) {
+	s.fake.cleanups = nil
+	ctx, err := cmdtesting.RunCommand(c, model.NewListCleanupsCommandForTest(&s.fake, s.store))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "")
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "No cleanups are queued.\n")
+}
+
+func (s *CleanupsCommandSuite) TestForceCleanup(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, model.NewForceCleanupCommandForTest(&s.fake, s.store), "5c9d1e8b", "5c9d1e8c")
+	c.Assert(err, jc.ErrorIsNil)
+	s.fake.CheckCalls(c, []gitjujutesting.StubCall{
+		{"ForceCleanups", []interface{}{[]string{"5c9d1e8b", "5c9d1e8c"}}},
+		{"Close", nil},
+	})
+}
+
+func (s *CleanupsCommandSuite) TestForceCleanupError(c *gc.C) {
+	s.fake.SetErrors(errors.New(`dyingMachine cleanup "5c9d1e8b" failed: boom`))
+	_, err := cmdtesting.RunCommand(c, model.NewForceCleanupCommandForTest(&s.fake, s.store), "5c9d1e8b")
+	c.Assert(err, gc.ErrorMatches, `dyingMachine cleanup "5c9d1e8b" failed: boom`)
+}
+
+func (s *CleanupsCommandSuite) TestCancelCleanup(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, model.NewCancelCleanupCommandForTest(&s.fake, s.store), "5c9d1e8b")
+	c.Assert(err, jc.ErrorIsNil)
+	s.fake.CheckCalls(c, []gitjujutesting.StubCall{
+		{"CancelCleanups", []interface{}{[]string{"5c9d1e8b"}}},
+		{"Close", nil},
+	})
+}
+
+func (s *CleanupsCommandSuite) TestChangeCleanupNoIds(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, model.NewCancelCleanupCommandForTest(&s.fake, s.store))
+	c.Assert(err, gc.ErrorMatches, "no cleanup ids specified")
+	s.fake.CheckNoCalls(c)
+}
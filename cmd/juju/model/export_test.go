@@ -52,6 +52,41 @@ func NewShowCommandForTest(api ShowModelAPI, refreshFunc func(jujuclient.ClientS
 	return modelcmd.Wrap(cmd, modelcmd.WrapSkipModelFlags)
 }
 
+// NewCheckModelCommandForTest returns a checkModelCommand with the api
+// provided as specified.
+func NewCheckModelCommandForTest(api CheckModelAPI, store jujuclient.ClientStore) cmd.Command {
+	cmd := &checkModelCommand{api: api}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
+// NewListCleanupsCommandForTest returns a listCleanupsCommand with the
+// api provided as specified.
+func NewListCleanupsCommandForTest(api CleanupsAPI, store jujuclient.ClientStore) cmd.Command {
+	cmd := &listCleanupsCommand{cleanupsCommandBase: cleanupsCommandBase{api: api}}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
+// NewForceCleanupCommandForTest returns a force-cleanup command with the
+// api provided as specified.
+func NewForceCleanupCommandForTest(api CleanupsAPI, store jujuclient.ClientStore) cmd.Command {
+	return newChangeCleanupsCommandForTest(NewForceCleanupCommand(), api, store)
+}
+
+// NewCancelCleanupCommandForTest returns a cancel-cleanup command with
+// the api provided as specified.
+func NewCancelCleanupCommandForTest(api CleanupsAPI, store jujuclient.ClientStore) cmd.Command {
+	return newChangeCleanupsCommandForTest(NewCancelCleanupCommand(), api, store)
+}
+
+func newChangeCleanupsCommandForTest(wrapped cmd.Command, api CleanupsAPI, store jujuclient.ClientStore) cmd.Command {
+	cmd := modelcmd.InnerCommand(wrapped).(*changeCleanupsCommand)
+	cmd.api = api
+	cmd.SetClientStore(store)
+	return wrapped
+}
+
 // NewDumpCommandForTest returns a DumpCommand with the api provided as specified.
 func NewDumpCommandForTest(api DumpModelAPI, store jujuclient.ClientStore) cmd.Command {
 	cmd := &dumpCommand{api: api}
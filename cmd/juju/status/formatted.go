@@ -48,7 +48,8 @@ type modelStatus struct {
 }
 
 type controllerStatus struct {
-	Timestamp string `json:"timestamp,omitempty" yaml:"timestamp,omitempty"`
+	Timestamp string   `json:"timestamp,omitempty" yaml:"timestamp,omitempty"`
+	Warnings  []string `json:"warnings,omitempty" yaml:"warnings,omitempty"`
 }
 
 type networkInterface struct {
@@ -94,9 +94,12 @@ func (sf *statusFormatter) format() (formattedStatus, error) {
 			Message: sf.status.Model.MeterStatus.Message,
 		}
 	}
-	if sf.status.ControllerTimestamp != nil {
+	if sf.status.ControllerTimestamp != nil || len(sf.status.ControllerWarnings) > 0 {
 		out.Controller = &controllerStatus{
-			Timestamp: common.FormatTimeAsTimestamp(sf.status.ControllerTimestamp, sf.isoTime),
+			Warnings: sf.status.ControllerWarnings,
+		}
+		if sf.status.ControllerTimestamp != nil {
+			out.Controller.Timestamp = common.FormatTimeAsTimestamp(sf.status.ControllerTimestamp, sf.isoTime)
 		}
 	}
 	for k, m := range sf.status.Machines {
@@ -57,7 +57,7 @@ func FormatTabular(writer io.Writer, forceColor bool, value interface{}) error {
 	values := []interface{}{fs.Model.Name, fs.Model.Controller, cloudRegion, fs.Model.Version}
 
 	// Optional table output if values exist
-	message := getModelMessage(fs.Model)
+	message := getModelMessage(fs)
 	if fs.Model.SLA != "" {
 		header = append(header, "SLA")
 		values = append(values, fs.Model.SLA)
@@ -360,11 +360,14 @@ func fromMeterStatusColor(msColor string) *ansiterm.Context {
 	return nil
 }
 
-func getModelMessage(model modelStatus) string {
+func getModelMessage(fs formattedStatus) string {
 	// Select the most important message about the model (if any).
+	model := fs.Model
 	switch {
 	case model.Status.Message != "":
 		return model.Status.Message
+	case fs.Controller != nil && len(fs.Controller.Warnings) > 0:
+		return fs.Controller.Warnings[0]
 	case model.AvailableVersion != "":
 		return "upgrade available: " + model.AvailableVersion
 	default:
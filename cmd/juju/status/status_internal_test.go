@@ -5732,6 +5732,31 @@ func (s *StatusSuite) TestControllerTimestampInFullStatus(c *gc.C) {
 	})
 }
 
+func (s *StatusSuite) TestControllerWarningsInFullStatus(c *gc.C) {
+	now := time.Now()
+	status := &params.FullStatus{
+		Model: params.ModelStatusInfo{
+			CloudTag: "cloud-dummy",
+		},
+		ControllerTimestamp: &now,
+		ControllerWarnings:  []string{"last scheduled backup failed at 2019-05-26T02:00:00Z: boom"},
+	}
+	isoTime := true
+	formatter := NewStatusFormatter(status, isoTime)
+	formatted, err := formatter.format()
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(formatted.Controller, jc.DeepEquals, &controllerStatus{
+		Timestamp: common.FormatTimeAsTimestamp(&now, isoTime),
+		Warnings:  []string{"last scheduled backup failed at 2019-05-26T02:00:00Z: boom"},
+	})
+	c.Check(getModelMessage(formatted), gc.Equals, "last scheduled backup failed at 2019-05-26T02:00:00Z: boom")
+
+	// A model status message takes precedence.
+	formatted.Model.Status.Message = "model message"
+	c.Check(getModelMessage(formatted), gc.Equals, "model message")
+}
+
 func (s *StatusSuite) TestTabularNoRelations(c *gc.C) {
 	ctx := s.FilteringTestSetup(c)
 	defer s.resetContext(c, ctx)
@@ -39,6 +39,7 @@ var (
 		"environ-tracker",
 		"firewaller",
 		"instance-poller",
+		"instance-tagger",
 		"machine-undertaker",      // tertiary dependency: will be inactive because migration workers will be inactive
 		"metric-worker",           // tertiary dependency: will be inactive because migration workers will be inactive
 		"migration-fortress",      // secondary dependency: will be inactive because depends on model-upgrader
@@ -60,6 +61,7 @@ var (
 		"environ-tracker",
 		"firewaller",
 		"instance-poller",
+		"instance-tagger",
 		"log-forwarder",
 		"machine-undertaker",
 		"metric-worker",
@@ -558,6 +558,7 @@ func (a *MachineAgent) makeEngineCreator(agentName string, previousAgentVersion
 			UpgradeCheckLock:        a.initialUpgradeCheckComplete,
 			OpenController:          a.initController,
 			OpenStatePool:           a.initState,
+			OpenReadStatePool:       a.openReadStatePool,
 			OpenStateForUpgrade:     a.openStateForUpgrade,
 			StartAPIWorkers:         a.startAPIWorkers,
 			PreUpgradeSteps:         a.preUpgradeSteps,
@@ -983,6 +984,38 @@ func (a *MachineAgent) initState(agentConfig agent.Config) (*state.StatePool, er
 	return pool, nil
 }
 
+// openReadStatePool opens a state pool for a read replica's API
+// server. Its session reads from the nearest replica set member,
+// which is normally the controller's local mongo secondary, so
+// status queries don't load the primary; writes still go to the
+// primary. The pool shares the workers of the agent's state pool.
+func (a *MachineAgent) openReadStatePool(agentConfig agent.Config, primary *state.StatePool) (*state.StatePool, error) {
+	info, ok := agentConfig.MongoInfo()
+	if !ok {
+		return nil, errors.Errorf("no state info available")
+	}
+	dialOpts, err := mongoDialOptions(
+		stateWorkerDialOpts,
+		agentConfig,
+		a.mongoDialCollector,
+	)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	session, err := mongo.DialWithInfo(*info, dialOpts)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer session.Close()
+	session.SetMode(mgo.Nearest, true)
+
+	pool, err := state.OpenReadStatePool(primary, session)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return pool, nil
+}
+
 // startModelWorkers starts the set of workers that run for every model
 // in each controller, both IAAS and CAAS.
 func (a *MachineAgent) startModelWorkers(modelUUID string, modelType state.ModelType) (worker.Worker, error) {
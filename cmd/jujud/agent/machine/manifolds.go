@@ -41,6 +41,7 @@ import (
 	"github.com/juju/juju/worker/apiservercertwatcher"
 	"github.com/juju/juju/worker/auditconfigupdater"
 	"github.com/juju/juju/worker/authenticationworker"
+	"github.com/juju/juju/worker/backupscheduler"
 	"github.com/juju/juju/worker/centralhub"
 	"github.com/juju/juju/worker/certupdater"
 	"github.com/juju/juju/worker/common"
@@ -71,6 +72,7 @@ import (
 	"github.com/juju/juju/worker/proxyupdater"
 	psworker "github.com/juju/juju/worker/pubsub"
 	"github.com/juju/juju/worker/raft"
+	"github.com/juju/juju/worker/raft/raftadmin"
 	"github.com/juju/juju/worker/raft/raftbackstop"
 	"github.com/juju/juju/worker/raft/raftclusterer"
 	"github.com/juju/juju/worker/raft/raftflag"
@@ -149,6 +151,12 @@ type ManifoldsConfig struct {
 	// *state.StatePool.
 	OpenStatePool func(coreagent.Config) (*state.StatePool, error)
 
+	// OpenReadStatePool is used by the apiserver manifold to create
+	// the *state.StatePool used to serve read-only API requests on
+	// read replica controllers, sharing the workers of the agent's
+	// state pool.
+	OpenReadStatePool func(coreagent.Config, *state.StatePool) (*state.StatePool, error)
+
 	// OpenStateForUpgrade is a function the upgradesteps worker can
 	// use to establish a connection to state.
 	OpenStateForUpgrade func() (*state.StatePool, error)
@@ -712,6 +720,16 @@ func Manifolds(config ManifoldsConfig) dependency.Manifolds {
 			},
 		))),
 
+		backupSchedulerName: ifNotMigrating(ifPrimaryController(backupscheduler.Manifold(
+			backupscheduler.ManifoldConfig{
+				AgentName:  agentName,
+				ClockName:  clockName,
+				StateName:  stateName,
+				NewBackups: backupscheduler.NewStateBackups,
+				NewWorker:  backupscheduler.NewWorker,
+			},
+		))),
+
 		httpServerArgsName: httpserverargs.Manifold(httpserverargs.ManifoldConfig{
 			ClockName:             clockName,
 			ControllerPortName:    controllerPortName,
@@ -762,6 +780,7 @@ func Manifolds(config ManifoldsConfig) dependency.Manifolds {
 			RegisterIntrospectionHTTPHandlers: config.RegisterIntrospectionHTTPHandlers,
 			Hub:                               config.CentralHub,
 			Presence:                          config.PresenceRecorder,
+			OpenReadStatePool:                 config.OpenReadStatePool,
 			NewWorker:                         apiserver.NewWorker,
 		}),
 
@@ -841,6 +860,17 @@ func Manifolds(config ManifoldsConfig) dependency.Manifolds {
 			NewWorker:      raftbackstop.NewWorker,
 		}),
 
+		// The raft admin worker reports the state of the local raft
+		// node to the RaftCluster facade, and makes configuration
+		// changes it requests while this node is the leader.
+		raftAdminName: raftadmin.Manifold(raftadmin.ManifoldConfig{
+			RaftName:       raftName,
+			CentralHubName: centralHubName,
+			AgentName:      agentName,
+			Logger:         loggo.GetLogger("juju.worker.raft.raftadmin"),
+			NewWorker:      raftadmin.NewWorker,
+		}),
+
 		// The raft forwarder accepts FSM commands from the hub and
 		// applies them to the raft leader.
 		raftForwarderName: ifRaftLeader(raftforwarder.Manifold(raftforwarder.ManifoldConfig{
@@ -982,6 +1012,7 @@ const (
 	isControllerFlagName          = "is-controller-flag"
 	logPrunerName                 = "log-pruner"
 	txnPrunerName                 = "transaction-pruner"
+	backupSchedulerName           = "backup-scheduler"
 	certificateWatcherName        = "certificate-watcher"
 	modelWorkerManagerName        = "model-worker-manager"
 	peergrouperName               = "peer-grouper"
@@ -1003,6 +1034,7 @@ const (
 	raftFlagName      = "raft-leader-flag"
 	raftBackstopName  = "raft-backstop"
 	raftForwarderName = "raft-forwarder"
+	raftAdminName     = "raft-admin"
 
 	validCredentialFlagName = "valid-credential-flag"
 )
@@ -43,6 +43,7 @@ func (s *ManifoldsSuite) TestIAASNames(c *gc.C) {
 		"environ-tracker",
 		"firewaller",
 		"instance-poller",
+		"instance-tagger",
 		"is-responsible-flag",
 		"log-forwarder",
 		"machine-undertaker",
@@ -462,6 +463,20 @@ var expectedIAASModelManifoldsWithDependencies = map[string][]string{
 		"valid-credential-flag",
 	},
 
+	"instance-tagger": {
+		"agent",
+		"api-caller",
+		"clock",
+		"environ-tracker",
+		"is-responsible-flag",
+		"migration-fortress",
+		"migration-inactive-flag",
+		"model-upgrade-gate",
+		"model-upgraded-flag",
+		"not-dead-flag",
+		"valid-credential-flag",
+	},
+
 	"is-responsible-flag": {"agent", "api-caller", "clock"},
 
 	"log-forwarder": {
@@ -0,0 +1,352 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+	"net"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/raft"
+	"github.com/juju/clock"
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/replicaset"
+	"github.com/juju/retry"
+	"gopkg.in/juju/names.v2"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/cmd/jujud/util"
+	"github.com/juju/juju/mongo"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/service"
+	"github.com/juju/juju/service/common"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/worker/peergrouper"
+	raftworker "github.com/juju/juju/worker/raft"
+	"github.com/juju/juju/worker/raft/raftutil"
+)
+
+const recoverControllerDoc = `
+recover-controller restores a controller that has lost a majority of its
+voting machines, using the controller machine it is run on as the survivor.
+It must be run as root on the surviving machine.
+
+The machine agent is stopped while the recovery takes place. The Mongo
+replica set is forced to a configuration containing only the survivor, the
+other controller machines are recorded as not voting and the API addresses
+of the survivor alone are published to agents. The raft cluster used for
+leases is reset to the survivor, and the machine agent is started again.
+
+With --dry-run, the planned steps are printed and nothing is changed.
+`
+
+// replicaSetSession is the part of the mongo session used to read and
+// force the replica set configuration.
+type replicaSetSession interface {
+	CurrentConfig() (*replicaset.Config, error)
+	ForceConfig(replicaset.Config) error
+	Close()
+}
+
+// NewRecoverControllerCommand returns a new RecoverController command
+// initialized with the default helper functions.
+func NewRecoverControllerCommand() *RecoverControllerCommand {
+	return &RecoverControllerCommand{
+		readConfig:      agent.ReadConfig,
+		discoverService: service.DiscoverService,
+		dialReplicaSet:  dialLocalReplicaSet,
+		recoverState:    recoverControllerState,
+		recoverRaft:     recoverControllerRaft,
+	}
+}
+
+// RecoverControllerCommand represents a jujud recover-controller command.
+type RecoverControllerCommand struct {
+	cmd.CommandBase
+	dataDir   string
+	machineId string
+	dryRun    bool
+
+	readConfig      func(string) (agent.ConfigSetterWriter, error)
+	discoverService discoverService
+	dialReplicaSet  func(agent.Config) (replicaSetSession, error)
+	recoverState    func(agent.Config, string) error
+	recoverRaft     func(agent.Config, raft.Server) error
+}
+
+// Info returns a description of the command.
+func (*RecoverControllerCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "recover-controller",
+		Purpose: "recover a controller that has lost a majority of its machines",
+		Doc:     recoverControllerDoc,
+	}
+}
+
+// SetFlags adds the flags for this command to the passed gnuflag.FlagSet.
+func (c *RecoverControllerCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.dataDir, "data-dir", util.DataDir, "directory for juju data")
+	f.StringVar(&c.machineId, "machine-id", "", "id of the surviving controller machine this is run on")
+	f.BoolVar(&c.dryRun, "dry-run", false, "print the recovery steps without running them")
+}
+
+// Init initializes the command for running.
+func (c *RecoverControllerCommand) Init(args []string) error {
+	if c.machineId == "" {
+		return errors.New("--machine-id option must be set")
+	}
+	if !names.IsValidMachine(c.machineId) {
+		return errors.NotValidf("machine id %q", c.machineId)
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// Run recovers the controller.
+func (c *RecoverControllerCommand) Run(ctx *cmd.Context) (err error) {
+	tag := names.NewMachineTag(c.machineId)
+	agentConfig, err := c.readConfig(agent.ConfigPath(c.dataDir, tag))
+	if err != nil {
+		return errors.Annotatef(err, "cannot read agent config for machine %s", c.machineId)
+	}
+	servingInfo, ok := agentConfig.StateServingInfo()
+	if !ok {
+		return errors.Errorf("machine %s is not a controller", c.machineId)
+	}
+	serviceName := agentConfig.Value(agent.AgentServiceName)
+	if serviceName == "" {
+		serviceName = "jujud-" + tag.String()
+	}
+
+	session, err := c.dialReplicaSet(agentConfig)
+	if err != nil {
+		return errors.Annotate(err, "cannot connect to the local mongo")
+	}
+	defer session.Close()
+	config, err := session.CurrentConfig()
+	if err != nil {
+		return errors.Annotate(err, "cannot get the replica set configuration")
+	}
+	survivor, lost, err := peergrouper.RecoveryMembers(config.Members, c.machineId)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	host, _, err := net.SplitHostPort(survivor.Address)
+	if err != nil {
+		return errors.Annotatef(err, "cannot parse replica set member address %q", survivor.Address)
+	}
+	raftServer := raft.Server{
+		ID:       raft.ServerID(c.machineId),
+		Address:  raft.ServerAddress(net.JoinHostPort(host, strconv.Itoa(servingInfo.APIPort))),
+		Suffrage: raft.Voter,
+	}
+
+	var lostAddresses, lostMachines []string
+	for _, m := range lost {
+		lostAddresses = append(lostAddresses, m.Address)
+		if id, ok := peergrouper.MemberMachineId(m); ok {
+			lostMachines = append(lostMachines, id)
+		}
+	}
+	steps := []string{
+		fmt.Sprintf("stop the %s service", serviceName),
+		fmt.Sprintf("force the mongo replica set to contain only %s%s",
+			survivor.Address, listSuffix(", removing ", lostAddresses)),
+		fmt.Sprintf("record machine %s as the only voting controller machine%s",
+			c.machineId, listSuffix(", in place of machines ", lostMachines)),
+		fmt.Sprintf("publish the API addresses of machine %s to agents", c.machineId),
+		fmt.Sprintf("reset the raft cluster to server %s (%s)", raftServer.ID, raftServer.Address),
+		fmt.Sprintf("start the %s service", serviceName),
+	}
+	fmt.Fprintf(ctx.Stdout, "Recovering the controller using machine %s:\n", c.machineId)
+	for i, step := range steps {
+		fmt.Fprintf(ctx.Stdout, "  %d. %s\n", i+1, step)
+	}
+	if c.dryRun {
+		fmt.Fprintln(ctx.Stdout, "Dry run: no changes made.")
+		return nil
+	}
+
+	svc, err := c.discoverService(serviceName, common.Conf{})
+	if err != nil {
+		return errors.Annotate(err, "cannot determine juju service")
+	}
+	if err := svc.Stop(); err != nil {
+		return errors.Annotate(err, "cannot stop juju to begin recovery")
+	}
+	defer func() {
+		if svcErr := svc.Start(); svcErr != nil {
+			if err == nil {
+				err = errors.Annotate(svcErr, "cannot start juju after recovery")
+			} else {
+				logger.Errorf("cannot start juju after failed recovery: %v", svcErr)
+			}
+		}
+	}()
+
+	config.Version++
+	config.Members = []replicaset.Member{survivor}
+	if err := session.ForceConfig(*config); err != nil {
+		return errors.Annotate(err, "cannot force the replica set configuration")
+	}
+	if err := c.recoverState(agentConfig, c.machineId); err != nil {
+		return errors.Annotate(err, "cannot update controller machines")
+	}
+	if err := c.recoverRaft(agentConfig, raftServer); err != nil {
+		return errors.Annotate(err, "cannot reset the raft cluster")
+	}
+
+	fmt.Fprintf(ctx.Stdout, "Machine %s is now the only controller machine.\n", c.machineId)
+	if len(lostMachines) > 0 {
+		fmt.Fprintf(ctx.Stdout, "Remove the lost machines with 'juju remove-machine --force %s',\n"+
+			"then run 'juju enable-ha' to restore high availability.\n", strings.Join(lostMachines, " "))
+	}
+	return nil
+}
+
+func listSuffix(prefix string, values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return prefix + strings.Join(values, ", ")
+}
+
+type mgoReplicaSet struct {
+	session *mgo.Session
+}
+
+// CurrentConfig is part of replicaSetSession.
+func (s mgoReplicaSet) CurrentConfig() (*replicaset.Config, error) {
+	return replicaset.CurrentConfig(s.session)
+}
+
+// ForceConfig is part of replicaSetSession. Forcing the configuration
+// is the only way to reconfigure a replica set that has no primary.
+func (s mgoReplicaSet) ForceConfig(config replicaset.Config) error {
+	return s.session.Run(bson.D{
+		{"replSetReconfig", config},
+		{"force", true},
+	}, nil)
+}
+
+// Close is part of replicaSetSession.
+func (s mgoReplicaSet) Close() {
+	s.session.Close()
+}
+
+// dialLocalReplicaSet connects directly to the controller's own mongo,
+// which can't be a primary while a majority of the voters are lost.
+func dialLocalReplicaSet(agentConfig agent.Config) (replicaSetSession, error) {
+	info, ok := agentConfig.MongoInfo()
+	if !ok {
+		return nil, errors.New("no state info available")
+	}
+	servingInfo, _ := agentConfig.StateServingInfo()
+	info.Addrs = []string{net.JoinHostPort("localhost", strconv.Itoa(servingInfo.StatePort))}
+	opts := mongo.DefaultDialOpts()
+	opts.Direct = true
+	session, err := mongo.DialWithInfo(*info, opts)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	session.SetMode(mgo.Monotonic, true)
+	return mgoReplicaSet{session}, nil
+}
+
+// recoverControllerState records the surviving machine as the only
+// voting controller machine, and publishes its API addresses.
+func recoverControllerState(agentConfig agent.Config, machineId string) error {
+	info, ok := agentConfig.MongoInfo()
+	if !ok {
+		return errors.New("no state info available")
+	}
+	// The survivor takes a little while to become primary after
+	// the replica set is reconfigured.
+	var session *mgo.Session
+	callArgs := defaultCallArgs
+	callArgs.Func = func() error {
+		var err error
+		session, err = mongo.DialWithInfo(*info, mongo.DefaultDialOpts())
+		return err
+	}
+	if err := retry.Call(callArgs); err != nil {
+		return errors.Annotate(retry.LastError(err), "cannot connect to the mongo primary")
+	}
+	defer session.Close()
+
+	pool, err := state.OpenStatePool(state.OpenParams{
+		Clock:              clock.WallClock,
+		ControllerTag:      agentConfig.Controller(),
+		ControllerModelTag: agentConfig.Model(),
+		MongoSession:       session,
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer pool.Close()
+	st := pool.SystemState()
+
+	controllerInfo, err := st.ControllerInfo()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, id := range controllerInfo.MachineIds {
+		m, err := st.Machine(id)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err := m.SetHasVote(id == machineId); err != nil {
+			return errors.Annotatef(err, "cannot update machine %s", id)
+		}
+	}
+
+	survivor, err := st.Machine(machineId)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	servingInfo, _ := agentConfig.StateServingInfo()
+	hostPorts := network.AddressesWithPort(survivor.Addresses(), servingInfo.APIPort)
+	if len(hostPorts) == 0 {
+		return errors.Errorf("machine %s has no addresses", machineId)
+	}
+	return errors.Annotate(
+		st.SetAPIHostPorts([][]network.HostPort{hostPorts}),
+		"cannot publish API addresses",
+	)
+}
+
+// recoverControllerRaft appends a raft configuration containing only
+// the surviving server to its log, so that it can elect itself leader
+// when the machine agent starts.
+func recoverControllerRaft(agentConfig agent.Config, server raft.Server) error {
+	dir := filepath.Join(agentConfig.DataDir(), "raft")
+	logStore, err := raftworker.NewLogStore(dir)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer logStore.Close()
+	snapshots, err := raftworker.NewSnapshotStore(dir, 2, logger)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	// Keep the address raft already has for the server, if any.
+	current, err := raftutil.LatestConfiguration(logStore, snapshots)
+	if err != nil && !errors.IsNotFound(err) {
+		return errors.Trace(err)
+	}
+	for _, s := range current.Servers {
+		if s.ID == server.ID {
+			server.Address = s.Address
+		}
+	}
+	return raftutil.AppendConfiguration(logStore, raft.Configuration{
+		Servers: []raft.Server{server},
+	})
+}
@@ -0,0 +1,207 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// +build !windows
+
+package main
+
+import (
+	"github.com/hashicorp/raft"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/replicaset"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/service"
+	"github.com/juju/juju/service/common"
+	"github.com/juju/juju/testing"
+	jujuversion "github.com/juju/juju/version"
+)
+
+type RecoverControllerSuite struct {
+	testing.BaseSuite
+	dataDir string
+	calls   []string
+	config  replicaset.Config
+	forced  *replicaset.Config
+	server  raft.Server
+}
+
+var _ = gc.Suite(&RecoverControllerSuite{})
+
+func (s *RecoverControllerSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.dataDir = c.MkDir()
+	s.calls = nil
+	s.forced = nil
+	s.server = raft.Server{}
+
+	vote := true
+	member := func(id int, machineId string) replicaset.Member {
+		return replicaset.Member{
+			Id:      id,
+			Address: "10.0.0." + machineId + ":37017",
+			Tags:    map[string]string{"juju-machine-id": machineId},
+			Votes:   &vote,
+		}
+	}
+	s.config = replicaset.Config{
+		Name:    "juju",
+		Version: 3,
+		Members: []replicaset.Member{member(1, "0"), member(2, "1"), member(3, "2")},
+	}
+
+	conf, err := agent.NewStateMachineConfig(agent.AgentConfigParams{
+		Paths:             agent.Paths{DataDir: s.dataDir},
+		Tag:               names.NewMachineTag("1"),
+		UpgradedToVersion: jujuversion.Current,
+		Password:          "sekrit",
+		CACert:            "ca cert",
+		APIAddresses:      []string{"localhost:17070"},
+		Nonce:             "a nonce",
+		Controller:        testing.ControllerTag,
+		Model:             testing.ModelTag,
+	}, params.StateServingInfo{
+		Cert:           "cert",
+		PrivateKey:     "key",
+		CAPrivateKey:   "ca key",
+		StatePort:      37017,
+		APIPort:        17070,
+		SharedSecret:   "shared",
+		SystemIdentity: "identity",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(conf.Write(), jc.ErrorIsNil)
+}
+
+func (s *RecoverControllerSuite) newCommand() *RecoverControllerCommand {
+	command := NewRecoverControllerCommand()
+	command.discoverService = func(name string, _ common.Conf) (service.Service, error) {
+		s.calls = append(s.calls, "discover "+name)
+		return &recordingService{calls: &s.calls}, nil
+	}
+	command.dialReplicaSet = func(agent.Config) (replicaSetSession, error) {
+		s.calls = append(s.calls, "dial")
+		return &fakeReplicaSet{suite: s}, nil
+	}
+	command.recoverState = func(_ agent.Config, machineId string) error {
+		s.calls = append(s.calls, "recoverState "+machineId)
+		return nil
+	}
+	command.recoverRaft = func(_ agent.Config, server raft.Server) error {
+		s.calls = append(s.calls, "recoverRaft")
+		s.server = server
+		return nil
+	}
+	return command
+}
+
+func (s *RecoverControllerSuite) TestInitRequiresMachineId(c *gc.C) {
+	err := cmdtesting.InitCommand(s.newCommand(), nil)
+	c.Assert(err, gc.ErrorMatches, "--machine-id option must be set")
+}
+
+func (s *RecoverControllerSuite) TestInitInvalidMachineId(c *gc.C) {
+	err := cmdtesting.InitCommand(s.newCommand(), []string{"--machine-id", "foo"})
+	c.Assert(err, gc.ErrorMatches, `machine id "foo" not valid`)
+}
+
+func (s *RecoverControllerSuite) TestDryRun(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, s.newCommand(),
+		"--data-dir", s.dataDir, "--machine-id", "1", "--dry-run")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+Recovering the controller using machine 1:
+  1. stop the jujud-machine-1 service
+  2. force the mongo replica set to contain only 10.0.0.1:37017, removing 10.0.0.0:37017, 10.0.0.2:37017
+  3. record machine 1 as the only voting controller machine, in place of machines 0, 2
+  4. publish the API addresses of machine 1 to agents
+  5. reset the raft cluster to server 1 (10.0.0.1:17070)
+  6. start the jujud-machine-1 service
+Dry run: no changes made.
+`[1:])
+	c.Assert(s.calls, jc.DeepEquals, []string{"dial", "CurrentConfig", "Close"})
+	c.Assert(s.forced, gc.IsNil)
+}
+
+func (s *RecoverControllerSuite) TestRun(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, s.newCommand(),
+		"--data-dir", s.dataDir, "--machine-id", "1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.calls, jc.DeepEquals, []string{
+		"dial",
+		"CurrentConfig",
+		"discover jujud-machine-1",
+		"Stop",
+		"ForceConfig",
+		"recoverState 1",
+		"recoverRaft",
+		"Start",
+		"Close",
+	})
+	c.Assert(s.forced, gc.NotNil)
+	c.Assert(s.forced.Version, gc.Equals, 4)
+	c.Assert(s.forced.Members, gc.HasLen, 1)
+	c.Assert(s.forced.Members[0].Address, gc.Equals, "10.0.0.1:37017")
+	c.Assert(s.server, jc.DeepEquals, raft.Server{
+		ID:       "1",
+		Address:  "10.0.0.1:17070",
+		Suffrage: raft.Voter,
+	})
+	c.Assert(cmdtesting.Stdout(ctx), jc.Contains,
+		"Remove the lost machines with 'juju remove-machine --force 0 2'")
+}
+
+func (s *RecoverControllerSuite) TestRunNoAgentConfig(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, s.newCommand(),
+		"--data-dir", s.dataDir, "--machine-id", "3")
+	c.Assert(err, gc.ErrorMatches, `cannot read agent config for machine 3: .*`)
+	c.Assert(s.calls, gc.HasLen, 0)
+}
+
+func (s *RecoverControllerSuite) TestRunMachineNotInReplicaSet(c *gc.C) {
+	s.config.Members = s.config.Members[:1]
+	_, err := cmdtesting.RunCommand(c, s.newCommand(),
+		"--data-dir", s.dataDir, "--machine-id", "1")
+	c.Assert(err, gc.ErrorMatches, `replica set member for machine "1" not found`)
+}
+
+type fakeReplicaSet struct {
+	suite *RecoverControllerSuite
+}
+
+func (f *fakeReplicaSet) CurrentConfig() (*replicaset.Config, error) {
+	f.suite.calls = append(f.suite.calls, "CurrentConfig")
+	config := f.suite.config
+	return &config, nil
+}
+
+func (f *fakeReplicaSet) ForceConfig(config replicaset.Config) error {
+	f.suite.calls = append(f.suite.calls, "ForceConfig")
+	f.suite.forced = &config
+	return nil
+}
+
+func (f *fakeReplicaSet) Close() {
+	f.suite.calls = append(f.suite.calls, "Close")
+}
+
+// recordingService is a fakeService that records its calls in a
+// shared list, so they can be ordered with the other recovery steps.
+type recordingService struct {
+	fakeService
+	calls *[]string
+}
+
+func (f *recordingService) Start() error {
+	*f.calls = append(*f.calls, "Start")
+	return nil
+}
+
+func (f *recordingService) Stop() error {
+	*f.calls = append(*f.calls, "Stop")
+	return nil
+}
@@ -16,12 +16,14 @@ import (
 	jujuos "github.com/juju/os"
 	"github.com/juju/utils/exec"
 	"gopkg.in/juju/names.v2"
+	"gopkg.in/yaml.v2"
 
 	"github.com/juju/juju/agent"
 	cmdutil "github.com/juju/juju/cmd/jujud/util"
 	"github.com/juju/juju/core/machinelock"
 	"github.com/juju/juju/juju/sockets"
 	"github.com/juju/juju/worker/uniter"
+	"github.com/juju/juju/worker/uniter/runner/context"
 )
 
 type RunCommand struct {
@@ -34,6 +36,8 @@ type RunCommand struct {
 	forceRemoteUnit bool
 	relationId      string
 	remoteUnitName  string
+	replayHookId    string
+	dryRun          bool
 }
 
 const runCommandDoc = `
@@ -48,6 +52,12 @@ If --no-context is specified, the <unit-name> positional
 argument is not needed.
 
 The commands are executed with '/bin/bash -s', and the output returned.
+
+If --replay-hook is specified, no commands are given; instead the failed
+hook with the given capture id is run again in the context it was
+captured in. With --dry-run, any relation or leader settings, statuses,
+workload version or pod spec the hook sets are printed rather than
+committed.
 `
 
 // Info returns usage information for the command.
@@ -66,6 +76,8 @@ func (c *RunCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.StringVar(&c.relationId, "relation", "", "")
 	f.StringVar(&c.remoteUnitName, "remote-unit", "", "run the commands for a specific remote unit in a relation context on a unit")
 	f.BoolVar(&c.forceRemoteUnit, "force-remote-unit", false, "run the commands for a specific relation context, bypassing the remote unit check")
+	f.StringVar(&c.replayHookId, "replay-hook", "", "replay the captured failed hook with the given id")
+	f.BoolVar(&c.dryRun, "dry-run", false, "record rather than commit changes made by a replayed hook")
 }
 
 func (c *RunCommand) Init(args []string) error {
@@ -92,6 +104,21 @@ func (c *RunCommand) Init(args []string) error {
 			}
 		}
 	}
+	if c.replayHookId != "" {
+		if c.noContext {
+			return fmt.Errorf("--replay-hook cannot be used with --no-context")
+		}
+		if c.relationId != "" || c.remoteUnitName != "" {
+			return fmt.Errorf("--replay-hook cannot be used with --relation or --remote-unit")
+		}
+		if !context.IsValidHookCaptureId(c.replayHookId) {
+			return fmt.Errorf("%q is not a valid hook id", c.replayHookId)
+		}
+		return cmd.CheckEmpty(args)
+	}
+	if c.dryRun {
+		return fmt.Errorf("--dry-run can only be used with --replay-hook")
+	}
 	if len(args) < 1 {
 		return fmt.Errorf("missing commands")
 	}
@@ -113,6 +140,11 @@ func (c *RunCommand) Run(ctx *cmd.Context) error {
 
 	ctx.Stdout.Write(result.Stdout)
 	ctx.Stderr.Write(result.Stderr)
+	if c.dryRun {
+		if err := c.writeDryRunRecord(ctx); err != nil {
+			return errors.Trace(err)
+		}
+	}
 	return cmd.NewRcPassthroughError(result.Code)
 }
 
@@ -121,6 +153,42 @@ func (c *RunCommand) socketPath() string {
 	return paths.Runtime.JujuRunSocket
 }
 
+func (c *RunCommand) hookCapturesDir() string {
+	paths := uniter.NewPaths(cmdutil.DataDir, c.unit)
+	return paths.ComponentDir(context.HookCapturesDir)
+}
+
+// prepareReplay sets up the commands and relation context needed to
+// replay the captured hook.
+func (c *RunCommand) prepareReplay() error {
+	capture, err := context.ReadHookCapture(c.hookCapturesDir(), c.replayHookId)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	c.commands = "hooks/" + capture.Hook
+	if capture.RelationId != -1 {
+		c.relationId = strconv.Itoa(capture.RelationId)
+	}
+	c.remoteUnitName = capture.RemoteUnit
+	c.forceRemoteUnit = true
+	return nil
+}
+
+// writeDryRunRecord writes out the settings changes recorded by a
+// dry-run replay.
+func (c *RunCommand) writeDryRunRecord(ctx *cmd.Context) error {
+	record, err := context.ReadDryRunRecord(c.hookCapturesDir(), c.replayHookId)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	data, err := yaml.Marshal(record)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	fmt.Fprintf(ctx.Stdout, "dry-run: changes not committed:\n%s", data)
+	return nil
+}
+
 func (c *RunCommand) executeInUnitContext() (*exec.ExecResponse, error) {
 	unitDir := agent.Dir(cmdutil.DataDir, c.unit)
 	logger.Debugf("looking for unit dir %s", unitDir)
@@ -132,6 +200,12 @@ func (c *RunCommand) executeInUnitContext() (*exec.ExecResponse, error) {
 		return nil, errors.Trace(err)
 	}
 
+	if c.replayHookId != "" {
+		if err := c.prepareReplay(); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+
 	relationId, err := checkRelationId(c.relationId)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -152,6 +226,8 @@ func (c *RunCommand) executeInUnitContext() (*exec.ExecResponse, error) {
 		RelationId:      relationId,
 		RemoteUnitName:  c.remoteUnitName,
 		ForceRemoteUnit: c.forceRemoteUnit,
+		ReplayHookId:    c.replayHookId,
+		DryRun:          c.dryRun,
 	}
 	err = client.Call(uniter.JujuRunEndpoint, args, &result)
 	return &result, errors.Trace(err)
@@ -5,6 +5,7 @@ package main
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -53,6 +54,8 @@ func (*RunTestSuite) TestArgParsing(c *gc.C) {
 		relationId      string
 		remoteUnit      string
 		forceRemoteUnit bool
+		replayHookId    string
+		dryRun          bool
 	}{{
 		title:    "no args",
 		errMatch: "missing unit-name",
@@ -112,6 +115,33 @@ func (*RunTestSuite) TestArgParsing(c *gc.C) {
 		unit:            names.NewUnitTag("name/2"),
 		relationId:      "mongodb:1",
 		forceRemoteUnit: true,
+	}, {
+		title:        "replay-hook",
+		args:         []string{"--replay-hook", "20190723-101500-install", "unit-name-2"},
+		unit:         names.NewUnitTag("name/2"),
+		replayHookId: "20190723-101500-install",
+	}, {
+		title:        "replay-hook dry-run",
+		args:         []string{"--replay-hook", "20190723-101500-install", "--dry-run", "unit-name-2"},
+		unit:         names.NewUnitTag("name/2"),
+		replayHookId: "20190723-101500-install",
+		dryRun:       true,
+	}, {
+		title:    "replay-hook with commands",
+		args:     []string{"--replay-hook", "20190723-101500-install", "unit-name-2", "command"},
+		errMatch: `unrecognized args: \["command"\]`,
+	}, {
+		title:    "replay-hook with relation",
+		args:     []string{"--replay-hook", "20190723-101500-install", "--relation", "db:1", "unit-name-2"},
+		errMatch: "--replay-hook cannot be used with --relation or --remote-unit",
+	}, {
+		title:    "replay-hook with invalid id",
+		args:     []string{"--replay-hook", "../../agent.conf", "unit-name-2"},
+		errMatch: `"../../agent.conf" is not a valid hook id`,
+	}, {
+		title:    "dry-run without replay-hook",
+		args:     []string{"--dry-run", "unit-name-2", "command"},
+		errMatch: "--dry-run can only be used with --replay-hook",
 	},
 	} {
 		c.Logf("%d: %s", i, test.title)
@@ -125,6 +155,8 @@ func (*RunTestSuite) TestArgParsing(c *gc.C) {
 			c.Assert(runCommand.relationId, gc.Equals, test.relationId)
 			c.Assert(runCommand.remoteUnitName, gc.Equals, test.remoteUnit)
 			c.Assert(runCommand.forceRemoteUnit, gc.Equals, test.forceRemoteUnit)
+			c.Assert(runCommand.replayHookId, gc.Equals, test.replayHookId)
+			c.Assert(runCommand.dryRun, gc.Equals, test.dryRun)
 		} else {
 			c.Assert(err, gc.ErrorMatches, test.errMatch)
 		}
@@ -238,6 +270,35 @@ func (s *RunTestSuite) TestRunningRelation(c *gc.C) {
 	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "bar stderr")
 }
 
+func (s *RunTestSuite) TestRunningReplayHook(c *gc.C) {
+	loggo.GetLogger("worker.uniter").SetLogLevel(loggo.TRACE)
+	s.runListenerForAgent(c, "unit-foo-1")
+	capturesDir := filepath.Join(cmdutil.DataDir, "agents", "unit-foo-1", "hook-captures")
+	err := os.MkdirAll(capturesDir, 0700)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ioutil.WriteFile(filepath.Join(capturesDir, "20190723-101500-db-relation-changed.yaml"), []byte(`
+id: 20190723-101500-db-relation-changed
+unit: foo/1
+hook: db-relation-changed
+relation-id: 1
+remote-unit: bar/0
+`[1:]), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+
+	ctx, err := cmdtesting.RunCommand(c, s.runCommand(), "--replay-hook", "20190723-101500-db-relation-changed", "foo/1")
+	c.Check(cmd.IsRcPassthroughError(err), jc.IsTrue)
+	c.Assert(err, gc.ErrorMatches, "subprocess encountered error code 42")
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "hooks/db-relation-changed stdout")
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "hooks/db-relation-changed stderr")
+}
+
+func (s *RunTestSuite) TestRunningReplayHookNotFound(c *gc.C) {
+	s.runListenerForAgent(c, "unit-foo-1")
+
+	_, err := cmdtesting.RunCommand(c, s.runCommand(), "--replay-hook", "20190723-101500-install", "foo/1")
+	c.Assert(err, gc.ErrorMatches, `hook capture "20190723-101500-install" not found`)
+}
+
 func (s *RunTestSuite) TestRunningBadRelation(c *gc.C) {
 	loggo.GetLogger("worker.uniter").SetLogLevel(loggo.TRACE)
 	s.runListenerForAgent(c, "unit-foo-1")
@@ -23,16 +23,33 @@ const (
 	Arch      = "arch"
 	Container = "container"
 	// cpuCores is an alias for Cores.
-	cpuCores     = "cpu-cores"
-	Cores        = "cores"
-	CpuPower     = "cpu-power"
-	Mem          = "mem"
-	RootDisk     = "root-disk"
-	Tags         = "tags"
-	InstanceType = "instance-type"
-	Spaces       = "spaces"
-	VirtType     = "virt-type"
-	Zones        = "zones"
+	cpuCores       = "cpu-cores"
+	Cores          = "cores"
+	CpuPower       = "cpu-power"
+	Mem            = "mem"
+	RootDisk       = "root-disk"
+	RootDiskSource = "root-disk-source"
+	Tags           = "tags"
+	InstanceType   = "instance-type"
+	Spaces         = "spaces"
+	VirtType       = "virt-type"
+	Zones          = "zones"
+	Spot           = "spot"
+	SpotMaxPrice   = "spot-max-price"
+	InstanceRole   = "instance-role"
+	AntiAffinity   = "anti-affinity"
+)
+
+const (
+	// AntiAffinityHard is the anti-affinity constraint value with which
+	// machines must not be placed on the same host as other machines of
+	// the same application.
+	AntiAffinityHard = "hard"
+
+	// AntiAffinitySoft is the anti-affinity constraint value with which
+	// machines are placed on different hosts from other machines of the
+	// same application where possible.
+	AntiAffinitySoft = "soft"
 )
 
 // Value describes a user's requirements of the hardware on which units
@@ -68,6 +85,11 @@ type Value struct {
 	// disk might be requested.
 	RootDisk *uint64 `json:"root-disk,omitempty" yaml:"root-disk,omitempty"`
 
+	// RootDiskSource, if not nil or empty, names where the machine's root
+	// disk should be created, such as a datastore. What the name refers
+	// to depends on the cloud. Only valid for clouds which offer a choice.
+	RootDiskSource *string `json:"root-disk-source,omitempty" yaml:"root-disk-source,omitempty"`
+
 	// Tags, if not nil, indicates tags that the machine must have applied to it.
 	// An empty list is treated the same as a nil (unspecified) list, except an
 	// empty list will override any default tags, where a nil list will not.
@@ -90,6 +112,29 @@ type Value struct {
 	// Zones, if not nil, holds a list of availability zones limiting where
 	// the machine can be located.
 	Zones *[]string `json:"zones,omitempty" yaml:"zones,omitempty"`
+
+	// Spot, if true, indicates that the machine should be started on
+	// spare capacity that the cloud may reclaim at short notice, at a
+	// lower price. Only valid for clouds which offer such capacity.
+	Spot *bool `json:"spot,omitempty" yaml:"spot,omitempty"`
+
+	// SpotMaxPrice, if not nil or empty, is the most that should be paid
+	// per hour for a machine started on spare capacity, in the cloud's
+	// currency. If unset, the cloud's on-demand price is the maximum.
+	// Only used when Spot is true.
+	SpotMaxPrice *string `json:"spot-max-price,omitempty" yaml:"spot-max-price,omitempty"`
+
+	// InstanceRole, if not nil or empty, names the cloud role that the
+	// machine should be started with, granting workloads on the machine
+	// access to the cloud's services. Only valid for clouds which
+	// support instance roles.
+	InstanceRole *string `json:"instance-role,omitempty" yaml:"instance-role,omitempty"`
+
+	// AntiAffinity, if not nil or empty, indicates whether the machine
+	// must ("hard") or should ("soft") be kept off hosts that run other
+	// machines of the same application. Only valid for clouds which
+	// expose host placement.
+	AntiAffinity *string `json:"anti-affinity,omitempty" yaml:"anti-affinity,omitempty"`
 }
 
 var rawAliases = map[string]string{
@@ -190,6 +235,36 @@ func (v *Value) HasZones() bool {
 	return v.Zones != nil && len(*v.Zones) > 0
 }
 
+// HasRootDiskSource returns true if the constraints.Value specifies
+// where the root disk should be created.
+func (v *Value) HasRootDiskSource() bool {
+	return v.RootDiskSource != nil && *v.RootDiskSource != ""
+}
+
+// HasInstanceRole returns true if the constraints.Value specifies an
+// instance role.
+func (v *Value) HasInstanceRole() bool {
+	return v.InstanceRole != nil && *v.InstanceRole != ""
+}
+
+// HasAntiAffinity returns true if the constraints.Value specifies an
+// anti-affinity policy.
+func (v *Value) HasAntiAffinity() bool {
+	return v.AntiAffinity != nil && *v.AntiAffinity != ""
+}
+
+// HasSpot returns true if the constraints.Value specifies that the machine
+// should be started on spare capacity.
+func (v *Value) HasSpot() bool {
+	return v.Spot != nil && *v.Spot
+}
+
+// HasSpotMaxPrice returns true if the constraints.Value specifies a maximum
+// price for a machine started on spare capacity.
+func (v *Value) HasSpotMaxPrice() bool {
+	return v.SpotMaxPrice != nil && *v.SpotMaxPrice != ""
+}
+
 // String expresses a constraints.Value in the language in which it was specified.
 func (v Value) String() string {
 	var strs []string
@@ -237,6 +312,21 @@ func (v Value) String() string {
 		s := strings.Join(*v.Zones, ",")
 		strs = append(strs, "zones="+s)
 	}
+	if v.Spot != nil {
+		strs = append(strs, "spot="+strconv.FormatBool(*v.Spot))
+	}
+	if v.SpotMaxPrice != nil {
+		strs = append(strs, "spot-max-price="+(*v.SpotMaxPrice))
+	}
+	if v.InstanceRole != nil {
+		strs = append(strs, "instance-role="+(*v.InstanceRole))
+	}
+	if v.AntiAffinity != nil {
+		strs = append(strs, "anti-affinity="+(*v.AntiAffinity))
+	}
+	if v.RootDiskSource != nil {
+		strs = append(strs, "root-disk-source="+(*v.RootDiskSource))
+	}
 	return strings.Join(strs, " ")
 }
 
@@ -283,6 +373,21 @@ func (v Value) GoString() string {
 	} else if v.Zones != nil {
 		values = append(values, "Zones: (*[]string)(nil)")
 	}
+	if v.Spot != nil {
+		values = append(values, fmt.Sprintf("Spot: %v", *v.Spot))
+	}
+	if v.SpotMaxPrice != nil {
+		values = append(values, fmt.Sprintf("SpotMaxPrice: %q", *v.SpotMaxPrice))
+	}
+	if v.InstanceRole != nil {
+		values = append(values, fmt.Sprintf("InstanceRole: %q", *v.InstanceRole))
+	}
+	if v.AntiAffinity != nil {
+		values = append(values, fmt.Sprintf("AntiAffinity: %q", *v.AntiAffinity))
+	}
+	if v.RootDiskSource != nil {
+		values = append(values, fmt.Sprintf("RootDiskSource: %q", *v.RootDiskSource))
+	}
 	return fmt.Sprintf("{%s}", strings.Join(values, ", "))
 }
 
@@ -441,6 +546,16 @@ func (v *Value) setRaw(name, str string) error {
 		err = v.setVirtType(str)
 	case Zones:
 		err = v.setZones(str)
+	case Spot:
+		err = v.setSpot(str)
+	case SpotMaxPrice:
+		err = v.setSpotMaxPrice(str)
+	case InstanceRole:
+		err = v.setInstanceRole(str)
+	case AntiAffinity:
+		err = v.setAntiAffinity(str)
+	case RootDiskSource:
+		err = v.setRootDiskSource(str)
 	default:
 		return errors.Errorf("unknown constraint %q", name)
 	}
@@ -506,6 +621,22 @@ func (v *Value) UnmarshalYAML(unmarshal func(interface{}) error) error {
 			v.VirtType = &vstr
 		case Zones:
 			v.Zones, err = parseYamlStrings("zones", val)
+		case Spot:
+			v.Spot, err = parseBool(vstr)
+		case SpotMaxPrice:
+			err = validateSpotMaxPrice(vstr)
+			if err == nil {
+				v.SpotMaxPrice = &vstr
+			}
+		case InstanceRole:
+			v.InstanceRole = &vstr
+		case AntiAffinity:
+			err = validateAntiAffinity(vstr)
+			if err == nil {
+				v.AntiAffinity = &vstr
+			}
+		case RootDiskSource:
+			v.RootDiskSource = &vstr
 		default:
 			return errors.Errorf("unknown constraint value: %v", k)
 		}
@@ -638,6 +769,83 @@ func (v *Value) setZones(str string) error {
 	return nil
 }
 
+func (v *Value) setInstanceRole(str string) error {
+	if v.InstanceRole != nil {
+		return errors.Errorf("already set")
+	}
+	v.InstanceRole = &str
+	return nil
+}
+
+func (v *Value) setRootDiskSource(str string) error {
+	if v.RootDiskSource != nil {
+		return errors.Errorf("already set")
+	}
+	v.RootDiskSource = &str
+	return nil
+}
+
+func (v *Value) setAntiAffinity(str string) error {
+	if v.AntiAffinity != nil {
+		return errors.Errorf("already set")
+	}
+	if err := validateAntiAffinity(str); err != nil {
+		return err
+	}
+	v.AntiAffinity = &str
+	return nil
+}
+
+func validateAntiAffinity(str string) error {
+	switch str {
+	case "", AntiAffinityHard, AntiAffinitySoft:
+		return nil
+	}
+	return errors.Errorf("must be %q or %q", AntiAffinityHard, AntiAffinitySoft)
+}
+
+func (v *Value) setSpot(str string) (err error) {
+	if v.Spot != nil {
+		return errors.Errorf("already set")
+	}
+	v.Spot, err = parseBool(str)
+	return
+}
+
+func (v *Value) setSpotMaxPrice(str string) error {
+	if v.SpotMaxPrice != nil {
+		return errors.Errorf("already set")
+	}
+	if err := validateSpotMaxPrice(str); err != nil {
+		return err
+	}
+	v.SpotMaxPrice = &str
+	return nil
+}
+
+func validateSpotMaxPrice(str string) error {
+	if str == "" {
+		return nil
+	}
+	price, err := strconv.ParseFloat(str, 64)
+	if err != nil || price <= 0 {
+		return errors.Errorf("must be a positive number")
+	}
+	return nil
+}
+
+func parseBool(str string) (*bool, error) {
+	var value bool
+	if str != "" {
+		val, err := strconv.ParseBool(str)
+		if err != nil {
+			return nil, errors.Errorf("must be true or false")
+		}
+		value = val
+	}
+	return &value, nil
+}
+
 func parseUint64(str string) (*uint64, error) {
 	var value uint64
 	if str != "" {
@@ -325,6 +325,74 @@ var parseConstraintsTests = []struct {
 		args:    []string{"zones="},
 	},
 
+	// Spot
+	{
+		summary: "set spot",
+		args:    []string{"spot=true"},
+	}, {
+		summary: "set spot false",
+		args:    []string{"spot=false"},
+	}, {
+		summary: "set spot empty",
+		args:    []string{"spot="},
+	}, {
+		summary: "set nonsense spot",
+		args:    []string{"spot=maybe"},
+		err:     `bad "spot" constraint: must be true or false`,
+	}, {
+		summary: "double set spot",
+		args:    []string{"spot=true", "spot=false"},
+		err:     `bad "spot" constraint: already set`,
+	}, {
+		summary: "set spot max price",
+		args:    []string{"spot=true spot-max-price=0.045"},
+	}, {
+		summary: "set nonsense spot max price",
+		args:    []string{"spot-max-price=cheap"},
+		err:     `bad "spot-max-price" constraint: must be a positive number`,
+	}, {
+		summary: "set instance role",
+		args:    []string{"instance-role=juju-workloads"},
+	}, {
+		summary: "set instance role empty",
+		args:    []string{"instance-role="},
+	}, {
+		summary: "double set instance role",
+		args:    []string{"instance-role=a", "instance-role=b"},
+		err:     `bad "instance-role" constraint: already set`,
+	}, {
+		summary: "set hard anti-affinity",
+		args:    []string{"anti-affinity=hard"},
+	}, {
+		summary: "set soft anti-affinity",
+		args:    []string{"anti-affinity=soft"},
+	}, {
+		summary: "set anti-affinity empty",
+		args:    []string{"anti-affinity="},
+	}, {
+		summary: "set nonsense anti-affinity",
+		args:    []string{"anti-affinity=always"},
+		err:     `bad "anti-affinity" constraint: must be "hard" or "soft"`,
+	}, {
+		summary: "double set anti-affinity",
+		args:    []string{"anti-affinity=hard", "anti-affinity=soft"},
+		err:     `bad "anti-affinity" constraint: already set`,
+	}, {
+		summary: "set root disk source",
+		args:    []string{"root-disk-source=datastore1"},
+	}, {
+		summary: "set root disk source empty",
+		args:    []string{"root-disk-source="},
+	}, {
+		summary: "double set root disk source",
+		args:    []string{"root-disk-source=a", "root-disk-source=b"},
+		err:     `bad "root-disk-source" constraint: already set`,
+	}, {
+		summary: "set negative spot max price",
+		args:    []string{"spot-max-price=-1"},
+		err:     `bad "spot-max-price" constraint: must be a positive number`,
+	},
+
 	// Everything at once.
 	{
 		summary: "kitchen sink together",
@@ -500,6 +568,10 @@ func strp(s string) *string {
 	return &s
 }
 
+func boolp(b bool) *bool {
+	return &b
+}
+
 func ctypep(ctype string) *instance.ContainerType {
 	res := instance.ContainerType(ctype)
 	return &res
@@ -540,6 +612,17 @@ var constraintsRoundtripTests = []roundTrip{
 	{"Zones1", constraints.Value{Zones: nil}},
 	{"Zones2", constraints.Value{Zones: &[]string{}}},
 	{"Zones3", constraints.Value{Zones: &[]string{"az1", "az2"}}},
+	{"Spot1", constraints.Value{Spot: nil}},
+	{"Spot2", constraints.Value{Spot: boolp(false)}},
+	{"Spot3", constraints.Value{Spot: boolp(true)}},
+	{"SpotMaxPrice1", constraints.Value{SpotMaxPrice: strp("")}},
+	{"SpotMaxPrice2", constraints.Value{Spot: boolp(true), SpotMaxPrice: strp("0.045")}},
+	{"InstanceRole1", constraints.Value{InstanceRole: strp("")}},
+	{"InstanceRole2", constraints.Value{InstanceRole: strp("juju-workloads")}},
+	{"AntiAffinity1", constraints.Value{AntiAffinity: strp("")}},
+	{"AntiAffinity2", constraints.Value{AntiAffinity: strp("soft")}},
+	{"RootDiskSource1", constraints.Value{RootDiskSource: strp("")}},
+	{"RootDiskSource2", constraints.Value{RootDiskSource: strp("datastore1")}},
 	{"All", constraints.Value{
 		Arch:         strp("i386"),
 		Container:    ctypep("lxd"),
@@ -598,6 +681,27 @@ func (s *ConstraintsSuite) TestRoundtripYaml(c *gc.C) {
 	}
 }
 
+func (s *ConstraintsSuite) TestUnmarshalYamlValidates(c *gc.C) {
+	for _, t := range []struct {
+		yaml string
+		err  string
+	}{{
+		yaml: "anti-affinity: always",
+		err:  `must be "hard" or "soft"`,
+	}, {
+		yaml: "spot-max-price: cheap",
+		err:  "must be a positive number",
+	}, {
+		yaml: "spot: maybe",
+		err:  "must be true or false",
+	}} {
+		c.Logf("yaml %q", t.yaml)
+		var cons constraints.Value
+		err := goyaml.Unmarshal([]byte(t.yaml), &cons)
+		c.Check(err, gc.ErrorMatches, t.err)
+	}
+}
+
 var hasContainerTests = []struct {
 	constraints  string
 	hasContainer bool
@@ -621,6 +725,45 @@ func (s *ConstraintsSuite) TestHasContainer(c *gc.C) {
 	}
 }
 
+func (s *ConstraintsSuite) TestHasSpot(c *gc.C) {
+	cons := constraints.MustParse("arch=amd64")
+	c.Check(cons.HasSpot(), jc.IsFalse)
+	c.Check(cons.HasSpotMaxPrice(), jc.IsFalse)
+	cons = constraints.MustParse("spot=false spot-max-price=")
+	c.Check(cons.HasSpot(), jc.IsFalse)
+	c.Check(cons.HasSpotMaxPrice(), jc.IsFalse)
+	cons = constraints.MustParse("spot=true spot-max-price=0.1")
+	c.Check(cons.HasSpot(), jc.IsTrue)
+	c.Check(cons.HasSpotMaxPrice(), jc.IsTrue)
+}
+
+func (s *ConstraintsSuite) TestHasInstanceRole(c *gc.C) {
+	cons := constraints.MustParse("arch=amd64")
+	c.Check(cons.HasInstanceRole(), jc.IsFalse)
+	cons = constraints.MustParse("instance-role=")
+	c.Check(cons.HasInstanceRole(), jc.IsFalse)
+	cons = constraints.MustParse("instance-role=juju-workloads")
+	c.Check(cons.HasInstanceRole(), jc.IsTrue)
+}
+
+func (s *ConstraintsSuite) TestHasRootDiskSource(c *gc.C) {
+	cons := constraints.MustParse("arch=amd64")
+	c.Check(cons.HasRootDiskSource(), jc.IsFalse)
+	cons = constraints.MustParse("root-disk-source=")
+	c.Check(cons.HasRootDiskSource(), jc.IsFalse)
+	cons = constraints.MustParse("root-disk-source=datastore1")
+	c.Check(cons.HasRootDiskSource(), jc.IsTrue)
+}
+
+func (s *ConstraintsSuite) TestHasAntiAffinity(c *gc.C) {
+	cons := constraints.MustParse("arch=amd64")
+	c.Check(cons.HasAntiAffinity(), jc.IsFalse)
+	cons = constraints.MustParse("anti-affinity=")
+	c.Check(cons.HasAntiAffinity(), jc.IsFalse)
+	cons = constraints.MustParse("anti-affinity=hard")
+	c.Check(cons.HasAntiAffinity(), jc.IsTrue)
+}
+
 func (s *ConstraintsSuite) TestHasInstanceType(c *gc.C) {
 	cons := constraints.MustParse("arch=amd64")
 	c.Check(cons.HasInstanceType(), jc.IsFalse)
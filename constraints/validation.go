@@ -8,6 +8,7 @@ import (
 	"reflect"
 
 	"github.com/juju/collections/set"
+	"github.com/juju/errors"
 )
 
 // Validator defines operations on constraints attributes which are
@@ -27,6 +28,12 @@ type Validator interface {
 	// RegisterUnsupported records attributes which are not supported by a constraints Value.
 	RegisterUnsupported(unsupported []string)
 
+	// RegisterRejected records attributes which are not supported by a
+	// constraints Value and which, unlike unsupported attributes, make
+	// the Value invalid if they are set to anything but the empty value.
+	// It is used for attributes that would be unsafe to ignore.
+	RegisterRejected(rejected []string)
+
 	// RegisterVocabulary records allowed values for the specified constraint attribute.
 	// allowedValues is expected to be a slice/array but is declared as interface{} so
 	// that vocabs of different types can be passed in.
@@ -58,6 +65,7 @@ func NewValidator() Validator {
 
 type validator struct {
 	unsupported set.Strings
+	rejected    set.Strings
 	conflicts   map[string]set.Strings
 	vocab       map[string][]interface{}
 }
@@ -77,6 +85,11 @@ func (v *validator) RegisterUnsupported(unsupported []string) {
 	v.unsupported = set.NewStrings(unsupported...)
 }
 
+// RegisterRejected is defined on Validator.
+func (v *validator) RegisterRejected(rejected []string) {
+	v.rejected = set.NewStrings(rejected...)
+}
+
 // RegisterVocabulary is defined on Validator.
 func (v *validator) RegisterVocabulary(attributeName string, allowedValues interface{}) {
 	v.vocab[resolveAlias(attributeName)] = convertToSlice(allowedValues)
@@ -161,6 +174,20 @@ func (v *validator) checkUnsupported(cons Value) []string {
 	return cons.hasAny(v.unsupported.Values()...)
 }
 
+// checkRejected returns an error if the constraints Value sets a
+// rejected attribute to anything but the empty value.
+func (v *validator) checkRejected(cons Value) error {
+	attrValues := cons.attributesWithValues()
+	for _, attrTag := range v.rejected.SortedValues() {
+		value, ok := attrValues[resolveAlias(attrTag)]
+		if !ok || value == "" {
+			continue
+		}
+		return errors.NotSupportedf("%q constraint", attrTag)
+	}
+	return nil
+}
+
 // checkValidValues returns an error if the constraints value contains an
 // attribute value which is not allowed by the vocab which may have been
 // registered for it.
@@ -256,6 +283,9 @@ func (v *validator) Validate(cons Value) ([]string, error) {
 	if err := v.checkConflicts(cons); err != nil {
 		return unsupported, err
 	}
+	if err := v.checkRejected(cons); err != nil {
+		return unsupported, err
+	}
 	if err := v.checkValidValues(cons); err != nil {
 		return unsupported, err
 	}
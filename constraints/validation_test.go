@@ -6,6 +6,7 @@ package constraints_test
 import (
 	"regexp"
 
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
@@ -136,6 +137,26 @@ var validationTests = []struct {
 	},
 }
 
+func (s *validationSuite) TestRejected(c *gc.C) {
+	validator := constraints.NewValidator()
+	validator.RegisterRejected([]string{constraints.InstanceRole})
+
+	_, err := validator.Validate(constraints.MustParse("mem=4G instance-role=juju-workloads"))
+	c.Assert(err, gc.ErrorMatches, `"instance-role" constraint not supported`)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+
+	// An empty value clears the attribute, so it is allowed.
+	unsupported, err := validator.Validate(constraints.MustParse("mem=4G instance-role="))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(unsupported, gc.HasLen, 0)
+
+	_, err = validator.Merge(
+		constraints.MustParse("instance-role=juju-workloads"),
+		constraints.MustParse("mem=4G"),
+	)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
 func (s *validationSuite) TestValidation(c *gc.C) {
 	for i, t := range validationTests {
 		c.Logf("test %d: %s", i, t.desc)
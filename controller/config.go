@@ -6,6 +6,7 @@ package controller
 import (
 	"fmt"
 	"net/url"
+	"path"
 	"regexp"
 	"time"
 
@@ -20,6 +21,7 @@ import (
 	"gopkg.in/macaroon-bakery.v2-unstable/bakery"
 
 	"github.com/juju/juju/cert"
+	"github.com/juju/juju/core/backups"
 	"github.com/juju/juju/core/resources"
 )
 
@@ -142,6 +144,53 @@ const (
 	// default value of 1M BatchSize and 100 passes will be used instead.
 	MaxPruneTxnPasses = "max-prune-txn-passes"
 
+	// BackupInterval is the interval at which the controller creates
+	// scheduled backups, eg "24h". Scheduled backups are disabled when
+	// it is not set.
+	BackupInterval = "backup-interval"
+
+	// BackupKeepDaily is the number of days for which the most recent
+	// scheduled backup of the day is kept.
+	BackupKeepDaily = "backup-keep-daily"
+
+	// BackupKeepWeekly is the number of weeks for which the most recent
+	// scheduled backup of the week is kept.
+	BackupKeepWeekly = "backup-keep-weekly"
+
+	// BackupStorage is where backup archives are stored: "mongo" (the
+	// default) stores them in the controller's own database, "local"
+	// in the directory given by backup-storage-path, and "s3" in the
+	// S3-compatible object store given by the backup-s3-* attributes.
+	BackupStorage = "backup-storage"
+
+	// BackupStoragePath is the directory, typically an NFS mount,
+	// in which backup archives are stored when backup-storage is
+	// "local". It must be available on every controller machine.
+	BackupStoragePath = "backup-storage-path"
+
+	// BackupS3Endpoint is the URL of the S3-compatible object store
+	// in which backup archives are stored.
+	BackupS3Endpoint = "backup-s3-endpoint"
+
+	// BackupS3Region is the region of the S3-compatible object store.
+	BackupS3Region = "backup-s3-region"
+
+	// BackupS3Bucket is the bucket in which backup archives are stored.
+	BackupS3Bucket = "backup-s3-bucket"
+
+	// BackupS3AccessKey is the access key used to authenticate with
+	// the S3-compatible object store.
+	BackupS3AccessKey = "backup-s3-access-key"
+
+	// BackupS3SecretKey is the secret key used to authenticate with
+	// the S3-compatible object store. It is not returned over the API.
+	BackupS3SecretKey = "backup-s3-secret-key"
+
+	// BackupPublicKey is a PEM-encoded RSA public key with which
+	// backup archives are encrypted, unless another key is supplied
+	// when the backup is created.
+	BackupPublicKey = "backup-public-key"
+
 	// Attribute Defaults
 
 	// DefaultAuditingEnabled contains the default value for the
@@ -193,6 +242,21 @@ const (
 	// DefaultMaxPruneTxnPasses is the default number of batches we will process
 	DefaultMaxPruneTxnPasses = 100
 
+	// DefaultBackupKeepDaily is the default number of daily scheduled
+	// backups to keep.
+	DefaultBackupKeepDaily = 7
+
+	// DefaultBackupKeepWeekly is the default number of weekly scheduled
+	// backups to keep.
+	DefaultBackupKeepWeekly = 4
+
+	// DefaultBackupStorage is the default location of backup archives.
+	DefaultBackupStorage = BackupStorageMongo
+
+	// DefaultBackupS3Region is the default region of the object store
+	// used for backup archives.
+	DefaultBackupS3Region = "us-east-1"
+
 	// JujuHASpace is the network space within which the MongoDB replica-set
 	// should communicate.
 	JujuHASpace = "juju-ha-space"
@@ -212,6 +276,13 @@ const (
 	MeteringURL = "metering-url"
 )
 
+// The supported values of BackupStorage.
+const (
+	BackupStorageMongo = "mongo"
+	BackupStorageLocal = "local"
+	BackupStorageS3    = "s3"
+)
+
 var (
 	// ControllerOnlyConfigAttributes are attributes which are only relevant
 	// for a controller, never a model.
@@ -235,6 +306,17 @@ var (
 		MaxTxnLogSize,
 		MaxPruneTxnBatchSize,
 		MaxPruneTxnPasses,
+		BackupInterval,
+		BackupKeepDaily,
+		BackupKeepWeekly,
+		BackupStorage,
+		BackupStoragePath,
+		BackupS3Endpoint,
+		BackupS3Region,
+		BackupS3Bucket,
+		BackupS3AccessKey,
+		BackupS3SecretKey,
+		BackupPublicKey,
 		JujuHASpace,
 		JujuManagementSpace,
 		AuditingEnabled,
@@ -262,12 +344,30 @@ var (
 		MaxPruneTxnPasses,
 		MaxLogsSize,
 		MaxLogsAge,
+		BackupInterval,
+		BackupKeepDaily,
+		BackupKeepWeekly,
+		BackupStorage,
+		BackupStoragePath,
+		BackupS3Endpoint,
+		BackupS3Region,
+		BackupS3Bucket,
+		BackupS3AccessKey,
+		BackupS3SecretKey,
+		BackupPublicKey,
 		JujuHASpace,
 		JujuManagementSpace,
 		CAASOperatorImagePath,
 		Features,
 	)
 
+	// SecretAttributes holds the controller config attributes whose
+	// values are secret. They may be set, but are never returned over
+	// the API.
+	SecretAttributes = set.NewStrings(
+		BackupS3SecretKey,
+	)
+
 	// DefaultAuditLogExcludeMethods is the default list of methods to
 	// exclude from the audit log.
 	DefaultAuditLogExcludeMethods = []string{
@@ -565,6 +665,79 @@ func (c Config) MaxPruneTxnPasses() int {
 	return c.intOrDefault(MaxPruneTxnPasses, DefaultMaxPruneTxnPasses)
 }
 
+// BackupInterval is the interval at which scheduled backups are
+// created. A zero interval means scheduled backups are disabled.
+func (c Config) BackupInterval() time.Duration {
+	// Value has already been validated.
+	val, _ := time.ParseDuration(c.asString(BackupInterval))
+	return val
+}
+
+// BackupKeepDaily is the number of days for which the most recent
+// scheduled backup of the day is kept.
+func (c Config) BackupKeepDaily() int {
+	return c.intOrDefault(BackupKeepDaily, DefaultBackupKeepDaily)
+}
+
+// BackupKeepWeekly is the number of weeks for which the most recent
+// scheduled backup of the week is kept.
+func (c Config) BackupKeepWeekly() int {
+	return c.intOrDefault(BackupKeepWeekly, DefaultBackupKeepWeekly)
+}
+
+// BackupStorage returns where backup archives are stored, one of
+// BackupStorageMongo, BackupStorageLocal or BackupStorageS3.
+func (c Config) BackupStorage() string {
+	if v := c.asString(BackupStorage); v != "" {
+		return v
+	}
+	return DefaultBackupStorage
+}
+
+// BackupStoragePath returns the directory in which backup archives
+// are stored when BackupStorage is BackupStorageLocal.
+func (c Config) BackupStoragePath() string {
+	return c.asString(BackupStoragePath)
+}
+
+// BackupS3Endpoint returns the URL of the object store in which backup
+// archives are stored when BackupStorage is BackupStorageS3.
+func (c Config) BackupS3Endpoint() string {
+	return c.asString(BackupS3Endpoint)
+}
+
+// BackupS3Region returns the region of the object store in which
+// backup archives are stored.
+func (c Config) BackupS3Region() string {
+	if v := c.asString(BackupS3Region); v != "" {
+		return v
+	}
+	return DefaultBackupS3Region
+}
+
+// BackupS3Bucket returns the bucket in which backup archives are stored.
+func (c Config) BackupS3Bucket() string {
+	return c.asString(BackupS3Bucket)
+}
+
+// BackupS3AccessKey returns the access key used to authenticate with
+// the object store in which backup archives are stored.
+func (c Config) BackupS3AccessKey() string {
+	return c.asString(BackupS3AccessKey)
+}
+
+// BackupS3SecretKey returns the secret key used to authenticate with
+// the object store in which backup archives are stored.
+func (c Config) BackupS3SecretKey() string {
+	return c.asString(BackupS3SecretKey)
+}
+
+// BackupPublicKey returns the PEM-encoded RSA public key with which
+// backup archives are encrypted by default, or "" if they are not.
+func (c Config) BackupPublicKey() string {
+	return c.asString(BackupPublicKey)
+}
+
 // JujuHASpace is the network space within which the MongoDB replica-set
 // should communicate.
 func (c Config) JujuHASpace() string {
@@ -651,6 +824,30 @@ func Validate(c Config) error {
 		}
 	}
 
+	if v, ok := c[BackupInterval].(string); ok && v != "" {
+		if d, err := time.ParseDuration(v); err != nil {
+			return errors.Annotate(err, "invalid backup interval in configuration")
+		} else if d < time.Hour {
+			return errors.Errorf("invalid backup interval in configuration: must be at least 1h, got %v", d)
+		}
+	}
+
+	for _, key := range []string{BackupKeepDaily, BackupKeepWeekly} {
+		if v, ok := c[key].(int); ok && v < 0 {
+			return errors.Errorf("invalid %s: should be a non-negative number of backups, got %d", key, v)
+		}
+	}
+
+	if err := c.validateBackupStorage(); err != nil {
+		return errors.Trace(err)
+	}
+
+	if v := c.BackupPublicKey(); v != "" {
+		if _, err := backups.ParsePublicKey([]byte(v)); err != nil {
+			return errors.Annotatef(err, "invalid %s", BackupPublicKey)
+		}
+	}
+
 	if err := c.validateSpaceConfig(JujuHASpace, "juju HA"); err != nil {
 		return errors.Trace(err)
 	}
@@ -722,6 +919,30 @@ func Validate(c Config) error {
 	return nil
 }
 
+func (c Config) validateBackupStorage() error {
+	switch c.BackupStorage() {
+	case BackupStorageMongo:
+	case BackupStorageLocal:
+		if p := c.BackupStoragePath(); !path.IsAbs(p) {
+			return errors.Errorf("invalid %s %q: must be an absolute path when %s is %q",
+				BackupStoragePath, p, BackupStorage, BackupStorageLocal)
+		}
+	case BackupStorageS3:
+		for _, key := range []string{BackupS3Endpoint, BackupS3Bucket, BackupS3AccessKey, BackupS3SecretKey} {
+			if c.asString(key) == "" {
+				return errors.Errorf("%s must be set when %s is %q", key, BackupStorage, BackupStorageS3)
+			}
+		}
+		if u, err := url.Parse(c.BackupS3Endpoint()); err != nil || u.Scheme == "" || u.Host == "" {
+			return errors.Errorf("invalid %s %q: expected a URL", BackupS3Endpoint, c.BackupS3Endpoint())
+		}
+	default:
+		return errors.Errorf("invalid %s %q: expected one of %q, %q or %q", BackupStorage, c.BackupStorage(),
+			BackupStorageMongo, BackupStorageLocal, BackupStorageS3)
+	}
+	return nil
+}
+
 func (c Config) validateSpaceConfig(key, topic string) error {
 	val := c[key]
 	if val == nil {
@@ -795,6 +1016,17 @@ var configChecker = schema.FieldMap(schema.Fields{
 	MaxTxnLogSize:           schema.String(),
 	MaxPruneTxnBatchSize:    schema.ForceInt(),
 	MaxPruneTxnPasses:       schema.ForceInt(),
+	BackupInterval:          schema.String(),
+	BackupKeepDaily:         schema.ForceInt(),
+	BackupKeepWeekly:        schema.ForceInt(),
+	BackupStorage:           schema.String(),
+	BackupStoragePath:       schema.String(),
+	BackupS3Endpoint:        schema.String(),
+	BackupS3Region:          schema.String(),
+	BackupS3Bucket:          schema.String(),
+	BackupS3AccessKey:       schema.String(),
+	BackupS3SecretKey:       schema.String(),
+	BackupPublicKey:         schema.String(),
 	JujuHASpace:             schema.String(),
 	JujuManagementSpace:     schema.String(),
 	CAASOperatorImagePath:   schema.String(),
@@ -823,6 +1055,17 @@ var configChecker = schema.FieldMap(schema.Fields{
 	MaxTxnLogSize:           fmt.Sprintf("%vM", DefaultMaxTxnLogCollectionMB),
 	MaxPruneTxnBatchSize:    DefaultMaxPruneTxnBatchSize,
 	MaxPruneTxnPasses:       DefaultMaxPruneTxnPasses,
+	BackupInterval:          schema.Omit,
+	BackupKeepDaily:         DefaultBackupKeepDaily,
+	BackupKeepWeekly:        DefaultBackupKeepWeekly,
+	BackupStorage:           schema.Omit,
+	BackupStoragePath:       schema.Omit,
+	BackupS3Endpoint:        schema.Omit,
+	BackupS3Region:          schema.Omit,
+	BackupS3Bucket:          schema.Omit,
+	BackupS3AccessKey:       schema.Omit,
+	BackupS3SecretKey:       schema.Omit,
+	BackupPublicKey:         schema.Omit,
 	JujuHASpace:             schema.Omit,
 	JujuManagementSpace:     schema.Omit,
 	CAASOperatorImagePath:   schema.Omit,
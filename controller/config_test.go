@@ -261,6 +261,91 @@ var validateTests = []struct {
 		controller.APIPortOpenDelay: "15",
 	},
 	expectError: `api-port-open-delay value "15" must be a valid duration`,
+}, {
+	about: "backup-interval not a duration",
+	config: controller.Config{
+		controller.CACertKey:      testing.CACert,
+		controller.BackupInterval: "daily",
+	},
+	expectError: `invalid backup interval in configuration: time: invalid duration "?daily"?`,
+}, {
+	about: "backup-interval too short",
+	config: controller.Config{
+		controller.CACertKey:      testing.CACert,
+		controller.BackupInterval: "10m",
+	},
+	expectError: `invalid backup interval in configuration: must be at least 1h, got 10m0s`,
+}, {
+	about: "negative backup-keep-daily",
+	config: controller.Config{
+		controller.CACertKey:       testing.CACert,
+		controller.BackupKeepDaily: -1,
+	},
+	expectError: `invalid backup-keep-daily: should be a non-negative number of backups, got -1`,
+}, {
+	about: "negative backup-keep-weekly",
+	config: controller.Config{
+		controller.CACertKey:        testing.CACert,
+		controller.BackupKeepWeekly: -2,
+	},
+	expectError: `invalid backup-keep-weekly: should be a non-negative number of backups, got -2`,
+}, {
+	about: "unknown backup-storage",
+	config: controller.Config{
+		controller.CACertKey:     testing.CACert,
+		controller.BackupStorage: "tape",
+	},
+	expectError: `invalid backup-storage "tape": expected one of "mongo", "local" or "s3"`,
+}, {
+	about: "local backup-storage without path",
+	config: controller.Config{
+		controller.CACertKey:     testing.CACert,
+		controller.BackupStorage: "local",
+	},
+	expectError: `invalid backup-storage-path "": must be an absolute path when backup-storage is "local"`,
+}, {
+	about: "local backup-storage with relative path",
+	config: controller.Config{
+		controller.CACertKey:         testing.CACert,
+		controller.BackupStorage:     "local",
+		controller.BackupStoragePath: "backups",
+	},
+	expectError: `invalid backup-storage-path "backups": must be an absolute path when backup-storage is "local"`,
+}, {
+	about: "s3 backup-storage without bucket",
+	config: controller.Config{
+		controller.CACertKey:         testing.CACert,
+		controller.BackupStorage:     "s3",
+		controller.BackupS3Endpoint:  "https://s3.example.com",
+		controller.BackupS3AccessKey: "access",
+		controller.BackupS3SecretKey: "secret",
+	},
+	expectError: `backup-s3-bucket must be set when backup-storage is "s3"`,
+}, {
+	about: "s3 backup-storage with invalid endpoint",
+	config: controller.Config{
+		controller.CACertKey:         testing.CACert,
+		controller.BackupStorage:     "s3",
+		controller.BackupS3Endpoint:  "s3.example.com",
+		controller.BackupS3Bucket:    "juju-backups",
+		controller.BackupS3AccessKey: "access",
+		controller.BackupS3SecretKey: "secret",
+	},
+	expectError: `invalid backup-s3-endpoint "s3.example.com": expected a URL`,
+}, {
+	about: "backup-public-key not PEM",
+	config: controller.Config{
+		controller.CACertKey:       testing.CACert,
+		controller.BackupPublicKey: "ssh-rsa AAAA",
+	},
+	expectError: `invalid backup-public-key: backup public key: no PEM data found not valid`,
+}, {
+	about: "backup-public-key not a public key",
+	config: controller.Config{
+		controller.CACertKey:       testing.CACert,
+		controller.BackupPublicKey: testing.CACert,
+	},
+	expectError: `invalid backup-public-key: backup public key with PEM type "CERTIFICATE" not valid`,
 }}
 
 func (s *ConfigSuite) TestValidate(c *gc.C) {
@@ -344,6 +429,54 @@ func (s *ConfigSuite) TestMaxPruneTxnConfigValue(c *gc.C) {
 	c.Check(cfg.MaxPruneTxnPasses(), gc.Equals, 10)
 }
 
+func (s *ConfigSuite) TestBackupConfigDefaults(c *gc.C) {
+	cfg, err := controller.NewConfig(testing.ControllerTag.Id(), testing.CACert, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cfg.BackupInterval(), gc.Equals, time.Duration(0))
+	c.Check(cfg.BackupKeepDaily(), gc.Equals, 7)
+	c.Check(cfg.BackupKeepWeekly(), gc.Equals, 4)
+	c.Check(cfg.BackupStorage(), gc.Equals, controller.BackupStorageMongo)
+	c.Check(cfg.BackupS3Region(), gc.Equals, "us-east-1")
+}
+
+func (s *ConfigSuite) TestBackupConfigValues(c *gc.C) {
+	cfg, err := controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{
+			"backup-interval":    "12h",
+			"backup-keep-daily":  "3",
+			"backup-keep-weekly": 2,
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cfg.BackupInterval(), gc.Equals, 12*time.Hour)
+	c.Check(cfg.BackupKeepDaily(), gc.Equals, 3)
+	c.Check(cfg.BackupKeepWeekly(), gc.Equals, 2)
+}
+
+func (s *ConfigSuite) TestBackupStorageConfigValues(c *gc.C) {
+	cfg, err := controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{
+			"backup-storage":       "s3",
+			"backup-s3-endpoint":   "https://s3.example.com",
+			"backup-s3-region":     "eu-west-2",
+			"backup-s3-bucket":     "juju-backups",
+			"backup-s3-access-key": "access",
+			"backup-s3-secret-key": "secret",
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cfg.BackupStorage(), gc.Equals, controller.BackupStorageS3)
+	c.Check(cfg.BackupS3Endpoint(), gc.Equals, "https://s3.example.com")
+	c.Check(cfg.BackupS3Region(), gc.Equals, "eu-west-2")
+	c.Check(cfg.BackupS3Bucket(), gc.Equals, "juju-backups")
+	c.Check(cfg.BackupS3AccessKey(), gc.Equals, "access")
+	c.Check(cfg.BackupS3SecretKey(), gc.Equals, "secret")
+}
+
 func (s *ConfigSuite) TestNetworkSpaceConfigValues(c *gc.C) {
 	haSpace := "space1"
 	managementSpace := "space2"
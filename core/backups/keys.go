@@ -0,0 +1,65 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package backups holds the parts of backup handling that are shared
+// between the controller config, the backups implementation and the
+// client.
+package backups
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+
+	"github.com/juju/errors"
+)
+
+// ParsePublicKey parses a PEM-encoded RSA public key, in either PKIX
+// or PKCS#1 form, for encrypting backup archives.
+func ParsePublicKey(data []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.NotValidf("backup public key: no PEM data found")
+	}
+	switch block.Type {
+	case "PUBLIC KEY":
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, errors.Annotate(err, "parsing backup public key")
+		}
+		rsaKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return nil, errors.NotValidf("backup public key of type %T (expected RSA)", key)
+		}
+		return rsaKey, nil
+	case "RSA PUBLIC KEY":
+		key, err := x509.ParsePKCS1PublicKey(block.Bytes)
+		return key, errors.Annotate(err, "parsing backup public key")
+	}
+	return nil, errors.NotValidf("backup public key with PEM type %q", block.Type)
+}
+
+// ParsePrivateKey parses a PEM-encoded RSA private key, in either
+// PKCS#8 or PKCS#1 form, for decrypting backup archives.
+func ParsePrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.NotValidf("backup private key: no PEM data found")
+	}
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, errors.Annotate(err, "parsing backup private key")
+		}
+		rsaKey, ok := key.(*rsa.PrivateKey)
+		if !ok {
+			return nil, errors.NotValidf("backup private key of type %T (expected RSA)", key)
+		}
+		return rsaKey, nil
+	case "RSA PRIVATE KEY":
+		key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		return key, errors.Annotate(err, "parsing backup private key")
+	}
+	return nil, errors.NotValidf("backup private key with PEM type %q", block.Type)
+}
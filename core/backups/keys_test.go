@@ -0,0 +1,62 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/backups"
+)
+
+type keysSuite struct {
+	key *rsa.PrivateKey
+}
+
+var _ = gc.Suite(&keysSuite{})
+
+func (s *keysSuite) SetUpSuite(c *gc.C) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	s.key = key
+}
+
+func (s *keysSuite) TestParsePublicKeyPKIX(c *gc.C) {
+	der, err := x509.MarshalPKIXPublicKey(&s.key.PublicKey)
+	c.Assert(err, jc.ErrorIsNil)
+	pub, err := backups.ParsePublicKey(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(pub, jc.DeepEquals, &s.key.PublicKey)
+}
+
+func (s *keysSuite) TestParsePublicKeyPKCS1(c *gc.C) {
+	pub, err := backups.ParsePublicKey(pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PUBLIC KEY",
+		Bytes: x509.MarshalPKCS1PublicKey(&s.key.PublicKey),
+	}))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(pub, jc.DeepEquals, &s.key.PublicKey)
+}
+
+func (s *keysSuite) TestParsePublicKeyInvalid(c *gc.C) {
+	_, err := backups.ParsePublicKey([]byte("not a key"))
+	c.Check(err, gc.ErrorMatches, "backup public key: no PEM data found not valid")
+
+	_, err = backups.ParsePublicKey(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("x")}))
+	c.Check(err, gc.ErrorMatches, `backup public key with PEM type "CERTIFICATE" not valid`)
+}
+
+func (s *keysSuite) TestParsePrivateKey(c *gc.C) {
+	priv, err := backups.ParsePrivateKey(pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(s.key),
+	}))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(priv.D, jc.DeepEquals, s.key.D)
+}
@@ -246,6 +246,7 @@ const (
 	ShowHistory ReportOption = iota
 	ShowStack
 	ShowDetailsYAML
+	ShowContention
 )
 
 func contains(opts []ReportOption, opt ReportOption) bool {
@@ -271,10 +272,25 @@ type reportInfo struct {
 	Stack string `yaml:"stack,omitempty"`
 }
 
+// contentionInfo summarises the lock usage of a single worker and
+// comment combination over the retained history.
+type contentionInfo struct {
+	Worker  string `yaml:"worker"`
+	Comment string `yaml:"comment,omitempty"`
+
+	Acquisitions int `yaml:"acquisitions"`
+
+	TotalWaitTime time.Duration `yaml:"total-wait-time"`
+	MaxWaitTime   time.Duration `yaml:"max-wait-time"`
+	TotalHoldTime time.Duration `yaml:"total-hold-time"`
+	MaxHoldTime   time.Duration `yaml:"max-hold-time"`
+}
+
 type report struct {
-	Holder  interface{}   `yaml:"holder"`
-	Waiting []interface{} `yaml:"waiting,omitempty"`
-	History []interface{} `yaml:"history,omitempty"`
+	Holder     interface{}       `yaml:"holder"`
+	Waiting    []interface{}     `yaml:"waiting,omitempty"`
+	History    []interface{}     `yaml:"history,omitempty"`
+	Contention []*contentionInfo `yaml:"contention,omitempty"`
 }
 
 func (c *lock) Report(opts ...ReportOption) (string, error) {
@@ -301,6 +317,9 @@ func (c *lock) Report(opts ...ReportOption) (string, error) {
 			r.History = append(r.History, displayInfo(v, includeStack, detailsYAML, now))
 		}
 	}
+	if contains(opts, ShowContention) {
+		r.Contention = c.contention()
+	}
 
 	output := map[string]report{c.agent: r}
 	out, err := yaml.Marshal(output)
@@ -310,6 +329,56 @@ func (c *lock) Report(opts ...ReportOption) (string, error) {
 	return string(out), nil
 }
 
+// contention aggregates the lock history by worker and comment, with
+// the entries that have held the lock the longest first. The mutex
+// must be held by the caller.
+func (c *lock) contention() []*contentionInfo {
+	type key struct {
+		worker  string
+		comment string
+	}
+	byKey := make(map[key]*contentionInfo)
+	var result []*contentionInfo
+	iter := c.history.Iterator()
+	var v *info
+	for iter.Next(&v) {
+		k := key{worker: v.worker, comment: v.comment}
+		entry, ok := byKey[k]
+		if !ok {
+			entry = &contentionInfo{Worker: v.worker, Comment: v.comment}
+			byKey[k] = entry
+			result = append(result, entry)
+		}
+		wait := v.acquired.Sub(v.requested)
+		hold := v.released.Sub(v.acquired)
+		entry.Acquisitions++
+		entry.TotalWaitTime += wait
+		entry.TotalHoldTime += hold
+		if wait > entry.MaxWaitTime {
+			entry.MaxWaitTime = wait
+		}
+		if hold > entry.MaxHoldTime {
+			entry.MaxHoldTime = hold
+		}
+	}
+	for _, entry := range result {
+		entry.TotalWaitTime = entry.TotalWaitTime.Round(time.Second)
+		entry.MaxWaitTime = entry.MaxWaitTime.Round(time.Second)
+		entry.TotalHoldTime = entry.TotalHoldTime.Round(time.Second)
+		entry.MaxHoldTime = entry.MaxHoldTime.Round(time.Second)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].TotalHoldTime != result[j].TotalHoldTime {
+			return result[i].TotalHoldTime > result[j].TotalHoldTime
+		}
+		if result[i].Worker != result[j].Worker {
+			return result[i].Worker < result[j].Worker
+		}
+		return result[i].Comment < result[j].Comment
+	})
+	return result
+}
+
 func sortedKeys(m map[int]*info) []int {
 	values := make([]int, 0, len(m))
 	for key := range m {
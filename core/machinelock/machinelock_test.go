@@ -200,6 +200,73 @@ test:
 `[1:])
 }
 
+func (s *lockSuite) TestContentionOutput(c *gc.C) {
+	short := 5 * time.Second
+	long := 2*time.Minute + short
+	s.addHistory(c, "uniter", "update-status", "2018-07-21 15:37:05", time.Second, short)
+	s.addHistory(c, "uniter", "config-changed", "2018-07-21 15:40:01", 3*time.Second, long)
+	s.addHistory(c, "uniter", "update-status", "2018-07-21 15:42:11", 10*time.Second, short)
+	s.addHistory(c, "reboot", "", "2018-07-21 15:43:00", time.Second, short)
+
+	output, err := s.lock.Report(machinelock.ShowContention)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(output, gc.Equals, `
+test:
+  holder: none
+  contention:
+  - worker: uniter
+    comment: config-changed
+    acquisitions: 1
+    total-wait-time: 3s
+    max-wait-time: 3s
+    total-hold-time: 2m5s
+    max-hold-time: 2m5s
+  - worker: uniter
+    comment: update-status
+    acquisitions: 2
+    total-wait-time: 11s
+    max-wait-time: 10s
+    total-hold-time: 10s
+    max-hold-time: 5s
+  - worker: reboot
+    acquisitions: 1
+    total-wait-time: 1s
+    max-wait-time: 1s
+    total-hold-time: 5s
+    max-hold-time: 5s
+`[1:])
+}
+
+func (s *lockSuite) TestContentionOutputNoHistory(c *gc.C) {
+	output, err := s.lock.Report(machinelock.ShowContention)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(output, gc.Equals, `
+test:
+  holder: none
+`[1:])
+}
+
+func (s *lockSuite) TestContentionAndHistoryOutput(c *gc.C) {
+	s.addHistory(c, "uniter", "update-status", "2018-07-21 15:37:05", time.Second, 5*time.Second)
+
+	output, err := s.lock.Report(machinelock.ShowContention, machinelock.ShowHistory)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(output, gc.Equals, `
+test:
+  holder: none
+  history:
+  - 2018-07-21 15:37:05 uniter (update-status), waited 1s, held 5s
+  contention:
+  - worker: uniter
+    comment: update-status
+    acquisitions: 1
+    total-wait-time: 1s
+    max-wait-time: 1s
+    total-hold-time: 5s
+    max-hold-time: 5s
+`[1:])
+}
+
 func (s *lockSuite) TestLogfileOutput(c *gc.C) {
 	short := 5 * time.Second
 	long := 2*time.Minute + short
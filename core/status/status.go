@@ -219,6 +219,11 @@ const (
 	Provisioning      Status = "allocating"
 	Running           Status = "running"
 	ProvisioningError Status = "provisioning error"
+
+	// Warning indicates that the instance is running, but that the
+	// cloud has reported something the user should know about, such
+	// as that the instance is about to be reclaimed.
+	Warning Status = "warning"
 )
 
 const (
@@ -236,6 +241,7 @@ func (status Status) KnownInstanceStatus() bool {
 		ProvisioningError,
 		Allocating,
 		Running,
+		Warning,
 		Error,
 		Unknown:
 		return true
@@ -192,6 +192,10 @@ const (
 	// automatically retry a hook that has failed
 	AutomaticallyRetryHooks = "automatically-retry-hooks"
 
+	// CaptureFailedHooks determines whether the uniter will record the
+	// context of a hook that has failed, so that it can be replayed.
+	CaptureFailedHooks = "capture-failed-hooks"
+
 	// TransmitVendorMetricsKey is the key for whether the controller sends
 	// metrics collected in this model for anonymized aggregate analytics.
 	TransmitVendorMetricsKey = "transmit-vendor-metrics"
@@ -216,6 +220,11 @@ const (
 	// grow to before it is pruned, eg "5M"
 	MaxActionResultsSize = "max-action-results-size"
 
+	// ActionResultsRetention overrides MaxActionResultsAge for actions
+	// with particular names, eg "backup=2160h,status=24h"; a duration
+	// of 0 disables age pruning for that action
+	ActionResultsRetention = "action-results-retention"
+
 	// UpdateStatusHookInterval is how often to run the update-status hook.
 	UpdateStatusHookInterval = "update-status-hook-interval"
 
@@ -628,6 +637,12 @@ func Validate(cfg, old *Config) error {
 		}
 	}
 
+	if v, ok := cfg.defined[ActionResultsRetention].(string); ok {
+		if _, err := parseActionResultsRetention(v); err != nil {
+			return errors.Annotate(err, "invalid action results retention in model configuration")
+		}
+	}
+
 	if v, ok := cfg.defined[UpdateStatusHookInterval].(string); ok {
 		if f, err := time.ParseDuration(v); err != nil {
 			return errors.Annotate(err, "invalid update status hook interval in model configuration")
@@ -1169,6 +1184,13 @@ func (c *Config) AutomaticallyRetryHooks() bool {
 	}
 }
 
+// CaptureFailedHooks returns whether the context of failed hooks should be
+// captured for replay. By default this is false.
+func (c *Config) CaptureFailedHooks() bool {
+	val, _ := c.defined[CaptureFailedHooks].(bool)
+	return val
+}
+
 // TransmitVendorMetrics returns whether the controller sends charm-collected metrics
 // in this model for anonymized aggregate analytics. By default this should be true.
 func (c *Config) TransmitVendorMetrics() bool {
@@ -1267,12 +1289,27 @@ func (c *Config) StorageDefaultFilesystemSource() (string, bool) {
 // that Juju creates and manages, if the provider supports them. These
 // tags have no special meaning to Juju, but may be used for existing
 // chargeback accounting schemes or other identification purposes.
+//
+// Tag values may refer to template variables such as ${units}; the
+// model and model-uuid variables are expanded here, the rest when
+// the tags are applied to a particular resource.
 func (c *Config) ResourceTags() (map[string]string, bool) {
-	tags, err := c.resourceTags()
+	resourceTags, err := c.resourceTags()
 	if err != nil {
 		panic(err) // should be prevented by Validate
 	}
-	return tags, tags != nil
+	if resourceTags == nil {
+		return nil, false
+	}
+	vars := tags.TemplateVars{
+		tags.TemplateModel:     c.Name(),
+		tags.TemplateModelUUID: c.UUID(),
+	}
+	expanded := make(map[string]string, len(resourceTags))
+	for k, v := range resourceTags {
+		expanded[k] = tags.ExpandTemplate(v, vars)
+	}
+	return expanded, true
 }
 
 func (c *Config) resourceTags() (map[string]string, error) {
@@ -1280,10 +1317,13 @@ func (c *Config) resourceTags() (map[string]string, error) {
 	if !ok {
 		return nil, nil
 	}
-	for k := range v {
+	for k, value := range v {
 		if strings.HasPrefix(k, tags.JujuTagPrefix) {
 			return nil, errors.Errorf("tag %q uses reserved prefix %q", k, tags.JujuTagPrefix)
 		}
+		if err := tags.ValidateTemplate(value); err != nil {
+			return nil, errors.Annotatef(err, "tag %q", k)
+		}
 	}
 	return v, nil
 }
@@ -1316,6 +1356,41 @@ func (c *Config) MaxActionResultsSizeMB() uint {
 	return uint(val)
 }
 
+// ActionResultsRetention returns the maximum age of the results of
+// actions with particular names, keyed on action name. Results of
+// actions not named here are kept for MaxActionResultsAge. A zero
+// age means the results of that action are only pruned by size.
+func (c *Config) ActionResultsRetention() map[string]time.Duration {
+	// Value has already been validated.
+	val, _ := parseActionResultsRetention(c.asString(ActionResultsRetention))
+	return val
+}
+
+// parseActionResultsRetention parses a comma separated list of
+// action-name=duration pairs.
+func parseActionResultsRetention(value string) (map[string]time.Duration, error) {
+	result := make(map[string]time.Duration)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, errors.Errorf("expected action-name=duration, got %q", entry)
+		}
+		age, err := time.ParseDuration(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, errors.Annotatef(err, "action %q", strings.TrimSpace(parts[0]))
+		}
+		if age < 0 {
+			return nil, errors.Errorf("action %q: negative duration %q", strings.TrimSpace(parts[0]), parts[1])
+		}
+		result[strings.TrimSpace(parts[0])] = age
+	}
+	return result, nil
+}
+
 // UpdateStatusHookInterval is how often to run the charm
 // update-status hook.
 func (c *Config) UpdateStatusHookInterval() time.Duration {
@@ -1485,6 +1560,7 @@ var alwaysOptional = schema.Defaults{
 	"disable-network-management": schema.Omit,
 	IgnoreMachineAddresses:       schema.Omit,
 	AutomaticallyRetryHooks:      schema.Omit,
+	CaptureFailedHooks:           schema.Omit,
 	"test-mode":                  schema.Omit,
 	TransmitVendorMetricsKey:     schema.Omit,
 	NetBondReconfigureDelayKey:   schema.Omit,
@@ -1493,6 +1569,7 @@ var alwaysOptional = schema.Defaults{
 	MaxStatusHistorySize:         schema.Omit,
 	MaxActionResultsAge:          schema.Omit,
 	MaxActionResultsSize:         schema.Omit,
+	ActionResultsRetention:       schema.Omit,
 	UpdateStatusHookInterval:     schema.Omit,
 	EgressSubnets:                schema.Omit,
 	FanConfig:                    schema.Omit,
@@ -1869,7 +1946,7 @@ global or per instance security groups.`,
 		Group:       environschema.EnvironGroup,
 	},
 	ResourceTagsKey: {
-		Description: "resource tags",
+		Description: `Tags to set on the cloud resources Juju creates. Values may refer to ${model}, ${model-uuid}, ${machine}, ${applications}, ${units} and ${storage}`,
 		Type:        environschema.Tattrs,
 		Group:       environschema.EnvironGroup,
 	},
@@ -1938,6 +2015,11 @@ data of the store. (default false)`,
 		Type:        environschema.Tbool,
 		Group:       environschema.EnvironGroup,
 	},
+	CaptureFailedHooks: {
+		Description: "Determines whether the uniter should capture the context of failed hooks so they can be replayed with juju replay-hook",
+		Type:        environschema.Tbool,
+		Group:       environschema.EnvironGroup,
+	},
 	TransmitVendorMetricsKey: {
 		Description: "Determines whether metrics declared by charms deployed into this model are sent for anonymized aggregate analytics",
 		Type:        environschema.Tbool,
@@ -1973,6 +2055,11 @@ data of the store. (default false)`,
 		Type:        environschema.Tstring,
 		Group:       environschema.EnvironGroup,
 	},
+	ActionResultsRetention: {
+		Description: "The maximum age for entries of particular actions before they are pruned, as a comma separated list of action-name=duration, overriding max-action-results-age; a duration of 0 disables age pruning for that action",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
 	UpdateStatusHookInterval: {
 		Description: "How often to run the charm update-status hook, in human-readable time format (default 5m, range 1-60m)",
 		Type:        environschema.Tstring,
@@ -463,6 +463,20 @@ var configTests = []configTest{
 			"resource-tags": []string{"a"},
 		}),
 		err: `resource-tags: expected "key=value", got "a"`,
+	}, {
+		about:       "Resource tags with unknown template variable",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"resource-tags": []string{"owner=${charm}"},
+		}),
+		err: `validating resource tags: tag "owner": template variable "charm" not valid`,
+	}, {
+		about:       "Resource tags with unterminated template variable",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"resource-tags": []string{"owner=${units"},
+		}),
+		err: `validating resource tags: tag "owner": unterminated variable in "\$\{units"`,
 	}, {
 		about:       "Invalid syslog ca cert format",
 		useDefaults: config.UseDefaults,
@@ -1114,6 +1128,17 @@ func (s *ConfigSuite) TestAutoHookRetryTrueEnv(c *gc.C) {
 	c.Assert(config.AutomaticallyRetryHooks(), gc.Equals, true)
 }
 
+func (s *ConfigSuite) TestCaptureFailedHooksDefault(c *gc.C) {
+	config := newTestConfig(c, testing.Attrs{})
+	c.Assert(config.CaptureFailedHooks(), jc.IsFalse)
+}
+
+func (s *ConfigSuite) TestCaptureFailedHooks(c *gc.C) {
+	config := newTestConfig(c, testing.Attrs{
+		"capture-failed-hooks": "true"})
+	c.Assert(config.CaptureFailedHooks(), jc.IsTrue)
+}
+
 func (s *ConfigSuite) TestNoBothProxy(c *gc.C) {
 	config := newTestConfig(c, testing.Attrs{
 		"http-proxy":  "http://user@10.0.0.1",
@@ -1306,6 +1331,32 @@ func (s *ConfigSuite) TestStatusHistoryConfigValues(c *gc.C) {
 	c.Assert(cfg.MaxStatusHistorySizeMB(), gc.Equals, uint(8192))
 }
 
+func (s *ConfigSuite) TestActionResultsRetentionDefault(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{})
+	c.Assert(cfg.ActionResultsRetention(), gc.HasLen, 0)
+}
+
+func (s *ConfigSuite) TestActionResultsRetention(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{
+		"action-results-retention": "backup=2160h, status=24h",
+	})
+	c.Assert(cfg.ActionResultsRetention(), jc.DeepEquals, map[string]time.Duration{
+		"backup": 2160 * time.Hour,
+		"status": 24 * time.Hour,
+	})
+}
+
+func (s *ConfigSuite) TestActionResultsRetentionInvalid(c *gc.C) {
+	for _, value := range []string{"backup", "=24h", "backup=forever", "backup=-1h"} {
+		_, err := config.New(config.UseDefaults, testing.Attrs{
+			"type": "my-type", "name": "my-name",
+			"uuid":                     testing.ModelTag.Id(),
+			"action-results-retention": value,
+		})
+		c.Check(err, gc.ErrorMatches, "invalid action results retention in model configuration: .*")
+	}
+}
+
 func (s *ConfigSuite) TestUpdateStatusHookIntervalConfigDefault(c *gc.C) {
 	cfg := newTestConfig(c, testing.Attrs{})
 	c.Assert(cfg.UpdateStatusHookInterval(), gc.Equals, 5*time.Minute)
@@ -1404,6 +1455,24 @@ func (s *ConfigSuite) TestCoerceForStorage(c *gc.C) {
 	c.Assert(tagsMap, gc.DeepEquals, expectedTags)
 }
 
+func (s *ConfigSuite) TestResourceTagsTemplates(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{
+		"resource-tags": "cost-centre=${model} owner=${units} id=${model-uuid}"})
+	tags, ok := cfg.ResourceTags()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(tags, gc.DeepEquals, map[string]string{
+		"cost-centre": "my-name",
+		"owner":       "${units}",
+		"id":          testing.ModelTag.Id(),
+	})
+
+	// The templates themselves are stored.
+	tagsStr := config.CoerceForStorage(cfg.AllAttrs())["resource-tags"].(string)
+	c.Assert(strings.Fields(tagsStr), jc.SameContents, []string{
+		"cost-centre=${model}", "owner=${units}", "id=${model-uuid}",
+	})
+}
+
 var specializeCharmRepoTests = []struct {
 	about    string
 	testMode bool
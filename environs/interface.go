@@ -492,7 +492,9 @@ type InstanceTagger interface {
 	// TagInstance tags the given instance with the specified tags.
 	//
 	// The specified tags will replace any existing ones with the
-	// same names, but other existing tags will be left alone.
+	// same names. Existing tags that Juju set, but which are not
+	// among those specified, will be removed, as determined by
+	// tags.RemovedTags; other existing tags will be left alone.
 	TagInstance(ctx context.ProviderCallContext, id instance.Id, tags map[string]string) error
 }
 
@@ -30,6 +30,21 @@ type ProvisionMachineArgs struct {
 	Host string
 	User string
 
+	// IdentityFile, if set, is the path of the private key with which
+	// to log in to the host when initialising it. If empty, the ssh
+	// client's default keys are used.
+	IdentityFile string
+
+	// NonInteractive, if true, means nobody is available to answer
+	// prompts. Hosts are then initialised without password
+	// authentication or a PTY, so a host that would prompt for a
+	// password fails instead of blocking.
+	NonInteractive bool
+
+	// Series, if set, is the series the host is expected to be running.
+	// Provisioning fails if the host is running any other series.
+	Series string
+
 	// DataDir is the root directory for juju data.
 	// If left blank, the default location "/var/lib/juju" will be used.
 	DataDir string
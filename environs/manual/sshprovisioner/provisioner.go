@@ -5,6 +5,7 @@
 package sshprovisioner
 
 import (
+	"github.com/juju/errors"
 	"github.com/juju/loggo"
 
 	"github.com/juju/juju/apiserver/params"
@@ -32,8 +33,8 @@ func ProvisionMachine(args manual.ProvisionMachineArgs) (machineId string, err e
 	// the ubuntu user's authorized_keys file with the public keys in the current
 	// user's ~/.ssh directory. The authenticationworker will later update the
 	// ubuntu user's authorized_keys.
-	if err = InitUbuntuUser(args.Host, args.User,
-		args.AuthorizedKeys, args.Stdin, args.Stdout); err != nil {
+	if err = initUbuntuUser(args.Host, args.User, args.IdentityFile,
+		args.AuthorizedKeys, args.NonInteractive, args.Stdin, args.Stdout); err != nil {
 		return "", err
 	}
 
@@ -41,6 +42,9 @@ func ProvisionMachine(args manual.ProvisionMachineArgs) (machineId string, err e
 	if err != nil {
 		return "", err
 	}
+	if args.Series != "" && machineParams.Series != args.Series {
+		return "", errors.Errorf("host is running %q, not %q", machineParams.Series, args.Series)
+	}
 
 	// Inform Juju that the machine exists.
 	machineId, err = manual.RecordMachineInState(args.Client, *machineParams)
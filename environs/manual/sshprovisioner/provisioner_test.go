@@ -127,6 +127,22 @@ func (s *provisionerSuite) TestProvisionMachine(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "error checking if provisioned: subprocess encountered error code 255")
 }
 
+func (s *provisionerSuite) TestProvisionMachineSeriesMismatch(c *gc.C) {
+	var series = jujuversion.SupportedLTS()
+	defer fakeSSH{
+		Series:             series,
+		Arch:               "amd64",
+		InitUbuntuUser:     true,
+		SkipProvisionAgent: true,
+	}.install(c).Restore()
+
+	args := s.getArgs(c)
+	args.Series = "not-" + series
+	machineId, err := sshprovisioner.ProvisionMachine(args)
+	c.Assert(err, gc.ErrorMatches, fmt.Sprintf("host is running %q, not %q", series, args.Series))
+	c.Assert(machineId, gc.Equals, "")
+}
+
 func (s *provisionerSuite) TestFinishInstancConfig(c *gc.C) {
 	var series = jujuversion.SupportedLTS()
 	const arch = "amd64"
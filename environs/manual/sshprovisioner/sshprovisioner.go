@@ -40,6 +40,14 @@ import (
 // authorizedKeys may be empty, in which case the file
 // will be created and left empty.
 func InitUbuntuUser(host, login, authorizedKeys string, read io.Reader, write io.Writer) error {
+	return initUbuntuUser(host, login, "", authorizedKeys, false, read, write)
+}
+
+// initUbuntuUser is InitUbuntuUser, logging in with the private key in
+// identityFile if it is not empty. If nonInteractive is true, password
+// authentication and PTY allocation are left disabled, so that ssh or
+// sudo fail rather than prompt.
+func initUbuntuUser(host, login, identityFile, authorizedKeys string, nonInteractive bool, read io.Reader, write io.Writer) error {
 	logger.Infof("initialising %q, user %q", host, login)
 
 	// To avoid unnecessary prompting for the specified login,
@@ -49,7 +57,12 @@ func InitUbuntuUser(host, login, authorizedKeys string, read io.Reader, write io
 	//
 	// Note that we explicitly do not allocate a PTY, so we
 	// get a failure if sudo prompts.
-	cmd := ssh.Command("ubuntu@"+host, []string{"sudo", "-n", "true"}, nil)
+	var ubuntuOptions *ssh.Options
+	if identityFile != "" {
+		ubuntuOptions = &ssh.Options{}
+		ubuntuOptions.SetIdentities(identityFile)
+	}
+	cmd := ssh.Command("ubuntu@"+host, []string{"sudo", "-n", "true"}, ubuntuOptions)
 	if cmd.Run() == nil {
 		logger.Infof("ubuntu user is already initialised")
 		return nil
@@ -62,8 +75,13 @@ func InitUbuntuUser(host, login, authorizedKeys string, read io.Reader, write io
 	}
 	script := fmt.Sprintf(initUbuntuScript, utils.ShQuote(authorizedKeys))
 	var options ssh.Options
-	options.AllowPasswordAuthentication()
-	options.EnablePTY()
+	if !nonInteractive {
+		options.AllowPasswordAuthentication()
+		options.EnablePTY()
+	}
+	if identityFile != "" {
+		options.SetIdentities(identityFile)
+	}
 	cmd = ssh.Command(host, []string{"sudo", "/bin/bash -c " + utils.ShQuote(script)}, &options)
 	var stderr bytes.Buffer
 	cmd.Stdin = read
@@ -3,7 +3,13 @@
 
 package tags
 
-import "gopkg.in/juju/names.v2"
+import (
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+)
 
 const (
 	// JujuTagPrefix is the prefix for Juju-managed tags.
@@ -40,8 +46,120 @@ const (
 	// the model and machine id corresponding to the
 	// provisioned machine instance.
 	JujuMachine = JujuTagPrefix + "machine-id"
+
+	// JujuManagedTags is the tag name used for recording the
+	// names of the user-defined tags that Juju set on a machine
+	// instance, so that they can be removed from the instance if
+	// they are dropped from the model's resource-tags. The value
+	// is a space-separated list of the tag names.
+	JujuManagedTags = JujuTagPrefix + "managed-tags"
 )
 
+// instanceTagNames holds the names of the Juju tags that may be set
+// on a machine instance.
+var instanceTagNames = []string{
+	JujuModel,
+	JujuController,
+	JujuIsController,
+	JujuUnitsDeployed,
+	JujuMachine,
+	JujuManagedTags,
+}
+
+// Template variables that may be used in the values of user-defined
+// resource tags, written as ${name}. Each variable is expanded to the
+// value appropriate to the resource being tagged; variables that do not
+// apply to a resource expand to the empty string.
+const (
+	// TemplateModel expands to the name of the model.
+	TemplateModel = "model"
+
+	// TemplateModelUUID expands to the UUID of the model.
+	TemplateModelUUID = "model-uuid"
+
+	// TemplateMachine expands to the id of the machine that
+	// an instance was provisioned for.
+	TemplateMachine = "machine"
+
+	// TemplateApplications expands to a space-separated list
+	// of the applications with units on a machine, or the
+	// application that owns a storage instance.
+	TemplateApplications = "applications"
+
+	// TemplateUnits expands to a space-separated list of the
+	// principal units on a machine, or the unit that owns a
+	// storage instance.
+	TemplateUnits = "units"
+
+	// TemplateStorage expands to the id of the storage instance
+	// that a volume or filesystem is assigned to.
+	TemplateStorage = "storage"
+)
+
+var templateVariables = map[string]bool{
+	TemplateModel:        true,
+	TemplateModelUUID:    true,
+	TemplateMachine:      true,
+	TemplateApplications: true,
+	TemplateUnits:        true,
+	TemplateStorage:      true,
+}
+
+// TemplateVars holds the values of template variables, keyed
+// by variable name.
+type TemplateVars map[string]string
+
+// ValidateTemplate returns an error if the given tag value is not
+// a valid template: every "${" must be closed by a "}", and must name
+// a known template variable.
+func ValidateTemplate(value string) error {
+	_, err := expandTemplate(value, nil, false)
+	return err
+}
+
+// ExpandTemplate returns the given tag value with the template
+// variables defined in vars replaced by their values. Variables
+// not defined in vars are left untouched. The value must already
+// have been validated with ValidateTemplate.
+func ExpandTemplate(value string, vars TemplateVars) string {
+	result, err := expandTemplate(value, vars, false)
+	if err != nil {
+		return value
+	}
+	return result
+}
+
+// expandTemplate replaces the template variables in value with
+// their values in vars. If clear is true, variables that are not
+// defined in vars are replaced with the empty string.
+func expandTemplate(value string, vars TemplateVars, clear bool) (string, error) {
+	var result []string
+	for {
+		start := strings.Index(value, "${")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(value[start:], "}")
+		if end < 0 {
+			return "", errors.Errorf("unterminated variable in %q", value)
+		}
+		end += start
+		name := value[start+2 : end]
+		if !templateVariables[name] {
+			return "", errors.NotValidf("template variable %q", name)
+		}
+		result = append(result, value[:start])
+		if v, ok := vars[name]; ok || clear {
+			result = append(result, v)
+		} else {
+			result = append(result, value[start:end+1])
+		}
+		value = value[end+1:]
+	}
+	result = append(result, value)
+	return strings.Join(result, ""), nil
+}
+
 // ResourceTagger is an interface that can provide resource tags.
 type ResourceTagger interface {
 	// ResourceTags returns a set of resource tags, and a
@@ -53,6 +171,24 @@ type ResourceTagger interface {
 // ResourceTags returns tags to set on an infrastructure resource
 // for the specified Juju environment.
 func ResourceTags(modelTag names.ModelTag, controllerTag names.ControllerTag, taggers ...ResourceTagger) map[string]string {
+	return ResourceTagsWithVars(modelTag, controllerTag, nil, taggers...)
+}
+
+// ResourceTagsWithVars returns tags to set on an infrastructure
+// resource for the specified Juju environment, expanding any template
+// variables in the tag values with the values in vars. The model-uuid
+// variable is always defined; any other variables missing from vars
+// expand to the empty string.
+func ResourceTagsWithVars(
+	modelTag names.ModelTag,
+	controllerTag names.ControllerTag,
+	vars TemplateVars,
+	taggers ...ResourceTagger,
+) map[string]string {
+	allVars := TemplateVars{TemplateModelUUID: modelTag.Id()}
+	for k, v := range vars {
+		allVars[k] = v
+	}
 	allTags := make(map[string]string)
 	for _, tagger := range taggers {
 		tags, ok := tagger.ResourceTags()
@@ -60,6 +196,9 @@ func ResourceTags(modelTag names.ModelTag, controllerTag names.ControllerTag, ta
 			continue
 		}
 		for k, v := range tags {
+			if expanded, err := expandTemplate(v, allVars, true); err == nil {
+				v = expanded
+			}
 			allTags[k] = v
 		}
 	}
@@ -67,3 +206,40 @@ func ResourceTags(modelTag names.ModelTag, controllerTag names.ControllerTag, ta
 	allTags[JujuController] = controllerTag.Id()
 	return allTags
 }
+
+// RecordManagedTags sets JujuManagedTags in the given machine instance
+// tags to record the names of the user-defined tags among them.
+func RecordManagedTags(instanceTags map[string]string) {
+	var names []string
+	for name := range instanceTags {
+		if !strings.HasPrefix(name, JujuTagPrefix) {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return
+	}
+	sort.Strings(names)
+	instanceTags[JujuManagedTags] = strings.Join(names, " ")
+}
+
+// RemovedTags returns the names of the tags that Juju set on a machine
+// instance, which has the existing tags, that are not among the tags
+// it should now have. Tags that Juju did not set are never included.
+func RemovedTags(existing, instanceTags map[string]string) []string {
+	managed := make(map[string]bool)
+	for _, name := range instanceTagNames {
+		managed[name] = true
+	}
+	for _, name := range strings.Fields(existing[JujuManagedTags]) {
+		managed[name] = true
+	}
+	var removed []string
+	for name := range existing {
+		if _, ok := instanceTags[name]; !ok && managed[name] {
+			removed = append(removed, name)
+		}
+	}
+	sort.Strings(removed)
+	return removed
+}
@@ -61,6 +61,86 @@ func (*tagsSuite) TestResourceTagsResourceTaggers(c *gc.C) {
 	})
 }
 
+func (*tagsSuite) TestResourceTagsWithVars(c *gc.C) {
+	tagger := resourceTagger(func() (map[string]string, bool) {
+		return map[string]string{
+			"owner":   "${units}",
+			"billing": "${model-uuid}/${machine}",
+			"storage": "${storage}",
+			"plain":   "$5",
+		}, true
+	})
+	tags := tags.ResourceTagsWithVars(testing.ModelTag, testing.ControllerTag, tags.TemplateVars{
+		tags.TemplateMachine: "2",
+		tags.TemplateUnits:   "mysql/0 wordpress/1",
+	}, tagger)
+	c.Assert(tags, jc.DeepEquals, map[string]string{
+		"juju-model-uuid":      testing.ModelTag.Id(),
+		"juju-controller-uuid": testing.ControllerTag.Id(),
+		"owner":                "mysql/0 wordpress/1",
+		"billing":              testing.ModelTag.Id() + "/2",
+		"storage":              "",
+		"plain":                "$5",
+	})
+}
+
+func (*tagsSuite) TestValidateTemplate(c *gc.C) {
+	for _, value := range []string{
+		"", "plain", "$", "${model}", "${model}-${machine}", "${applications}${units}${storage}",
+	} {
+		c.Check(tags.ValidateTemplate(value), jc.ErrorIsNil, gc.Commentf("%q", value))
+	}
+	c.Check(tags.ValidateTemplate("${charm}"), gc.ErrorMatches, `template variable "charm" not valid`)
+	c.Check(tags.ValidateTemplate("x-${model"), gc.ErrorMatches, `unterminated variable in "x-\$\{model"`)
+}
+
+func (*tagsSuite) TestExpandTemplate(c *gc.C) {
+	vars := tags.TemplateVars{tags.TemplateModel: "prod"}
+	c.Assert(tags.ExpandTemplate("${model}-${units}", vars), gc.Equals, "prod-${units}")
+	c.Assert(tags.ExpandTemplate("no variables", vars), gc.Equals, "no variables")
+}
+
+func (*tagsSuite) TestRecordManagedTags(c *gc.C) {
+	instanceTags := map[string]string{
+		"juju-model-uuid": testing.ModelTag.Id(),
+		"owner":           "mysql/0",
+		"billing":         "",
+	}
+	tags.RecordManagedTags(instanceTags)
+	c.Assert(instanceTags, jc.DeepEquals, map[string]string{
+		"juju-model-uuid":   testing.ModelTag.Id(),
+		"juju-managed-tags": "billing owner",
+		"owner":             "mysql/0",
+		"billing":           "",
+	})
+
+	instanceTags = map[string]string{"juju-model-uuid": testing.ModelTag.Id()}
+	tags.RecordManagedTags(instanceTags)
+	c.Assert(instanceTags, jc.DeepEquals, map[string]string{"juju-model-uuid": testing.ModelTag.Id()})
+}
+
+func (*tagsSuite) TestRemovedTags(c *gc.C) {
+	existing := map[string]string{
+		"Name":                "juju-machine-0",
+		"juju-model-uuid":     testing.ModelTag.Id(),
+		"juju-units-deployed": "mysql/0",
+		"juju-managed-tags":   "billing owner",
+		"juju-spot":           "true",
+		"owner":               "mysql/0",
+		"billing":             "",
+		"cost-centre":         "1234",
+	}
+	c.Assert(tags.RemovedTags(existing, map[string]string{
+		"juju-model-uuid":   testing.ModelTag.Id(),
+		"juju-managed-tags": "owner",
+		"owner":             "",
+	}), jc.DeepEquals, []string{"billing", "juju-units-deployed"})
+	c.Assert(tags.RemovedTags(existing, map[string]string{
+		"juju-model-uuid": testing.ModelTag.Id(),
+	}), jc.DeepEquals, []string{"billing", "juju-managed-tags", "juju-units-deployed", "owner"})
+	c.Assert(tags.RemovedTags(existing, existing), gc.HasLen, 0)
+}
+
 func testResourceTags(c *gc.C, controller names.ControllerTag, model names.ModelTag, taggers []tags.ResourceTagger, expectTags map[string]string) {
 	tags := tags.ResourceTags(model, controller, taggers...)
 	c.Assert(tags, jc.DeepEquals, expectTags)
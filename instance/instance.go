@@ -42,6 +42,13 @@ type Instance interface {
 	Addresses(context.ProviderCallContext) ([]network.Address, error)
 }
 
+// InterruptibleInstance is implemented by instances that the cloud may
+// reclaim at short notice, such as those started on spare capacity.
+type InterruptibleInstance interface {
+	// Interruptible reports whether the cloud may reclaim the instance.
+	Interruptible() bool
+}
+
 // InstanceFirewaller provides instance-level firewall functionality
 type InstanceFirewaller interface {
 	// OpenPorts opens the given port ranges on the instance, which
@@ -212,7 +212,7 @@ func FillInStartInstanceParams(env environs.Environ, machineId string, isControl
 		instanceConfig.Jobs = []multiwatcher.MachineJob{multiwatcher.JobHostUnits, multiwatcher.JobManageModel}
 	}
 	cfg := env.Config()
-	instanceConfig.Tags = instancecfg.InstanceTags(env.Config().UUID(), params.ControllerUUID, cfg, nil)
+	instanceConfig.Tags = instancecfg.InstanceTags(env.Config().UUID(), params.ControllerUUID, cfg, nil, nil)
 	params.Tools = possibleTools
 	params.InstanceConfig = instanceConfig
 	if params.StatusCallback == nil {
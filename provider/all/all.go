@@ -16,6 +16,7 @@ import (
 	_ "github.com/juju/juju/provider/manual"
 	_ "github.com/juju/juju/provider/oci"
 	_ "github.com/juju/juju/provider/openstack"
+	_ "github.com/juju/juju/provider/proxmox"
 	_ "github.com/juju/juju/provider/rackspace"
 	_ "github.com/juju/juju/provider/vsphere"
 )
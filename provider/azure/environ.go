@@ -123,6 +123,7 @@ type azureEnviron struct {
 }
 
 var _ environs.Environ = (*azureEnviron)(nil)
+var _ environs.InstanceTagger = (*azureEnviron)(nil)
 
 // newEnviron creates a new azureEnviron.
 func newEnviron(
@@ -381,7 +382,12 @@ func (env *azureEnviron) ConstraintsValidator(ctx context.ProviderCallContext) (
 		constraints.CpuPower,
 		constraints.Tags,
 		constraints.VirtType,
+		constraints.Spot,
+		constraints.SpotMaxPrice,
+		constraints.AntiAffinity,
+		constraints.RootDiskSource,
 	})
+	validator.RegisterRejected([]string{constraints.InstanceRole})
 	validator.RegisterVocabulary(
 		constraints.Arch,
 		[]string{arch.AMD64},
@@ -1527,6 +1533,41 @@ func (env *azureEnviron) updateResourceControllerTag(
 	return errorutils.HandleCredentialError(errors.Annotatef(err, "updating controller for %q", to.String(resource.Name)), ctx)
 }
 
+// TagInstance implements environs.InstanceTagger.
+func (env *azureEnviron) TagInstance(ctx context.ProviderCallContext, id instance.Id, instanceTags map[string]string) error {
+	vmClient := compute.VirtualMachinesClient{env.compute}
+	sdkCtx := stdcontext.Background()
+	vmName := string(id)
+	vm, err := vmClient.Get(sdkCtx, env.resourceGroup, vmName, "")
+	if err != nil {
+		return errorutils.HandleCredentialError(errors.Annotatef(err, "getting virtual machine %q", vmName), ctx)
+	}
+	existing := make(map[string]string)
+	for k, v := range vm.Tags {
+		existing[k] = to.String(v)
+	}
+	if vm.Tags == nil {
+		vm.Tags = make(map[string]*string)
+	}
+	for _, k := range tags.RemovedTags(existing, instanceTags) {
+		delete(vm.Tags, k)
+	}
+	for k, v := range instanceTags {
+		vm.Tags[k] = to.StringPtr(v)
+	}
+	future, err := vmClient.CreateOrUpdate(sdkCtx, env.resourceGroup, vmName, vm)
+	if err != nil {
+		return errorutils.HandleCredentialError(errors.Annotatef(err, "tagging virtual machine %q", vmName), ctx)
+	}
+	if err := future.WaitForCompletionRef(sdkCtx, vmClient.Client); err != nil {
+		return errorutils.HandleCredentialError(errors.Annotatef(err, "tagging virtual machine %q", vmName), ctx)
+	}
+	if _, err := future.Result(vmClient); err != nil {
+		return errors.Annotatef(err, "tagging virtual machine %q", vmName)
+	}
+	return nil
+}
+
 // AllInstances is specified in the InstanceBroker interface.
 func (env *azureEnviron) AllInstances(ctx context.ProviderCallContext) ([]instance.Instance, error) {
 	return env.allInstances(ctx, env.resourceGroup, true /* refresh addresses */, false /* all instances */)
@@ -1923,6 +1923,79 @@ func (s *environSuite) TestAdoptResourcesErrorUpdatingGroup(c *gc.C) {
 	c.Assert(s.requests, gc.HasLen, 3)
 }
 
+func (s *environSuite) TestTagInstance(c *gc.C) {
+	env := s.openEnviron(c)
+	vm := compute.VirtualMachine{
+		Name: to.StringPtr("machine-0"),
+		Tags: map[string]*string{
+			"juju-machine-name": to.StringPtr("machine-0"),
+		},
+	}
+	s.sender = azuretesting.Senders{
+		s.makeSender(`.*/Microsoft\.Compute/virtualMachines/machine-0`, vm),
+		s.makeSender(`.*/Microsoft\.Compute/virtualMachines/machine-0`, vm),
+		s.makeSender(`.*/Microsoft\.Compute/virtualMachines/machine-0`, vm), // future.Result call
+	}
+
+	err := env.(environs.InstanceTagger).TagInstance(s.callCtx, "machine-0", map[string]string{
+		"juju-units-deployed": "mysql/0",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.requests, gc.HasLen, 3)
+	c.Assert(s.requests[0].Method, gc.Equals, "GET")
+	c.Assert(s.requests[1].Method, gc.Equals, "PUT")
+	var updated compute.VirtualMachine
+	unmarshalRequestBody(c, s.requests[1], &updated)
+	c.Assert(to.StringMap(updated.Tags), jc.DeepEquals, map[string]string{
+		"juju-machine-name":   "machine-0",
+		"juju-units-deployed": "mysql/0",
+	})
+}
+
+func (s *environSuite) TestTagInstanceRemovesTags(c *gc.C) {
+	env := s.openEnviron(c)
+	vm := compute.VirtualMachine{
+		Name: to.StringPtr("machine-0"),
+		Tags: map[string]*string{
+			"juju-machine-name":   to.StringPtr("machine-0"),
+			"juju-units-deployed": to.StringPtr("mysql/0"),
+			"juju-managed-tags":   to.StringPtr("owner"),
+			"owner":               to.StringPtr("mysql"),
+			"cost-centre":         to.StringPtr("1234"),
+		},
+	}
+	s.sender = azuretesting.Senders{
+		s.makeSender(`.*/Microsoft\.Compute/virtualMachines/machine-0`, vm),
+		s.makeSender(`.*/Microsoft\.Compute/virtualMachines/machine-0`, vm),
+		s.makeSender(`.*/Microsoft\.Compute/virtualMachines/machine-0`, vm), // future.Result call
+	}
+
+	err := env.(environs.InstanceTagger).TagInstance(s.callCtx, "machine-0", map[string]string{
+		"juju-model-uuid": "model-uuid",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.requests, gc.HasLen, 3)
+	var updated compute.VirtualMachine
+	unmarshalRequestBody(c, s.requests[1], &updated)
+	c.Assert(to.StringMap(updated.Tags), jc.DeepEquals, map[string]string{
+		"juju-machine-name": "machine-0",
+		"juju-model-uuid":   "model-uuid",
+		"cost-centre":       "1234",
+	})
+}
+
+func (s *environSuite) TestTagInstanceNotFound(c *gc.C) {
+	env := s.openEnviron(c)
+	s.sender = azuretesting.Senders{
+		s.makeErrorSender(c, `.*/Microsoft\.Compute/virtualMachines/machine-0`, errors.New("not found"), 4),
+	}
+
+	err := env.(environs.InstanceTagger).TagInstance(s.callCtx, "machine-0", map[string]string{
+		"juju-units-deployed": "mysql/0",
+	})
+	c.Assert(err, gc.ErrorMatches, `getting virtual machine "machine-0": .*not found`)
+}
+
 func (s *environSuite) TestAdoptResourcesErrorGettingVersions(c *gc.C) {
 	env := s.openEnviron(c)
 	errorSender := s.makeErrorSender(
@@ -13,6 +13,10 @@ var unsupportedConstraints = []string{
 	constraints.InstanceType,
 	constraints.Tags,
 	constraints.VirtType,
+	constraints.Spot,
+	constraints.SpotMaxPrice,
+	constraints.AntiAffinity,
+	constraints.RootDiskSource,
 }
 
 // ConstraintsValidator returns a Validator instance which
@@ -20,6 +24,7 @@ var unsupportedConstraints = []string{
 func (env *environ) ConstraintsValidator(ctx context.ProviderCallContext) (constraints.Validator, error) {
 	validator := constraints.NewValidator()
 	validator.RegisterUnsupported(unsupportedConstraints)
+	validator.RegisterRejected([]string{constraints.InstanceRole})
 	return validator, nil
 }
 
@@ -81,6 +81,7 @@ type environ struct {
 
 var _ environs.Environ = (*environ)(nil)
 var _ environs.Networking = (*environ)(nil)
+var _ environs.InstanceTagger = (*environ)(nil)
 
 func (e *environ) Config() *config.Config {
 	return e.ecfg().Config
@@ -166,6 +167,8 @@ var unsupportedConstraints = []string{
 	// TODO(anastasiamac 2016-03-16) LP#1557874
 	// use virt-type in StartInstances
 	constraints.VirtType,
+	constraints.AntiAffinity,
+	constraints.RootDiskSource,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -184,7 +187,7 @@ func (e *environ) ConstraintsValidator(ctx context.ProviderCallContext) (constra
 		instTypeNames[i] = itype.Name
 	}
 	validator.RegisterVocabulary(constraints.InstanceType, instTypeNames)
-	return validator, nil
+	return constraintsValidator{validator}, nil
 }
 
 func archMatches(arches []string, arch *string) bool {
@@ -552,6 +555,18 @@ func (e *environ) StartInstance(ctx context.ProviderCallContext, args environs.S
 		BlockDeviceMappings: blockDeviceMappings,
 		ImageId:             spec.Image.Id,
 	}
+	// extraRunParams holds the RunInstances parameters
+	// that aren't supported by commonRunArgs.
+	extraRunParams := spotMarketParams(args.Constraints)
+	if extraRunParams != nil {
+		logger.Debugf("requesting spot instance with parameters %v", extraRunParams)
+	}
+	if args.Constraints.HasInstanceRole() {
+		if extraRunParams == nil {
+			extraRunParams = make(map[string]string)
+		}
+		extraRunParams["IamInstanceProfile.Name"] = *args.Constraints.InstanceRole
+	}
 
 	runArgs := commonRunArgs
 	runArgs.AvailZone = availabilityZone
@@ -601,7 +616,8 @@ func (e *environ) StartInstance(ctx context.ProviderCallContext, args environs.S
 	}
 
 	callback(status.Allocating, fmt.Sprintf("Trying to start instance in availability zone %q", availabilityZone), nil)
-	instResp, err = runInstances(e.ec2, ctx, runArgs, callback)
+	client := withActionParams(e.ec2, "RunInstances", extraRunParams)
+	instResp, err = runInstances(client, ctx, runArgs, callback)
 	if err != nil {
 		if !isZoneOrSubnetConstrainedError(err) {
 			err = annotateWrapError(err, "cannot run instances")
@@ -630,6 +646,9 @@ func (e *environ) StartInstance(ctx context.ProviderCallContext, args environs.S
 		names.NewMachineTag(args.InstanceConfig.MachineId), e.Config().Name(),
 	)
 	args.InstanceConfig.Tags[tagName] = instanceName
+	if args.Constraints.HasSpot() {
+		args.InstanceConfig.Tags[tagSpot] = "true"
+	}
 	if err := tagResources(e.ec2, ctx, args.InstanceConfig.Tags, string(inst.Id())); err != nil {
 		return nil, annotateWrapError(err, "tagging instance")
 	}
@@ -795,6 +814,23 @@ func tagResources(e *ec2.EC2, ctx context.ProviderCallContext, tags map[string]s
 	return maybeConvertCredentialError(err, ctx)
 }
 
+// deleteTags removes the tags with the given names from the given
+// resources. gopkg.in/amz.v3/ec2 does not implement DeleteTags, so
+// the request is made directly.
+func deleteTags(e *ec2.EC2, names []string, resourceIds ...string) error {
+	params := map[string]string{"Action": "DeleteTags"}
+	for i, id := range resourceIds {
+		params[fmt.Sprintf("ResourceId.%d", i+1)] = id
+	}
+	for i, name := range names {
+		params[fmt.Sprintf("Tag.%d.Key", i+1)] = name
+	}
+	var resp struct {
+		Return bool `xml:"return"`
+	}
+	return errors.Trace(query(e, params, &resp))
+}
+
 func tagRootDisk(e *ec2.EC2, ctx context.ProviderCallContext, tags map[string]string, inst *ec2.Instance) error {
 	if len(tags) == 0 {
 		return nil
@@ -946,6 +982,7 @@ func (e *environ) gatherInstances(
 		return maybeConvertCredentialError(err, ctx)
 	}
 	n := 0
+	var gathered []*ec2Instance
 	// For each requested id, add it to the returned instances
 	// if we find it in the response.
 	for i, id := range ids {
@@ -961,11 +998,14 @@ func (e *environ) gatherInstances(
 				}
 				inst := r.Instances[k]
 				// TODO(wallyworld): lookup the details to fill in the instance type data
-				insts[i] = &ec2Instance{e: e, Instance: &inst}
+				ec2Inst := &ec2Instance{e: e, Instance: &inst}
+				insts[i] = ec2Inst
+				gathered = append(gathered, ec2Inst)
 				n++
 			}
 		}
 	}
+	e.gatherSpotInterruptions(ctx, gathered)
 	if n < len(ids) {
 		return environs.ErrPartialInstances
 	}
@@ -1180,6 +1220,29 @@ func (e *environ) AdoptResources(ctx context.ProviderCallContext, controllerUUID
 	return errors.Annotate(tagResources(e.ec2, ctx, tags, resourceIds...), "updating tags")
 }
 
+// TagInstance implements environs.InstanceTagger.
+func (e *environ) TagInstance(ctx context.ProviderCallContext, id instance.Id, instanceTags map[string]string) error {
+	resp, err := e.ec2.Instances([]string{string(id)}, nil)
+	if err != nil {
+		return errors.Annotate(maybeConvertCredentialError(err, ctx), "tagging instance")
+	}
+	existing := make(map[string]string)
+	for _, r := range resp.Reservations {
+		for _, inst := range r.Instances {
+			for _, tag := range inst.Tags {
+				existing[tag.Key] = tag.Value
+			}
+		}
+	}
+	if removed := tags.RemovedTags(existing, instanceTags); len(removed) > 0 {
+		if err := deleteTags(e.ec2, removed, string(id)); err != nil {
+			return errors.Annotate(maybeConvertCredentialError(err, ctx), "tagging instance")
+		}
+	}
+	err = tagResources(e.ec2, ctx, instanceTags, string(id))
+	return errors.Annotate(maybeConvertCredentialError(err, ctx), "tagging instance")
+}
+
 // AllInstances is part of the environs.InstanceBroker interface.
 func (e *environ) AllInstances(ctx context.ProviderCallContext) ([]instance.Instance, error) {
 	return e.AllInstancesByState(ctx, "pending", "running")
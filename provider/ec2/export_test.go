@@ -32,6 +32,12 @@ func InstanceEC2(inst instance.Instance) *ec2.Instance {
 	return inst.(*ec2Instance).Instance
 }
 
+// NewInstance returns an instance wrapping the supplied EC2 instance,
+// which EC2 is about to interrupt if interruption is not empty.
+func NewInstance(inst *ec2.Instance, interruption string) instance.Instance {
+	return &ec2Instance{Instance: inst, interruption: interruption}
+}
+
 func TerminatedInstances(e environs.Environ) ([]instance.Instance, error) {
 	return e.(*environ).AllInstancesByState(context.NewCloudCallContext(), "shutting-down", "terminated")
 }
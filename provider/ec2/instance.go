@@ -19,6 +19,10 @@ type ec2Instance struct {
 	e *environ
 
 	*ec2.Instance
+
+	// interruption, if not empty, describes why EC2 is about to
+	// interrupt the instance, which was started on spare capacity.
+	interruption string
 }
 
 func (inst *ec2Instance) String() string {
@@ -26,6 +30,7 @@ func (inst *ec2Instance) String() string {
 }
 
 var _ instance.Instance = (*ec2Instance)(nil)
+var _ instance.InterruptibleInstance = (*ec2Instance)(nil)
 
 func (inst *ec2Instance) Id() instance.Id {
 	return instance.Id(inst.InstanceId)
@@ -44,11 +49,31 @@ func (inst *ec2Instance) Status(ctx context.ProviderCallContext) instance.Instan
 	default:
 		jujuStatus = status.Empty
 	}
+	message := inst.State.Name
+	if inst.Interruptible() {
+		if inst.interruption != "" && jujuStatus != status.Empty {
+			return instance.InstanceStatus{
+				Status:  status.Warning,
+				Message: "spot instance interruption: " + inst.interruption,
+			}
+		}
+		message += " (spot)"
+	}
 	return instance.InstanceStatus{
 		Status:  jujuStatus,
-		Message: inst.State.Name,
+		Message: message,
 	}
+}
 
+// Interruptible implements instance.InterruptibleInstance, reporting
+// whether the instance was started on spare capacity.
+func (inst *ec2Instance) Interruptible() bool {
+	for _, tag := range inst.Tags {
+		if tag.Key == tagSpot {
+			return tag.Value == "true"
+		}
+	}
+	return false
 }
 
 // Addresses implements network.Addresses() returning generic address
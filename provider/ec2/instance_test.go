@@ -0,0 +1,67 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	amzec2 "gopkg.in/amz.v3/ec2"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/provider/ec2"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type instanceSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&instanceSuite{})
+
+func (s *instanceSuite) newInstance(stateName string, spot bool, interruption string) instance.Instance {
+	inst := &amzec2.Instance{
+		InstanceId: "i-123",
+		State:      amzec2.InstanceState{Name: stateName},
+	}
+	if spot {
+		inst.Tags = []amzec2.Tag{{Key: "juju-spot", Value: "true"}}
+	}
+	return ec2.NewInstance(inst, interruption)
+}
+
+func (s *instanceSuite) TestStatusOnDemand(c *gc.C) {
+	inst := s.newInstance("running", false, "")
+	c.Assert(inst.Status(context.NewCloudCallContext()), jc.DeepEquals, instance.InstanceStatus{
+		Status:  status.Running,
+		Message: "running",
+	})
+	c.Assert(inst.(instance.InterruptibleInstance).Interruptible(), jc.IsFalse)
+}
+
+func (s *instanceSuite) TestStatusSpot(c *gc.C) {
+	inst := s.newInstance("running", true, "")
+	c.Assert(inst.Status(context.NewCloudCallContext()), jc.DeepEquals, instance.InstanceStatus{
+		Status:  status.Running,
+		Message: "running (spot)",
+	})
+	c.Assert(inst.(instance.InterruptibleInstance).Interruptible(), jc.IsTrue)
+}
+
+func (s *instanceSuite) TestStatusSpotInterrupted(c *gc.C) {
+	inst := s.newInstance("running", true, "Spot Instance terminated due to no available capacity.")
+	c.Assert(inst.Status(context.NewCloudCallContext()), jc.DeepEquals, instance.InstanceStatus{
+		Status:  status.Warning,
+		Message: "spot instance interruption: Spot Instance terminated due to no available capacity.",
+	})
+}
+
+func (s *instanceSuite) TestStatusSpotInterruptedTerminated(c *gc.C) {
+	inst := s.newInstance("terminated", true, "Spot Instance terminated due to no available capacity.")
+	c.Assert(inst.Status(context.NewCloudCallContext()), jc.DeepEquals, instance.InstanceStatus{
+		Status:  status.Empty,
+		Message: "terminated (spot)",
+	})
+}
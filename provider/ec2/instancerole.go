@@ -0,0 +1,56 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2
+
+import (
+	"regexp"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/constraints"
+)
+
+// instanceProfileNameRegexp matches the names IAM allows for instance
+// profiles.
+// See https://docs.aws.amazon.com/IAM/latest/APIReference/API_CreateInstanceProfile.html
+var instanceProfileNameRegexp = regexp.MustCompile(`^[\w+=,.@-]{1,128}$`)
+
+// constraintsValidator wraps the generic constraints validator so that
+// the instance-role constraint is checked against the names IAM accepts
+// for instance profiles.
+type constraintsValidator struct {
+	constraints.Validator
+}
+
+// Validate is defined on constraints.Validator.
+func (v constraintsValidator) Validate(cons constraints.Value) ([]string, error) {
+	unsupported, err := v.Validator.Validate(cons)
+	if err != nil {
+		return unsupported, err
+	}
+	return unsupported, validateInstanceRole(cons)
+}
+
+// Merge is defined on constraints.Validator.
+func (v constraintsValidator) Merge(consFallback, cons constraints.Value) (constraints.Value, error) {
+	if err := validateInstanceRole(consFallback); err != nil {
+		return constraints.Value{}, err
+	}
+	if err := validateInstanceRole(cons); err != nil {
+		return constraints.Value{}, err
+	}
+	return v.Validator.Merge(consFallback, cons)
+}
+
+// validateInstanceRole returns an error if the instance-role constraint
+// is set to something that can't be the name of an instance profile.
+func validateInstanceRole(cons constraints.Value) error {
+	if !cons.HasInstanceRole() {
+		return nil
+	}
+	if !instanceProfileNameRegexp.MatchString(*cons.InstanceRole) {
+		return errors.NotValidf("instance profile name %q", *cons.InstanceRole)
+	}
+	return nil
+}
@@ -4,7 +4,11 @@
 package ec2_test
 
 import (
+	"bytes"
+	"encoding/xml"
 	"fmt"
+	"io/ioutil"
+	"net/http"
 	"net/http/httptest"
 	"net/http/httputil"
 	"net/url"
@@ -12,6 +16,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/juju/clock"
 	"github.com/juju/collections/set"
@@ -836,6 +841,154 @@ func (t *localServerSuite) TestStartInstanceZoneIndependent(c *gc.C) {
 	c.Assert(err, jc.Satisfies, environs.IsAvailabilityZoneIndependent)
 }
 
+// recordActionParams records the parameters of the requests for the
+// given action that are made to the test server, returning a function
+// that returns those recorded so far.
+func (t *localServerSuite) recordActionParams(c *gc.C, action string) func() []url.Values {
+	var mu sync.Mutex
+	var recorded []url.Values
+	director := t.srv.proxy.Director
+	t.srv.proxy.Director = func(req *http.Request) {
+		director(req)
+		params := req.URL.Query()
+		if req.Body != nil {
+			body, err := ioutil.ReadAll(req.Body)
+			c.Check(err, jc.ErrorIsNil)
+			req.Body = ioutil.NopCloser(bytes.NewReader(body))
+			form, err := url.ParseQuery(string(body))
+			c.Check(err, jc.ErrorIsNil)
+			for name, values := range form {
+				params[name] = append(params[name], values...)
+			}
+		}
+		if params.Get("Action") != action {
+			return
+		}
+		mu.Lock()
+		recorded = append(recorded, params)
+		mu.Unlock()
+	}
+	return func() []url.Values {
+		mu.Lock()
+		defer mu.Unlock()
+		return recorded
+	}
+}
+
+func (t *localServerSuite) TestStartInstanceSpot(c *gc.C) {
+	env := t.prepareAndBootstrap(c)
+	runParams := t.recordActionParams(c, "RunInstances")
+
+	inst, _ := testing.AssertStartInstanceWithConstraints(
+		c, env, t.callCtx, t.ControllerUUID, "1",
+		constraints.MustParse("spot=true spot-max-price=0.05"),
+	)
+	c.Assert(runParams(), gc.HasLen, 1)
+	params := runParams()[0]
+	c.Check(params.Get("Version"), gc.Equals, "2016-11-15")
+	c.Check(params.Get("InstanceMarketOptions.MarketType"), gc.Equals, "spot")
+	c.Check(params.Get("InstanceMarketOptions.SpotOptions.MaxPrice"), gc.Equals, "0.05")
+	c.Check(params.Get("InstanceMarketOptions.SpotOptions.SpotInstanceType"), gc.Equals, "one-time")
+	c.Check(params.Get("InstanceMarketOptions.SpotOptions.InstanceInterruptionBehavior"), gc.Equals, "terminate")
+
+	insts, err := env.Instances(t.callCtx, []instance.Id{inst.Id()})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(insts[0].(instance.InterruptibleInstance).Interruptible(), jc.IsTrue)
+}
+
+func (t *localServerSuite) TestStartInstanceOnDemand(c *gc.C) {
+	env := t.prepareAndBootstrap(c)
+	runParams := t.recordActionParams(c, "RunInstances")
+
+	inst, _ := testing.AssertStartInstanceWithConstraints(
+		c, env, t.callCtx, t.ControllerUUID, "1",
+		constraints.MustParse("spot=false spot-max-price=0.05"),
+	)
+	c.Assert(runParams(), gc.HasLen, 1)
+	for name := range runParams()[0] {
+		c.Check(strings.HasPrefix(name, "InstanceMarketOptions."), jc.IsFalse, gc.Commentf("%s", name))
+	}
+
+	insts, err := env.Instances(t.callCtx, []instance.Id{inst.Id()})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(insts[0].(instance.InterruptibleInstance).Interruptible(), jc.IsFalse)
+}
+
+type spotInstanceRequestsResp struct {
+	XMLName  xml.Name              `xml:"DescribeSpotInstanceRequestsResponse"`
+	Requests []spotInstanceRequest `xml:"spotInstanceRequestSet>item"`
+}
+
+type spotInstanceRequest struct {
+	Id         string `xml:"spotInstanceRequestId"`
+	InstanceId string `xml:"instanceId"`
+	Code       string `xml:"status>code"`
+	Message    string `xml:"status>message"`
+}
+
+func (t *localServerSuite) TestInstancesSpotInterruption(c *gc.C) {
+	env := t.prepareAndBootstrap(c)
+	inst, _ := testing.AssertStartInstanceWithConstraints(
+		c, env, t.callCtx, t.ControllerUUID, "1", constraints.MustParse("spot=true"),
+	)
+
+	spotParams := t.recordActionParams(c, "DescribeSpotInstanceRequests")
+	t.srv.proxy.ModifyResponse = func(resp *http.Response) error {
+		if resp.Request.URL.Query().Get("Action") != "DescribeSpotInstanceRequests" {
+			return nil
+		}
+		resp.StatusCode = http.StatusOK
+		resp.Header.Del("Content-Length")
+		resp.ContentLength = -1
+		return replaceResponseBody(resp, spotInstanceRequestsResp{
+			Requests: []spotInstanceRequest{{
+				Id:         "sir-1",
+				InstanceId: string(inst.Id()),
+				Code:       "instance-terminated-no-capacity",
+				Message:    "Spot Instance terminated due to no available capacity.",
+			}},
+		})
+	}
+
+	insts, err := env.Instances(t.callCtx, []instance.Id{inst.Id()})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(insts[0].Status(t.callCtx), jc.DeepEquals, instance.InstanceStatus{
+		Status:  status.Warning,
+		Message: "spot instance interruption: Spot Instance terminated due to no available capacity.",
+	})
+	c.Assert(spotParams(), gc.HasLen, 1)
+	c.Check(spotParams()[0].Get("Filter.1.Name"), gc.Equals, "instance-id")
+	c.Check(spotParams()[0].Get("Filter.1.Value.1"), gc.Equals, string(inst.Id()))
+}
+
+func (t *localServerSuite) TestInstancesSpotRequestsError(c *gc.C) {
+	env := t.prepareAndBootstrap(c)
+	inst, _ := testing.AssertStartInstanceWithConstraints(
+		c, env, t.callCtx, t.ControllerUUID, "1", constraints.MustParse("spot=true"),
+	)
+
+	// The test server doesn't implement DescribeSpotInstanceRequests,
+	// so the instance is reported without the state of its request.
+	insts, err := env.Instances(t.callCtx, []instance.Id{inst.Id()})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(insts[0].Status(t.callCtx), jc.DeepEquals, instance.InstanceStatus{
+		Status:  status.Pending,
+		Message: "pending (spot)",
+	})
+}
+
+func (t *localServerSuite) TestStartInstanceInstanceRole(c *gc.C) {
+	env := t.prepareAndBootstrap(c)
+	runParams := t.recordActionParams(c, "RunInstances")
+
+	testing.AssertStartInstanceWithConstraints(
+		c, env, t.callCtx, t.ControllerUUID, "1",
+		constraints.MustParse("instance-role=juju-machine"),
+	)
+	c.Assert(runParams(), gc.HasLen, 1)
+	c.Check(runParams()[0].Get("IamInstanceProfile.Name"), gc.Equals, "juju-machine")
+}
+
 func (t *localServerSuite) TestStartInstanceSubnet(c *gc.C) {
 	inst, err := t.testStartInstanceSubnet(c, "0.1.2.0/24")
 	c.Assert(err, jc.ErrorIsNil)
@@ -1412,6 +1565,18 @@ func (t *localServerSuite) TestConstraintsValidatorVocabSpecifiedVPC(c *gc.C) {
 	assertVPCInstanceTypeAvailable(c, env, t.callCtx)
 }
 
+func (t *localServerSuite) TestConstraintsValidatorInstanceRole(c *gc.C) {
+	env := t.Prepare(c)
+	validator, err := env.ConstraintsValidator(t.callCtx)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = validator.Validate(constraints.MustParse("instance-role=juju-machine"))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = validator.Validate(constraints.MustParse("instance-role=juju/machine"))
+	c.Assert(err, gc.ErrorMatches, `instance profile name "juju/machine" not valid`)
+	_, err = validator.Merge(constraints.MustParse("instance-role=juju/machine"), constraints.Value{})
+	c.Assert(err, gc.ErrorMatches, `instance profile name "juju/machine" not valid`)
+}
+
 func assertVPCInstanceTypeAvailable(c *gc.C, env environs.Environ, ctx context.ProviderCallContext) {
 	validator, err := env.ConstraintsValidator(ctx)
 	c.Assert(err, jc.ErrorIsNil)
@@ -1769,6 +1934,88 @@ func (t *localServerSuite) TestInstanceTags(c *gc.C) {
 	})
 }
 
+func (t *localServerSuite) TestTagInstance(c *gc.C) {
+	env := t.prepareAndBootstrap(c)
+
+	instances, err := env.AllInstances(t.callCtx)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instances, gc.HasLen, 1)
+
+	deleteParams := t.recordActionParams(c, "DeleteTags")
+	machineTags := map[string]string{
+		"juju-model-uuid":      coretesting.ModelTag.Id(),
+		"juju-controller-uuid": t.ControllerUUID,
+		"juju-is-controller":   "true",
+		"juju-units-deployed":  "mysql/0",
+		"juju-managed-tags":    "owner",
+		"owner":                "mysql",
+	}
+	err = env.(environs.InstanceTagger).TagInstance(t.callCtx, instances[0].Id(), machineTags)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(deleteParams(), gc.HasLen, 0)
+
+	instances, err = env.AllInstances(t.callCtx)
+	c.Assert(err, jc.ErrorIsNil)
+	ec2Inst := ec2.InstanceEC2(instances[0])
+	c.Assert(ec2Inst.Tags, jc.SameContents, []amzec2.Tag{
+		{"Name", "juju-sample-machine-0"},
+		{"juju-model-uuid", coretesting.ModelTag.Id()},
+		{"juju-controller-uuid", t.ControllerUUID},
+		{"juju-is-controller", "true"},
+		{"juju-units-deployed", "mysql/0"},
+		{"juju-managed-tags", "owner"},
+		{"owner", "mysql"},
+	})
+}
+
+type deleteTagsResp struct {
+	XMLName xml.Name `xml:"DeleteTagsResponse"`
+	Return  bool     `xml:"return"`
+}
+
+func (t *localServerSuite) TestTagInstanceRemovesTags(c *gc.C) {
+	env := t.prepareAndBootstrap(c)
+
+	instances, err := env.AllInstances(t.callCtx)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instances, gc.HasLen, 1)
+	machineTags := map[string]string{
+		"juju-model-uuid":      coretesting.ModelTag.Id(),
+		"juju-controller-uuid": t.ControllerUUID,
+		"juju-is-controller":   "true",
+		"juju-units-deployed":  "mysql/0",
+		"juju-managed-tags":    "owner",
+		"owner":                "mysql",
+	}
+	err = env.(environs.InstanceTagger).TagInstance(t.callCtx, instances[0].Id(), machineTags)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The test server doesn't implement DeleteTags.
+	deleteParams := t.recordActionParams(c, "DeleteTags")
+	t.srv.proxy.ModifyResponse = func(resp *http.Response) error {
+		if resp.Request.URL.Query().Get("Action") != "DeleteTags" {
+			return nil
+		}
+		resp.StatusCode = http.StatusOK
+		resp.Header.Del("Content-Length")
+		resp.ContentLength = -1
+		return replaceResponseBody(resp, deleteTagsResp{Return: true})
+	}
+	delete(machineTags, "juju-units-deployed")
+	delete(machineTags, "juju-managed-tags")
+	delete(machineTags, "owner")
+	err = env.(environs.InstanceTagger).TagInstance(t.callCtx, instances[0].Id(), machineTags)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(deleteParams(), gc.HasLen, 1)
+	params := deleteParams()[0]
+	c.Check(params.Get("ResourceId.1"), gc.Equals, string(instances[0].Id()))
+	c.Check(params.Get("Tag.1.Key"), gc.Equals, "juju-managed-tags")
+	c.Check(params.Get("Tag.2.Key"), gc.Equals, "juju-units-deployed")
+	c.Check(params.Get("Tag.3.Key"), gc.Equals, "owner")
+	c.Check(params.Get("Tag.4.Key"), gc.Equals, "")
+}
+
 func (t *localServerSuite) TestRootDiskTags(c *gc.C) {
 	env := t.prepareAndBootstrap(c)
 
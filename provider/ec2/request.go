@@ -0,0 +1,128 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/juju/errors"
+	"gopkg.in/amz.v3/aws"
+	"gopkg.in/amz.v3/ec2"
+)
+
+// queryAPIVersion is the version of the EC2 Query API used for the
+// requests and parameters that gopkg.in/amz.v3/ec2 does not support.
+const queryAPIVersion = "2016-11-15"
+
+// withActionParams returns a copy of the supplied client that adds the
+// given parameters to each request it makes for the given action.
+// This lets callers pass parameters to the actions that
+// gopkg.in/amz.v3/ec2 does implement, but whose arguments it does not
+// support. If any parameters are added, the request is made with
+// queryAPIVersion, which is the version that defines them.
+func withActionParams(client *ec2.EC2, action string, params map[string]string) *ec2.EC2 {
+	if len(params) == 0 {
+		return client
+	}
+	clientCopy := *client
+	sign := client.Sign
+	clientCopy.Sign = func(req *http.Request, auth aws.Auth) error {
+		if err := addActionParams(req, action, params); err != nil {
+			return errors.Trace(err)
+		}
+		return sign(req, auth)
+	}
+	return &clientCopy
+}
+
+// addActionParams adds the given parameters to req, which may carry
+// its parameters either in the URL or in a form-encoded body, if it is
+// a request for the given action.
+func addActionParams(req *http.Request, action string, params map[string]string) error {
+	update := func(values url.Values) bool {
+		if values.Get("Action") != action {
+			return false
+		}
+		values.Set("Version", queryAPIVersion)
+		for name, value := range params {
+			values.Set(name, value)
+		}
+		return true
+	}
+	if req.Body == nil {
+		values := req.URL.Query()
+		if update(values) {
+			req.URL.RawQuery = values.Encode()
+		}
+		return nil
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if update(values) {
+		body = []byte(values.Encode())
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	req.ContentLength = int64(len(body))
+	return nil
+}
+
+// query makes a signed EC2 Query API request with the given parameters,
+// and decodes the response into resp. It is used for the actions that
+// gopkg.in/amz.v3/ec2 does not implement; errors are returned as
+// *ec2.Error, like those from the client's own requests.
+func query(client *ec2.EC2, params map[string]string, resp interface{}) error {
+	values := make(url.Values)
+	values.Set("Version", queryAPIVersion)
+	for name, value := range params {
+		values.Set(name, value)
+	}
+	endpoint := strings.TrimSuffix(client.Region.EC2Endpoint, "/") + "/?" + values.Encode()
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := client.Sign(req, client.Auth); err != nil {
+		return errors.Trace(err)
+	}
+	r, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer r.Body.Close()
+	if r.StatusCode != http.StatusOK {
+		return queryError(r)
+	}
+	return errors.Trace(xml.NewDecoder(r.Body).Decode(resp))
+}
+
+// queryError returns the error described by the body of the supplied
+// unsuccessful response.
+func queryError(r *http.Response) error {
+	var resp struct {
+		RequestId string      `xml:"RequestID"`
+		Errors    []ec2.Error `xml:"Errors>Error"`
+	}
+	err := &ec2.Error{StatusCode: r.StatusCode}
+	if xml.NewDecoder(r.Body).Decode(&resp) == nil && len(resp.Errors) > 0 {
+		*err = resp.Errors[0]
+		err.StatusCode = r.StatusCode
+		err.RequestId = resp.RequestId
+	}
+	if err.Message == "" {
+		err.Message = r.Status
+	}
+	return err
+}
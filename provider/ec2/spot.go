@@ -0,0 +1,123 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2
+
+import (
+	"fmt"
+
+	"github.com/juju/collections/set"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs/context"
+)
+
+const (
+	// tagSpot is the tag with which instances started on spare
+	// capacity are marked, so that they can be told apart from
+	// on-demand instances without looking up their spot requests.
+	tagSpot = "juju-spot"
+
+	// spotMarketType is the market type with which RunInstances is
+	// asked to start an instance on spare capacity.
+	spotMarketType = "spot"
+)
+
+// spotInterruptionCodes holds the spot request status codes that
+// EC2 sets when it is about to interrupt the request's instance.
+// See https://docs.aws.amazon.com/AWSEC2/latest/UserGuide/spot-bid-status.html
+var spotInterruptionCodes = set.NewStrings(
+	"marked-for-termination",
+	"marked-for-stop",
+	"instance-terminated-by-price",
+	"instance-terminated-no-capacity",
+	"instance-terminated-capacity-oversubscribed",
+)
+
+// spotMarketParams returns the RunInstances parameters with which to run
+// an instance with the given constraints on spare capacity, or nil if
+// the instance should be run on demand.
+func spotMarketParams(cons constraints.Value) map[string]string {
+	if !cons.HasSpot() {
+		return nil
+	}
+	params := map[string]string{
+		"InstanceMarketOptions.MarketType": spotMarketType,
+		// Juju can't resume a machine whose instance has been
+		// stopped or hibernated, so interrupted instances are
+		// terminated. A one-time request is needed for that.
+		"InstanceMarketOptions.SpotOptions.SpotInstanceType":             "one-time",
+		"InstanceMarketOptions.SpotOptions.InstanceInterruptionBehavior": "terminate",
+	}
+	if cons.HasSpotMaxPrice() {
+		params["InstanceMarketOptions.SpotOptions.MaxPrice"] = *cons.SpotMaxPrice
+	}
+	return params
+}
+
+// spotInstanceRequest holds the details of a spot instance request
+// returned by DescribeSpotInstanceRequests.
+type spotInstanceRequest struct {
+	Id         string `xml:"spotInstanceRequestId"`
+	InstanceId string `xml:"instanceId"`
+	Status     struct {
+		Code    string `xml:"code"`
+		Message string `xml:"message"`
+	} `xml:"status"`
+}
+
+// spotInstanceRequests returns the spot requests that started the
+// instances with the given ids.
+func (e *environ) spotInstanceRequests(instIds []string) ([]spotInstanceRequest, error) {
+	params := map[string]string{
+		"Action":        "DescribeSpotInstanceRequests",
+		"Filter.1.Name": "instance-id",
+	}
+	for i, id := range instIds {
+		params[fmt.Sprintf("Filter.1.Value.%d", i+1)] = id
+	}
+	var resp struct {
+		Requests []spotInstanceRequest `xml:"spotInstanceRequestSet>item"`
+	}
+	if err := query(e.ec2, params, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Requests, nil
+}
+
+// gatherSpotInterruptions records on each of the supplied instances that
+// was started on spare capacity whether EC2 is about to interrupt it. A
+// failure to find out is logged and otherwise ignored, as it must not
+// prevent the instances from being reported.
+func (e *environ) gatherSpotInterruptions(ctx context.ProviderCallContext, insts []*ec2Instance) {
+	byId := make(map[string]*ec2Instance)
+	var instIds []string
+	for _, inst := range insts {
+		if inst == nil || !inst.Interruptible() {
+			continue
+		}
+		byId[inst.InstanceId] = inst
+		instIds = append(instIds, inst.InstanceId)
+	}
+	if len(instIds) == 0 {
+		return
+	}
+	requests, err := e.spotInstanceRequests(instIds)
+	if err != nil {
+		logger.Warningf(
+			"cannot get spot requests for instances %v: %v",
+			instIds, maybeConvertCredentialError(err, ctx),
+		)
+		return
+	}
+	for _, req := range requests {
+		inst, ok := byId[req.InstanceId]
+		if !ok || !spotInterruptionCodes.Contains(req.Status.Code) {
+			continue
+		}
+		inst.interruption = req.Status.Message
+		if inst.interruption == "" {
+			inst.interruption = req.Status.Code
+		}
+	}
+}
@@ -31,6 +31,9 @@ type gceConnection interface {
 	AddInstance(spec google.InstanceSpec) (*google.Instance, error)
 	RemoveInstances(prefix string, ids ...string) error
 	UpdateMetadata(key, value string, ids ...string) error
+	// UpdateInstanceMetadata sets the given metadata items, and
+	// removes the items with the given keys, on an instance.
+	UpdateInstanceMetadata(id string, items map[string]string, remove []string) error
 
 	IngressRules(fwname string) ([]network.IngressRule, error)
 	OpenPorts(fwname string, rules ...network.IngressRule) error
@@ -86,6 +89,7 @@ type environ struct {
 
 var _ environs.Environ = (*environ)(nil)
 var _ environs.NetworkingEnviron = (*environ)(nil)
+var _ environs.InstanceTagger = (*environ)(nil)
 
 // Function entry points defined as variables so they can be overridden
 // for testing purposes.
@@ -127,8 +127,26 @@ func (env *environ) findInstanceSpec(
 	imageMetadata []*imagemetadata.ImageMetadata,
 ) (*instances.InstanceSpec, error) {
 	images := instances.ImageMetadataToImages(imageMetadata)
-	spec, err := instances.FindInstanceSpec(images, ic, allInstanceTypes)
-	return spec, errors.Trace(err)
+	instanceTypes := allInstanceTypes
+	if ic.Constraints.HasInstanceType() {
+		if custom, ok := parseCustomInstanceType(*ic.Constraints.InstanceType); ok {
+			instanceTypes = []instances.InstanceType{custom}
+		}
+	}
+	spec, err := instances.FindInstanceSpec(images, ic, instanceTypes)
+	custom, ok := customInstanceType(ic.Constraints)
+	if !ok || (err == nil && fitsWell(spec.InstanceType, custom, ic.Constraints)) {
+		return spec, errors.Trace(err)
+	}
+	customSpec, customErr := instances.FindInstanceSpec(images, ic, []instances.InstanceType{custom})
+	if customErr != nil {
+		// The custom type doesn't satisfy some other constraint,
+		// so the predefined types are all we have.
+		logger.Debugf("not using custom machine type %q: %v", custom.Name, customErr)
+		return spec, errors.Trace(err)
+	}
+	logger.Infof("using custom machine type %q", custom.Name)
+	return customSpec, nil
 }
 
 // newRawInstance is where the new physical instance is actually
@@ -177,6 +195,7 @@ func (env *environ) newRawInstance(ctx context.ProviderCallContext, args environ
 		Metadata:          metadata,
 		Tags:              tags,
 		AvailabilityZone:  args.AvailabilityZone,
+		Preemptible:       args.Constraints.HasSpot(),
 		// Network is omitted (left empty).
 	})
 	if err != nil {
@@ -13,6 +13,7 @@ import (
 	"github.com/juju/version"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/imagemetadata"
 	"github.com/juju/juju/environs/instances"
@@ -145,6 +146,50 @@ func (s *environBrokerSuite) TestFindInstanceSpec(c *gc.C) {
 	c.Check(spec, jc.DeepEquals, s.spec)
 }
 
+func (s *environBrokerSuite) TestFindInstanceSpecCustom(c *gc.C) {
+	s.ic.Constraints = constraints.MustParse("cores=10 mem=20G")
+	spec, err := gce.FindInstanceSpec(s.Env, s.ic, s.imageMetadata)
+
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(spec.InstanceType.Name, gc.Equals, "custom-10-20480")
+	c.Check(spec.InstanceType.CpuCores, gc.Equals, uint64(10))
+	c.Check(spec.InstanceType.Mem, gc.Equals, uint64(20480))
+	c.Check(*spec.InstanceType.CpuPower, gc.Equals, uint64(2750))
+}
+
+func (s *environBrokerSuite) TestFindInstanceSpecCustomNoPredefinedMatch(c *gc.C) {
+	s.ic.Constraints = constraints.MustParse("cores=63")
+	spec, err := gce.FindInstanceSpec(s.Env, s.ic, s.imageMetadata)
+
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(spec.InstanceType.Name, gc.Equals, "custom-64-59136")
+}
+
+func (s *environBrokerSuite) TestFindInstanceSpecPredefinedFitsWell(c *gc.C) {
+	s.ic.Constraints = constraints.MustParse("cores=2 mem=7G")
+	spec, err := gce.FindInstanceSpec(s.Env, s.ic, s.imageMetadata)
+
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(spec.InstanceType.Name, gc.Equals, "n1-standard-2")
+}
+
+func (s *environBrokerSuite) TestFindInstanceSpecCustomInstanceType(c *gc.C) {
+	s.ic.Constraints = constraints.MustParse("instance-type=custom-4-8192")
+	spec, err := gce.FindInstanceSpec(s.Env, s.ic, s.imageMetadata)
+
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(spec.InstanceType.Name, gc.Equals, "custom-4-8192")
+	c.Check(spec.InstanceType.CpuCores, gc.Equals, uint64(4))
+	c.Check(spec.InstanceType.Mem, gc.Equals, uint64(8192))
+}
+
+func (s *environBrokerSuite) TestFindInstanceSpecCustomTooBig(c *gc.C) {
+	s.ic.Constraints = constraints.MustParse("cores=128")
+	_, err := gce.FindInstanceSpec(s.Env, s.ic, s.imageMetadata)
+
+	c.Assert(err, gc.ErrorMatches, "no instance types in home matching constraints.*")
+}
+
 func (s *environBrokerSuite) TestNewRawInstance(c *gc.C) {
 	s.FakeConn.Inst = s.BaseInstance
 	s.FakeCommon.AZInstances = []common.AvailabilityZoneInstances{{
@@ -158,6 +203,18 @@ func (s *environBrokerSuite) TestNewRawInstance(c *gc.C) {
 	c.Check(inst, jc.DeepEquals, s.BaseInstance)
 }
 
+func (s *environBrokerSuite) TestNewRawInstancePreemptible(c *gc.C) {
+	s.FakeConn.Inst = s.BaseInstance
+	s.StartInstArgs.Constraints = constraints.MustParse("spot=true")
+
+	_, err := gce.NewRawInstance(s.Env, s.CallCtx, s.StartInstArgs, s.spec)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(s.FakeConn.Calls, gc.HasLen, 1)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "AddInstance")
+	c.Check(s.FakeConn.Calls[0].InstanceSpec.Preemptible, jc.IsTrue)
+}
+
 func (s *environBrokerSuite) TestNewRawInstanceZoneInvalidCredentialError(c *gc.C) {
 	s.FakeConn.Err = gce.InvalidCredentialError
 	c.Assert(s.InvalidatedCredentials, jc.IsFalse)
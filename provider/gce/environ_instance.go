@@ -142,6 +142,32 @@ func (env *environ) AdoptResources(ctx context.ProviderCallContext, controllerUU
 	return nil
 }
 
+// TagInstance implements environs.InstanceTagger. The tags are
+// stored in the instance's metadata, like those set at creation.
+func (env *environ) TagInstance(ctx context.ProviderCallContext, id instance.Id, instanceTags map[string]string) error {
+	instances, err := env.gceInstances(ctx)
+	if err != nil {
+		return errors.Annotate(err, "tagging instance")
+	}
+	var existing map[string]string
+	found := false
+	for _, inst := range instances {
+		if inst.ID == string(id) {
+			existing = inst.Metadata()
+			found = true
+			break
+		}
+	}
+	if !found {
+		return errors.NotFoundf("instance %q", id)
+	}
+	removed := tags.RemovedTags(existing, instanceTags)
+	if err := env.gce.UpdateInstanceMetadata(string(id), instanceTags, removed); err != nil {
+		return google.HandleCredentialError(errors.Annotate(err, "tagging instance"), ctx)
+	}
+	return nil
+}
+
 // TODO(ericsnow) Turn into an interface.
 type instPlacement struct {
 	Zone *google.AvailabilityZone
@@ -172,9 +198,12 @@ func (env *environ) parsePlacement(ctx context.ProviderCallContext, placement st
 }
 
 // checkInstanceType is used to ensure the the provided constraints
-// specify a recognized instance type.
+// specify a recognized instance type, either predefined or custom.
 func checkInstanceType(cons constraints.Value) bool {
 	// Constraint has an instance-type constraint so let's see if it is valid.
+	if _, ok := parseCustomInstanceType(*cons.InstanceType); ok {
+		return true
+	}
 	for _, itype := range allInstanceTypes {
 		if itype.Name == *cons.InstanceType {
 			return true
@@ -189,6 +189,29 @@ func (s *environInstSuite) TestCheckInstanceTypeUnknown(c *gc.C) {
 	c.Check(matched, jc.IsFalse)
 }
 
+func (s *environInstSuite) TestCheckInstanceTypeCustom(c *gc.C) {
+	for _, typ := range []string{"custom-1-1024", "custom-4-8192", "custom-96-638976"} {
+		c.Logf("instance type %q", typ)
+		cons := constraints.Value{InstanceType: &typ}
+		c.Check(gce.CheckInstanceType(cons), jc.IsTrue)
+	}
+}
+
+func (s *environInstSuite) TestCheckInstanceTypeCustomInvalid(c *gc.C) {
+	for _, typ := range []string{
+		"custom-3-8192",  // odd number of cores
+		"custom-4-8000",  // not a multiple of 256MB
+		"custom-4-1024",  // too little memory per core
+		"custom-2-14336", // too much memory per core
+		"custom-98-90112",
+		"custom-0-1024",
+	} {
+		c.Logf("instance type %q", typ)
+		cons := constraints.Value{InstanceType: &typ}
+		c.Check(gce.CheckInstanceType(cons), jc.IsFalse)
+	}
+}
+
 func (s *environInstSuite) TestPrecheckInstanceInvalidCredentialError(c *gc.C) {
 	zone := google.NewZone("a-zone", google.StatusUp, "", "")
 	s.FakeConn.Zones = []google.AvailabilityZone{zone}
@@ -208,11 +231,29 @@ func (s *environInstSuite) TestListMachineTypes(c *gc.C) {
 	zone := google.NewZone("a-zone", google.StatusUp, "", "")
 	s.FakeConn.Zones = []google.AvailabilityZone{zone}
 
+	// The predefined type listed by GCE is returned along with the
+	// custom type synthesised for the constraints.
 	mem := uint64(1025)
 	types, err := s.Env.InstanceTypes(s.CallCtx, constraints.Value{Mem: &mem})
 	c.Assert(err, jc.ErrorIsNil)
-	c.Assert(types.InstanceTypes, gc.HasLen, 1)
+	c.Assert(types.InstanceTypes, gc.HasLen, 2)
+	var names []string
+	for _, itype := range types.InstanceTypes {
+		names = append(names, itype.Name)
+	}
+	c.Assert(names, jc.SameContents, []string{"type-2", "custom-1-1280"})
+}
 
+func (s *environInstSuite) TestListMachineTypesCustomInstanceType(c *gc.C) {
+	zone := google.NewZone("a-zone", google.StatusUp, "", "")
+	s.FakeConn.Zones = []google.AvailabilityZone{zone}
+
+	types, err := s.Env.InstanceTypes(s.CallCtx, constraints.MustParse("instance-type=custom-4-8192"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(types.InstanceTypes, gc.HasLen, 1)
+	c.Check(types.InstanceTypes[0].Name, gc.Equals, "custom-4-8192")
+	c.Check(types.InstanceTypes[0].CpuCores, gc.Equals, uint64(4))
+	c.Check(types.InstanceTypes[0].Mem, gc.Equals, uint64(8192))
 }
 
 func (s *environInstSuite) TestAdoptResources(c *gc.C) {
@@ -241,3 +282,59 @@ func (s *environInstSuite) TestAdoptResourcesInvalidCredentialError(c *gc.C) {
 	c.Check(err, gc.NotNil)
 	c.Assert(s.InvalidatedCredentials, jc.IsTrue)
 }
+
+func (s *environInstSuite) TestTagInstance(c *gc.C) {
+	s.FakeConn.Insts = []google.Instance{*s.NewBaseInstance(c, "john")}
+	machineTags := map[string]string{
+		"owner":                "mysql",
+		tags.JujuUnitsDeployed: "mysql/0",
+		tags.JujuManagedTags:   "owner",
+	}
+	err := s.Env.TagInstance(s.CallCtx, "john", machineTags)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.FakeConn.Calls, gc.HasLen, 2)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "Instances")
+	call := s.FakeConn.Calls[1]
+	c.Check(call.FuncName, gc.Equals, "UpdateInstanceMetadata")
+	c.Check(call.ID, gc.Equals, "john")
+	c.Check(call.Metadata, jc.DeepEquals, machineTags)
+	// The instance's controller tags were set by Juju,
+	// but aren't among those it should now have.
+	c.Check(call.Keys, jc.DeepEquals, []string{tags.JujuController, tags.JujuIsController})
+}
+
+func (s *environInstSuite) TestTagInstanceRemovesTags(c *gc.C) {
+	base := s.NewBaseInstance(c, "john")
+	summary := base.InstanceSummary
+	summary.Metadata = map[string]string{
+		tags.JujuController:    s.ControllerUUID,
+		tags.JujuUnitsDeployed: "mysql/0",
+		tags.JujuManagedTags:   "owner",
+		"owner":                "mysql",
+		"user-data":            "#cloud-config",
+	}
+	s.FakeConn.Insts = []google.Instance{*google.NewInstance(summary, nil)}
+
+	err := s.Env.TagInstance(s.CallCtx, "john", map[string]string{
+		tags.JujuController: s.ControllerUUID,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.FakeConn.Calls, gc.HasLen, 2)
+	call := s.FakeConn.Calls[1]
+	c.Check(call.FuncName, gc.Equals, "UpdateInstanceMetadata")
+	c.Check(call.Keys, jc.DeepEquals, []string{tags.JujuManagedTags, tags.JujuUnitsDeployed, "owner"})
+}
+
+func (s *environInstSuite) TestTagInstanceNotFound(c *gc.C) {
+	err := s.Env.TagInstance(s.CallCtx, "john", map[string]string{"owner": "mysql"})
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *environInstSuite) TestTagInstanceInvalidCredentialError(c *gc.C) {
+	s.FakeConn.Err = gce.InvalidCredentialError
+	c.Assert(s.InvalidatedCredentials, jc.IsFalse)
+
+	err := s.Env.TagInstance(s.CallCtx, "john", map[string]string{"owner": "mysql"})
+	c.Check(err, gc.NotNil)
+	c.Assert(s.InvalidatedCredentials, jc.IsTrue)
+}
@@ -34,6 +34,9 @@ func (env *environ) PrecheckInstance(ctx context.ProviderCallContext, args envir
 var unsupportedConstraints = []string{
 	constraints.Tags,
 	constraints.VirtType,
+	constraints.SpotMaxPrice,
+	constraints.AntiAffinity,
+	constraints.RootDiskSource,
 }
 
 // instanceTypeConstraints defines the fields defined on each of the
@@ -62,6 +65,7 @@ func (env *environ) ConstraintsValidator(ctx context.ProviderCallContext) (const
 	// unsupported
 
 	validator.RegisterUnsupported(unsupportedConstraints)
+	validator.RegisterRejected([]string{constraints.InstanceRole})
 
 	// vocab
 
@@ -73,7 +77,37 @@ func (env *environ) ConstraintsValidator(ctx context.ProviderCallContext) (const
 
 	validator.RegisterVocabulary(constraints.Container, []string{vtype})
 
-	return validator, nil
+	return customInstanceTypeValidator{validator}, nil
+}
+
+// customInstanceTypeValidator adds the custom machine types named in
+// the constraints it validates to the instance-type vocabulary, which
+// can't list them all up front.
+type customInstanceTypeValidator struct {
+	constraints.Validator
+}
+
+func (v customInstanceTypeValidator) addCustomInstanceTypes(values ...constraints.Value) {
+	for _, cons := range values {
+		if !cons.HasInstanceType() {
+			continue
+		}
+		if _, ok := parseCustomInstanceType(*cons.InstanceType); ok {
+			v.UpdateVocabulary(constraints.InstanceType, []string{*cons.InstanceType})
+		}
+	}
+}
+
+// Validate is part of constraints.Validator.
+func (v customInstanceTypeValidator) Validate(cons constraints.Value) ([]string, error) {
+	v.addCustomInstanceTypes(cons)
+	return v.Validator.Validate(cons)
+}
+
+// Merge is part of constraints.Validator.
+func (v customInstanceTypeValidator) Merge(consFallback, cons constraints.Value) (constraints.Value, error) {
+	v.addCustomInstanceTypes(consFallback, cons)
+	return v.Validator.Merge(consFallback, cons)
 }
 
 // SupportNetworks returns whether the environment has support to
@@ -186,6 +186,31 @@ func (s *environPolSuite) TestConstraintsValidatorVocabInstType(c *gc.C) {
 	c.Check(err, gc.ErrorMatches, "invalid constraint value: instance-type=foo\nvalid values are:.*")
 }
 
+func (s *environPolSuite) TestConstraintsValidatorCustomInstType(c *gc.C) {
+	validator, err := s.Env.ConstraintsValidator(s.CallCtx)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = validator.Validate(constraints.MustParse("instance-type=custom-4-8192"))
+	c.Check(err, jc.ErrorIsNil)
+
+	merged, err := validator.Merge(
+		constraints.MustParse("mem=4G"),
+		constraints.MustParse("instance-type=custom-4-8192"),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(merged, jc.DeepEquals, constraints.MustParse("instance-type=custom-4-8192"))
+
+	_, err = validator.Validate(constraints.MustParse("instance-type=custom-3-8192"))
+	c.Check(err, gc.ErrorMatches, "invalid constraint value: instance-type=custom-3-8192\nvalid values are:.*")
+}
+
+func (s *environPolSuite) TestPrecheckInstanceCustomInstanceType(c *gc.C) {
+	cons := constraints.MustParse("instance-type=custom-4-8192")
+	err := s.Env.PrecheckInstance(s.CallCtx, environs.PrecheckInstanceParams{Series: version.SupportedLTS(), Constraints: cons})
+
+	c.Check(err, jc.ErrorIsNil)
+}
+
 func (s *environPolSuite) TestConstraintsValidatorVocabContainer(c *gc.C) {
 	validator, err := s.Env.ConstraintsValidator(s.CallCtx)
 	c.Assert(err, jc.ErrorIsNil)
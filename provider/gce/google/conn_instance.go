@@ -5,6 +5,7 @@ package google
 
 import (
 	"path"
+	"sort"
 
 	"github.com/juju/errors"
 	"google.golang.org/api/compute/v1"
@@ -188,6 +189,72 @@ func (gce *Connection) updateInstanceMetadata(instance *compute.Instance, key, v
 	return errors.Trace(gce.raw.SetMetadata(gce.projectID, zoneName, instance.Name, metadata))
 }
 
+// UpdateInstanceMetadata sets the given metadata items, and removes
+// the items with the given keys, on the instance with the given id,
+// all in a single request. The call blocks until the instance is
+// updated or the request fails.
+func (gce *Connection) UpdateInstanceMetadata(id string, items map[string]string, remove []string) error {
+	instances, err := gce.raw.ListInstances(gce.projectID, id)
+	if err != nil {
+		return errors.Annotatef(err, "updating metadata for instance %q", id)
+	}
+	var inst *compute.Instance
+	for _, candidate := range instances {
+		if candidate.Name == id {
+			inst = candidate
+			break
+		}
+	}
+	if inst == nil {
+		return errors.NotFoundf("instance %q", id)
+	}
+
+	metadata := inst.Metadata
+	if metadata == nil {
+		metadata = &compute.Metadata{}
+	}
+	removing := make(map[string]bool)
+	for _, key := range remove {
+		removing[key] = true
+	}
+	var changed bool
+	var updated []*compute.MetadataItems
+	for _, item := range metadata.Items {
+		if item == nil {
+			continue
+		}
+		if removing[item.Key] {
+			changed = true
+			continue
+		}
+		updated = append(updated, item)
+	}
+	keys := make([]string, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := items[key]
+		item := findMetadataItem(updated, key)
+		if item == nil {
+			updated = append(updated, &compute.MetadataItems{Key: key, Value: &value})
+			changed = true
+		} else if item.Value == nil || *item.Value != value {
+			item.Value = &value
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	metadata.Items = updated
+	// The GCE API won't accept a full URL for the zone (lp:1667172).
+	zoneName := path.Base(inst.Zone)
+	err = gce.raw.SetMetadata(gce.projectID, zoneName, inst.Name, metadata)
+	return errors.Annotatef(err, "updating metadata for instance %q", id)
+}
+
 func findMetadataItem(items []*compute.MetadataItems, key string) *compute.MetadataItems {
 	for _, item := range items {
 		if item == nil {
@@ -463,6 +463,56 @@ func (s *connSuite) TestUpdateMetadataChecksCurrentValue(c *gc.C) {
 	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "ListInstances")
 }
 
+func (s *connSuite) TestUpdateInstanceMetadata(c *gc.C) {
+	s.RawInstanceFull.Zone = "http://eels/lone/wolf/a-zone"
+	s.RawInstanceFull.Metadata = &compute.Metadata{
+		Fingerprint: "heymumwatchthis",
+		Items: []*compute.MetadataItems{
+			makeMetadataItems("eggs", "steak"),
+			makeMetadataItems("rick", "moranis"),
+			makeMetadataItems("ham", "spam"),
+		},
+	}
+	s.FakeConn.Instances = []*compute.Instance{&s.RawInstanceFull}
+
+	err := s.Conn.UpdateInstanceMetadata("spam", map[string]string{
+		"rick":     "morty",
+		"business": "time",
+		"eggs":     "steak",
+	}, []string{"ham", "unset"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(s.FakeConn.Calls, gc.HasLen, 2)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "ListInstances")
+	call := s.FakeConn.Calls[1]
+	c.Check(call.FuncName, gc.Equals, "SetMetadata")
+	c.Check(call.ZoneName, gc.Equals, "a-zone")
+	c.Check(call.InstanceId, gc.Equals, "spam")
+
+	md := call.Metadata
+	c.Check(md.Fingerprint, gc.Equals, "heymumwatchthis")
+	c.Assert(md.Items, gc.HasLen, 3)
+	checkMetadataItems(c, md.Items[0], "eggs", "steak")
+	checkMetadataItems(c, md.Items[1], "rick", "morty")
+	checkMetadataItems(c, md.Items[2], "business", "time")
+}
+
+func (s *connSuite) TestUpdateInstanceMetadataUnchanged(c *gc.C) {
+	s.FakeConn.Instances = []*compute.Instance{&s.RawInstanceFull}
+
+	err := s.Conn.UpdateInstanceMetadata("spam", map[string]string{"eggs": "steak"}, []string{"ham"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.FakeConn.Calls, gc.HasLen, 1)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "ListInstances")
+}
+
+func (s *connSuite) TestUpdateInstanceMetadataNotFound(c *gc.C) {
+	s.FakeConn.Instances = []*compute.Instance{&s.RawInstanceFull}
+
+	err := s.Conn.UpdateInstanceMetadata("trucks", map[string]string{"eggs": "steak"}, nil)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 func makeMetadataItems(key, value string) *compute.MetadataItems {
 	return &compute.MetadataItems{Key: key, Value: google.StringPtr(value)}
 }
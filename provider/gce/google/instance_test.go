@@ -36,6 +36,22 @@ func (s *instanceSuite) TestNewInstanceNoSpec(c *gc.C) {
 	c.Check(spec, gc.IsNil)
 }
 
+func (s *instanceSuite) TestNewInstancePreemptible(c *gc.C) {
+	s.RawInstanceFull.Scheduling = &compute.Scheduling{Preemptible: true}
+	inst := google.NewInstanceRaw(&s.RawInstanceFull, &s.InstanceSpec)
+
+	c.Check(inst.Preemptible, jc.IsTrue)
+}
+
+func (s *instanceSuite) TestInstanceSpecSummaryPreemptible(c *gc.C) {
+	summary := s.InstanceSpec.Summary()
+	c.Check(summary.Preemptible, jc.IsFalse)
+
+	s.InstanceSpec.Preemptible = true
+	summary = s.InstanceSpec.Summary()
+	c.Check(summary.Preemptible, jc.IsTrue)
+}
+
 func (s *instanceSuite) TestInstanceRootDiskGB(c *gc.C) {
 	size := s.Instance.RootDiskGB()
 
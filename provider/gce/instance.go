@@ -46,12 +46,22 @@ func (inst *environInstance) Status(ctx context.ProviderCallContext) instance.In
 	default:
 		jujuStatus = status.Empty
 	}
+	message := instStatus
+	if inst.Interruptible() {
+		message += " (preemptible)"
+	}
 	return instance.InstanceStatus{
 		Status:  jujuStatus,
-		Message: instStatus,
+		Message: message,
 	}
 }
 
+// Interruptible implements instance.InterruptibleInstance, reporting
+// whether the instance was scheduled as a preemptible instance.
+func (inst *environInstance) Interruptible() bool {
+	return inst.base.Preemptible
+}
+
 // Addresses implements instance.Instance.
 func (inst *environInstance) Addresses(ctx context.ProviderCallContext) ([]network.Address, error) {
 	return inst.base.Addresses(), nil
@@ -51,6 +51,15 @@ func (env *environ) InstanceTypes(ctx context.ProviderCallContext, c constraints
 			resultUnique[m.Name] = i
 		}
 	}
+	// GCE doesn't list custom machine types, so add the one named by,
+	// or synthesised for, the constraints.
+	if c.HasInstanceType() {
+		if custom, ok := parseCustomInstanceType(*c.InstanceType); ok {
+			resultUnique[custom.Name] = custom
+		}
+	} else if custom, ok := customInstanceType(c); ok {
+		resultUnique[custom.Name] = custom
+	}
 
 	result := make([]instances.InstanceType, len(resultUnique))
 	i := 0
@@ -4,8 +4,13 @@
 package gce
 
 import (
+	"fmt"
+	"regexp"
+	"strconv"
+
 	"github.com/juju/utils/arch"
 
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs/instances"
 )
 
@@ -155,3 +160,104 @@ var allInstanceTypes = []instances.InstanceType{
 		VirtType: &vtype,
 	},
 }
+
+// The limits GCE places on custom machine types.
+// See https://cloud.google.com/compute/docs/instances/creating-instance-with-custom-machine-type
+const (
+	customMaxCpuCores     = 96
+	customMinMemPerCore   = 922  // 0.9GB, in MB
+	customMaxMemPerCore   = 6656 // 6.5GB, in MB
+	customMemGranularity  = 256
+	customCpuPowerPerCore = 275
+)
+
+// customInstanceType returns a custom machine type with just enough
+// cores and memory to satisfy the given constraints. The result is
+// false if the constraints don't ask for cores or memory, ask for a
+// specific instance type, or can't be satisfied by a custom type.
+func customInstanceType(cons constraints.Value) (instances.InstanceType, bool) {
+	if cons.HasInstanceType() || (!cons.HasCpuCores() && !cons.HasMem()) {
+		return instances.InstanceType{}, false
+	}
+	cores := uint64(1)
+	if cons.HasCpuCores() {
+		cores = *cons.CpuCores
+	}
+	mem := uint64(0)
+	if cons.HasMem() {
+		mem = *cons.Mem
+	}
+	// There must be enough cores to back the requested memory.
+	if minCores := (mem + customMaxMemPerCore - 1) / customMaxMemPerCore; minCores > cores {
+		cores = minCores
+	}
+	// Only a single core or an even number of cores may be requested.
+	if cores > 1 && cores%2 != 0 {
+		cores++
+	}
+	if cores > customMaxCpuCores {
+		return instances.InstanceType{}, false
+	}
+	if minMem := cores * customMinMemPerCore; mem < minMem {
+		mem = minMem
+	}
+	mem = (mem + customMemGranularity - 1) / customMemGranularity * customMemGranularity
+	return newCustomInstanceType(cores, mem), true
+}
+
+// customInstanceTypeRegexp matches the names of custom machine types:
+// "custom-<cores>-<memory in MB>".
+var customInstanceTypeRegexp = regexp.MustCompile(`^custom-([0-9]+)-([0-9]+)$`)
+
+// parseCustomInstanceType returns the custom machine type with the
+// given name, as given in an instance-type constraint. The result is
+// false if the name isn't that of a custom type GCE can create.
+func parseCustomInstanceType(name string) (instances.InstanceType, bool) {
+	parts := customInstanceTypeRegexp.FindStringSubmatch(name)
+	if parts == nil {
+		return instances.InstanceType{}, false
+	}
+	cores, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return instances.InstanceType{}, false
+	}
+	mem, err := strconv.ParseUint(parts[2], 10, 64)
+	if err != nil {
+		return instances.InstanceType{}, false
+	}
+	switch {
+	case cores == 0 || cores > customMaxCpuCores:
+	case cores > 1 && cores%2 != 0:
+	case mem%customMemGranularity != 0:
+	case mem < cores*customMinMemPerCore || mem > cores*customMaxMemPerCore:
+	default:
+		return newCustomInstanceType(cores, mem), true
+	}
+	return instances.InstanceType{}, false
+}
+
+func newCustomInstanceType(cores, mem uint64) instances.InstanceType {
+	return instances.InstanceType{
+		Name:     fmt.Sprintf("custom-%d-%d", cores, mem),
+		Arches:   arches,
+		CpuCores: cores,
+		CpuPower: instances.CpuPower(cores * customCpuPowerPerCore),
+		Mem:      mem,
+		VirtType: &vtype,
+	}
+}
+
+// fitsWell reports whether the given predefined instance type is close
+// enough to the custom type synthesised for the same constraints that
+// it should be preferred. Custom machine types cost a little more than
+// predefined ones, so some slack is allowed in each of the constrained
+// dimensions before a custom type is used instead.
+func fitsWell(itype, custom instances.InstanceType, cons constraints.Value) bool {
+	if cons.HasCpuCores() && itype.CpuCores*4 > custom.CpuCores*5 {
+		return false
+	}
+	if cons.HasMem() && itype.Mem*4 > custom.Mem*5 {
+		return false
+	}
+	return true
+}
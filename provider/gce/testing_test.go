@@ -503,6 +503,8 @@ type fakeConnCall struct {
 	Value            string
 	LabelFingerprint string
 	Labels           map[string]string
+	Metadata         map[string]string
+	Keys             []string
 }
 
 type fakeConn struct {
@@ -583,6 +585,16 @@ func (fc *fakeConn) UpdateMetadata(key, value string, ids ...string) error {
 	return fc.err()
 }
 
+func (fc *fakeConn) UpdateInstanceMetadata(id string, items map[string]string, remove []string) error {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName: "UpdateInstanceMetadata",
+		ID:       id,
+		Metadata: items,
+		Keys:     remove,
+	})
+	return fc.err()
+}
+
 func (fc *fakeConn) IngressRules(fwname string) ([]network.IngressRule, error) {
 	fc.Calls = append(fc.Calls, fakeConnCall{
 		FuncName:     "Ports",
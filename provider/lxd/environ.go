@@ -45,6 +45,9 @@ type environ struct {
 	ecfg *environConfig
 }
 
+var _ common.ZonedEnviron = (*environ)(nil)
+var _ instance.Distributor = (*environ)(nil)
+
 func newEnviron(
 	_ *environProvider,
 	spec environs.CloudSpec,
@@ -299,6 +302,14 @@ func (env *environ) DeriveAvailabilityZones(
 	return []string{p.nodeName}, nil
 }
 
+// DistributeInstances implements the state.InstanceDistributor policy,
+// spreading the units of an application across the cluster members.
+func (env *environ) DistributeInstances(
+	ctx context.ProviderCallContext, candidates, distributionGroup []instance.Id, limitZones []string,
+) ([]instance.Id, error) {
+	return common.DistributeInstances(env, ctx, candidates, distributionGroup, limitZones)
+}
+
 // MaybeWriteLXDProfile implements environs.LXDProfiler.
 func (env *environ) MaybeWriteLXDProfile(pName string, put *charm.LXDProfile) error {
 	hasProfile, err := env.server.HasProfile(pName)
@@ -242,8 +242,9 @@ func (env *environ) getContainerSpec(
 }
 
 // getTargetServer checks to see if a valid zone was passed as a placement
-// directive in the start-up start-up arguments. If so, a server for the
-// specific node is returned.
+// directive in the start-up start-up arguments, or chosen by the
+// provisioner as the availability zone. If so, a server for the specific
+// node is returned.
 func (env *environ) getTargetServer(
 	ctx context.ProviderCallContext, args environs.StartInstanceParams,
 ) (Server, error) {
@@ -252,10 +253,18 @@ func (env *environ) getTargetServer(
 		return nil, errors.Trace(err)
 	}
 
-	if p.nodeName == "" {
+	nodeName := p.nodeName
+	if nodeName == "" && args.AvailabilityZone != "" {
+		// When not clustered, the only zone is the server itself.
+		if !env.server.IsClustered() {
+			return env.server, nil
+		}
+		nodeName = args.AvailabilityZone
+	}
+	if nodeName == "" {
 		return env.server, nil
 	}
-	return env.server.UseTargetServer(p.nodeName)
+	return env.server.UseTargetServer(nodeName)
 }
 
 type lxdPlacement struct {
@@ -303,11 +312,17 @@ func (env *environ) getHardwareCharacteristics(
 	}
 	cores := uint64(container.CPUs())
 	mem := uint64(container.Mem())
-	return &instance.HardwareCharacteristics{
+	hwc := &instance.HardwareCharacteristics{
 		Arch:     &archStr,
 		CpuCores: &cores,
 		Mem:      &mem,
 	}
+	// LXD reports the cluster member running the container as its
+	// location, or "none" if the server is not clustered.
+	if location := container.Location; location != "" && location != "none" {
+		hwc.AvailabilityZone = &location
+	}
+	return hwc
 }
 
 // AllInstances implements environs.InstanceBroker.
@@ -4,6 +4,7 @@
 package lxd
 
 import (
+	"github.com/juju/collections/set"
 	"github.com/juju/errors"
 
 	"github.com/juju/juju/constraints"
@@ -14,8 +15,33 @@ import (
 // PrecheckInstance verifies that the provided series and constraints
 // are valid for use in creating an instance in this environment.
 func (env *environ) PrecheckInstance(ctx context.ProviderCallContext, args environs.PrecheckInstanceParams) error {
-	_, err := env.parsePlacement(ctx, args.Placement)
-	return errors.Trace(err)
+	if _, err := env.parsePlacement(ctx, args.Placement); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(env.checkZonesConstraint(ctx, args.Constraints))
+}
+
+// checkZonesConstraint ensures that each zone in the zones constraint, if
+// set, names a member of the cluster. Members that are currently offline
+// are allowed, as the provisioner will choose among those available.
+func (env *environ) checkZonesConstraint(ctx context.ProviderCallContext, cons constraints.Value) error {
+	if !cons.HasZones() {
+		return nil
+	}
+	zones, err := env.AvailabilityZones(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	names := set.NewStrings()
+	for _, zone := range zones {
+		names.Add(zone.Name())
+	}
+	for _, zone := range *cons.Zones {
+		if !names.Contains(zone) {
+			return errors.NotValidf("availability zone %q", zone)
+		}
+	}
+	return nil
 }
 
 var unsupportedConstraints = []string{
@@ -23,6 +49,10 @@ var unsupportedConstraints = []string{
 	constraints.Tags,
 	constraints.VirtType,
 	constraints.Container,
+	constraints.Spot,
+	constraints.SpotMaxPrice,
+	constraints.AntiAffinity,
+	constraints.RootDiskSource,
 }
 
 // ConstraintsValidator returns a Validator value which is used to
@@ -31,6 +61,7 @@ func (env *environ) ConstraintsValidator(ctx context.ProviderCallContext) (const
 	validator := constraints.NewValidator()
 
 	validator.RegisterUnsupported(unsupportedConstraints)
+	validator.RegisterRejected([]string{constraints.InstanceRole})
 	validator.RegisterVocabulary(constraints.Arch, []string{env.server.HostArch()})
 
 	return validator, nil
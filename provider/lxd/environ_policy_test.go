@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/golang/mock/gomock"
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils/arch"
 	"github.com/lxc/lxd/shared/api"
@@ -111,6 +112,41 @@ func (s *environPolicySuite) TestPrecheckInstanceAvailZone(c *gc.C) {
 	c.Check(err, gc.ErrorMatches, `availability zone "a-zone" not valid`)
 }
 
+func (s *environPolicySuite) TestPrecheckInstanceZonesConstraint(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	svr := lxd.NewMockServer(ctrl)
+
+	env := s.NewEnviron(c, svr, nil)
+
+	members := []api.ClusterMember{
+		{
+			ServerName: "node01",
+			Status:     "ONLINE",
+		},
+		{
+			ServerName: "node02",
+			Status:     "OFFLINE",
+		},
+	}
+
+	exp := svr.EXPECT()
+	gomock.InOrder(
+		exp.IsClustered().Return(true),
+		exp.GetClusterMembers().Return(members, nil),
+		exp.IsClustered().Return(true),
+		exp.GetClusterMembers().Return(members, nil),
+	)
+
+	cons := constraints.MustParse("zones=node01,node02")
+	err := env.PrecheckInstance(context.NewCloudCallContext(), environs.PrecheckInstanceParams{Series: version.SupportedLTS(), Constraints: cons})
+	c.Check(err, jc.ErrorIsNil)
+
+	cons = constraints.MustParse("zones=node01,node03")
+	err = env.PrecheckInstance(context.NewCloudCallContext(), environs.PrecheckInstanceParams{Series: version.SupportedLTS(), Constraints: cons})
+	c.Check(err, gc.ErrorMatches, `availability zone "node03" not valid`)
+}
+
 func (s *environPolicySuite) TestConstraintsValidatorOkay(c *gc.C) {
 	ctrl := gomock.NewController(c)
 	defer ctrl.Finish()
@@ -183,6 +219,24 @@ func (s *environPolicySuite) TestConstraintsValidatorUnsupported(c *gc.C) {
 	c.Check(unsupported, jc.SameContents, expected)
 }
 
+func (s *environPolicySuite) TestConstraintsValidatorInstanceRole(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	svr := lxd.NewMockServer(ctrl)
+
+	env := s.NewEnviron(c, svr, nil)
+
+	exp := svr.EXPECT()
+	exp.HostArch().Return(arch.AMD64)
+
+	validator, err := env.ConstraintsValidator(context.NewCloudCallContext())
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = validator.Validate(constraints.MustParse("instance-role=juju-machine"))
+	c.Assert(err, gc.ErrorMatches, `"instance-role" constraint not supported`)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
 func (s *environPolicySuite) TestConstraintsValidatorVocabArchKnown(c *gc.C) {
 	ctrl := gomock.NewController(c)
 	defer ctrl.Finish()
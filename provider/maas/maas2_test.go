@@ -579,6 +579,7 @@ type fakeBlockDevice struct {
 
 	name   string
 	idPath string
+	path   string
 	size   uint64
 }
 
@@ -586,6 +587,10 @@ func (bd fakeBlockDevice) Name() string {
 	return bd.name
 }
 
+func (bd fakeBlockDevice) Path() string {
+	return bd.path
+}
+
 func (bd fakeBlockDevice) IDPath() string {
 	return bd.idPath
 }
@@ -32,8 +32,37 @@ const (
 	// tagsAttribute is the name of the pool attribute used
 	// to specify tag values for requested volumes.
 	tagsAttribute = "tags"
+
+	// diskTypeAttribute is the name of the pool attribute used
+	// to specify the type of disk backing requested volumes.
+	diskTypeAttribute = "disk-type"
+
+	// raidLevelAttribute is the name of the pool attribute used
+	// to request volumes backed by a RAID array of the given level.
+	raidLevelAttribute = "raid-level"
+
+	// partitionAttribute is the name of the pool attribute used
+	// to request volumes backed by a partition rather than a
+	// whole block device.
+	partitionAttribute = "partition"
+
+	// partitionTag is the tag recognised by MAAS as matching
+	// partitions, rather than block devices, in a storage
+	// constraint.
+	partitionTag = "partition"
 )
 
+// diskTypes holds the valid values for the disk-type pool attribute.
+// Each is requested from MAAS as a block device tag of the same name;
+// MAAS tags disks "ssd" or "rotary" when commissioning them, whereas
+// "nvme" and "sata" disks must be tagged by the operator.
+var diskTypes = set.NewStrings("nvme", "sata", "ssd", "rotary")
+
+// raidLevels holds the valid values for the raid-level pool attribute.
+// Each is requested from MAAS as a tag of the same name, which the
+// operator is expected to have applied to the RAID devices.
+var raidLevels = set.NewStrings("raid-0", "raid-1", "raid-5", "raid-6", "raid-10")
+
 // StorageProviderTypes implements storage.ProviderRegistry.
 func (*maasEnviron) StorageProviderTypes() ([]storage.ProviderType, error) {
 	return []storage.ProviderType{maasStorageProviderType}, nil
@@ -55,17 +84,26 @@ var storageConfigFields = schema.Fields{
 		schema.List(schema.String()),
 		schema.String(),
 	),
+	diskTypeAttribute:  schema.String(),
+	raidLevelAttribute: schema.String(),
+	partitionAttribute: schema.Bool(),
 }
 
 var storageConfigChecker = schema.FieldMap(
 	storageConfigFields,
 	schema.Defaults{
-		tagsAttribute: schema.Omit,
+		tagsAttribute:      schema.Omit,
+		diskTypeAttribute:  schema.Omit,
+		raidLevelAttribute: schema.Omit,
+		partitionAttribute: false,
 	},
 )
 
 type storageConfig struct {
-	tags []string
+	tags      []string
+	diskType  string
+	raidLevel string
+	partition bool
 }
 
 func newStorageConfig(attrs map[string]interface{}) (*storageConfig, error) {
@@ -91,7 +129,41 @@ func newStorageConfig(attrs map[string]interface{}) (*storageConfig, error) {
 			tags = append(tags, f)
 		}
 	}
-	return &storageConfig{tags: tags}, nil
+	diskType, _ := coerced[diskTypeAttribute].(string)
+	if diskType != "" && !diskTypes.Contains(diskType) {
+		return nil, errors.Errorf(
+			"%s must be one of %s, got %q",
+			diskTypeAttribute, strings.Join(diskTypes.SortedValues(), ", "), diskType,
+		)
+	}
+	raidLevel, _ := coerced[raidLevelAttribute].(string)
+	if raidLevel != "" && !raidLevels.Contains(raidLevel) {
+		return nil, errors.Errorf(
+			"%s must be one of %s, got %q",
+			raidLevelAttribute, strings.Join(raidLevels.SortedValues(), ", "), raidLevel,
+		)
+	}
+	return &storageConfig{
+		tags:      tags,
+		diskType:  diskType,
+		raidLevel: raidLevel,
+		partition: coerced[partitionAttribute].(bool),
+	}, nil
+}
+
+// constraintTags returns the tags to request from MAAS in the
+// storage constraint for a volume in a pool with this config.
+func (cfg *storageConfig) constraintTags() []string {
+	tags := append([]string(nil), cfg.tags...)
+	for _, tag := range []string{cfg.diskType, cfg.raidLevel} {
+		if tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	if cfg.partition {
+		tags = append(tags, partitionTag)
+	}
+	return tags
 }
 
 // ValidateConfig is defined on the Provider interface.
@@ -168,7 +240,7 @@ func buildMAASVolumeParameters(args []storage.VolumeParams, cons constraints.Val
 		info := volumeInfo{
 			name:     v.Tag.Id(),
 			sizeInGB: mibToGb(v.Size),
-			tags:     cfg.tags,
+			tags:     cfg.constraintTags(),
 		}
 		volumes[i+1] = info
 	}
@@ -364,7 +436,12 @@ func (mi *maas2Instance) volumes(
 			// Handle a block device specifically that way the path used
 			// by Juju will always be a persistent path.
 			idPath := blockDev.IDPath()
-			if idPath == devPrefix+blockDev.Name() {
+			if idPath == "" {
+				// Virtual block devices, such as RAID arrays,
+				// have no id_path; fall back to the path MAAS
+				// provides, which is based on the device's dname.
+				attachment.DeviceLink = blockDev.Path()
+			} else if idPath == devPrefix+blockDev.Name() {
 				// On vMAAS (i.e. with virtio), the device name
 				// will be stable, and is what is used to form
 				// id_path.
@@ -372,9 +449,15 @@ func (mi *maas2Instance) volumes(
 				attachment.DeviceName = deviceName
 			} else if strings.HasPrefix(idPath, devDiskByIdPrefix) {
 				const wwnPrefix = "wwn-"
+				const nvmePrefix = "nvme-"
 				id := idPath[len(devDiskByIdPrefix):]
 				if strings.HasPrefix(id, wwnPrefix) {
 					vol.WWN = id[len(wwnPrefix):]
+				} else if strings.HasPrefix(id, nvmePrefix) {
+					// udev does not set ID_BUS for NVMe devices, so
+					// the hardware ID Juju discovers for them will
+					// never match; use the link MAAS gave us instead.
+					attachment.DeviceLink = idPath
 				} else {
 					vol.HardwareId = id
 				}
@@ -75,6 +75,35 @@ func (s *volumeSuite) TestBuildMAASVolumeParametersWithTags(c *gc.C) {
 	})
 }
 
+func (s *volumeSuite) TestBuildMAASVolumeParametersDiskShape(c *gc.C) {
+	vInfo, err := buildMAASVolumeParameters([]storage.VolumeParams{{
+		Tag:  names.NewVolumeTag("1"),
+		Size: 2000000,
+		Attributes: map[string]interface{}{
+			"tags":      "tag1",
+			"disk-type": "nvme",
+		},
+	}, {
+		Tag:        names.NewVolumeTag("2"),
+		Size:       2000000,
+		Attributes: map[string]interface{}{"raid-level": "raid-1"},
+	}, {
+		Tag:  names.NewVolumeTag("3"),
+		Size: 2000000,
+		Attributes: map[string]interface{}{
+			"disk-type": "sata",
+			"partition": true,
+		},
+	}}, constraints.Value{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(vInfo, jc.DeepEquals, []volumeInfo{
+		{"root", 0, nil}, //root disk
+		{"1", 1954, []string{"tag1", "nvme"}},
+		{"2", 1954, []string{"raid-1"}},
+		{"3", 1954, []string{"sata", "partition"}},
+	})
+}
+
 func (s *volumeSuite) TestInstanceVolumesMAAS2(c *gc.C) {
 	instance := maas2Instance{
 		machine: &fakeMachine{},
@@ -174,6 +203,59 @@ func (s *volumeSuite) TestInstanceVolumesMAAS2(c *gc.C) {
 	}})
 }
 
+func (s *volumeSuite) TestInstanceVolumesMAAS2NVMeAndRAID(c *gc.C) {
+	instance := maas2Instance{
+		machine: &fakeMachine{},
+		constraintMatches: gomaasapi.ConstraintMatches{
+			Storage: map[string][]gomaasapi.StorageDevice{
+				"1": {&fakeBlockDevice{
+					name:   "nvme0n1",
+					idPath: "/dev/disk/by-id/nvme-Samsung_SSD_970_S123",
+					path:   "/dev/disk/by-dname/nvme0n1",
+					size:   500059350016,
+				}},
+				"2": {&fakeBlockDevice{
+					name: "md0",
+					path: "/dev/disk/by-dname/md0",
+					size: 500059350016,
+				}},
+			},
+		},
+	}
+	mTag := names.NewMachineTag("1")
+	volumes, attachments, err := instance.volumes(mTag, []names.VolumeTag{
+		names.NewVolumeTag("1"),
+		names.NewVolumeTag("2"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(volumes, jc.SameContents, []storage.Volume{{
+		names.NewVolumeTag("1"),
+		storage.VolumeInfo{
+			VolumeId: "volume-1",
+			Size:     476893,
+		},
+	}, {
+		names.NewVolumeTag("2"),
+		storage.VolumeInfo{
+			VolumeId: "volume-2",
+			Size:     476893,
+		},
+	}})
+	c.Check(attachments, jc.SameContents, []storage.VolumeAttachment{{
+		names.NewVolumeTag("1"),
+		mTag,
+		storage.VolumeAttachmentInfo{
+			DeviceLink: "/dev/disk/by-id/nvme-Samsung_SSD_970_S123",
+		},
+	}, {
+		names.NewVolumeTag("2"),
+		mTag,
+		storage.VolumeAttachmentInfo{
+			DeviceLink: "/dev/disk/by-dname/md0",
+		},
+	}})
+}
+
 func (s *volumeSuite) TestInstanceVolumes(c *gc.C) {
 	obj := s.testMAASObject.TestServer.NewNode(validVolumeJson)
 	statusGetter := func(context.ProviderCallContext, instance.Id) (string, string) {
@@ -374,6 +456,37 @@ func (*storageProviderSuite) TestValidateConfigInvalidConfig(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `tags may not contain whitespace: "white space"`)
 }
 
+func (*storageProviderSuite) TestValidateConfigDiskShape(c *gc.C) {
+	p := maasStorageProvider{}
+	for i, test := range []struct {
+		attrs map[string]interface{}
+		err   string
+	}{{
+		attrs: map[string]interface{}{"disk-type": "nvme", "partition": true},
+	}, {
+		attrs: map[string]interface{}{"raid-level": "raid-10", "partition": "true"},
+	}, {
+		attrs: map[string]interface{}{"disk-type": "floppy"},
+		err:   `disk-type must be one of nvme, rotary, sata, ssd, got "floppy"`,
+	}, {
+		attrs: map[string]interface{}{"raid-level": "raid-3"},
+		err:   `raid-level must be one of raid-0, raid-1, raid-10, raid-5, raid-6, got "raid-3"`,
+	}, {
+		attrs: map[string]interface{}{"partition": "maybe"},
+		err:   `validating MAAS storage config: partition: expected bool, got string\("maybe"\)`,
+	}} {
+		c.Logf("test %d: %v", i, test.attrs)
+		cfg, err := storage.NewConfig("foo", maasStorageProviderType, test.attrs)
+		c.Assert(err, jc.ErrorIsNil)
+		err = p.ValidateConfig(cfg)
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.err)
+		}
+	}
+}
+
 func (*storageProviderSuite) TestValidateConfigUnknownAttribute(c *gc.C) {
 	p := maasStorageProvider{}
 	cfg, err := storage.NewConfig("foo", maasStorageProviderType, map[string]interface{}{
@@ -331,10 +331,15 @@ func (e *Environ) ConstraintsValidator(ctx envcontext.ProviderCallContext) (cons
 		constraints.Container,
 		constraints.VirtType,
 		constraints.Tags,
+		constraints.Spot,
+		constraints.SpotMaxPrice,
+		constraints.AntiAffinity,
+		constraints.RootDiskSource,
 	}
 
 	validator := constraints.NewValidator()
 	validator.RegisterUnsupported(unsupportedConstraints)
+	validator.RegisterRejected([]string{constraints.InstanceRole})
 	validator.RegisterVocabulary(constraints.Arch, []string{arch.AMD64})
 	logger.Infof("Returning constraints validator: %v", validator)
 	return validator, nil
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"regexp"
 
+	"gopkg.in/goose.v2/client"
 	"gopkg.in/goose.v2/neutron"
 	"gopkg.in/goose.v2/nova"
 	"gopkg.in/goose.v2/swift"
@@ -134,6 +135,15 @@ func GetNovaClient(e environs.Environ) *nova.Client {
 	return e.(*Environ).nova()
 }
 
+// WrapClient replaces the environ's authenticated client with the one
+// returned by wrap. The nova and neutron clients are left as they are.
+func WrapClient(e environs.Environ, wrap func(client.AuthenticatingClient) client.AuthenticatingClient) {
+	env := e.(*Environ)
+	env.ecfgMutex.Lock()
+	defer env.ecfgMutex.Unlock()
+	env.clientUnlocked = wrap(env.clientUnlocked)
+}
+
 // ResolveNetwork exposes environ helper function resolveNetwork for testing
 func ResolveNetwork(e environs.Environ, networkName string, external bool) (string, error) {
 	return e.(*Environ).networking.ResolveNetwork(networkName, external)
@@ -29,6 +29,7 @@ import (
 	gc "gopkg.in/check.v1"
 	"gopkg.in/goose.v2/cinder"
 	"gopkg.in/goose.v2/client"
+	goosehttp "gopkg.in/goose.v2/http"
 	"gopkg.in/goose.v2/identity"
 	"gopkg.in/goose.v2/neutron"
 	"gopkg.in/goose.v2/nova"
@@ -2406,6 +2407,18 @@ func (t *localServerSuite) TestTagInstance(c *gc.C) {
 	err := bootstrapEnv(c, t.env)
 	c.Assert(err, jc.ErrorIsNil)
 
+	bootstrapTags := map[string]string{
+		"juju-model-uuid":      coretesting.ModelTag.Id(),
+		"juju-controller-uuid": coretesting.ControllerTag.Id(),
+		"juju-is-controller":   "true",
+	}
+	instanceTags := func(extraKey, extraValue string) map[string]string {
+		instanceTags := map[string]string{extraKey: extraValue}
+		for k, v := range bootstrapTags {
+			instanceTags[k] = v
+		}
+		return instanceTags
+	}
 	assertMetadata := func(extraKey, extraValue string) {
 		// Refresh instance
 		instances, err := t.env.AllInstances(t.callCtx)
@@ -2414,12 +2427,7 @@ func (t *localServerSuite) TestTagInstance(c *gc.C) {
 		c.Assert(
 			openstack.InstanceServerDetail(instances[0]).Metadata,
 			jc.DeepEquals,
-			map[string]string{
-				"juju-model-uuid":      coretesting.ModelTag.Id(),
-				"juju-controller-uuid": coretesting.ControllerTag.Id(),
-				"juju-is-controller":   "true",
-				extraKey:               extraValue,
-			},
+			instanceTags(extraKey, extraValue),
 		)
 	}
 
@@ -2432,7 +2440,7 @@ func (t *localServerSuite) TestTagInstance(c *gc.C) {
 	err = t.env.(environs.InstanceTagger).TagInstance(
 		t.callCtx,
 		instances[0].Id(),
-		map[string]string{extraKey: extraValue},
+		instanceTags(extraKey, extraValue),
 	)
 	c.Assert(err, jc.ErrorIsNil)
 	assertMetadata(extraKey, extraValue)
@@ -2442,12 +2450,62 @@ func (t *localServerSuite) TestTagInstance(c *gc.C) {
 	err = t.env.(environs.InstanceTagger).TagInstance(
 		t.callCtx,
 		instances[0].Id(),
-		map[string]string{extraKey: extraValue},
+		instanceTags(extraKey, extraValue),
 	)
 	c.Assert(err, jc.ErrorIsNil)
 	assertMetadata(extraKey, extraValue)
 }
 
+// metadataClient records the requests to delete server metadata, which
+// the goose test double does not implement. All other requests are
+// passed on to the test double.
+type metadataClient struct {
+	client.AuthenticatingClient
+	deleted []string
+}
+
+func (c *metadataClient) SendRequest(method, svcType, apiVersion, apiCall string, requestData *goosehttp.RequestData) error {
+	if svcType == "compute" && method == client.DELETE && strings.Contains(apiCall, "/metadata/") {
+		c.deleted = append(c.deleted, apiCall)
+		return nil
+	}
+	return c.AuthenticatingClient.SendRequest(method, svcType, apiVersion, apiCall, requestData)
+}
+
+func (t *localServerSuite) TestTagInstanceRemovesTags(c *gc.C) {
+	err := bootstrapEnv(c, t.env)
+	c.Assert(err, jc.ErrorIsNil)
+	instances, err := t.env.AllInstances(t.callCtx)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instances, gc.HasLen, 1)
+	id := string(instances[0].Id())
+
+	// The server has metadata that Juju didn't set, as well as the
+	// user-defined tags that it did.
+	err = openstack.GetNovaClient(t.env).SetServerMetadata(id, map[string]string{
+		tags.JujuManagedTags: "owner",
+		"owner":              "mysql",
+		"user-key":           "user-value",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	fake := &metadataClient{}
+	openstack.WrapClient(t.env, func(real client.AuthenticatingClient) client.AuthenticatingClient {
+		fake.AuthenticatingClient = real
+		return fake
+	})
+	err = t.env.(environs.InstanceTagger).TagInstance(t.callCtx, instances[0].Id(), map[string]string{
+		tags.JujuModel:      coretesting.ModelTag.Id(),
+		tags.JujuController: coretesting.ControllerTag.Id(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(fake.deleted, jc.DeepEquals, []string{
+		"servers/" + id + "/metadata/juju-is-controller",
+		"servers/" + id + "/metadata/juju-managed-tags",
+		"servers/" + id + "/metadata/owner",
+	})
+}
+
 func (s *localServerSuite) TestAdoptResources(c *gc.C) {
 	err := bootstrapEnv(c, s.env)
 	c.Assert(err, jc.ErrorIsNil)
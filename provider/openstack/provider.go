@@ -9,6 +9,7 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
+	"net/http"
 	"net/url"
 	"path"
 	"strconv"
@@ -26,6 +27,7 @@ import (
 	"gopkg.in/goose.v2/cinder"
 	"gopkg.in/goose.v2/client"
 	gooseerrors "gopkg.in/goose.v2/errors"
+	goosehttp "gopkg.in/goose.v2/http"
 	"gopkg.in/goose.v2/identity"
 	gooselogging "gopkg.in/goose.v2/logging"
 	"gopkg.in/goose.v2/neutron"
@@ -318,6 +320,10 @@ type Environ struct {
 	clock clock.Clock
 
 	publicIPMutex sync.Mutex
+
+	// serverGroupMutex serialises starting servers in server groups
+	// with the deletion of server groups.
+	serverGroupMutex sync.Mutex
 }
 
 var _ environs.Environ = (*Environ)(nil)
@@ -521,6 +527,9 @@ func (e *Environ) neutron() *neutron.Client {
 var unsupportedConstraints = []string{
 	constraints.Tags,
 	constraints.CpuPower,
+	constraints.Spot,
+	constraints.SpotMaxPrice,
+	constraints.RootDiskSource,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -530,6 +539,7 @@ func (e *Environ) ConstraintsValidator(ctx context.ProviderCallContext) (constra
 		[]string{constraints.InstanceType},
 		[]string{constraints.Mem, constraints.RootDisk, constraints.Cores})
 	validator.RegisterUnsupported(unsupportedConstraints)
+	validator.RegisterRejected([]string{constraints.InstanceRole})
 	novaClient := e.nova()
 	flavors, err := novaClient.ListFlavorsDetail()
 	if err != nil {
@@ -1208,10 +1218,11 @@ func (e *Environ) StartInstance(ctx context.ProviderCallContext, args environs.S
 	tryStartNovaInstance := func(
 		attempts utils.AttemptStrategy,
 		client *nova.Client,
+		runServer func(nova.RunServerOpts) (*nova.Entity, error),
 		instanceOpts nova.RunServerOpts,
 	) (server *nova.Entity, err error) {
 		for a := attempts.Start(); a.Next(); {
-			server, err = client.RunServer(instanceOpts)
+			server, err = runServer(instanceOpts)
 			if err != nil {
 				common.HandleCredentialError(IsAuthorisationFailure, err, ctx)
 				break
@@ -1258,9 +1269,24 @@ func (e *Environ) StartInstance(ctx context.ProviderCallContext, args environs.S
 		Metadata:           args.InstanceConfig.Tags,
 		AvailabilityZone:   args.AvailabilityZone,
 	}
+	runServer := e.nova().RunServer
+	if args.Constraints.HasAntiAffinity() {
+		appName, err := serverGroupApplication(args.InstanceConfig.Tags)
+		if err != nil {
+			return nil, common.ZoneIndependentError(err)
+		}
+		if appName == "" {
+			logger.Debugf("no units assigned to machine %q, ignoring anti-affinity", args.InstanceConfig.MachineId)
+		} else {
+			antiAffinity := *args.Constraints.AntiAffinity
+			runServer = func(opts nova.RunServerOpts) (*nova.Entity, error) {
+				return e.runServerInGroup(ctx, opts, appName, antiAffinity)
+			}
+		}
+	}
 	e.configurator.ModifyRunServerOptions(&opts)
 
-	server, err := tryStartNovaInstance(shortAttempt, e.nova(), opts)
+	server, err := tryStartNovaInstance(shortAttempt, e.nova(), runServer, opts)
 	if err != nil || server == nil {
 		common.HandleCredentialError(IsAuthorisationFailure, err, ctx)
 		// 'No valid host available' is typically a resource error,
@@ -1412,8 +1438,7 @@ func (e *Environ) StopInstances(ctx context.ProviderCallContext, ids ...instance
 		common.HandleCredentialError(IsAuthorisationFailure, err, ctx)
 		return err
 	}
-	logger.Debugf("terminating instances %v", ids)
-	if err := e.terminateInstances(ctx, ids); err != nil {
+	if err := e.terminateInstancesAndServerGroups(ctx, ids); err != nil {
 		common.HandleCredentialError(IsAuthorisationFailure, err, ctx)
 		return err
 	}
@@ -1566,7 +1591,9 @@ func (e *Environ) AdoptResources(ctx context.ProviderCallContext, controllerUUID
 		return errors.Trace(err)
 	}
 	for _, instance := range instances {
-		err := e.TagInstance(ctx, instance.Id(), controllerTag)
+		// Only the controller tag changes, so the server's other
+		// metadata must be kept, as TagInstance would remove it.
+		err := e.nova().SetServerMetadata(string(instance.Id()), controllerTag)
 		if err != nil {
 			logger.Errorf("error updating controller tag for instance %s: %v", instance.Id(), err)
 			failed = append(failed, string(instance.Id()))
@@ -1706,6 +1733,10 @@ func (e *Environ) Destroy(ctx context.ProviderCallContext) error {
 		common.HandleCredentialError(IsAuthorisationFailure, err, ctx)
 		return errors.Trace(err)
 	}
+	// Delete all server groups remaining in the model.
+	if err := e.deleteModelServerGroups(ctx); err != nil {
+		return errors.Trace(err)
+	}
 	return nil
 }
 
@@ -1889,15 +1920,37 @@ func (e *Environ) cloudSpec(region string) (simplestreams.CloudSpec, error) {
 	}, nil
 }
 
-// TagInstance implements environs.InstanceTagger.
-func (e *Environ) TagInstance(ctx context.ProviderCallContext, id instance.Id, tags map[string]string) error {
-	if err := e.nova().SetServerMetadata(string(id), tags); err != nil {
+// TagInstance implements environs.InstanceTagger. Metadata that Juju
+// set on the server, but that isn't among the given tags, is removed.
+func (e *Environ) TagInstance(ctx context.ProviderCallContext, id instance.Id, instanceTags map[string]string) error {
+	server, err := e.nova().GetServer(string(id))
+	if err != nil {
+		common.HandleCredentialError(IsAuthorisationFailure, err, ctx)
+		return errors.Annotate(err, "getting server metadata")
+	}
+	for _, key := range tags.RemovedTags(server.Metadata, instanceTags) {
+		if err := e.deleteServerMetadata(string(id), key); err != nil {
+			common.HandleCredentialError(IsAuthorisationFailure, err, ctx)
+			return errors.Annotatef(err, "deleting server metadata %q", key)
+		}
+	}
+	if err := e.nova().SetServerMetadata(string(id), instanceTags); err != nil {
 		common.HandleCredentialError(IsAuthorisationFailure, err, ctx)
 		return errors.Annotate(err, "setting server metadata")
 	}
 	return nil
 }
 
+// deleteServerMetadata deletes the metadata item with the given key
+// from the server. The nova client in goose.v2 can only add and update
+// server metadata, so the request is made directly.
+func (e *Environ) deleteServerMetadata(serverId, key string) error {
+	apiCall := fmt.Sprintf("servers/%s/metadata/%s", serverId, url.PathEscape(key))
+	return e.client().SendRequest(client.DELETE, "compute", "v2", apiCall, &goosehttp.RequestData{
+		ExpectedStatus: []int{http.StatusNoContent},
+	})
+}
+
 func (e *Environ) SetClock(clock clock.Clock) {
 	e.clock = clock
 }
@@ -0,0 +1,290 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package openstack
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	"gopkg.in/goose.v2/client"
+	gooseerrors "gopkg.in/goose.v2/errors"
+	goosehttp "gopkg.in/goose.v2/http"
+	"gopkg.in/goose.v2/nova"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/environs/tags"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/provider/common"
+)
+
+// serverGroupPolicies maps anti-affinity constraint values to the nova
+// server group policies that implement them.
+var serverGroupPolicies = map[string]string{
+	constraints.AntiAffinityHard: "anti-affinity",
+	constraints.AntiAffinitySoft: "soft-anti-affinity",
+}
+
+// serverGroupPrefix returns the prefix of the names of all server groups
+// created for applications in the model.
+func (e *Environ) serverGroupPrefix() string {
+	return resourceName(e.namespace, e.name, "application-")
+}
+
+// serverGroupApplication returns the name of the application whose units
+// a machine with the given instance tags is being started to host, or ""
+// if no units have been assigned to the machine.
+func serverGroupApplication(instanceTags map[string]string) (string, error) {
+	for _, unitName := range strings.Fields(instanceTags[tags.JujuUnitsDeployed]) {
+		if !names.IsValidUnit(unitName) {
+			continue
+		}
+		appName, err := names.UnitApplication(unitName)
+		if err != nil {
+			return "", errors.Annotate(err, "getting application name")
+		}
+		return appName, nil
+	}
+	return "", nil
+}
+
+// serverGroupsAPIVersion is the nova API microversion with which
+// server groups are managed. It is the first to support the
+// soft-anti-affinity policy.
+const serverGroupsAPIVersion = "2.15"
+
+// serverGroup holds the details of a nova server group.
+type serverGroup struct {
+	Id       string   `json:"id"`
+	Name     string   `json:"name"`
+	Policies []string `json:"policies"`
+	Members  []string `json:"members"`
+}
+
+// schedulerHints holds the nova scheduler hints with which a server
+// is started.
+type schedulerHints struct {
+	Group string `json:"group,omitempty"`
+}
+
+// The nova client in goose.v2 supports neither server groups nor
+// scheduler hints, so the requests below are made directly with the
+// environ's authenticated client.
+
+func (e *Environ) sendServerGroupRequest(method, apiCall string, requestData *goosehttp.RequestData) error {
+	requestData.ReqHeaders = http.Header{
+		"X-OpenStack-Nova-API-Version": []string{serverGroupsAPIVersion},
+	}
+	return e.client().SendRequest(method, "compute", "v2", apiCall, requestData)
+}
+
+func (e *Environ) listServerGroups() ([]serverGroup, error) {
+	var resp struct {
+		ServerGroups []serverGroup `json:"server_groups"`
+	}
+	err := e.sendServerGroupRequest(client.GET, "os-server-groups", &goosehttp.RequestData{
+		RespValue:      &resp,
+		ExpectedStatus: []int{http.StatusOK},
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return resp.ServerGroups, nil
+}
+
+func (e *Environ) createServerGroup(name, policy string) (serverGroup, error) {
+	var req struct {
+		ServerGroup serverGroup `json:"server_group"`
+	}
+	req.ServerGroup = serverGroup{Name: name, Policies: []string{policy}}
+	var resp struct {
+		ServerGroup serverGroup `json:"server_group"`
+	}
+	err := e.sendServerGroupRequest(client.POST, "os-server-groups", &goosehttp.RequestData{
+		ReqValue:       req,
+		RespValue:      &resp,
+		ExpectedStatus: []int{http.StatusOK},
+	})
+	if err != nil {
+		return serverGroup{}, errors.Trace(err)
+	}
+	return resp.ServerGroup, nil
+}
+
+func (e *Environ) deleteServerGroup(id string) error {
+	return e.sendServerGroupRequest(client.DELETE, "os-server-groups/"+id, &goosehttp.RequestData{
+		ExpectedStatus: []int{http.StatusNoContent},
+	})
+}
+
+// runServer starts a server like nova.Client.RunServer, passing the
+// given scheduler hints with the request.
+func (e *Environ) runServer(opts nova.RunServerOpts, hints schedulerHints) (*nova.Entity, error) {
+	req := struct {
+		Server nova.RunServerOpts `json:"server"`
+		Hints  schedulerHints     `json:"os:scheduler_hints"`
+	}{opts, hints}
+	var resp struct {
+		Server nova.Entity `json:"server"`
+	}
+	err := e.client().SendRequest(client.POST, "compute", "v2", "servers", &goosehttp.RequestData{
+		ReqValue:       req,
+		RespValue:      &resp,
+		ExpectedStatus: []int{http.StatusAccepted},
+	})
+	if err != nil {
+		return nil, errors.Annotate(err, "failed to run a server")
+	}
+	return &resp.Server, nil
+}
+
+// runServerInGroup starts a server in the server group that keeps apart
+// the machines of the named application, creating the group with the
+// given anti-affinity if it doesn't exist yet.
+func (e *Environ) runServerInGroup(
+	ctx context.ProviderCallContext, opts nova.RunServerOpts, appName, antiAffinity string,
+) (*nova.Entity, error) {
+	policy, ok := serverGroupPolicies[antiAffinity]
+	if !ok {
+		return nil, errors.NotValidf("anti-affinity %q", antiAffinity)
+	}
+	groupName := e.serverGroupPrefix() + appName
+
+	// Machines for the same application may be started concurrently,
+	// and nova doesn't require server group names to be unique. The
+	// lock is held until the server is a member of the group, so that
+	// StopInstances doesn't delete the group before it's used.
+	e.serverGroupMutex.Lock()
+	defer e.serverGroupMutex.Unlock()
+
+	groups, err := e.listServerGroups()
+	if err != nil {
+		common.HandleCredentialError(IsAuthorisationFailure, err, ctx)
+		return nil, errors.Annotate(err, "listing server groups")
+	}
+	var groupId string
+	for _, group := range groups {
+		if group.Name != groupName {
+			continue
+		}
+		if !set.NewStrings(group.Policies...).Contains(policy) {
+			logger.Warningf(
+				"server group %q has policies %v, not %q; the anti-affinity constraint for %q only applies to new groups",
+				groupName, group.Policies, policy, appName,
+			)
+		}
+		groupId = group.Id
+		break
+	}
+	if groupId == "" {
+		group, err := e.createServerGroup(groupName, policy)
+		if err != nil {
+			common.HandleCredentialError(IsAuthorisationFailure, err, ctx)
+			return nil, errors.Annotatef(err, "creating server group %q", groupName)
+		}
+		logger.Infof("created server group %q with policy %q", groupName, policy)
+		groupId = group.Id
+	}
+	return e.runServer(opts, schedulerHints{Group: groupId})
+}
+
+// serverGroupsToDelete returns the IDs of the model's server groups that
+// will be empty once the instances with the given IDs are terminated,
+// including those that are already empty. The server group mutex must
+// be held, so that no server is being started in any of the groups.
+func (e *Environ) serverGroupsToDelete(ctx context.ProviderCallContext, ids []instance.Id) ([]string, error) {
+	groups, err := e.listServerGroups()
+	if err != nil {
+		common.HandleCredentialError(IsAuthorisationFailure, err, ctx)
+		return nil, errors.Annotate(err, "listing server groups")
+	}
+	stopping := set.NewStrings()
+	for _, id := range ids {
+		stopping.Add(string(id))
+	}
+	prefix := e.serverGroupPrefix()
+	var groupIds []string
+	for _, group := range groups {
+		if !strings.HasPrefix(group.Name, prefix) {
+			continue
+		}
+		if set.NewStrings(group.Members...).Difference(stopping).IsEmpty() {
+			groupIds = append(groupIds, group.Id)
+		}
+	}
+	return groupIds, nil
+}
+
+// modelServerGroups returns the IDs of all of the model's server groups.
+func (e *Environ) modelServerGroups(ctx context.ProviderCallContext) ([]string, error) {
+	groups, err := e.listServerGroups()
+	if err != nil {
+		common.HandleCredentialError(IsAuthorisationFailure, err, ctx)
+		return nil, errors.Annotate(err, "listing server groups")
+	}
+	prefix := e.serverGroupPrefix()
+	var groupIds []string
+	for _, group := range groups {
+		if strings.HasPrefix(group.Name, prefix) {
+			groupIds = append(groupIds, group.Id)
+		}
+	}
+	return groupIds, nil
+}
+
+// terminateInstancesAndServerGroups terminates the instances with the
+// given IDs, and deletes the model's server groups that are left without
+// members. Server groups are deleted along with the last of their
+// members, which happens when an application's last unit is removed.
+// Not every cloud supports server groups, so failing to delete them
+// must not prevent the instances from being stopped; any that are left
+// are deleted by a later call, or with the model.
+func (e *Environ) terminateInstancesAndServerGroups(ctx context.ProviderCallContext, ids []instance.Id) error {
+	e.serverGroupMutex.Lock()
+	defer e.serverGroupMutex.Unlock()
+
+	serverGroupIds, err := e.serverGroupsToDelete(ctx, ids)
+	if err != nil {
+		logger.Warningf("cannot find server groups to delete: %v", err)
+	}
+	logger.Debugf("terminating instances %v", ids)
+	if err := e.terminateInstances(ctx, ids); err != nil {
+		return err
+	}
+	if err := e.deleteServerGroups(ctx, serverGroupIds); err != nil {
+		logger.Warningf("cannot delete server groups: %v", err)
+	}
+	return nil
+}
+
+// deleteModelServerGroups deletes all of the model's server groups.
+func (e *Environ) deleteModelServerGroups(ctx context.ProviderCallContext) error {
+	e.serverGroupMutex.Lock()
+	defer e.serverGroupMutex.Unlock()
+
+	serverGroupIds, err := e.modelServerGroups(ctx)
+	if err != nil {
+		logger.Warningf("cannot find server groups to delete: %v", err)
+	}
+	return errors.Trace(e.deleteServerGroups(ctx, serverGroupIds))
+}
+
+// deleteServerGroups deletes the server groups with the given IDs.
+func (e *Environ) deleteServerGroups(ctx context.ProviderCallContext, groupIds []string) error {
+	for _, id := range groupIds {
+		logger.Debugf("deleting server group %q", id)
+		err := e.deleteServerGroup(id)
+		if gooseerrors.IsNotFound(errors.Cause(err)) {
+			err = nil
+		}
+		if err != nil {
+			common.HandleCredentialError(IsAuthorisationFailure, err, ctx)
+			return errors.Annotatef(err, "deleting server group %q", id)
+		}
+	}
+	return nil
+}
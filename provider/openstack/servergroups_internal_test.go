@@ -0,0 +1,42 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package openstack
+
+import (
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/tags"
+)
+
+type serverGroupsInternalSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&serverGroupsInternalSuite{})
+
+func (s *serverGroupsInternalSuite) TestServerGroupApplication(c *gc.C) {
+	appName, err := serverGroupApplication(map[string]string{
+		tags.JujuUnitsDeployed: "mysql/0 wordpress/1",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(appName, gc.Equals, "mysql")
+}
+
+func (s *serverGroupsInternalSuite) TestServerGroupApplicationSkipsInvalidUnits(c *gc.C) {
+	appName, err := serverGroupApplication(map[string]string{
+		tags.JujuUnitsDeployed: "not-a-unit wordpress/1",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(appName, gc.Equals, "wordpress")
+}
+
+func (s *serverGroupsInternalSuite) TestServerGroupApplicationNoUnits(c *gc.C) {
+	appName, err := serverGroupApplication(map[string]string{
+		tags.JujuModel: "deadbeef",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(appName, gc.Equals, "")
+}
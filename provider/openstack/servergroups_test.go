@@ -0,0 +1,300 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package openstack_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/goose.v2/client"
+	gooseerrors "gopkg.in/goose.v2/errors"
+	goosehttp "gopkg.in/goose.v2/http"
+	"gopkg.in/goose.v2/nova"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/tags"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/provider/openstack"
+)
+
+type serverGroup struct {
+	Id       string   `json:"id"`
+	Name     string   `json:"name"`
+	Policies []string `json:"policies"`
+	Members  []string `json:"members"`
+}
+
+// serverGroupsClient implements the nova server group API, which the
+// goose test double does not, and records the scheduler hints with
+// which servers are started. All other requests are passed on to the
+// test double.
+type serverGroupsClient struct {
+	client.AuthenticatingClient
+
+	mu     sync.Mutex
+	nextId int
+	groups map[string]*serverGroup
+	hints  []map[string]string
+}
+
+func newServerGroupsClient(env environs.Environ) *serverGroupsClient {
+	fake := &serverGroupsClient{groups: make(map[string]*serverGroup)}
+	openstack.WrapClient(env, func(real client.AuthenticatingClient) client.AuthenticatingClient {
+		fake.AuthenticatingClient = real
+		return fake
+	})
+	return fake
+}
+
+func (c *serverGroupsClient) SendRequest(method, svcType, apiVersion, apiCall string, requestData *goosehttp.RequestData) error {
+	if svcType == "compute" {
+		switch {
+		case method == client.GET && apiCall == "os-server-groups":
+			return c.listServerGroups(requestData)
+		case method == client.POST && apiCall == "os-server-groups":
+			return c.createServerGroup(requestData)
+		case method == client.DELETE && strings.HasPrefix(apiCall, "os-server-groups/"):
+			return c.deleteServerGroup(strings.TrimPrefix(apiCall, "os-server-groups/"))
+		case method == client.POST && apiCall == "servers":
+			return c.runServer(method, svcType, apiVersion, apiCall, requestData)
+		}
+	}
+	return c.AuthenticatingClient.SendRequest(method, svcType, apiVersion, apiCall, requestData)
+}
+
+func (c *serverGroupsClient) listServerGroups(requestData *goosehttp.RequestData) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// Nova removes servers from their groups as they are deleted.
+	novaClient := nova.New(c.AuthenticatingClient)
+	resp := struct {
+		ServerGroups []serverGroup `json:"server_groups"`
+	}{[]serverGroup{}}
+	for _, group := range c.sortedGroups() {
+		var members []string
+		for _, id := range group.Members {
+			_, err := novaClient.GetServer(id)
+			if gooseerrors.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return errors.Trace(err)
+			}
+			members = append(members, id)
+		}
+		group.Members = members
+		resp.ServerGroups = append(resp.ServerGroups, *group)
+	}
+	return copyJSON(resp, requestData.RespValue)
+}
+
+func (c *serverGroupsClient) createServerGroup(requestData *goosehttp.RequestData) error {
+	if version := requestData.ReqHeaders.Get("X-OpenStack-Nova-API-Version"); version != "2.15" {
+		return errors.Errorf("unexpected nova API version %q", version)
+	}
+	var req struct {
+		ServerGroup serverGroup `json:"server_group"`
+	}
+	if err := copyJSON(requestData.ReqValue, &req); err != nil {
+		return errors.Trace(err)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.nextId++
+	group := req.ServerGroup
+	group.Id = fmt.Sprintf("group-%d", c.nextId)
+	c.groups[group.Id] = &group
+	return copyJSON(map[string]serverGroup{"server_group": group}, requestData.RespValue)
+}
+
+func (c *serverGroupsClient) deleteServerGroup(id string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.groups[id]; !ok {
+		return errors.Errorf("server group %q not found", id)
+	}
+	delete(c.groups, id)
+	return nil
+}
+
+func (c *serverGroupsClient) runServer(method, svcType, apiVersion, apiCall string, requestData *goosehttp.RequestData) error {
+	var req struct {
+		Hints map[string]string `json:"os:scheduler_hints"`
+	}
+	if err := copyJSON(requestData.ReqValue, &req); err != nil {
+		return errors.Trace(err)
+	}
+	err := c.AuthenticatingClient.SendRequest(method, svcType, apiVersion, apiCall, requestData)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var resp struct {
+		Server nova.Entity `json:"server"`
+	}
+	if err := copyJSON(requestData.RespValue, &resp); err != nil {
+		return errors.Trace(err)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.hints = append(c.hints, req.Hints)
+	if group, ok := c.groups[req.Hints["group"]]; ok {
+		group.Members = append(group.Members, resp.Server.Id)
+	}
+	return nil
+}
+
+func (c *serverGroupsClient) sortedGroups() []*serverGroup {
+	var groups []*serverGroup
+	for _, group := range c.groups {
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Id < groups[j].Id
+	})
+	return groups
+}
+
+// Groups returns the server groups, with their current members.
+func (c *serverGroupsClient) Groups() []serverGroup {
+	var resp struct {
+		ServerGroups []serverGroup `json:"server_groups"`
+	}
+	err := c.listServerGroups(&goosehttp.RequestData{RespValue: &resp})
+	if err != nil {
+		panic(err)
+	}
+	return resp.ServerGroups
+}
+
+// Hints returns the scheduler hints with which servers were started.
+func (c *serverGroupsClient) Hints() []map[string]string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hints
+}
+
+// AddGroup adds a server group, as if it had been left behind.
+func (c *serverGroupsClient) AddGroup(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.nextId++
+	id := fmt.Sprintf("group-%d", c.nextId)
+	c.groups[id] = &serverGroup{Id: id, Name: name, Policies: []string{"anti-affinity"}}
+}
+
+func copyJSON(from, to interface{}) error {
+	data, err := json.Marshal(from)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(json.Unmarshal(data, to))
+}
+
+func (s *localServerSuite) startInstanceWithUnits(
+	c *gc.C, env environs.Environ, machineId, units string, cons constraints.Value,
+) instance.Instance {
+	params := environs.StartInstanceParams{
+		ControllerUUID: s.ControllerUUID,
+		Constraints:    cons,
+	}
+	err := testing.FillInStartInstanceParams(env, machineId, false, &params)
+	c.Assert(err, jc.ErrorIsNil)
+	params.InstanceConfig.Tags[tags.JujuUnitsDeployed] = units
+	result, err := env.StartInstance(s.callCtx, params)
+	c.Assert(err, jc.ErrorIsNil)
+	return result.Instance
+}
+
+func (s *localServerSuite) TestStartInstanceAntiAffinity(c *gc.C) {
+	fake := newServerGroupsClient(s.env)
+	cons := constraints.MustParse("anti-affinity=hard")
+	inst0 := s.startInstanceWithUnits(c, s.env, "100", "mysql/0", cons)
+	inst1 := s.startInstanceWithUnits(c, s.env, "101", "mysql/1", cons)
+
+	groups := fake.Groups()
+	c.Assert(groups, gc.HasLen, 1)
+	c.Check(strings.HasSuffix(groups[0].Name, "-application-mysql"), jc.IsTrue, gc.Commentf("%s", groups[0].Name))
+	c.Check(groups[0].Policies, jc.DeepEquals, []string{"anti-affinity"})
+	c.Check(groups[0].Members, jc.DeepEquals, []string{string(inst0.Id()), string(inst1.Id())})
+	c.Check(fake.Hints(), jc.DeepEquals, []map[string]string{
+		{"group": groups[0].Id},
+		{"group": groups[0].Id},
+	})
+}
+
+func (s *localServerSuite) TestStartInstanceSoftAntiAffinity(c *gc.C) {
+	fake := newServerGroupsClient(s.env)
+	s.startInstanceWithUnits(c, s.env, "100", "mysql/0", constraints.MustParse("anti-affinity=soft"))
+
+	groups := fake.Groups()
+	c.Assert(groups, gc.HasLen, 1)
+	c.Check(groups[0].Policies, jc.DeepEquals, []string{"soft-anti-affinity"})
+}
+
+func (s *localServerSuite) TestStartInstanceAntiAffinityNoUnits(c *gc.C) {
+	fake := newServerGroupsClient(s.env)
+	s.startInstanceWithUnits(c, s.env, "100", "", constraints.MustParse("anti-affinity=hard"))
+
+	c.Check(fake.Groups(), gc.HasLen, 0)
+	c.Check(fake.Hints(), gc.HasLen, 0)
+}
+
+func (s *localServerSuite) TestStopInstancesDeletesServerGroups(c *gc.C) {
+	fake := newServerGroupsClient(s.env)
+	cons := constraints.MustParse("anti-affinity=hard")
+	inst0 := s.startInstanceWithUnits(c, s.env, "100", "mysql/0", cons)
+	inst1 := s.startInstanceWithUnits(c, s.env, "101", "mysql/1", cons)
+	groups := fake.Groups()
+	c.Assert(groups, gc.HasLen, 1)
+	mysqlGroup := groups[0]
+
+	// An empty group left behind by an earlier failure
+	// is deleted along with the next instance stopped.
+	prefix := strings.TrimSuffix(mysqlGroup.Name, "mysql")
+	fake.AddGroup(prefix + "wordpress")
+	c.Assert(fake.Groups(), gc.HasLen, 2)
+
+	err := s.env.StopInstances(s.callCtx, inst0.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	groups = fake.Groups()
+	c.Assert(groups, gc.HasLen, 1)
+	c.Check(groups[0].Id, gc.Equals, mysqlGroup.Id)
+	c.Check(groups[0].Members, jc.DeepEquals, []string{string(inst1.Id())})
+
+	err = s.env.StopInstances(s.callCtx, inst1.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(fake.Groups(), gc.HasLen, 0)
+}
+
+func (s *localServerSuite) TestStopInstancesKeepsOtherModelsServerGroups(c *gc.C) {
+	fake := newServerGroupsClient(s.env)
+	inst := s.startInstanceWithUnits(c, s.env, "100", "mysql/0", constraints.MustParse("anti-affinity=hard"))
+	fake.AddGroup("juju-other-model-application-mysql")
+
+	err := s.env.StopInstances(s.callCtx, inst.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	groups := fake.Groups()
+	c.Assert(groups, gc.HasLen, 1)
+	c.Check(groups[0].Name, gc.Equals, "juju-other-model-application-mysql")
+}
+
+func (s *localServerSuite) TestDestroyDeletesServerGroups(c *gc.C) {
+	fake := newServerGroupsClient(s.env)
+	cons := constraints.MustParse("anti-affinity=hard")
+	s.startInstanceWithUnits(c, s.env, "100", "mysql/0", cons)
+	s.startInstanceWithUnits(c, s.env, "101", "wordpress/0", cons)
+	c.Assert(fake.Groups(), gc.HasLen, 2)
+
+	err := s.env.Destroy(s.callCtx)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(fake.Groups(), gc.HasLen, 0)
+}
@@ -0,0 +1,571 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package proxmox
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+
+	"github.com/juju/juju/environs"
+)
+
+const (
+	// defaultAPIPort is the port on which Proxmox VE nodes serve
+	// the API, used when the cloud endpoint doesn't specify one.
+	defaultAPIPort = "8006"
+
+	// apiPath is the path of the API, relative to the endpoint.
+	apiPath = "/api2/json"
+
+	// authCookie is the name of the cookie that holds the
+	// ticket with which requests are authenticated.
+	authCookie = "PVEAuthCookie"
+
+	// csrfHeader is the name of the header that holds the token
+	// required for any request that changes something.
+	csrfHeader = "CSRFPreventionToken"
+)
+
+var (
+	// taskPollInterval is how often the status of a task
+	// is checked while waiting for it to finish.
+	taskPollInterval = 2 * time.Second
+
+	// taskTimeout is how long to wait for a task to finish.
+	taskTimeout = 30 * time.Minute
+
+	// requestTimeout is how long to wait for a request to complete.
+	// Anything that takes longer, such as cloning a VM, is run by
+	// the API as a task, which is polled rather than waited on, so
+	// only uploads of seed images come anywhere near it.
+	requestTimeout = 5 * time.Minute
+)
+
+// client is a client for the Proxmox VE API.
+// See https://pve.proxmox.com/pve-docs/api-viewer/.
+type client struct {
+	endpoint   string
+	username   string
+	password   string
+	httpClient *http.Client
+	clock      clock.Clock
+
+	mu        sync.Mutex
+	ticket    string
+	csrfToken string
+}
+
+// newClient returns a client for the Proxmox VE API at the given cloud's
+// endpoint, which authenticates with the cloud's credential.
+func newClient(spec environs.CloudSpec, verifySSL bool) (*client, error) {
+	u, err := endpointURL(spec.Endpoint)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	attrs := spec.Credential.Attributes()
+	return &client{
+		endpoint:   u.String(),
+		username:   attrs[credAttrUsername],
+		password:   attrs[credAttrPassword],
+		httpClient: newHTTPClient(spec.CACertificates, verifySSL),
+		clock:      clock.WallClock,
+	}, nil
+}
+
+func newHTTPClient(caCerts []string, verifySSL bool) *http.Client {
+	tlsConfig := utils.SecureTLSConfig()
+	if len(caCerts) > 0 {
+		pool := x509.NewCertPool()
+		for _, cert := range caCerts {
+			pool.AppendCertsFromPEM([]byte(cert))
+		}
+		tlsConfig.RootCAs = pool
+	}
+	// Proxmox VE nodes use self-signed certificates by default.
+	tlsConfig.InsecureSkipVerify = !verifySSL
+	return &http.Client{
+		Transport: &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+			DialContext: (&net.Dialer{
+				Timeout:   30 * time.Second,
+				KeepAlive: 30 * time.Second,
+			}).DialContext,
+			TLSClientConfig:       tlsConfig,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ResponseHeaderTimeout: time.Minute,
+		},
+		Timeout: requestTimeout,
+	}
+}
+
+// apiError is an error returned by the API.
+type apiError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+
+	// Message is the reason given in the response's status line,
+	// which is where the API describes what went wrong.
+	Message string
+
+	// Errors holds any problems with individual request parameters.
+	Errors map[string]string
+}
+
+// Error is part of the error interface.
+func (e *apiError) Error() string {
+	if len(e.Errors) == 0 {
+		return e.Message
+	}
+	params := make([]string, 0, len(e.Errors))
+	for param := range e.Errors {
+		params = append(params, param)
+	}
+	sort.Strings(params)
+	problems := make([]string, len(params))
+	for i, param := range params {
+		problems[i] = fmt.Sprintf("%s: %s", param, strings.TrimSpace(e.Errors[param]))
+	}
+	return fmt.Sprintf("%s (%s)", e.Message, strings.Join(problems, ", "))
+}
+
+// do makes an API request, decoding the data in the response into
+// result if it is not nil. Parameters are sent in the query string for
+// GET and DELETE requests, and in a form otherwise.
+func (c *client) do(method, path string, params url.Values, result interface{}) error {
+	newRequest := func() (*http.Request, error) {
+		u := c.endpoint + apiPath + path
+		var body io.Reader
+		if method == "GET" || method == "DELETE" {
+			if len(params) > 0 {
+				u += "?" + params.Encode()
+			}
+		} else {
+			body = strings.NewReader(params.Encode())
+		}
+		req, err := http.NewRequest(method, u, body)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if body != nil {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+		return req, nil
+	}
+	return errors.Trace(c.send(newRequest, result))
+}
+
+// upload uploads the given content to a file with the given name,
+// in a multipart form with the given fields.
+func (c *client) upload(path string, fields map[string]string, filename string, content []byte, result interface{}) error {
+	newRequest := func() (*http.Request, error) {
+		var body bytes.Buffer
+		w := multipart.NewWriter(&body)
+		for name, value := range fields {
+			if err := w.WriteField(name, value); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		part, err := w.CreateFormFile("filename", filename)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if _, err := part.Write(content); err != nil {
+			return nil, errors.Trace(err)
+		}
+		if err := w.Close(); err != nil {
+			return nil, errors.Trace(err)
+		}
+		req, err := http.NewRequest("POST", c.endpoint+apiPath+path, &body)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		req.Header.Set("Content-Type", w.FormDataContentType())
+		return req, nil
+	}
+	return errors.Trace(c.send(newRequest, result))
+}
+
+// send sends an authenticated request made by newRequest, logging in
+// first if necessary. Tickets expire, so if the request is rejected it
+// logs in again and sends a new request.
+func (c *client) send(newRequest func() (*http.Request, error), result interface{}) error {
+	if c.username == "" {
+		req, err := newRequest()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		return c.roundTrip(req, result)
+	}
+	for i := 0; ; i++ {
+		ticket, csrfToken, err := c.credentials(i > 0)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		req, err := newRequest()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		req.AddCookie(&http.Cookie{Name: authCookie, Value: ticket})
+		if req.Method != "GET" {
+			req.Header.Set(csrfHeader, csrfToken)
+		}
+		err = c.roundTrip(req, result)
+		if i == 0 && IsAuthorisationFailure(err) {
+			continue
+		}
+		return err
+	}
+}
+
+// credentials returns the ticket and CSRF prevention token with which to
+// authenticate requests, logging in to get new ones if there are none
+// yet or renew is true.
+func (c *client) credentials(renew bool) (ticket, csrfToken string, _ error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ticket == "" || renew {
+		req, err := http.NewRequest("POST", c.endpoint+apiPath+"/access/ticket", strings.NewReader(url.Values{
+			"username": {c.username},
+			"password": {c.password},
+		}.Encode()))
+		if err != nil {
+			return "", "", errors.Trace(err)
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		var result struct {
+			Ticket              string `json:"ticket"`
+			CSRFPreventionToken string `json:"CSRFPreventionToken"`
+		}
+		if err := c.roundTrip(req, &result); err != nil {
+			return "", "", errors.Annotatef(err, "logging in as %q", c.username)
+		}
+		c.ticket, c.csrfToken = result.Ticket, result.CSRFPreventionToken
+	}
+	return c.ticket, c.csrfToken, nil
+}
+
+// roundTrip sends the request, and decodes the data in the response
+// into result if it is not nil.
+func (c *client) roundTrip(req *http.Request, result interface{}) error {
+	logger.Tracef("%s %s", req.Method, req.URL.Path)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Annotate(err, "reading response")
+	}
+	var response struct {
+		Data   json.RawMessage   `json:"data"`
+		Errors map[string]string `json:"errors"`
+	}
+	if resp.StatusCode != http.StatusOK {
+		// The body doesn't always describe the error,
+		// so a failure to decode it doesn't matter.
+		json.Unmarshal(body, &response)
+		return &apiError{
+			StatusCode: resp.StatusCode,
+			Message:    strings.TrimPrefix(resp.Status, strconv.Itoa(resp.StatusCode)+" "),
+			Errors:     response.Errors,
+		}
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return errors.Annotate(err, "decoding response")
+	}
+	if result == nil || len(response.Data) == 0 {
+		return nil
+	}
+	return errors.Annotate(json.Unmarshal(response.Data, result), "decoding response data")
+}
+
+// node describes a node in the cluster.
+type node struct {
+	Node   string `json:"node"`
+	Status string `json:"status"`
+}
+
+// nodes returns the nodes in the cluster.
+func (c *client) nodes() ([]node, error) {
+	var nodes []node
+	if err := c.do("GET", "/nodes", nil, &nodes); err != nil {
+		return nil, errors.Annotate(err, "listing nodes")
+	}
+	return nodes, nil
+}
+
+// virtualMachine describes a QEMU virtual machine, or template, as
+// listed in the cluster's resources.
+type virtualMachine struct {
+	Type     string `json:"type"`
+	VMID     int    `json:"vmid"`
+	Name     string `json:"name"`
+	Node     string `json:"node"`
+	Status   string `json:"status"`
+	Template int    `json:"template"`
+}
+
+// virtualMachines returns all of the QEMU virtual machines and
+// templates in the cluster.
+func (c *client) virtualMachines() ([]virtualMachine, error) {
+	var resources []virtualMachine
+	if err := c.do("GET", "/cluster/resources", url.Values{"type": {"vm"}}, &resources); err != nil {
+		return nil, errors.Annotate(err, "listing virtual machines")
+	}
+	// The cluster's LXC containers are listed as VM resources too.
+	vms := resources[:0]
+	for _, r := range resources {
+		if r.Type == "qemu" {
+			vms = append(vms, r)
+		}
+	}
+	return vms, nil
+}
+
+// nextID returns an unused VM ID.
+func (c *client) nextID() (int, error) {
+	var id string
+	if err := c.do("GET", "/cluster/nextid", nil, &id); err != nil {
+		return 0, errors.Annotate(err, "getting next VM ID")
+	}
+	vmid, err := strconv.Atoi(id)
+	return vmid, errors.Annotatef(err, "parsing VM ID %q", id)
+}
+
+// cloneParams holds the parameters for cloning a template VM.
+type cloneParams struct {
+	// NewID is the ID of the new VM.
+	NewID int
+
+	// Name is the name of the new VM.
+	Name string
+
+	// Target is the node on which to create the new VM.
+	Target string
+
+	// Storage is the storage to which the template's disks are
+	// copied, making a full clone. If it is empty, the new VM is a
+	// linked clone of the template.
+	Storage string
+}
+
+// cloneVM clones the given template VM, and waits for it to be cloned.
+func (c *client) cloneVM(template virtualMachine, args cloneParams) error {
+	params := url.Values{
+		"newid":  {strconv.Itoa(args.NewID)},
+		"name":   {args.Name},
+		"target": {args.Target},
+	}
+	if args.Storage != "" {
+		params.Set("full", "1")
+		params.Set("storage", args.Storage)
+	}
+	var upid string
+	if err := c.do("POST", vmPath(template.Node, template.VMID, "/clone"), params, &upid); err != nil {
+		return errors.Annotatef(err, "cloning template %q", template.Name)
+	}
+	return errors.Annotatef(c.waitTask(template.Node, upid), "cloning template %q", template.Name)
+}
+
+// vmConfig returns the configuration of a VM.
+func (c *client) vmConfig(node string, vmid int) (vmConfig, error) {
+	var cfg vmConfig
+	if err := c.do("GET", vmPath(node, vmid, "/config"), nil, &cfg); err != nil {
+		return nil, errors.Annotatef(err, "getting configuration of VM %d", vmid)
+	}
+	return cfg, nil
+}
+
+// setVMConfig updates the configuration of a VM.
+func (c *client) setVMConfig(node string, vmid int, params url.Values) error {
+	err := c.do("PUT", vmPath(node, vmid, "/config"), params, nil)
+	return errors.Annotatef(err, "configuring VM %d", vmid)
+}
+
+// resizeDisk sets the size of a VM's disk, in MiB.
+func (c *client) resizeDisk(node string, vmid int, disk string, sizeMiB uint64) error {
+	err := c.do("PUT", vmPath(node, vmid, "/resize"), url.Values{
+		"disk": {disk},
+		"size": {fmt.Sprintf("%dM", sizeMiB)},
+	}, nil)
+	return errors.Annotatef(err, "resizing disk %q of VM %d", disk, vmid)
+}
+
+// startVM starts a VM, and waits for it to start.
+func (c *client) startVM(node string, vmid int) error {
+	return errors.Annotatef(c.doTask("POST", node, vmPath(node, vmid, "/status/start"), nil), "starting VM %d", vmid)
+}
+
+// stopVM stops a VM immediately, and waits for it to stop.
+func (c *client) stopVM(node string, vmid int) error {
+	return errors.Annotatef(c.doTask("POST", node, vmPath(node, vmid, "/status/stop"), nil), "stopping VM %d", vmid)
+}
+
+// deleteVM deletes a stopped VM, along with all of its disks.
+func (c *client) deleteVM(node string, vmid int) error {
+	err := c.doTask("DELETE", node, vmPath(node, vmid, ""), url.Values{"purge": {"1"}})
+	return errors.Annotatef(err, "deleting VM %d", vmid)
+}
+
+// guestInterface describes a network interface of a VM, as reported
+// by the QEMU guest agent running in it.
+type guestInterface struct {
+	Name        string `json:"name"`
+	IPAddresses []struct {
+		IPAddress string `json:"ip-address"`
+	} `json:"ip-addresses"`
+}
+
+// vmInterfaces returns the network interfaces of a running VM. It
+// fails if the guest agent isn't running in the VM.
+func (c *client) vmInterfaces(node string, vmid int) ([]guestInterface, error) {
+	var result struct {
+		Result []guestInterface `json:"result"`
+	}
+	if err := c.do("GET", vmPath(node, vmid, "/agent/network-get-interfaces"), nil, &result); err != nil {
+		return nil, errors.Annotatef(err, "getting network interfaces of VM %d", vmid)
+	}
+	return result.Result, nil
+}
+
+// uploadISO uploads an ISO image to a node's storage.
+func (c *client) uploadISO(node, storage, filename string, content []byte) error {
+	var upid string
+	err := c.upload(storagePath(node, storage, "/upload"), map[string]string{
+		"content": "iso",
+	}, filename, content, &upid)
+	if err == nil && upid != "" {
+		err = c.waitTask(node, upid)
+	}
+	return errors.Annotatef(err, "uploading %q to storage %q", filename, storage)
+}
+
+// storageVolume describes a volume in a node's storage.
+type storageVolume struct {
+	VolID string `json:"volid"`
+	VMID  int    `json:"vmid"`
+	Size  uint64 `json:"size"`
+}
+
+// createVolume creates a disk of the given size, in MiB, in a node's
+// storage, owned by the VM with the given ID. It returns the ID of
+// the new volume.
+func (c *client) createVolume(node, storage string, vmid int, filename string, sizeMiB uint64) (string, error) {
+	var volid string
+	err := c.do("POST", storagePath(node, storage, "/content"), url.Values{
+		"vmid":     {strconv.Itoa(vmid)},
+		"filename": {filename},
+		"size":     {fmt.Sprintf("%dM", sizeMiB)},
+	}, &volid)
+	return volid, errors.Annotatef(err, "creating volume %q in storage %q", filename, storage)
+}
+
+// storageVolumes returns the disk volumes in a node's storage.
+func (c *client) storageVolumes(node, storage string) ([]storageVolume, error) {
+	var volumes []storageVolume
+	err := c.do("GET", storagePath(node, storage, "/content"), url.Values{"content": {"images"}}, &volumes)
+	return volumes, errors.Annotatef(err, "listing volumes in storage %q", storage)
+}
+
+// storageVolume returns the volume with the given ID in a node's storage.
+func (c *client) storageVolume(node, volid string) (storageVolume, error) {
+	storage := strings.SplitN(volid, ":", 2)[0]
+	volume := storageVolume{VolID: volid}
+	err := c.do("GET", storagePath(node, storage, "/content/"+url.PathEscape(volid)), nil, &volume)
+	return volume, errors.Annotatef(err, "getting volume %q", volid)
+}
+
+// deleteVolume deletes the volume with the given ID from a node's
+// storage.
+func (c *client) deleteVolume(node, volid string) error {
+	storage := strings.SplitN(volid, ":", 2)[0]
+	var upid string
+	err := c.do("DELETE", storagePath(node, storage, "/content/"+url.PathEscape(volid)), nil, &upid)
+	if err == nil && upid != "" {
+		err = c.waitTask(node, upid)
+	}
+	return errors.Annotatef(err, "deleting volume %q", volid)
+}
+
+// doTask makes an API request that starts a task on the node,
+// and waits for the task to finish.
+func (c *client) doTask(method, node, path string, params url.Values) error {
+	var upid string
+	if err := c.do(method, path, params, &upid); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(c.waitTask(node, upid))
+}
+
+// waitTask waits for the task with the given ID on the node to finish,
+// and returns an error if it failed.
+func (c *client) waitTask(node, upid string) error {
+	timeout := c.clock.After(taskTimeout)
+	for {
+		var status struct {
+			Status     string `json:"status"`
+			ExitStatus string `json:"exitstatus"`
+		}
+		path := "/nodes/" + url.PathEscape(node) + "/tasks/" + url.PathEscape(upid) + "/status"
+		if err := c.do("GET", path, nil, &status); err != nil {
+			return errors.Annotate(err, "getting task status")
+		}
+		if status.Status == "stopped" {
+			if status.ExitStatus != "OK" {
+				return errors.Errorf("task failed: %s", status.ExitStatus)
+			}
+			return nil
+		}
+		select {
+		case <-c.clock.After(taskPollInterval):
+		case <-timeout:
+			return errors.Errorf("timed out waiting for task %q", upid)
+		}
+	}
+}
+
+func vmPath(node string, vmid int, path string) string {
+	return fmt.Sprintf("/nodes/%s/qemu/%d%s", url.PathEscape(node), vmid, path)
+}
+
+func storagePath(node, storage, path string) string {
+	return fmt.Sprintf("/nodes/%s/storage/%s%s", url.PathEscape(node), url.PathEscape(storage), path)
+}
+
+// vmConfig holds the configuration of a VM. The values
+// are strings or numbers, depending on the option.
+type vmConfig map[string]interface{}
+
+// get returns the value of the given option as a string,
+// or "" if it is not set.
+func (cfg vmConfig) get(option string) string {
+	switch v := cfg[option].(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package proxmox
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/schema"
+
+	"github.com/juju/juju/environs/config"
+)
+
+// The proxmox-specific config keys.
+const (
+	// cfgTemplatePrefix is the prefix of the names of the template
+	// VMs that machines are cloned from. The template for a series is
+	// found by appending the series to the prefix, e.g. "juju-bionic".
+	cfgTemplatePrefix = "template-prefix"
+
+	// cfgCloneStorage is the storage to which machines' disks are
+	// copied. If it is not set, machines are linked clones of their
+	// template, and share its storage.
+	cfgCloneStorage = "clone-storage"
+
+	// cfgSeedStorage is the storage to which the ISO images holding
+	// the cloud-init data for machines are uploaded. It must be
+	// available on every node, and allow ISO image content.
+	cfgSeedStorage = "seed-storage"
+)
+
+// configFields is the spec for each proxmox config value's type.
+var (
+	configFields = schema.Fields{
+		cfgTemplatePrefix: schema.String(),
+		cfgCloneStorage:   schema.String(),
+		cfgSeedStorage:    schema.String(),
+	}
+
+	configDefaults = schema.Defaults{
+		cfgTemplatePrefix: "juju-",
+		cfgCloneStorage:   schema.Omit,
+		cfgSeedStorage:    "local",
+	}
+
+	configRequiredFields  = []string{cfgTemplatePrefix, cfgSeedStorage}
+	configImmutableFields = []string{}
+)
+
+type environConfig struct {
+	*config.Config
+	attrs map[string]interface{}
+}
+
+// newConfig builds a new environConfig from the provided Config and
+// returns it.
+func newConfig(cfg *config.Config) *environConfig {
+	return &environConfig{
+		Config: cfg,
+		attrs:  cfg.UnknownAttrs(),
+	}
+}
+
+// newValidConfig builds a new environConfig from the provided Config
+// and returns it. The resulting config values are validated.
+func newValidConfig(cfg *config.Config) (*environConfig, error) {
+	// Ensure that the provided config is valid.
+	if err := config.Validate(cfg, nil); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	// Apply the defaults and coerce/validate the custom config attrs.
+	validated, err := cfg.ValidateUnknownAttrs(configFields, configDefaults)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	validCfg, err := cfg.Apply(validated)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	// Build the config.
+	ecfg := newConfig(validCfg)
+
+	// Do final validation.
+	if err := ecfg.validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return ecfg, nil
+}
+
+// templateName returns the name of the template VM from which
+// machines running the given series are cloned.
+func (c *environConfig) templateName(series string) string {
+	return c.attrs[cfgTemplatePrefix].(string) + series
+}
+
+func (c *environConfig) cloneStorage() string {
+	storage, _ := c.attrs[cfgCloneStorage].(string)
+	return storage
+}
+
+func (c *environConfig) seedStorage() string {
+	return c.attrs[cfgSeedStorage].(string)
+}
+
+// validate checks proxmox-specific config values.
+func (c environConfig) validate() error {
+	// All fields must be populated, even with just the default.
+	for _, field := range configRequiredFields {
+		if c.attrs[field].(string) == "" {
+			return errors.Errorf("%s: must not be empty", field)
+		}
+	}
+	return nil
+}
+
+// update applies changes from the provided config to the env config.
+// Changes to any immutable attributes result in an error.
+func (c *environConfig) update(cfg *config.Config) error {
+	// Validate the updates. newValidConfig does not modify the "known"
+	// config attributes so it is safe to call Validate here first.
+	if err := config.Validate(cfg, c.Config); err != nil {
+		return errors.Trace(err)
+	}
+
+	updates, err := newValidConfig(cfg)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	// Check that no immutable fields have changed.
+	attrs := updates.UnknownAttrs()
+	for _, field := range configImmutableFields {
+		if attrs[field] != c.attrs[field] {
+			return errors.Errorf("%s: cannot change from %v to %v", field, c.attrs[field], attrs[field])
+		}
+	}
+
+	// Apply the updates.
+	c.Config = updates.Config
+	c.attrs = updates.attrs
+	return nil
+}
@@ -0,0 +1,242 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package proxmox
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/juju/errors"
+	"github.com/juju/version"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/environs/tags"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/provider/common"
+)
+
+type environ struct {
+	name     string
+	cloud    environs.CloudSpec
+	provider *environProvider
+	client   *client
+
+	// namespace is used to create the machine names.
+	namespace instance.Namespace
+
+	lock sync.Mutex // lock protects access the following fields.
+	ecfg *environConfig
+}
+
+var _ common.ZonedEnviron = (*environ)(nil)
+
+func newEnviron(
+	provider *environProvider,
+	cloud environs.CloudSpec,
+	cfg *config.Config,
+) (*environ, error) {
+	ecfg, err := newValidConfig(cfg)
+	if err != nil {
+		return nil, errors.Annotate(err, "invalid config")
+	}
+
+	namespace, err := instance.NewNamespace(cfg.UUID())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	client, err := newClient(cloud, ecfg.SSLHostnameVerification())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	env := &environ{
+		name:      ecfg.Name(),
+		cloud:     cloud,
+		provider:  provider,
+		client:    client,
+		ecfg:      ecfg,
+		namespace: namespace,
+	}
+	return env, nil
+}
+
+// Name is part of the environs.Environ interface.
+func (env *environ) Name() string {
+	return env.name
+}
+
+// Provider is part of the environs.Environ interface.
+func (env *environ) Provider() environs.EnvironProvider {
+	return env.provider
+}
+
+// SetConfig is part of the environs.Environ interface.
+func (env *environ) SetConfig(cfg *config.Config) error {
+	env.lock.Lock()
+	defer env.lock.Unlock()
+
+	if env.ecfg == nil {
+		return errors.New("cannot set config on uninitialized env")
+	}
+
+	if err := env.ecfg.update(cfg); err != nil {
+		return errors.Annotate(err, "invalid config change")
+	}
+	return nil
+}
+
+// Config is part of the environs.Environ interface.
+func (env *environ) Config() *config.Config {
+	return env.config().Config
+}
+
+func (env *environ) config() *environConfig {
+	env.lock.Lock()
+	defer env.lock.Unlock()
+	ecfg := *env.ecfg
+	return &ecfg
+}
+
+// PrepareForBootstrap implements environs.Environ.
+func (env *environ) PrepareForBootstrap(ctx environs.BootstrapContext) error {
+	return nil
+}
+
+// Create implements environs.Environ.
+func (env *environ) Create(ctx context.ProviderCallContext, args environs.CreateParams) error {
+	return nil
+}
+
+// Bootstrap is part of the environs.Environ interface.
+func (env *environ) Bootstrap(
+	ctx environs.BootstrapContext,
+	callCtx context.ProviderCallContext,
+	args environs.BootstrapParams,
+) (*environs.BootstrapResult, error) {
+	return common.Bootstrap(ctx, env, callCtx, args)
+}
+
+// ControllerInstances is part of the environs.Environ interface.
+func (env *environ) ControllerInstances(ctx context.ProviderCallContext, controllerUUID string) ([]instance.Id, error) {
+	vms, err := env.taggedVMs(ctx, env.namespace.Prefix(), func(vmTags map[string]string) bool {
+		return vmTags[tags.JujuIsController] == "true" &&
+			vmTags[tags.JujuController] == controllerUUID
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(vms) == 0 {
+		return nil, environs.ErrNotBootstrapped
+	}
+	ids := make([]instance.Id, len(vms))
+	for i, vm := range vms {
+		ids[i] = instance.Id(vm.Name)
+	}
+	return ids, nil
+}
+
+// AdoptResources is part of the environs.Environ interface.
+func (env *environ) AdoptResources(ctx context.ProviderCallContext, controllerUUID string, fromVersion version.Number) error {
+	vms, err := env.modelVMs(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var failed []string
+	for _, vm := range vms {
+		if err := env.adoptVM(vm, controllerUUID); err != nil {
+			handleCredentialError(err, ctx)
+			logger.Errorf("updating controller for %q: %v", vm.Name, err)
+			failed = append(failed, vm.Name)
+		}
+	}
+	if len(failed) > 0 {
+		return errors.Errorf("failed to update controller for machines: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+func (env *environ) adoptVM(vm virtualMachine, controllerUUID string) error {
+	cfg, err := env.client.vmConfig(vm.Node, vm.VMID)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	vmTags := vmTags(cfg)
+	vmTags[tags.JujuController] = controllerUUID
+	return env.client.setVMConfig(vm.Node, vm.VMID, url.Values{
+		"description": {vmDescription(vmTags)},
+	})
+}
+
+// Destroy is part of the environs.Environ interface.
+func (env *environ) Destroy(ctx context.ProviderCallContext) error {
+	return errors.Trace(common.Destroy(env, ctx))
+}
+
+// DestroyController is part of the environs.Environ interface.
+func (env *environ) DestroyController(ctx context.ProviderCallContext, controllerUUID string) error {
+	if err := env.Destroy(ctx); err != nil {
+		return errors.Trace(err)
+	}
+	// Remove the machines of any hosted models too.
+	vms, err := env.taggedVMs(ctx, "juju-", func(vmTags map[string]string) bool {
+		return vmTags[tags.JujuController] == controllerUUID
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(env.deleteVMs(ctx, vms))
+}
+
+// modelVMs returns the VMs that are machines in the model.
+func (env *environ) modelVMs(ctx context.ProviderCallContext) ([]virtualMachine, error) {
+	return env.vmsWithPrefix(ctx, env.namespace.Prefix())
+}
+
+// vmsWithPrefix returns the VMs, other than templates, whose names
+// start with the given prefix.
+func (env *environ) vmsWithPrefix(ctx context.ProviderCallContext, prefix string) ([]virtualMachine, error) {
+	all, err := env.client.virtualMachines()
+	if err != nil {
+		handleCredentialError(err, ctx)
+		return nil, errors.Trace(err)
+	}
+	var vms []virtualMachine
+	for _, vm := range all {
+		if vm.Template == 0 && strings.HasPrefix(vm.Name, prefix) {
+			vms = append(vms, vm)
+		}
+	}
+	return vms, nil
+}
+
+// taggedVMs returns the VMs whose names start with the given prefix,
+// and whose tags are accepted by match.
+func (env *environ) taggedVMs(
+	ctx context.ProviderCallContext,
+	prefix string,
+	match func(map[string]string) bool,
+) ([]virtualMachine, error) {
+	vms, err := env.vmsWithPrefix(ctx, prefix)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var matched []virtualMachine
+	for _, vm := range vms {
+		cfg, err := env.client.vmConfig(vm.Node, vm.VMID)
+		if isNotFound(err) {
+			continue
+		} else if err != nil {
+			handleCredentialError(err, ctx)
+			return nil, errors.Trace(err)
+		}
+		if match(vmTags(cfg)) {
+			matched = append(matched, vm)
+		}
+	}
+	return matched, nil
+}
@@ -0,0 +1,106 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package proxmox
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/provider/common"
+)
+
+// proxmoxAvailZone is an availability zone, which is
+// a node in the cluster.
+type proxmoxAvailZone struct {
+	node node
+}
+
+// Name implements common.AvailabilityZone
+func (z *proxmoxAvailZone) Name() string {
+	return z.node.Node
+}
+
+// Available implements common.AvailabilityZone
+func (z *proxmoxAvailZone) Available() bool {
+	return z.node.Status == "online"
+}
+
+// AvailabilityZones is part of the common.ZonedEnviron interface.
+func (env *environ) AvailabilityZones(ctx context.ProviderCallContext) ([]common.AvailabilityZone, error) {
+	nodes, err := env.client.nodes()
+	if err != nil {
+		handleCredentialError(err, ctx)
+		return nil, errors.Trace(err)
+	}
+	zones := make([]common.AvailabilityZone, len(nodes))
+	for i, n := range nodes {
+		zones[i] = &proxmoxAvailZone{n}
+	}
+	return zones, nil
+}
+
+// InstanceAvailabilityZoneNames is part of the common.ZonedEnviron interface.
+func (env *environ) InstanceAvailabilityZoneNames(ctx context.ProviderCallContext, ids []instance.Id) ([]string, error) {
+	instances, err := env.Instances(ctx, ids)
+	switch err {
+	case nil, environs.ErrPartialInstances:
+		break
+	case environs.ErrNoInstances:
+		return nil, err
+	default:
+		return nil, errors.Trace(err)
+	}
+
+	results := make([]string, len(ids))
+	for i, inst := range instances {
+		if inst != nil {
+			results[i] = inst.(*environInstance).vm.Node
+		}
+	}
+	return results, err
+}
+
+// DeriveAvailabilityZones is part of the common.ZonedEnviron interface.
+func (env *environ) DeriveAvailabilityZones(ctx context.ProviderCallContext, args environs.StartInstanceParams) ([]string, error) {
+	zone, err := env.parsePlacement(ctx, args.Placement)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if zone != "" {
+		return []string{zone}, nil
+	}
+	return nil, nil
+}
+
+// parsePlacement extracts the availability zone from the placement
+// string and returns it, after checking that it exists.
+func (env *environ) parsePlacement(ctx context.ProviderCallContext, placement string) (string, error) {
+	if placement == "" {
+		return "", nil
+	}
+
+	pos := strings.IndexRune(placement, '=')
+	if pos == -1 {
+		return "", errors.Errorf("unknown placement directive: %v", placement)
+	}
+
+	switch key, value := placement[:pos], placement[pos+1:]; key {
+	case "zone":
+		zones, err := env.AvailabilityZones(ctx)
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+		for _, z := range zones {
+			if z.Name() == value {
+				return value, nil
+			}
+		}
+		return "", errors.NotFoundf("availability zone %q", value)
+	}
+	return "", errors.Errorf("unknown placement directive: %v", placement)
+}
@@ -0,0 +1,101 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package proxmox_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/provider/common"
+)
+
+type environAvailzonesSuite struct {
+	EnvironFixture
+}
+
+var _ = gc.Suite(&environAvailzonesSuite{})
+
+func (s *environAvailzonesSuite) TestAvailabilityZones(c *gc.C) {
+	s.api.Nodes["pve2"] = "offline"
+
+	c.Assert(s.env, gc.Implements, new(common.ZonedEnviron))
+	zonedEnviron := s.env.(common.ZonedEnviron)
+	zones, err := zonedEnviron.AvailabilityZones(s.callCtx)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(zones, gc.HasLen, 2)
+	c.Assert(zones[0].Name(), gc.Equals, "pve1")
+	c.Assert(zones[0].Available(), jc.IsTrue)
+	c.Assert(zones[1].Name(), gc.Equals, "pve2")
+	c.Assert(zones[1].Available(), jc.IsFalse)
+}
+
+func (s *environAvailzonesSuite) TestInstanceAvailabilityZoneNames(c *gc.C) {
+	s.api.AddVM("pve2", "juju-f75cba-0")
+	s.api.AddVM("pve1", "juju-f75cba-1")
+	ids := []instance.Id{"juju-f75cba-0", "juju-f75cba-1", "juju-f75cba-2"}
+
+	zonedEnviron := s.env.(common.ZonedEnviron)
+	zones, err := zonedEnviron.InstanceAvailabilityZoneNames(s.callCtx, ids)
+	c.Assert(err, gc.Equals, environs.ErrPartialInstances)
+	c.Assert(zones, jc.DeepEquals, []string{"pve2", "pve1", ""})
+}
+
+func (s *environAvailzonesSuite) TestInstanceAvailabilityZoneNamesNoInstances(c *gc.C) {
+	zonedEnviron := s.env.(common.ZonedEnviron)
+	_, err := zonedEnviron.InstanceAvailabilityZoneNames(s.callCtx, []instance.Id{"juju-f75cba-0"})
+	c.Assert(err, gc.Equals, environs.ErrNoInstances)
+}
+
+func (s *environAvailzonesSuite) TestDeriveAvailabilityZones(c *gc.C) {
+	zonedEnviron := s.env.(common.ZonedEnviron)
+	zones, err := zonedEnviron.DeriveAvailabilityZones(s.callCtx, environs.StartInstanceParams{
+		Placement: "zone=pve2",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(zones, jc.DeepEquals, []string{"pve2"})
+
+	zones, err = zonedEnviron.DeriveAvailabilityZones(s.callCtx, environs.StartInstanceParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(zones, gc.HasLen, 0)
+}
+
+func (s *environAvailzonesSuite) TestPrecheckInstancePlacement(c *gc.C) {
+	for _, test := range []struct {
+		placement string
+		err       string
+	}{
+		{placement: ""},
+		{placement: "zone=pve1"},
+		{placement: "zone=pve3", err: `availability zone "pve3" not found`},
+		{placement: "node=pve1", err: "unknown placement directive: node=pve1"},
+		{placement: "pve1", err: "unknown placement directive: pve1"},
+	} {
+		c.Logf("placement %q", test.placement)
+		err := s.env.PrecheckInstance(s.callCtx, environs.PrecheckInstanceParams{
+			Series:    "bionic",
+			Placement: test.placement,
+		})
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.err)
+		}
+	}
+}
+
+func (s *environAvailzonesSuite) TestConstraintsValidator(c *gc.C) {
+	validator, err := s.env.ConstraintsValidator(s.callCtx)
+	c.Assert(err, jc.ErrorIsNil)
+
+	cons := constraints.MustParse("arch=amd64 cores=2 mem=2G tags=foo virt-type=kvm")
+	unsupported, err := validator.Validate(cons)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(unsupported, jc.SameContents, []string{"tags", "virt-type"})
+
+	_, err = validator.Validate(constraints.MustParse("arch=arm64"))
+	c.Assert(err, gc.ErrorMatches, "invalid constraint value: arch=arm64\nvalid values are: \\[amd64\\]")
+}
@@ -0,0 +1,399 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package proxmox
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/arch"
+
+	"github.com/juju/juju/cloudconfig/cloudinit"
+	"github.com/juju/juju/cloudconfig/instancecfg"
+	"github.com/juju/juju/cloudconfig/providerinit"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/provider/common"
+	"github.com/juju/juju/tools"
+)
+
+// defaultBootDisk is the disk that a VM boots from if
+// its configuration doesn't say otherwise.
+const defaultBootDisk = "scsi0"
+
+// MaintainInstance is specified in the InstanceBroker interface.
+func (*environ) MaintainInstance(ctx context.ProviderCallContext, args environs.StartInstanceParams) error {
+	return nil
+}
+
+// StartInstance implements environs.InstanceBroker.
+func (env *environ) StartInstance(ctx context.ProviderCallContext, args environs.StartInstanceParams) (*environs.StartInstanceResult, error) {
+	instArch, err := instanceArch(args.Constraints)
+	if err != nil {
+		return nil, common.ZoneIndependentError(err)
+	}
+	if err := env.finishInstanceConfig(args, instArch); err != nil {
+		return nil, common.ZoneIndependentError(err)
+	}
+
+	vm, hw, err := env.newRawInstance(ctx, args, instArch)
+	if err != nil {
+		handleCredentialError(err, ctx)
+		if args.StatusCallback != nil {
+			args.StatusCallback(status.ProvisioningError, err.Error(), nil)
+		}
+		return nil, errors.Trace(err)
+	}
+	logger.Infof("started instance %q", vm.Name)
+	return &environs.StartInstanceResult{
+		Instance: newInstance(vm, env),
+		Hardware: hw,
+	}, nil
+}
+
+// instanceArch returns the architecture of the machine to start. The
+// templates are amd64 VMs, so no other architecture can be provided.
+func instanceArch(cons constraints.Value) (string, error) {
+	if cons.HasArch() && *cons.Arch != arch.AMD64 {
+		return "", errors.NotSupportedf("architecture %q", *cons.Arch)
+	}
+	return arch.AMD64, nil
+}
+
+// finishInstanceConfig updates args.InstanceConfig in place, setting up
+// the API, StateServing, and SSHkeys information.
+func (env *environ) finishInstanceConfig(args environs.StartInstanceParams, instArch string) error {
+	envTools, err := args.Tools.Match(tools.Filter{Arch: instArch})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := args.InstanceConfig.SetTools(envTools); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(instancecfg.FinishInstanceConfig(args.InstanceConfig, env.Config()))
+}
+
+// newRawInstance clones the template VM for the series to make the new
+// machine, sizes it to fit the constraints, gives it its cloud-init
+// seed image, and starts it.
+func (env *environ) newRawInstance(
+	ctx context.ProviderCallContext,
+	args environs.StartInstanceParams,
+	instArch string,
+) (_ virtualMachine, _ *instance.HardwareCharacteristics, err error) {
+	ecfg := env.config()
+	series := args.InstanceConfig.Series
+	name, err := env.namespace.Hostname(args.InstanceConfig.MachineId)
+	if err != nil {
+		return virtualMachine{}, nil, common.ZoneIndependentError(err)
+	}
+
+	vms, err := env.client.virtualMachines()
+	if err != nil {
+		return virtualMachine{}, nil, errors.Trace(err)
+	}
+	templateName := ecfg.templateName(series)
+	template, ok := findTemplate(vms, templateName)
+	if !ok {
+		return virtualMachine{}, nil, common.ZoneIndependentError(
+			errors.NotFoundf("template VM %q", templateName),
+		)
+	}
+	userData, err := env.userData(args)
+	if err != nil {
+		return virtualMachine{}, nil, common.ZoneIndependentError(err)
+	}
+
+	vm := virtualMachine{
+		Type:   "qemu",
+		Name:   name,
+		Node:   args.AvailabilityZone,
+		Status: "running",
+	}
+	if vm.Node == "" {
+		vm.Node = template.Node
+	}
+	if args.StatusCallback != nil {
+		args.StatusCallback(status.Provisioning, fmt.Sprintf("cloning %s", templateName), nil)
+	}
+	if vm.VMID, err = env.cloneTemplate(template, cloneParams{
+		Name:    name,
+		Target:  vm.Node,
+		Storage: ecfg.cloneStorage(),
+	}); err != nil {
+		return virtualMachine{}, nil, errors.Trace(err)
+	}
+	defer func() {
+		if err == nil {
+			return
+		}
+		if err := env.deleteVM(vm); err != nil {
+			logger.Errorf("cleaning up %q: %v", name, err)
+		}
+	}()
+
+	seed := newSeedImage(userData, seedMetaData(name))
+	if err := env.client.uploadISO(vm.Node, ecfg.seedStorage(), seedFileName(name), seed); err != nil {
+		return virtualMachine{}, nil, errors.Trace(err)
+	}
+
+	cfg, err := env.client.vmConfig(vm.Node, vm.VMID)
+	if err != nil {
+		return virtualMachine{}, nil, errors.Trace(err)
+	}
+	hw, err := env.configureVM(vm, cfg, args, instArch)
+	if err != nil {
+		return virtualMachine{}, nil, errors.Trace(err)
+	}
+
+	if err := env.client.startVM(vm.Node, vm.VMID); err != nil {
+		return virtualMachine{}, nil, errors.Trace(err)
+	}
+	return vm, hw, nil
+}
+
+// cloneAttempts is how many VM IDs are tried when cloning a template.
+const cloneAttempts = 5
+
+// cloneTemplate clones the given template VM with a new VM ID, which
+// it returns. The API only suggests an ID that is free at the time,
+// so if another client takes it before the clone is made, the clone is
+// tried again with the next free ID.
+func (env *environ) cloneTemplate(template virtualMachine, args cloneParams) (int, error) {
+	for attempt := 1; ; attempt++ {
+		vmid, err := env.client.nextID()
+		if err != nil {
+			return 0, errors.Trace(err)
+		}
+		args.NewID = vmid
+		err = env.client.cloneVM(template, args)
+		if err == nil {
+			return vmid, nil
+		}
+		if !isVMIDConflict(err) || attempt == cloneAttempts {
+			return 0, errors.Trace(err)
+		}
+		logger.Debugf("VM ID %d taken while cloning %q, retrying", vmid, template.Name)
+	}
+}
+
+// userData returns the cloud-init user data for the machine.
+func (env *environ) userData(args environs.StartInstanceParams) ([]byte, error) {
+	cloudcfg, err := cloudinit.New(args.InstanceConfig.Series)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	// The guest agent reports the machine's addresses.
+	cloudcfg.AddPackage("qemu-guest-agent")
+	cloudcfg.AddRunCmd("systemctl start qemu-guest-agent")
+	// Make sure the hostname is resolvable by adding it to /etc/hosts.
+	cloudcfg.ManageEtcHosts(true)
+
+	userData, err := providerinit.ComposeUserData(args.InstanceConfig, cloudcfg, proxmoxRenderer{})
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot make user data")
+	}
+	logger.Debugf("proxmox user data; %d bytes", len(userData))
+	return userData, nil
+}
+
+// configureVM applies the constraints to the cloned VM with the given
+// configuration, attaches its seed image, and returns the hardware it
+// will have.
+func (env *environ) configureVM(
+	vm virtualMachine,
+	cfg vmConfig,
+	args environs.StartInstanceParams,
+	instArch string,
+) (*instance.HardwareCharacteristics, error) {
+	cons := args.Constraints
+	hw := &instance.HardwareCharacteristics{
+		Arch:             &instArch,
+		AvailabilityZone: &vm.Node,
+	}
+	params := url.Values{
+		"description": {vmDescription(args.InstanceConfig.Tags)},
+		"agent":       {"1"},
+		"ide2":        {fmt.Sprintf("%s:iso/%s,media=cdrom", env.config().seedStorage(), seedFileName(vm.Name))},
+	}
+	if cons.HasCpuCores() {
+		params.Set("cores", strconv.FormatUint(*cons.CpuCores, 10))
+		hw.CpuCores = cons.CpuCores
+	} else if cores, err := strconv.ParseUint(cfg.get("cores"), 10, 64); err == nil {
+		hw.CpuCores = &cores
+	}
+	if cons.HasMem() {
+		params.Set("memory", strconv.FormatUint(*cons.Mem, 10))
+		hw.Mem = cons.Mem
+	} else if mem, err := strconv.ParseUint(cfg.get("memory"), 10, 64); err == nil {
+		hw.Mem = &mem
+	}
+	if err := env.client.setVMConfig(vm.Node, vm.VMID, params); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	// Grow the boot disk if it's smaller than required. Disks can't be
+	// shrunk, so a larger template disk is left as it is.
+	rootDisk := common.MinRootDiskSizeGiB(args.InstanceConfig.Series) * 1024
+	if cons.RootDisk != nil && *cons.RootDisk > rootDisk {
+		rootDisk = *cons.RootDisk
+	}
+	bootDisk := cfg.get("bootdisk")
+	if bootDisk == "" {
+		bootDisk = defaultBootDisk
+	}
+	size, err := diskSize(cfg.get(bootDisk))
+	if err != nil {
+		return nil, errors.Annotatef(err, "boot disk %q", bootDisk)
+	}
+	if size < rootDisk {
+		if err := env.client.resizeDisk(vm.Node, vm.VMID, bootDisk, rootDisk); err != nil {
+			return nil, errors.Trace(err)
+		}
+		size = rootDisk
+	}
+	hw.RootDisk = &size
+	return hw, nil
+}
+
+// findTemplate returns the template VM with the given name.
+func findTemplate(vms []virtualMachine, name string) (virtualMachine, bool) {
+	for _, vm := range vms {
+		if vm.Template == 1 && vm.Name == name {
+			return vm, true
+		}
+	}
+	return virtualMachine{}, false
+}
+
+// diskSize returns the size, in MiB, of the disk with the given
+// configuration, e.g. "local-lvm:vm-100-disk-0,size=10G".
+func diskSize(disk string) (uint64, error) {
+	if disk == "" {
+		return 0, errors.NotFoundf("disk")
+	}
+	for _, option := range strings.Split(disk, ",") {
+		if !strings.HasPrefix(option, "size=") {
+			continue
+		}
+		size := strings.TrimPrefix(option, "size=")
+		multiplier := map[byte]float64{
+			'K': 1.0 / 1024,
+			'M': 1,
+			'G': 1024,
+			'T': 1024 * 1024,
+		}[size[len(size)-1]]
+		if multiplier == 0 {
+			// Sizes without a unit are in bytes.
+			multiplier = 1.0 / (1024 * 1024)
+		} else {
+			size = size[:len(size)-1]
+		}
+		n, err := strconv.ParseFloat(size, 64)
+		if err != nil {
+			return 0, errors.NotValidf("disk size %q", option)
+		}
+		return uint64(n * multiplier), nil
+	}
+	return 0, errors.NotFoundf("size of disk %q", disk)
+}
+
+// AllInstances implements environs.InstanceBroker.
+func (env *environ) AllInstances(ctx context.ProviderCallContext) ([]instance.Instance, error) {
+	vms, err := env.modelVMs(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	instances := make([]instance.Instance, len(vms))
+	for i, vm := range vms {
+		instances[i] = newInstance(vm, env)
+	}
+	return instances, nil
+}
+
+// Instances implements environs.Environ.
+func (env *environ) Instances(ctx context.ProviderCallContext, ids []instance.Id) ([]instance.Instance, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	vms, err := env.modelVMs(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	byName := make(map[instance.Id]virtualMachine)
+	for _, vm := range vms {
+		byName[instance.Id(vm.Name)] = vm
+	}
+	var found int
+	instances := make([]instance.Instance, len(ids))
+	for i, id := range ids {
+		if vm, ok := byName[id]; ok {
+			instances[i] = newInstance(vm, env)
+			found++
+		}
+	}
+	if found == 0 {
+		return nil, environs.ErrNoInstances
+	} else if found < len(ids) {
+		return instances, environs.ErrPartialInstances
+	}
+	return instances, nil
+}
+
+// StopInstances implements environs.InstanceBroker.
+func (env *environ) StopInstances(ctx context.ProviderCallContext, ids ...instance.Id) error {
+	vms, err := env.modelVMs(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	stopping := make(map[instance.Id]bool)
+	for _, id := range ids {
+		stopping[id] = true
+	}
+	var toDelete []virtualMachine
+	for _, vm := range vms {
+		if stopping[instance.Id(vm.Name)] {
+			toDelete = append(toDelete, vm)
+		}
+	}
+	return errors.Trace(env.deleteVMs(ctx, toDelete))
+}
+
+// deleteVMs deletes the given VMs, and their seed images.
+func (env *environ) deleteVMs(ctx context.ProviderCallContext, vms []virtualMachine) error {
+	var failed []string
+	for _, vm := range vms {
+		if err := env.deleteVM(vm); err != nil {
+			handleCredentialError(err, ctx)
+			logger.Errorf("deleting %q: %v", vm.Name, err)
+			failed = append(failed, vm.Name)
+		}
+	}
+	if len(failed) > 0 {
+		return errors.Errorf("failed to stop instances %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// deleteVM stops and deletes a VM, and then deletes its seed image.
+func (env *environ) deleteVM(vm virtualMachine) error {
+	err := env.client.stopVM(vm.Node, vm.VMID)
+	if err == nil {
+		err = env.client.deleteVM(vm.Node, vm.VMID)
+	}
+	if err != nil && !isNotFound(err) {
+		return errors.Trace(err)
+	}
+	seed := fmt.Sprintf("%s:iso/%s", env.config().seedStorage(), seedFileName(vm.Name))
+	if err := env.client.deleteVolume(vm.Node, seed); err != nil && !isNotFound(err) {
+		return errors.Trace(err)
+	}
+	return nil
+}
@@ -0,0 +1,307 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package proxmox_test
+
+import (
+	"bytes"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/arch"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cloud"
+	"github.com/juju/juju/cloudconfig/instancecfg"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/environs"
+	callcontext "github.com/juju/juju/environs/context"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/provider/common"
+	"github.com/juju/juju/provider/proxmox"
+	coretesting "github.com/juju/juju/testing"
+	coretools "github.com/juju/juju/tools"
+)
+
+type environBrokerSuite struct {
+	EnvironFixture
+	statusCallbackStub testing.Stub
+}
+
+var _ = gc.Suite(&environBrokerSuite{})
+
+func (s *environBrokerSuite) SetUpTest(c *gc.C) {
+	s.EnvironFixture.SetUpTest(c)
+	s.statusCallbackStub.ResetCalls()
+	s.api.AddTemplate("pve1", "juju-trusty")
+}
+
+func (s *environBrokerSuite) createStartInstanceArgs(c *gc.C) environs.StartInstanceParams {
+	var cons constraints.Value
+	instanceConfig, err := instancecfg.NewBootstrapInstanceConfig(
+		coretesting.FakeControllerConfig(), cons, cons, "trusty", "",
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	instanceConfig.AuthorizedKeys = fakeConfig(c).AuthorizedKeys()
+
+	tools := coretools.List{{
+		Version: version.Binary{
+			Number: version.MustParse("1.2.3"),
+			Arch:   arch.AMD64,
+			Series: "trusty",
+		},
+		URL: "https://example.org",
+	}}
+	err = instanceConfig.SetTools(tools)
+	c.Assert(err, jc.ErrorIsNil)
+
+	return environs.StartInstanceParams{
+		ControllerUUID: instanceConfig.Controller.Config.ControllerUUID(),
+		InstanceConfig: instanceConfig,
+		Tools:          tools,
+		Constraints:    cons,
+		StatusCallback: func(status status.Status, info string, data map[string]interface{}) error {
+			s.statusCallbackStub.AddCall("StatusCallback", status, info, data)
+			return s.statusCallbackStub.NextErr()
+		},
+	}
+}
+
+func (s *environBrokerSuite) TestStartInstance(c *gc.C) {
+	args := s.createStartInstanceArgs(c)
+	args.InstanceConfig.Tags = map[string]string{
+		"k0": "v0",
+		"k1": "v1",
+	}
+
+	result, err := s.env.StartInstance(s.callCtx, args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Instance.Id(), gc.Equals, instance.Id("juju-f75cba-0"))
+
+	vm := s.api.VMNamed("juju-f75cba-0")
+	c.Assert(vm, gc.NotNil)
+	c.Assert(vm.Node, gc.Equals, "pve1")
+	c.Assert(vm.Status, gc.Equals, "running")
+	c.Assert(vm.Config["description"], gc.Equals, "k0=v0\nk1=v1")
+	c.Assert(vm.Config["agent"], gc.Equals, "1")
+	c.Assert(vm.Config["ide2"], gc.Equals, "local:iso/juju-f75cba-0-seed.iso,media=cdrom")
+	// The disk is grown to the minimum size for the series.
+	c.Assert(vm.Config["scsi0"], gc.Equals, "local-lvm:vm-101-disk-0,size=8192M")
+
+	seed, ok := s.api.Uploads["local:iso/juju-f75cba-0-seed.iso"]
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(bytes.Contains(seed, []byte("instance-id: juju-f75cba-0\n")), jc.IsTrue)
+	c.Assert(bytes.Contains(seed, []byte("qemu-guest-agent")), jc.IsTrue)
+
+	cores, mem, rootDisk := uint64(1), uint64(512), uint64(8192)
+	amd64, zone := arch.AMD64, "pve1"
+	c.Assert(result.Hardware, jc.DeepEquals, &instance.HardwareCharacteristics{
+		Arch:             &amd64,
+		CpuCores:         &cores,
+		Mem:              &mem,
+		RootDisk:         &rootDisk,
+		AvailabilityZone: &zone,
+	})
+	s.statusCallbackStub.CheckCall(c, 0, "StatusCallback", status.Provisioning, "cloning juju-trusty", map[string]interface{}(nil))
+}
+
+func (s *environBrokerSuite) TestStartInstanceConstraints(c *gc.C) {
+	args := s.createStartInstanceArgs(c)
+	args.AvailabilityZone = "pve2"
+	args.Constraints = constraints.MustParse("cores=4 mem=4G root-disk=20G")
+
+	result, err := s.env.StartInstance(s.callCtx, args)
+	c.Assert(err, jc.ErrorIsNil)
+
+	vm := s.api.VMNamed("juju-f75cba-0")
+	c.Assert(vm, gc.NotNil)
+	c.Assert(vm.Node, gc.Equals, "pve2")
+	c.Assert(vm.Config["cores"], gc.Equals, "4")
+	c.Assert(vm.Config["memory"], gc.Equals, "4096")
+	c.Assert(vm.Config["scsi0"], gc.Equals, "local-lvm:vm-101-disk-0,size=20480M")
+	c.Assert(*result.Hardware.CpuCores, gc.Equals, uint64(4))
+	c.Assert(*result.Hardware.Mem, gc.Equals, uint64(4096))
+	c.Assert(*result.Hardware.RootDisk, gc.Equals, uint64(20480))
+	c.Assert(*result.Hardware.AvailabilityZone, gc.Equals, "pve2")
+}
+
+func (s *environBrokerSuite) TestStartInstanceCloneStorage(c *gc.C) {
+	err := s.env.SetConfig(fakeConfig(c, coretesting.Attrs{"clone-storage": "ceph"}))
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.env.StartInstance(s.callCtx, s.createStartInstanceArgs(c))
+	c.Assert(err, jc.ErrorIsNil)
+	vm := s.api.VMNamed("juju-f75cba-0")
+	c.Assert(vm, gc.NotNil)
+	c.Assert(vm.Config["scsi0"], gc.Equals, "ceph:vm-101-disk-0,size=8192M")
+}
+
+func (s *environBrokerSuite) TestStartInstanceNoTemplate(c *gc.C) {
+	args := s.createStartInstanceArgs(c)
+	args.InstanceConfig.Series = "bionic"
+
+	_, err := s.env.StartInstance(s.callCtx, args)
+	c.Assert(err, gc.ErrorMatches, `template VM "juju-bionic" not found`)
+	c.Assert(err, jc.Satisfies, common.IsZoneIndependentError)
+}
+
+func (s *environBrokerSuite) TestStartInstanceCleansUp(c *gc.C) {
+	s.api.Fail["POST /nodes/pve1/qemu/101/status/start"] = "start failed"
+
+	_, err := s.env.StartInstance(s.callCtx, s.createStartInstanceArgs(c))
+	c.Assert(err, gc.ErrorMatches, "starting VM 101: start failed")
+	c.Assert(s.api.VMNamed("juju-f75cba-0"), gc.IsNil)
+	c.Assert(s.api.Uploads, gc.HasLen, 0)
+}
+
+func (s *environBrokerSuite) TestStartInstanceVMIDTaken(c *gc.C) {
+	s.api.TakenIDs = 2
+
+	_, err := s.env.StartInstance(s.callCtx, s.createStartInstanceArgs(c))
+	c.Assert(err, jc.ErrorIsNil)
+	vm := s.api.VMNamed("juju-f75cba-0")
+	c.Assert(vm, gc.NotNil)
+	c.Assert(vm.VMID, gc.Equals, 103)
+	c.Assert(s.api.VMNamed("other-101"), gc.NotNil)
+	c.Assert(s.api.VMNamed("other-102"), gc.NotNil)
+}
+
+func (s *environBrokerSuite) TestStartInstanceVMIDsTaken(c *gc.C) {
+	s.api.TakenIDs = 5
+
+	_, err := s.env.StartInstance(s.callCtx, s.createStartInstanceArgs(c))
+	c.Assert(err, gc.ErrorMatches, `cloning template "juju-trusty": VM 105 already exists on node 'pve1'`)
+	c.Assert(s.api.VMNamed("juju-f75cba-0"), gc.IsNil)
+}
+
+func (s *environBrokerSuite) TestStartInstanceArch(c *gc.C) {
+	args := s.createStartInstanceArgs(c)
+	args.Constraints = constraints.MustParse("arch=arm64")
+
+	_, err := s.env.StartInstance(s.callCtx, args)
+	c.Assert(err, gc.ErrorMatches, `architecture "arm64" not supported`)
+	c.Assert(err, jc.Satisfies, common.IsZoneIndependentError)
+	c.Assert(s.api.VMs, gc.HasLen, 1)
+}
+
+func (s *environBrokerSuite) TestStartInstanceInvalidCredential(c *gc.C) {
+	spec := fakeCloudSpec(s.api.URL)
+	credential := cloud.NewCredential(cloud.UserPassAuthType, map[string]string{
+		"username": fakeUsername,
+		"password": "wrong",
+	})
+	spec.Credential = &credential
+	env, err := s.provider.Open(environs.OpenParams{
+		Cloud:  spec,
+		Config: fakeConfig(c),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	var called bool
+	ctx := &callcontext.CloudCallContext{
+		InvalidateCredentialFunc: func(string) error {
+			called = true
+			return nil
+		},
+	}
+	_, err = env.StartInstance(ctx, s.createStartInstanceArgs(c))
+	c.Assert(err, gc.ErrorMatches, `listing virtual machines: logging in as "root@pam": authentication failure`)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *environBrokerSuite) TestAllInstances(c *gc.C) {
+	s.api.AddVM("pve1", "juju-f75cba-0")
+	s.api.AddVM("pve2", "juju-f75cba-1")
+	s.api.AddVM("pve2", "juju-0f00d0-0")
+	s.api.AddVM("pve2", "other")
+
+	instances, err := s.env.AllInstances(s.callCtx)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instances, gc.HasLen, 2)
+	c.Assert(instances[0].Id(), gc.Equals, instance.Id("juju-f75cba-0"))
+	c.Assert(instances[1].Id(), gc.Equals, instance.Id("juju-f75cba-1"))
+	c.Assert(instances[0].Status(s.callCtx).Status, gc.Equals, status.Running)
+}
+
+func (s *environBrokerSuite) TestInstances(c *gc.C) {
+	s.api.AddVM("pve1", "juju-f75cba-0")
+
+	instances, err := s.env.Instances(s.callCtx, []instance.Id{"juju-f75cba-0", "juju-f75cba-1"})
+	c.Assert(err, gc.Equals, environs.ErrPartialInstances)
+	c.Assert(instances, gc.HasLen, 2)
+	c.Assert(instances[0].Id(), gc.Equals, instance.Id("juju-f75cba-0"))
+	c.Assert(instances[1], gc.IsNil)
+
+	_, err = s.env.Instances(s.callCtx, []instance.Id{"juju-f75cba-1"})
+	c.Assert(err, gc.Equals, environs.ErrNoInstances)
+}
+
+func (s *environBrokerSuite) TestInstanceAddresses(c *gc.C) {
+	vm := s.api.AddVM("pve1", "juju-f75cba-0")
+
+	instances, err := s.env.Instances(s.callCtx, []instance.Id{"juju-f75cba-0"})
+	c.Assert(err, jc.ErrorIsNil)
+	// The guest agent isn't running yet.
+	addresses, err := instances[0].Addresses(s.callCtx)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addresses, gc.HasLen, 0)
+
+	vm.Addresses = []string{"10.0.0.5", "fe80::1", "2001:db8::5"}
+	addresses, err = instances[0].Addresses(s.callCtx)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addresses, jc.DeepEquals, []network.Address{
+		network.NewAddress("10.0.0.5"),
+		network.NewAddress("2001:db8::5"),
+	})
+}
+
+func (s *environBrokerSuite) TestStopInstances(c *gc.C) {
+	s.api.AddVM("pve1", "juju-f75cba-0")
+	s.api.AddVM("pve2", "juju-f75cba-1")
+	s.api.Uploads["local:iso/juju-f75cba-0-seed.iso"] = []byte("seed")
+	s.api.Volumes["local:iso/juju-f75cba-0-seed.iso"] = &fakeVolume{
+		Node:  "pve1",
+		VolID: "local:iso/juju-f75cba-0-seed.iso",
+	}
+
+	err := s.env.StopInstances(s.callCtx, "juju-f75cba-0", "juju-f75cba-2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.VMNamed("juju-f75cba-0"), gc.IsNil)
+	c.Assert(s.api.VMNamed("juju-f75cba-1"), gc.NotNil)
+	c.Assert(s.api.Uploads, gc.HasLen, 0)
+}
+
+func (s *environBrokerSuite) TestStopInstancesFails(c *gc.C) {
+	s.api.AddVM("pve1", "juju-f75cba-0")
+	s.api.Fail["DELETE /nodes/pve1/qemu/101"] = "locked"
+
+	err := s.env.StopInstances(s.callCtx, "juju-f75cba-0")
+	c.Assert(err, gc.ErrorMatches, "failed to stop instances juju-f75cba-0")
+}
+
+func (s *environBrokerSuite) TestDiskSize(c *gc.C) {
+	for _, test := range []struct {
+		disk string
+		size uint64
+		err  string
+	}{
+		{disk: "local-lvm:vm-100-disk-0,size=10G", size: 10240},
+		{disk: "local-lvm:vm-100-disk-0,cache=none,size=512M", size: 512},
+		{disk: "local-lvm:vm-100-disk-0,size=2T", size: 2 * 1024 * 1024},
+		{disk: "local-lvm:vm-100-disk-0,size=1073741824", size: 1024},
+		{disk: "local-lvm:vm-100-disk-0,size=xG", err: `disk size "size=xG" not valid`},
+		{disk: "local-lvm:vm-100-disk-0", err: `size of disk "local-lvm:vm-100-disk-0" not found`},
+	} {
+		c.Logf("disk %q", test.disk)
+		size, err := proxmox.DiskSize(test.disk)
+		if test.err != "" {
+			c.Check(err, gc.ErrorMatches, test.err)
+			continue
+		}
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(size, gc.Equals, test.size)
+	}
+}
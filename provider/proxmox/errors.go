@@ -0,0 +1,47 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package proxmox
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/provider/common"
+)
+
+// IsAuthorisationFailure determines whether the given error indicates
+// that the Proxmox VE credential used is bad.
+func IsAuthorisationFailure(err error) bool {
+	apiErr, ok := errors.Cause(err).(*apiError)
+	return ok && apiErr.StatusCode == http.StatusUnauthorized
+}
+
+// isNotFound determines whether the given error indicates that the
+// thing requested doesn't exist. The API reports this in the message
+// of a server error, rather than with a 404, for most things.
+func isNotFound(err error) bool {
+	apiErr, ok := errors.Cause(err).(*apiError)
+	if !ok {
+		return false
+	}
+	return apiErr.StatusCode == http.StatusNotFound ||
+		strings.Contains(apiErr.Message, "does not exist") ||
+		strings.Contains(apiErr.Message, "no such")
+}
+
+// isVMIDConflict determines whether the given error indicates that a
+// VM could not be created because its ID was already in use.
+func isVMIDConflict(err error) bool {
+	apiErr, ok := errors.Cause(err).(*apiError)
+	return ok && strings.Contains(apiErr.Message, "already exists")
+}
+
+// handleCredentialError marks the current credential as invalid if
+// the given error indicates it should be.
+func handleCredentialError(err error, ctx context.ProviderCallContext) {
+	common.HandleCredentialError(IsAuthorisationFailure, err, ctx)
+}
@@ -0,0 +1,11 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package proxmox
+
+var (
+	Provider         = providerInstance
+	TaskPollInterval = &taskPollInterval
+	NewSeedImage     = newSeedImage
+	DiskSize         = diskSize
+)
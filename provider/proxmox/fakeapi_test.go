@@ -0,0 +1,556 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package proxmox_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	fakeUsername = "root@pam"
+	fakePassword = "secret"
+	fakeTicket   = "PVE:root@pam:ticket"
+	fakeCSRF     = "csrf-token"
+)
+
+// fakeVM is a VM, or template, known to the fake API.
+type fakeVM struct {
+	VMID     int
+	Name     string
+	Node     string
+	Status   string
+	Template bool
+	Config   map[string]interface{}
+
+	// Addresses are the addresses the guest agent reports, or nil
+	// if the guest agent isn't running.
+	Addresses []string
+}
+
+// fakeVolume is a volume in the storage of a node.
+type fakeVolume struct {
+	Node  string
+	VolID string
+	VMID  int
+	Size  uint64
+}
+
+// fakeAPI is an in-memory stand-in for the Proxmox VE API, served
+// over HTTP, which implements just enough to exercise the provider.
+type fakeAPI struct {
+	*httptest.Server
+
+	mu sync.Mutex
+
+	// Nodes maps the names of the nodes in the cluster to their status.
+	Nodes map[string]string
+
+	// VMs holds the cluster's VMs and templates, keyed by ID.
+	VMs map[int]*fakeVM
+
+	// Volumes holds the volumes in the nodes' storage, keyed by ID.
+	Volumes map[string]*fakeVolume
+
+	// Uploads holds the content of uploaded files, keyed by volume ID.
+	Uploads map[string][]byte
+
+	// Requests records the method and path of every request made,
+	// other than logins and task status checks.
+	Requests []string
+
+	// Logins counts the times a client logs in.
+	Logins int
+
+	// Fail maps "METHOD path" to a message with which to fail
+	// matching requests.
+	Fail map[string]string
+
+	// TakenIDs is how many of the IDs next handed out are taken by
+	// another client before they can be used.
+	TakenIDs int
+
+	nextID int
+}
+
+func newFakeAPI() *fakeAPI {
+	api := &fakeAPI{
+		Nodes:   map[string]string{"pve1": "online", "pve2": "online"},
+		VMs:     make(map[int]*fakeVM),
+		Volumes: make(map[string]*fakeVolume),
+		Uploads: make(map[string][]byte),
+		Fail:    make(map[string]string),
+		nextID:  100,
+	}
+	api.Server = httptest.NewServer(http.HandlerFunc(api.serveHTTP))
+	return api
+}
+
+// AddTemplate adds a template VM to the cluster.
+func (api *fakeAPI) AddTemplate(node, name string) *fakeVM {
+	vm := api.AddVM(node, name)
+	vm.Template = true
+	vm.Status = "stopped"
+	return vm
+}
+
+// AddVM adds a running VM to the cluster.
+func (api *fakeAPI) AddVM(node, name string) *fakeVM {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	vm := &fakeVM{
+		VMID:   api.nextID,
+		Name:   name,
+		Node:   node,
+		Status: "running",
+		Config: map[string]interface{}{
+			"name":     name,
+			"cores":    float64(1),
+			"memory":   float64(512),
+			"bootdisk": "scsi0",
+			"scsi0":    fmt.Sprintf("local-lvm:vm-%d-disk-0,size=2G", api.nextID),
+		},
+	}
+	api.VMs[vm.VMID] = vm
+	api.nextID++
+	return vm
+}
+
+// VMNamed returns the VM with the given name, or nil.
+func (api *fakeAPI) VMNamed(name string) *fakeVM {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	for _, vm := range api.VMs {
+		if vm.Name == name {
+			return vm
+		}
+	}
+	return nil
+}
+
+// ResetRequests forgets the requests made so far.
+func (api *fakeAPI) ResetRequests() {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	api.Requests = nil
+}
+
+var (
+	nodePattern    = `/nodes/([^/]+)`
+	vmPattern      = nodePattern + `/qemu/(\d+)`
+	storagePattern = nodePattern + `/storage/([^/]+)`
+)
+
+type fakeRoute struct {
+	method  string
+	pattern *regexp.Regexp
+	handle  func(api *fakeAPI, req *http.Request, args []string) (interface{}, error)
+}
+
+func route(method, pattern string, handle func(*fakeAPI, *http.Request, []string) (interface{}, error)) fakeRoute {
+	return fakeRoute{method, regexp.MustCompile("^" + pattern + "$"), handle}
+}
+
+var fakeRoutes = []fakeRoute{
+	route("GET", "/version", func(*fakeAPI, *http.Request, []string) (interface{}, error) {
+		return map[string]string{"version": "5.4"}, nil
+	}),
+	route("GET", "/nodes", (*fakeAPI).listNodes),
+	route("GET", "/cluster/resources", (*fakeAPI).listResources),
+	route("GET", "/cluster/nextid", (*fakeAPI).nextVMID),
+	route("GET", nodePattern+`/tasks/([^/]+)/status`, func(*fakeAPI, *http.Request, []string) (interface{}, error) {
+		return map[string]string{"status": "stopped", "exitstatus": "OK"}, nil
+	}),
+	route("POST", vmPattern+"/clone", (*fakeAPI).cloneVM),
+	route("GET", vmPattern+"/config", (*fakeAPI).getConfig),
+	route("PUT", vmPattern+"/config", (*fakeAPI).setConfig),
+	route("PUT", vmPattern+"/resize", (*fakeAPI).resizeDisk),
+	route("POST", vmPattern+"/status/(start|stop)", (*fakeAPI).setStatus),
+	route("DELETE", vmPattern, (*fakeAPI).deleteVM),
+	route("GET", vmPattern+"/agent/network-get-interfaces", (*fakeAPI).interfaces),
+	route("POST", storagePattern+"/upload", (*fakeAPI).upload),
+	route("POST", storagePattern+"/content", (*fakeAPI).createVolume),
+	route("GET", storagePattern+"/content", (*fakeAPI).listVolumes),
+	route("GET", storagePattern+"/content/([^/]+)", (*fakeAPI).getVolume),
+	route("DELETE", storagePattern+"/content/([^/]+)", (*fakeAPI).deleteVolume),
+}
+
+// apiError makes the API respond with the given status and message.
+type apiError struct {
+	code    int
+	message string
+}
+
+func (e *apiError) Error() string {
+	return e.message
+}
+
+func (api *fakeAPI) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	path := strings.TrimPrefix(req.URL.EscapedPath(), "/api2/json")
+	if req.Method == "POST" && path == "/access/ticket" {
+		api.login(w, req)
+		return
+	}
+	if cookie, err := req.Cookie("PVEAuthCookie"); err != nil || cookie.Value != fakeTicket {
+		writeError(w, http.StatusUnauthorized, "authentication failure")
+		return
+	}
+	if req.Method != "GET" && req.Header.Get("CSRFPreventionToken") != fakeCSRF {
+		writeError(w, http.StatusUnauthorized, "Permission check failed")
+		return
+	}
+
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	request := req.Method + " " + path
+	if !strings.Contains(path, "/tasks/") {
+		api.Requests = append(api.Requests, request)
+	}
+	if message, ok := api.Fail[request]; ok {
+		writeError(w, http.StatusInternalServerError, message)
+		return
+	}
+	for _, r := range fakeRoutes {
+		if r.method != req.Method {
+			continue
+		}
+		m := r.pattern.FindStringSubmatch(path)
+		if m == nil {
+			continue
+		}
+		for i := range m {
+			m[i], _ = url.PathUnescape(m[i])
+		}
+		data, err := r.handle(api, req, m[1:])
+		if err, ok := err.(*apiError); ok {
+			writeError(w, err.code, err.message)
+			return
+		} else if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+		return
+	}
+	writeError(w, http.StatusNotImplemented, fmt.Sprintf("Method '%s' not implemented", request))
+}
+
+func (api *fakeAPI) login(w http.ResponseWriter, req *http.Request) {
+	api.mu.Lock()
+	api.Logins++
+	api.mu.Unlock()
+	if req.FormValue("username") != fakeUsername || req.FormValue("password") != fakePassword {
+		writeError(w, http.StatusUnauthorized, "authentication failure")
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data": map[string]string{
+			"ticket":              fakeTicket,
+			"CSRFPreventionToken": fakeCSRF,
+		},
+	})
+}
+
+// writeError writes a response with the given status, and the message
+// as its reason phrase, as the API does. The standard library always
+// uses the standard reason phrase, so the response is written directly.
+func writeError(w http.ResponseWriter, code int, message string) {
+	conn, buf, err := w.(http.Hijacker).Hijack()
+	if err != nil {
+		http.Error(w, message, code)
+		return
+	}
+	defer conn.Close()
+	body := `{"data":null}`
+	fmt.Fprintf(buf, "HTTP/1.1 %d %s\r\n", code, message)
+	fmt.Fprintf(buf, "Content-Type: application/json\r\nContent-Length: %d\r\nConnection: close\r\n\r\n%s", len(body), body)
+	buf.Flush()
+}
+
+func (api *fakeAPI) listNodes(*http.Request, []string) (interface{}, error) {
+	var nodes []map[string]string
+	for name, status := range api.Nodes {
+		nodes = append(nodes, map[string]string{"node": name, "status": status})
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i]["node"] < nodes[j]["node"]
+	})
+	return nodes, nil
+}
+
+func (api *fakeAPI) listResources(req *http.Request, _ []string) (interface{}, error) {
+	if req.FormValue("type") != "vm" {
+		return nil, &apiError{http.StatusBadRequest, "unexpected resource type"}
+	}
+	ids := make([]int, 0, len(api.VMs))
+	for id := range api.VMs {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	resources := []map[string]interface{}{{
+		// Containers are listed too.
+		"type": "lxc", "vmid": 99, "name": "container", "node": "pve1",
+	}}
+	for _, id := range ids {
+		vm := api.VMs[id]
+		template := 0
+		if vm.Template {
+			template = 1
+		}
+		resources = append(resources, map[string]interface{}{
+			"type":     "qemu",
+			"vmid":     vm.VMID,
+			"name":     vm.Name,
+			"node":     vm.Node,
+			"status":   vm.Status,
+			"template": template,
+		})
+	}
+	return resources, nil
+}
+
+func (api *fakeAPI) nextVMID(*http.Request, []string) (interface{}, error) {
+	vmid := api.nextID
+	if api.TakenIDs > 0 {
+		api.TakenIDs--
+		api.VMs[vmid] = &fakeVM{
+			VMID:   vmid,
+			Name:   fmt.Sprintf("other-%d", vmid),
+			Node:   "pve1",
+			Status: "stopped",
+			Config: make(map[string]interface{}),
+		}
+		api.nextID++
+	}
+	return strconv.Itoa(vmid), nil
+}
+
+func (api *fakeAPI) vm(args []string) (*fakeVM, error) {
+	vmid, _ := strconv.Atoi(args[1])
+	vm, ok := api.VMs[vmid]
+	if !ok || vm.Node != args[0] {
+		return nil, fmt.Errorf("Configuration file 'nodes/%s/qemu-server/%d.conf' does not exist", args[0], vmid)
+	}
+	return vm, nil
+}
+
+func (api *fakeAPI) cloneVM(req *http.Request, args []string) (interface{}, error) {
+	template, err := api.vm(args)
+	if err != nil {
+		return nil, err
+	}
+	newID, _ := strconv.Atoi(req.FormValue("newid"))
+	if newID == 0 {
+		return nil, &apiError{http.StatusBadRequest, "Parameter verification failed."}
+	}
+	if vm, ok := api.VMs[newID]; ok {
+		return nil, fmt.Errorf("VM %d already exists on node '%s'", newID, vm.Node)
+	}
+	vm := &fakeVM{
+		VMID:   newID,
+		Name:   req.FormValue("name"),
+		Node:   req.FormValue("target"),
+		Status: "stopped",
+		Config: make(map[string]interface{}),
+	}
+	for k, v := range template.Config {
+		vm.Config[k] = v
+	}
+	vm.Config["name"] = vm.Name
+	storage := "local-lvm"
+	if req.FormValue("full") == "1" {
+		storage = req.FormValue("storage")
+	}
+	vm.Config["scsi0"] = fmt.Sprintf("%s:vm-%d-disk-0,size=2G", storage, newID)
+	api.VMs[newID] = vm
+	if newID >= api.nextID {
+		api.nextID = newID + 1
+	}
+	return "UPID:clone", nil
+}
+
+func (api *fakeAPI) getConfig(req *http.Request, args []string) (interface{}, error) {
+	vm, err := api.vm(args)
+	if err != nil {
+		return nil, err
+	}
+	return vm.Config, nil
+}
+
+func (api *fakeAPI) setConfig(req *http.Request, args []string) (interface{}, error) {
+	vm, err := api.vm(args)
+	if err != nil {
+		return nil, err
+	}
+	req.ParseForm()
+	for k, v := range req.PostForm {
+		if k == "delete" {
+			for _, option := range strings.Split(v[0], ",") {
+				delete(vm.Config, option)
+			}
+			continue
+		}
+		vm.Config[k] = v[0]
+	}
+	return nil, nil
+}
+
+func (api *fakeAPI) resizeDisk(req *http.Request, args []string) (interface{}, error) {
+	vm, err := api.vm(args)
+	if err != nil {
+		return nil, err
+	}
+	disk := req.FormValue("disk")
+	value, _ := vm.Config[disk].(string)
+	if value == "" {
+		return nil, &apiError{http.StatusBadRequest, "disk does not exist"}
+	}
+	vm.Config[disk] = strings.SplitN(value, ",", 2)[0] + ",size=" + req.FormValue("size")
+	return nil, nil
+}
+
+func (api *fakeAPI) setStatus(req *http.Request, args []string) (interface{}, error) {
+	vm, err := api.vm(args)
+	if err != nil {
+		return nil, err
+	}
+	if args[2] == "start" {
+		vm.Status = "running"
+	} else {
+		vm.Status = "stopped"
+	}
+	return "UPID:" + args[2], nil
+}
+
+func (api *fakeAPI) deleteVM(req *http.Request, args []string) (interface{}, error) {
+	vm, err := api.vm(args)
+	if err != nil {
+		return nil, err
+	}
+	if vm.Status == "running" {
+		return nil, fmt.Errorf("VM %d is running - destroy failed", vm.VMID)
+	}
+	delete(api.VMs, vm.VMID)
+	for volid, volume := range api.Volumes {
+		if volume.VMID == vm.VMID {
+			delete(api.Volumes, volid)
+		}
+	}
+	return "UPID:delete", nil
+}
+
+func (api *fakeAPI) interfaces(req *http.Request, args []string) (interface{}, error) {
+	vm, err := api.vm(args)
+	if err != nil {
+		return nil, err
+	}
+	if vm.Addresses == nil {
+		return nil, fmt.Errorf("QEMU guest agent is not running")
+	}
+	lo := map[string]interface{}{
+		"name":         "lo",
+		"ip-addresses": []map[string]string{{"ip-address": "127.0.0.1"}},
+	}
+	var addresses []map[string]string
+	for _, addr := range vm.Addresses {
+		addresses = append(addresses, map[string]string{"ip-address": addr})
+	}
+	eth0 := map[string]interface{}{
+		"name":         "eth0",
+		"ip-addresses": addresses,
+	}
+	return map[string]interface{}{"result": []interface{}{lo, eth0}}, nil
+}
+
+func (api *fakeAPI) upload(req *http.Request, args []string) (interface{}, error) {
+	if err := req.ParseMultipartForm(1 << 20); err != nil {
+		return nil, err
+	}
+	if req.FormValue("content") != "iso" {
+		return nil, &apiError{http.StatusBadRequest, "unexpected content type"}
+	}
+	f, header, err := req.FormFile("filename")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	content, err := ioutil.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	volid := fmt.Sprintf("%s:iso/%s", args[1], header.Filename)
+	api.Uploads[volid] = content
+	api.Volumes[volid] = &fakeVolume{Node: args[0], VolID: volid, Size: uint64(len(content))}
+	return "UPID:upload", nil
+}
+
+func (api *fakeAPI) createVolume(req *http.Request, args []string) (interface{}, error) {
+	vmid, _ := strconv.Atoi(req.FormValue("vmid"))
+	size := strings.TrimSuffix(req.FormValue("size"), "M")
+	sizeMiB, err := strconv.ParseUint(size, 10, 64)
+	if err != nil {
+		return nil, &apiError{http.StatusBadRequest, "Parameter verification failed."}
+	}
+	volid := fmt.Sprintf("%s:%s", args[1], req.FormValue("filename"))
+	api.Volumes[volid] = &fakeVolume{
+		Node:  args[0],
+		VolID: volid,
+		VMID:  vmid,
+		Size:  sizeMiB * 1024 * 1024,
+	}
+	return volid, nil
+}
+
+func (api *fakeAPI) listVolumes(req *http.Request, args []string) (interface{}, error) {
+	var volumes []map[string]interface{}
+	for _, volume := range api.Volumes {
+		if volume.Node != args[0] || volume.VMID == 0 || !strings.HasPrefix(volume.VolID, args[1]+":") {
+			continue
+		}
+		volumes = append(volumes, map[string]interface{}{
+			"volid": volume.VolID,
+			"vmid":  volume.VMID,
+			"size":  volume.Size,
+		})
+	}
+	sort.Slice(volumes, func(i, j int) bool {
+		return volumes[i]["volid"].(string) < volumes[j]["volid"].(string)
+	})
+	return volumes, nil
+}
+
+func (api *fakeAPI) volume(args []string) (*fakeVolume, error) {
+	volume, ok := api.Volumes[args[2]]
+	if !ok || volume.Node != args[0] {
+		return nil, fmt.Errorf("no such volume '%s'", args[2])
+	}
+	return volume, nil
+}
+
+func (api *fakeAPI) getVolume(req *http.Request, args []string) (interface{}, error) {
+	volume, err := api.volume(args)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"size": volume.Size}, nil
+}
+
+func (api *fakeAPI) deleteVolume(req *http.Request, args []string) (interface{}, error) {
+	volume, err := api.volume(args)
+	if err != nil {
+		return nil, err
+	}
+	delete(api.Volumes, volume.VolID)
+	delete(api.Uploads, volume.VolID)
+	return nil, nil
+}
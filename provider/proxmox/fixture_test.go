@@ -0,0 +1,88 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package proxmox_test
+
+import (
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cloud"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/provider/proxmox"
+	coretesting "github.com/juju/juju/testing"
+)
+
+func fakeConfig(c *gc.C, attrs ...coretesting.Attrs) *config.Config {
+	cfg, err := coretesting.ModelConfig(c).Apply(fakeConfigAttrs(attrs...))
+	c.Assert(err, jc.ErrorIsNil)
+	return cfg
+}
+
+func fakeConfigAttrs(attrs ...coretesting.Attrs) coretesting.Attrs {
+	merged := coretesting.FakeConfig().Merge(coretesting.Attrs{
+		"type": "proxmox",
+		"uuid": "2d02eeac-9dbb-11e4-89d3-123b93f75cba",
+	})
+	for _, attrs := range attrs {
+		merged = merged.Merge(attrs)
+	}
+	return merged
+}
+
+func fakeCloudSpec(endpoint string) environs.CloudSpec {
+	cred := fakeCredential()
+	return environs.CloudSpec{
+		Type:       "proxmox",
+		Name:       "proxmox",
+		Endpoint:   endpoint,
+		Credential: &cred,
+	}
+}
+
+func fakeCredential() cloud.Credential {
+	return cloud.NewCredential(cloud.UserPassAuthType, map[string]string{
+		"username": fakeUsername,
+		"password": fakePassword,
+	})
+}
+
+type ProviderFixture struct {
+	testing.IsolationSuite
+	provider environs.CloudEnvironProvider
+	callCtx  context.ProviderCallContext
+}
+
+func (s *ProviderFixture) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.provider = proxmox.Provider
+	s.callCtx = context.NewCloudCallContext()
+}
+
+type EnvironFixture struct {
+	ProviderFixture
+	api *fakeAPI
+	env environs.Environ
+}
+
+func (s *EnvironFixture) SetUpTest(c *gc.C) {
+	s.ProviderFixture.SetUpTest(c)
+	s.PatchValue(proxmox.TaskPollInterval, time.Duration(0))
+
+	s.api = newFakeAPI()
+	s.AddCleanup(func(*gc.C) {
+		s.api.Close()
+	})
+
+	env, err := s.provider.Open(environs.OpenParams{
+		Cloud:  fakeCloudSpec(s.api.URL),
+		Config: fakeConfig(c),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.env = env
+}
@@ -0,0 +1,16 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package proxmox
+
+import (
+	"github.com/juju/juju/environs"
+)
+
+const (
+	providerType = "proxmox"
+)
+
+func init() {
+	environs.RegisterProvider(providerType, providerInstance)
+}
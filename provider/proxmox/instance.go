@@ -0,0 +1,105 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package proxmox
+
+import (
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
+)
+
+type environInstance struct {
+	vm  virtualMachine
+	env *environ
+}
+
+var _ instance.Instance = (*environInstance)(nil)
+
+func newInstance(vm virtualMachine, env *environ) *environInstance {
+	return &environInstance{
+		vm:  vm,
+		env: env,
+	}
+}
+
+// Id implements instance.Instance.
+func (inst *environInstance) Id() instance.Id {
+	return instance.Id(inst.vm.Name)
+}
+
+// Status implements instance.Instance.
+func (inst *environInstance) Status(ctx context.ProviderCallContext) instance.InstanceStatus {
+	instanceStatus := instance.InstanceStatus{
+		Status:  status.Empty,
+		Message: inst.vm.Status,
+	}
+	switch inst.vm.Status {
+	case "running":
+		instanceStatus.Status = status.Running
+	}
+	return instanceStatus
+}
+
+// Addresses implements instance.Instance. The addresses are reported by
+// the QEMU guest agent, so there are none until it has started.
+func (inst *environInstance) Addresses(ctx context.ProviderCallContext) ([]network.Address, error) {
+	if inst.vm.Status != "running" {
+		return nil, nil
+	}
+	interfaces, err := inst.env.client.vmInterfaces(inst.vm.Node, inst.vm.VMID)
+	if err != nil {
+		apiErr, ok := errors.Cause(err).(*apiError)
+		if ok && apiErr.StatusCode == http.StatusInternalServerError {
+			// The guest agent isn't running yet.
+			logger.Debugf("no addresses for %q: %v", inst.vm.Name, err)
+			return nil, nil
+		}
+		handleCredentialError(err, ctx)
+		return nil, errors.Trace(err)
+	}
+	var addresses []network.Address
+	for _, iface := range interfaces {
+		if iface.Name == "lo" {
+			continue
+		}
+		for _, addr := range iface.IPAddresses {
+			ip := net.ParseIP(addr.IPAddress)
+			if ip == nil || ip.IsLoopback() || ip.IsLinkLocalUnicast() {
+				continue
+			}
+			addresses = append(addresses, network.NewAddress(ip.String()))
+		}
+	}
+	return addresses, nil
+}
+
+// vmDescription returns the description of a VM that records the
+// given tags.
+func vmDescription(tags map[string]string) string {
+	lines := make([]string, 0, len(tags))
+	for k, v := range tags {
+		lines = append(lines, k+"="+v)
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
+
+// vmTags returns the tags recorded in the description of a VM.
+func vmTags(cfg vmConfig) map[string]string {
+	tags := make(map[string]string)
+	for _, line := range strings.Split(cfg.get("description"), "\n") {
+		if kv := strings.SplitN(strings.TrimSpace(line), "=", 2); len(kv) == 2 {
+			tags[kv[0]] = kv[1]
+		}
+	}
+	return tags
+}
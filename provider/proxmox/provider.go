@@ -0,0 +1,178 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package proxmox implements a Juju provider for Proxmox VE clusters.
+//
+// Machines are linked or full clones of template VMs, one per series,
+// which must have cloud-init installed. Each machine is given its
+// cloud-init user data on an ISO image attached as a CD-ROM drive, and
+// its addresses are discovered through the QEMU guest agent. The nodes
+// of the cluster are the availability zones.
+package proxmox
+
+import (
+	"net/url"
+
+	"github.com/juju/errors"
+	"github.com/juju/jsonschema"
+	"github.com/juju/loggo"
+	"github.com/juju/schema"
+
+	"github.com/juju/juju/cloud"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/context"
+)
+
+var logger = loggo.GetLogger("juju.provider.proxmox")
+
+type environProvider struct {
+	environProviderCredentials
+}
+
+var providerInstance = &environProvider{}
+
+var _ environs.CloudEnvironProvider = (*environProvider)(nil)
+
+// Version implements environs.EnvironProvider.
+func (p *environProvider) Version() int {
+	return 0
+}
+
+// Open implements environs.EnvironProvider.
+func (p *environProvider) Open(args environs.OpenParams) (environs.Environ, error) {
+	if err := validateCloudSpec(args.Cloud); err != nil {
+		return nil, errors.Annotate(err, "validating cloud spec")
+	}
+	env, err := newEnviron(p, args.Cloud, args.Config)
+	return env, errors.Trace(err)
+}
+
+var cloudSchema = &jsonschema.Schema{
+	Type:     []jsonschema.Type{jsonschema.ObjectType},
+	Required: []string{cloud.EndpointKey, cloud.AuthTypesKey},
+	Order:    []string{cloud.EndpointKey, cloud.AuthTypesKey},
+	Properties: map[string]*jsonschema.Schema{
+		cloud.EndpointKey: {
+			Singular: "the address or URL of a Proxmox VE node",
+			Type:     []jsonschema.Type{jsonschema.StringType},
+			Format:   jsonschema.FormatURI,
+		},
+		cloud.AuthTypesKey: {
+			// don't need a prompt, since there's only one choice.
+			Type: []jsonschema.Type{jsonschema.ArrayType},
+			Enum: []interface{}{[]string{string(cloud.UserPassAuthType)}},
+		},
+	},
+}
+
+// CloudSchema returns the schema for adding new clouds of this type.
+func (p *environProvider) CloudSchema() *jsonschema.Schema {
+	return cloudSchema
+}
+
+// Ping tests the connection to the cloud, to verify the endpoint is valid.
+func (p *environProvider) Ping(ctx context.ProviderCallContext, endpoint string) error {
+	u, err := endpointURL(endpoint)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	// The version API requires authentication, but the response
+	// to an unauthenticated request still shows whether Proxmox VE
+	// is listening at the endpoint.
+	c := &client{
+		endpoint:   u.String(),
+		httpClient: newHTTPClient(nil, true),
+	}
+	err = c.do("GET", "/version", nil, nil)
+	if err == nil || IsAuthorisationFailure(err) {
+		return nil
+	}
+	logger.Debugf("pinging %s: %v", u, err)
+	return errors.Errorf("no Proxmox VE API available at %s", endpoint)
+}
+
+// PrepareConfig implements environs.EnvironProvider.
+func (p *environProvider) PrepareConfig(args environs.PrepareConfigParams) (*config.Config, error) {
+	if err := validateCloudSpec(args.Cloud); err != nil {
+		return nil, errors.Annotate(err, "validating cloud spec")
+	}
+	return args.Config, nil
+}
+
+// Validate implements environs.EnvironProvider.
+func (*environProvider) Validate(cfg, old *config.Config) (valid *config.Config, err error) {
+	if old == nil {
+		ecfg, err := newValidConfig(cfg)
+		if err != nil {
+			return nil, errors.Annotate(err, "invalid config")
+		}
+		return ecfg.Config, nil
+	}
+
+	ecfg, err := newValidConfig(old)
+	if err != nil {
+		return nil, errors.Annotate(err, "invalid base config")
+	}
+
+	if err := ecfg.update(cfg); err != nil {
+		return nil, errors.Annotate(err, "invalid config change")
+	}
+
+	return ecfg.Config, nil
+}
+
+// ConfigSchema returns extra config attributes specific
+// to this provider only.
+func (p *environProvider) ConfigSchema() schema.Fields {
+	return configFields
+}
+
+// ConfigDefaults returns the default values for the
+// provider specific config attributes.
+func (p *environProvider) ConfigDefaults() schema.Defaults {
+	return configDefaults
+}
+
+func validateCloudSpec(spec environs.CloudSpec) error {
+	if err := spec.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := endpointURL(spec.Endpoint); err != nil {
+		return errors.Trace(err)
+	}
+	if spec.Credential == nil {
+		return errors.NotValidf("missing credential")
+	}
+	if authType := spec.Credential.AuthType(); authType != cloud.UserPassAuthType {
+		return errors.NotSupportedf("%q auth-type", authType)
+	}
+	return nil
+}
+
+// endpointURL returns the URL of the Proxmox VE API server at the
+// given endpoint. If the endpoint is just an address, the API is
+// expected to be served over HTTPS on the default port.
+func endpointURL(endpoint string) (*url.URL, error) {
+	if endpoint == "" {
+		return nil, errors.NotValidf("empty endpoint")
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		// The endpoint has no scheme, so may not parse as a URL.
+		u, err = url.Parse("https://" + endpoint)
+		if err != nil {
+			return nil, errors.NotValidf("endpoint %q", endpoint)
+		}
+		if u.Port() == "" {
+			u.Host += ":" + defaultAPIPort
+		}
+	}
+	switch u.Scheme {
+	case "http", "https":
+	default:
+		return nil, errors.NotValidf("endpoint %q", endpoint)
+	}
+	u.Path = ""
+	return u, nil
+}
@@ -0,0 +1,115 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package proxmox_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cloud"
+	"github.com/juju/juju/environs"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type providerSuite struct {
+	ProviderFixture
+}
+
+var _ = gc.Suite(&providerSuite{})
+
+func (s *providerSuite) TestRegistered(c *gc.C) {
+	provider, err := environs.Provider("proxmox")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(provider, gc.Equals, s.provider)
+}
+
+func (s *providerSuite) TestOpen(c *gc.C) {
+	env, err := s.provider.Open(environs.OpenParams{
+		Cloud:  fakeCloudSpec("pve1.example.com"),
+		Config: fakeConfig(c),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(env.Config().Name(), gc.Equals, "testmodel")
+}
+
+func (s *providerSuite) TestOpenInvalidEndpoint(c *gc.C) {
+	s.testOpenError(c, fakeCloudSpec("ftp://pve1.example.com"), `validating cloud spec: endpoint "ftp://pve1.example.com" not valid`)
+}
+
+func (s *providerSuite) TestOpenMissingCredential(c *gc.C) {
+	spec := fakeCloudSpec("pve1.example.com")
+	spec.Credential = nil
+	s.testOpenError(c, spec, `validating cloud spec: missing credential not valid`)
+}
+
+func (s *providerSuite) TestOpenUnsupportedCredential(c *gc.C) {
+	credential := cloud.NewCredential(cloud.OAuth1AuthType, map[string]string{})
+	spec := fakeCloudSpec("pve1.example.com")
+	spec.Credential = &credential
+	s.testOpenError(c, spec, `validating cloud spec: "oauth1" auth-type not supported`)
+}
+
+func (s *providerSuite) testOpenError(c *gc.C, spec environs.CloudSpec, expect string) {
+	_, err := s.provider.Open(environs.OpenParams{
+		Cloud:  spec,
+		Config: fakeConfig(c),
+	})
+	c.Assert(err, gc.ErrorMatches, expect)
+}
+
+func (s *providerSuite) TestCredentialSchemas(c *gc.C) {
+	schemas := s.provider.CredentialSchemas()
+	c.Assert(schemas, gc.HasLen, 1)
+	schema, ok := schemas[cloud.UserPassAuthType]
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(schema, gc.HasLen, 2)
+	c.Assert(schema[0].Name, gc.Equals, "username")
+	c.Assert(schema[1].Name, gc.Equals, "password")
+	c.Assert(schema[1].Hidden, jc.IsTrue)
+}
+
+func (s *providerSuite) TestPing(c *gc.C) {
+	api := newFakeAPI()
+	defer api.Close()
+	// The request isn't authenticated, but that's enough
+	// to know there's an API there.
+	err := s.provider.Ping(s.callCtx, api.URL)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *providerSuite) TestPingNoAPI(c *gc.C) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+	err := s.provider.Ping(s.callCtx, server.URL)
+	c.Assert(err, gc.ErrorMatches, "no Proxmox VE API available at "+server.URL)
+}
+
+func (s *providerSuite) TestValidate(c *gc.C) {
+	cfg, err := s.provider.Validate(fakeConfig(c), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	attrs := cfg.UnknownAttrs()
+	c.Assert(attrs["template-prefix"], gc.Equals, "juju-")
+	c.Assert(attrs["seed-storage"], gc.Equals, "local")
+	_, ok := attrs["clone-storage"]
+	c.Assert(ok, jc.IsFalse)
+}
+
+func (s *providerSuite) TestValidateEmptyTemplatePrefix(c *gc.C) {
+	_, err := s.provider.Validate(fakeConfig(c, coretesting.Attrs{
+		"template-prefix": "",
+	}), nil)
+	c.Assert(err, gc.ErrorMatches, "invalid config: template-prefix: must not be empty")
+}
+
+func (s *providerSuite) TestValidateChange(c *gc.C) {
+	old := fakeConfig(c)
+	cfg, err := s.provider.Validate(fakeConfig(c, coretesting.Attrs{
+		"clone-storage": "ceph",
+	}), old)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.UnknownAttrs()["clone-storage"], gc.Equals, "ceph")
+}
@@ -0,0 +1,163 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package proxmox
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+// The Proxmox VE API can't store arbitrary cloud-init user data for a
+// VM, but it can upload ISO images. The user data is therefore put in
+// an ISO 9660 image labelled so that cloud-init's NoCloud datasource
+// finds it when the image is attached to the VM as a CD-ROM. See
+// https://cloudinit.readthedocs.io/en/latest/topics/datasources/nocloud.html.
+
+const (
+	// seedLabel is the volume label of the image.
+	seedLabel = "cidata"
+
+	// sectorSize is the size of the logical sectors of the image.
+	sectorSize = 2048
+
+	// The image has the minimum layout: the volume descriptors
+	// follow the 16 sectors of the system area, then come the
+	// two copies of the path table, then the root directory,
+	// and then the files in it.
+	pvdSector        = 16
+	terminatorSector = 17
+	lPathTableSector = 18
+	mPathTableSector = 19
+	rootDirSector    = 20
+	firstFileSector  = 21
+
+	// pathTableSize is the size of a path table with just the
+	// root directory in it.
+	pathTableSize = 10
+
+	// minImageSectors is the smallest size of an image. Some
+	// readers, libarchive among them, don't recognise an image
+	// unless it extends eight sectors beyond the system area.
+	minImageSectors = pvdSector + 8
+)
+
+// seedFileName returns the name of the file holding the cloud-init
+// seed image for the named machine.
+func seedFileName(machineName string) string {
+	return machineName + "-seed.iso"
+}
+
+// seedMetaData returns the cloud-init meta data for the named machine.
+func seedMetaData(machineName string) []byte {
+	return []byte(fmt.Sprintf("instance-id: %s\nlocal-hostname: %s\n", machineName, machineName))
+}
+
+// newSeedImage returns an ISO 9660 image holding the given user data
+// and meta data for cloud-init.
+func newSeedImage(userData, metaData []byte) []byte {
+	// Directory records must be sorted by name. The file names
+	// aren't restricted to the characters ISO 9660 allows, as
+	// Linux shows them as they are, in lower case and without
+	// the version.
+	files := []struct {
+		name    string
+		content []byte
+	}{
+		{"META-DATA;1", metaData},
+		{"USER-DATA;1", userData},
+	}
+
+	rootDir := make([]byte, sectorSize)
+	offset := copy(rootDir, dirRecord("\x00", rootDirSector, sectorSize, true))
+	offset += copy(rootDir[offset:], dirRecord("\x01", rootDirSector, sectorSize, true))
+	sector := uint32(firstFileSector)
+	var contents []byte
+	for _, f := range files {
+		record := dirRecord(f.name, sector, uint32(len(f.content)), false)
+		offset += copy(rootDir[offset:], record)
+		sectors := (len(f.content) + sectorSize - 1) / sectorSize
+		contents = append(contents, f.content...)
+		contents = append(contents, make([]byte, sectors*sectorSize-len(f.content))...)
+		sector += uint32(sectors)
+	}
+	if sector < minImageSectors {
+		contents = append(contents, make([]byte, (minImageSectors-sector)*sectorSize)...)
+		sector = minImageSectors
+	}
+
+	image := make([]byte, firstFileSector*sectorSize, int(sector)*sectorSize)
+	copy(image[pvdSector*sectorSize:], primaryVolumeDescriptor(sector))
+	copy(image[terminatorSector*sectorSize:], "\xffCD001\x01")
+	copy(image[lPathTableSector*sectorSize:], pathTable(binary.LittleEndian))
+	copy(image[mPathTableSector*sectorSize:], pathTable(binary.BigEndian))
+	copy(image[rootDirSector*sectorSize:], rootDir)
+	return append(image, contents...)
+}
+
+// primaryVolumeDescriptor returns the primary volume descriptor of an
+// image with the given number of sectors.
+func primaryVolumeDescriptor(sectors uint32) []byte {
+	pvd := make([]byte, sectorSize)
+	copy(pvd, "\x01CD001\x01")
+	// The system and volume identifiers, and the string fields from
+	// the volume set identifier to the bibliographic file identifier,
+	// are padded with spaces.
+	copy(pvd[8:72], fmt.Sprintf("%-32s%-32s", "", seedLabel))
+	bothEndian32(pvd[80:], sectors)
+	bothEndian16(pvd[120:], 1) // volume set size
+	bothEndian16(pvd[124:], 1) // volume sequence number
+	bothEndian16(pvd[128:], sectorSize)
+	bothEndian32(pvd[132:], pathTableSize)
+	binary.LittleEndian.PutUint32(pvd[140:], lPathTableSector)
+	binary.BigEndian.PutUint32(pvd[148:], mPathTableSector)
+	copy(pvd[156:190], dirRecord("\x00", rootDirSector, sectorSize, true))
+	copy(pvd[190:813], strings.Repeat(" ", 813-190))
+	// The creation, modification, expiration and effective dates
+	// are unspecified.
+	for offset := 813; offset < 881; offset += 17 {
+		copy(pvd[offset:], strings.Repeat("0", 16))
+	}
+	pvd[881] = 1 // file structure version
+	return pvd
+}
+
+// pathTable returns a path table holding just the root directory.
+func pathTable(order binary.ByteOrder) []byte {
+	table := make([]byte, pathTableSize)
+	table[0] = 1 // length of the name
+	order.PutUint32(table[2:], rootDirSector)
+	order.PutUint16(table[6:], 1) // parent directory number
+	return table
+}
+
+// dirRecord returns a directory record for the named file or directory,
+// whose content is in the given sector onwards.
+func dirRecord(name string, sector, size uint32, dir bool) []byte {
+	length := 33 + len(name)
+	if len(name)%2 == 0 {
+		length++
+	}
+	record := make([]byte, length)
+	record[0] = byte(length)
+	bothEndian32(record[2:], sector)
+	bothEndian32(record[10:], size)
+	if dir {
+		record[25] = 2
+	}
+	bothEndian16(record[28:], 1) // volume sequence number
+	record[32] = byte(len(name))
+	copy(record[33:], name)
+	return record
+}
+
+func bothEndian16(b []byte, v uint16) {
+	binary.LittleEndian.PutUint16(b, v)
+	binary.BigEndian.PutUint16(b[2:], v)
+}
+
+func bothEndian32(b []byte, v uint32) {
+	binary.LittleEndian.PutUint32(b, v)
+	binary.BigEndian.PutUint32(b[4:], v)
+}
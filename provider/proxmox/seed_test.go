@@ -0,0 +1,98 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package proxmox_test
+
+import (
+	"encoding/binary"
+	"strings"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/provider/proxmox"
+)
+
+type seedSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&seedSuite{})
+
+func (*seedSuite) TestNewSeedImage(c *gc.C) {
+	userData := []byte("#cloud-config\n" + strings.Repeat("x", 3000))
+	metaData := []byte("instance-id: juju-06f00d-0\n")
+	image := proxmox.NewSeedImage(userData, metaData)
+
+	// The image is a whole number of sectors.
+	c.Assert(len(image)%2048, gc.Equals, 0)
+	pvd := image[16*2048:]
+	c.Assert(string(pvd[:7]), gc.Equals, "\x01CD001\x01")
+	c.Assert(strings.TrimRight(string(pvd[40:72]), " "), gc.Equals, "cidata")
+	c.Assert(binary.LittleEndian.Uint32(pvd[80:]), gc.Equals, uint32(len(image)/2048))
+	c.Assert(string(image[17*2048:17*2048+7]), gc.Equals, "\xffCD001\x01")
+
+	// Linux shows the names in lower case.
+	c.Assert(readSeedFiles(c, image), jc.DeepEquals, map[string]string{
+		"META-DATA": string(metaData),
+		"USER-DATA": string(userData),
+	})
+}
+
+func (*seedSuite) TestNewSeedImageSmall(c *gc.C) {
+	image := proxmox.NewSeedImage([]byte("user"), []byte("meta"))
+
+	// The image is padded so that readers that look beyond
+	// the volume descriptors recognise it.
+	c.Assert(len(image), gc.Equals, 24*2048)
+	c.Assert(binary.LittleEndian.Uint32(image[16*2048+80:]), gc.Equals, uint32(24))
+	c.Assert(readSeedFiles(c, image), jc.DeepEquals, map[string]string{
+		"META-DATA": "meta",
+		"USER-DATA": "user",
+	})
+}
+
+func (*seedSuite) TestNewSeedImageDeterministic(c *gc.C) {
+	image1 := proxmox.NewSeedImage([]byte("user"), []byte("meta"))
+	image2 := proxmox.NewSeedImage([]byte("user"), []byte("meta"))
+	c.Assert(image1, jc.DeepEquals, image2)
+}
+
+// readSeedFiles follows the image's primary volume descriptor to its
+// root directory, and returns the content of the files in it, keyed by
+// name without the version. It checks that both copies of each
+// both-endian field agree.
+func readSeedFiles(c *gc.C, image []byte) map[string]string {
+	const sectorSize = 2048
+	extent := func(record []byte) []byte {
+		sector := binary.LittleEndian.Uint32(record[2:])
+		c.Assert(binary.BigEndian.Uint32(record[6:]), gc.Equals, sector)
+		size := binary.LittleEndian.Uint32(record[10:])
+		c.Assert(binary.BigEndian.Uint32(record[14:]), gc.Equals, size)
+		start := int(sector) * sectorSize
+		c.Assert(start+int(size) <= len(image), jc.IsTrue)
+		return image[start : start+int(size)]
+	}
+
+	pvd := image[16*sectorSize : 17*sectorSize]
+	c.Assert(binary.LittleEndian.Uint16(pvd[128:]), gc.Equals, uint16(sectorSize))
+	rootRecord := pvd[156:190]
+	c.Assert(rootRecord[25]&2, gc.Equals, byte(2))
+	rootDir := extent(rootRecord)
+
+	files := make(map[string]string)
+	for offset := 0; offset < len(rootDir) && rootDir[offset] != 0; {
+		record := rootDir[offset : offset+int(rootDir[offset])]
+		offset += len(record)
+		name := string(record[33 : 33+int(record[32])])
+		if name == "\x00" || name == "\x01" {
+			// The records for the directory itself and its parent.
+			continue
+		}
+		c.Assert(record[25]&2, gc.Equals, byte(0), gc.Commentf("%s", name))
+		name = strings.TrimSuffix(name, ";1")
+		files[name] = string(extent(record))
+	}
+	return files
+}
@@ -0,0 +1,452 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package proxmox
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/schema"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/storage"
+)
+
+const (
+	// storageProviderType is the storage provider type for volumes
+	// created in the cluster's storage and attached to VMs.
+	storageProviderType = storage.ProviderType("proxmox")
+
+	// storageAttr is the pool attribute naming the Proxmox VE
+	// storage in which volumes are created.
+	storageAttr = "storage"
+
+	// defaultStorage is the storage in which volumes are created
+	// if the pool doesn't say otherwise.
+	defaultStorage = "local-lvm"
+
+	// maxSCSIDevices is the number of SCSI devices a VM can have.
+	// The first is the boot disk.
+	maxSCSIDevices = 31
+
+	// maxSerialLength is the longest serial number QEMU allows a disk.
+	maxSerialLength = 20
+)
+
+var storageConfigFields = schema.Fields{
+	storageAttr: schema.String(),
+}
+
+var storageConfigChecker = schema.FieldMap(
+	storageConfigFields,
+	schema.Defaults{
+		storageAttr: defaultStorage,
+	},
+)
+
+type storageConfig struct {
+	storage string
+}
+
+func newStorageConfig(attrs map[string]interface{}) (*storageConfig, error) {
+	out, err := storageConfigChecker.Coerce(attrs, nil)
+	if err != nil {
+		return nil, errors.Annotate(err, "validating Proxmox VE storage config")
+	}
+	coerced := out.(map[string]interface{})
+	storageName := coerced[storageAttr].(string)
+	if storageName == "" {
+		return nil, errors.NotValidf("empty %q", storageAttr)
+	}
+	return &storageConfig{storage: storageName}, nil
+}
+
+// StorageProviderTypes implements storage.ProviderRegistry.
+func (env *environ) StorageProviderTypes() ([]storage.ProviderType, error) {
+	return []storage.ProviderType{storageProviderType}, nil
+}
+
+// StorageProvider implements storage.ProviderRegistry.
+func (env *environ) StorageProvider(t storage.ProviderType) (storage.Provider, error) {
+	if t == storageProviderType {
+		return &storageProvider{env}, nil
+	}
+	return nil, errors.NotFoundf("storage provider %q", t)
+}
+
+type storageProvider struct {
+	env *environ
+}
+
+var _ storage.Provider = (*storageProvider)(nil)
+
+// ValidateConfig implements storage.Provider.
+func (*storageProvider) ValidateConfig(cfg *storage.Config) error {
+	_, err := newStorageConfig(cfg.Attrs())
+	return errors.Trace(err)
+}
+
+// Supports implements storage.Provider.
+func (*storageProvider) Supports(k storage.StorageKind) bool {
+	return k == storage.StorageKindBlock
+}
+
+// Scope implements storage.Provider.
+func (*storageProvider) Scope() storage.Scope {
+	return storage.ScopeEnviron
+}
+
+// Dynamic implements storage.Provider.
+func (*storageProvider) Dynamic() bool {
+	return true
+}
+
+// Releasable implements storage.Provider. Volumes are owned by the
+// VM they are created for, and are deleted along with it, so they
+// can't be released.
+func (*storageProvider) Releasable() bool {
+	return false
+}
+
+// DefaultPools implements storage.Provider.
+func (*storageProvider) DefaultPools() []*storage.Config {
+	return nil
+}
+
+// FilesystemSource implements storage.Provider.
+func (*storageProvider) FilesystemSource(*storage.Config) (storage.FilesystemSource, error) {
+	return nil, errors.NotSupportedf("filesystems")
+}
+
+// VolumeSource implements storage.Provider.
+func (p *storageProvider) VolumeSource(cfg *storage.Config) (storage.VolumeSource, error) {
+	storageCfg, err := newStorageConfig(cfg.Attrs())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &volumeSource{
+		env:     p.env,
+		storage: storageCfg.storage,
+	}, nil
+}
+
+type volumeSource struct {
+	env     *environ
+	storage string
+}
+
+var _ storage.VolumeSource = (*volumeSource)(nil)
+
+// ValidateVolumeParams implements storage.VolumeSource.
+func (v *volumeSource) ValidateVolumeParams(params storage.VolumeParams) error {
+	if params.Attachment == nil {
+		// Volumes are owned by a VM, so they can't be
+		// created without knowing which one.
+		return errors.NotSupportedf("creating volume %s without an attachment", params.Tag.Id())
+	}
+	return nil
+}
+
+// CreateVolumes implements storage.VolumeSource.
+func (v *volumeSource) CreateVolumes(ctx context.ProviderCallContext, params []storage.VolumeParams) ([]storage.CreateVolumesResult, error) {
+	vms, err := v.vmsByInstanceId(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	results := make([]storage.CreateVolumesResult, len(params))
+	for i, p := range params {
+		if err := v.ValidateVolumeParams(p); err != nil {
+			results[i].Error = err
+			continue
+		}
+		vm, ok := vms[p.Attachment.InstanceId]
+		if !ok {
+			results[i].Error = errors.NotFoundf("instance %q", p.Attachment.InstanceId)
+			continue
+		}
+		volume, attachment, err := v.createVolume(vm, p)
+		if err != nil {
+			handleCredentialError(err, ctx)
+			logger.Errorf("creating volume %s: %v", p.Tag.Id(), err)
+			results[i].Error = err
+			continue
+		}
+		results[i].Volume = volume
+		results[i].VolumeAttachment = attachment
+	}
+	return results, nil
+}
+
+func (v *volumeSource) createVolume(vm virtualMachine, p storage.VolumeParams) (_ *storage.Volume, _ *storage.VolumeAttachment, err error) {
+	// Disk names must start with the ID of the VM that owns them.
+	filename := fmt.Sprintf("vm-%d-%s", vm.VMID, p.Tag.String())
+	volid, err := v.env.client.createVolume(vm.Node, v.storage, vm.VMID, filename, p.Size)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	defer func() {
+		if err == nil {
+			return
+		}
+		if err := v.env.client.deleteVolume(vm.Node, volid); err != nil {
+			logger.Errorf("cleaning up volume %q: %v", volid, err)
+		}
+	}()
+
+	info, err := v.attachVolume(vm, volid, p.Tag)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	volume := &storage.Volume{
+		Tag: p.Tag,
+		VolumeInfo: storage.VolumeInfo{
+			VolumeId: makeVolumeId(vm.Node, volid),
+			Size:     p.Size,
+		},
+	}
+	attachment := &storage.VolumeAttachment{
+		Volume:               p.Tag,
+		Machine:              p.Attachment.Machine,
+		VolumeAttachmentInfo: info,
+	}
+	return volume, attachment, nil
+}
+
+// ListVolumes implements storage.VolumeSource.
+func (v *volumeSource) ListVolumes(ctx context.ProviderCallContext) ([]string, error) {
+	vms, err := v.env.modelVMs(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	owners := make(map[string]map[int]bool)
+	for _, vm := range vms {
+		if owners[vm.Node] == nil {
+			owners[vm.Node] = make(map[int]bool)
+		}
+		owners[vm.Node][vm.VMID] = true
+	}
+	var volumeIds []string
+	for node, vmids := range owners {
+		volumes, err := v.env.client.storageVolumes(node, v.storage)
+		if err != nil {
+			handleCredentialError(err, ctx)
+			return nil, errors.Trace(err)
+		}
+		for _, volume := range volumes {
+			// Only the volumes made for the storage provider are
+			// listed, and not the VMs' boot disks.
+			if vmids[volume.VMID] && strings.Contains(volume.VolID, "-"+names.VolumeTagKind+"-") {
+				volumeIds = append(volumeIds, makeVolumeId(node, volume.VolID))
+			}
+		}
+	}
+	return volumeIds, nil
+}
+
+// DescribeVolumes implements storage.VolumeSource.
+func (v *volumeSource) DescribeVolumes(ctx context.ProviderCallContext, volumeIds []string) ([]storage.DescribeVolumesResult, error) {
+	results := make([]storage.DescribeVolumesResult, len(volumeIds))
+	for i, volumeId := range volumeIds {
+		node, volid, err := parseVolumeId(volumeId)
+		if err != nil {
+			results[i].Error = err
+			continue
+		}
+		volume, err := v.env.client.storageVolume(node, volid)
+		if err != nil {
+			handleCredentialError(err, ctx)
+			results[i].Error = err
+			continue
+		}
+		results[i].VolumeInfo = &storage.VolumeInfo{
+			VolumeId: volumeId,
+			Size:     volume.Size / (1024 * 1024),
+		}
+	}
+	return results, nil
+}
+
+// DestroyVolumes implements storage.VolumeSource.
+func (v *volumeSource) DestroyVolumes(ctx context.ProviderCallContext, volumeIds []string) ([]error, error) {
+	results := make([]error, len(volumeIds))
+	for i, volumeId := range volumeIds {
+		node, volid, err := parseVolumeId(volumeId)
+		if err != nil {
+			results[i] = err
+			continue
+		}
+		if err := v.env.client.deleteVolume(node, volid); err != nil && !isNotFound(err) {
+			handleCredentialError(err, ctx)
+			results[i] = err
+		}
+	}
+	return results, nil
+}
+
+// ReleaseVolumes implements storage.VolumeSource.
+func (v *volumeSource) ReleaseVolumes(ctx context.ProviderCallContext, volumeIds []string) ([]error, error) {
+	return nil, errors.NotSupportedf("releasing volumes")
+}
+
+// AttachVolumes implements storage.VolumeSource.
+func (v *volumeSource) AttachVolumes(ctx context.ProviderCallContext, params []storage.VolumeAttachmentParams) ([]storage.AttachVolumesResult, error) {
+	vms, err := v.vmsByInstanceId(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	results := make([]storage.AttachVolumesResult, len(params))
+	for i, p := range params {
+		vm, ok := vms[p.InstanceId]
+		if !ok {
+			results[i].Error = errors.NotFoundf("instance %q", p.InstanceId)
+			continue
+		}
+		_, volid, err := parseVolumeId(p.VolumeId)
+		if err != nil {
+			results[i].Error = err
+			continue
+		}
+		info, err := v.attachVolume(vm, volid, p.Volume)
+		if err != nil {
+			handleCredentialError(err, ctx)
+			logger.Errorf("attaching %q to %q: %v", p.VolumeId, p.InstanceId, err)
+			results[i].Error = err
+			continue
+		}
+		results[i].VolumeAttachment = &storage.VolumeAttachment{
+			Volume:               p.Volume,
+			Machine:              p.Machine,
+			VolumeAttachmentInfo: info,
+		}
+	}
+	return results, nil
+}
+
+// attachVolume attaches the volume to the first free SCSI device of the
+// VM, unless it is already attached, and returns the attachment.
+func (v *volumeSource) attachVolume(vm virtualMachine, volid string, tag names.VolumeTag) (storage.VolumeAttachmentInfo, error) {
+	cfg, err := v.env.client.vmConfig(vm.Node, vm.VMID)
+	if err != nil {
+		return storage.VolumeAttachmentInfo{}, errors.Trace(err)
+	}
+	serial := volumeSerial(tag)
+	info := storage.VolumeAttachmentInfo{
+		DeviceLink: "/dev/disk/by-id/scsi-0QEMU_QEMU_HARDDISK_" + serial,
+	}
+	if device := findDevice(cfg, volid); device != "" {
+		return info, nil
+	}
+	device := ""
+	for i := 1; i < maxSCSIDevices; i++ {
+		if cfg.get(fmt.Sprintf("scsi%d", i)) == "" {
+			device = fmt.Sprintf("scsi%d", i)
+			break
+		}
+	}
+	if device == "" {
+		return storage.VolumeAttachmentInfo{}, errors.Errorf("VM %d has no free SCSI devices", vm.VMID)
+	}
+	if err := v.env.client.setVMConfig(vm.Node, vm.VMID, url.Values{
+		device: {fmt.Sprintf("%s,serial=%s", volid, serial)},
+	}); err != nil {
+		return storage.VolumeAttachmentInfo{}, errors.Trace(err)
+	}
+	return info, nil
+}
+
+// DetachVolumes implements storage.VolumeSource.
+func (v *volumeSource) DetachVolumes(ctx context.ProviderCallContext, params []storage.VolumeAttachmentParams) ([]error, error) {
+	vms, err := v.vmsByInstanceId(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	results := make([]error, len(params))
+	for i, p := range params {
+		vm, ok := vms[p.InstanceId]
+		if !ok {
+			// The VM, and so the attachment, is already gone.
+			continue
+		}
+		_, volid, err := parseVolumeId(p.VolumeId)
+		if err != nil {
+			results[i] = err
+			continue
+		}
+		if err := v.detachVolume(vm, volid); err != nil {
+			handleCredentialError(err, ctx)
+			results[i] = err
+		}
+	}
+	return results, nil
+}
+
+func (v *volumeSource) detachVolume(vm virtualMachine, volid string) error {
+	cfg, err := v.env.client.vmConfig(vm.Node, vm.VMID)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	device := findDevice(cfg, volid)
+	if device == "" {
+		return nil
+	}
+	return errors.Trace(v.env.client.setVMConfig(vm.Node, vm.VMID, url.Values{
+		"delete": {device},
+	}))
+}
+
+// vmsByInstanceId returns the model's VMs, keyed by instance ID.
+func (v *volumeSource) vmsByInstanceId(ctx context.ProviderCallContext) (map[instance.Id]virtualMachine, error) {
+	vms, err := v.env.modelVMs(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	byId := make(map[instance.Id]virtualMachine, len(vms))
+	for _, vm := range vms {
+		byId[instance.Id(vm.Name)] = vm
+	}
+	return byId, nil
+}
+
+// findDevice returns the SCSI device of the VM to which the
+// volume is attached, or "" if it isn't attached.
+func findDevice(cfg vmConfig, volid string) string {
+	for i := 1; i < maxSCSIDevices; i++ {
+		device := fmt.Sprintf("scsi%d", i)
+		if strings.SplitN(cfg.get(device), ",", 2)[0] == volid {
+			return device
+		}
+	}
+	return ""
+}
+
+// volumeSerial returns the serial number given to the disk for the
+// volume, which identifies it in the machine.
+func volumeSerial(tag names.VolumeTag) string {
+	serial := tag.String()
+	if len(serial) > maxSerialLength {
+		serial = serial[:maxSerialLength]
+	}
+	return serial
+}
+
+// makeVolumeId returns the ID of the volume with the given ID in the
+// storage of the given node. Storage may be local to a node, so the
+// node is part of the ID.
+func makeVolumeId(node, volid string) string {
+	return node + "/" + volid
+}
+
+// parseVolumeId returns the node and Proxmox VE volume ID
+// of the volume with the given ID.
+func parseVolumeId(volumeId string) (node, volid string, _ error) {
+	parts := strings.SplitN(volumeId, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", errors.NotValidf("volume ID %q", volumeId)
+	}
+	return parts[0], parts[1], nil
+}
@@ -0,0 +1,238 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package proxmox_test
+
+import (
+	"fmt"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/storage"
+)
+
+type storageSuite struct {
+	EnvironFixture
+	provider storage.Provider
+	source   storage.VolumeSource
+	vm       *fakeVM
+}
+
+var _ = gc.Suite(&storageSuite{})
+
+func (s *storageSuite) SetUpTest(c *gc.C) {
+	s.EnvironFixture.SetUpTest(c)
+	s.vm = s.api.AddVM("pve1", "juju-f75cba-0")
+
+	var err error
+	s.provider, err = s.env.StorageProvider("proxmox")
+	c.Assert(err, jc.ErrorIsNil)
+	cfg, err := storage.NewConfig("proxmox", "proxmox", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.source, err = s.provider.VolumeSource(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *storageSuite) TestStorageProviderTypes(c *gc.C) {
+	types, err := s.env.StorageProviderTypes()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(types, jc.DeepEquals, []storage.ProviderType{"proxmox"})
+
+	_, err = s.env.StorageProvider("ebs")
+	c.Assert(err, gc.ErrorMatches, `storage provider "ebs" not found`)
+}
+
+func (s *storageSuite) TestProvider(c *gc.C) {
+	c.Assert(s.provider.Supports(storage.StorageKindBlock), jc.IsTrue)
+	c.Assert(s.provider.Supports(storage.StorageKindFilesystem), jc.IsFalse)
+	c.Assert(s.provider.Scope(), gc.Equals, storage.ScopeEnviron)
+	c.Assert(s.provider.Dynamic(), jc.IsTrue)
+	c.Assert(s.provider.Releasable(), jc.IsFalse)
+}
+
+func (s *storageSuite) TestValidateConfig(c *gc.C) {
+	cfg, err := storage.NewConfig("fast", "proxmox", map[string]interface{}{
+		"storage": "ceph",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.provider.ValidateConfig(cfg), jc.ErrorIsNil)
+
+	cfg, err = storage.NewConfig("fast", "proxmox", map[string]interface{}{
+		"storage": 42,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.provider.ValidateConfig(cfg)
+	c.Assert(err, gc.ErrorMatches, `validating Proxmox VE storage config: storage: expected string, got int\(42\)`)
+}
+
+func (s *storageSuite) createVolume(c *gc.C) storage.CreateVolumesResult {
+	results, err := s.source.CreateVolumes(s.callCtx, []storage.VolumeParams{{
+		Tag:      names.NewVolumeTag("0"),
+		Size:     1024,
+		Provider: "proxmox",
+		Attachment: &storage.VolumeAttachmentParams{
+			AttachmentParams: storage.AttachmentParams{
+				Provider:   "proxmox",
+				Machine:    names.NewMachineTag("0"),
+				InstanceId: "juju-f75cba-0",
+			},
+			Volume: names.NewVolumeTag("0"),
+		},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	return results[0]
+}
+
+func (s *storageSuite) TestCreateVolumes(c *gc.C) {
+	result := s.createVolume(c)
+	c.Assert(result.Error, jc.ErrorIsNil)
+	c.Assert(result.Volume, jc.DeepEquals, &storage.Volume{
+		Tag: names.NewVolumeTag("0"),
+		VolumeInfo: storage.VolumeInfo{
+			VolumeId: "pve1/local-lvm:vm-100-volume-0",
+			Size:     1024,
+		},
+	})
+	c.Assert(result.VolumeAttachment, jc.DeepEquals, &storage.VolumeAttachment{
+		Volume:  names.NewVolumeTag("0"),
+		Machine: names.NewMachineTag("0"),
+		VolumeAttachmentInfo: storage.VolumeAttachmentInfo{
+			DeviceLink: "/dev/disk/by-id/scsi-0QEMU_QEMU_HARDDISK_volume-0",
+		},
+	})
+
+	volume := s.api.Volumes["local-lvm:vm-100-volume-0"]
+	c.Assert(volume, gc.NotNil)
+	c.Assert(volume.VMID, gc.Equals, 100)
+	c.Assert(volume.Size, gc.Equals, uint64(1024*1024*1024))
+	c.Assert(s.vm.Config["scsi1"], gc.Equals, "local-lvm:vm-100-volume-0,serial=volume-0")
+}
+
+func (s *storageSuite) TestCreateVolumesUnknownInstance(c *gc.C) {
+	results, err := s.source.CreateVolumes(s.callCtx, []storage.VolumeParams{{
+		Tag:  names.NewVolumeTag("0"),
+		Size: 1024,
+		Attachment: &storage.VolumeAttachmentParams{
+			AttachmentParams: storage.AttachmentParams{
+				Machine:    names.NewMachineTag("1"),
+				InstanceId: "juju-f75cba-1",
+			},
+		},
+	}, {
+		Tag:  names.NewVolumeTag("1"),
+		Size: 1024,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Assert(results[0].Error, gc.ErrorMatches, `instance "juju-f75cba-1" not found`)
+	c.Assert(results[1].Error, gc.ErrorMatches, "creating volume 1 without an attachment not supported")
+	c.Assert(s.api.Volumes, gc.HasLen, 0)
+}
+
+func (s *storageSuite) TestCreateVolumesCleansUp(c *gc.C) {
+	s.api.Fail["PUT /nodes/pve1/qemu/100/config"] = "config locked"
+
+	result := s.createVolume(c)
+	c.Assert(result.Error, gc.ErrorMatches, "configuring VM 100: config locked")
+	c.Assert(s.api.Volumes, gc.HasLen, 0)
+}
+
+func (s *storageSuite) TestListVolumes(c *gc.C) {
+	s.createVolume(c)
+	other := s.api.AddVM("pve2", "juju-0f00d0-0")
+	s.api.Volumes["local-lvm:vm-100-disk-0"] = &fakeVolume{
+		Node: "pve1", VolID: "local-lvm:vm-100-disk-0", VMID: 100,
+	}
+	s.api.Volumes["local-lvm:vm-101-volume-0"] = &fakeVolume{
+		Node: "pve2", VolID: "local-lvm:vm-101-volume-0", VMID: other.VMID,
+	}
+
+	volumeIds, err := s.source.ListVolumes(s.callCtx)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumeIds, jc.DeepEquals, []string{"pve1/local-lvm:vm-100-volume-0"})
+}
+
+func (s *storageSuite) TestDescribeVolumes(c *gc.C) {
+	s.createVolume(c)
+
+	results, err := s.source.DescribeVolumes(s.callCtx, []string{
+		"pve1/local-lvm:vm-100-volume-0",
+		"pve1/local-lvm:vm-100-volume-1",
+		"invalid",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 3)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].VolumeInfo, jc.DeepEquals, &storage.VolumeInfo{
+		VolumeId: "pve1/local-lvm:vm-100-volume-0",
+		Size:     1024,
+	})
+	c.Assert(results[1].Error, gc.ErrorMatches, `getting volume "local-lvm:vm-100-volume-1": no such volume .*`)
+	c.Assert(results[2].Error, gc.ErrorMatches, `volume ID "invalid" not valid`)
+}
+
+func (s *storageSuite) TestDetachAndAttachVolumes(c *gc.C) {
+	s.createVolume(c)
+	params := []storage.VolumeAttachmentParams{{
+		AttachmentParams: storage.AttachmentParams{
+			Provider:   "proxmox",
+			Machine:    names.NewMachineTag("0"),
+			InstanceId: "juju-f75cba-0",
+		},
+		Volume:   names.NewVolumeTag("0"),
+		VolumeId: "pve1/local-lvm:vm-100-volume-0",
+	}}
+
+	errs, err := s.source.DetachVolumes(s.callCtx, params)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(errs, jc.DeepEquals, []error{nil})
+	_, ok := s.vm.Config["scsi1"]
+	c.Assert(ok, jc.IsFalse)
+
+	results, err := s.source.AttachVolumes(s.callCtx, params)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].VolumeAttachment.DeviceLink, gc.Equals, "/dev/disk/by-id/scsi-0QEMU_QEMU_HARDDISK_volume-0")
+	c.Assert(s.vm.Config["scsi1"], gc.Equals, "local-lvm:vm-100-volume-0,serial=volume-0")
+
+	// Attaching again changes nothing.
+	s.api.ResetRequests()
+	results, err = s.source.AttachVolumes(s.callCtx, params)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(s.api.Requests, gc.Not(jc.Contains), "PUT /nodes/pve1/qemu/100/config")
+}
+
+func (s *storageSuite) TestAttachVolumesNoFreeDevices(c *gc.C) {
+	for i := 1; i < 31; i++ {
+		s.vm.Config[fmt.Sprintf("scsi%d", i)] = fmt.Sprintf("local-lvm:vm-100-disk-%d,size=1G", i)
+	}
+	results, err := s.source.AttachVolumes(s.callCtx, []storage.VolumeAttachmentParams{{
+		AttachmentParams: storage.AttachmentParams{
+			Machine:    names.NewMachineTag("0"),
+			InstanceId: instance.Id("juju-f75cba-0"),
+		},
+		Volume:   names.NewVolumeTag("0"),
+		VolumeId: "pve1/local-lvm:vm-100-volume-0",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results[0].Error, gc.ErrorMatches, "VM 100 has no free SCSI devices")
+}
+
+func (s *storageSuite) TestDestroyVolumes(c *gc.C) {
+	s.createVolume(c)
+
+	errs, err := s.source.DestroyVolumes(s.callCtx, []string{
+		"pve1/local-lvm:vm-100-volume-0",
+		// Volumes that are already gone are ignored.
+		"pve1/local-lvm:vm-100-volume-1",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(errs, jc.DeepEquals, []error{nil, nil})
+	c.Assert(s.api.Volumes, gc.HasLen, 0)
+}
@@ -4,6 +4,8 @@
 package vsphere
 
 import (
+	"strings"
+
 	"github.com/juju/errors"
 	"github.com/juju/schema"
 
@@ -16,6 +18,10 @@ const (
 	cfgExternalNetwork = "external-network"
 	cfgDatastore       = "datastore"
 	cfgEnableDiskUUID  = "enable-disk-uuid"
+	cfgVMFolder        = "vm-folder"
+	cfgResourcePool    = "resource-pool"
+	cfgVMTemplates     = "vm-templates"
+	cfgLinkedClones    = "linked-clones"
 )
 
 // configFields is the spec for each vmware config value's type.
@@ -25,6 +31,10 @@ var (
 		cfgDatastore:       schema.String(),
 		cfgPrimaryNetwork:  schema.String(),
 		cfgEnableDiskUUID:  schema.Bool(),
+		cfgVMFolder:        schema.String(),
+		cfgResourcePool:    schema.String(),
+		cfgVMTemplates:     schema.String(),
+		cfgLinkedClones:    schema.Bool(),
 	}
 
 	configDefaults = schema.Defaults{
@@ -32,10 +42,19 @@ var (
 		cfgDatastore:       schema.Omit,
 		cfgPrimaryNetwork:  schema.Omit,
 		cfgEnableDiskUUID:  true,
+		cfgVMFolder:        schema.Omit,
+		cfgResourcePool:    schema.Omit,
+		cfgVMTemplates:     schema.Omit,
+		cfgLinkedClones:    false,
 	}
 
-	configRequiredFields  = []string{}
-	configImmutableFields = []string{}
+	configRequiredFields = []string{}
+
+	// The VM folder cannot be changed, as the model's VMs
+	// would then no longer be found.
+	configImmutableFields = []string{
+		cfgVMFolder,
+	}
 )
 
 type environConfig struct {
@@ -99,6 +118,68 @@ func (c *environConfig) enableDiskUUID() bool {
 	return c.attrs[cfgEnableDiskUUID].(bool)
 }
 
+func (c *environConfig) vmFolder() string {
+	folder, _ := c.attrs[cfgVMFolder].(string)
+	return strings.Trim(folder, "/")
+}
+
+func (c *environConfig) resourcePool() string {
+	pool, _ := c.attrs[cfgResourcePool].(string)
+	return strings.Trim(pool, "/")
+}
+
+func (c *environConfig) linkedClones() bool {
+	linked, _ := c.attrs[cfgLinkedClones].(bool)
+	return linked
+}
+
+// vmTemplates returns the VM templates to clone VMs from, keyed by
+// series or by "series/arch".
+func (c *environConfig) vmTemplates() map[string]string {
+	templates, _ := c.attrs[cfgVMTemplates].(string)
+	result, _ := parseVMTemplates(templates)
+	return result
+}
+
+// vmTemplate returns the path of the VM template to clone for the given
+// series, and the architecture of the template. A template configured
+// for a specific architecture is preferred over one configured for the
+// series alone, which is assumed to be of the first of the given
+// architectures. If there is no template for the series, vmTemplate
+// returns an empty path.
+func (c *environConfig) vmTemplate(series string, arches []string) (template, arch string) {
+	templates := c.vmTemplates()
+	for _, arch := range arches {
+		if template, ok := templates[series+"/"+arch]; ok {
+			return template, arch
+		}
+	}
+	if template, ok := templates[series]; ok && len(arches) > 0 {
+		return template, arches[0]
+	}
+	return "", ""
+}
+
+// parseVMTemplates parses the vm-templates config value, which is a
+// space-separated list of <series>[/<arch>]=<template path> entries.
+func parseVMTemplates(value string) (map[string]string, error) {
+	templates := make(map[string]string)
+	for _, entry := range strings.Fields(value) {
+		pos := strings.IndexRune(entry, '=')
+		if pos <= 0 || pos == len(entry)-1 {
+			return nil, errors.Errorf(
+				"expected <series>[/<arch>]=<template path>, got %q", entry,
+			)
+		}
+		key, template := entry[:pos], entry[pos+1:]
+		if _, ok := templates[key]; ok {
+			return nil, errors.Errorf("duplicate template for %q", key)
+		}
+		templates[key] = strings.Trim(template, "/")
+	}
+	return templates, nil
+}
+
 // validate checks vmware-specific config values.
 func (c environConfig) validate() error {
 	// All fields must be populated, even with just the default.
@@ -107,6 +188,11 @@ func (c environConfig) validate() error {
 			return errors.Errorf("%s: must not be empty", field)
 		}
 	}
+	if templates, ok := c.attrs[cfgVMTemplates].(string); ok {
+		if _, err := parseVMTemplates(templates); err != nil {
+			return errors.Annotate(err, cfgVMTemplates)
+		}
+	}
 	return nil
 }
 
@@ -123,6 +123,25 @@ var newConfigTests = []configTestSpec{{
 	info:   "unknown field is not touched",
 	insert: testing.Attrs{"unknown-field": "12345"},
 	expect: testing.Attrs{"unknown-field": "12345"},
+}, {
+	info:   "linked-clones defaults to false",
+	expect: testing.Attrs{"linked-clones": false},
+}, {
+	info:   "vm templates",
+	insert: testing.Attrs{"vm-templates": "bionic=templates/bionic xenial/amd64=xenial"},
+	expect: testing.Attrs{"vm-templates": "bionic=templates/bionic xenial/amd64=xenial"},
+}, {
+	info:   "vm templates missing path",
+	insert: testing.Attrs{"vm-templates": "bionic="},
+	err:    `vm-templates: expected <series>\[/<arch>\]=<template path>, got "bionic="`,
+}, {
+	info:   "vm templates missing series",
+	insert: testing.Attrs{"vm-templates": "templates/bionic"},
+	err:    `vm-templates: expected <series>\[/<arch>\]=<template path>, got "templates/bionic"`,
+}, {
+	info:   "vm templates duplicate series",
+	insert: testing.Attrs{"vm-templates": "bionic=a bionic=b"},
+	err:    `vm-templates: duplicate template for "bionic"`,
 }}
 
 func (*ConfigSuite) TestNewModelConfig(c *gc.C) {
@@ -199,6 +218,14 @@ var changeConfigTests = []configTestSpec{{
 	info:   "can insert unknown field",
 	insert: testing.Attrs{"unknown": "ignoti"},
 	expect: testing.Attrs{"unknown": "ignoti"},
+}, {
+	info:   "can change resource pool",
+	insert: testing.Attrs{"resource-pool": "juju"},
+	expect: testing.Attrs{"resource-pool": "juju"},
+}, {
+	info:   "cannot change vm folder",
+	insert: testing.Attrs{"vm-folder": "juju"},
+	err:    "vm-folder: cannot change from <nil> to juju",
 }}
 
 func (s *ConfigSuite) TestValidateChange(c *gc.C) {
@@ -152,7 +152,7 @@ func (env *sessionEnviron) Bootstrap(
 
 func (env *sessionEnviron) ensureVMFolder(controllerUUID string, ctx callcontext.ProviderCallContext) error {
 	_, err := env.client.EnsureVMFolder(env.ctx, path.Join(
-		controllerFolderName(controllerUUID),
+		env.controllerFolderPath(controllerUUID),
 		env.modelFolderName(),
 	))
 	HandleCredentialError(err, ctx)
@@ -173,9 +173,9 @@ func (env *environ) AdoptResources(ctx callcontext.ProviderCallContext, controll
 // AdoptResources is part of the Environ interface.
 func (env *sessionEnviron) AdoptResources(ctx callcontext.ProviderCallContext, controllerUUID string, fromVersion version.Number) error {
 	err := env.client.MoveVMFolderInto(env.ctx,
-		controllerFolderName(controllerUUID),
+		env.controllerFolderPath(controllerUUID),
 		path.Join(
-			controllerFolderName("*"),
+			env.controllerFolderPath("*"),
 			env.modelFolderName(),
 		),
 	)
@@ -200,7 +200,7 @@ func (env *sessionEnviron) Destroy(ctx callcontext.ProviderCallContext) error {
 		return errors.Trace(err)
 	}
 	err := env.client.DestroyVMFolder(env.ctx, path.Join(
-		controllerFolderName("*"),
+		env.controllerFolderPath("*"),
 		env.modelFolderName(),
 	))
 	HandleCredentialError(err, ctx)
@@ -219,16 +219,16 @@ func (env *sessionEnviron) DestroyController(ctx callcontext.ProviderCallContext
 	if err := env.Destroy(ctx); err != nil {
 		return errors.Trace(err)
 	}
-	controllerFolderName := controllerFolderName(controllerUUID)
+	controllerFolderPath := env.controllerFolderPath(controllerUUID)
 	if err := env.client.RemoveVirtualMachines(env.ctx, path.Join(
-		controllerFolderName,
+		controllerFolderPath,
 		modelFolderName("*", "*"),
 		"*",
 	)); err != nil {
 		HandleCredentialError(err, ctx)
 		return errors.Annotate(err, "removing VMs")
 	}
-	if err := env.client.DestroyVMFolder(env.ctx, controllerFolderName); err != nil {
+	if err := env.client.DestroyVMFolder(env.ctx, controllerFolderPath); err != nil {
 		HandleCredentialError(err, ctx)
 		return errors.Annotate(err, "destroying VM folder")
 	}
@@ -14,6 +14,7 @@ import (
 	"github.com/juju/clock"
 	"github.com/juju/errors"
 	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
 
 	"github.com/juju/juju/cloudconfig/cloudinit"
 	"github.com/juju/juju/cloudconfig/instancecfg"
@@ -76,15 +77,23 @@ func (env *environ) StartInstance(ctx context.ProviderCallContext, args environs
 
 // StartInstance implements environs.InstanceBroker.
 func (env *sessionEnviron) StartInstance(ctx context.ProviderCallContext, args environs.StartInstanceParams) (*environs.StartInstanceResult, error) {
-	img, err := findImageMetadata(env, args)
-	if err != nil {
-		return nil, common.ZoneIndependentError(err)
+	// If the user has configured a VM template for the series, we clone
+	// that; otherwise we import the OVA from the image metadata.
+	template, arch := env.ecfg.vmTemplate(args.Tools.OneSeries(), args.Tools.Arches())
+	var img *OvaFileMetadata
+	if template == "" {
+		var err error
+		img, err = findImageMetadata(env, args)
+		if err != nil {
+			return nil, common.ZoneIndependentError(err)
+		}
+		arch = img.Arch
 	}
-	if err := env.finishMachineConfig(args, img); err != nil {
+	if err := env.finishMachineConfig(args, arch); err != nil {
 		return nil, common.ZoneIndependentError(err)
 	}
 
-	vm, hw, err := env.newRawInstance(ctx, args, img)
+	vm, hw, err := env.newRawInstance(ctx, args, arch, img, template)
 	if err != nil {
 		args.StatusCallback(status.ProvisioningError, fmt.Sprint(err), nil)
 		return nil, errors.Trace(err)
@@ -105,8 +114,8 @@ var FinishInstanceConfig = instancecfg.FinishInstanceConfig
 
 // finishMachineConfig updates args.MachineConfig in place. Setting up
 // the API, StateServing, and SSHkeys information.
-func (env *sessionEnviron) finishMachineConfig(args environs.StartInstanceParams, img *OvaFileMetadata) error {
-	envTools, err := args.Tools.Match(tools.Filter{Arch: img.Arch})
+func (env *sessionEnviron) finishMachineConfig(args environs.StartInstanceParams, arch string) error {
+	envTools, err := args.Tools.Match(tools.Filter{Arch: arch})
 	if err != nil {
 		return err
 	}
@@ -117,12 +126,16 @@ func (env *sessionEnviron) finishMachineConfig(args environs.StartInstanceParams
 }
 
 // newRawInstance is where the new physical instance is actually
-// provisioned, relative to the provided args and spec. Info for that
-// low-level instance is returned.
+// provisioned, relative to the provided args and spec. The instance is
+// cloned from the given VM template if one is specified, and otherwise
+// created from the given image. Info for that low-level instance is
+// returned.
 func (env *sessionEnviron) newRawInstance(
 	ctx context.ProviderCallContext,
 	args environs.StartInstanceParams,
+	arch string,
 	img *OvaFileMetadata,
+	template string,
 ) (_ *mo.VirtualMachine, _ *instance.HardwareCharacteristics, err error) {
 
 	vmName, err := env.namespace.Hostname(args.InstanceConfig.MachineId)
@@ -186,9 +199,21 @@ func (env *sessionEnviron) newRawInstance(
 
 	// Obtain the final constraints by merging with defaults.
 	cons := args.Constraints
-	minRootDisk := common.MinRootDiskSizeGiB(args.InstanceConfig.Series) * 1024
-	if cons.RootDisk == nil || *cons.RootDisk < minRootDisk {
-		cons.RootDisk = &minRootDisk
+	linkedClone := template != "" && env.ecfg.linkedClones()
+	if linkedClone {
+		// The disks of a linked clone are backed by the template's
+		// snapshot and cannot be extended, so the root disk is the
+		// size of the template's.
+		if cons.RootDisk != nil {
+			return nil, nil, common.ZoneIndependentError(
+				errors.NotSupportedf("root-disk constraint with linked-clones"),
+			)
+		}
+	} else {
+		minRootDisk := common.MinRootDiskSizeGiB(args.InstanceConfig.Series) * 1024
+		if cons.RootDisk == nil || *cons.RootDisk < minRootDisk {
+			cons.RootDisk = &minRootDisk
+		}
 	}
 
 	// Download and extract the OVA file. If we're bootstrapping we use
@@ -201,34 +226,44 @@ func (env *sessionEnviron) newRawInstance(
 		args.StatusCallback(status.Provisioning, message, nil)
 	}
 
-	readOVA := func() (string, io.ReadCloser, error) {
-		resp, err := http.Get(img.URL)
-		if err != nil {
-			return "", nil, errors.Trace(err)
-		}
-		return img.URL, resp.Body, nil
+	// The root-disk-source constraint, if specified, names the
+	// datastore to use in place of the one in model config.
+	datastore := env.ecfg.datastore()
+	if cons.HasRootDiskSource() {
+		datastore = *cons.RootDiskSource
 	}
 
 	createVMArgs := vsphereclient.CreateVirtualMachineParams{
 		Name: vmName,
 		Folder: path.Join(
-			controllerFolderName(args.ControllerUUID),
+			env.controllerFolderPath(args.ControllerUUID),
 			env.modelFolderName(),
 		),
 		Series:                 series,
-		ReadOVA:                readOVA,
-		OVASHA256:              img.Sha256,
+		Template:               template,
+		LinkedClone:            linkedClone,
 		VMDKDirectory:          vmdkDirectoryName(args.ControllerUUID),
 		UserData:               string(userData),
 		Metadata:               args.InstanceConfig.Tags,
 		Constraints:            cons,
 		NetworkDevices:         networkDevices,
-		Datastore:              env.ecfg.datastore(),
+		ResourcePool:           env.ecfg.resourcePool(),
+		Datastore:              datastore,
 		UpdateProgress:         updateProgress,
 		UpdateProgressInterval: updateProgressInterval,
 		Clock:                  clock.WallClock,
 		EnableDiskUUID:         env.ecfg.enableDiskUUID(),
 	}
+	if img != nil {
+		createVMArgs.ReadOVA = func() (string, io.ReadCloser, error) {
+			resp, err := http.Get(img.URL)
+			if err != nil {
+				return "", nil, errors.Trace(err)
+			}
+			return img.URL, resp.Body, nil
+		}
+		createVMArgs.OVASHA256 = img.Sha256
+	}
 
 	// Attempt to create a VM in each of the AZs in turn.
 	logger.Debugf("attempting to create VM in availability zone %s", args.AvailabilityZone)
@@ -244,16 +279,35 @@ func (env *sessionEnviron) newRawInstance(
 		return nil, nil, errors.Trace(err)
 	}
 
+	rootDisk := cons.RootDisk
+	if linkedClone {
+		rootDisk = rootDiskSize(vm)
+	}
 	hw := &instance.HardwareCharacteristics{
-		Arch:     &img.Arch,
+		Arch:     &arch,
 		Mem:      cons.Mem,
 		CpuCores: cons.CpuCores,
 		CpuPower: cons.CpuPower,
-		RootDisk: cons.RootDisk,
+		RootDisk: rootDisk,
 	}
 	return vm, hw, err
 }
 
+// rootDiskSize returns the size of the VM's root disk in MiB, or nil
+// if the VM has no disks.
+func rootDiskSize(vm *mo.VirtualMachine) *uint64 {
+	if vm.Config == nil {
+		return nil
+	}
+	for _, dev := range vm.Config.Hardware.Device {
+		if disk, ok := dev.(*types.VirtualDisk); ok {
+			size := uint64(disk.CapacityInKB) / 1024
+			return &size
+		}
+	}
+	return nil
+}
+
 // AllInstances implements environs.InstanceBroker.
 func (env *environ) AllInstances(ctx context.ProviderCallContext) (instances []instance.Instance, err error) {
 	err = env.withSession(ctx, func(env *sessionEnviron) error {
@@ -266,7 +320,7 @@ func (env *environ) AllInstances(ctx context.ProviderCallContext) (instances []i
 // AllInstances implements environs.InstanceBroker.
 func (env *sessionEnviron) AllInstances(ctx context.ProviderCallContext) ([]instance.Instance, error) {
 	modelFolderPath := path.Join(
-		controllerFolderName("*"),
+		env.controllerFolderPath("*"),
 		env.modelFolderName(),
 	)
 	vms, err := env.client.VirtualMachines(env.ctx, modelFolderPath+"/*")
@@ -294,7 +348,7 @@ func (env *environ) StopInstances(ctx context.ProviderCallContext, ids ...instan
 // StopInstances implements environs.InstanceBroker.
 func (env *sessionEnviron) StopInstances(ctx context.ProviderCallContext, ids ...instance.Id) error {
 	modelFolderPath := path.Join(
-		controllerFolderName("*"),
+		env.controllerFolderPath("*"),
 		env.modelFolderName(),
 	)
 	results := make([]error, len(ids))
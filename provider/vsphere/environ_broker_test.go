@@ -348,6 +348,115 @@ func (s *environBrokerSuite) TestStartInstanceDatastore(c *gc.C) {
 	c.Assert(createVMArgs.Datastore, gc.Equals, "datastore0")
 }
 
+func (s *environBrokerSuite) TestStartInstanceRootDiskSource(c *gc.C) {
+	cfg := s.env.Config()
+	cfg, err := cfg.Apply(map[string]interface{}{
+		"datastore": "datastore0",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.env.SetConfig(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+
+	startInstArgs := s.createStartInstanceArgs(c)
+	startInstArgs.Constraints = constraints.MustParse("root-disk-source=datastore1")
+	_, err = s.env.StartInstance(s.callCtx, startInstArgs)
+	c.Assert(err, jc.ErrorIsNil)
+
+	call := s.client.Calls()[1]
+	createVMArgs := call.Args[1].(vsphereclient.CreateVirtualMachineParams)
+	c.Assert(createVMArgs.Datastore, gc.Equals, "datastore1")
+}
+
+func (s *environBrokerSuite) TestStartInstanceFolderAndResourcePool(c *gc.C) {
+	env, err := s.provider.Open(environs.OpenParams{
+		Cloud: fakeCloudSpec(),
+		Config: fakeConfig(c, coretesting.Attrs{
+			"vm-folder":          "/juju/",
+			"resource-pool":      "dev/juju",
+			"image-metadata-url": s.imageServer.URL,
+		}),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = env.StartInstance(s.callCtx, s.createStartInstanceArgs(c))
+	c.Assert(err, jc.ErrorIsNil)
+
+	call := s.client.Calls()[1]
+	createVMArgs := call.Args[1].(vsphereclient.CreateVirtualMachineParams)
+	c.Assert(createVMArgs.Folder, gc.Equals,
+		`juju/Juju Controller (deadbeef-1bad-500d-9000-4b1d0d06f00d)/Model "testmodel" (2d02eeac-9dbb-11e4-89d3-123b93f75cba)`,
+	)
+	c.Assert(createVMArgs.ResourcePool, gc.Equals, "dev/juju")
+}
+
+func (s *environBrokerSuite) TestStartInstanceFromTemplate(c *gc.C) {
+	env, err := s.provider.Open(environs.OpenParams{
+		Cloud: fakeCloudSpec(),
+		Config: fakeConfig(c, coretesting.Attrs{
+			"vm-templates":  "xenial=templates/ubuntu-16.04 trusty/amd64=templates/ubuntu-14.04",
+			"linked-clones": true,
+			// There is no image metadata, so the instance
+			// can only be created from the template.
+			"image-metadata-url": "http://invalid.example.com",
+		}),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.client.createdVirtualMachine = buildVM("new-vm").disk(20 * 1024 * 1024).vm()
+
+	res, err := env.StartInstance(s.callCtx, s.createStartInstanceArgs(c))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*res.Hardware.Arch, gc.Equals, arch.AMD64)
+	// The root disk of a linked clone is the size of the template's.
+	c.Assert(*res.Hardware.RootDisk, gc.Equals, uint64(20*1024))
+
+	call := s.client.Calls()[1]
+	createVMArgs := call.Args[1].(vsphereclient.CreateVirtualMachineParams)
+	c.Assert(createVMArgs.Template, gc.Equals, "templates/ubuntu-14.04")
+	c.Assert(createVMArgs.LinkedClone, jc.IsTrue)
+	c.Assert(createVMArgs.Constraints.RootDisk, gc.IsNil)
+	c.Assert(createVMArgs.ReadOVA, gc.IsNil)
+	c.Assert(createVMArgs.OVASHA256, gc.Equals, "")
+}
+
+func (s *environBrokerSuite) TestStartInstanceLinkedCloneRootDisk(c *gc.C) {
+	env, err := s.provider.Open(environs.OpenParams{
+		Cloud: fakeCloudSpec(),
+		Config: fakeConfig(c, coretesting.Attrs{
+			"vm-templates":  "trusty=templates/ubuntu-14.04",
+			"linked-clones": true,
+		}),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	startInstArgs := s.createStartInstanceArgs(c)
+	rootDisk := uint64(20 * 1024)
+	startInstArgs.Constraints.RootDisk = &rootDisk
+	_, err = env.StartInstance(s.callCtx, startInstArgs)
+	c.Assert(err, gc.ErrorMatches, "root-disk constraint with linked-clones not supported")
+	c.Assert(err, jc.Satisfies, environs.IsAvailabilityZoneIndependent)
+	s.client.CheckCallNames(c, "Close")
+}
+
+func (s *environBrokerSuite) TestStartInstanceNoTemplateForSeries(c *gc.C) {
+	env, err := s.provider.Open(environs.OpenParams{
+		Cloud: fakeCloudSpec(),
+		Config: fakeConfig(c, coretesting.Attrs{
+			"vm-templates":       "xenial=templates/ubuntu-16.04",
+			"image-metadata-url": s.imageServer.URL,
+		}),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = env.StartInstance(s.callCtx, s.createStartInstanceArgs(c))
+	c.Assert(err, jc.ErrorIsNil)
+
+	call := s.client.Calls()[1]
+	createVMArgs := call.Args[1].(vsphereclient.CreateVirtualMachineParams)
+	c.Assert(createVMArgs.Template, gc.Equals, "")
+	c.Assert(createVMArgs.ReadOVA, gc.NotNil)
+}
+
 func (s *environBrokerSuite) TestStopInstances(c *gc.C) {
 	err := s.env.StopInstances(s.callCtx, "vm-0", "vm-1")
 	c.Assert(err, jc.ErrorIsNil)
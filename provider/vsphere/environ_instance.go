@@ -4,6 +4,7 @@
 package vsphere
 
 import (
+	"path"
 	"strings"
 
 	"github.com/juju/errors"
@@ -99,6 +100,43 @@ func (env *sessionEnviron) ControllerInstances(ctx context.ProviderCallContext,
 	return results, nil
 }
 
+// TagInstance implements environs.InstanceTagger.
+func (env *environ) TagInstance(ctx context.ProviderCallContext, id instance.Id, instanceTags map[string]string) error {
+	return env.withSession(ctx, func(env *sessionEnviron) error {
+		return env.TagInstance(ctx, id, instanceTags)
+	})
+}
+
+// TagInstance implements environs.InstanceTagger. The tags are
+// stored in the VM's ExtraConfig, like those set at creation.
+func (env *sessionEnviron) TagInstance(ctx context.ProviderCallContext, id instance.Id, instanceTags map[string]string) error {
+	instances, err := env.Instances(ctx, []instance.Id{id})
+	if err == environs.ErrNoInstances {
+		return errors.NotFoundf("instance %q", id)
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	vm := instances[0].(*environInstance).base
+	existing := make(map[string]string)
+	for _, item := range vm.Config.ExtraConfig {
+		value := item.GetOptionValue()
+		existing[value.Key], _ = value.Value.(string)
+	}
+	metadata := make(map[string]string)
+	for k, v := range instanceTags {
+		metadata[k] = v
+	}
+	// Setting an ExtraConfig option to the empty string removes it.
+	for _, k := range tags.RemovedTags(existing, instanceTags) {
+		metadata[k] = ""
+	}
+	if err := env.client.UpdateVirtualMachineExtraConfig(env.ctx, vm, metadata); err != nil {
+		HandleCredentialError(err, ctx)
+		return errors.Annotatef(err, "tagging instance %q", id)
+	}
+	return nil
+}
+
 // parsePlacement extracts the availability zone from the placement
 // string and returns it. If no zone is found there then an error is
 // returned.
@@ -123,6 +161,12 @@ func (env *sessionEnviron) parsePlacement(ctx context.ProviderCallContext, place
 	return nil, errors.Errorf("unknown placement directive: %v", placement)
 }
 
+// controllerFolderPath returns the path of the controller's VM folder,
+// relative to the root VM folder.
+func (env *sessionEnviron) controllerFolderPath(controllerUUID string) string {
+	return path.Join(env.ecfg.vmFolder(), controllerFolderName(controllerUUID))
+}
+
 func (env *sessionEnviron) modelFolderName() string {
 	cfg := env.Config()
 	return modelFolderName(cfg.UUID(), cfg.Name())
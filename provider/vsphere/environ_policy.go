@@ -30,6 +30,9 @@ func (env *sessionEnviron) PrecheckInstance(ctx context.ProviderCallContext, arg
 var unsupportedConstraints = []string{
 	constraints.Tags,
 	constraints.VirtType,
+	constraints.Spot,
+	constraints.SpotMaxPrice,
+	constraints.AntiAffinity,
 }
 
 // ConstraintsValidator returns a Validator value which is used to
@@ -37,6 +40,7 @@ var unsupportedConstraints = []string{
 func (env *environ) ConstraintsValidator(ctx context.ProviderCallContext) (constraints.Validator, error) {
 	validator := constraints.NewValidator()
 	validator.RegisterUnsupported(unsupportedConstraints)
+	validator.RegisterRejected([]string{constraints.InstanceRole})
 	validator.RegisterVocabulary(constraints.Arch, []string{
 		arch.AMD64, arch.I386,
 	})
@@ -148,6 +148,60 @@ func (s *environSuite) TestAdoptResources(c *gc.C) {
 	)
 }
 
+func (s *environSuite) TestTagInstance(c *gc.C) {
+	vm := buildVM("juju-f75cba-0").vm()
+	s.client.virtualMachines = []*mo.VirtualMachine{vm}
+
+	c.Assert(s.env, gc.Implements, new(environs.InstanceTagger))
+	err := s.env.(environs.InstanceTagger).TagInstance(s.callCtx, "juju-f75cba-0", map[string]string{
+		"juju-units-deployed": "mysql/0",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.client.CheckCallNames(c, "VirtualMachines", "UpdateVirtualMachineExtraConfig", "Close")
+	updateCall := s.client.Calls()[1]
+	c.Assert(updateCall.Args[1], gc.Equals, vm)
+	c.Assert(updateCall.Args[2], jc.DeepEquals, map[string]string{
+		"juju-units-deployed": "mysql/0",
+	})
+}
+
+func (s *environSuite) TestTagInstanceRemovesTags(c *gc.C) {
+	vm := buildVM("juju-f75cba-0").
+		extraConfig("juju-units-deployed", "mysql/0").
+		extraConfig("juju-managed-tags", "owner").
+		extraConfig("owner", "mysql").
+		extraConfig("guestinfo.foo", "bar").
+		vm()
+	s.client.virtualMachines = []*mo.VirtualMachine{vm}
+
+	err := s.env.(environs.InstanceTagger).TagInstance(s.callCtx, "juju-f75cba-0", map[string]string{
+		"juju-model-uuid": "model-uuid",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.client.CheckCallNames(c, "VirtualMachines", "UpdateVirtualMachineExtraConfig", "Close")
+	updateCall := s.client.Calls()[1]
+	c.Assert(updateCall.Args[2], jc.DeepEquals, map[string]string{
+		"juju-model-uuid":     "model-uuid",
+		"juju-units-deployed": "",
+		"juju-managed-tags":   "",
+		"owner":               "",
+	})
+}
+
+func (s *environSuite) TestTagInstanceNotFound(c *gc.C) {
+	err := s.env.(environs.InstanceTagger).TagInstance(s.callCtx, "juju-f75cba-0", nil)
+	c.Assert(err, gc.ErrorMatches, `instance "juju-f75cba-0" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *environSuite) TestTagInstancePermissionError(c *gc.C) {
+	AssertInvalidatesCredential(c, s.client, func(ctx environscontext.ProviderCallContext) error {
+		return s.env.(environs.InstanceTagger).TagInstance(ctx, "juju-f75cba-0", nil)
+	})
+}
+
 func (s *environSuite) TestPrepareForBootstrap(c *gc.C) {
 	err := s.env.PrepareForBootstrap(envtesting.BootstrapContext(c))
 	c.Check(err, jc.ErrorIsNil)
@@ -18,6 +18,7 @@ import (
 	"github.com/juju/clock"
 	"github.com/juju/errors"
 	"github.com/kr/pretty"
+	"github.com/vmware/govmomi/find"
 	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/ovf"
 	"github.com/vmware/govmomi/vim25/mo"
@@ -71,10 +72,27 @@ type CreateVirtualMachineParams struct {
 	// UserData is the cloud-init user-data.
 	UserData string
 
+	// Template is the path of a VM template, relative to the root VM
+	// folder, to clone the VM from. If this is empty, the VM is created
+	// from the OVA returned by ReadOVA.
+	Template string
+
+	// LinkedClone controls whether a VM cloned from Template should be
+	// a linked clone, sharing the disks of the template's current
+	// snapshot, rather than a full copy of the template. The disks of
+	// a linked clone cannot be extended, so Constraints.RootDisk must
+	// not be specified.
+	LinkedClone bool
+
 	// ComputeResource is the compute resource (host or cluster) to be used
 	// to create the VM.
 	ComputeResource *mo.ComputeResource
 
+	// ResourcePool is the path of the resource pool in which to create
+	// the VM, relative to the compute resource's root resource pool.
+	// If this is empty, the root resource pool will be used.
+	ResourcePool string
+
 	// Datastore is the name of the datastore in which to create the VM.
 	// If this is empty, any accessible datastore will be used.
 	Datastore string
@@ -108,8 +126,11 @@ type CreateVirtualMachineParams struct {
 
 // CreateVirtualMachine creates and powers on a new VM.
 //
-// This method imports an OVF template using the vSphere API. This process
-// comprises the following steps:
+// If args.Template is specified, the VM is cloned from that template in
+// place of steps 1 to 4 below.
+//
+// Otherwise, this method imports an OVF template using the vSphere API.
+// This process comprises the following steps:
 //   1. Ensure the VMDK contained within the OVA archive (args.OVA) is
 //      stored in the datastore, in this controller's cache. If it is
 //      there already, we use it; otherwise we remove any existing VMDK
@@ -159,10 +180,36 @@ func (c *Client) CreateVirtualMachine(
 	datastore.DatacenterPath = datacenter.InventoryPath
 	datastore.SetInventoryPath(path.Join(folders.DatastoreFolder.InventoryPath, datastoreMo.Name))
 
+	resourcePool, err := c.resourcePool(ctx, args.ComputeResource, args.ResourcePool)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	taskWaiter := &taskWaiter{args.Clock, args.UpdateProgress, args.UpdateProgressInterval}
+
+	if args.Template != "" {
+		if args.LinkedClone && args.Constraints.RootDisk != nil {
+			// The disks of a linked clone are backed by the
+			// template's snapshot, and cannot be extended.
+			return nil, errors.NotSupportedf("root-disk constraint with linked clones")
+		}
+		templatePath := path.Join(folders.VmFolder.InventoryPath, args.Template)
+		vm, err := c.cloneTemplate(ctx, args, finder, templatePath, vmFolder, datastore, resourcePool, taskWaiter)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		defer func() {
+			if resultErr == nil {
+				return
+			}
+			if err := c.destroyVM(ctx, vm, taskWaiter); err != nil {
+				c.logger.Warningf("failed to delete VM: %s", err)
+			}
+		}()
+		return c.startVM(ctx, args, vm, datacenter, taskWaiter)
+	}
+
 	// Ensure the VMDK is present in the datastore, uploading it if it
 	// doesn't already exist.
-	resourcePool := object.NewResourcePool(c.client.Client, *args.ComputeResource.ResourcePool)
-	taskWaiter := &taskWaiter{args.Clock, args.UpdateProgress, args.UpdateProgressInterval}
 	vmdkDatastorePath, releaseVMDK, err := c.ensureVMDK(ctx, args, datastore, datacenter, taskWaiter)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -173,7 +220,7 @@ func (c *Client) CreateVirtualMachine(
 	// import the VMDK, which exists in the datastore as a not-a-disk
 	// file type.
 	args.UpdateProgress("creating import spec")
-	importSpec, err := c.createImportSpec(ctx, args, datastore, vmdkDatastorePath)
+	importSpec, err := c.createImportSpec(ctx, args, datastore, resourcePool, vmdkDatastorePath)
 	if err != nil {
 		return nil, errors.Annotate(err, "creating import spec")
 	}
@@ -221,6 +268,18 @@ func (c *Client) CreateVirtualMachine(
 	if _, err := c.detachDisk(ctx, tempVM, taskWaiter); err != nil {
 		return nil, errors.Trace(err)
 	}
+	return c.startVM(ctx, args, vm, datacenter, taskWaiter)
+}
+
+// startVM extends the root disk of the newly created VM to satisfy the
+// root-disk constraint, if any, and then powers it on.
+func (c *Client) startVM(
+	ctx context.Context,
+	args CreateVirtualMachineParams,
+	vm *object.VirtualMachine,
+	datacenter *object.Datacenter,
+	taskWaiter *taskWaiter,
+) (*mo.VirtualMachine, error) {
 	if args.Constraints.RootDisk != nil {
 		// The user specified a root disk, so extend the VM's
 		// disk before powering the VM on.
@@ -254,6 +313,130 @@ func (c *Client) CreateVirtualMachine(
 	return &res, nil
 }
 
+// cloneTemplate clones the VM template at the given inventory path to
+// create the VM we will associate with the Juju machine.
+func (c *Client) cloneTemplate(
+	ctx context.Context,
+	args CreateVirtualMachineParams,
+	finder *find.Finder,
+	templatePath string,
+	vmFolder *object.Folder,
+	datastore *object.Datastore,
+	resourcePool *object.ResourcePool,
+	taskWaiter *taskWaiter,
+) (*object.VirtualMachine, error) {
+	template, err := finder.VirtualMachine(ctx, templatePath)
+	if err != nil {
+		return nil, errors.Annotatef(err, "finding VM template %q", args.Template)
+	}
+	var templateMo mo.VirtualMachine
+	if err := c.client.RetrieveOne(
+		ctx, template.Reference(),
+		[]string{"config.hardware", "config.vAppConfig", "snapshot"},
+		&templateMo,
+	); err != nil {
+		return nil, errors.Annotatef(err, "retrieving VM template %q details", args.Template)
+	}
+	spec, err := c.createCloneSpec(ctx, args, &templateMo, datastore, resourcePool)
+	if err != nil {
+		return nil, errors.Annotate(err, "creating clone spec")
+	}
+
+	args.UpdateProgress(fmt.Sprintf("cloning template %q", args.Template))
+	c.logger.Debugf("cloning VM template %s into folder %s", templatePath, vmFolder)
+	c.logger.Tracef("clone spec: %s", pretty.Sprint(spec))
+	task, err := template.Clone(ctx, vmFolder, args.Name, *spec)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	info, err := taskWaiter.waitTask(ctx, task, "cloning VM template")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	args.UpdateProgress("VM cloned")
+	return object.NewVirtualMachine(c.client.Client, info.Result.(types.ManagedObjectReference)), nil
+}
+
+func (c *Client) createCloneSpec(
+	ctx context.Context,
+	args CreateVirtualMachineParams,
+	template *mo.VirtualMachine,
+	datastore *object.Datastore,
+	resourcePool *object.ResourcePool,
+) (*types.VirtualMachineCloneSpec, error) {
+	datastoreRef := datastore.Reference()
+	resourcePoolRef := resourcePool.Reference()
+	spec := &types.VirtualMachineCloneSpec{
+		Config: &types.VirtualMachineConfigSpec{},
+		Location: types.VirtualMachineRelocateSpec{
+			Datastore: &datastoreRef,
+			Pool:      &resourcePoolRef,
+		},
+	}
+	if args.LinkedClone {
+		if template.Snapshot == nil || template.Snapshot.CurrentSnapshot == nil {
+			return nil, errors.Errorf(
+				"cannot create linked clone: VM template %q has no snapshot",
+				args.Template,
+			)
+		}
+		spec.Snapshot = template.Snapshot.CurrentSnapshot
+		spec.Location.DiskMoveType = string(
+			types.VirtualMachineRelocateDiskMoveOptionsCreateNewChildDiskBacking,
+		)
+	}
+
+	s := spec.Config
+	applyConstraints(s, args)
+	applyMetadata(s, args)
+
+	// The Ubuntu cloud images take the cloud-init user-data and the
+	// hostname from vApp properties, which the template will have
+	// inherited from the OVF it was created from.
+	if template.Config == nil || template.Config.VAppConfig == nil {
+		return nil, errors.Errorf("VM template %q has no vApp properties", args.Template)
+	}
+	var properties []types.VAppPropertySpec
+	var foundUserData bool
+	for _, property := range template.Config.VAppConfig.GetVmConfigInfo().Property {
+		switch property.Id {
+		case "user-data":
+			property.Value = args.UserData
+			foundUserData = true
+		case "hostname":
+			property.Value = args.Name
+		default:
+			continue
+		}
+		info := property
+		properties = append(properties, types.VAppPropertySpec{
+			ArrayUpdateSpec: types.ArrayUpdateSpec{
+				Operation: types.ArrayUpdateOperationEdit,
+			},
+			Info: &info,
+		})
+	}
+	if !foundUserData {
+		return nil, errors.Errorf("VM template %q has no user-data vApp property", args.Template)
+	}
+	s.VAppConfig = &types.VmConfigSpec{Property: properties}
+
+	// Replace the template's network devices with those requested.
+	for _, dev := range template.Config.Hardware.Device {
+		if _, ok := dev.(types.BaseVirtualEthernetCard); !ok {
+			continue
+		}
+		s.DeviceChange = append(s.DeviceChange, &types.VirtualDeviceConfigSpec{
+			Operation: types.VirtualDeviceConfigSpecOperationRemove,
+			Device:    dev,
+		})
+	}
+	if err := c.addNetworkDevices(ctx, s, args); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return spec, nil
+}
+
 func (c *Client) extendVMRootDisk(
 	ctx context.Context,
 	vm *object.VirtualMachine,
@@ -292,6 +475,7 @@ func (c *Client) createImportSpec(
 	ctx context.Context,
 	args CreateVirtualMachineParams,
 	datastore *object.Datastore,
+	resourcePool *object.ResourcePool,
 	vmdkDatastorePath string,
 ) (*types.VirtualMachineImportSpec, error) {
 	cisp := types.OvfCreateImportSpecParams{
@@ -303,8 +487,6 @@ func (c *Client) createImportSpec(
 	}
 
 	ovfManager := ovf.NewManager(c.client.Client)
-	resourcePool := object.NewReference(c.client.Client, *args.ComputeResource.ResourcePool)
-
 	spec, err := ovfManager.CreateImportSpec(ctx, UbuntuOVF, resourcePool, datastore, cisp)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -313,8 +495,20 @@ func (c *Client) createImportSpec(
 	}
 	importSpec := spec.ImportSpec.(*types.VirtualMachineImportSpec)
 	s := &spec.ImportSpec.(*types.VirtualMachineImportSpec).ConfigSpec
+	applyConstraints(s, args)
+	if err := c.addRootDisk(s, args, datastore, vmdkDatastorePath); err != nil {
+		return nil, errors.Trace(err)
+	}
+	applyMetadata(s, args)
+	if err := c.addNetworkDevices(ctx, s, args); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return importSpec, nil
+}
 
-	// Apply resource constraints.
+// applyConstraints applies the resource constraints, and the disk
+// UUID flag, to the VM config spec.
+func applyConstraints(s *types.VirtualMachineConfigSpec, args CreateVirtualMachineParams) {
 	if args.Constraints.HasCpuCores() {
 		s.NumCPUs = int32(*args.Constraints.CpuCores)
 	}
@@ -332,20 +526,29 @@ func (c *Client) createImportSpec(
 		s.Flags = &types.VirtualMachineFlagInfo{}
 	}
 	s.Flags.DiskUuidEnabled = &args.EnableDiskUUID
-	if err := c.addRootDisk(s, args, datastore, vmdkDatastorePath); err != nil {
-		return nil, errors.Trace(err)
-	}
+}
 
-	// Apply metadata. Note that we do not have the ability set create or
-	// apply tags that will show up in vCenter, as that requires a separate
-	// vSphere Automation that we do not have an SDK for.
+// applyMetadata applies the metadata to the VM config spec. Note that
+// we do not have the ability set create or apply tags that will show
+// up in vCenter, as that requires a separate vSphere Automation that
+// we do not have an SDK for.
+func applyMetadata(s *types.VirtualMachineConfigSpec, args CreateVirtualMachineParams) {
 	for k, v := range args.Metadata {
 		s.ExtraConfig = append(s.ExtraConfig, &types.OptionValue{Key: k, Value: v})
 	}
+}
 
+// addNetworkDevices adds entries to the VirtualMachineConfigSpec's
+// DeviceChange list to create a NIC for each of the requested network
+// devices.
+func (c *Client) addNetworkDevices(
+	ctx context.Context,
+	s *types.VirtualMachineConfigSpec,
+	args CreateVirtualMachineParams,
+) error {
 	networks, dvportgroupConfig, err := c.computeResourceNetworks(ctx, args.ComputeResource)
 	if err != nil {
-		return nil, errors.Trace(err)
+		return errors.Trace(err)
 	}
 
 	for i, networkDevice := range args.NetworkDevices {
@@ -356,15 +559,15 @@ func (c *Client) createImportSpec(
 
 		networkReference, err := findNetwork(networks, network)
 		if err != nil {
-			return nil, errors.Trace(err)
+			return errors.Trace(err)
 		}
 		device, err := c.addNetworkDevice(ctx, s, networkReference, networkDevice.MAC, dvportgroupConfig)
 		if err != nil {
-			return nil, errors.Annotatef(err, "adding network device %d - network %s", i, network)
+			return errors.Annotatef(err, "adding network device %d - network %s", i, network)
 		}
 		c.logger.Debugf("network device: %+v", device)
 	}
-	return importSpec, nil
+	return nil
 }
 
 func (c *Client) addRootDisk(
@@ -401,6 +604,44 @@ func (c *Client) addRootDisk(
 	return nil
 }
 
+// resourcePool returns the resource pool with the given path, relative
+// to the compute resource's root resource pool.
+func (c *Client) resourcePool(
+	ctx context.Context,
+	computeResource *mo.ComputeResource,
+	poolPath string,
+) (*object.ResourcePool, error) {
+	ref := *computeResource.ResourcePool
+	if poolPath == "" {
+		return object.NewResourcePool(c.client.Client, ref), nil
+	}
+	for _, name := range strings.Split(poolPath, "/") {
+		var pool mo.ResourcePool
+		if err := c.client.RetrieveOne(ctx, ref, []string{"resourcePool"}, &pool); err != nil {
+			return nil, errors.Annotate(err, "retrieving resource pool details")
+		}
+		var children []mo.ResourcePool
+		if len(pool.ResourcePool) > 0 {
+			if err := c.client.Retrieve(ctx, pool.ResourcePool, []string{"name"}, &children); err != nil {
+				return nil, errors.Annotate(err, "retrieving resource pool details")
+			}
+		}
+		var found bool
+		for _, child := range children {
+			if child.Name == name {
+				ref = child.Reference()
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, errors.NotFoundf("resource pool %q", poolPath)
+		}
+	}
+	c.logger.Debugf("using resource pool %q", poolPath)
+	return object.NewResourcePool(c.client.Client, ref), nil
+}
+
 func (c *Client) selectDatastore(
 	ctx context.Context,
 	args CreateVirtualMachineParams,
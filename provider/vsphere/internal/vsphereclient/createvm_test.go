@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/juju/clock/testclock"
+	"github.com/juju/errors"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	"github.com/vmware/govmomi/vim25/mo"
@@ -142,7 +143,9 @@ func (s *clientSuite) TestCreateVirtualMachine(c *gc.C) {
 
 		{"HttpNfcLeaseComplete", []interface{}{"FakeLease"}},
 
-		{"CloneVM_Task", nil},
+		{"CloneVM_Task", []interface{}{"vm-0", types.VirtualMachineCloneSpec{
+			Config: &types.VirtualMachineConfigSpec{},
+		}}},
 		{"CreatePropertyCollector", nil},
 		{"CreateFilter", nil},
 		{"WaitForUpdatesEx", nil},
@@ -407,6 +410,120 @@ func (s *clientSuite) TestCreateVirtualMachineRootDiskSize(c *gc.C) {
 	})
 }
 
+func (s *clientSuite) TestCreateVirtualMachineResourcePool(c *gc.C) {
+	s.addResourcePools()
+	args := baseCreateVirtualMachineParams(c)
+	args.ResourcePool = "parent/child"
+
+	client := s.newFakeClient(&s.roundTripper, "dc0")
+	_, err := client.CreateVirtualMachine(context.Background(), args)
+	c.Assert(err, jc.ErrorIsNil)
+
+	calls := s.roundTripper.Calls()
+	c.Assert(calls[8:12], jc.DeepEquals, []testing.StubCall{
+		retrievePropertiesStubCall("FakeResourcePool1"),
+		retrievePropertiesStubCall("FakeResourcePool3"),
+		retrievePropertiesStubCall("FakeResourcePool3"),
+		retrievePropertiesStubCall("FakeResourcePool4"),
+	})
+}
+
+func (s *clientSuite) TestCreateVirtualMachineResourcePoolNotFound(c *gc.C) {
+	s.addResourcePools()
+	args := baseCreateVirtualMachineParams(c)
+	args.ResourcePool = "parent/orphan"
+
+	client := s.newFakeClient(&s.roundTripper, "dc0")
+	_, err := client.CreateVirtualMachine(context.Background(), args)
+	c.Assert(err, gc.ErrorMatches, `resource pool "parent/orphan" not found`)
+}
+
+func (s *clientSuite) TestCreateVirtualMachineFromTemplate(c *gc.C) {
+	s.addTemplate(true)
+	var statusUpdates []string
+	args := baseCreateVirtualMachineParams(c)
+	args.Template = "ubuntu-bionic"
+	args.UpdateProgress = func(status string) {
+		statusUpdates = append(statusUpdates, status)
+	}
+
+	client := s.newFakeClient(&s.roundTripper, "dc0")
+	_, err := client.CreateVirtualMachine(context.Background(), args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(statusUpdates, jc.DeepEquals, []string{
+		`cloning template "ubuntu-bionic"`,
+		"VM cloned",
+		"powering on",
+	})
+
+	// Nothing is uploaded or imported when cloning a template.
+	calls := s.roundTripper.Calls()
+	assertNoCall(c, calls, "SearchDatastore")
+	assertNoCall(c, calls, "ImportVApp")
+	c.Assert(s.uploadRequests, gc.HasLen, 0)
+
+	call := findStubCall(c, calls, "CloneVM_Task")
+	c.Assert(call.Args, jc.DeepEquals, []interface{}{"vm-0", baseTemplateCloneSpec()})
+}
+
+func (s *clientSuite) TestCreateVirtualMachineFromTemplateLinkedClone(c *gc.C) {
+	s.addTemplate(true)
+	args := baseCreateVirtualMachineParams(c)
+	args.Template = "ubuntu-bionic"
+	args.LinkedClone = true
+
+	client := s.newFakeClient(&s.roundTripper, "dc0")
+	_, err := client.CreateVirtualMachine(context.Background(), args)
+	c.Assert(err, jc.ErrorIsNil)
+
+	expected := baseTemplateCloneSpec()
+	expected.Snapshot = &types.ManagedObjectReference{
+		Type:  "VirtualMachineSnapshot",
+		Value: "FakeSnapshot",
+	}
+	expected.Location.DiskMoveType = "createNewChildDiskBacking"
+	calls := s.roundTripper.Calls()
+	call := findStubCall(c, calls, "CloneVM_Task")
+	c.Assert(call.Args, jc.DeepEquals, []interface{}{"vm-0", expected})
+	assertNoCall(c, calls, "ExtendVirtualDisk")
+}
+
+func (s *clientSuite) TestCreateVirtualMachineFromTemplateLinkedCloneRootDisk(c *gc.C) {
+	s.addTemplate(true)
+	args := baseCreateVirtualMachineParams(c)
+	args.Template = "ubuntu-bionic"
+	args.LinkedClone = true
+	rootDisk := uint64(1024 * 20) // 20 GiB
+	args.Constraints.RootDisk = &rootDisk
+
+	client := s.newFakeClient(&s.roundTripper, "dc0")
+	_, err := client.CreateVirtualMachine(context.Background(), args)
+	c.Assert(err, gc.ErrorMatches, "root-disk constraint with linked clones not supported")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	assertNoCall(c, s.roundTripper.Calls(), "CloneVM_Task")
+}
+
+func (s *clientSuite) TestCreateVirtualMachineFromTemplateNoSnapshot(c *gc.C) {
+	s.addTemplate(false)
+	args := baseCreateVirtualMachineParams(c)
+	args.Template = "ubuntu-bionic"
+	args.LinkedClone = true
+
+	client := s.newFakeClient(&s.roundTripper, "dc0")
+	_, err := client.CreateVirtualMachine(context.Background(), args)
+	c.Assert(err, gc.ErrorMatches, `creating clone spec: cannot create linked clone: VM template "ubuntu-bionic" has no snapshot`)
+	assertNoCall(c, s.roundTripper.Calls(), "CloneVM_Task")
+}
+
+func (s *clientSuite) TestCreateVirtualMachineTemplateNotFound(c *gc.C) {
+	args := baseCreateVirtualMachineParams(c)
+	args.Template = "ubuntu-cosmic"
+
+	client := s.newFakeClient(&s.roundTripper, "dc0")
+	_, err := client.CreateVirtualMachine(context.Background(), args)
+	c.Assert(err, gc.ErrorMatches, `finding VM template "ubuntu-cosmic": .* not found`)
+}
+
 func (s *clientSuite) TestVerifyMAC(c *gc.C) {
 	var testData = []struct {
 		Mac    string
@@ -477,6 +594,140 @@ func baseCreateVirtualMachineParams(c *gc.C) CreateVirtualMachineParams {
 	}
 }
 
+// addResourcePools adds the resource pool "parent/child" to the
+// root resource pool of compute resource z0.
+func (s *clientSuite) addResourcePools() {
+	s.roundTripper.contents["FakeResourcePool1"] = []types.ObjectContent{{
+		Obj: types.ManagedObjectReference{
+			Type:  "ResourcePool",
+			Value: "FakeResourcePool1",
+		},
+		PropSet: []types.DynamicProperty{
+			{Name: "resourcePool", Val: []types.ManagedObjectReference{{
+				Type:  "ResourcePool",
+				Value: "FakeResourcePool3",
+			}}},
+		},
+	}}
+	s.roundTripper.contents["FakeResourcePool3"] = []types.ObjectContent{{
+		Obj: types.ManagedObjectReference{
+			Type:  "ResourcePool",
+			Value: "FakeResourcePool3",
+		},
+		PropSet: []types.DynamicProperty{
+			{Name: "name", Val: "parent"},
+			{Name: "resourcePool", Val: []types.ManagedObjectReference{{
+				Type:  "ResourcePool",
+				Value: "FakeResourcePool4",
+			}}},
+		},
+	}}
+	s.roundTripper.contents["FakeResourcePool4"] = []types.ObjectContent{{
+		Obj: types.ManagedObjectReference{
+			Type:  "ResourcePool",
+			Value: "FakeResourcePool4",
+		},
+		PropSet: []types.DynamicProperty{
+			{Name: "name", Val: "child"},
+		},
+	}}
+}
+
+// addTemplate adds the VM template "ubuntu-bionic" to the root VM
+// folder, with or without a snapshot.
+func (s *clientSuite) addTemplate(withSnapshot bool) {
+	template := types.ManagedObjectReference{
+		Type:  "VirtualMachine",
+		Value: "FakeTemplate",
+	}
+	s.roundTripper.contents["FakeVmFolder"] = append(
+		s.roundTripper.contents["FakeVmFolder"],
+		types.ObjectContent{
+			Obj: template,
+			PropSet: []types.DynamicProperty{
+				{Name: "name", Val: "ubuntu-bionic"},
+			},
+		},
+	)
+	propSet := []types.DynamicProperty{
+		{Name: "name", Val: "ubuntu-bionic"},
+		{Name: "config.hardware", Val: types.VirtualHardware{
+			Device: []types.BaseVirtualDevice{
+				&types.VirtualDisk{
+					VirtualDevice: types.VirtualDevice{Key: 2000},
+				},
+				&types.VirtualVmxnet3{
+					VirtualEthernetCard: types.VirtualEthernetCard{
+						VirtualDevice: types.VirtualDevice{Key: 4000},
+					},
+				},
+			},
+		}},
+		{Name: "config.vAppConfig", Val: &types.VmConfigInfo{
+			Property: []types.VAppPropertyInfo{
+				{Key: 1, Id: "user-data"},
+				{Key: 2, Id: "hostname"},
+				{Key: 3, Id: "password"},
+			},
+		}},
+	}
+	if withSnapshot {
+		propSet = append(propSet, types.DynamicProperty{
+			Name: "snapshot",
+			Val: types.VirtualMachineSnapshotInfo{
+				CurrentSnapshot: &types.ManagedObjectReference{
+					Type:  "VirtualMachineSnapshot",
+					Value: "FakeSnapshot",
+				},
+			},
+		})
+	}
+	s.roundTripper.contents["FakeTemplate"] = []types.ObjectContent{{
+		Obj:     template,
+		PropSet: propSet,
+	}}
+}
+
+func baseTemplateCloneSpec() types.VirtualMachineCloneSpec {
+	return types.VirtualMachineCloneSpec{
+		Config: &types.VirtualMachineConfigSpec{
+			ExtraConfig: []types.BaseOptionValue{
+				&types.OptionValue{Key: "k", Value: "v"},
+			},
+			Flags: &types.VirtualMachineFlagInfo{DiskUuidEnabled: newBool(true)},
+			VAppConfig: &types.VmConfigSpec{
+				Property: []types.VAppPropertySpec{{
+					ArrayUpdateSpec: types.ArrayUpdateSpec{Operation: "edit"},
+					Info:            &types.VAppPropertyInfo{Key: 1, Id: "user-data", Value: "baz"},
+				}, {
+					ArrayUpdateSpec: types.ArrayUpdateSpec{Operation: "edit"},
+					Info:            &types.VAppPropertyInfo{Key: 2, Id: "hostname", Value: "vm-0"},
+				}},
+			},
+			DeviceChange: []types.BaseVirtualDeviceConfigSpec{
+				&types.VirtualDeviceConfigSpec{
+					Operation: "remove",
+					Device: &types.VirtualVmxnet3{
+						VirtualEthernetCard: types.VirtualEthernetCard{
+							VirtualDevice: types.VirtualDevice{Key: 4000},
+						},
+					},
+				},
+			},
+		},
+		Location: types.VirtualMachineRelocateSpec{
+			Datastore: &types.ManagedObjectReference{
+				Type:  "Datastore",
+				Value: "FakeDatastore2",
+			},
+			Pool: &types.ManagedObjectReference{
+				Type:  "ResourcePool",
+				Value: "FakeResourcePool1",
+			},
+		},
+	}
+}
+
 func baseCisp() types.OvfCreateImportSpecParams {
 	return types.OvfCreateImportSpecParams{
 		EntityName: "vm-0",
@@ -108,7 +108,8 @@ func (r *mockRoundTripper) RoundTrip(ctx context.Context, req, res soap.HasFault
 		r.MethodCall(r, "PowerOnVM_Task")
 		res.Res = &types.PowerOnVM_TaskResponse{powerOnVMTask}
 	case *methods.CloneVM_TaskBody:
-		r.MethodCall(r, "CloneVM_Task")
+		req := req.(*methods.CloneVM_TaskBody).Req
+		r.MethodCall(r, "CloneVM_Task", req.Name, req.Spec)
 		res.Res = &types.CloneVM_TaskResponse{cloneVMTask}
 	case *methods.CreateFolderBody:
 		r.MethodCall(r, "CreateFolder")
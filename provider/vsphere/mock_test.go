@@ -138,6 +138,7 @@ type vmBuilder struct {
 	nics       []types.GuestNicInfo
 	rp         *types.ManagedObjectReference
 	metadata   []types.BaseOptionValue
+	devices    []types.BaseVirtualDevice
 }
 
 func (b *vmBuilder) vm() *mo.VirtualMachine {
@@ -148,6 +149,7 @@ func (b *vmBuilder) vm() *mo.VirtualMachine {
 	vm.ResourcePool = b.rp
 	vm.Config = &types.VirtualMachineConfigInfo{
 		ExtraConfig: b.metadata,
+		Hardware:    types.VirtualHardware{Device: b.devices},
 	}
 	vm.Self = types.ManagedObjectReference{Value: b.name}
 	return vm
@@ -173,6 +175,11 @@ func (b *vmBuilder) extraConfig(k, v string) *vmBuilder {
 	return b
 }
 
+func (b *vmBuilder) disk(capacityInKB int64) *vmBuilder {
+	b.devices = append(b.devices, &types.VirtualDisk{CapacityInKB: capacityInKB})
+	return b
+}
+
 func newNic(addrs ...string) types.GuestNicInfo {
 	return types.GuestNicInfo{IpAddress: addrs}
 }
@@ -99,7 +99,7 @@ func (step modelFoldersUpgradeStep) Run(ctx context.ProviderCallContext) error {
 	return step.env.withSession(ctx, func(env *sessionEnviron) error {
 		// We must create the folder even if there are no VMs in the model.
 		modelFolderPath := path.Join(
-			controllerFolderName(step.controllerUUID),
+			env.controllerFolderPath(step.controllerUUID),
 			env.modelFolderName(),
 		)
 		if _, err := env.client.EnsureVMFolder(env.ctx, modelFolderPath); err != nil {
@@ -0,0 +1,74 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package raftcluster
+
+// StatusRequestTopic is published by the RaftCluster facade to ask
+// every controller machine for the state of its raft node.
+// data: `StatusRequest`
+const StatusRequestTopic = "raft.status.request"
+
+// RemoveServerRequestTopic is published by the RaftCluster facade to
+// ask the raft leader to remove a server from the raft configuration.
+// Only the leader responds.
+// data: `RemoveServerRequest`
+const RemoveServerRequestTopic = "raft.remove-server.request"
+
+// StatusRequest asks for the state of the raft node on each
+// controller machine. Each machine publishes its Status on the
+// response topic.
+type StatusRequest struct {
+	ResponseTopic string `yaml:"response-topic"`
+}
+
+// Status describes the raft node on a controller machine.
+type Status struct {
+	// ServerID is the raft server ID, which is the controller
+	// machine ID.
+	ServerID string `yaml:"server-id"`
+
+	// State is the raft state of the node: Leader, Follower,
+	// Candidate or Shutdown.
+	State string `yaml:"state"`
+
+	// Leader is the address of the current leader, as known by
+	// the node.
+	Leader string `yaml:"leader,omitempty"`
+
+	Term              uint64 `yaml:"term"`
+	AppliedIndex      uint64 `yaml:"applied-index"`
+	LastIndex         uint64 `yaml:"last-index"`
+	LastSnapshotIndex uint64 `yaml:"last-snapshot-index"`
+	LastSnapshotTerm  uint64 `yaml:"last-snapshot-term"`
+
+	// LastContact is the time the node last heard from the leader,
+	// formatted as RFC3339. It is empty if the node is the leader or
+	// has never heard from one.
+	LastContact string `yaml:"last-contact,omitempty"`
+
+	// Servers is the raft configuration as known by the node.
+	Servers []Server `yaml:"servers,omitempty"`
+
+	// Error is set if the node's state could not be read.
+	Error string `yaml:"error,omitempty"`
+}
+
+// Server is a member of the raft configuration.
+type Server struct {
+	ID       string `yaml:"id"`
+	Address  string `yaml:"address"`
+	Suffrage string `yaml:"suffrage"`
+}
+
+// RemoveServerRequest asks the raft leader to remove a server from
+// the raft configuration. The leader publishes a RemoveServerResponse
+// on the response topic.
+type RemoveServerRequest struct {
+	ServerID      string `yaml:"server-id"`
+	ResponseTopic string `yaml:"response-topic"`
+}
+
+// RemoveServerResponse is the result of a RemoveServerRequest.
+type RemoveServerResponse struct {
+	Error string `yaml:"error,omitempty"`
+}
@@ -4,6 +4,7 @@
 package state
 
 import (
+	"sort"
 	"time"
 
 	"github.com/juju/errors"
@@ -104,6 +105,9 @@ type actionDoc struct {
 
 	// Results are the structured results from the action.
 	Results map[string]interface{} `bson:"results"`
+
+	// Pinned is true if the action should never be pruned.
+	Pinned bool `bson:"pinned,omitempty"`
 }
 
 // action represents an instruction to do some "action" and is expected
@@ -162,6 +166,28 @@ func (a *action) Results() (map[string]interface{}, string) {
 	return a.doc.Results, a.doc.Message
 }
 
+// Pinned returns whether the action is protected from pruning.
+func (a *action) Pinned() bool {
+	return a.doc.Pinned
+}
+
+// SetPinned sets whether the action is protected from pruning.
+func (a *action) SetPinned(pinned bool) error {
+	ops := []txn.Op{{
+		C:      actionsC,
+		Id:     a.doc.DocId,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{{"pinned", pinned}}}},
+	}}
+	if err := a.st.db().RunTransaction(ops); err == txn.ErrAborted {
+		return errors.NotFoundf("action %q", a.Id())
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot pin action %q", a.Id())
+	}
+	a.doc.Pinned = pinned
+	return nil
+}
+
 // Tag implements the Entity interface and returns a names.Tag that
 // is a names.ActionTag.
 func (a *action) Tag() names.Tag {
@@ -483,8 +509,40 @@ func (st *State) matchingActionsByReceiverAndStatus(tag names.Tag, statusConditi
 // PruneActions removes action entries until
 // only logs newer than <maxLogTime> remain and also ensures
 // that the collection is smaller than <maxLogsMB> after the
-// deletion.
-func PruneActions(st *State, maxHistoryTime time.Duration, maxHistoryMB int) error {
-	err := pruneCollection(st, maxHistoryTime, maxHistoryMB, actionsC, "completed", GoTime)
-	return errors.Trace(err)
+// deletion. Actions whose names are keys of retention are kept
+// for the corresponding duration instead of maxHistoryTime; a
+// duration of 0 means those actions are not pruned by age at all,
+// only by size. Pinned actions are never removed.
+func PruneActions(st *State, maxHistoryTime time.Duration, maxHistoryMB int, retention map[string]time.Duration) error {
+	unpinned := bson.DocElem{"pinned", bson.D{{"$ne", true}}}
+	names := make([]string, 0, len(retention))
+	for name := range retention {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if retention[name] == 0 {
+			// No age limit; still excluded from maxHistoryTime below.
+			continue
+		}
+		filter := bson.D{unpinned, {"name", name}}
+		err := pruneCollection(st, retention[name], 0, actionsC, "completed", GoTime, filter)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if maxHistoryTime > 0 {
+		filter := bson.D{unpinned, {"name", bson.D{{"$nin", names}}}}
+		err := pruneCollection(st, maxHistoryTime, 0, actionsC, "completed", GoTime, filter)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if maxHistoryMB > 0 {
+		err := pruneCollection(st, 0, maxHistoryMB, actionsC, "completed", GoTime, bson.D{unpinned})
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
 }
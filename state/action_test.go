@@ -989,7 +989,7 @@ func (s *ActionPruningSuite) TestPruneActionsBySize(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(actions, gc.HasLen, numActionEntries)
 
-	err = state.PruneActions(s.State, 0, maxLogSize)
+	err = state.PruneActions(s.State, 0, maxLogSize, nil)
 	c.Assert(err, jc.ErrorIsNil)
 
 	actions, err = unit.Actions()
@@ -1026,7 +1026,7 @@ func (s *ActionPruningSuite) TestPruneActionsBySizeOldestFirst(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(actions, gc.HasLen, numActionEntries)
 
-	err = state.PruneActions(s.State, 0, maxLogSize)
+	err = state.PruneActions(s.State, 0, maxLogSize, nil)
 	c.Assert(err, jc.ErrorIsNil)
 
 	actions, err = unit.Actions()
@@ -1063,7 +1063,7 @@ func (s *ActionPruningSuite) TestPruneActionByAge(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(actions, gc.HasLen, numCurrentActionEntries+numExpiredActionEntries)
 
-	err = state.PruneActions(s.State, 1*time.Hour, 0)
+	err = state.PruneActions(s.State, 1*time.Hour, 0, nil)
 	c.Assert(err, jc.ErrorIsNil)
 
 	actions, err = unit.Actions()
@@ -1090,7 +1090,7 @@ func (s *ActionPruningSuite) TestDoNotPruneIncompleteActions(c *gc.C) {
 	actions, err := unit.Actions()
 	c.Assert(err, jc.ErrorIsNil)
 
-	err = state.PruneActions(s.State, 1*time.Hour, 0)
+	err = state.PruneActions(s.State, 1*time.Hour, 0, nil)
 	c.Assert(err, jc.ErrorIsNil)
 
 	actions, err = unit.Actions()
@@ -1099,3 +1099,81 @@ func (s *ActionPruningSuite) TestDoNotPruneIncompleteActions(c *gc.C) {
 
 	c.Assert(actionsLen, gc.Equals, numZeroValueEntries)
 }
+
+func (s *ActionPruningSuite) TestPruneActionsByNameRetention(c *gc.C) {
+	clock := testclock.NewClock(time.Now())
+	err := s.State.SetClockForTesting(clock)
+	c.Assert(err, jc.ErrorIsNil)
+	application := s.Factory.MakeApplication(c, nil)
+	unit := s.Factory.MakeUnit(c, &factory.UnitParams{Application: application})
+
+	state.PrimeNamedActions(c, clock.Now().Add(-48*time.Hour), unit, "backup", 2)
+	state.PrimeNamedActions(c, clock.Now().Add(-2*time.Hour), unit, "status", 3)
+	state.PrimeNamedActions(c, clock.Now().Add(-2*time.Hour), unit, "other", 4)
+
+	err = state.PruneActions(s.State, 10*time.Hour, 0, map[string]time.Duration{
+		"backup": 72 * time.Hour,
+		"status": time.Hour,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	actions, err := unit.Actions()
+	c.Assert(err, jc.ErrorIsNil)
+	counts := make(map[string]int)
+	for _, action := range actions {
+		counts[action.Name()]++
+	}
+	c.Assert(counts, jc.DeepEquals, map[string]int{
+		"backup": 2,
+		"other":  4,
+	})
+}
+
+func (s *ActionPruningSuite) TestPruneActionsZeroRetention(c *gc.C) {
+	clock := testclock.NewClock(time.Now())
+	err := s.State.SetClockForTesting(clock)
+	c.Assert(err, jc.ErrorIsNil)
+	application := s.Factory.MakeApplication(c, nil)
+	unit := s.Factory.MakeUnit(c, &factory.UnitParams{Application: application})
+
+	state.PrimeNamedActions(c, clock.Now().Add(-48*time.Hour), unit, "backup", 2)
+	state.PrimeNamedActions(c, clock.Now().Add(-48*time.Hour), unit, "other", 3)
+
+	// A zero retention disables age pruning for that action only.
+	err = state.PruneActions(s.State, time.Hour, 0, map[string]time.Duration{
+		"backup": 0,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	actions, err := unit.Actions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(actions, gc.HasLen, 2)
+	for _, action := range actions {
+		c.Check(action.Name(), gc.Equals, "backup")
+	}
+}
+
+func (s *ActionPruningSuite) TestDoNotPrunePinnedActions(c *gc.C) {
+	clock := testclock.NewClock(time.Now())
+	err := s.State.SetClockForTesting(clock)
+	c.Assert(err, jc.ErrorIsNil)
+	application := s.Factory.MakeApplication(c, nil)
+	unit := s.Factory.MakeUnit(c, &factory.UnitParams{Application: application})
+
+	state.PrimeActions(c, clock.Now().Add(-10*time.Hour), unit, 3)
+	actions, err := unit.Actions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(actions, gc.HasLen, 3)
+	err = actions[0].SetPinned(true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(actions[0].Pinned(), jc.IsTrue)
+
+	err = state.PruneActions(s.State, time.Hour, 0, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	remaining, err := unit.Actions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(remaining, gc.HasLen, 1)
+	c.Assert(remaining[0].Id(), gc.Equals, actions[0].Id())
+	c.Assert(remaining[0].Pinned(), jc.IsTrue)
+}
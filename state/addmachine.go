@@ -39,6 +39,11 @@ type MachineTemplate struct {
 	// It is ignored if Jobs does not contain JobManageModel.
 	NoVote bool
 
+	// ReadReplica holds whether a machine running a controller
+	// is a read replica, which never votes and only serves
+	// read-only API calls. It implies NoVote.
+	ReadReplica bool
+
 	// Addresses holds the addresses to be associated with the
 	// new machine.
 	//
@@ -511,7 +516,8 @@ func (st *State) machineDocForTemplate(template MachineTemplate, id string) *mac
 		Addresses:               fromNetworkAddresses(template.Addresses, OriginMachine),
 		PreferredPrivateAddress: fromNetworkAddress(privateAddr, OriginMachine),
 		PreferredPublicAddress:  fromNetworkAddress(publicAddr, OriginMachine),
-		NoVote:                  template.NoVote,
+		NoVote:                  template.NoVote || template.ReadReplica,
+		ReadReplica:             template.ReadReplica,
 		Placement:               template.Placement,
 	}
 }
@@ -4,6 +4,7 @@
 package backups
 
 import (
+	"bufio"
 	"bytes"
 	"compress/gzip"
 	"io"
@@ -22,6 +23,7 @@ const (
 	filesBundle  = "root.tar"
 	dbDumpDir    = "dump"
 	metadataFile = "metadata.json"
+	manifestFile = "manifest.json"
 )
 
 var legacyVersion = version.Number{Major: 1, Minor: 20}
@@ -47,6 +49,10 @@ type ArchivePaths struct {
 
 	// MetadataFile is the path to the metadata file.
 	MetadataFile string
+
+	// ManifestFile is the path to the manifest file, which lists the
+	// SHA-256 digest of every other file in the archive.
+	ManifestFile string
 }
 
 // NewCanonicalArchivePaths composes a new ArchivePaths with default
@@ -59,6 +65,7 @@ func NewCanonicalArchivePaths() ArchivePaths {
 		FilesBundle:  path.Join(contentDir, filesBundle),
 		DBDumpDir:    path.Join(contentDir, dbDumpDir),
 		MetadataFile: path.Join(contentDir, metadataFile),
+		ManifestFile: path.Join(contentDir, manifestFile),
 	}
 }
 
@@ -73,6 +80,7 @@ func NewNonCanonicalArchivePaths(rootDir string) ArchivePaths {
 		FilesBundle:  filepath.Join(rootDir, contentDir, filesBundle),
 		DBDumpDir:    filepath.Join(rootDir, contentDir, dbDumpDir),
 		MetadataFile: filepath.Join(rootDir, contentDir, metadataFile),
+		ManifestFile: filepath.Join(rootDir, contentDir, manifestFile),
 	}
 }
 
@@ -111,7 +119,7 @@ func NewArchiveWorkspaceReader(archive io.Reader) (*ArchiveWorkspace, error) {
 }
 
 func unpackCompressedReader(targetDir string, tarFile io.Reader) error {
-	tarFile, err := gzip.NewReader(tarFile)
+	tarFile, err := newGzipReader(tarFile)
 	if err != nil {
 		return errors.Annotate(err, "while uncompressing archive file")
 	}
@@ -119,6 +127,19 @@ func unpackCompressedReader(targetDir string, tarFile io.Reader) error {
 	return errors.Trace(err)
 }
 
+// newGzipReader returns a reader for the uncompressed content of an
+// unencrypted backup archive. Encrypted archives must be decrypted by
+// their owner before they can be used.
+func newGzipReader(r io.Reader) (*gzip.Reader, error) {
+	br := bufio.NewReader(r)
+	if encrypted, err := IsEncryptedArchive(br); err != nil {
+		return nil, errors.Trace(err)
+	} else if encrypted {
+		return nil, errors.New(`backup archive is encrypted: decrypt it with "juju verify-backup --decrypt-to" first`)
+	}
+	return gzip.NewReader(br)
+}
+
 // Close cleans up the workspace dir.
 func (ws *ArchiveWorkspace) Close() error {
 	err := os.RemoveAll(ws.RootDir)
@@ -194,7 +215,7 @@ func NewArchiveData(data []byte) *ArchiveData {
 // memory and kept there. So for relatively large archives it will often
 // be more appropriate to use ArchiveWorkspace instead.
 func NewArchiveDataReader(r io.Reader) (*ArchiveData, error) {
-	gzr, err := gzip.NewReader(r)
+	gzr, err := newGzipReader(r)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
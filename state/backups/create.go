@@ -5,7 +5,9 @@ package backups
 
 import (
 	"compress/gzip"
+	"crypto/rsa"
 	"crypto/sha1"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -33,6 +35,7 @@ type createArgs struct {
 	db             DBDumper
 	metadataReader io.Reader
 	noDownload     bool
+	encryptionKey  *rsa.PublicKey
 }
 
 type createResult struct {
@@ -50,6 +53,7 @@ func create(args *createArgs) (_ *createResult, err error) {
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	builder.encryptionKey = args.encryptionKey
 	defer func() {
 		if cerr := builder.cleanUp(args.noDownload); cerr != nil {
 			cerr.Log(logger)
@@ -105,6 +109,9 @@ type builder struct {
 	// bundleFile is the inner archive file containing all the juju
 	// state-related files gathered during backup.
 	bundleFile io.WriteCloser
+	// encryptionKey, if set, is the public key with which the archive
+	// is encrypted.
+	encryptionKey *rsa.PublicKey
 }
 
 // newBuilder returns a new backup archive builder.  It creates the temp
@@ -289,6 +296,22 @@ func (b *builder) buildDBDump() error {
 	return nil
 }
 
+func (b *builder) buildManifest() error {
+	logger.Infof("building manifest")
+	if err := b.closeBundleFile(); err != nil {
+		return errors.Trace(err)
+	}
+	manifest, err := buildManifest(b.rootDir, b.archivePaths.ContentDir, b.archivePaths.ManifestFile)
+	if err != nil {
+		return errors.Annotate(err, "while building manifest")
+	}
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(ioutil.WriteFile(b.archivePaths.ManifestFile, data, 0600))
+}
+
 func (b *builder) buildArchive(outFile io.Writer) error {
 	tarball := gzip.NewWriter(outFile)
 	defer tarball.Close()
@@ -317,8 +340,24 @@ func (b *builder) buildArchiveAndChecksum() error {
 	// that users can compare the published checksum against the
 	// checksum of the file without having to decompress it first.
 	hasher := hash.NewHashingWriter(b.archiveFile, sha1.New())
-	if err := b.buildArchive(hasher); err != nil {
-		return errors.Trace(err)
+	if b.encryptionKey == nil {
+		if err := b.buildArchive(hasher); err != nil {
+			return errors.Trace(err)
+		}
+	} else {
+		// The checksum is of the encrypted archive, as that is
+		// what is stored and downloaded.
+		logger.Infof("encrypting archive file")
+		encrypter, err := newEncryptingWriter(hasher, b.encryptionKey)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err := b.buildArchive(encrypter); err != nil {
+			return errors.Trace(err)
+		}
+		if err := encrypter.Close(); err != nil {
+			return errors.Trace(err)
+		}
 	}
 
 	// Save the SHA1 checksum.
@@ -341,6 +380,11 @@ func (b *builder) buildAll() error {
 		return errors.Trace(err)
 	}
 
+	// Record the digest of everything gathered so far.
+	if err := b.buildManifest(); err != nil {
+		return errors.Trace(err)
+	}
+
 	// Bundle it all into a tarball.
 	if err := b.buildArchiveAndChecksum(); err != nil {
 		return errors.Trace(err)
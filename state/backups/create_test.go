@@ -56,6 +56,10 @@ func (s *createSuite) TestLegacy(c *gc.C) {
 	s.checkSize(c, file, size)
 	s.checkChecksum(c, file, checksum)
 	s.checkArchive(c, file, expected)
+
+	// The archive is complete and consistent with its manifest.
+	_, err = backups.VerifyArchive(file)
+	c.Check(err, jc.ErrorIsNil)
 }
 
 func (s *createSuite) TestMetadataFileMissing(c *gc.C) {
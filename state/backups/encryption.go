@@ -0,0 +1,191 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/binary"
+	"hash"
+	"io"
+
+	"github.com/juju/errors"
+)
+
+// An encrypted backup archive is the gzipped tarball encrypted with a
+// random AES-256 key in CTR mode, authenticated with HMAC-SHA256
+// (encrypt-then-MAC). The random keys are encrypted with the RSA
+// public key using OAEP, so that only the holder of the corresponding
+// private key can decrypt the archive. The layout is:
+//
+//	magic | wrapped key length (uint16) | wrapped keys | IV | ciphertext | MAC
+//
+// where the MAC covers everything that precedes it.
+const (
+	encryptedArchiveMagic = "JUJUBKE1"
+	encryptionKeySize     = 32
+	macKeySize            = 32
+	macSize               = sha256.Size
+	decryptChunkSize      = 32 * 1024
+)
+
+// oaepLabel binds the wrapped keys to their use in backup archives.
+var oaepLabel = []byte("juju-backup")
+
+// IsEncryptedArchive reports whether the archive read by r is
+// encrypted, without consuming any of it.
+func IsEncryptedArchive(r *bufio.Reader) (bool, error) {
+	magic, err := r.Peek(len(encryptedArchiveMagic))
+	if err == io.EOF {
+		return false, nil
+	} else if err != nil {
+		return false, errors.Trace(err)
+	}
+	return string(magic) == encryptedArchiveMagic, nil
+}
+
+// encryptingWriter encrypts everything written to it. It must be
+// closed to write the MAC, without which the archive cannot be
+// decrypted.
+type encryptingWriter struct {
+	w      io.Writer
+	stream cipher.Stream
+	mac    hash.Hash
+	buf    []byte
+}
+
+func newEncryptingWriter(w io.Writer, key *rsa.PublicKey) (io.WriteCloser, error) {
+	keys := make([]byte, encryptionKeySize+macKeySize)
+	if _, err := io.ReadFull(rand.Reader, keys); err != nil {
+		return nil, errors.Trace(err)
+	}
+	wrapped, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, key, keys, oaepLabel)
+	if err != nil {
+		return nil, errors.Annotate(err, "encrypting archive key")
+	}
+	block, err := aes.NewCipher(keys[:encryptionKeySize])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	iv := make([]byte, aes.BlockSize)
+	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	header := []byte(encryptedArchiveMagic)
+	header = append(header, 0, 0)
+	binary.BigEndian.PutUint16(header[len(encryptedArchiveMagic):], uint16(len(wrapped)))
+	header = append(header, wrapped...)
+	header = append(header, iv...)
+
+	mac := hmac.New(sha256.New, keys[encryptionKeySize:])
+	mac.Write(header)
+	if _, err := w.Write(header); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &encryptingWriter{
+		w:      w,
+		stream: cipher.NewCTR(block, iv),
+		mac:    mac,
+	}, nil
+}
+
+// Write implements io.Writer.
+func (e *encryptingWriter) Write(p []byte) (int, error) {
+	if cap(e.buf) < len(p) {
+		e.buf = make([]byte, len(p))
+	}
+	out := e.buf[:len(p)]
+	e.stream.XORKeyStream(out, p)
+	e.mac.Write(out)
+	return e.w.Write(out)
+}
+
+// Close writes the MAC. It does not close the underlying writer.
+func (e *encryptingWriter) Close() error {
+	_, err := e.w.Write(e.mac.Sum(nil))
+	return errors.Trace(err)
+}
+
+// EncryptArchive encrypts the gzipped tarball read from r with the
+// public key, writing the encrypted archive to w.
+func EncryptArchive(w io.Writer, r io.Reader, key *rsa.PublicKey) error {
+	enc, err := newEncryptingWriter(w, key)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := io.Copy(enc, r); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(enc.Close())
+}
+
+// DecryptArchive decrypts the encrypted archive read from r, writing
+// the gzipped tarball to w. The archive is only authenticated once it
+// has been read in full, so if an error is returned anything written
+// to w must be discarded.
+func DecryptArchive(w io.Writer, r io.Reader, key *rsa.PrivateKey) error {
+	header := make([]byte, len(encryptedArchiveMagic)+2)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return errors.Annotate(err, "reading encrypted archive header")
+	}
+	if string(header[:len(encryptedArchiveMagic)]) != encryptedArchiveMagic {
+		return errors.New("backup archive is not encrypted")
+	}
+	wrapped := make([]byte, binary.BigEndian.Uint16(header[len(encryptedArchiveMagic):]))
+	iv := make([]byte, aes.BlockSize)
+	if _, err := io.ReadFull(r, wrapped); err != nil {
+		return errors.Annotate(err, "reading encrypted archive header")
+	}
+	if _, err := io.ReadFull(r, iv); err != nil {
+		return errors.Annotate(err, "reading encrypted archive header")
+	}
+	keys, err := rsa.DecryptOAEP(sha256.New(), rand.Reader, key, wrapped, oaepLabel)
+	if err != nil {
+		return errors.New("cannot decrypt backup archive key: wrong private key?")
+	}
+	block, err := aes.NewCipher(keys[:encryptionKeySize])
+	if err != nil {
+		return errors.Trace(err)
+	}
+	stream := cipher.NewCTR(block, iv)
+	mac := hmac.New(sha256.New, keys[encryptionKeySize:])
+	mac.Write(header)
+	mac.Write(wrapped)
+	mac.Write(iv)
+
+	// The MAC is the last macSize bytes of the archive, so always
+	// hold that many bytes back from decryption.
+	br := bufio.NewReaderSize(r, decryptChunkSize+macSize)
+	out := make([]byte, decryptChunkSize)
+	for {
+		data, err := br.Peek(decryptChunkSize + macSize)
+		if err != nil && err != io.EOF {
+			return errors.Annotate(err, "reading encrypted archive")
+		}
+		if len(data) < macSize {
+			return errors.New("encrypted backup archive is truncated")
+		}
+		n := len(data) - macSize
+		mac.Write(data[:n])
+		stream.XORKeyStream(out[:n], data[:n])
+		if _, err := w.Write(out[:n]); err != nil {
+			return errors.Trace(err)
+		}
+		if err == io.EOF {
+			if !hmac.Equal(mac.Sum(nil), data[n:]) {
+				return errors.New("encrypted backup archive failed authentication: it is corrupt or has been tampered with")
+			}
+			return nil
+		}
+		if _, err := br.Discard(n); err != nil {
+			return errors.Trace(err)
+		}
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/juju/testing"
 	"github.com/juju/utils/filestorage"
 
+	"github.com/juju/juju/controller"
 	"github.com/juju/juju/state"
 )
 
@@ -30,16 +31,33 @@ var (
 	RunCommand            = &runCommandFn
 	ReplaceableFolders    = &replaceableFolders
 	MongoInstalledVersion = &mongoInstalledVersion
+	NewS3Bucket           = &newS3Bucket
 )
 
+// ObjectBucket exposes objectBucket for patching NewS3Bucket.
+type ObjectBucket = objectBucket
+
 var _ filestorage.DocStorage = (*backupsDocStorage)(nil)
 var _ filestorage.RawFileStorage = (*backupBlobStorage)(nil)
+var _ filestorage.RawFileStorage = (*localFileStorage)(nil)
+var _ filestorage.RawFileStorage = (*s3FileStorage)(nil)
 
 func getBackupDBWrapper(st *state.State) *storageDBWrapper {
 	db := st.MongoSession().DB(storageDBName)
 	return newStorageDBWrapper(db, storageMetaName, st.ModelUUID())
 }
 
+// NewStorageWithConfig returns a backup FileStorage for the state
+// which selects archive storage using the given controller config.
+func NewStorageWithConfig(st *state.State, controllerConfig func() (controller.Config, error)) filestorage.FileStorage {
+	dbWrap := getBackupDBWrapper(st)
+	defer dbWrap.Close()
+	blobs := newFileStorage(dbWrap, backupStorageRoot)
+	files := newRoutedFileStorage(dbWrap, blobs, controllerConfig)
+	docs := newMetadataStorage(dbWrap)
+	return filestorage.NewFileStorage(docs, files)
+}
+
 // NewBackupID creates a new backup ID based on the metadata.
 func NewBackupID(meta *Metadata) string {
 	doc := newStorageMetaDoc(meta)
@@ -0,0 +1,195 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// ArchiveFormatVersion is the version of the backup archive format
+// created by this version of juju. It is recorded in the manifest of
+// each archive, and increased whenever the archive layout changes in
+// a way that older versions of juju cannot restore.
+const ArchiveFormatVersion = 1
+
+// Manifest lists the SHA-256 digest of every file in a backup archive.
+type Manifest struct {
+	// FormatVersion is the version of the archive format.
+	FormatVersion int `json:"format-version"`
+
+	// Files maps the path of each file in the archive, other than the
+	// manifest itself, to its hex-encoded SHA-256 digest.
+	Files map[string]string `json:"files"`
+}
+
+// buildManifest computes the manifest of the files under contentDir,
+// keyed by their slash-separated paths relative to rootDir.
+func buildManifest(rootDir, contentDir, manifestFile string) (*Manifest, error) {
+	manifest := Manifest{
+		FormatVersion: ArchiveFormatVersion,
+		Files:         make(map[string]string),
+	}
+	err := filepath.Walk(contentDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if !info.Mode().IsRegular() || path == manifestFile {
+			return nil
+		}
+		rel, err := filepath.Rel(rootDir, path)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		defer f.Close()
+		digest, err := sha256Hex(f)
+		if err != nil {
+			return errors.Annotatef(err, "while hashing %q", rel)
+		}
+		manifest.Files[filepath.ToSlash(rel)] = digest
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &manifest, nil
+}
+
+func sha256Hex(r io.Reader) (string, error) {
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", errors.Trace(err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// VerifyResult describes a backup archive that passed verification.
+type VerifyResult struct {
+	// FormatVersion is the version of the archive format.
+	FormatVersion int
+
+	// FileCount is the number of files checked against the manifest.
+	FileCount int
+
+	// Metadata is the backup metadata stored in the archive.
+	Metadata *Metadata
+}
+
+// VerifyArchive checks that the unencrypted, gzipped backup archive
+// read from r has a supported format version, and that its content
+// exactly matches its manifest. It does not restore anything.
+func VerifyArchive(r io.Reader) (*VerifyResult, error) {
+	br := bufio.NewReader(r)
+	if encrypted, err := IsEncryptedArchive(br); err != nil {
+		return nil, errors.Trace(err)
+	} else if encrypted {
+		return nil, errors.New("backup archive is encrypted: it must be decrypted before it can be verified")
+	}
+	gzr, err := gzip.NewReader(br)
+	if err != nil {
+		return nil, errors.Annotate(err, "while uncompressing archive file")
+	}
+	defer gzr.Close()
+
+	paths := NewCanonicalArchivePaths()
+	digests := make(map[string]string)
+	var manifest *Manifest
+	var meta *Metadata
+	tr := tar.NewReader(gzr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, errors.Annotate(err, "while reading archive")
+		}
+		if hdr.Typeflag != tar.TypeReg && hdr.Typeflag != tar.TypeRegA {
+			continue
+		}
+		switch hdr.Name {
+		case paths.ManifestFile:
+			manifest = &Manifest{}
+			if err := json.NewDecoder(tr).Decode(manifest); err != nil {
+				return nil, errors.Annotate(err, "while reading manifest")
+			}
+			continue
+		case paths.MetadataFile:
+			// The metadata is both parsed and checked against the
+			// manifest.
+			h := sha256.New()
+			meta, err = NewMetadataJSONReader(io.TeeReader(tr, h))
+			if err != nil {
+				return nil, errors.Annotate(err, "while reading metadata")
+			}
+			if _, err := io.Copy(h, tr); err != nil {
+				return nil, errors.Trace(err)
+			}
+			digests[hdr.Name] = hex.EncodeToString(h.Sum(nil))
+			continue
+		}
+		digest, err := sha256Hex(tr)
+		if err != nil {
+			return nil, errors.Annotatef(err, "while reading %q", hdr.Name)
+		}
+		digests[hdr.Name] = digest
+	}
+
+	if manifest == nil {
+		return nil, errors.NotFoundf("manifest in backup archive (it may have been created by an older version of juju)")
+	}
+	if manifest.FormatVersion > ArchiveFormatVersion {
+		return nil, errors.NotSupportedf("backup archive format version %d (latest supported is %d)",
+			manifest.FormatVersion, ArchiveFormatVersion)
+	}
+	if meta == nil {
+		return nil, errors.NotFoundf("metadata in backup archive")
+	}
+	var problems []string
+	for name, expected := range manifest.Files {
+		actual, ok := digests[name]
+		if !ok {
+			problems = append(problems, name+" is missing")
+		} else if actual != expected {
+			problems = append(problems, name+" does not match its checksum")
+		}
+	}
+	for name := range digests {
+		if _, ok := manifest.Files[name]; !ok {
+			problems = append(problems, name+" is not in the manifest")
+		}
+	}
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return nil, errors.Errorf("backup archive failed verification: %s", joinProblems(problems))
+	}
+	return &VerifyResult{
+		FormatVersion: manifest.FormatVersion,
+		FileCount:     len(manifest.Files),
+		Metadata:      meta,
+	}, nil
+}
+
+func joinProblems(problems []string) string {
+	const maxShown = 5
+	if len(problems) <= maxShown {
+		return strings.Join(problems, "; ")
+	}
+	return fmt.Sprintf("%s (and %d more)", strings.Join(problems[:maxShown], "; "), len(problems)-maxShown)
+}
@@ -0,0 +1,180 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"crypto/rsa"
+	"io"
+	"io/ioutil"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state/backups"
+	backupstesting "github.com/juju/juju/state/backups/testing"
+	"github.com/juju/juju/testing"
+)
+
+type manifestSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&manifestSuite{})
+
+func (s *manifestSuite) newArchive(c *gc.C) *bytes.Buffer {
+	meta := backupstesting.NewMetadata()
+	files := []backupstesting.File{{
+		Name:    "var/lib/juju/system-identity",
+		Content: "<an ssh key goes here>",
+	}}
+	dump := []backupstesting.File{{
+		Name:    "juju/machines.bson",
+		Content: "<BSON data goes here>",
+	}}
+	archive, err := backupstesting.NewArchiveWithManifest(meta, files, dump)
+	c.Assert(err, jc.ErrorIsNil)
+	return archive
+}
+
+func (s *manifestSuite) TestVerifyArchive(c *gc.C) {
+	result, err := backups.VerifyArchive(s.newArchive(c))
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(result.FormatVersion, gc.Equals, backups.ArchiveFormatVersion)
+	c.Check(result.FileCount, gc.Equals, 3)
+	c.Check(result.Metadata.Origin.Model, gc.Equals, backupstesting.NewMetadata().Origin.Model)
+}
+
+func (s *manifestSuite) TestVerifyArchiveNoManifest(c *gc.C) {
+	archive, err := backupstesting.NewArchiveBasic(backupstesting.NewMetadata())
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = backups.VerifyArchive(archive)
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+	c.Check(err, gc.ErrorMatches, `manifest in backup archive \(it may have been created by an older version of juju\) not found`)
+}
+
+func (s *manifestSuite) TestVerifyArchiveCorrupt(c *gc.C) {
+	archive := s.tamper(c, s.newArchive(c), "juju-backup/dump/juju/machines.bson")
+
+	_, err := backups.VerifyArchive(archive)
+	c.Check(err, gc.ErrorMatches, "backup archive failed verification: juju-backup/dump/juju/machines.bson does not match its checksum")
+}
+
+func (s *manifestSuite) TestVerifyArchiveTruncated(c *gc.C) {
+	archive := s.newArchive(c)
+
+	_, err := backups.VerifyArchive(bytes.NewReader(archive.Bytes()[:archive.Len()/2]))
+	c.Check(err, gc.ErrorMatches, "while reading .*")
+}
+
+// tamper returns a copy of the archive with the content of the named
+// file changed.
+func (s *manifestSuite) tamper(c *gc.C, archive io.Reader, name string) *bytes.Buffer {
+	gzr, err := gzip.NewReader(archive)
+	c.Assert(err, jc.ErrorIsNil)
+	tr := tar.NewReader(gzr)
+
+	var out bytes.Buffer
+	gzw := gzip.NewWriter(&out)
+	tw := tar.NewWriter(gzw)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		c.Assert(err, jc.ErrorIsNil)
+		data, err := ioutil.ReadAll(tr)
+		c.Assert(err, jc.ErrorIsNil)
+		if hdr.Name == name {
+			data = []byte("<tampered>")
+			hdr.Size = int64(len(data))
+		}
+		c.Assert(tw.WriteHeader(hdr), jc.ErrorIsNil)
+		_, err = tw.Write(data)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	c.Assert(tw.Close(), jc.ErrorIsNil)
+	c.Assert(gzw.Close(), jc.ErrorIsNil)
+	return &out
+}
+
+func (s *manifestSuite) TestVerifyArchiveEncrypted(c *gc.C) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	var encrypted bytes.Buffer
+	err = backups.EncryptArchive(&encrypted, s.newArchive(c), &key.PublicKey)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = backups.VerifyArchive(&encrypted)
+	c.Check(err, gc.ErrorMatches, "backup archive is encrypted: it must be decrypted before it can be verified")
+}
+
+type encryptionSuite struct {
+	testing.BaseSuite
+	key *rsa.PrivateKey
+}
+
+var _ = gc.Suite(&encryptionSuite{})
+
+func (s *encryptionSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	s.key = key
+}
+
+func (s *encryptionSuite) encrypt(c *gc.C, data []byte) []byte {
+	var encrypted bytes.Buffer
+	err := backups.EncryptArchive(&encrypted, bytes.NewReader(data), &s.key.PublicKey)
+	c.Assert(err, jc.ErrorIsNil)
+	return encrypted.Bytes()
+}
+
+func (s *encryptionSuite) TestRoundTrip(c *gc.C) {
+	// Larger than a single decryption chunk.
+	data := bytes.Repeat([]byte("<archive data>"), 10000)
+	encrypted := s.encrypt(c, data)
+	c.Check(bytes.Contains(encrypted, []byte("<archive data>")), jc.IsFalse)
+
+	isEncrypted, err := backups.IsEncryptedArchive(bufio.NewReader(bytes.NewReader(encrypted)))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(isEncrypted, jc.IsTrue)
+
+	var decrypted bytes.Buffer
+	err = backups.DecryptArchive(&decrypted, bytes.NewReader(encrypted), s.key)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(decrypted.Bytes(), jc.DeepEquals, data)
+}
+
+func (s *encryptionSuite) TestIsEncryptedArchivePlain(c *gc.C) {
+	isEncrypted, err := backups.IsEncryptedArchive(bufio.NewReader(bytes.NewReader([]byte("<archive data>"))))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(isEncrypted, jc.IsFalse)
+}
+
+func (s *encryptionSuite) TestDecryptTampered(c *gc.C) {
+	encrypted := s.encrypt(c, []byte("<archive data>"))
+	encrypted[len(encrypted)-40] ^= 0xff
+
+	var decrypted bytes.Buffer
+	err := backups.DecryptArchive(&decrypted, bytes.NewReader(encrypted), s.key)
+	c.Check(err, gc.ErrorMatches, "encrypted backup archive failed authentication: .*")
+}
+
+func (s *encryptionSuite) TestDecryptWrongKey(c *gc.C) {
+	encrypted := s.encrypt(c, []byte("<archive data>"))
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+
+	var decrypted bytes.Buffer
+	err = backups.DecryptArchive(&decrypted, bytes.NewReader(encrypted), other)
+	c.Check(err, gc.ErrorMatches, `cannot decrypt backup archive key: wrong private key\?`)
+}
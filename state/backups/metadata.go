@@ -68,6 +68,20 @@ type Metadata struct {
 	// Notes is an optional user-supplied annotation.
 	Notes string
 
+	// Scheduled records whether the backup was created by the
+	// controller's backup schedule, rather than requested by a user.
+	// Only scheduled backups are subject to the retention policy.
+	Scheduled bool
+
+	// Failure holds the error with which a scheduled backup failed.
+	// The metadata of a failed backup is kept so that the outcome of
+	// the schedule can be reported, but there is no archive.
+	Failure string
+
+	// Encrypted records whether the archive is encrypted, in which
+	// case it can only be used by the holder of the private key.
+	Encrypted bool
+
 	// TODO(wallyworld) - remove these ASAP
 	// These are only used by the restore CLI when re-bootstrapping.
 	// We will use a better solution but the way restore currently
@@ -0,0 +1,66 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// ScheduleStatus describes the outcome of the controller's most recent
+// scheduled backups, as recorded in their metadata.
+type ScheduleStatus struct {
+	// LastAttempt is when a scheduled backup was last attempted.
+	LastAttempt time.Time
+
+	// LastSuccess is when a scheduled backup last succeeded.
+	LastSuccess time.Time
+
+	// LastBackupID is the ID of the most recent successful
+	// scheduled backup.
+	LastBackupID string
+
+	// LastError holds the error with which the most recent scheduled
+	// backup failed, or is empty if it succeeded.
+	LastError string
+}
+
+// Failed reports whether the most recent scheduled backup failed.
+func (s ScheduleStatus) Failed() bool {
+	return s.LastError != ""
+}
+
+// NewScheduleStatus returns the outcome of the most recent scheduled
+// backups recorded in the given metadata. If no scheduled backup has
+// been attempted, a zero ScheduleStatus is returned.
+func NewScheduleStatus(all []*Metadata) ScheduleStatus {
+	var status ScheduleStatus
+	for _, meta := range all {
+		if !meta.Scheduled {
+			continue
+		}
+		if meta.Started.After(status.LastAttempt) {
+			status.LastAttempt = meta.Started
+			status.LastError = meta.Failure
+		}
+		if meta.Failure == "" && meta.Started.After(status.LastSuccess) {
+			status.LastSuccess = meta.Started
+			status.LastBackupID = meta.ID()
+		}
+	}
+	return status
+}
+
+// GetScheduleStatus returns the outcome of the most recent scheduled
+// backups of the controller whose database is supplied.
+func GetScheduleStatus(db DB) (ScheduleStatus, error) {
+	stor := NewStorage(db)
+	defer stor.Close()
+	all, err := NewBackups(stor).List()
+	if err != nil {
+		return ScheduleStatus{}, errors.Annotate(err, "cannot list backups")
+	}
+	return NewScheduleStatus(all), nil
+}
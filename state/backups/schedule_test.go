@@ -0,0 +1,69 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state/backups"
+	"github.com/juju/juju/testing"
+)
+
+type scheduleSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&scheduleSuite{})
+
+func scheduleMetadata(id string, started time.Time, scheduled bool, failure string) *backups.Metadata {
+	meta := backups.NewMetadata()
+	meta.SetID(id)
+	meta.Started = started
+	meta.Scheduled = scheduled
+	meta.Failure = failure
+	return meta
+}
+
+func (s *scheduleSuite) TestNewScheduleStatusNone(c *gc.C) {
+	started := time.Date(2019, 5, 26, 2, 0, 0, 0, time.UTC)
+	status := backups.NewScheduleStatus([]*backups.Metadata{
+		scheduleMetadata("manual", started, false, ""),
+	})
+	c.Assert(status, jc.DeepEquals, backups.ScheduleStatus{})
+	c.Assert(status.Failed(), jc.IsFalse)
+}
+
+func (s *scheduleSuite) TestNewScheduleStatusSucceeded(c *gc.C) {
+	started := time.Date(2019, 5, 26, 2, 0, 0, 0, time.UTC)
+	status := backups.NewScheduleStatus([]*backups.Metadata{
+		scheduleMetadata("failed", started.Add(-48*time.Hour), true, "mongodump failed"),
+		scheduleMetadata("latest", started, true, ""),
+		scheduleMetadata("older", started.Add(-24*time.Hour), true, ""),
+		scheduleMetadata("manual", started.Add(time.Hour), false, ""),
+	})
+	c.Assert(status, jc.DeepEquals, backups.ScheduleStatus{
+		LastAttempt:  started,
+		LastSuccess:  started,
+		LastBackupID: "latest",
+	})
+	c.Assert(status.Failed(), jc.IsFalse)
+}
+
+func (s *scheduleSuite) TestNewScheduleStatusFailed(c *gc.C) {
+	started := time.Date(2019, 5, 26, 2, 0, 0, 0, time.UTC)
+	status := backups.NewScheduleStatus([]*backups.Metadata{
+		scheduleMetadata("failed", started, true, "mongodump failed"),
+		scheduleMetadata("older", started.Add(-24*time.Hour), true, ""),
+	})
+	c.Assert(status, jc.DeepEquals, backups.ScheduleStatus{
+		LastAttempt:  started,
+		LastSuccess:  started.Add(-24 * time.Hour),
+		LastBackupID: "older",
+		LastError:    "mongodump failed",
+	})
+	c.Assert(status.Failed(), jc.IsTrue)
+}
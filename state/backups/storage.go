@@ -42,12 +42,26 @@ type storageMetaDoc struct {
 	Size           int64  `bson:"size,minsize"`
 	Stored         int64  `bson:"stored,minsize"`
 
+	// StorageType and StorageLocation record where the archive was
+	// stored, so that it can be found after the controller's backup
+	// storage configuration changes. They are empty for archives
+	// stored in the controller's database.
+	StorageType     string `bson:"storagetype,omitempty"`
+	StorageLocation string `bson:"storagelocation,omitempty"`
+
 	// backup
 
 	Started  int64  `bson:"started,minsize"`
 	Finished int64  `bson:"finished,minsize"`
 	Notes    string `bson:"notes,omitempty"`
 
+	Scheduled bool `bson:"scheduled,omitempty"`
+	Encrypted bool `bson:"encrypted,omitempty"`
+
+	// Failure is set, and there is no archive, if a scheduled
+	// backup failed.
+	Failure string `bson:"failure,omitempty"`
+
 	// origin
 
 	Model    string         `bson:"model"`
@@ -124,6 +138,9 @@ func docAsMetadata(doc *storageMetaDoc) *Metadata {
 	meta := NewMetadata()
 	meta.Started = metadocUnixToTime(doc.Started)
 	meta.Notes = doc.Notes
+	meta.Scheduled = doc.Scheduled
+	meta.Failure = doc.Failure
+	meta.Encrypted = doc.Encrypted
 
 	meta.Origin.Model = doc.Model
 	meta.Origin.Machine = doc.Machine
@@ -175,6 +192,9 @@ func newStorageMetaDoc(meta *Metadata) storageMetaDoc {
 		doc.Finished = metadocTimeToUnix(*meta.Finished)
 	}
 	doc.Notes = meta.Notes
+	doc.Scheduled = meta.Scheduled
+	doc.Failure = meta.Failure
+	doc.Encrypted = meta.Encrypted
 
 	doc.Model = meta.Origin.Model
 	doc.Machine = meta.Origin.Machine
@@ -373,6 +393,24 @@ func setStorageStoredTime(dbWrap *storageDBWrapper, id string, stored time.Time)
 	return nil
 }
 
+// setStorageLocation updates the backup metadata associated with "id"
+// to record where its archive was stored. If "id" does not match any
+// stored records, an error satisfying juju/errors.IsNotFound() is
+// returned.
+func setStorageLocation(dbWrap *storageDBWrapper, id, storageType, location string) error {
+	op := dbWrap.txnOpUpdate(id,
+		bson.DocElem{"storagetype", storageType},
+		bson.DocElem{"storagelocation", location},
+	)
+	if err := dbWrap.runTransaction([]txn.Op{op}); err != nil {
+		if errors.Cause(err) == txn.ErrAborted {
+			return errors.NotFoundf("backup metadata %q", id)
+		}
+		return errors.Annotate(err, "while running transaction")
+	}
+	return nil
+}
+
 //---------------------------
 // metadata storage
 
@@ -521,6 +559,153 @@ func (s *backupBlobStorage) Close() error {
 	return s.dbWrap.Close()
 }
 
+// routedFileStorage stores new backup archives in the storage selected
+// by the controller config, and reads and removes existing archives
+// from wherever their metadata records they were stored.
+type routedFileStorage struct {
+	dbWrap           *storageDBWrapper
+	blobs            filestorage.RawFileStorage
+	controllerConfig func() (controller.Config, error)
+}
+
+func newRoutedFileStorage(
+	dbWrap *storageDBWrapper,
+	blobs filestorage.RawFileStorage,
+	controllerConfig func() (controller.Config, error),
+) filestorage.RawFileStorage {
+	return &routedFileStorage{
+		dbWrap:           dbWrap.Copy(),
+		blobs:            blobs,
+		controllerConfig: controllerConfig,
+	}
+}
+
+// target returns the raw storage for the given storage type and
+// location. The caller must close the returned storage.
+func (s *routedFileStorage) target(storageType, location string) (filestorage.RawFileStorage, error) {
+	switch storageType {
+	case "", controller.BackupStorageMongo:
+		return nopCloser{s.blobs}, nil
+	case controller.BackupStorageLocal:
+		return newLocalFileStorage(location, s.dbWrap.modelUUID), nil
+	case controller.BackupStorageS3:
+		cfg, err := s.controllerConfig()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return newS3FileStorage(cfg, location, s.dbWrap.modelUUID)
+	}
+	return nil, errors.NotSupportedf("backup storage %q", storageType)
+}
+
+// metadata returns the metadata document of the identified archive.
+func (s *routedFileStorage) metadata(id string) (storageMetaDoc, error) {
+	dbWrap := s.dbWrap.Copy()
+	defer dbWrap.Close()
+
+	var doc storageMetaDoc
+	err := dbWrap.metadata(id, &doc)
+	return doc, errors.Trace(err)
+}
+
+// stored returns the raw storage holding the identified archive.
+func (s *routedFileStorage) stored(id string) (filestorage.RawFileStorage, error) {
+	doc, err := s.metadata(id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return s.target(doc.StorageType, doc.StorageLocation)
+}
+
+// File returns the identified file from storage.
+func (s *routedFileStorage) File(id string) (io.ReadCloser, error) {
+	stor, err := s.stored(id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer stor.Close()
+	return stor.File(id)
+}
+
+// AddFile adds the file to the storage selected by the controller
+// config, and records its location in the backup metadata.
+func (s *routedFileStorage) AddFile(id string, file io.Reader, size int64) error {
+	cfg, err := s.controllerConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var location string
+	storageType := cfg.BackupStorage()
+	switch storageType {
+	case controller.BackupStorageLocal:
+		location = cfg.BackupStoragePath()
+	case controller.BackupStorageS3:
+		location = cfg.BackupS3Bucket()
+	}
+	stor, err := s.target(storageType, location)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer stor.Close()
+	if err := stor.AddFile(id, file, size); err != nil {
+		return errors.Trace(err)
+	}
+	if storageType == controller.BackupStorageMongo {
+		return nil
+	}
+
+	dbWrap := s.dbWrap.Copy()
+	defer dbWrap.Close()
+	if err := setStorageLocation(dbWrap, id, storageType, location); err != nil {
+		if removeErr := stor.RemoveFile(id); removeErr != nil {
+			logger.Errorf("cannot remove backup archive %q: %v", id, removeErr)
+		}
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// RemoveFile removes the identified file from storage.
+func (s *routedFileStorage) RemoveFile(id string) error {
+	doc, err := s.metadata(id)
+	if errors.IsNotFound(err) {
+		// Without metadata we cannot know where the archive was
+		// stored; fall back to the database, which was the only
+		// storage before others were supported.
+		return s.blobs.RemoveFile(id)
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	if doc.Failure != "" {
+		// Failed scheduled backups have no archive.
+		return nil
+	}
+	stor, err := s.target(doc.StorageType, doc.StorageLocation)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer stor.Close()
+	return stor.RemoveFile(id)
+}
+
+// Close closes the storage.
+func (s *routedFileStorage) Close() error {
+	err := s.blobs.Close()
+	s.dbWrap.Close()
+	return errors.Trace(err)
+}
+
+// nopCloser prevents a shared storage from being closed by each
+// individual use of it.
+type nopCloser struct {
+	filestorage.RawFileStorage
+}
+
+// Close is a no-op.
+func (nopCloser) Close() error {
+	return nil
+}
+
 //---------------------------
 // backup storage
 
@@ -550,14 +735,18 @@ type DB interface {
 }
 
 // NewStorage returns a new FileStorage to use for storing backup
-// archives (and metadata).
+// archives (and metadata). Archives are stored in the controller's
+// database, a local directory or an S3-compatible object store, as
+// selected by the "backup-storage" controller config attribute;
+// metadata is always stored in the controller's database.
 func NewStorage(st DB) filestorage.FileStorage {
 	modelUUID := st.ModelTag().Id()
 	db := st.MongoSession().DB(storageDBName)
 	dbWrap := newStorageDBWrapper(db, storageMetaName, modelUUID)
 	defer dbWrap.Close()
 
-	files := newFileStorage(dbWrap, backupStorageRoot)
+	blobs := newFileStorage(dbWrap, backupStorageRoot)
+	files := newRoutedFileStorage(dbWrap, blobs, st.ControllerConfig)
 	docs := newMetadataStorage(dbWrap)
 	return filestorage.NewFileStorage(docs, files)
 }
@@ -0,0 +1,94 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/filestorage"
+)
+
+// localFileStorage stores backup archives in a directory on the
+// controller machine's filesystem, typically an NFS mount shared by
+// all controller machines. Archives for each controller model are
+// kept in their own subdirectory, so several controllers may share
+// the same directory.
+type localFileStorage struct {
+	dir string
+}
+
+func newLocalFileStorage(dir, modelUUID string) filestorage.RawFileStorage {
+	return &localFileStorage{
+		dir: filepath.Join(dir, modelUUID),
+	}
+}
+
+func (s *localFileStorage) path(id string) string {
+	return filepath.Join(s.dir, id+".tar.gz")
+}
+
+// File returns the identified file from storage.
+func (s *localFileStorage) File(id string) (io.ReadCloser, error) {
+	file, err := os.Open(s.path(id))
+	if os.IsNotExist(err) {
+		return nil, errors.NotFoundf("backup archive %q", id)
+	}
+	return file, errors.Trace(err)
+}
+
+// AddFile adds the file to storage. The archive is written to a
+// temporary file first, so that a partially written archive is never
+// mistaken for a complete one.
+func (s *localFileStorage) AddFile(id string, file io.Reader, size int64) (err error) {
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return errors.Annotate(err, "creating backup directory")
+	}
+	target := s.path(id)
+	if _, err := os.Stat(target); err == nil {
+		return errors.AlreadyExistsf("backup archive %q", id)
+	}
+
+	tmp, err := ioutil.TempFile(s.dir, id+".tmp")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() {
+		tmp.Close()
+		if err != nil {
+			os.Remove(tmp.Name())
+		}
+	}()
+	written, err := io.Copy(tmp, file)
+	if err != nil {
+		return errors.Annotate(err, "writing backup archive")
+	}
+	if written != size {
+		return errors.Errorf("backup archive size mismatch: expected %d bytes, wrote %d", size, written)
+	}
+	if err := tmp.Sync(); err != nil {
+		return errors.Trace(err)
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(os.Rename(tmp.Name(), target))
+}
+
+// RemoveFile removes the identified file from storage.
+func (s *localFileStorage) RemoveFile(id string) error {
+	err := os.Remove(s.path(id))
+	if os.IsNotExist(err) {
+		return errors.NotFoundf("backup archive %q", id)
+	}
+	return errors.Trace(err)
+}
+
+// Close closes the storage.
+func (s *localFileStorage) Close() error {
+	return nil
+}
@@ -0,0 +1,105 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"io"
+	"net/http"
+	"path"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/filestorage"
+	"gopkg.in/amz.v3/aws"
+	"gopkg.in/amz.v3/s3"
+
+	"github.com/juju/juju/controller"
+)
+
+const backupArchiveContentType = "application/x-gzip"
+
+// objectBucket is the subset of an S3 bucket's methods used to store
+// backup archives.
+type objectBucket interface {
+	PutReader(path string, r io.Reader, length int64, contType string, perm s3.ACL) error
+	GetReader(path string) (io.ReadCloser, error)
+	Del(path string) error
+}
+
+// newS3Bucket returns the named bucket of the S3-compatible object
+// store described by the controller config.
+var newS3Bucket = func(cfg controller.Config, name string) (objectBucket, error) {
+	auth := aws.Auth{
+		AccessKey: cfg.BackupS3AccessKey(),
+		SecretKey: cfg.BackupS3SecretKey(),
+	}
+	region := aws.Region{
+		Name:       cfg.BackupS3Region(),
+		S3Endpoint: cfg.BackupS3Endpoint(),
+		Sign:       aws.SignV4Factory(cfg.BackupS3Region(), "s3"),
+	}
+	bucket, err := s3.New(auth, region).Bucket(name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return bucket, nil
+}
+
+// s3FileStorage stores backup archives in a bucket of an S3-compatible
+// object store. Archives for each controller model are kept under
+// their own prefix, so several controllers may share the same bucket.
+type s3FileStorage struct {
+	bucket objectBucket
+	root   string
+}
+
+func newS3FileStorage(cfg controller.Config, bucketName, modelUUID string) (filestorage.RawFileStorage, error) {
+	bucket, err := newS3Bucket(cfg, bucketName)
+	if err != nil {
+		return nil, errors.Annotatef(err, "opening backup bucket %q", bucketName)
+	}
+	return &s3FileStorage{
+		bucket: bucket,
+		root:   path.Join(backupStorageRoot, modelUUID),
+	}, nil
+}
+
+func (s *s3FileStorage) path(id string) string {
+	return path.Join(s.root, id+".tar.gz")
+}
+
+// File returns the identified file from storage.
+func (s *s3FileStorage) File(id string) (io.ReadCloser, error) {
+	file, err := s.bucket.GetReader(s.path(id))
+	if isS3NotFound(err) {
+		return nil, errors.NotFoundf("backup archive %q", id)
+	}
+	return file, errors.Trace(err)
+}
+
+// AddFile adds the file to storage.
+func (s *s3FileStorage) AddFile(id string, file io.Reader, size int64) error {
+	err := s.bucket.PutReader(s.path(id), file, size, backupArchiveContentType, s3.Private)
+	return errors.Annotate(err, "uploading backup archive")
+}
+
+// RemoveFile removes the identified file from storage.
+func (s *s3FileStorage) RemoveFile(id string) error {
+	err := s.bucket.Del(s.path(id))
+	if isS3NotFound(err) {
+		return errors.NotFoundf("backup archive %q", id)
+	}
+	return errors.Trace(err)
+}
+
+// Close closes the storage.
+func (s *s3FileStorage) Close() error {
+	return nil
+}
+
+func isS3NotFound(err error) bool {
+	if s3err, ok := errors.Cause(err).(*s3.Error); ok {
+		return s3err.StatusCode == http.StatusNotFound
+	}
+	return false
+}
@@ -4,12 +4,18 @@
 package backups_test
 
 import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"path/filepath"
 	"time"
 
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
+	"gopkg.in/amz.v3/s3"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/controller"
 	"github.com/juju/juju/state/backups"
 	statetesting "github.com/juju/juju/state/testing"
 )
@@ -36,6 +42,9 @@ func (s *storageSuite) checkMeta(c *gc.C, meta, expected *backups.Metadata, id s
 		c.Check(meta.ID(), gc.Equals, id)
 	}
 	c.Check(meta.Notes, gc.Equals, expected.Notes)
+	c.Check(meta.Scheduled, gc.Equals, expected.Scheduled)
+	c.Check(meta.Failure, gc.Equals, expected.Failure)
+	c.Check(meta.Encrypted, gc.Equals, expected.Encrypted)
 	c.Check(meta.Started.Unix(), gc.Equals, expected.Started.Unix())
 	c.Check(meta.Checksum(), gc.Equals, expected.Checksum())
 	c.Check(meta.ChecksumFormat(), gc.Equals, expected.ChecksumFormat())
@@ -71,6 +80,43 @@ func (s *storageSuite) TestGetBackupMetadataFound(c *gc.C) {
 	s.checkMeta(c, meta, original, id)
 }
 
+func (s *storageSuite) TestGetBackupMetadataEncrypted(c *gc.C) {
+	original := s.metadata(c)
+	original.Encrypted = true
+	id, err := backups.AddBackupMetadata(s.State, original)
+	c.Assert(err, jc.ErrorIsNil)
+
+	meta, err := backups.GetBackupMetadata(s.State, id)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.checkMeta(c, meta, original, id)
+}
+
+func (s *storageSuite) TestGetBackupMetadataScheduled(c *gc.C) {
+	original := s.metadata(c)
+	original.Scheduled = true
+	id, err := backups.AddBackupMetadata(s.State, original)
+	c.Assert(err, jc.ErrorIsNil)
+
+	meta, err := backups.GetBackupMetadata(s.State, id)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.checkMeta(c, meta, original, id)
+}
+
+func (s *storageSuite) TestGetBackupMetadataFailed(c *gc.C) {
+	original := s.metadata(c)
+	original.Scheduled = true
+	original.Failure = "mongodump failed"
+	id, err := backups.AddBackupMetadata(s.State, original)
+	c.Assert(err, jc.ErrorIsNil)
+
+	meta, err := backups.GetBackupMetadata(s.State, id)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.checkMeta(c, meta, original, id)
+}
+
 func (s *storageSuite) TestGetBackupMetadataNotFound(c *gc.C) {
 	_, err := backups.GetBackupMetadata(s.State, "spam")
 
@@ -136,3 +182,139 @@ func (s *storageSuite) TestSetBackupStoredTimeNotFound(c *gc.C) {
 
 	c.Check(err, jc.Satisfies, errors.IsNotFound)
 }
+
+func (s *storageSuite) addArchive(c *gc.C, cfg controller.Config) (string, func() error) {
+	stor := backups.NewStorageWithConfig(s.State, func() (controller.Config, error) {
+		return cfg, nil
+	})
+	archive := []byte("<compressed archive data>")
+	meta := backups.NewMetadata()
+	meta.Origin.Model = s.State.ModelUUID()
+	meta.Origin.Machine = "0"
+	meta.Origin.Hostname = "localhost"
+	err := meta.MarkComplete(int64(len(archive)), "some hash")
+	c.Assert(err, jc.ErrorIsNil)
+
+	id, err := stor.Add(meta, bytes.NewReader(archive))
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(*gc.C) { stor.Close() })
+	return id, func() error { return stor.Remove(id) }
+}
+
+func (s *storageSuite) checkArchive(c *gc.C, cfg controller.Config, id string) {
+	stor := backups.NewStorageWithConfig(s.State, func() (controller.Config, error) {
+		return cfg, nil
+	})
+	defer stor.Close()
+	_, file, err := stor.Get(id)
+	c.Assert(err, jc.ErrorIsNil)
+	defer file.Close()
+	data, err := ioutil.ReadAll(file)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(data), gc.Equals, "<compressed archive data>")
+}
+
+func (s *storageSuite) TestStorageMongo(c *gc.C) {
+	cfg := controller.Config{}
+	id, remove := s.addArchive(c, cfg)
+	s.checkArchive(c, cfg, id)
+	c.Assert(remove(), jc.ErrorIsNil)
+}
+
+func (s *storageSuite) TestStorageFailedBackup(c *gc.C) {
+	stor := backups.NewStorageWithConfig(s.State, func() (controller.Config, error) {
+		return controller.Config{}, nil
+	})
+	defer stor.Close()
+	meta := s.metadata(c)
+	meta.Scheduled = true
+	meta.Failure = "mongodump failed"
+
+	// A failed backup is recorded without an archive, and can be
+	// removed.
+	id, err := stor.Add(meta, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	stored, err := stor.Metadata(id)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(stored.(*backups.Metadata).Failure, gc.Equals, "mongodump failed")
+
+	err = stor.Remove(id)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = stor.Metadata(id)
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *storageSuite) TestStorageLocal(c *gc.C) {
+	dir := c.MkDir()
+	cfg := controller.Config{
+		controller.BackupStorage:     controller.BackupStorageLocal,
+		controller.BackupStoragePath: dir,
+	}
+	id, remove := s.addArchive(c, cfg)
+	path := filepath.Join(dir, s.State.ModelUUID(), id+".tar.gz")
+	c.Assert(path, jc.IsNonEmptyFile)
+
+	// The archive is found where it was stored, even after the
+	// configured storage changes.
+	s.checkArchive(c, controller.Config{}, id)
+
+	c.Assert(remove(), jc.ErrorIsNil)
+	c.Assert(path, jc.DoesNotExist)
+}
+
+func (s *storageSuite) TestStorageS3(c *gc.C) {
+	bucket := &fakeBucket{objects: make(map[string][]byte)}
+	s.PatchValue(backups.NewS3Bucket, func(cfg controller.Config, name string) (backups.ObjectBucket, error) {
+		c.Check(cfg.BackupS3Endpoint(), gc.Equals, "https://s3.example.com")
+		c.Check(name, gc.Equals, "juju-backups")
+		return bucket, nil
+	})
+	cfg := controller.Config{
+		controller.BackupStorage:     controller.BackupStorageS3,
+		controller.BackupS3Endpoint:  "https://s3.example.com",
+		controller.BackupS3Bucket:    "juju-backups",
+		controller.BackupS3AccessKey: "access",
+		controller.BackupS3SecretKey: "secret",
+	}
+	id, remove := s.addArchive(c, cfg)
+	key := "backups/" + s.State.ModelUUID() + "/" + id + ".tar.gz"
+	c.Assert(bucket.objects, gc.HasLen, 1)
+	c.Assert(bucket.objects[key], gc.NotNil)
+
+	s.checkArchive(c, cfg, id)
+
+	c.Assert(remove(), jc.ErrorIsNil)
+	c.Assert(bucket.objects, gc.HasLen, 0)
+}
+
+type fakeBucket struct {
+	objects map[string][]byte
+}
+
+func (b *fakeBucket) PutReader(path string, r io.Reader, length int64, contType string, perm s3.ACL) error {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if int64(len(data)) != length {
+		return errors.Errorf("expected %d bytes, got %d", length, len(data))
+	}
+	b.objects[path] = data
+	return nil
+}
+
+func (b *fakeBucket) GetReader(path string) (io.ReadCloser, error) {
+	data, ok := b.objects[path]
+	if !ok {
+		return nil, &s3.Error{StatusCode: 404, Code: "NoSuchKey"}
+	}
+	return ioutil.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (b *fakeBucket) Del(path string) error {
+	if _, ok := b.objects[path]; !ok {
+		return &s3.Error{StatusCode: 404, Code: "NoSuchKey"}
+	}
+	delete(b.objects, path)
+	return nil
+}
@@ -4,6 +4,7 @@
 package testing
 
 import (
+	"crypto/rsa"
 	"io"
 
 	"github.com/juju/errors"
@@ -53,6 +54,8 @@ type FakeBackups struct {
 	KeepCopy bool
 	// NoDownload holds the noDownload bool that was passed in.
 	NoDownload bool
+	// EncryptionKey holds the encryption key that was passed in.
+	EncryptionKey *rsa.PublicKey
 }
 
 var _ backups.Backups = (*FakeBackups)(nil)
@@ -64,6 +67,7 @@ func (b *FakeBackups) Create(
 	paths *backups.Paths,
 	dbInfo *backups.DBInfo,
 	keepCopy, noDownload bool,
+	encryptionKey *rsa.PublicKey,
 ) (string, error) {
 	b.Calls = append(b.Calls, "Create")
 
@@ -72,6 +76,7 @@ func (b *FakeBackups) Create(
 	b.MetaArg = meta
 	b.KeepCopy = keepCopy
 	b.NoDownload = noDownload
+	b.EncryptionKey = encryptionKey
 
 	if b.Meta != nil {
 		*meta = *b.Meta
@@ -7,6 +7,9 @@ import (
 	"archive/tar"
 	"bytes"
 	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"io"
 	"path"
 	"strings"
@@ -55,6 +58,16 @@ func (f *File) AddToArchive(archive *tar.Writer) error {
 
 // NewArchive returns a new archive file containing the files.
 func NewArchive(meta *backups.Metadata, files, dump []File) (*bytes.Buffer, error) {
+	return newArchive(meta, files, dump, false)
+}
+
+// NewArchiveWithManifest returns a new archive file containing the
+// files, and a manifest of their digests.
+func NewArchiveWithManifest(meta *backups.Metadata, files, dump []File) (*bytes.Buffer, error) {
+	return newArchive(meta, files, dump, true)
+}
+
+func newArchive(meta *backups.Metadata, files, dump []File, withManifest bool) (*bytes.Buffer, error) {
 	dirs := set.NewStrings()
 	var sysFiles []File
 	for _, file := range files {
@@ -125,6 +138,27 @@ func NewArchive(meta *backups.Metadata, files, dump []File) (*bytes.Buffer, erro
 		)
 	}
 
+	if withManifest {
+		manifest := backups.Manifest{
+			FormatVersion: backups.ArchiveFormatVersion,
+			Files:         make(map[string]string),
+		}
+		for _, file := range topfiles {
+			if !file.IsDir {
+				digest := sha256.Sum256([]byte(file.Content))
+				manifest.Files[file.Name] = hex.EncodeToString(digest[:])
+			}
+		}
+		data, err := json.Marshal(manifest)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		topfiles = append(topfiles, File{
+			Name:    "juju-backup/manifest.json",
+			Content: string(data),
+		})
+	}
+
 	var arFile bytes.Buffer
 	compressed := gzip.NewWriter(&arFile)
 	defer compressed.Close()
@@ -4,10 +4,13 @@
 package state
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	jujutxn "github.com/juju/txn"
 	"gopkg.in/juju/charm.v6"
 	"gopkg.in/juju/names.v2"
+	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
 	"gopkg.in/mgo.v2/txn"
 
@@ -50,6 +53,12 @@ type cleanupDoc struct {
 	Kind   cleanupKind   `bson:"kind"`
 	Prefix string        `bson:"prefix"`
 	Args   []*cleanupArg `bson:"args,omitempty"`
+
+	// Attempts, LastError and LastAttempt record the cleanup's
+	// failures, so that cleanups that are stuck can be seen.
+	Attempts    int    `bson:"attempts,omitempty"`
+	LastError   string `bson:"last-error,omitempty"`
+	LastAttempt int64  `bson:"last-attempt,omitempty"`
 }
 
 type cleanupArg struct {
@@ -115,67 +124,221 @@ func (st *State) Cleanup() (err error) {
 	iter := cleanups.Find(nil).Iter()
 	defer closeIter(iter, &err, "reading cleanup document")
 	for iter.Next(&doc) {
-		var err error
 		logger.Debugf("model %v cleanup: %v(%q)", modelId, doc.Kind, doc.Prefix)
-		args := make([]bson.Raw, len(doc.Args))
-		for i, arg := range doc.Args {
-			args[i] = arg.Value.(bson.Raw)
-		}
-		switch doc.Kind {
-		case cleanupRelationSettings:
-			err = st.cleanupRelationSettings(doc.Prefix)
-		case cleanupCharm:
-			err = st.cleanupCharm(doc.Prefix)
-		case cleanupUnitsForDyingApplication:
-			err = st.cleanupUnitsForDyingApplication(doc.Prefix, args)
-		case cleanupDyingUnit:
-			err = st.cleanupDyingUnit(doc.Prefix, args)
-		case cleanupDyingUnitResources:
-			err = st.cleanupDyingUnitResources(doc.Prefix)
-		case cleanupRemovedUnit:
-			err = st.cleanupRemovedUnit(doc.Prefix)
-		case cleanupApplicationsForDyingModel:
-			err = st.cleanupApplicationsForDyingModel()
-		case cleanupDyingMachine:
-			err = st.cleanupDyingMachine(doc.Prefix)
-		case cleanupForceDestroyedMachine:
-			err = st.cleanupForceDestroyedMachine(doc.Prefix)
-		case cleanupAttachmentsForDyingStorage:
-			err = st.cleanupAttachmentsForDyingStorage(doc.Prefix)
-		case cleanupAttachmentsForDyingVolume:
-			err = st.cleanupAttachmentsForDyingVolume(doc.Prefix)
-		case cleanupAttachmentsForDyingFilesystem:
-			err = st.cleanupAttachmentsForDyingFilesystem(doc.Prefix)
-		case cleanupModelsForDyingController:
-			err = st.cleanupModelsForDyingController(args)
-		case cleanupMachinesForDyingModel: // IAAS models only
-			err = st.cleanupMachinesForDyingModel()
-		case cleanupResourceBlob:
-			err = st.cleanupResourceBlob(doc.Prefix)
-		case cleanupStorageForDyingModel:
-			err = st.cleanupStorageForDyingModel(args)
-		default:
-			err = errors.Errorf("unknown cleanup kind %q", doc.Kind)
-		}
-		if err != nil {
+		if err := st.runCleanup(&doc, false); err != nil {
 			logger.Warningf(
-				"cleanup failed in model %v for %v(%q): %v",
-				modelUUID, doc.Kind, doc.Prefix, err,
+				"cleanup failed in model %v for %v(%q) after %d attempts: %v",
+				modelUUID, doc.Kind, doc.Prefix, doc.Attempts+1, err,
 			)
+			// Failing to record the failure mustn't stop the
+			// remaining cleanups from running.
+			if err := st.recordCleanupFailure(&doc, err); err != nil {
+				logger.Errorf("cannot record cleanup failure in model %v for %v(%q): %v",
+					modelUUID, doc.Kind, doc.Prefix, err)
+			}
 			continue
 		}
-		ops := []txn.Op{{
-			C:      cleanupsC,
-			Id:     doc.DocID,
-			Remove: true,
-		}}
-		if err := st.db().RunTransaction(ops); err != nil {
+		if err := st.removeCleanup(doc.DocID); err != nil {
 			return errors.Annotate(err, "cannot remove empty cleanup document")
 		}
 	}
 	return nil
 }
 
+// runCleanup runs the cleanup described by the supplied document. If
+// force is true, cleanups that have a forceful equivalent run that
+// instead: a dying machine is cleaned up as if it had been
+// force-destroyed.
+func (st *State) runCleanup(doc *cleanupDoc, force bool) error {
+	args := make([]bson.Raw, len(doc.Args))
+	for i, arg := range doc.Args {
+		args[i] = arg.Value.(bson.Raw)
+	}
+	kind := doc.Kind
+	if force && kind == cleanupDyingMachine {
+		kind = cleanupForceDestroyedMachine
+	}
+	switch kind {
+	case cleanupRelationSettings:
+		return st.cleanupRelationSettings(doc.Prefix)
+	case cleanupCharm:
+		return st.cleanupCharm(doc.Prefix)
+	case cleanupUnitsForDyingApplication:
+		return st.cleanupUnitsForDyingApplication(doc.Prefix, args)
+	case cleanupDyingUnit:
+		return st.cleanupDyingUnit(doc.Prefix, args)
+	case cleanupDyingUnitResources:
+		return st.cleanupDyingUnitResources(doc.Prefix)
+	case cleanupRemovedUnit:
+		return st.cleanupRemovedUnit(doc.Prefix)
+	case cleanupApplicationsForDyingModel:
+		return st.cleanupApplicationsForDyingModel()
+	case cleanupDyingMachine:
+		return st.cleanupDyingMachine(doc.Prefix)
+	case cleanupForceDestroyedMachine:
+		return st.cleanupForceDestroyedMachine(doc.Prefix)
+	case cleanupAttachmentsForDyingStorage:
+		return st.cleanupAttachmentsForDyingStorage(doc.Prefix)
+	case cleanupAttachmentsForDyingVolume:
+		return st.cleanupAttachmentsForDyingVolume(doc.Prefix)
+	case cleanupAttachmentsForDyingFilesystem:
+		return st.cleanupAttachmentsForDyingFilesystem(doc.Prefix)
+	case cleanupModelsForDyingController:
+		return st.cleanupModelsForDyingController(args)
+	case cleanupMachinesForDyingModel: // IAAS models only
+		return st.cleanupMachinesForDyingModel()
+	case cleanupResourceBlob:
+		return st.cleanupResourceBlob(doc.Prefix)
+	case cleanupStorageForDyingModel:
+		return st.cleanupStorageForDyingModel(args)
+	default:
+		return errors.Errorf("unknown cleanup kind %q", doc.Kind)
+	}
+}
+
+// recordCleanupFailure counts a failed attempt to run the cleanup, and
+// records the error it failed with, so that stuck cleanups can be
+// found and dealt with. The update is ignored by WatchCleanups, so it
+// doesn't cause the cleanup to be retried straight away.
+func (st *State) recordCleanupFailure(doc *cleanupDoc, cleanupErr error) error {
+	ops := []txn.Op{{
+		C:      cleanupsC,
+		Id:     doc.DocID,
+		Assert: txn.DocExists,
+		Update: bson.D{
+			{"$inc", bson.D{{"attempts", 1}}},
+			{"$set", bson.D{
+				{"last-error", cleanupErr.Error()},
+				{"last-attempt", st.clock().Now().UnixNano()},
+			}},
+		},
+	}}
+	err := st.db().RunTransaction(ops)
+	if err == txn.ErrAborted {
+		// The cleanup has been cancelled or run elsewhere.
+		return nil
+	}
+	return errors.Trace(err)
+}
+
+func (st *State) removeCleanup(id string) error {
+	ops := []txn.Op{{
+		C:      cleanupsC,
+		Id:     id,
+		Remove: true,
+	}}
+	return st.db().RunTransaction(ops)
+}
+
+// CleanupInfo describes a cleanup that has yet to complete.
+type CleanupInfo struct {
+	// Id uniquely identifies the cleanup within the model.
+	Id string
+
+	// Kind describes what the cleanup does.
+	Kind string
+
+	// Target identifies the entity the cleanup applies to, if any.
+	Target string
+
+	// Attempts is the number of times the cleanup has been run
+	// and failed.
+	Attempts int
+
+	// LastError is the error the cleanup last failed with.
+	LastError string
+
+	// LastAttempt is when the cleanup last failed. It is zero if
+	// the cleanup has not failed.
+	LastAttempt time.Time
+}
+
+// Cleanups returns the cleanups queued for the model, including those
+// that have failed and will be retried.
+func (st *State) Cleanups() ([]CleanupInfo, error) {
+	cleanups, closer := st.db().GetCollection(cleanupsC)
+	defer closer()
+
+	var docs []cleanupDoc
+	if err := cleanups.Find(nil).Sort("_id").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "reading cleanup documents")
+	}
+	result := make([]CleanupInfo, len(docs))
+	for i, doc := range docs {
+		result[i] = CleanupInfo{
+			Id:        st.localID(doc.DocID),
+			Kind:      string(doc.Kind),
+			Target:    doc.Prefix,
+			Attempts:  doc.Attempts,
+			LastError: doc.LastError,
+		}
+		if doc.LastAttempt != 0 {
+			result[i].LastAttempt = time.Unix(0, doc.LastAttempt).UTC()
+		}
+	}
+	return result, nil
+}
+
+func (st *State) cleanup(id string) (*cleanupDoc, error) {
+	cleanups, closer := st.db().GetCollection(cleanupsC)
+	defer closer()
+
+	var doc cleanupDoc
+	err := cleanups.FindId(id).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("cleanup %q", id)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "reading cleanup %q", id)
+	}
+	return &doc, nil
+}
+
+// ForceCleanup runs the cleanup with the supplied id immediately,
+// forcefully where the cleanup allows it; see runCleanup. The cleanup is
+// removed if it succeeds. If it fails, the failure is recorded as for
+// any other attempt, the cleanup remains queued, and the error is
+// returned.
+func (st *State) ForceCleanup(id string) error {
+	doc, err := st.cleanup(id)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := st.runCleanup(doc, true); err != nil {
+		if err := st.recordCleanupFailure(doc, err); err != nil {
+			return errors.Annotate(err, "cannot record cleanup failure")
+		}
+		return errors.Annotatef(err, "%s cleanup %q failed", doc.Kind, id)
+	}
+	err = st.removeCleanup(doc.DocID)
+	if err == txn.ErrAborted {
+		// The cleanup was removed while it was running.
+		return nil
+	}
+	return errors.Annotate(err, "cannot remove cleanup document")
+}
+
+// CancelCleanup removes the cleanup with the supplied id without running
+// it. Whatever the cleanup would have done must then be done some other
+// way, if at all.
+func (st *State) CancelCleanup(id string) error {
+	doc, err := st.cleanup(id)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	logger.Infof("cancelling %v cleanup %q for %q", doc.Kind, id, doc.Prefix)
+	ops := []txn.Op{{
+		C:      cleanupsC,
+		Id:     doc.DocID,
+		Assert: txn.DocExists,
+		Remove: true,
+	}}
+	err = st.db().RunTransaction(ops)
+	if err == txn.ErrAborted {
+		return errors.NotFoundf("cleanup %q", id)
+	}
+	return errors.Annotatef(err, "cannot cancel cleanup %q", id)
+}
+
 func (st *State) cleanupResourceBlob(storagePath string) error {
 	// Ignore attempts to clean up a placeholder resource.
 	if storagePath == "" {
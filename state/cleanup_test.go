@@ -933,6 +933,99 @@ func (s *CleanupSuite) TestCleanupIDSanity(c *gc.C) {
 	s.assertCleanupRuns(c)
 }
 
+func (s *CleanupSuite) TestCleanupFailureRecorded(c *gc.C) {
+	err := state.AddCleanup(s.State, "bogus", "thing")
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertCleanupRuns(c)
+	s.assertCleanupRuns(c)
+	cleanups, err := s.State.Cleanups()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cleanups, gc.HasLen, 1)
+	c.Assert(cleanups[0].Id, gc.Not(gc.Equals), "")
+	c.Assert(cleanups[0].Kind, gc.Equals, "bogus")
+	c.Assert(cleanups[0].Target, gc.Equals, "thing")
+	c.Assert(cleanups[0].Attempts, gc.Equals, 2)
+	c.Assert(cleanups[0].LastError, gc.Equals, `unknown cleanup kind "bogus"`)
+	c.Assert(cleanups[0].LastAttempt.IsZero(), jc.IsFalse)
+}
+
+func (s *CleanupSuite) TestCleanupsNotAttempted(c *gc.C) {
+	app := s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	err := app.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+
+	cleanups, err := s.State.Cleanups()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cleanups, gc.Not(gc.HasLen), 0)
+	for _, cleanup := range cleanups {
+		c.Check(cleanup.Attempts, gc.Equals, 0)
+		c.Check(cleanup.LastError, gc.Equals, "")
+		c.Check(cleanup.LastAttempt.IsZero(), jc.IsTrue)
+	}
+	s.assertCleanupRuns(c)
+}
+
+func (s *CleanupSuite) TestCancelCleanup(c *gc.C) {
+	err := state.AddCleanup(s.State, "bogus", "thing")
+	c.Assert(err, jc.ErrorIsNil)
+	cleanups, err := s.State.Cleanups()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cleanups, gc.HasLen, 1)
+
+	err = s.State.CancelCleanup(cleanups[0].Id)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertDoesNotNeedCleanup(c)
+
+	err = s.State.CancelCleanup(cleanups[0].Id)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *CleanupSuite) TestForceCleanupFails(c *gc.C) {
+	err := state.AddCleanup(s.State, "bogus", "thing")
+	c.Assert(err, jc.ErrorIsNil)
+	cleanups, err := s.State.Cleanups()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cleanups, gc.HasLen, 1)
+
+	err = s.State.ForceCleanup(cleanups[0].Id)
+	c.Assert(err, gc.ErrorMatches, `bogus cleanup ".*" failed: unknown cleanup kind "bogus"`)
+	cleanups, err = s.State.Cleanups()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cleanups, gc.HasLen, 1)
+	c.Assert(cleanups[0].Attempts, gc.Equals, 1)
+}
+
+func (s *CleanupSuite) TestForceCleanupNotFound(c *gc.C) {
+	err := s.State.ForceCleanup("deadbeef")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, `cleanup "deadbeef" not found`)
+}
+
+func (s *CleanupSuite) TestForceCleanupDyingMachine(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	mysql := s.AddTestingApplication(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	unit, err := mysql.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(machine)
+	c.Assert(err, jc.ErrorIsNil)
+	err = state.AddCleanup(s.State, "dyingMachine", machine.Id())
+	c.Assert(err, jc.ErrorIsNil)
+
+	cleanups, err := s.State.Cleanups()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cleanups, gc.HasLen, 1)
+	err = s.State.ForceCleanup(cleanups[0].Id)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The machine is cleaned up as if it had been force-destroyed:
+	// its units are removed and it is left dead for the provisioner.
+	err = unit.Refresh()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	assertLife(c, machine, state.Dead)
+}
+
 func (s *CleanupSuite) assertCleanupRuns(c *gc.C) {
 	err := s.State.Cleanup()
 	c.Assert(err, jc.ErrorIsNil)
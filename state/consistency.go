@@ -0,0 +1,623 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v6"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// The consistency checks that can be run against a model.
+const (
+	// CheckUnitCount checks that each application's unit count
+	// matches the number of its units.
+	CheckUnitCount = "unit-count"
+
+	// CheckRelationCount checks that each application's relation
+	// count matches the number of relations it takes part in.
+	CheckRelationCount = "relation-count"
+
+	// CheckRelationUnitCount checks that each relation's unit
+	// count matches the number of units in its scopes.
+	CheckRelationUnitCount = "relation-unit-count"
+
+	// CheckCharmRefcount checks that the reference counts of
+	// charms and their per-application settings match the
+	// applications and units using them.
+	CheckCharmRefcount = "charm-refcount"
+
+	// CheckRelationEndpoints checks that every application a
+	// relation refers to exists.
+	CheckRelationEndpoints = "relation-endpoints"
+
+	// CheckUnitReferences checks that the application, principal
+	// and machine of every unit exist.
+	CheckUnitReferences = "unit-references"
+
+	// CheckOrphanedSettings checks that every application and
+	// relation settings document has an owner.
+	CheckOrphanedSettings = "orphaned-settings"
+
+	// CheckPendingCleanups reports cleanups that have failed, or
+	// that have not run for longer than pendingCleanupAge.
+	CheckPendingCleanups = "pending-cleanups"
+)
+
+// pendingCleanupAge is how long a cleanup that has not failed may be
+// queued before CheckPendingCleanups reports it. Cleanups normally run
+// within seconds of being queued.
+const pendingCleanupAge = 10 * time.Minute
+
+// Inconsistency describes a problem found in a model's documents by
+// CheckConsistency.
+type Inconsistency struct {
+	// Check is the name of the check that found the problem.
+	Check string
+
+	// Collection is the collection holding the inconsistent
+	// document.
+	Collection string
+
+	// Id is the model-local id of the inconsistent document.
+	Id string
+
+	// Message describes the problem.
+	Message string
+
+	// Repairable reports whether RepairConsistency can safely fix
+	// the problem.
+	Repairable bool
+
+	// Repaired reports whether the problem was fixed.
+	Repaired bool
+
+	repairOps []txn.Op
+}
+
+// CheckConsistency looks for documents in the model that are
+// inconsistent with each other, such as reference counts that don't
+// match the references, settings without owners and references to
+// removed entities. Nothing is changed.
+func (st *State) CheckConsistency() ([]Inconsistency, error) {
+	checker, err := newConsistencyChecker(st)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	results := checker.check()
+	for i := range results {
+		results[i].repairOps = nil
+	}
+	return results, nil
+}
+
+// RepairConsistency checks the model as CheckConsistency does, and
+// then fixes each inconsistency that can be fixed safely: counts are
+// set to the number of references found and orphaned settings are
+// removed. Each repair asserts that the documents involved have not
+// changed since they were checked; a repair that fails that assertion
+// is left unrepaired.
+func (st *State) RepairConsistency() ([]Inconsistency, error) {
+	checker, err := newConsistencyChecker(st)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	results := checker.check()
+	for i, result := range results {
+		results[i].repairOps = nil
+		if !result.Repairable {
+			continue
+		}
+		err := st.db().RunTransaction(result.repairOps)
+		if err == txn.ErrAborted {
+			logger.Warningf("not repairing %s %q: changed since checked", result.Collection, result.Id)
+			continue
+		} else if err != nil {
+			return nil, errors.Annotatef(err, "repairing %s %q", result.Collection, result.Id)
+		}
+		results[i].Repaired = true
+	}
+	return results, nil
+}
+
+type consistencyApplication struct {
+	Name          string     `bson:"name"`
+	CharmURL      *charm.URL `bson:"charmurl"`
+	UnitCount     int        `bson:"unitcount"`
+	RelationCount int        `bson:"relationcount"`
+	TxnRevno      int64      `bson:"txn-revno"`
+}
+
+type consistencyUnit struct {
+	Name        string     `bson:"name"`
+	Application string     `bson:"application"`
+	CharmURL    *charm.URL `bson:"charmurl"`
+	Principal   string     `bson:"principal"`
+	MachineId   string     `bson:"machineid"`
+}
+
+type consistencyRelation struct {
+	Key       string     `bson:"key"`
+	Id        int        `bson:"id"`
+	Endpoints []Endpoint `bson:"endpoints"`
+	UnitCount int        `bson:"unitcount"`
+	TxnRevno  int64      `bson:"txn-revno"`
+}
+
+// consistencyChecker holds the documents that the consistency checks
+// compare.
+type consistencyChecker struct {
+	applications       map[string]consistencyApplication
+	remoteApplications set.Strings
+	units              map[string]consistencyUnit
+	machines           set.Strings
+	relations          map[int]consistencyRelation
+	scopeCounts        map[int]int
+	refcounts          map[string]int
+	settings           []string
+	cleanups           []cleanupDoc
+	localID            func(string) string
+	now                time.Time
+}
+
+func newConsistencyChecker(st *State) (*consistencyChecker, error) {
+	c := &consistencyChecker{
+		applications:       make(map[string]consistencyApplication),
+		remoteApplications: set.NewStrings(),
+		units:              make(map[string]consistencyUnit),
+		machines:           set.NewStrings(),
+		relations:          make(map[int]consistencyRelation),
+		scopeCounts:        make(map[int]int),
+		refcounts:          make(map[string]int),
+		localID:            st.localID,
+		now:                st.clock().Now(),
+	}
+	db := st.db()
+
+	var app consistencyApplication
+	if err := readAll(db, applicationsC, &app, func() {
+		c.applications[app.Name] = app
+	}); err != nil {
+		return nil, errors.Trace(err)
+	}
+	var named struct {
+		Name string `bson:"name"`
+	}
+	if err := readAll(db, remoteApplicationsC, &named, func() {
+		c.remoteApplications.Add(named.Name)
+	}); err != nil {
+		return nil, errors.Trace(err)
+	}
+	var unit consistencyUnit
+	if err := readAll(db, unitsC, &unit, func() {
+		c.units[unit.Name] = unit
+	}); err != nil {
+		return nil, errors.Trace(err)
+	}
+	var machine struct {
+		Id string `bson:"machineid"`
+	}
+	if err := readAll(db, machinesC, &machine, func() {
+		c.machines.Add(machine.Id)
+	}); err != nil {
+		return nil, errors.Trace(err)
+	}
+	var relation consistencyRelation
+	if err := readAll(db, relationsC, &relation, func() {
+		c.relations[relation.Id] = relation
+	}); err != nil {
+		return nil, errors.Trace(err)
+	}
+	var scope relationScopeDoc
+	if err := readAll(db, relationScopesC, &scope, func() {
+		if id, ok := relationIdFromKey(scope.Key); ok {
+			c.scopeCounts[id]++
+		}
+	}); err != nil {
+		return nil, errors.Trace(err)
+	}
+	var refcount struct {
+		DocID    string `bson:"_id"`
+		RefCount int    `bson:"refcount"`
+	}
+	if err := readAll(db, refcountsC, &refcount, func() {
+		c.refcounts[st.localID(refcount.DocID)] = refcount.RefCount
+	}); err != nil {
+		return nil, errors.Trace(err)
+	}
+	var settings struct {
+		DocID string `bson:"_id"`
+	}
+	if err := readAll(db, settingsC, &settings, func() {
+		c.settings = append(c.settings, st.localID(settings.DocID))
+	}); err != nil {
+		return nil, errors.Trace(err)
+	}
+	var cleanup cleanupDoc
+	if err := readAll(db, cleanupsC, &cleanup, func() {
+		c.cleanups = append(c.cleanups, cleanup)
+	}); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return c, nil
+}
+
+// readAll calls f after reading each document in the model's part of
+// the named collection into doc, which must be a pointer. The value
+// doc points to is zeroed before each document is read.
+func readAll(db Database, collectionName string, doc interface{}, f func()) error {
+	coll, closer := db.GetCollection(collectionName)
+	defer closer()
+
+	value := reflect.ValueOf(doc).Elem()
+	zero := reflect.Zero(value.Type())
+	iter := coll.Find(nil).Sort("_id").Iter()
+	defer iter.Close()
+	for iter.Next(doc) {
+		f()
+		value.Set(zero)
+	}
+	return errors.Annotatef(iter.Close(), "reading collection %q", collectionName)
+}
+
+// relationIdFromKey returns the relation id from a relation scope or
+// settings key, which look like r#<relation id>#...
+func relationIdFromKey(key string) (int, bool) {
+	parts := strings.Split(key, "#")
+	if len(parts) < 2 || parts[0] != "r" {
+		return 0, false
+	}
+	id, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, false
+	}
+	return id, true
+}
+
+func (c *consistencyChecker) check() []Inconsistency {
+	var results []Inconsistency
+	for _, check := range []func() []Inconsistency{
+		c.checkUnitCounts,
+		c.checkRelationCounts,
+		c.checkRelationUnitCounts,
+		c.checkCharmRefcounts,
+		c.checkRelationEndpoints,
+		c.checkUnitReferences,
+		c.checkOrphanedSettings,
+		c.checkPendingCleanups,
+	} {
+		results = append(results, check()...)
+	}
+	return results
+}
+
+func (c *consistencyChecker) sortedApplications() []consistencyApplication {
+	var names []string
+	for name := range c.applications {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	result := make([]consistencyApplication, len(names))
+	for i, name := range names {
+		result[i] = c.applications[name]
+	}
+	return result
+}
+
+func (c *consistencyChecker) sortedRelations() []consistencyRelation {
+	var ids []int
+	for id := range c.relations {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	result := make([]consistencyRelation, len(ids))
+	for i, id := range ids {
+		result[i] = c.relations[id]
+	}
+	return result
+}
+
+func (c *consistencyChecker) checkUnitCounts() []Inconsistency {
+	counts := make(map[string]int)
+	for _, unit := range c.units {
+		counts[unit.Application]++
+	}
+	var results []Inconsistency
+	for _, app := range c.sortedApplications() {
+		if app.UnitCount == counts[app.Name] {
+			continue
+		}
+		results = append(results, Inconsistency{
+			Check:      CheckUnitCount,
+			Collection: applicationsC,
+			Id:         app.Name,
+			Message: fmt.Sprintf("application %q has unit count %d but %d units",
+				app.Name, app.UnitCount, counts[app.Name]),
+			Repairable: true,
+			repairOps: []txn.Op{{
+				C:      applicationsC,
+				Id:     app.Name,
+				Assert: bson.D{{"txn-revno", app.TxnRevno}},
+				Update: bson.D{{"$set", bson.D{{"unitcount", counts[app.Name]}}}},
+			}},
+		})
+	}
+	return results
+}
+
+func (c *consistencyChecker) checkRelationCounts() []Inconsistency {
+	counts := make(map[string]int)
+	for _, relation := range c.relations {
+		apps := set.NewStrings()
+		for _, ep := range relation.Endpoints {
+			apps.Add(ep.ApplicationName)
+		}
+		for _, name := range apps.Values() {
+			counts[name]++
+		}
+	}
+	var results []Inconsistency
+	for _, app := range c.sortedApplications() {
+		if app.RelationCount == counts[app.Name] {
+			continue
+		}
+		results = append(results, Inconsistency{
+			Check:      CheckRelationCount,
+			Collection: applicationsC,
+			Id:         app.Name,
+			Message: fmt.Sprintf("application %q has relation count %d but %d relations",
+				app.Name, app.RelationCount, counts[app.Name]),
+			Repairable: true,
+			repairOps: []txn.Op{{
+				C:      applicationsC,
+				Id:     app.Name,
+				Assert: bson.D{{"txn-revno", app.TxnRevno}},
+				Update: bson.D{{"$set", bson.D{{"relationcount", counts[app.Name]}}}},
+			}},
+		})
+	}
+	return results
+}
+
+func (c *consistencyChecker) checkRelationUnitCounts() []Inconsistency {
+	var results []Inconsistency
+	for _, relation := range c.sortedRelations() {
+		count := c.scopeCounts[relation.Id]
+		if relation.UnitCount == count {
+			continue
+		}
+		results = append(results, Inconsistency{
+			Check:      CheckRelationUnitCount,
+			Collection: relationsC,
+			Id:         relation.Key,
+			Message: fmt.Sprintf("relation %q has unit count %d but %d units in scope",
+				relation.Key, relation.UnitCount, count),
+			Repairable: true,
+			repairOps: []txn.Op{{
+				C:      relationsC,
+				Id:     relation.Key,
+				Assert: bson.D{{"txn-revno", relation.TxnRevno}},
+				Update: bson.D{{"$set", bson.D{{"unitcount", count}}}},
+			}},
+		})
+	}
+	return results
+}
+
+func (c *consistencyChecker) checkCharmRefcounts() []Inconsistency {
+	// Each application and each unit holds a reference to its
+	// charm, and to the application's settings for that charm.
+	expected := make(map[string]int)
+	addRef := func(appName string, curl *charm.URL) {
+		if curl == nil {
+			return
+		}
+		expected[charmGlobalKey(curl)]++
+		expected[applicationCharmConfigKey(appName, curl)]++
+	}
+	for _, app := range c.applications {
+		addRef(app.Name, app.CharmURL)
+	}
+	for _, unit := range c.units {
+		addRef(unit.Application, unit.CharmURL)
+	}
+	keys := set.NewStrings()
+	for key := range expected {
+		keys.Add(key)
+	}
+	for key := range c.refcounts {
+		if strings.HasPrefix(key, "c#") || isApplicationCharmConfigKey(key) {
+			keys.Add(key)
+		}
+	}
+
+	settings := set.NewStrings(c.settings...)
+	var results []Inconsistency
+	for _, key := range keys.SortedValues() {
+		refcount, exists := c.refcounts[key]
+		want := expected[key]
+		if refcount == want {
+			continue
+		}
+		result := Inconsistency{
+			Check:      CheckCharmRefcount,
+			Collection: refcountsC,
+			Id:         key,
+			Message:    fmt.Sprintf("%q has refcount %d but %d references", key, refcount, want),
+			Repairable: true,
+		}
+		switch {
+		case !exists:
+			result.Message = fmt.Sprintf("%q has no refcount but %d references", key, want)
+			result.repairOps = []txn.Op{nsRefcounts.JustCreateOp(refcountsC, key, want)}
+		case want == 0 && !strings.HasPrefix(key, "c#"):
+			// Settings refcounts are removed along with
+			// their last reference, and the settings with
+			// them; charm refcounts are left for the charm
+			// cleanup. The settings of a removed application
+			// are removed by the orphaned settings check.
+			result.repairOps = []txn.Op{nsRefcounts.JustRemoveOp(refcountsC, key, refcount)}
+			appName := strings.SplitN(key, "#", 3)[1]
+			if _, ok := c.applications[appName]; ok && settings.Contains(key) {
+				result.repairOps = append(result.repairOps, removeSettingsOp(settingsC, key))
+			}
+		default:
+			result.repairOps = []txn.Op{{
+				C:      refcountsC,
+				Id:     key,
+				Assert: bson.D{{"refcount", refcount}},
+				Update: bson.D{{"$set", bson.D{{"refcount", want}}}},
+			}}
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// isApplicationCharmConfigKey returns whether the key is that of an
+// application's settings for a charm, a#<application>#<charm url>.
+func isApplicationCharmConfigKey(key string) bool {
+	parts := strings.SplitN(key, "#", 3)
+	if len(parts) != 3 || parts[0] != "a" {
+		return false
+	}
+	_, err := charm.ParseURL(parts[2])
+	return err == nil
+}
+
+func (c *consistencyChecker) applicationExists(name string) bool {
+	if _, ok := c.applications[name]; ok {
+		return true
+	}
+	return c.remoteApplications.Contains(name)
+}
+
+func (c *consistencyChecker) checkRelationEndpoints() []Inconsistency {
+	var results []Inconsistency
+	for _, relation := range c.sortedRelations() {
+		for _, ep := range relation.Endpoints {
+			if c.applicationExists(ep.ApplicationName) {
+				continue
+			}
+			results = append(results, Inconsistency{
+				Check:      CheckRelationEndpoints,
+				Collection: relationsC,
+				Id:         relation.Key,
+				Message: fmt.Sprintf("relation %q refers to missing application %q",
+					relation.Key, ep.ApplicationName),
+			})
+		}
+	}
+	return results
+}
+
+func (c *consistencyChecker) checkUnitReferences() []Inconsistency {
+	var names []string
+	for name := range c.units {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var results []Inconsistency
+	add := func(unitName, format string, args ...interface{}) {
+		results = append(results, Inconsistency{
+			Check:      CheckUnitReferences,
+			Collection: unitsC,
+			Id:         unitName,
+			Message:    fmt.Sprintf("unit %q refers to missing ", unitName) + fmt.Sprintf(format, args...),
+		})
+	}
+	for _, name := range names {
+		unit := c.units[name]
+		if _, ok := c.applications[unit.Application]; !ok {
+			add(name, "application %q", unit.Application)
+		}
+		if unit.Principal != "" {
+			if _, ok := c.units[unit.Principal]; !ok {
+				add(name, "principal unit %q", unit.Principal)
+			}
+		}
+		if unit.MachineId != "" && !c.machines.Contains(unit.MachineId) {
+			add(name, "machine %q", unit.MachineId)
+		}
+	}
+	return results
+}
+
+func (c *consistencyChecker) checkOrphanedSettings() []Inconsistency {
+	var results []Inconsistency
+	for _, key := range c.settings {
+		var message string
+		var ops []txn.Op
+		if id, ok := relationIdFromKey(key); ok {
+			if _, exists := c.relations[id]; exists {
+				continue
+			}
+			// Relation ids are never reused, so there's
+			// nothing to assert about the relation.
+			message = fmt.Sprintf("relation settings %q have no relation %d", key, id)
+		} else if parts := strings.Split(key, "#"); len(parts) >= 3 && parts[0] == "a" {
+			if _, exists := c.applications[parts[1]]; exists {
+				continue
+			}
+			message = fmt.Sprintf("application settings %q have no application %q", key, parts[1])
+			ops = append(ops, txn.Op{
+				C:      applicationsC,
+				Id:     parts[1],
+				Assert: txn.DocMissing,
+			})
+		} else {
+			continue
+		}
+		results = append(results, Inconsistency{
+			Check:      CheckOrphanedSettings,
+			Collection: settingsC,
+			Id:         key,
+			Message:    message,
+			Repairable: true,
+			repairOps:  append(ops, removeSettingsOp(settingsC, key)),
+		})
+	}
+	return results
+}
+
+func (c *consistencyChecker) checkPendingCleanups() []Inconsistency {
+	var results []Inconsistency
+	for _, doc := range c.cleanups {
+		id := c.localID(doc.DocID)
+		if doc.Attempts == 0 {
+			// Cleanup ids are object ids, which record when
+			// the cleanup was queued.
+			if !bson.IsObjectIdHex(id) {
+				continue
+			}
+			if c.now.Sub(bson.ObjectIdHex(id).Time()) < pendingCleanupAge {
+				continue
+			}
+		}
+		message := fmt.Sprintf("%s cleanup has not run", doc.Kind)
+		if doc.Prefix != "" {
+			message = fmt.Sprintf("%s cleanup for %q has not run", doc.Kind, doc.Prefix)
+		}
+		if doc.Attempts > 0 {
+			message = fmt.Sprintf("%s (failed %d times: %s)", message, doc.Attempts, doc.LastError)
+		}
+		results = append(results, Inconsistency{
+			Check:      CheckPendingCleanups,
+			Collection: cleanupsC,
+			Id:         id,
+			Message:    message,
+		})
+	}
+	return results
+}
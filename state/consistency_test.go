@@ -0,0 +1,242 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/state"
+)
+
+type ConsistencySuite struct {
+	ConnSuite
+	mysql *state.Application
+	unit  *state.Unit
+}
+
+var _ = gc.Suite(&ConsistencySuite{})
+
+func (s *ConsistencySuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.mysql = s.AddTestingApplication(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	wordpress := s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	eps, err := s.State.InferEndpoints("wordpress", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddRelation(eps...)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.unit, err = s.mysql.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = wordpress.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ConsistencySuite) runTransaction(c *gc.C, ops ...txn.Op) {
+	err := state.RunTransaction(s.State, ops)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ConsistencySuite) checkConsistent(c *gc.C) {
+	results, err := s.State.CheckConsistency()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 0)
+}
+
+func (s *ConsistencySuite) TestConsistent(c *gc.C) {
+	s.checkConsistent(c)
+}
+
+func (s *ConsistencySuite) TestUnitCount(c *gc.C) {
+	s.runTransaction(c, txn.Op{
+		C:      state.ApplicationsC,
+		Id:     "mysql",
+		Update: bson.D{{"$set", bson.D{{"unitcount", 5}}}},
+	})
+
+	results, err := s.State.CheckConsistency()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []state.Inconsistency{{
+		Check:      state.CheckUnitCount,
+		Collection: state.ApplicationsC,
+		Id:         "mysql",
+		Message:    `application "mysql" has unit count 5 but 1 units`,
+		Repairable: true,
+	}})
+}
+
+func (s *ConsistencySuite) TestRepairUnitCount(c *gc.C) {
+	s.runTransaction(c, txn.Op{
+		C:      state.ApplicationsC,
+		Id:     "mysql",
+		Update: bson.D{{"$set", bson.D{{"unitcount", 5}}}},
+	})
+
+	results, err := s.State.RepairConsistency()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Repaired, jc.IsTrue)
+	s.checkConsistent(c)
+
+	err = s.mysql.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.mysql.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkConsistent(c)
+}
+
+func (s *ConsistencySuite) TestRepairCharmRefcount(c *gc.C) {
+	curl, _ := s.mysql.CharmURL()
+	key := "c#" + curl.String()
+	s.runTransaction(c, txn.Op{
+		C:      state.RefcountsC,
+		Id:     key,
+		Update: bson.D{{"$set", bson.D{{"refcount", 7}}}},
+	})
+
+	results, err := s.State.CheckConsistency()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Check, gc.Equals, state.CheckCharmRefcount)
+	c.Assert(results[0].Id, gc.Equals, key)
+	c.Assert(results[0].Message, gc.Equals, `"`+key+`" has refcount 7 but 1 references`)
+
+	results, err = s.State.RepairConsistency()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Repaired, jc.IsTrue)
+	s.checkConsistent(c)
+}
+
+func (s *ConsistencySuite) TestRepairUnusedCharmSettings(c *gc.C) {
+	key := "a#mysql#cs:quantal/mysql-99"
+	s.runTransaction(c, txn.Op{
+		C:      state.SettingsC,
+		Id:     key,
+		Assert: txn.DocMissing,
+		Insert: bson.D{{"settings", bson.D{}}},
+	}, txn.Op{
+		C:      state.RefcountsC,
+		Id:     key,
+		Assert: txn.DocMissing,
+		Insert: bson.D{{"refcount", 1}},
+	})
+
+	results, err := s.State.RepairConsistency()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []state.Inconsistency{{
+		Check:      state.CheckCharmRefcount,
+		Collection: state.RefcountsC,
+		Id:         key,
+		Message:    `"` + key + `" has refcount 1 but 0 references`,
+		Repairable: true,
+		Repaired:   true,
+	}})
+	s.checkConsistent(c)
+
+	// The settings are removed along with their refcount.
+	s.runTransaction(c, txn.Op{
+		C:      state.SettingsC,
+		Id:     key,
+		Assert: txn.DocMissing,
+	})
+}
+
+func (s *ConsistencySuite) TestRepairOrphanedSettings(c *gc.C) {
+	s.runTransaction(c, txn.Op{
+		C:      state.SettingsC,
+		Id:     "a#gone#application",
+		Assert: txn.DocMissing,
+		Insert: bson.D{{"settings", bson.D{}}},
+	})
+
+	results, err := s.State.RepairConsistency()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []state.Inconsistency{{
+		Check:      state.CheckOrphanedSettings,
+		Collection: state.SettingsC,
+		Id:         "a#gone#application",
+		Message:    `application settings "a#gone#application" have no application "gone"`,
+		Repairable: true,
+		Repaired:   true,
+	}})
+	s.checkConsistent(c)
+}
+
+func (s *ConsistencySuite) TestUnitReferencesNotRepaired(c *gc.C) {
+	s.runTransaction(c, txn.Op{
+		C:      "units",
+		Id:     s.unit.Name(),
+		Update: bson.D{{"$set", bson.D{{"machineid", "42"}}}},
+	})
+
+	results, err := s.State.RepairConsistency()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []state.Inconsistency{{
+		Check:      state.CheckUnitReferences,
+		Collection: "units",
+		Id:         "mysql/0",
+		Message:    `unit "mysql/0" refers to missing machine "42"`,
+	}})
+}
+
+func (s *ConsistencySuite) addCleanup(c *gc.C, queued time.Time, attempts int) string {
+	id := bson.NewObjectIdWithTime(queued).Hex()
+	s.runTransaction(c, txn.Op{
+		C:      "cleanups",
+		Id:     id,
+		Assert: txn.DocMissing,
+		Insert: bson.D{
+			{"kind", "units"},
+			{"prefix", "mysql"},
+			{"attempts", attempts},
+			{"last-error", "boom"},
+		},
+	})
+	return id
+}
+
+func (s *ConsistencySuite) pendingCleanups(c *gc.C) []state.Inconsistency {
+	results, err := s.State.CheckConsistency()
+	c.Assert(err, jc.ErrorIsNil)
+	var pending []state.Inconsistency
+	for _, result := range results {
+		if result.Check == state.CheckPendingCleanups {
+			pending = append(pending, result)
+		}
+	}
+	return pending
+}
+
+func (s *ConsistencySuite) TestPendingCleanupsIgnoresNew(c *gc.C) {
+	s.Clock.Advance(24 * time.Hour)
+	s.addCleanup(c, s.Clock.Now().Add(-time.Minute), 0)
+	c.Assert(s.pendingCleanups(c), gc.HasLen, 0)
+}
+
+func (s *ConsistencySuite) TestPendingCleanupsOld(c *gc.C) {
+	s.Clock.Advance(24 * time.Hour)
+	id := s.addCleanup(c, s.Clock.Now().Add(-time.Hour), 0)
+	c.Assert(s.pendingCleanups(c), jc.DeepEquals, []state.Inconsistency{{
+		Check:      state.CheckPendingCleanups,
+		Collection: "cleanups",
+		Id:         id,
+		Message:    `units cleanup for "mysql" has not run`,
+	}})
+}
+
+func (s *ConsistencySuite) TestPendingCleanupsFailed(c *gc.C) {
+	s.Clock.Advance(24 * time.Hour)
+	id := s.addCleanup(c, s.Clock.Now(), 2)
+	c.Assert(s.pendingCleanups(c), jc.DeepEquals, []state.Inconsistency{{
+		Check:      state.CheckPendingCleanups,
+		Collection: "cleanups",
+		Id:         id,
+		Message:    `units cleanup for "mysql" has not run (failed 2 times: boom)`,
+	}})
+}
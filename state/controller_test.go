@@ -40,6 +40,15 @@ func (s *ControllerSuite) TestControllerAndModelConfigInitialisation(c *gc.C) {
 		controller.MaxPruneTxnPasses,
 		controller.MaxLogsSize,
 		controller.MaxLogsAge,
+		controller.BackupInterval,
+		controller.BackupStorage,
+		controller.BackupStoragePath,
+		controller.BackupS3Endpoint,
+		controller.BackupS3Region,
+		controller.BackupS3Bucket,
+		controller.BackupS3AccessKey,
+		controller.BackupS3SecretKey,
+		controller.BackupPublicKey,
 		controller.CAASOperatorImagePath,
 		controller.CharmStoreURL,
 		controller.Features,
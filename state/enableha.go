@@ -4,6 +4,8 @@
 package state
 
 import (
+	"strings"
+
 	"github.com/juju/errors"
 	"github.com/juju/replicaset"
 	jujutxn "github.com/juju/txn"
@@ -165,6 +167,87 @@ func (st *State) EnableHA(
 	return change, nil
 }
 
+// EnableReadReplicas adds read replica controller machines as necessary
+// to make the number of live read replicas equal to numReplicas. Read
+// replicas are controllers that are non-voting members of the replica
+// set; their API servers only serve read-only calls. The given
+// constraints and series will be attached to any new machines, which
+// are started according to the placement directives until they are
+// exhausted. Unlike EnableHA, existing machines cannot be converted
+// into read replicas.
+func (st *State) EnableReadReplicas(
+	numReplicas int, cons constraints.Value, series string, placement []string,
+) (ControllersChanges, error) {
+	if numReplicas < 0 {
+		return ControllersChanges{}, errors.New("number of read replicas must be non-negative")
+	}
+	if numReplicas > replicaset.MaxPeers {
+		return ControllersChanges{}, errors.Errorf("read replica count is too large (allowed %d)", replicaset.MaxPeers)
+	}
+	for _, s := range placement {
+		p, err := instance.ParsePlacement(s)
+		if err == nil && p.Scope == instance.MachineScope {
+			return ControllersChanges{}, errors.Errorf("cannot use existing machine %q as a read replica", p.Directive)
+		}
+	}
+	var change ControllersChanges
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		change = ControllersChanges{}
+		currentInfo, err := st.ControllerInfo()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, id := range currentInfo.MachineIds {
+			m, err := st.Machine(id)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if m.IsReadReplica() && m.Life() == Alive {
+				change.Maintained = append(change.Maintained, id)
+			}
+		}
+		if len(change.Maintained) > numReplicas {
+			return nil, errors.New("cannot reduce read replica count")
+		}
+		if len(change.Maintained) == numReplicas {
+			return nil, jujutxn.ErrNoOperations
+		}
+
+		var ops []txn.Op
+		mdocs := make([]*machineDoc, numReplicas-len(change.Maintained))
+		for i := range mdocs {
+			template := MachineTemplate{
+				Series: series,
+				Jobs: []MachineJob{
+					JobHostUnits,
+					JobManageModel,
+				},
+				Constraints: cons,
+				ReadReplica: true,
+			}
+			if i < len(placement) {
+				template.Placement = placement[i]
+			}
+			mdoc, addOps, err := st.addMachineOps(template)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			mdocs[i] = mdoc
+			ops = append(ops, addOps...)
+			change.Added = append(change.Added, mdoc.Id)
+		}
+		ssOps, err := st.maintainControllersOps(mdocs, currentInfo)
+		if err != nil {
+			return nil, errors.Annotate(err, "cannot prepare machine add operations")
+		}
+		return append(ops, ssOps...), nil
+	}
+	if err := st.db().Run(buildTxn); err != nil {
+		return ControllersChanges{}, errors.Annotate(err, "failed to create read replica machines")
+	}
+	return change, nil
+}
+
 // Change in controllers after the ensure availability txn has committed.
 type ControllersChanges struct {
 	Added      []string
@@ -194,7 +277,8 @@ func (st *State) enableHAIntentionOps(
 	}
 	// Use any placement directives that have been provided
 	// when adding new machines, until the directives have
-	// been all used up. Ignore constraints for provided machines.
+	// been all used up. Ignore constraints for provided machines,
+	// but not for those that are only placed in a zone.
 	// Set up a helper function to do the work required.
 	placementCount := 0
 	getPlacementConstraints := func() (string, constraints.Value) {
@@ -203,6 +287,9 @@ func (st *State) enableHAIntentionOps(
 		}
 		result := intent.placement[placementCount]
 		placementCount++
+		if isZonePlacement(result) {
+			return result, cons
+		}
 		return result, constraints.Value{}
 	}
 	mdocs := make([]*machineDoc, intent.newCount)
@@ -244,6 +331,12 @@ func (st *State) enableHAIntentionOps(
 	return ops, change, nil
 }
 
+// isZonePlacement reports whether the placement directive only
+// specifies the availability zone of a new machine.
+func isZonePlacement(placement string) bool {
+	return strings.HasPrefix(placement, "zone=")
+}
+
 type enableHAIntent struct {
 	newCount  int
 	placement []string
@@ -291,6 +384,10 @@ func (st *State) enableHAIntentions(info *ControllerInfo, placement []string) (*
 		if err != nil {
 			return nil, err
 		}
+		if m.IsReadReplica() {
+			// Read replicas never vote, so they can't be promoted.
+			continue
+		}
 		logger.Infof("machine %q, wants vote %v, has vote %v", m, m.WantsVote(), m.HasVote())
 		if m.WantsVote() {
 			intent.maintain = append(intent.maintain, m)
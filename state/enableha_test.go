@@ -154,6 +154,24 @@ func (s *EnableHASuite) TestEnableHALessPlacementThanNewCount(c *gc.C) {
 	s.assertControllerInfo(c, []string{"0", "1", "2"}, []string{"0", "1", "2"}, []string{"p1", "p2"})
 }
 
+func (s *EnableHASuite) TestEnableHAZonePlacementKeepsConstraints(c *gc.C) {
+	cons := constraints.MustParse("mem=4G")
+	placement := []string{"zone=az1", "p2", "zone=az3"}
+	changes, err := s.State.EnableHA(3, cons, "quantal", placement)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changes.Added, gc.HasLen, 3)
+	s.assertControllerInfo(c, []string{"0", "1", "2"}, []string{"0", "1", "2"}, placement)
+
+	expectedCons := []constraints.Value{cons, {}, cons}
+	for i, expected := range expectedCons {
+		m, err := s.State.Machine(fmt.Sprint(i))
+		c.Assert(err, jc.ErrorIsNil)
+		gotCons, err := m.Constraints()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(gotCons, gc.DeepEquals, expected)
+	}
+}
+
 func (s *EnableHASuite) TestEnableHAMockBootstrap(c *gc.C) {
 	// Testing based on lp:1748275 - Juju HA fails due to demotion of Machine 0
 	m0, err := s.State.AddMachine("quantal", state.JobHostUnits, state.JobManageModel)
@@ -516,3 +534,50 @@ func (s *EnableHASuite) TestRemoveControllerMachineRace(c *gc.C) {
 	c.Check(m0.HasVote(), jc.IsFalse)
 	c.Check(m0.Jobs(), gc.DeepEquals, []state.MachineJob{state.JobHostUnits, state.JobManageModel})
 }
+
+func (s *EnableHASuite) TestEnableReadReplicas(c *gc.C) {
+	_, err := s.State.AddMachine("quantal", state.JobHostUnits, state.JobManageModel)
+	c.Assert(err, jc.ErrorIsNil)
+
+	cons := constraints.MustParse("mem=4G")
+	changes, err := s.State.EnableReadReplicas(2, cons, "quantal", []string{"zone=az1"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changes.Added, jc.DeepEquals, []string{"1", "2"})
+	c.Assert(changes.Maintained, gc.HasLen, 0)
+	s.assertControllerInfo(c, []string{"0", "1", "2"}, []string{"0"}, []string{"", "zone=az1"})
+
+	for _, id := range changes.Added {
+		m, err := s.State.Machine(id)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(m.IsReadReplica(), jc.IsTrue)
+		c.Check(m.WantsVote(), jc.IsFalse)
+		gotCons, err := m.Constraints()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(gotCons, gc.DeepEquals, cons)
+	}
+
+	changes, err = s.State.EnableReadReplicas(2, cons, "quantal", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changes.Added, gc.HasLen, 0)
+
+	_, err = s.State.EnableReadReplicas(1, cons, "quantal", nil)
+	c.Assert(err, gc.ErrorMatches, "failed to create read replica machines: cannot reduce read replica count")
+}
+
+func (s *EnableHASuite) TestEnableHADoesNotPromoteReadReplicas(c *gc.C) {
+	_, err := s.State.AddMachine("quantal", state.JobHostUnits, state.JobManageModel)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.EnableReadReplicas(1, constraints.Value{}, "quantal", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	changes, err := s.State.EnableHA(3, constraints.Value{}, "quantal", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changes.Promoted, gc.HasLen, 0)
+	c.Assert(changes.Added, jc.DeepEquals, []string{"2", "3"})
+	s.assertControllerInfo(c, []string{"0", "1", "2", "3"}, []string{"0", "2", "3"}, nil)
+}
+
+func (s *EnableHASuite) TestEnableReadReplicasRejectsMachinePlacement(c *gc.C) {
+	_, err := s.State.EnableReadReplicas(1, constraints.Value{}, "quantal", []string{"3"})
+	c.Assert(err, gc.ErrorMatches, `cannot use existing machine "3" as a read replica`)
+}
@@ -47,6 +47,7 @@ const (
 	GUISettingsC      = guisettingsC
 	GlobalSettingsC   = globalSettingsC
 	SettingsC         = settingsC
+	RefcountsC        = refcountsC
 )
 
 var (
@@ -692,6 +693,12 @@ func PrimeUnitStatusHistory(
 // approximate size of the entry and limit the number of entries that
 // must be generated for size related tests.
 func PrimeActions(c *gc.C, age time.Time, unit *Unit, count int) {
+	PrimeNamedActions(c, age, unit, "", count)
+}
+
+// PrimeNamedActions is like PrimeActions, but the actions are given
+// the supplied name.
+func PrimeNamedActions(c *gc.C, age time.Time, unit *Unit, name string, count int) {
 	actionCollection, closer := unit.st.db().GetCollection(actionsC)
 	defer closer()
 
@@ -707,6 +714,7 @@ func PrimeActions(c *gc.C, age time.Time, unit *Unit, count int) {
 			DocId:     id.String(),
 			ModelUUID: unit.st.ModelUUID(),
 			Receiver:  unit.Name(),
+			Name:      name,
 			Completed: age,
 			Status:    ActionCompleted,
 			Message:   string(padding[:numBytes]),
@@ -763,6 +771,11 @@ func AssertNoCleanupsWithKind(c *gc.C, st *State, kind cleanupKind) {
 	}
 }
 
+// AddCleanup queues a cleanup of the given kind and prefix.
+func AddCleanup(st *State, kind, prefix string) error {
+	return st.db().RunTransaction([]txn.Op{newCleanupOp(cleanupKind(kind), prefix)})
+}
+
 // AssertNoCleanups checks that there are no cleanups scheduled.
 func AssertNoCleanups(c *gc.C, st *State) {
 	var docs []cleanupDoc
@@ -221,6 +221,12 @@ type Action interface {
 	// Finish removes action from the pending queue and captures the output
 	// and end state of the action.
 	Finish(results ActionResults) (Action, error)
+
+	// Pinned returns whether the action is protected from pruning.
+	Pinned() bool
+
+	// SetPinned sets whether the action is protected from pruning.
+	SetPinned(pinned bool) error
 }
 
 // ApplicationEntity represents a local or remote application.
@@ -108,6 +108,7 @@ type machineDoc struct {
 	Jobs          []MachineJob
 	NoVote        bool
 	HasVote       bool
+	ReadReplica   bool `bson:"readreplica,omitempty"`
 	PasswordHash  string
 	Clean         bool
 
@@ -448,6 +449,13 @@ func (m *Machine) WantsVote() bool {
 	return wantsVote(m.doc.Jobs, m.doc.NoVote)
 }
 
+// IsReadReplica reports whether the machine is a read replica
+// controller: one that never votes in the replica set and whose API
+// server only serves read-only calls.
+func (m *Machine) IsReadReplica() bool {
+	return m.doc.ReadReplica
+}
+
 // HasVote reports whether that machine is currently a voting
 // member of the replica set.
 func (m *Machine) HasVote() bool {
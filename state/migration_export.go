@@ -16,6 +16,7 @@ import (
 	"gopkg.in/juju/names.v2"
 	"gopkg.in/mgo.v2/bson"
 
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/feature"
 	"github.com/juju/juju/payload"
 	"github.com/juju/juju/resource"
@@ -565,6 +566,11 @@ func (e *exporter) applications() error {
 		return errors.Trace(err)
 	}
 
+	unitStates, err := e.readAllUnitStates()
+	if err != nil {
+		return errors.Trace(err)
+	}
+
 	bindings, err := e.readAllEndpointBindings()
 	if err != nil {
 		return errors.Trace(err)
@@ -609,6 +615,7 @@ func (e *exporter) applications() error {
 			application:      application,
 			units:            applicationUnits,
 			meterStatus:      meterStatus,
+			unitStates:       unitStates,
 			podSpecs:         podSpecs,
 			cloudServices:    cloudServices,
 			cloudContainers:  cloudContainers,
@@ -670,6 +677,7 @@ type addApplicationContext struct {
 	application      *Application
 	units            []*Unit
 	meterStatus      map[string]*meterStatusDoc
+	unitStates       map[string]map[string]string
 	leader           string
 	payloads         map[string][]payload.FullPayloadInfo
 	resources        resource.ApplicationResources
@@ -848,7 +856,11 @@ func (e *exporter) addApplication(ctx addApplicationContext) error {
 			}
 			e.statusHistoryArgs(globalCCKey)
 		}
-		exUnit.SetAnnotations(e.getAnnotations(globalKey))
+		annotations := e.getAnnotations(globalKey)
+		if unitState := ctx.unitStates[globalKey]; len(unitState) > 0 {
+			annotations = unitStateAnnotations(annotations, unitState)
+		}
+		exUnit.SetAnnotations(annotations)
 
 		constraintsArgs, err := e.constraintsArgs(agentKey)
 		if err != nil {
@@ -1221,6 +1233,12 @@ func (e *exporter) actions() error {
 	}
 	e.logger.Debugf("read %d actions", len(actions))
 	for _, action := range actions {
+		// The model description has nowhere to record pinning yet,
+		// so a pinned action is migrated unpinned and may be pruned
+		// in the target model.
+		if action.Pinned() {
+			e.logger.Warningf("action %q is pinned; it will not be pinned after migration", action.Id())
+		}
 		results, message := action.Results()
 		e.model.AddAction(description.ActionArgs{
 			Receiver:   action.Receiver(),
@@ -1312,6 +1330,23 @@ func (e *exporter) readAllMeterStatus() (map[string]*meterStatusDoc, error) {
 	return result, nil
 }
 
+func (e *exporter) readAllUnitStates() (map[string]map[string]string, error) {
+	unitStates, closer := e.st.db().GetCollection(unitStatesC)
+	defer closer()
+
+	docs := []unitStateDoc{}
+	err := unitStates.Find(nil).All(&docs)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get all unit state docs")
+	}
+	e.logger.Debugf("found %d unit state docs", len(docs))
+	result := make(map[string]map[string]string)
+	for _, doc := range docs {
+		result[e.st.localID(doc.DocID)] = unescapeUnitState(doc.State)
+	}
+	return result, nil
+}
+
 func (e *exporter) readAllPodSpecs() (map[string]string, error) {
 	specs, closer := e.st.db().GetCollection(podSpecsC)
 	defer closer()
@@ -1590,6 +1625,21 @@ func (e *exporter) statusHistoryArgs(globalKey string) []description.StatusArgs
 	return result
 }
 
+// unexportedConstraints holds the constraints that the model
+// description can't yet represent, with their constraints document
+// fields. They are left out of the exported model, with a warning;
+// they only affect machines that have yet to be provisioned.
+var unexportedConstraints = []struct {
+	field string
+	name  string
+}{
+	{"spot", constraints.Spot},
+	{"spotmaxprice", constraints.SpotMaxPrice},
+	{"instancerole", constraints.InstanceRole},
+	{"antiaffinity", constraints.AntiAffinity},
+	{"rootdisksource", constraints.RootDiskSource},
+}
+
 func (e *exporter) constraintsArgs(globalKey string) (description.ConstraintsArgs, error) {
 	doc, found := e.constraints[globalKey]
 	if !found {
@@ -1597,6 +1647,11 @@ func (e *exporter) constraintsArgs(globalKey string) (description.ConstraintsArg
 		e.logger.Tracef("no constraints found for key %q", globalKey)
 		return description.ConstraintsArgs{}, nil
 	}
+	for _, cons := range unexportedConstraints {
+		if doc[cons.field] != nil {
+			e.logger.Warningf("%q constraint for %s can't be exported; dropping it", cons.name, globalKey)
+		}
+	}
 	// We capture any type error using a closure to avoid having to return
 	// multiple values from the optional functions. This does mean that we will
 	// only report on the last one, but that is fine as there shouldn't be any.
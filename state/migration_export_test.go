@@ -5,6 +5,7 @@ package state_test
 
 import (
 	"bytes"
+	"fmt"
 	"io/ioutil"
 	"math/rand"
 	"time"
@@ -518,10 +519,85 @@ func (s *MigrationExportSuite) TestMultipleApplications(c *gc.C) {
 	c.Assert(applications, gc.HasLen, 3)
 }
 
+func (s *MigrationExportSuite) assertConstraintDropped(c *gc.C, name, globalKey string) {
+	_, err := s.State.Export()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(c.GetTestLog(), jc.Contains, fmt.Sprintf(`%q constraint for %s can't be exported; dropping it`, name, globalKey))
+}
+
+func (s *MigrationExportSuite) TestSpotConstraintsDropped(c *gc.C) {
+	err := s.State.SetModelConstraints(constraints.MustParse("spot=true mem=4G"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertConstraintDropped(c, "spot", "e")
+	model, err := s.State.Export()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(model.Constraints().Memory(), gc.Equals, uint64(4*1024))
+}
+
+func (s *MigrationExportSuite) TestSpotMaxPriceConstraintDropped(c *gc.C) {
+	machine := s.Factory.MakeMachine(c, &factory.MachineParams{
+		Constraints: constraints.MustParse("spot-max-price=0.05"),
+	})
+
+	s.assertConstraintDropped(c, "spot-max-price", "m#"+machine.Id())
+}
+
+func (s *MigrationExportSuite) TestInstanceRoleConstraintDropped(c *gc.C) {
+	s.Factory.MakeApplication(c, &factory.ApplicationParams{
+		Name:        "wordpress",
+		Constraints: constraints.MustParse("instance-role=juju-machine"),
+	})
+
+	s.assertConstraintDropped(c, "instance-role", "a#wordpress")
+}
+
+func (s *MigrationExportSuite) TestAntiAffinityConstraintDropped(c *gc.C) {
+	s.Factory.MakeApplication(c, &factory.ApplicationParams{
+		Name:        "wordpress",
+		Constraints: constraints.MustParse("anti-affinity=hard"),
+	})
+
+	s.assertConstraintDropped(c, "anti-affinity", "a#wordpress")
+}
+
+func (s *MigrationExportSuite) TestRootDiskSourceConstraintDropped(c *gc.C) {
+	machine := s.Factory.MakeMachine(c, &factory.MachineParams{
+		Constraints: constraints.MustParse("root-disk-source=fast-ssd"),
+	})
+
+	s.assertConstraintDropped(c, "root-disk-source", "m#"+machine.Id())
+}
+
 func (s *MigrationExportSuite) TestUnits(c *gc.C) {
 	s.assertMigrateUnits(c, s.State)
 }
 
+func (s *MigrationExportSuite) TestUnitsWithCharmState(c *gc.C) {
+	unit := s.Factory.MakeUnit(c, nil)
+	err := unit.SetState(map[string]string{"foo": "bar"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	model, err := s.State.Model()
+	c.Assert(err, jc.ErrorIsNil)
+	err = model.SetAnnotations(unit, testAnnotations)
+	c.Assert(err, jc.ErrorIsNil)
+
+	exported, err := s.State.Export()
+	c.Assert(err, jc.ErrorIsNil)
+	applications := exported.Applications()
+	c.Assert(applications, gc.HasLen, 1)
+	units := applications[0].Units()
+	c.Assert(units, gc.HasLen, 1)
+
+	// The charm state travels with the unit's annotations.
+	expected := map[string]string{"juju-unit-state:foo": "bar"}
+	for k, v := range testAnnotations {
+		expected[k] = v
+	}
+	c.Assert(units[0].Annotations(), jc.DeepEquals, expected)
+}
+
 func (s *MigrationExportSuite) TestCAASUnits(c *gc.C) {
 	caasSt := s.Factory.MakeCAASModel(c, nil)
 	s.AddCleanup(func(_ *gc.C) { caasSt.Close() })
@@ -1211,6 +1287,24 @@ func (s *MigrationExportSuite) TestActions(c *gc.C) {
 	c.Check(action.Message(), gc.Equals, "")
 }
 
+func (s *MigrationExportSuite) TestActionsPinned(c *gc.C) {
+	machine := s.Factory.MakeMachine(c, nil)
+	m, err := s.State.Model()
+	c.Assert(err, jc.ErrorIsNil)
+	action, err := m.EnqueueAction(machine.MachineTag(), "foo", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = action.SetPinned(true)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The pin is dropped, but the action itself is still exported.
+	model, err := s.State.Export()
+	c.Assert(err, jc.ErrorIsNil)
+	actions := model.Actions()
+	c.Assert(actions, gc.HasLen, 1)
+	c.Check(actions[0].Id(), gc.Equals, action.Id())
+	c.Check(c.GetTestLog(), jc.Contains, `action "`+action.Id()+`" is pinned; it will not be pinned after migration`)
+}
+
 func (s *MigrationExportSuite) TestActionsSkipped(c *gc.C) {
 	machine := s.Factory.MakeMachine(c, &factory.MachineParams{
 		Constraints: constraints.MustParse("arch=amd64 mem=8G"),
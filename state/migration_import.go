@@ -964,6 +964,11 @@ func (i *importer) unit(s description.Application, u description.Unit) error {
 		})
 	}
 
+	annotations, unitState := splitUnitStateAnnotations(u.Annotations())
+	if len(unitState) > 0 {
+		ops = append(ops, createUnitStateOp(i.st, unitGlobalKey(u.Name()), unitState))
+	}
+
 	// We should only have constraints for principal agents.
 	// We don't encode that business logic here, if there are constraints
 	// in the imported model, we put them in the database.
@@ -982,7 +987,7 @@ func (i *importer) unit(s description.Application, u description.Unit) error {
 		return errors.Trace(err)
 	}
 	unit := newUnit(i.st, model.Type(), udoc)
-	if annotations := u.Annotations(); len(annotations) > 0 {
+	if len(annotations) > 0 {
 		if err := i.dbModel.SetAnnotations(unit, annotations); err != nil {
 			return errors.Trace(err)
 		}
@@ -803,6 +803,29 @@ func (s *MigrationImportSuite) TestCAASUnits(c *gc.C) {
 	s.assertUnitsMigrated(c, caasSt, constraints.MustParse("arch=amd64 mem=8G"))
 }
 
+func (s *MigrationImportSuite) TestUnitsWithCharmState(c *gc.C) {
+	exported := s.Factory.MakeUnit(c, nil)
+	err := exported.SetState(map[string]string{"foo": "bar", "a.b": "c"})
+	c.Assert(err, jc.ErrorIsNil)
+	model, err := s.State.Model()
+	c.Assert(err, jc.ErrorIsNil)
+	err = model.SetAnnotations(exported, testAnnotations)
+	c.Assert(err, jc.ErrorIsNil)
+
+	newModel, newSt := s.importModel(c, s.State)
+
+	imported, err := newSt.Unit(exported.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	unitState, err := imported.State()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(unitState, jc.DeepEquals, map[string]string{"foo": "bar", "a.b": "c"})
+
+	// The charm state is not left behind as annotations.
+	annotations, err := newModel.Annotations(imported)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(annotations, jc.DeepEquals, testAnnotations)
+}
+
 func (s *MigrationImportSuite) TestUnitsWithVirtConstraint(c *gc.C) {
 	s.assertUnitsMigrated(c, s.State, constraints.MustParse("arch=amd64 mem=8G virt-type=kvm"))
 }
@@ -42,6 +42,7 @@ func (s *MigrationSuite) TestKnownCollections(c *gc.C) {
 		applicationsC,
 		unitsC,
 		meterStatusC, // red / green status for metrics of units
+		unitStatesC,
 		payloadsC,
 		"resources",
 
@@ -727,7 +728,12 @@ func (s *MigrationSuite) TestActionDocFields(c *gc.C) {
 		"Message",
 		"Status",
 	)
-	s.AssertExportedFields(c, actionDoc{}, migrated.Union(ignored))
+	// The description package cannot yet record pinning, so the
+	// export refuses models with pinned actions.
+	refused := set.NewStrings(
+		"Pinned",
+	)
+	s.AssertExportedFields(c, actionDoc{}, migrated.Union(ignored).Union(refused))
 }
 
 func (s *MigrationSuite) TestVolumeDocFields(c *gc.C) {
@@ -195,7 +195,10 @@ func newState(
 func (st *State) Close() (err error) {
 	defer errors.DeferredAnnotatef(&err, "closing state failed")
 
-	if st.workers != nil {
+	if st.primary != nil {
+		// The workers belong to the primary State.
+		st.primary.Release()
+	} else if st.workers != nil {
 		if err := worker.Stop(st.workers); err != nil {
 			return errors.Annotatef(err, "failed to stop workers")
 		}
@@ -15,6 +15,7 @@ import (
 	"github.com/juju/pubsub"
 	"gopkg.in/juju/names.v2"
 	"gopkg.in/juju/worker.v1"
+	"gopkg.in/mgo.v2"
 
 	"github.com/juju/juju/mongo"
 	"github.com/juju/juju/state/watcher"
@@ -114,6 +115,10 @@ type StatePool struct {
 
 	// watcherRunner makes sure the TxnWatcher stays running.
 	watcherRunner *worker.Runner
+
+	// primary is set for read pools, and holds the pool whose
+	// States' workers are shared by the States in this pool.
+	primary *StatePool
 }
 
 // OpenStatePool returns a new StatePool instance.
@@ -184,6 +189,32 @@ func OpenStatePool(args OpenParams) (*StatePool, error) {
 	return pool, nil
 }
 
+// OpenReadStatePool returns a StatePool whose States use the given
+// session, normally one that prefers to read from a mongo secondary.
+// The States share the watchers and lease managers of the corresponding
+// States in the primary pool, so the read pool runs no workers of its
+// own. The caller remains responsible for closing the session, and
+// must close the read pool before the primary pool.
+func OpenReadStatePool(primary *StatePool, session *mgo.Session) (*StatePool, error) {
+	pool := &StatePool{
+		pool:    make(map[string]*PoolItem),
+		hub:     primary.hub,
+		primary: primary,
+	}
+	systemState := primary.SystemState()
+	st, err := newState(
+		systemState.modelTag, systemState.controllerModelTag,
+		session.Copy(), systemState.newPolicy, systemState.stateClock,
+		systemState.runTransactionObserver,
+	)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	st.shareWorkers(newPooledState(systemState, primary, systemState.ModelUUID(), true))
+	pool.systemState = st
+	return pool, nil
+}
+
 // Get returns a PooledState for a given model, creating a new State instance
 // if required.
 // If the State has been marked for removal, an error is returned.
@@ -236,6 +267,13 @@ func (p *StatePool) Get(modelUUID string) (*PooledState, error) {
 }
 
 func (p *StatePool) openState(modelUUID string) (*State, error) {
+	var primary *PooledState
+	if p.primary != nil {
+		var err error
+		if primary, err = p.primary.Get(modelUUID); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
 	modelTag := names.NewModelTag(modelUUID)
 	session := p.systemState.session.Copy()
 	newSt, err := newState(
@@ -244,8 +282,15 @@ func (p *StatePool) openState(modelUUID string) (*State, error) {
 		p.systemState.runTransactionObserver,
 	)
 	if err != nil {
+		if primary != nil {
+			primary.Release()
+		}
 		return nil, errors.Trace(err)
 	}
+	if primary != nil {
+		newSt.shareWorkers(primary)
+		return newSt, nil
+	}
 	if err := newSt.start(p.systemState.controllerTag, p.hub); err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -401,7 +446,9 @@ func (p *StatePool) IntrospectionReport() string {
 func (p *StatePool) Report() map[string]interface{} {
 	p.mu.Lock()
 	report := make(map[string]interface{})
-	report["txn-watcher"] = p.watcherRunner.Report()
+	if p.watcherRunner != nil {
+		report["txn-watcher"] = p.watcherRunner.Report()
+	}
 	report["system"] = p.systemState.Report()
 	report["pool-size"] = len(p.pool)
 	for uuid, item := range p.pool {
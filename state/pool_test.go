@@ -203,3 +203,33 @@ func (s *statePoolSuite) TestReport(c *gc.C) {
 	report := s.StatePool.Report()
 	c.Check(report, gc.HasLen, 3)
 }
+
+func (s *statePoolSuite) TestReadPool(c *gc.C) {
+	readPool, err := state.OpenReadStatePool(s.StatePool, s.Session)
+	c.Assert(err, jc.ErrorIsNil)
+
+	st, err := readPool.Get(s.ModelUUID1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(st.ModelUUID(), gc.Equals, s.ModelUUID1)
+	assertNotClosed(c, st.State)
+
+	// The read pool's States use the workers of the primary pool's
+	// States rather than starting their own.
+	primary, err := s.StatePool.Get(s.ModelUUID1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(st.State, gc.Not(gc.Equals), primary.State)
+	c.Assert(state.GetInternalWorkers(st.State), gc.Equals, state.GetInternalWorkers(primary.State))
+	primary.Release()
+
+	// Closing the read pool releases the primary pool's States
+	// without stopping their workers.
+	st.Release()
+	err = readPool.Close()
+	c.Assert(err, jc.ErrorIsNil)
+	assertNotClosed(c, primary.State)
+	workertest.CheckAlive(c, state.GetInternalWorkers(primary.State))
+
+	removed, err := s.StatePool.Remove(s.ModelUUID1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(removed, jc.IsTrue)
+}
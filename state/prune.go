@@ -17,8 +17,9 @@ import (
 // pruneCollection removes collection entries until
 // only entries newer than <maxLogTime> remain and also ensures
 // that the collection is smaller than <maxLogsMB> after the
-// deletion.
-func pruneCollection(mb modelBackend, maxHistoryTime time.Duration, maxHistoryMB int, collectionName string, ageField string, timeUnit TimeUnit) error {
+// deletion. If filter is non-empty, only entries matching it are
+// considered for removal.
+func pruneCollection(mb modelBackend, maxHistoryTime time.Duration, maxHistoryMB int, collectionName string, ageField string, timeUnit TimeUnit, filter bson.D) error {
 
 	// NOTE(axw) we require a raw collection to obtain the size of the
 	// collection. Take care to include model-uuid in queries where
@@ -33,6 +34,7 @@ func pruneCollection(mb modelBackend, maxHistoryTime time.Duration, maxHistoryMB
 		maxSize:  maxHistoryMB,
 		ageField: ageField,
 		timeUnit: timeUnit,
+		filter:   filter,
 	}
 	if err := p.validate(); err != nil {
 		return errors.Trace(err)
@@ -64,6 +66,7 @@ type collectionPruner struct {
 
 	ageField string
 	timeUnit TimeUnit
+	filter   bson.D
 }
 
 func (p *collectionPruner) validate() error {
@@ -96,10 +99,11 @@ func (p *collectionPruner) pruneByAge() error {
 		notSet = time.Time{}
 	}
 
-	iter := p.coll.Find(bson.D{
+	query := append(bson.D{
 		{"model-uuid", p.st.modelUUID()},
 		{p.ageField, bson.M{"$gt": notSet, "$lt": age}},
-	}).Select(bson.M{"_id": 1}).Iter()
+	}, p.filter...)
+	iter := p.coll.Find(query).Select(bson.M{"_id": 1}).Iter()
 	defer iter.Close()
 
 	modelName, err := p.st.modelName()
@@ -155,7 +159,11 @@ func (p *collectionPruner) pruneBySize() error {
 	}
 	toDelete := int(float64(collMB-p.maxSize) / sizePerStatus)
 
-	iter := p.coll.Find(nil).Sort(p.ageField).Limit(toDelete).Select(bson.M{"_id": 1}).Iter()
+	var query interface{}
+	if len(p.filter) > 0 {
+		query = p.filter
+	}
+	iter := p.coll.Find(query).Sort(p.ageField).Limit(toDelete).Select(bson.M{"_id": 1}).Iter()
 	defer iter.Close()
 
 	template := fmt.Sprintf("%s size pruning: deleted %%d of %d (estimated)", p.coll.Name, toDelete)
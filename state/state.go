@@ -108,6 +108,10 @@ type State struct {
 	// first step.
 	workers *workers
 
+	// primary is set for the States of a read pool, and holds the
+	// State of the primary pool whose workers this State shares.
+	primary *PooledState
+
 	// TODO(anastasiamac 2015-07-16) As state gets broken up, remove this.
 	CloudImageMetadataStorage cloudimagemetadata.Storage
 }
@@ -481,6 +485,20 @@ func (st *State) start(controllerTag names.ControllerTag, hub *pubsub.SimpleHub)
 	return nil
 }
 
+// shareWorkers completes a State opened by a read pool, using the
+// workers of the given State from the primary pool instead of starting
+// its own. The primary State is released when this State is closed.
+func (st *State) shareWorkers(primary *PooledState) {
+	st.primary = primary
+	st.controllerTag = primary.controllerTag
+	st.leaseStoreId = primary.leaseStoreId
+	st.workers = primary.workers
+	st.CloudImageMetadataStorage = cloudimagemetadata.NewStorage(
+		cloudimagemetadataC,
+		&environMongo{st},
+	)
+}
+
 // ApplicationLeaders returns a map of the application name to the
 // unit name that is the current leader.
 func (st *State) ApplicationLeaders() (map[string]string, error) {
@@ -3411,6 +3411,32 @@ func (s *StateSuite) TestWatchCleanups(c *gc.C) {
 	wc.AssertClosed()
 }
 
+func (s *StateSuite) TestWatchCleanupsIgnoresFailures(c *gc.C) {
+	// Check initial event.
+	w := s.State.WatchCleanups()
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	err := state.AddCleanup(s.State, "bogus", "thing")
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	// Recording the failed attempts doesn't trigger the watcher, so
+	// a failing cleanup isn't retried in a loop.
+	err = s.State.Cleanup()
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+	err = s.State.Cleanup()
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+
+	cleanups, err := s.State.Cleanups()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cleanups, gc.HasLen, 1)
+	c.Assert(cleanups[0].Attempts, gc.Equals, 2)
+}
+
 func (s *StateSuite) TestWatchCleanupsDiesOnStateClose(c *gc.C) {
 	testWatcherDiesWhenStateCloses(c, s.Session, s.modelTag, s.State.ControllerTag(), func(c *gc.C, st *state.State) waiter {
 		w := st.WatchCleanups()
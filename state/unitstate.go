@@ -0,0 +1,200 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	mgoutils "github.com/juju/juju/mongo/utils"
+)
+
+// MaxUnitStateSize is the maximum number of bytes, summed over all
+// keys and values, that a charm may store in its unit state.
+const MaxUnitStateSize = 64 * 1024
+
+// unitStateDoc records the key/value pairs that a charm has chosen to
+// persist for one of its units via the state-set hook tool.
+type unitStateDoc struct {
+	// DocID is always the same as a unit's global key.
+	DocID     string `bson:"_id"`
+	ModelUUID string `bson:"model-uuid"`
+	TxnRevno  int64  `bson:"txn-revno"`
+
+	// State is the unit's persisted key/value state.
+	State map[string]string `bson:"state,omitempty"`
+}
+
+// unitStateSize returns the number of bytes used by the supplied
+// unit state, for the purposes of enforcing MaxUnitStateSize.
+func unitStateSize(state map[string]string) int {
+	var size int
+	for k, v := range state {
+		size += len(k) + len(v)
+	}
+	return size
+}
+
+// State returns the key/value pairs persisted for the unit by its
+// charm. If nothing has been stored, an empty map is returned.
+func (u *Unit) State() (map[string]string, error) {
+	doc, err := u.stateDoc()
+	if errors.IsNotFound(err) {
+		return map[string]string{}, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make(map[string]string, len(doc.State))
+	for k, v := range doc.State {
+		result[k] = v
+	}
+	return result, nil
+}
+
+// SetState replaces the key/value pairs persisted for the unit with
+// the supplied values. An empty map removes all stored state. It is
+// an error to store more than MaxUnitStateSize bytes, or to set
+// state on a unit that is dead.
+func (u *Unit) SetState(state map[string]string) error {
+	if size := unitStateSize(state); size > MaxUnitStateSize {
+		return errors.Errorf(
+			"unit state of %d bytes exceeds the %d byte limit", size, MaxUnitStateSize,
+		)
+	}
+	escaped := make(map[string]string, len(state))
+	for k, v := range state {
+		escaped[mgoutils.EscapeKey(k)] = v
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := u.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if u.doc.Life == Dead {
+			return nil, errors.Errorf("unit is dead")
+		}
+		ops := []txn.Op{{
+			C:      unitsC,
+			Id:     u.doc.DocID,
+			Assert: notDeadDoc,
+		}}
+		_, err := u.stateDoc()
+		if errors.IsNotFound(err) {
+			if len(escaped) == 0 {
+				return nil, jujutxn.ErrNoOperations
+			}
+			return append(ops, txn.Op{
+				C:      unitStatesC,
+				Id:     u.globalKey(),
+				Assert: txn.DocMissing,
+				Insert: &unitStateDoc{
+					DocID: u.globalKey(),
+					State: escaped,
+				},
+			}), nil
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return append(ops, txn.Op{
+			C:      unitStatesC,
+			Id:     u.globalKey(),
+			Assert: txn.DocExists,
+			Update: bson.D{{"$set", bson.D{{"state", escaped}}}},
+		}), nil
+	}
+	err := u.st.db().Run(buildTxn)
+	return errors.Annotatef(err, "cannot set state for unit %q", u.Name())
+}
+
+func (u *Unit) stateDoc() (*unitStateDoc, error) {
+	coll, closer := u.st.db().GetCollection(unitStatesC)
+	defer closer()
+
+	var doc unitStateDoc
+	err := coll.FindId(u.globalKey()).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("state for unit %q", u.Name())
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	doc.State = unescapeUnitState(doc.State)
+	return &doc, nil
+}
+
+func unescapeUnitState(escaped map[string]string) map[string]string {
+	result := make(map[string]string, len(escaped))
+	for k, v := range escaped {
+		result[mgoutils.UnescapeKey(k)] = v
+	}
+	return result
+}
+
+// unitStateAnnotationPrefix marks the unit annotations that carry a
+// unit's charm state through a model migration. The model description
+// has no field of its own for unit state, so it travels alongside the
+// unit's annotations and is split out again on import.
+const unitStateAnnotationPrefix = "juju-unit-state:"
+
+// unitStateAnnotations returns a copy of the supplied annotations with
+// the unit state added under unitStateAnnotationPrefix.
+func unitStateAnnotations(annotations, state map[string]string) map[string]string {
+	result := make(map[string]string, len(annotations)+len(state))
+	for k, v := range annotations {
+		result[k] = v
+	}
+	for k, v := range state {
+		result[unitStateAnnotationPrefix+k] = v
+	}
+	return result
+}
+
+// splitUnitStateAnnotations separates the unit state carried in the
+// supplied annotations from the annotations proper.
+func splitUnitStateAnnotations(all map[string]string) (annotations, state map[string]string) {
+	annotations = make(map[string]string)
+	state = make(map[string]string)
+	for k, v := range all {
+		if strings.HasPrefix(k, unitStateAnnotationPrefix) {
+			state[strings.TrimPrefix(k, unitStateAnnotationPrefix)] = v
+		} else {
+			annotations[k] = v
+		}
+	}
+	return annotations, state
+}
+
+// createUnitStateOp returns the operation needed to create the unit
+// state document associated with the given globalKey.
+func createUnitStateOp(mb modelBackend, globalKey string, state map[string]string) txn.Op {
+	escaped := make(map[string]string, len(state))
+	for k, v := range state {
+		escaped[mgoutils.EscapeKey(k)] = v
+	}
+	return txn.Op{
+		C:      unitStatesC,
+		Id:     mb.docID(globalKey),
+		Assert: txn.DocMissing,
+		Insert: &unitStateDoc{
+			DocID:     mb.docID(globalKey),
+			ModelUUID: mb.modelUUID(),
+			State:     escaped,
+		},
+	}
+}
+
+// removeUnitStateOp returns the operation needed to remove the unit
+// state document associated with the given globalKey.
+func removeUnitStateOp(mb modelBackend, globalKey string) txn.Op {
+	return txn.Op{
+		C:      unitStatesC,
+		Id:     mb.docID(globalKey),
+		Remove: true,
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type UnitStateSuite struct {
+	ConnSuite
+	unit *state.Unit
+}
+
+var _ = gc.Suite(&UnitStateSuite{})
+
+func (s *UnitStateSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.unit = s.Factory.MakeUnit(c, nil)
+}
+
+func (s *UnitStateSuite) TestStateEmpty(c *gc.C) {
+	st, err := s.unit.State()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(st, gc.HasLen, 0)
+}
+
+func (s *UnitStateSuite) TestSetState(c *gc.C) {
+	err := s.unit.SetState(map[string]string{"foo": "bar", "a.b$c": "d"})
+	c.Assert(err, jc.ErrorIsNil)
+	st, err := s.unit.State()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(st, jc.DeepEquals, map[string]string{"foo": "bar", "a.b$c": "d"})
+
+	err = s.unit.SetState(map[string]string{"baz": "qux"})
+	c.Assert(err, jc.ErrorIsNil)
+	st, err = s.unit.State()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(st, jc.DeepEquals, map[string]string{"baz": "qux"})
+}
+
+func (s *UnitStateSuite) TestSetStateEmptyClears(c *gc.C) {
+	err := s.unit.SetState(map[string]string{"foo": "bar"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.SetState(map[string]string{})
+	c.Assert(err, jc.ErrorIsNil)
+	st, err := s.unit.State()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(st, gc.HasLen, 0)
+}
+
+func (s *UnitStateSuite) TestSetStateQuota(c *gc.C) {
+	big := strings.Repeat("x", state.MaxUnitStateSize)
+	err := s.unit.SetState(map[string]string{"foo": big})
+	c.Assert(err, gc.ErrorMatches, `unit state of \d+ bytes exceeds the \d+ byte limit`)
+}
+
+func (s *UnitStateSuite) TestSetStateDeadUnit(c *gc.C) {
+	err := s.unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.SetState(map[string]string{"foo": "bar"})
+	c.Assert(err, gc.ErrorMatches, `cannot set state for unit ".*": unit is dead`)
+}
+
+func (s *UnitStateSuite) TestStateRemovedWithUnit(c *gc.C) {
+	err := s.unit.SetState(map[string]string{"foo": "bar"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+
+	coll := s.MgoSuite.Session.DB("juju").C("unitstates")
+	count, err := coll.Count()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(count, gc.Equals, 0)
+}
@@ -2427,9 +2427,94 @@ func (w *machineAddressesWatcher) loop() error {
 	}
 }
 
-// WatchCleanups starts and returns a CleanupWatcher.
+// WatchCleanups starts and returns a CleanupWatcher, which notifies
+// when cleanups are queued or removed. The failed attempts recorded
+// against a cleanup don't trigger a notification, so a cleanup that
+// keeps failing is retried when other cleanups are queued or the
+// cleaner's retry period elapses, rather than immediately.
 func (st *State) WatchCleanups() NotifyWatcher {
-	return newNotifyCollWatcher(st, cleanupsC, isLocalID(st))
+	return newCleanupWatcher(st)
+}
+
+// cleanupWatcher notifies of the insertion and removal of cleanup
+// documents, ignoring updates to them.
+type cleanupWatcher struct {
+	commonWatcher
+	st   *State
+	sink chan struct{}
+}
+
+func newCleanupWatcher(st *State) NotifyWatcher {
+	w := &cleanupWatcher{
+		commonWatcher: newCommonWatcher(st),
+		st:            st,
+		sink:          make(chan struct{}),
+	}
+	w.tomb.Go(func() error {
+		defer close(w.sink)
+		return w.loop()
+	})
+	return w
+}
+
+// Changes returns the event channel for this watcher.
+func (w *cleanupWatcher) Changes() <-chan struct{} {
+	return w.sink
+}
+
+func (w *cleanupWatcher) loop() error {
+	in := make(chan watcher.Change)
+	w.watcher.WatchCollectionWithFilter(cleanupsC, in, isLocalID(w.st))
+	defer w.watcher.UnwatchCollection(cleanupsC, in)
+
+	known, err := w.cleanupIds()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	out := w.sink // out set so that initial event is sent.
+	for {
+		select {
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case <-w.watcher.Dead():
+			return stateWatcherDeadError(w.watcher.Err())
+		case change := <-in:
+			changes, ok := collect(change, in, w.tomb.Dying())
+			if !ok {
+				return tomb.ErrDying
+			}
+			for id, exists := range changes {
+				if exists != known.Contains(id.(string)) {
+					out = w.sink
+				}
+				if exists {
+					known.Add(id.(string))
+				} else {
+					known.Remove(id.(string))
+				}
+			}
+		case out <- struct{}{}:
+			out = nil
+		}
+	}
+}
+
+// cleanupIds returns the ids of the model's cleanup documents.
+func (w *cleanupWatcher) cleanupIds() (set.Strings, error) {
+	cleanups, closer := w.st.db().GetCollection(cleanupsC)
+	defer closer()
+
+	var docs []struct {
+		DocID string `bson:"_id"`
+	}
+	if err := cleanups.Find(nil).Select(bson.D{{"_id", 1}}).All(&docs); err != nil {
+		return nil, errors.Annotate(err, "reading cleanup documents")
+	}
+	ids := set.NewStrings()
+	for _, doc := range docs {
+		ids.Add(doc.DocID)
+	}
+	return ids, nil
 }
 
 // actionStatusWatcher is a StringsWatcher that filters notifications
@@ -10,6 +10,7 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/pubsub"
 	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/juju/names.v2"
 	"gopkg.in/juju/worker.v1"
 	"gopkg.in/juju/worker.v1/dependency"
 
@@ -43,6 +44,12 @@ type ManifoldConfig struct {
 	Hub                               *pubsub.StructuredHub
 	Presence                          presence.Recorder
 
+	// OpenReadStatePool, if non-nil, is used to open the state pool
+	// for the API server when the agent's machine is a read replica.
+	// The pool should read from a mongo secondary where possible,
+	// and share the workers of the supplied state pool.
+	OpenReadStatePool func(agent.Config, *state.StatePool) (*state.StatePool, error)
+
 	NewWorker func(Config) (worker.Worker, error)
 }
 
@@ -170,11 +177,21 @@ func (config ManifoldConfig) start(context dependency.Context) (worker.Worker, e
 		return nil, errors.Trace(err)
 	}
 
+	readPool, err := config.openReadStatePool(agent.CurrentConfig(), statePool)
+	if err != nil {
+		stTracker.Done()
+		return nil, errors.Trace(err)
+	}
+	serverPool := statePool
+	if readPool != nil {
+		serverPool = readPool
+	}
+
 	w, err := config.NewWorker(Config{
 		AgentConfig:                       agent.CurrentConfig(),
 		Clock:                             clock,
 		Mux:                               mux,
-		StatePool:                         statePool,
+		StatePool:                         serverPool,
 		LeaseManager:                      leaseManager,
 		PrometheusRegisterer:              config.PrometheusRegisterer,
 		RegisterIntrospectionHTTPHandlers: config.RegisterIntrospectionHTTPHandlers,
@@ -185,14 +202,55 @@ func (config ManifoldConfig) start(context dependency.Context) (worker.Worker, e
 		Authenticator:                     authenticator,
 		GetAuditConfig:                    getAuditConfig,
 		NewServer:                         newServerShim,
+		ReadOnly:                          readPool != nil,
 	})
 	if err != nil {
+		closeReadStatePool(readPool)
 		stTracker.Done()
 		return nil, errors.Trace(err)
 	}
 	mux.AddClient()
 	return common.NewCleanupWorker(w, func() {
 		mux.ClientDone()
+		closeReadStatePool(readPool)
 		stTracker.Done()
 	}), nil
 }
+
+// openReadStatePool returns a state pool reading from a mongo
+// secondary if the agent's machine is a read replica controller,
+// and nil otherwise.
+func (config ManifoldConfig) openReadStatePool(
+	agentConfig agent.Config,
+	statePool *state.StatePool,
+) (*state.StatePool, error) {
+	if config.OpenReadStatePool == nil {
+		return nil, nil
+	}
+	tag, ok := agentConfig.Tag().(names.MachineTag)
+	if !ok {
+		return nil, nil
+	}
+	machine, err := statePool.SystemState().Machine(tag.Id())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !machine.IsReadReplica() {
+		return nil, nil
+	}
+	logger.Infof("machine %s is a read replica; serving read-only API requests", tag.Id())
+	readPool, err := config.OpenReadStatePool(agentConfig, statePool)
+	if err != nil {
+		return nil, errors.Annotate(err, "opening read-only state pool")
+	}
+	return readPool, nil
+}
+
+func closeReadStatePool(pool *state.StatePool) {
+	if pool == nil {
+		return
+	}
+	if err := pool.Close(); err != nil {
+		logger.Errorf("closing read-only state pool: %v", err)
+	}
+}
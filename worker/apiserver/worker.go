@@ -41,6 +41,10 @@ type Config struct {
 	UpgradeComplete                   func() bool
 	GetAuditConfig                    func() auditlog.Config
 	NewServer                         NewServerFunc
+
+	// ReadOnly holds whether the API server should only serve
+	// read-only requests, as it does on read replica controllers.
+	ReadOnly bool
 }
 
 // NewServerFunc is the type of function that will be used
@@ -144,6 +148,7 @@ func NewWorker(config Config) (worker.Worker, error) {
 		PrometheusRegisterer:          config.PrometheusRegisterer,
 		GetAuditConfig:                config.GetAuditConfig,
 		LeaseManager:                  config.LeaseManager,
+		ReadOnly:                      config.ReadOnly,
 	}
 	return config.NewServer(serverConfig)
 }
@@ -0,0 +1,8 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backupscheduler
+
+var ExpiredBackups = expiredBackups
+
+var CreateScheduledBackup = createScheduledBackup
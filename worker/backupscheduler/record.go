@@ -0,0 +1,38 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backupscheduler
+
+import (
+	"io"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/filestorage"
+
+	"github.com/juju/juju/state/backups"
+)
+
+// scheduledBackupNotes is the annotation given to scheduled backups.
+const scheduledBackupNotes = "scheduled backup"
+
+// metadataAdder is the part of filestorage.FileStorage needed to
+// record a failed scheduled backup.
+type metadataAdder interface {
+	Add(meta filestorage.Metadata, archive io.Reader) (string, error)
+}
+
+// createScheduledBackup marks meta as a scheduled backup and calls
+// create to take it. If that fails, the failure is recorded in stor,
+// without an archive, so that it can be reported.
+func createScheduledBackup(stor metadataAdder, meta *backups.Metadata, create func() error) error {
+	meta.Notes = scheduledBackupNotes
+	meta.Scheduled = true
+	if err := create(); err != nil {
+		meta.Failure = err.Error()
+		if _, addErr := stor.Add(meta, nil); addErr != nil {
+			logger.Errorf("cannot record failed backup: %v", addErr)
+		}
+		return errors.Trace(err)
+	}
+	return nil
+}
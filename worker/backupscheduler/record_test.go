@@ -0,0 +1,73 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backupscheduler_test
+
+import (
+	"io"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/filestorage"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state/backups"
+	"github.com/juju/juju/worker/backupscheduler"
+)
+
+type RecordSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&RecordSuite{})
+
+type fakeAdder struct {
+	added []filestorage.Metadata
+	err   error
+}
+
+func (a *fakeAdder) Add(meta filestorage.Metadata, archive io.Reader) (string, error) {
+	if archive != nil {
+		return "", errors.New("unexpected archive")
+	}
+	a.added = append(a.added, meta)
+	return "id", a.err
+}
+
+func (s *RecordSuite) TestCreateScheduledBackupSuccess(c *gc.C) {
+	var adder fakeAdder
+	meta := backups.NewMetadata()
+	err := backupscheduler.CreateScheduledBackup(&adder, meta, func() error {
+		c.Check(meta.Scheduled, jc.IsTrue)
+		return nil
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(meta.Notes, gc.Equals, "scheduled backup")
+	c.Check(meta.Failure, gc.Equals, "")
+	c.Check(adder.added, gc.HasLen, 0)
+}
+
+func (s *RecordSuite) TestCreateScheduledBackupFailure(c *gc.C) {
+	var adder fakeAdder
+	meta := backups.NewMetadata()
+	err := backupscheduler.CreateScheduledBackup(&adder, meta, func() error {
+		return errors.New("mongodump failed")
+	})
+	c.Assert(err, gc.ErrorMatches, "mongodump failed")
+	c.Assert(adder.added, gc.HasLen, 1)
+	recorded := adder.added[0].(*backups.Metadata)
+	c.Check(recorded.Scheduled, jc.IsTrue)
+	c.Check(recorded.Failure, gc.Equals, "mongodump failed")
+}
+
+func (s *RecordSuite) TestCreateScheduledBackupFailureNotRecorded(c *gc.C) {
+	adder := fakeAdder{err: errors.New("mongo is down")}
+	meta := backups.NewMetadata()
+	err := backupscheduler.CreateScheduledBackup(&adder, meta, func() error {
+		return errors.New("mongodump failed")
+	})
+	// The original failure is reported, not the failure to record it.
+	c.Assert(err, gc.ErrorMatches, "mongodump failed")
+	c.Check(c.GetTestLog(), jc.Contains, "cannot record failed backup: mongo is down")
+}
@@ -0,0 +1,63 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backupscheduler
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/juju/collections/set"
+
+	"github.com/juju/juju/state/backups"
+)
+
+// expiredBackups returns the IDs of the scheduled backups that fall
+// outside the retention policy. The most recent scheduled backup of
+// each of the last keepDaily days, and of each of the last keepWeekly
+// weeks, is kept, as is the most recent successful scheduled backup.
+// The records of failed scheduled backups are expired unless they
+// record the most recent attempt. Backups that were not created by
+// the schedule are never expired.
+func expiredBackups(all []*backups.Metadata, keepDaily, keepWeekly int) []string {
+	var scheduled []*backups.Metadata
+	for _, meta := range all {
+		if meta.Scheduled {
+			scheduled = append(scheduled, meta)
+		}
+	}
+	sort.Slice(scheduled, func(i, j int) bool {
+		return scheduled[i].Started.After(scheduled[j].Started)
+	})
+
+	days := set.NewStrings()
+	weeks := set.NewStrings()
+	var expired []string
+	keptLatest := false
+	for i, meta := range scheduled {
+		if meta.Failure != "" {
+			if i > 0 {
+				expired = append(expired, meta.ID())
+			}
+			continue
+		}
+		started := meta.Started.UTC()
+		keep := !keptLatest
+		keptLatest = true
+		day := started.Format("2006-01-02")
+		if !days.Contains(day) && days.Size() < keepDaily {
+			days.Add(day)
+			keep = true
+		}
+		year, wk := started.ISOWeek()
+		week := fmt.Sprintf("%d-W%02d", year, wk)
+		if !weeks.Contains(week) && weeks.Size() < keepWeekly {
+			weeks.Add(week)
+			keep = true
+		}
+		if !keep {
+			expired = append(expired, meta.ID())
+		}
+	}
+	return expired
+}
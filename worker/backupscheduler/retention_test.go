@@ -0,0 +1,103 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backupscheduler_test
+
+import (
+	"time"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state/backups"
+	"github.com/juju/juju/worker/backupscheduler"
+)
+
+type RetentionSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&RetentionSuite{})
+
+func newMetadata(id string, started time.Time, scheduled bool) *backups.Metadata {
+	meta := backups.NewMetadata()
+	meta.SetID(id)
+	meta.Started = started
+	meta.Scheduled = scheduled
+	return meta
+}
+
+// dailyBackups returns scheduled backups taken at 02:00 on each of the
+// given number of days, ending on Sunday 2019-05-26.
+func dailyBackups(days int) []*backups.Metadata {
+	last := time.Date(2019, 5, 26, 2, 0, 0, 0, time.UTC)
+	var result []*backups.Metadata
+	for i := 0; i < days; i++ {
+		started := last.AddDate(0, 0, -i)
+		result = append(result, newMetadata(started.Format("2006-01-02"), started, true))
+	}
+	return result
+}
+
+func (s *RetentionSuite) TestKeepDaily(c *gc.C) {
+	expired := backupscheduler.ExpiredBackups(dailyBackups(5), 3, 0)
+	c.Assert(expired, jc.DeepEquals, []string{"2019-05-23", "2019-05-22"})
+}
+
+func (s *RetentionSuite) TestKeepDailyAndWeekly(c *gc.C) {
+	// 2019-05-26 is a Sunday, so the most recent backups of the two
+	// weeks before the last 3 days are those of 2019-05-19 and
+	// 2019-05-12.
+	expired := backupscheduler.ExpiredBackups(dailyBackups(21), 3, 3)
+	c.Assert(expired, gc.HasLen, 16)
+	kept := set.NewStrings()
+	for _, meta := range dailyBackups(21) {
+		kept.Add(meta.ID())
+	}
+	kept = kept.Difference(set.NewStrings(expired...))
+	c.Assert(kept.SortedValues(), jc.DeepEquals, []string{
+		"2019-05-12", "2019-05-19", "2019-05-24", "2019-05-25", "2019-05-26",
+	})
+}
+
+func (s *RetentionSuite) TestKeepsMostRecentOfDay(c *gc.C) {
+	day := time.Date(2019, 5, 26, 0, 0, 0, 0, time.UTC)
+	all := []*backups.Metadata{
+		newMetadata("early", day.Add(time.Hour), true),
+		newMetadata("late", day.Add(20*time.Hour), true),
+	}
+	expired := backupscheduler.ExpiredBackups(all, 1, 0)
+	c.Assert(expired, jc.DeepEquals, []string{"early"})
+}
+
+func (s *RetentionSuite) TestAlwaysKeepsMostRecent(c *gc.C) {
+	expired := backupscheduler.ExpiredBackups(dailyBackups(3), 0, 0)
+	c.Assert(expired, jc.DeepEquals, []string{"2019-05-25", "2019-05-24"})
+}
+
+func (s *RetentionSuite) TestIgnoresUnscheduled(c *gc.C) {
+	all := dailyBackups(3)
+	all = append(all, newMetadata("manual", time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC), false))
+	expired := backupscheduler.ExpiredBackups(all, 1, 0)
+	c.Assert(expired, jc.DeepEquals, []string{"2019-05-25", "2019-05-24"})
+}
+
+func (s *RetentionSuite) TestExpiresOldFailures(c *gc.C) {
+	all := dailyBackups(2)
+	failed := newMetadata("failed", time.Date(2019, 5, 24, 2, 0, 0, 0, time.UTC), true)
+	failed.Failure = "mongodump failed"
+	all = append(all, failed)
+	expired := backupscheduler.ExpiredBackups(all, 2, 0)
+	c.Assert(expired, jc.DeepEquals, []string{"failed"})
+}
+
+func (s *RetentionSuite) TestKeepsLatestFailureAndSuccess(c *gc.C) {
+	all := dailyBackups(2)
+	failed := newMetadata("failed", time.Date(2019, 5, 27, 2, 0, 0, 0, time.UTC), true)
+	failed.Failure = "mongodump failed"
+	all = append(all, failed)
+	expired := backupscheduler.ExpiredBackups(all, 0, 0)
+	c.Assert(expired, jc.DeepEquals, []string{"2019-05-25"})
+}
@@ -0,0 +1,134 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backupscheduler
+
+import (
+	"crypto/rsa"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/filestorage"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/agent"
+	corebackups "github.com/juju/juju/core/backups"
+	"github.com/juju/juju/mongo"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/backups"
+)
+
+// backupsDB implements backups.DB.
+type backupsDB struct {
+	*state.State
+	*state.Model
+}
+
+// ModelTag disambiguates the ModelTag method pending further
+// refactoring to separate model functionality from state functionality.
+func (db backupsDB) ModelTag() names.ModelTag {
+	return db.Model.ModelTag()
+}
+
+// NewStateBackups returns a Backups that creates backups of the
+// controller with the supplied state, on the controller machine with
+// the supplied agent config.
+func NewStateBackups(st *state.State, agentConfig agent.Config) (Backups, error) {
+	model, err := st.Model()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &stateBackups{
+		db:          backupsDB{st, model},
+		agentConfig: agentConfig,
+	}, nil
+}
+
+type stateBackups struct {
+	db          backupsDB
+	agentConfig agent.Config
+}
+
+// Create is part of the Backups interface.
+func (b *stateBackups) Create() (*backups.Metadata, error) {
+	machineID := b.agentConfig.Tag().Id()
+	machine, err := b.db.Machine(machineID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	meta, err := backups.NewMetadataState(b.db, machineID, machine.Series())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	stor := backups.NewStorage(b.db)
+	defer stor.Close()
+	err = createScheduledBackup(stor, meta, func() error {
+		return b.create(stor, meta)
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return meta, nil
+}
+
+func (b *stateBackups) create(stor filestorage.FileStorage, meta *backups.Metadata) error {
+	session := b.db.MongoSession().Copy()
+	defer session.Close()
+
+	mgoInfo, ok := b.agentConfig.MongoInfo()
+	if !ok {
+		return errors.New("no mongo info found in agent config")
+	}
+	v, err := b.db.MongoVersion()
+	if err != nil {
+		return errors.Annotate(err, "discovering mongo version")
+	}
+	mongoVersion, err := mongo.NewVersion(v)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	dbInfo, err := backups.NewDBInfo(mgoInfo, session, mongoVersion)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	modelConfig, err := b.db.ModelConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	paths := backups.Paths{
+		BackupDir: modelConfig.BackupDir(),
+		DataDir:   b.agentConfig.DataDir(),
+		LogsDir:   b.agentConfig.LogDir(),
+	}
+
+	// Scheduled backups are encrypted with the controller's backup
+	// public key, if one is configured.
+	controllerConfig, err := b.db.ControllerConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var encryptionKey *rsa.PublicKey
+	if publicKey := controllerConfig.BackupPublicKey(); publicKey != "" {
+		if encryptionKey, err = corebackups.ParsePublicKey([]byte(publicKey)); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	_, err = backups.NewBackups(stor).Create(meta, &paths, dbInfo, true, true, encryptionKey)
+	return errors.Trace(err)
+}
+
+// List is part of the Backups interface.
+func (b *stateBackups) List() ([]*backups.Metadata, error) {
+	stor := backups.NewStorage(b.db)
+	defer stor.Close()
+	return backups.NewBackups(stor).List()
+}
+
+// Remove is part of the Backups interface.
+func (b *stateBackups) Remove(id string) error {
+	stor := backups.NewStorage(b.db)
+	defer stor.Close()
+	return backups.NewBackups(stor).Remove(id)
+}
@@ -0,0 +1,299 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backupscheduler
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"gopkg.in/juju/worker.v1"
+	"gopkg.in/juju/worker.v1/catacomb"
+
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/backups"
+	jworker "github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.backupscheduler")
+
+// Backend exposes the controller state used by the backup scheduler.
+type Backend interface {
+	WatchControllerConfig() state.NotifyWatcher
+	ControllerConfig() (controller.Config, error)
+}
+
+// Backups exposes the backup operations used by the backup scheduler.
+type Backups interface {
+	// Create creates and stores a new scheduled backup, returning
+	// its metadata. If the backup fails, the failure is recorded in
+	// the metadata of the backups where possible.
+	Create() (*backups.Metadata, error)
+
+	// List returns the metadata of all stored backups.
+	List() ([]*backups.Metadata, error)
+
+	// Remove removes the stored backup with the given ID.
+	Remove(id string) error
+}
+
+// Config holds the dependencies of a backup scheduler worker.
+type Config struct {
+	Backend Backend
+	Backups Backups
+	Clock   clock.Clock
+}
+
+// Validate returns an error if the config cannot be used to start
+// a backup scheduler worker.
+func (config Config) Validate() error {
+	if config.Backend == nil {
+		return errors.NotValidf("nil Backend")
+	}
+	if config.Backups == nil {
+		return errors.NotValidf("nil Backups")
+	}
+	if config.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	return nil
+}
+
+// NewWorker returns a worker which creates controller backups at the
+// interval configured in the controller config, and removes scheduled
+// backups that fall outside the configured retention policy. The
+// outcome of each scheduled backup is recorded in the backup metadata.
+// This worker must not be run in more than one agent concurrently.
+func NewWorker(config Config) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := &scheduleWorker{
+		config:  config,
+		results: make(chan backups.ScheduleStatus),
+	}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &w.catacomb,
+		Work: w.loop,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+type scheduleWorker struct {
+	catacomb catacomb.Catacomb
+	config   Config
+	results  chan backups.ScheduleStatus
+	mu       sync.Mutex
+	current  report
+}
+
+type report struct {
+	interval   time.Duration
+	keepDaily  int
+	keepWeekly int
+	nextBackup time.Time
+	running    bool
+	status     backups.ScheduleStatus
+}
+
+// Report is shown in the engine report.
+func (w *scheduleWorker) Report() map[string]interface{} {
+	w.mu.Lock()
+	report := w.current
+	w.mu.Unlock()
+
+	result := map[string]interface{}{
+		"interval":    report.interval,
+		"keep-daily":  report.keepDaily,
+		"keep-weekly": report.keepWeekly,
+	}
+	if report.running {
+		result["running"] = true
+	}
+	if !report.nextBackup.IsZero() {
+		result["next-backup"] = report.nextBackup.Round(time.Second)
+	}
+	if !report.status.LastAttempt.IsZero() {
+		result["last-attempt"] = report.status.LastAttempt.Round(time.Second)
+	}
+	if !report.status.LastSuccess.IsZero() {
+		result["last-success"] = report.status.LastSuccess.Round(time.Second)
+	}
+	if report.status.LastError != "" {
+		result["last-error"] = report.status.LastError
+	}
+	return result
+}
+
+func (w *scheduleWorker) loop() error {
+	configWatcher := w.config.Backend.WatchControllerConfig()
+	if err := w.catacomb.Add(configWatcher); err != nil {
+		return errors.Trace(err)
+	}
+
+	all, err := w.config.Backups.List()
+	if err != nil {
+		return errors.Annotate(err, "cannot list backups")
+	}
+	w.setStatus(backups.NewScheduleStatus(all))
+
+	var backup <-chan time.Time
+	for {
+		select {
+		case <-w.catacomb.Dying():
+			return w.catacomb.ErrDying()
+
+		case _, ok := <-configWatcher.Changes():
+			if !ok {
+				return errors.New("controller configuration watcher closed")
+			}
+			cfg, err := w.config.Backend.ControllerConfig()
+			if err != nil {
+				return errors.Annotate(err, "cannot load controller configuration")
+			}
+			interval := cfg.BackupInterval()
+			w.mu.Lock()
+			if interval != w.current.interval {
+				logger.Infof("scheduled backup interval: %v", interval)
+			}
+			w.current.interval = interval
+			w.current.keepDaily = cfg.BackupKeepDaily()
+			w.current.keepWeekly = cfg.BackupKeepWeekly()
+			running := w.current.running
+			w.mu.Unlock()
+			if !running {
+				backup = w.schedule()
+			}
+
+		case <-backup:
+			backup = nil
+			if err := w.startBackup(); err != nil {
+				return errors.Trace(err)
+			}
+
+		case status := <-w.results:
+			w.mu.Lock()
+			w.current.running = false
+			w.current.status = status
+			w.mu.Unlock()
+			backup = w.schedule()
+		}
+	}
+}
+
+// schedule returns a channel on which the next scheduled backup is
+// due, or nil if scheduled backups are disabled. A backup is due one
+// interval after the last attempt.
+func (w *scheduleWorker) schedule() <-chan time.Time {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.current.interval <= 0 {
+		w.current.nextBackup = time.Time{}
+		return nil
+	}
+	now := w.config.Clock.Now()
+	next := now
+	if last := w.current.status.LastAttempt; !last.IsZero() {
+		next = last.Add(w.current.interval)
+	}
+	w.current.nextBackup = next
+	return w.config.Clock.After(next.Sub(now))
+}
+
+// startBackup runs a scheduled backup in a worker tied to the
+// catacomb, so that the loop keeps responding while the backup is
+// created. The outcome is sent on w.results.
+func (w *scheduleWorker) startBackup() error {
+	w.mu.Lock()
+	w.current.running = true
+	w.current.nextBackup = time.Time{}
+	w.mu.Unlock()
+	return w.catacomb.Add(jworker.NewSimpleWorker(func(stopCh <-chan struct{}) error {
+		status, err := w.backup()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		select {
+		case w.results <- status:
+		case <-stopCh:
+		}
+		return nil
+	}))
+}
+
+// backup creates a scheduled backup, applies the retention policy and
+// returns the outcome recorded in the backup metadata. Failure to
+// create the backup is logged rather than returned, so the worker
+// keeps running and retries at the next scheduled time.
+func (w *scheduleWorker) backup() (backups.ScheduleStatus, error) {
+	w.mu.Lock()
+	keepDaily, keepWeekly := w.current.keepDaily, w.current.keepWeekly
+	w.mu.Unlock()
+
+	attempted := w.config.Clock.Now()
+	meta, createErr := w.config.Backups.Create()
+	if createErr != nil {
+		logger.Errorf("scheduled backup failed: %v", createErr)
+	} else {
+		logger.Infof("created scheduled backup %q", meta.ID())
+	}
+
+	all, err := w.config.Backups.List()
+	if err != nil {
+		return backups.ScheduleStatus{}, errors.Annotate(err, "cannot list backups")
+	}
+	all = w.prune(all, keepDaily, keepWeekly)
+	status := backups.NewScheduleStatus(all)
+	if createErr != nil && status.LastAttempt.Before(attempted) {
+		// The backup failed before its metadata could be
+		// recorded; report the failure until the next attempt.
+		status.LastAttempt = attempted
+		status.LastError = createErr.Error()
+	}
+	return status, nil
+}
+
+// prune removes the scheduled backups that fall outside the retention
+// policy, returning the metadata of those that remain. Failures are
+// logged, and retried after the next backup.
+func (w *scheduleWorker) prune(all []*backups.Metadata, keepDaily, keepWeekly int) []*backups.Metadata {
+	removed := make(map[string]bool)
+	for _, id := range expiredBackups(all, keepDaily, keepWeekly) {
+		if err := w.config.Backups.Remove(id); err != nil {
+			logger.Warningf("cannot remove expired backup %q: %v", id, err)
+			continue
+		}
+		logger.Infof("removed expired scheduled backup %q", id)
+		removed[id] = true
+	}
+	var remaining []*backups.Metadata
+	for _, meta := range all {
+		if !removed[meta.ID()] {
+			remaining = append(remaining, meta)
+		}
+	}
+	return remaining
+}
+
+func (w *scheduleWorker) setStatus(status backups.ScheduleStatus) {
+	w.mu.Lock()
+	w.current.status = status
+	w.mu.Unlock()
+}
+
+// Kill implements Worker.Kill().
+func (w *scheduleWorker) Kill() {
+	w.catacomb.Kill(nil)
+}
+
+// Wait implements Worker.Wait().
+func (w *scheduleWorker) Wait() error {
+	return w.catacomb.Wait()
+}
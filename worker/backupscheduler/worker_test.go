@@ -0,0 +1,327 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backupscheduler_test
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/juju/clock/testclock"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/worker.v1"
+	"gopkg.in/juju/worker.v1/workertest"
+
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/backups"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/backupscheduler"
+)
+
+type WorkerSuite struct {
+	testing.IsolationSuite
+
+	clock   *testclock.Clock
+	backend *mockBackend
+	backups *mockBackups
+}
+
+var _ = gc.Suite(&WorkerSuite{})
+
+func (s *WorkerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.clock = testclock.NewClock(time.Date(2019, 5, 26, 2, 0, 0, 0, time.UTC))
+	s.backend = &mockBackend{
+		watcher: apiservertesting.NewFakeNotifyWatcher(),
+		config: controller.Config{
+			controller.BackupInterval:   "24h",
+			controller.BackupKeepDaily:  1,
+			controller.BackupKeepWeekly: 0,
+		},
+	}
+	s.backups = &mockBackups{
+		clock:   s.clock,
+		created: make(chan struct{}, 10),
+	}
+}
+
+func (s *WorkerSuite) startWorker(c *gc.C) worker.Worker {
+	w, err := backupscheduler.NewWorker(backupscheduler.Config{
+		Backend: s.backend,
+		Backups: s.backups,
+		Clock:   s.clock,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(c *gc.C) { workertest.CleanKill(c, w) })
+	return w
+}
+
+func (s *WorkerSuite) waitCreate(c *gc.C) {
+	select {
+	case <-s.backups.created:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for backup")
+	}
+}
+
+type reporter interface {
+	Report() map[string]interface{}
+}
+
+// waitScheduled waits for the worker to schedule its next backup,
+// and returns the worker's report.
+func (s *WorkerSuite) waitScheduled(c *gc.C, w worker.Worker) map[string]interface{} {
+	err := s.clock.WaitAdvance(0, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	return w.(reporter).Report()
+}
+
+func (s *WorkerSuite) TestValidate(c *gc.C) {
+	_, err := backupscheduler.NewWorker(backupscheduler.Config{
+		Backups: s.backups,
+		Clock:   s.clock,
+	})
+	c.Check(err, gc.ErrorMatches, "nil Backend not valid")
+	_, err = backupscheduler.NewWorker(backupscheduler.Config{
+		Backend: s.backend,
+		Clock:   s.clock,
+	})
+	c.Check(err, gc.ErrorMatches, "nil Backups not valid")
+	_, err = backupscheduler.NewWorker(backupscheduler.Config{
+		Backend: s.backend,
+		Backups: s.backups,
+	})
+	c.Check(err, gc.ErrorMatches, "nil Clock not valid")
+}
+
+func (s *WorkerSuite) TestBackupDueAfterInterval(c *gc.C) {
+	s.backups.stored = []*backups.Metadata{
+		newMetadata("backup-0", s.clock.Now().Add(-23*time.Hour), true),
+	}
+	w := s.startWorker(c)
+
+	err := s.clock.WaitAdvance(59*time.Minute, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	select {
+	case <-s.backups.created:
+		c.Fatalf("unexpected backup")
+	case <-time.After(coretesting.ShortWait):
+	}
+
+	s.clock.Advance(time.Minute)
+	s.waitCreate(c)
+	report := s.waitScheduled(c, w)
+	c.Assert(report["last-attempt"], gc.Equals, s.clock.Now())
+	c.Assert(report["last-success"], gc.Equals, s.clock.Now())
+	c.Assert(report["next-backup"], gc.Equals, s.clock.Now().Add(24*time.Hour))
+	c.Assert(s.backups.ids(), jc.DeepEquals, []string{"backup-1"})
+}
+
+func (s *WorkerSuite) TestFirstBackupImmediately(c *gc.C) {
+	s.startWorker(c)
+	err := s.clock.WaitAdvance(0, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	s.waitCreate(c)
+}
+
+func (s *WorkerSuite) TestFailedBackupRecorded(c *gc.C) {
+	lastSuccess := s.clock.Now().Add(-24 * time.Hour)
+	s.backups.stored = []*backups.Metadata{
+		newMetadata("backup-0", lastSuccess, true),
+	}
+	s.backups.createErr = errors.New("mongodump failed")
+	w := s.startWorker(c)
+
+	err := s.clock.WaitAdvance(0, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	s.waitCreate(c)
+	report := s.waitScheduled(c, w)
+	c.Assert(report["last-attempt"], gc.Equals, s.clock.Now())
+	c.Assert(report["last-success"], gc.Equals, lastSuccess)
+	c.Assert(report["last-error"], gc.Equals, "mongodump failed")
+	c.Assert(s.backups.ids(), jc.DeepEquals, []string{"backup-0", "failed-1"})
+
+	// The worker keeps running, and retries at the next interval.
+	// The failure record is removed once it is no longer the most
+	// recent attempt.
+	s.backups.createErr = nil
+	s.clock.Advance(24 * time.Hour)
+	s.waitCreate(c)
+	report = s.waitScheduled(c, w)
+	c.Assert(report["last-success"], gc.Equals, s.clock.Now())
+	c.Assert(report["last-error"], gc.IsNil)
+	c.Assert(s.backups.ids(), jc.DeepEquals, []string{"backup-2"})
+	workertest.CheckAlive(c, w)
+}
+
+func (s *WorkerSuite) TestUnrecordedFailureReported(c *gc.C) {
+	s.backups.createErr = errors.New("cannot get machine")
+	s.backups.unrecorded = true
+	w := s.startWorker(c)
+
+	err := s.clock.WaitAdvance(0, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	s.waitCreate(c)
+	report := s.waitScheduled(c, w)
+	c.Assert(report["last-attempt"], gc.Equals, s.clock.Now())
+	c.Assert(report["last-error"], gc.Equals, "cannot get machine")
+	c.Assert(report["next-backup"], gc.Equals, s.clock.Now().Add(24*time.Hour))
+}
+
+func (s *WorkerSuite) TestExpiredBackupsRemoved(c *gc.C) {
+	yesterday := s.clock.Now().Add(-24 * time.Hour)
+	s.backups.stored = []*backups.Metadata{
+		newMetadata("manual", yesterday.Add(-time.Hour), false),
+		newMetadata("backup-0", yesterday, true),
+	}
+	w := s.startWorker(c)
+
+	err := s.clock.WaitAdvance(0, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	s.waitCreate(c)
+	s.waitScheduled(c, w)
+	s.backups.CheckCallNames(c, "List", "Create", "List", "Remove")
+	s.backups.CheckCall(c, 3, "Remove", "backup-0")
+	c.Assert(s.backups.ids(), jc.DeepEquals, []string{"manual", "backup-1"})
+}
+
+func (s *WorkerSuite) TestConfigChangesHandledDuringBackup(c *gc.C) {
+	s.backups.block = make(chan struct{})
+	w := s.startWorker(c)
+
+	err := s.clock.WaitAdvance(0, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	s.waitCreate(c)
+
+	// The backup is still being created, but the worker still
+	// responds to configuration changes.
+	s.backend.setConfig(controller.Config{
+		controller.BackupInterval:   "12h",
+		controller.BackupKeepDaily:  1,
+		controller.BackupKeepWeekly: 0,
+	})
+	s.backend.watcher.C <- struct{}{}
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		if w.(reporter).Report()["interval"] == 12*time.Hour {
+			break
+		}
+	}
+	report := w.(reporter).Report()
+	c.Assert(report["interval"], gc.Equals, 12*time.Hour)
+	c.Assert(report["running"], jc.IsTrue)
+	c.Assert(report["next-backup"], gc.IsNil)
+
+	close(s.backups.block)
+	report = s.waitScheduled(c, w)
+	c.Assert(report["running"], gc.IsNil)
+	c.Assert(report["next-backup"], gc.Equals, s.clock.Now().Add(12*time.Hour))
+}
+
+func (s *WorkerSuite) TestReport(c *gc.C) {
+	failed := newMetadata("failed-0", s.clock.Now().Add(-time.Hour), true)
+	failed.Failure = "boom"
+	s.backups.stored = []*backups.Metadata{failed}
+	w := s.startWorker(c)
+
+	report := s.waitScheduled(c, w)
+	c.Assert(report, jc.DeepEquals, map[string]interface{}{
+		"interval":     24 * time.Hour,
+		"keep-daily":   1,
+		"keep-weekly":  0,
+		"next-backup":  s.clock.Now().Add(23 * time.Hour),
+		"last-attempt": s.clock.Now().Add(-time.Hour),
+		"last-error":   "boom",
+	})
+}
+
+type mockBackend struct {
+	mu      sync.Mutex
+	watcher *apiservertesting.FakeNotifyWatcher
+	config  controller.Config
+}
+
+func (b *mockBackend) WatchControllerConfig() state.NotifyWatcher {
+	return b.watcher
+}
+
+func (b *mockBackend) ControllerConfig() (controller.Config, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.config, nil
+}
+
+func (b *mockBackend) setConfig(config controller.Config) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.config = config
+}
+
+type mockBackups struct {
+	testing.Stub
+	mu         sync.Mutex
+	clock      *testclock.Clock
+	stored     []*backups.Metadata
+	attempts   int
+	createErr  error
+	unrecorded bool
+	created    chan struct{}
+	block      chan struct{}
+}
+
+func (b *mockBackups) Create() (*backups.Metadata, error) {
+	b.MethodCall(b, "Create")
+	b.created <- struct{}{}
+	if b.block != nil {
+		<-b.block
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.attempts++
+	if b.createErr != nil {
+		if !b.unrecorded {
+			meta := newMetadata(fmt.Sprintf("failed-%d", b.attempts), b.clock.Now(), true)
+			meta.Failure = b.createErr.Error()
+			b.stored = append(b.stored, meta)
+		}
+		return nil, b.createErr
+	}
+	meta := newMetadata(fmt.Sprintf("backup-%d", b.attempts), b.clock.Now(), true)
+	b.stored = append(b.stored, meta)
+	return meta, nil
+}
+
+func (b *mockBackups) List() ([]*backups.Metadata, error) {
+	b.MethodCall(b, "List")
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]*backups.Metadata(nil), b.stored...), nil
+}
+
+func (b *mockBackups) Remove(id string) error {
+	b.MethodCall(b, "Remove", id)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i, meta := range b.stored {
+		if meta.ID() == id {
+			b.stored = append(b.stored[:i], b.stored[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (b *mockBackups) ids() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var ids []string
+	for _, meta := range b.stored {
+		ids = append(ids, meta.ID())
+	}
+	return ids
+}
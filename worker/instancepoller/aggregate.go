@@ -164,10 +164,14 @@ func (a *aggregator) instInfo(id instance.Id, inst instance.Instance) (instanceI
 	if err != nil {
 		return instanceInfo{}, err
 	}
-	return instanceInfo{
-		addr,
-		inst.Status(a.callContext),
-	}, nil
+	info := instanceInfo{
+		addresses: addr,
+		status:    inst.Status(a.callContext),
+	}
+	if inst, ok := inst.(instance.InterruptibleInstance); ok {
+		info.interruptible = inst.Interruptible()
+	}
+	return info, nil
 }
 
 func (a *aggregator) Kill() {
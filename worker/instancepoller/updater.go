@@ -27,10 +27,15 @@ var logger = loggo.GetLogger("juju.worker.instancepoller")
 //
 // When a machine has an address and is started LongPoll will be used to
 // check that the instance address or status has not changed.
+//
+// Instances that the cloud may reclaim at short notice are polled at least
+// every InterruptiblePoll, so that a notice of their interruption is seen
+// before they are gone.
 var (
-	ShortPoll        = 1 * time.Second
-	ShortPollBackoff = 2.0
-	LongPoll         = 15 * time.Minute
+	ShortPoll         = 1 * time.Second
+	ShortPollBackoff  = 2.0
+	LongPoll          = 15 * time.Minute
+	InterruptiblePoll = 1 * time.Minute
 )
 
 type machine interface {
@@ -49,8 +54,9 @@ type machine interface {
 }
 
 type instanceInfo struct {
-	addresses []network.Address
-	status    instance.InstanceStatus
+	addresses     []network.Address
+	status        instance.InstanceStatus
+	interruptible bool
 }
 
 // lifetimeContext was extracted to allow the various context clients to get
@@ -217,6 +223,9 @@ func machineLoop(context machineContext, m machine, lifeChanged <-chan struct{},
 				}
 			}
 		}
+		if instInfo.interruptible && pollInterval > InterruptiblePoll {
+			pollInterval = InterruptiblePoll
+		}
 		return nil
 	}
 
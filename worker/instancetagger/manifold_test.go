@@ -0,0 +1,128 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package instancetagger_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/worker.v1"
+	"gopkg.in/juju/worker.v1/dependency"
+	dt "gopkg.in/juju/worker.v1/dependency/testing"
+
+	"github.com/juju/juju/api/base"
+	apitesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/worker/common"
+	"github.com/juju/juju/worker/instancetagger"
+)
+
+type ManifoldSuite struct {
+	testing.IsolationSuite
+	config instancetagger.ManifoldConfig
+	worker worker.Worker
+	got    instancetagger.Config
+}
+
+var _ = gc.Suite(&ManifoldSuite{})
+
+func (s *ManifoldSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.worker = &fakeWorker{}
+	s.config = instancetagger.ManifoldConfig{
+		APICallerName: "api-caller",
+		EnvironName:   "environ",
+		NewFacade: func(base.APICaller) instancetagger.Facade {
+			return &fakeFacade{}
+		},
+		NewWorker: func(config instancetagger.Config) (worker.Worker, error) {
+			s.got = config
+			return s.worker, nil
+		},
+		NewCredentialValidatorFacade: func(base.APICaller) (common.CredentialAPI, error) {
+			return &fakeCredentialAPI{}, nil
+		},
+	}
+}
+
+func (s *ManifoldSuite) TestValidate(c *gc.C) {
+	c.Assert(s.config.Validate(), jc.ErrorIsNil)
+
+	config := s.config
+	config.APICallerName = ""
+	c.Check(config.Validate(), gc.ErrorMatches, "empty APICallerName not valid")
+	config = s.config
+	config.EnvironName = ""
+	c.Check(config.Validate(), gc.ErrorMatches, "empty EnvironName not valid")
+	config = s.config
+	config.NewFacade = nil
+	c.Check(config.Validate(), gc.ErrorMatches, "nil NewFacade not valid")
+	config = s.config
+	config.NewWorker = nil
+	c.Check(config.Validate(), gc.ErrorMatches, "nil NewWorker not valid")
+	config = s.config
+	config.NewCredentialValidatorFacade = nil
+	c.Check(config.Validate(), gc.ErrorMatches, "nil NewCredentialValidatorFacade not valid")
+}
+
+func (s *ManifoldSuite) TestInputs(c *gc.C) {
+	manifold := instancetagger.Manifold(s.config)
+	c.Assert(manifold.Inputs, jc.SameContents, []string{"api-caller", "environ"})
+}
+
+func (s *ManifoldSuite) TestStart(c *gc.C) {
+	environ := &taggingEnviron{}
+	manifold := instancetagger.Manifold(s.config)
+	w, err := manifold.Start(dt.StubContext(nil, map[string]interface{}{
+		"api-caller": apitesting.APICallerFunc(nil),
+		"environ":    environ,
+	}))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(w, gc.Equals, s.worker)
+	c.Assert(s.got.Tagger, gc.Equals, environ)
+	c.Assert(s.got.CallContext, gc.NotNil)
+}
+
+func (s *ManifoldSuite) TestUninstallsWithoutTagging(c *gc.C) {
+	manifold := instancetagger.Manifold(s.config)
+	_, err := manifold.Start(dt.StubContext(nil, map[string]interface{}{
+		"api-caller": apitesting.APICallerFunc(nil),
+		"environ":    &fakeEnviron{},
+	}))
+	c.Assert(err, gc.Equals, dependency.ErrUninstall)
+}
+
+func (s *ManifoldSuite) TestMissingEnviron(c *gc.C) {
+	manifold := instancetagger.Manifold(s.config)
+	_, err := manifold.Start(dt.StubContext(nil, map[string]interface{}{
+		"api-caller": apitesting.APICallerFunc(nil),
+		"environ":    dependency.ErrMissing,
+	}))
+	c.Assert(errors.Cause(err), gc.Equals, dependency.ErrMissing)
+}
+
+type fakeWorker struct {
+	worker.Worker
+}
+
+type fakeEnviron struct {
+	environs.Environ
+}
+
+type taggingEnviron struct {
+	fakeEnviron
+}
+
+func (*taggingEnviron) TagInstance(context.ProviderCallContext, instance.Id, map[string]string) error {
+	return nil
+}
+
+type fakeCredentialAPI struct{}
+
+func (*fakeCredentialAPI) InvalidateModelCredential(reason string) error {
+	return nil
+}
@@ -0,0 +1,292 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package instancetagger provides a worker that keeps the tags on
+// machine instances up to date, as the units deployed to machines
+// and the model's resource-tags change.
+package instancetagger
+
+import (
+	"sort"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"gopkg.in/juju/names.v2"
+	"gopkg.in/juju/worker.v1"
+	"gopkg.in/juju/worker.v1/catacomb"
+
+	"github.com/juju/juju/api/instancetagger"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/watcher"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/context"
+)
+
+var logger = loggo.GetLogger("juju.worker.instancetagger")
+
+// Facade defines the methods the worker needs from the
+// InstanceTagger API facade.
+type Facade interface {
+	WatchForModelConfigChanges() (watcher.NotifyWatcher, error)
+	WatchModelMachines() (watcher.StringsWatcher, error)
+	WatchUnits(names.MachineTag) (watcher.StringsWatcher, error)
+	Life(names.MachineTag) (params.Life, error)
+	InstanceTags(...names.MachineTag) ([]instancetagger.InstanceTags, error)
+}
+
+// Config holds the dependencies and configuration for an
+// instance tagger worker.
+type Config struct {
+	Facade      Facade
+	Tagger      environs.InstanceTagger
+	CallContext context.ProviderCallContext
+}
+
+// Validate returns an error if the config cannot be used to
+// start a worker.
+func (config Config) Validate() error {
+	if config.Facade == nil {
+		return errors.NotValidf("nil Facade")
+	}
+	if config.Tagger == nil {
+		return errors.NotValidf("nil Tagger")
+	}
+	if config.CallContext == nil {
+		return errors.NotValidf("nil CallContext")
+	}
+	return nil
+}
+
+// NewWorker returns a worker that watches the model config and the
+// units assigned to each top level machine, and updates the tags on
+// the instances of the machines affected by each change.
+func NewWorker(config Config) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := &tagger{
+		config:       config,
+		machines:     make(map[names.MachineTag]*machineData),
+		dirty:        make(map[names.MachineTag]bool),
+		unitsChanged: make(chan names.MachineTag),
+	}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &w.catacomb,
+		Work: w.loop,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+type tagger struct {
+	catacomb catacomb.Catacomb
+	config   Config
+
+	// machines holds the machines whose units are being watched.
+	machines map[names.MachineTag]*machineData
+
+	// dirty records the machines whose instances need to be
+	// tagged.
+	dirty map[names.MachineTag]bool
+
+	unitsChanged chan names.MachineTag
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *tagger) Kill() {
+	w.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *tagger) Wait() error {
+	return w.catacomb.Wait()
+}
+
+func (w *tagger) loop() error {
+	configWatcher, err := w.config.Facade.WatchForModelConfigChanges()
+	if err != nil {
+		return errors.Annotate(err, "watching model config")
+	}
+	if err := w.catacomb.Add(configWatcher); err != nil {
+		return errors.Trace(err)
+	}
+	machinesWatcher, err := w.config.Facade.WatchModelMachines()
+	if err != nil {
+		return errors.Annotate(err, "watching machines")
+	}
+	if err := w.catacomb.Add(machinesWatcher); err != nil {
+		return errors.Trace(err)
+	}
+
+	for {
+		select {
+		case <-w.catacomb.Dying():
+			return w.catacomb.ErrDying()
+		case _, ok := <-configWatcher.Changes():
+			if !ok {
+				return errors.New("model config watcher closed")
+			}
+			// The model's resource-tags may have changed, so
+			// every instance is tagged again.
+			for tag := range w.machines {
+				w.dirty[tag] = true
+			}
+		case ids, ok := <-machinesWatcher.Changes():
+			if !ok {
+				return errors.New("machines watcher closed")
+			}
+			for _, id := range ids {
+				if err := w.machineChanged(names.NewMachineTag(id)); err != nil {
+					return errors.Trace(err)
+				}
+			}
+		case tag := <-w.unitsChanged:
+			if w.machines[tag] != nil {
+				w.dirty[tag] = true
+			}
+		}
+		if err := w.updateTags(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+}
+
+// machineChanged starts watching the units assigned to a machine
+// that has been added, and stops watching those of a machine that
+// has died or been removed.
+func (w *tagger) machineChanged(tag names.MachineTag) error {
+	life, err := w.config.Facade.Life(tag)
+	if params.IsCodeNotFound(err) {
+		life = params.Dead
+	} else if err != nil {
+		return errors.Annotatef(err, "getting life of machine %s", tag.Id())
+	}
+	machined := w.machines[tag]
+	switch {
+	case life == params.Dead && machined != nil:
+		// Unusually, it's fine to ignore this error, because we know
+		// the machined is being tracked in w.catacomb. But we do still
+		// want to wait until the watch loop has stopped.
+		worker.Stop(machined)
+		delete(w.machines, tag)
+		delete(w.dirty, tag)
+		logger.Debugf("stopped watching machine %s", tag.Id())
+	case life != params.Dead && machined == nil:
+		return errors.Trace(w.startMachine(tag))
+	}
+	return nil
+}
+
+// startMachine starts watching the units assigned to a machine. The
+// watcher's initial event marks the machine's instance to be tagged.
+func (w *tagger) startMachine(tag names.MachineTag) error {
+	unitsWatcher, err := w.config.Facade.WatchUnits(tag)
+	if params.IsCodeNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Annotatef(err, "watching units of machine %s", tag.Id())
+	}
+	machined := &machineData{
+		tag:          tag,
+		unitsChanged: w.unitsChanged,
+	}
+	err = catacomb.Invoke(catacomb.Plan{
+		Site: &machined.catacomb,
+		Work: func() error {
+			return machined.watchLoop(unitsWatcher)
+		},
+		Init: []worker.Worker{unitsWatcher},
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := w.catacomb.Add(machined); err != nil {
+		return errors.Trace(err)
+	}
+	w.machines[tag] = machined
+	logger.Debugf("started watching machine %s", tag.Id())
+	return nil
+}
+
+// updateTags tags the instances of the dirty machines. Machines whose
+// instances cannot be tagged, or that have not yet been provisioned,
+// are left dirty to be retried after the next change.
+func (w *tagger) updateTags() error {
+	if len(w.dirty) == 0 {
+		return nil
+	}
+	tags := make([]names.MachineTag, 0, len(w.dirty))
+	for tag := range w.dirty {
+		tags = append(tags, tag)
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		return tags[i].String() < tags[j].String()
+	})
+	instanceTags, err := w.config.Facade.InstanceTags(tags...)
+	if err != nil {
+		return errors.Annotate(err, "getting instance tags")
+	}
+	for _, it := range instanceTags {
+		machineId := it.Machine.Id()
+		switch {
+		case params.IsCodeNotProvisioned(it.Error):
+			logger.Debugf("machine %s is not provisioned yet", machineId)
+			continue
+		case params.IsCodeNotFound(it.Error):
+			// The machine has been removed, and will be forgotten
+			// when the machines watcher reports it.
+		case it.Error != nil:
+			logger.Warningf("cannot get tags for machine %s: %v", machineId, it.Error)
+			continue
+		case it.InstanceId == "":
+			// The machine has no instance for the provider to tag.
+		default:
+			logger.Debugf("tagging instance %q of machine %s", it.InstanceId, machineId)
+			err := w.config.Tagger.TagInstance(w.config.CallContext, it.InstanceId, it.Tags)
+			if err != nil {
+				logger.Warningf("cannot tag instance %q of machine %s: %v", it.InstanceId, machineId, err)
+				continue
+			}
+		}
+		delete(w.dirty, it.Machine)
+	}
+	return nil
+}
+
+// machineData watches the units assigned to a machine, and
+// reports each change to the tagger.
+type machineData struct {
+	catacomb     catacomb.Catacomb
+	tag          names.MachineTag
+	unitsChanged chan<- names.MachineTag
+}
+
+func (md *machineData) watchLoop(unitsWatcher watcher.StringsWatcher) error {
+	for {
+		select {
+		case <-md.catacomb.Dying():
+			return md.catacomb.ErrDying()
+		case _, ok := <-unitsWatcher.Changes():
+			if !ok {
+				return errors.Errorf("units watcher for machine %s closed", md.tag.Id())
+			}
+			select {
+			case <-md.catacomb.Dying():
+				return md.catacomb.ErrDying()
+			case md.unitsChanged <- md.tag:
+			}
+		}
+	}
+}
+
+// Kill is part of the worker.Worker interface.
+func (md *machineData) Kill() {
+	md.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (md *machineData) Wait() error {
+	return md.catacomb.Wait()
+}
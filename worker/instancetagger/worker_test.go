@@ -0,0 +1,324 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package instancetagger_test
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+	"gopkg.in/juju/worker.v1"
+	"gopkg.in/juju/worker.v1/workertest"
+
+	apiinstancetagger "github.com/juju/juju/api/instancetagger"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/watcher"
+	"github.com/juju/juju/core/watcher/watchertest"
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/instance"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/instancetagger"
+)
+
+type WorkerSuite struct {
+	testing.IsolationSuite
+	facade *fakeFacade
+	tagger *fakeTagger
+	config instancetagger.Config
+}
+
+var _ = gc.Suite(&WorkerSuite{})
+
+func (s *WorkerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.facade = newFakeFacade()
+	s.facade.setMachine("0", params.Alive, apiinstancetagger.InstanceTags{
+		InstanceId: "i-0",
+		Tags:       map[string]string{"juju-units-deployed": "mysql/0"},
+	})
+	s.facade.setMachine("1", params.Alive, apiinstancetagger.InstanceTags{
+		Error: errors.New("boom"),
+	})
+	s.facade.setMachine("2", params.Alive, apiinstancetagger.InstanceTags{
+		InstanceId: "i-2",
+		Tags:       map[string]string{"owner": "wordpress"},
+	})
+	s.tagger = &fakeTagger{
+		tagged: make(chan instance.Id, 10),
+		failed: make(chan instance.Id, 10),
+	}
+	s.config = instancetagger.Config{
+		Facade:      s.facade,
+		Tagger:      s.tagger,
+		CallContext: context.NewCloudCallContext(),
+	}
+}
+
+func (s *WorkerSuite) TestValidate(c *gc.C) {
+	c.Assert(s.config.Validate(), jc.ErrorIsNil)
+
+	config := s.config
+	config.Facade = nil
+	c.Check(config.Validate(), gc.ErrorMatches, "nil Facade not valid")
+	config = s.config
+	config.Tagger = nil
+	c.Check(config.Validate(), gc.ErrorMatches, "nil Tagger not valid")
+	config = s.config
+	config.CallContext = nil
+	c.Check(config.Validate(), gc.ErrorMatches, "nil CallContext not valid")
+}
+
+// startWorker starts a worker, and waits for it to tag the
+// instances of the machines reported by the machines watcher.
+func (s *WorkerSuite) startWorker(c *gc.C) worker.Worker {
+	w, err := instancetagger.NewWorker(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	s.facade.configChanges <- struct{}{}
+	s.facade.machinesChanges <- []string{"0", "1", "2"}
+	s.waitForTagging(c, "i-0", "i-2")
+	s.tagger.ResetCalls()
+	return w
+}
+
+func (s *WorkerSuite) TestTagsInstancesOnStart(c *gc.C) {
+	w := s.startWorker(c)
+	defer workertest.CleanKill(c, w)
+	s.assertNoTagging(c)
+}
+
+func (s *WorkerSuite) TestTagsInstanceWhenUnitsChange(c *gc.C) {
+	w := s.startWorker(c)
+	defer workertest.CleanKill(c, w)
+
+	s.facade.setMachine("0", params.Alive, apiinstancetagger.InstanceTags{
+		InstanceId: "i-0",
+		Tags:       map[string]string{"juju-units-deployed": "mysql/0 wordpress/0"},
+	})
+	s.facade.unitsChanges("0") <- []string{"wordpress/0"}
+	s.waitForTagging(c, "i-0")
+	s.tagger.CheckCalls(c, []testing.StubCall{
+		{"TagInstance", []interface{}{instance.Id("i-0"), map[string]string{"juju-units-deployed": "mysql/0 wordpress/0"}}},
+	})
+	s.assertNoTagging(c)
+}
+
+func (s *WorkerSuite) TestTagsAllInstancesWhenModelConfigChanges(c *gc.C) {
+	w := s.startWorker(c)
+	defer workertest.CleanKill(c, w)
+
+	s.facade.configChanges <- struct{}{}
+	s.waitForTagging(c, "i-0", "i-2")
+	s.assertNoTagging(c)
+}
+
+func (s *WorkerSuite) TestTagsAddedMachine(c *gc.C) {
+	w := s.startWorker(c)
+	defer workertest.CleanKill(c, w)
+
+	s.facade.setMachine("3", params.Alive, apiinstancetagger.InstanceTags{
+		InstanceId: "i-3",
+		Tags:       map[string]string{"owner": "mysql"},
+	})
+	s.facade.machinesChanges <- []string{"3"}
+	s.waitForTagging(c, "i-3")
+	s.assertNoTagging(c)
+}
+
+func (s *WorkerSuite) TestStopsWatchingDeadMachine(c *gc.C) {
+	w := s.startWorker(c)
+	defer workertest.CleanKill(c, w)
+
+	unitsWatcher := s.facade.unitsWatcher("2")
+	s.facade.setMachine("2", params.Dead, apiinstancetagger.InstanceTags{})
+	s.facade.machinesChanges <- []string{"2"}
+	workertest.CheckKilled(c, unitsWatcher)
+
+	s.facade.configChanges <- struct{}{}
+	s.waitForTagging(c, "i-0")
+	s.assertNoTagging(c)
+}
+
+func (s *WorkerSuite) TestRetriesFailedTagging(c *gc.C) {
+	s.tagger.failures = map[instance.Id]error{"i-2": errors.New("rate limited")}
+	w, err := instancetagger.NewWorker(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+	s.facade.configChanges <- struct{}{}
+	s.facade.machinesChanges <- []string{"0", "1", "2"}
+	s.waitForTagging(c, "i-0")
+	select {
+	case <-s.tagger.failed:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for tagging to fail")
+	}
+
+	// The instance that could not be tagged is tagged
+	// again along with the next change.
+	s.facade.unitsChanges("0") <- []string{"mysql/0"}
+	s.waitForTagging(c, "i-0", "i-2")
+	s.assertNoTagging(c)
+}
+
+func (s *WorkerSuite) TestFacadeError(c *gc.C) {
+	s.facade.setErr(errors.New("no tags for you"))
+
+	w, err := instancetagger.NewWorker(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	s.facade.configChanges <- struct{}{}
+	s.facade.machinesChanges <- []string{"0"}
+	err = workertest.CheckKilled(c, w)
+	c.Assert(err, gc.ErrorMatches, "getting instance tags: no tags for you")
+}
+
+// waitForTagging waits for the given instances, and only those,
+// to be tagged.
+func (s *WorkerSuite) waitForTagging(c *gc.C, ids ...instance.Id) {
+	expect := make(map[instance.Id]bool)
+	for _, id := range ids {
+		expect[id] = true
+	}
+	got := make(map[instance.Id]bool)
+	for len(got) < len(expect) {
+		select {
+		case id := <-s.tagger.tagged:
+			c.Assert(expect[id], jc.IsTrue, gc.Commentf("unexpected tagging of %q", id))
+			got[id] = true
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("timed out waiting for %v to be tagged; got %v", ids, got)
+		}
+	}
+}
+
+func (s *WorkerSuite) assertNoTagging(c *gc.C) {
+	select {
+	case id := <-s.tagger.tagged:
+		c.Fatalf("unexpected tagging of %q", id)
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+type fakeFacade struct {
+	mu              sync.Mutex
+	configChanges   chan struct{}
+	machinesChanges chan []string
+	units           map[string]chan []string
+	unitsWatchers   map[string]*watchertest.MockStringsWatcher
+	life            map[string]params.Life
+	tags            map[string]apiinstancetagger.InstanceTags
+	err             error
+}
+
+func newFakeFacade() *fakeFacade {
+	return &fakeFacade{
+		configChanges:   make(chan struct{}),
+		machinesChanges: make(chan []string),
+		units:           make(map[string]chan []string),
+		unitsWatchers:   make(map[string]*watchertest.MockStringsWatcher),
+		life:            make(map[string]params.Life),
+		tags:            make(map[string]apiinstancetagger.InstanceTags),
+	}
+}
+
+func (f *fakeFacade) setMachine(id string, life params.Life, tags apiinstancetagger.InstanceTags) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.life[id] = life
+	f.tags[id] = tags
+	if _, ok := f.units[id]; !ok {
+		f.units[id] = make(chan []string, 1)
+	}
+}
+
+func (f *fakeFacade) setErr(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.err = err
+}
+
+func (f *fakeFacade) unitsChanges(id string) chan<- []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.units[id]
+}
+
+func (f *fakeFacade) unitsWatcher(id string) *watchertest.MockStringsWatcher {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.unitsWatchers[id]
+}
+
+func (f *fakeFacade) WatchForModelConfigChanges() (watcher.NotifyWatcher, error) {
+	return watchertest.NewMockNotifyWatcher(f.configChanges), nil
+}
+
+func (f *fakeFacade) WatchModelMachines() (watcher.StringsWatcher, error) {
+	return watchertest.NewMockStringsWatcher(f.machinesChanges), nil
+}
+
+func (f *fakeFacade) WatchUnits(tag names.MachineTag) (watcher.StringsWatcher, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	units, ok := f.units[tag.Id()]
+	if !ok {
+		return nil, &params.Error{Code: params.CodeNotFound}
+	}
+	// Send the initial event.
+	units <- []string{}
+	w := watchertest.NewMockStringsWatcher(units)
+	f.unitsWatchers[tag.Id()] = w
+	return w, nil
+}
+
+func (f *fakeFacade) Life(tag names.MachineTag) (params.Life, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	life, ok := f.life[tag.Id()]
+	if !ok {
+		return "", &params.Error{Code: params.CodeNotFound}
+	}
+	return life, nil
+}
+
+func (f *fakeFacade) InstanceTags(machines ...names.MachineTag) ([]apiinstancetagger.InstanceTags, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return nil, f.err
+	}
+	results := make([]apiinstancetagger.InstanceTags, len(machines))
+	for i, tag := range machines {
+		result, ok := f.tags[tag.Id()]
+		if !ok {
+			result.Error = &params.Error{Code: params.CodeNotFound}
+		}
+		result.Machine = tag
+		results[i] = result
+	}
+	return results, nil
+}
+
+type fakeTagger struct {
+	testing.Stub
+	tagged chan instance.Id
+	failed chan instance.Id
+
+	// failures holds the errors with which the first attempts
+	// to tag the corresponding instances fail.
+	failures map[instance.Id]error
+}
+
+func (t *fakeTagger) TagInstance(ctx context.ProviderCallContext, id instance.Id, tags map[string]string) error {
+	t.MethodCall(t, "TagInstance", id, tags)
+	if err, ok := t.failures[id]; ok {
+		delete(t.failures, id)
+		t.failed <- id
+		return err
+	}
+	t.tagged <- id
+	return nil
+}
@@ -108,8 +108,17 @@ juju_statetracker_report () {
 }
 
 juju_machine_lock () {
+  # Optional args select extra report sections, any of:
+  # history, contention, yaml, stack.
+  local query=
+  for opt in "$@"; do
+    query="$query&$opt=true"
+  done
+  if [ -n "$query" ]; then
+    query="?${query#&}"
+  fi
   for agent in $(ls /var/lib/juju/agents); do
-    juju_machine_or_unit machinelock $agent 2> /dev/null
+    juju_machine_or_unit "machinelock/$query" $agent 2> /dev/null
   done
 }
 
@@ -5,6 +5,7 @@ package introspection
 
 import (
 	"io/ioutil"
+	"os/exec"
 	"runtime"
 
 	"github.com/juju/testing"
@@ -43,3 +44,23 @@ func (s *profileSuite) TestLinux(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(string(content), gc.Equals, bashFuncs)
 }
+
+func (*profileSuite) TestMachineLockQuery(c *gc.C) {
+	if runtime.GOOS != "linux" {
+		c.Skip("testing linux")
+	}
+	// Stub out the agent lookup and the socket call so that only the
+	// query built from the arguments is checked.
+	script := bashFuncs + `
+ls () { echo machine-0; }
+juju_machine_or_unit () { echo "$1 $2"; }
+juju_machine_lock
+juju_machine_lock history contention
+`
+	out, err := exec.Command("bash", "-c", script).CombinedOutput()
+	c.Assert(err, jc.ErrorIsNil, gc.Commentf("%s", out))
+	c.Assert(string(out), gc.Equals, `
+machinelock/ machine-0
+machinelock/?history=true&contention=true machine-0
+`[1:])
+}
@@ -237,6 +237,9 @@ func (h machineLockHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if v := q.Get("stack"); v != "" {
 		args = append(args, machinelock.ShowStack)
 	}
+	if v := q.Get("contention"); v != "" {
+		args = append(args, machinelock.ShowContention)
+	}
 
 	content, err := h.lock.Report(args...)
 	if err != nil {
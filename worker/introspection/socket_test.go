@@ -23,6 +23,7 @@ import (
 	"gopkg.in/juju/worker.v1/workertest"
 
 	// Bring in the state package for the tracker profile.
+	"github.com/juju/juju/core/machinelock"
 	"github.com/juju/juju/core/presence"
 	_ "github.com/juju/juju/state"
 	"github.com/juju/juju/worker/introspection"
@@ -61,6 +62,7 @@ type introspectionSuite struct {
 	reporter introspection.DepEngineReporter
 	gatherer prometheus.Gatherer
 	recorder presence.Recorder
+	lock     machinelock.Lock
 }
 
 var _ = gc.Suite(&introspectionSuite{})
@@ -73,6 +75,7 @@ func (s *introspectionSuite) SetUpTest(c *gc.C) {
 	s.reporter = nil
 	s.worker = nil
 	s.recorder = nil
+	s.lock = nil
 	s.gatherer = newPrometheusGatherer()
 	s.startWorker(c)
 }
@@ -84,6 +87,7 @@ func (s *introspectionSuite) startWorker(c *gc.C) {
 		DepEngine:          s.reporter,
 		PrometheusGatherer: s.gatherer,
 		Presence:           s.recorder,
+		MachineLock:        s.lock,
 	})
 	c.Assert(err, jc.ErrorIsNil)
 	s.worker = w
@@ -148,6 +152,27 @@ func (s *introspectionSuite) TestMissingMachineLock(c *gc.C) {
 	matches(c, buf, "missing machine lock reporter")
 }
 
+func (s *introspectionSuite) TestMachineLock(c *gc.C) {
+	// We need to make sure the existing worker is shut down
+	// so we can connect to the socket.
+	workertest.CheckKill(c, s.worker)
+	lock := &fakeLock{}
+	s.lock = lock
+	s.startWorker(c)
+
+	buf := s.call(c, "/machinelock/")
+	matches(c, buf, "200 OK")
+	matches(c, buf, "holder: none")
+	c.Check(lock.opts, gc.HasLen, 0)
+
+	buf = s.call(c, "/machinelock/?history=true&contention=true")
+	matches(c, buf, "200 OK")
+	c.Check(lock.opts, jc.DeepEquals, []machinelock.ReportOption{
+		machinelock.ShowHistory,
+		machinelock.ShowContention,
+	})
+}
+
 func (s *introspectionSuite) TestStateTrackerReporter(c *gc.C) {
 	buf := s.call(c, "/debug/pprof/juju/state/tracker?debug=1")
 	matches(c, buf, "200 OK")
@@ -242,3 +267,13 @@ func newPrometheusGatherer() prometheus.Gatherer {
 	r.MustRegister(counter)
 	return r
 }
+
+type fakeLock struct {
+	machinelock.Lock
+	opts []machinelock.ReportOption
+}
+
+func (l *fakeLock) Report(opts ...machinelock.ReportOption) (string, error) {
+	l.opts = opts
+	return "holder: none\n", nil
+}
@@ -373,26 +373,63 @@ func (p *peerGroupChanges) reviewPeerGroupChanges() {
 		// if this is true we will create an odd number of voters
 		return
 	}
+	// Whether we hold back a new voter or remove an existing one, choose
+	// a machine in the zone holding the most voters, so that the voters
+	// are spread across as many availability zones as possible.
+	zoneVoters := p.zoneVoters()
 	if len(p.toAddVote) > 0 {
-		last := p.toAddVote[len(p.toAddVote)-1]
-		logger.Debugf("number of voters would be even, not adding %q to maintain odd", last)
-		p.toAddVote = p.toAddVote[:len(p.toAddVote)-1]
+		// Prefer not to add the last candidate when zones don't decide.
+		skip := len(p.toAddVote) - 1
+		for i := skip - 1; i >= 0; i-- {
+			if zoneVoters[p.machineZone(p.toAddVote[i])] > zoneVoters[p.machineZone(p.toAddVote[skip])] {
+				skip = i
+			}
+		}
+		logger.Debugf("number of voters would be even, not adding %q to maintain odd", p.toAddVote[skip])
+		p.toAddVote = append(p.toAddVote[:skip], p.toAddVote[skip+1:]...)
 		return
 	}
 	// we must remove an extra peer
 	// make sure we don't pick the primary to be removed.
+	remove := -1
 	for i, id := range p.toKeepVoting {
-		if !isPrimaryMember(p.info, id) {
-			p.toRemoveVote = append(p.toRemoveVote, id)
-			logger.Debugf("removing vote from %q to maintain odd number of voters", id)
-			if i == len(p.toKeepVoting)-1 {
-				p.toKeepVoting = p.toKeepVoting[:i]
-			} else {
-				p.toKeepVoting = append(p.toKeepVoting[:i], p.toKeepVoting[i+1:]...)
+		if isPrimaryMember(p.info, id) {
+			continue
+		}
+		if remove == -1 || zoneVoters[p.machineZone(id)] > zoneVoters[p.machineZone(p.toKeepVoting[remove])] {
+			remove = i
+		}
+	}
+	if remove != -1 {
+		id := p.toKeepVoting[remove]
+		p.toRemoveVote = append(p.toRemoveVote, id)
+		logger.Debugf("removing vote from %q to maintain odd number of voters", id)
+		p.toKeepVoting = append(p.toKeepVoting[:remove], p.toKeepVoting[remove+1:]...)
+	}
+}
+
+// zoneVoters returns the number of machines in each known availability
+// zone that are to keep or gain a vote. Machines in unknown zones are
+// not counted.
+func (p *peerGroupChanges) zoneVoters() map[string]int {
+	counts := make(map[string]int)
+	for _, ids := range [][]string{p.toKeepVoting, p.toAddVote} {
+		for _, id := range ids {
+			if zone := p.machineZone(id); zone != "" {
+				counts[zone]++
 			}
-			break
 		}
 	}
+	return counts
+}
+
+// machineZone returns the availability zone of the machine with the
+// given ID, or the empty string if it is not known.
+func (p *peerGroupChanges) machineZone(id string) string {
+	if m, ok := p.info.machines[id]; ok {
+		return m.AvailabilityZone()
+	}
+	return ""
 }
 
 func isVotingMember(m *replicaset.Member) bool {
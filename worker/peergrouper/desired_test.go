@@ -330,6 +330,22 @@ func desiredPeerGroupTests(ipVersion TestIPVersion) []desiredPeerGroupTest {
 			expectMembers:  mkMembers("1 2 3v", ipVersion),
 			expectStepDown: true,
 			expectChanged:  true,
+		}, {
+			about:         "even number of voters -> candidate in the most used zone is not added",
+			machines:      withZones(mkMachines("11v 12v 13v 14v", ipVersion), "az1", "az2", "az1", "az3"),
+			members:       mkMembers("1v 2 3 4", ipVersion),
+			statuses:      mkStatuses("1p 2s 3s 4s", ipVersion),
+			expectVoting:  []bool{true, true, false, true},
+			expectMembers: mkMembers("1v 2v 3 4v", ipVersion),
+			expectChanged: true,
+		}, {
+			about:         "even number of voters -> voter in the most used zone loses its vote",
+			machines:      withZones(mkMachines("11v 12v 13v 14v 15", ipVersion), "az1", "az2", "az3", "az1", "az2"),
+			members:       mkMembers("1v 2v 3v 4v 5v", ipVersion),
+			statuses:      mkStatuses("1p 2s 3s 4s 5s", ipVersion),
+			expectVoting:  []bool{true, true, true, false, false},
+			expectMembers: mkMembers("1v 2v 3v 4 5", ipVersion),
+			expectChanged: true,
 		},
 	}
 }
@@ -482,6 +498,14 @@ func mkMachines(description string, ipVersion TestIPVersion) []*machineTracker {
 	return ms
 }
 
+// withZones sets the availability zones of the given machines, in order.
+func withZones(machines []*machineTracker, zones ...string) []*machineTracker {
+	for i, zone := range zones {
+		machines[i].zone = zone
+	}
+	return machines
+}
+
 func memberTag(id string) map[string]string {
 	return map[string]string{jujuMachineKey: id}
 }
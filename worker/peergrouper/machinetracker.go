@@ -26,18 +26,22 @@ type machineTracker struct {
 	// Outside of the machineTracker implementation itself, these
 	// should always be accessed via the getter methods in order to be
 	// protected by the mutex.
-	id        string
-	wantsVote bool
-	addresses []network.Address
+	id          string
+	wantsVote   bool
+	addresses   []network.Address
+	zone        string
+	readReplica bool
 }
 
 func newMachineTracker(stm Machine, notifyCh chan struct{}) (*machineTracker, error) {
 	m := &machineTracker{
-		notifyCh:  notifyCh,
-		id:        stm.Id(),
-		stm:       stm,
-		addresses: stm.Addresses(),
-		wantsVote: stm.WantsVote(),
+		notifyCh:    notifyCh,
+		id:          stm.Id(),
+		stm:         stm,
+		addresses:   stm.Addresses(),
+		wantsVote:   stm.WantsVote(),
+		zone:        machineZone(stm),
+		readReplica: stm.IsReadReplica(),
 	}
 	err := catacomb.Invoke(catacomb.Plan{
 		Site: &m.catacomb,
@@ -83,6 +87,35 @@ func (m *machineTracker) Addresses() []network.Address {
 	return out
 }
 
+// IsReadReplica returns whether the machine is a read replica
+// controller. This never changes during the life of the machine.
+func (m *machineTracker) IsReadReplica() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.readReplica
+}
+
+// AvailabilityZone returns the availability zone of the machine's
+// instance, or the empty string if it is not known.
+func (m *machineTracker) AvailabilityZone() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.zone
+}
+
+// machineZone returns the availability zone of the machine, or the empty
+// string if the machine is not provisioned or its zone is not known.
+func machineZone(stm Machine) string {
+	zone, err := stm.AvailabilityZone()
+	if err != nil {
+		if !errors.IsNotProvisioned(err) {
+			logger.Warningf("cannot get availability zone of machine %q: %v", stm.Id(), err)
+		}
+		return ""
+	}
+	return zone
+}
+
 // SelectMongoAddress returns the best address on the machine for MongoDB peer
 // use, using the input space.
 // An error is returned if the empty space is supplied.
@@ -125,8 +158,8 @@ func (m *machineTracker) GoString() string {
 	defer m.mu.Unlock()
 
 	return fmt.Sprintf(
-		"&peergrouper.machine{id: %q, wantsVote: %v, addresses: %v}",
-		m.id, m.wantsVote, m.addresses,
+		"&peergrouper.machine{id: %q, wantsVote: %v, addresses: %v, zone: %q}",
+		m.id, m.wantsVote, m.addresses, m.zone,
 	)
 }
 
@@ -183,5 +216,9 @@ func (m *machineTracker) hasChanged() (bool, error) {
 		m.addresses = addrs
 		changed = true
 	}
+	if zone := machineZone(m.stm); zone != m.zone {
+		m.zone = zone
+		changed = true
+	}
 	return changed, nil
 }
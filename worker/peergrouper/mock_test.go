@@ -307,13 +307,15 @@ type fakeMachine struct {
 }
 
 type machineDoc struct {
-	id         string
-	wantsVote  bool
-	hasVote    bool
-	instanceId instance.Id
-	addresses  []network.Address
-	statusInfo status.StatusInfo
-	life       state.Life
+	id          string
+	wantsVote   bool
+	hasVote     bool
+	instanceId  instance.Id
+	addresses   []network.Address
+	statusInfo  status.StatusInfo
+	life        state.Life
+	zone        string
+	readReplica bool
 }
 
 func (m *fakeMachine) doc() machineDoc {
@@ -372,6 +374,18 @@ func (m *fakeMachine) Addresses() []network.Address {
 	return m.doc().addresses
 }
 
+func (m *fakeMachine) IsReadReplica() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.doc().readReplica
+}
+
+func (m *fakeMachine) AvailabilityZone() (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.doc().zone, nil
+}
+
 func (m *fakeMachine) Status() (status.StatusInfo, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
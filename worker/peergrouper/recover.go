@@ -0,0 +1,41 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package peergrouper
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/replicaset"
+)
+
+// RecoveryMembers splits the replica set members of a controller that
+// has lost a majority of its voting machines into the member for the
+// surviving machine with the given id, which is made a voter, and the
+// members for the other machines, which are to be removed. Forcing a
+// replica set configuration with only the survivor lets it become
+// primary again; the peer grouper adds members for the other
+// controller machines as usual once they are healthy.
+func RecoveryMembers(members []replicaset.Member, machineId string) (replicaset.Member, []replicaset.Member, error) {
+	var survivor *replicaset.Member
+	var lost []replicaset.Member
+	for i, m := range members {
+		if m.Tags[jujuMachineKey] == machineId {
+			survivor = &members[i]
+			continue
+		}
+		lost = append(lost, m)
+	}
+	if survivor == nil {
+		return replicaset.Member{}, nil, errors.NotFoundf("replica set member for machine %q", machineId)
+	}
+	result := *survivor
+	setMemberVoting(&result, true)
+	return result, lost, nil
+}
+
+// MemberMachineId returns the id of the controller machine for the
+// replica set member, if known.
+func MemberMachineId(member replicaset.Member) (string, bool) {
+	id, ok := member.Tags[jujuMachineKey]
+	return id, ok
+}
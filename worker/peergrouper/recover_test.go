@@ -0,0 +1,47 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package peergrouper
+
+import (
+	"github.com/juju/replicaset"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+)
+
+type recoverySuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&recoverySuite{})
+
+func recoveryMember(id int, machineId string, voting bool) replicaset.Member {
+	m := replicaset.Member{
+		Id:      id,
+		Address: "10.0.0." + machineId + ":37017",
+		Tags:    map[string]string{jujuMachineKey: machineId},
+	}
+	setMemberVoting(&m, voting)
+	return m
+}
+
+func (s *recoverySuite) TestRecoveryMembers(c *gc.C) {
+	members := []replicaset.Member{
+		recoveryMember(1, "0", true),
+		recoveryMember(2, "1", true),
+		recoveryMember(3, "2", false),
+	}
+	survivor, lost, err := RecoveryMembers(members, "2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(survivor, jc.DeepEquals, recoveryMember(3, "2", true))
+	c.Assert(lost, jc.DeepEquals, members[:2])
+
+	// The members passed in are left alone.
+	c.Assert(members[2], jc.DeepEquals, recoveryMember(3, "2", false))
+}
+
+func (s *recoverySuite) TestRecoveryMembersNotFound(c *gc.C) {
+	_, _, err := RecoveryMembers([]replicaset.Member{recoveryMember(1, "0", true)}, "1")
+	c.Assert(err, gc.ErrorMatches, `replica set member for machine "1" not found`)
+}
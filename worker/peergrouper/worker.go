@@ -54,6 +54,8 @@ type Machine interface {
 	HasVote() bool
 	SetHasVote(hasVote bool) error
 	Addresses() []network.Address
+	AvailabilityZone() (string, error)
+	IsReadReplica() bool
 }
 
 type MongoSession interface {
@@ -262,7 +264,13 @@ func (w *pgWorker) loop() error {
 
 		servers := w.apiServerHostPorts()
 		apiHostPorts := make([][]network.HostPort, 0, len(servers))
-		for _, serverHostPorts := range servers {
+		for id, serverHostPorts := range servers {
+			// Agents need to make changes, so they are never
+			// told about the read-only API servers of read
+			// replicas.
+			if w.machineTrackers[id].IsReadReplica() {
+				continue
+			}
 			apiHostPorts = append(apiHostPorts, serverHostPorts)
 		}
 
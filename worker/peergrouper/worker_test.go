@@ -470,6 +470,30 @@ func (s *workerSuite) TestControllersArePublished(c *gc.C) {
 	})
 }
 
+func (s *workerSuite) TestReadReplicasAreNotPublished(c *gc.C) {
+	publishCh := make(chan [][]network.HostPort, 10)
+	publish := func(apiServers [][]network.HostPort) error {
+		publishCh <- apiServers
+		return nil
+	}
+
+	st := NewFakeState()
+	InitState(c, st, 3, testIPv4)
+	st.machine("12").mutate(func(doc *machineDoc) {
+		doc.wantsVote = false
+		doc.readReplica = true
+	})
+	w := s.newWorker(c, st, st.session, SetAPIHostPortsFunc(publish))
+	defer workertest.CleanKill(c, w)
+
+	select {
+	case servers := <-publishCh:
+		AssertAPIHostPorts(c, servers, ExpectedAPIHostPorts(2, testIPv4))
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for publish")
+	}
+}
+
 func (s *workerSuite) TestControllersArePublishedOverHub(c *gc.C) {
 	st := NewFakeState()
 	InitState(c, st, 3, testIPv4)
@@ -0,0 +1,221 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package raftadmin
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/raft"
+	"github.com/juju/errors"
+	"github.com/juju/pubsub"
+	"gopkg.in/juju/worker.v1"
+	"gopkg.in/juju/worker.v1/catacomb"
+
+	"github.com/juju/juju/pubsub/raftcluster"
+)
+
+// removeServerTimeout is how long the leader waits for the removal of
+// a server to be committed.
+const removeServerTimeout = 10 * time.Second
+
+// This worker answers requests about the local raft node published
+// over the central hub by the RaftCluster facade. Every controller
+// machine reports the state of its own node; only the leader handles
+// requests to change the raft configuration.
+
+// RaftNode captures the part of the *raft.Raft API needed by the
+// raftadmin worker.
+type RaftNode interface {
+	State() raft.RaftState
+	Leader() raft.ServerAddress
+	Stats() map[string]string
+	AppliedIndex() uint64
+	LastIndex() uint64
+	LastContact() time.Time
+	GetConfiguration() raft.ConfigurationFuture
+	RemoveServer(id raft.ServerID, prevIndex uint64, timeout time.Duration) raft.IndexFuture
+}
+
+// Logger represents the logging methods called.
+type Logger interface {
+	Infof(message string, args ...interface{})
+	Tracef(message string, args ...interface{})
+}
+
+// Config holds the values needed by the worker.
+type Config struct {
+	Raft    RaftNode
+	Hub     *pubsub.StructuredHub
+	Logger  Logger
+	LocalID raft.ServerID
+}
+
+// Validate validates the raftadmin worker configuration.
+func (config Config) Validate() error {
+	if config.Raft == nil {
+		return errors.NotValidf("nil Raft")
+	}
+	if config.Hub == nil {
+		return errors.NotValidf("nil Hub")
+	}
+	if config.Logger == nil {
+		return errors.NotValidf("nil Logger")
+	}
+	if config.LocalID == "" {
+		return errors.NotValidf("empty LocalID")
+	}
+	return nil
+}
+
+// NewWorker returns a worker that reports the state of the local raft
+// node, and removes servers from the raft configuration on request
+// while the node is the leader.
+func NewWorker(config Config) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := &adminWorker{config: config}
+	unsubscribeStatus, err := config.Hub.Subscribe(raftcluster.StatusRequestTopic, w.handleStatusRequest)
+	if err != nil {
+		return nil, errors.Annotatef(err, "subscribing to %q", raftcluster.StatusRequestTopic)
+	}
+	unsubscribeRemove, err := config.Hub.Subscribe(raftcluster.RemoveServerRequestTopic, w.handleRemoveServerRequest)
+	if err != nil {
+		unsubscribeStatus()
+		return nil, errors.Annotatef(err, "subscribing to %q", raftcluster.RemoveServerRequestTopic)
+	}
+	unsubscribe := func() {
+		unsubscribeStatus()
+		unsubscribeRemove()
+	}
+	if err := catacomb.Invoke(catacomb.Plan{
+		Site: &w.catacomb,
+		Work: func() error {
+			defer unsubscribe()
+			<-w.catacomb.Dying()
+			return w.catacomb.ErrDying()
+		},
+	}); err != nil {
+		unsubscribe()
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+type adminWorker struct {
+	catacomb catacomb.Catacomb
+	config   Config
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *adminWorker) Kill() {
+	w.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *adminWorker) Wait() error {
+	return w.catacomb.Wait()
+}
+
+func (w *adminWorker) handleStatusRequest(_ string, req raftcluster.StatusRequest, err error) {
+	w.config.Logger.Tracef("received %#v, err: %s", req, err)
+	if err != nil {
+		// This should never happen, so treat it as fatal.
+		w.catacomb.Kill(errors.Annotate(err, "status request callback failed"))
+		return
+	}
+	if _, err := w.config.Hub.Publish(req.ResponseTopic, w.status()); err != nil {
+		w.catacomb.Kill(errors.Annotate(err, "publishing status"))
+	}
+}
+
+func (w *adminWorker) status() raftcluster.Status {
+	r := w.config.Raft
+	state := r.State()
+	stats := r.Stats()
+	status := raftcluster.Status{
+		ServerID:          string(w.config.LocalID),
+		State:             state.String(),
+		Leader:            string(r.Leader()),
+		Term:              parseStat(stats, "term"),
+		AppliedIndex:      r.AppliedIndex(),
+		LastIndex:         r.LastIndex(),
+		LastSnapshotIndex: parseStat(stats, "last_snapshot_index"),
+		LastSnapshotTerm:  parseStat(stats, "last_snapshot_term"),
+	}
+	if state != raft.Leader {
+		if t := r.LastContact(); !t.IsZero() {
+			status.LastContact = t.UTC().Format(time.RFC3339)
+		}
+	}
+	future := r.GetConfiguration()
+	if err := future.Error(); err != nil {
+		status.Error = errors.Annotate(err, "getting raft configuration").Error()
+		return status
+	}
+	for _, server := range future.Configuration().Servers {
+		status.Servers = append(status.Servers, raftcluster.Server{
+			ID:       string(server.ID),
+			Address:  string(server.Address),
+			Suffrage: server.Suffrage.String(),
+		})
+	}
+	return status
+}
+
+// parseStat returns the numeric raft statistic with the given name,
+// or zero if it is missing.
+func parseStat(stats map[string]string, name string) uint64 {
+	value, err := strconv.ParseUint(stats[name], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return value
+}
+
+func (w *adminWorker) handleRemoveServerRequest(_ string, req raftcluster.RemoveServerRequest, err error) {
+	w.config.Logger.Tracef("received %#v, err: %s", req, err)
+	if err != nil {
+		// This should never happen, so treat it as fatal.
+		w.catacomb.Kill(errors.Annotate(err, "remove server request callback failed"))
+		return
+	}
+	if w.config.Raft.State() != raft.Leader {
+		// Configuration changes can only be made by the leader.
+		return
+	}
+	var response raftcluster.RemoveServerResponse
+	if err := w.removeServer(raft.ServerID(req.ServerID)); err != nil {
+		response.Error = err.Error()
+	}
+	if _, err := w.config.Hub.Publish(req.ResponseTopic, response); err != nil {
+		w.catacomb.Kill(errors.Annotate(err, "publishing remove server response"))
+	}
+}
+
+func (w *adminWorker) removeServer(id raft.ServerID) error {
+	if id == w.config.LocalID {
+		return errors.Errorf("cannot remove raft leader %q", id)
+	}
+	future := w.config.Raft.GetConfiguration()
+	if err := future.Error(); err != nil {
+		return errors.Annotate(err, "getting raft configuration")
+	}
+	found := false
+	for _, server := range future.Configuration().Servers {
+		if server.ID == id {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return errors.NotFoundf("raft server %q", id)
+	}
+	w.config.Logger.Infof("removing server %q from raft configuration", id)
+	if err := w.config.Raft.RemoveServer(id, 0, removeServerTimeout).Error(); err != nil {
+		return errors.Annotatef(err, "removing raft server %q", id)
+	}
+	return nil
+}
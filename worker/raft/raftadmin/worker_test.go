@@ -0,0 +1,195 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package raftadmin_test
+
+import (
+	"time"
+
+	"github.com/hashicorp/raft"
+	"github.com/juju/loggo"
+	"github.com/juju/pubsub"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+	"gopkg.in/juju/worker.v1"
+	"gopkg.in/juju/worker.v1/workertest"
+
+	"github.com/juju/juju/pubsub/centralhub"
+	"github.com/juju/juju/pubsub/raftcluster"
+	coretesting "github.com/juju/juju/testing"
+	jujuraft "github.com/juju/juju/worker/raft"
+	"github.com/juju/juju/worker/raft/raftadmin"
+	"github.com/juju/juju/worker/raft/rafttest"
+)
+
+type workerFixture struct {
+	rafttest.RaftFixture
+	hub    *pubsub.StructuredHub
+	config raftadmin.Config
+}
+
+func (s *workerFixture) SetUpTest(c *gc.C) {
+	s.FSM = &jujuraft.SimpleFSM{}
+	s.RaftFixture.SetUpTest(c)
+	s.hub = centralhub.New(names.NewMachineTag("0"))
+	s.config = raftadmin.Config{
+		Raft:    s.Raft,
+		Hub:     s.hub,
+		Logger:  loggo.GetLogger("raftadmin_test"),
+		LocalID: "0",
+	}
+}
+
+type WorkerValidationSuite struct {
+	workerFixture
+}
+
+var _ = gc.Suite(&WorkerValidationSuite{})
+
+func (s *WorkerValidationSuite) TestValidateErrors(c *gc.C) {
+	type test struct {
+		f      func(*raftadmin.Config)
+		expect string
+	}
+	tests := []test{{
+		func(cfg *raftadmin.Config) { cfg.Raft = nil },
+		"nil Raft not valid",
+	}, {
+		func(cfg *raftadmin.Config) { cfg.Hub = nil },
+		"nil Hub not valid",
+	}, {
+		func(cfg *raftadmin.Config) { cfg.Logger = nil },
+		"nil Logger not valid",
+	}, {
+		func(cfg *raftadmin.Config) { cfg.LocalID = "" },
+		"empty LocalID not valid",
+	}}
+	for i, test := range tests {
+		c.Logf("test #%d (%s)", i, test.expect)
+		config := s.config
+		test.f(&config)
+		w, err := raftadmin.NewWorker(config)
+		if !c.Check(err, gc.NotNil) {
+			workertest.DirtyKill(c, w)
+			continue
+		}
+		c.Check(w, gc.IsNil)
+		c.Check(err, gc.ErrorMatches, test.expect)
+	}
+}
+
+type WorkerSuite struct {
+	workerFixture
+	worker worker.Worker
+}
+
+var _ = gc.Suite(&WorkerSuite{})
+
+func (s *WorkerSuite) SetUpTest(c *gc.C) {
+	s.workerFixture.SetUpTest(c)
+	worker, err := raftadmin.NewWorker(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(c *gc.C) {
+		workertest.DirtyKill(c, worker)
+	})
+	s.worker = worker
+}
+
+func (s *WorkerSuite) TestCleanKill(c *gc.C) {
+	workertest.CleanKill(c, s.worker)
+}
+
+func (s *WorkerSuite) TestStatus(c *gc.C) {
+	responses := make(chan raftcluster.Status, 1)
+	unsubscribe, err := s.hub.Subscribe(
+		"test.status",
+		func(_ string, status raftcluster.Status, err error) {
+			c.Check(err, jc.ErrorIsNil)
+			responses <- status
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	defer unsubscribe()
+
+	_, err = s.hub.Publish(raftcluster.StatusRequestTopic, raftcluster.StatusRequest{
+		ResponseTopic: "test.status",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	select {
+	case status := <-responses:
+		c.Assert(status.ServerID, gc.Equals, "0")
+		c.Assert(status.State, gc.Equals, "Leader")
+		c.Assert(status.Leader, gc.Equals, string(s.Transport.LocalAddr()))
+		c.Assert(status.Term, gc.Not(gc.Equals), uint64(0))
+		c.Assert(status.AppliedIndex, gc.Equals, s.Raft.AppliedIndex())
+		c.Assert(status.LastIndex, gc.Equals, s.Raft.LastIndex())
+		c.Assert(status.LastContact, gc.Equals, "")
+		c.Assert(status.Error, gc.Equals, "")
+		c.Assert(status.Servers, jc.DeepEquals, []raftcluster.Server{{
+			ID:       "0",
+			Address:  string(s.Transport.LocalAddr()),
+			Suffrage: "Voter",
+		}})
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for status")
+	}
+}
+
+func (s *WorkerSuite) removeServer(c *gc.C, id string) raftcluster.RemoveServerResponse {
+	responses := make(chan raftcluster.RemoveServerResponse, 1)
+	unsubscribe, err := s.hub.Subscribe(
+		"test.remove",
+		func(_ string, resp raftcluster.RemoveServerResponse, err error) {
+			c.Check(err, jc.ErrorIsNil)
+			responses <- resp
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	defer unsubscribe()
+
+	_, err = s.hub.Publish(raftcluster.RemoveServerRequestTopic, raftcluster.RemoveServerRequest{
+		ServerID:      id,
+		ResponseTopic: "test.remove",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	select {
+	case resp := <-responses:
+		return resp
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for remove server response")
+	}
+	panic("unreachable")
+}
+
+func (s *WorkerSuite) TestRemoveServer(c *gc.C) {
+	// A non-voter doesn't need to be reachable for the
+	// configuration change to be committed.
+	err := s.Raft.AddNonvoter("1", "testing.invalid:1234", 0, 0).Error()
+	c.Assert(err, jc.ErrorIsNil)
+
+	resp := s.removeServer(c, "1")
+	c.Assert(resp.Error, gc.Equals, "")
+	rafttest.CheckConfiguration(c, s.Raft, []raft.Server{{
+		ID:       "0",
+		Address:  s.Transport.LocalAddr(),
+		Suffrage: raft.Voter,
+	}})
+}
+
+func (s *WorkerSuite) TestRemoveServerUnknown(c *gc.C) {
+	resp := s.removeServer(c, "42")
+	c.Assert(resp.Error, gc.Equals, `raft server "42" not found`)
+}
+
+func (s *WorkerSuite) TestRemoveServerLeader(c *gc.C) {
+	resp := s.removeServer(c, "0")
+	c.Assert(resp.Error, gc.Equals, `cannot remove raft leader "0"`)
+	rafttest.CheckConfiguration(c, s.Raft, []raft.Server{{
+		ID:       "0",
+		Address:  s.Transport.LocalAddr(),
+		Suffrage: raft.Voter,
+	}})
+}
@@ -4,9 +4,6 @@
 package raftbackstop
 
 import (
-	"bytes"
-
-	"github.com/hashicorp/go-msgpack/codec"
 	"github.com/hashicorp/raft"
 	"github.com/juju/errors"
 	"github.com/juju/pubsub"
@@ -14,6 +11,7 @@ import (
 	"gopkg.in/juju/worker.v1/catacomb"
 
 	"github.com/juju/juju/pubsub/apiserver"
+	"github.com/juju/juju/worker/raft/raftutil"
 )
 
 // RaftNode captures the part of the *raft.Raft API needed by the
@@ -191,41 +189,13 @@ func (w *backstopWorker) recoverCluster(server *raft.Server) error {
 		Servers: []raft.Server{newServer},
 	}
 	w.config.Logger.Debugf("appending recovery configuration: %#v", configuration)
-	data, err := encodeConfiguration(configuration)
-
-	// Work out the last term and index.
-	lastIndex, err := w.config.LogStore.LastIndex()
-	if err != nil {
-		return errors.Annotate(err, "getting last log index")
-	}
-	var lastLog raft.Log
-	err = w.config.LogStore.GetLog(lastIndex, &lastLog)
-	if err != nil {
-		return errors.Annotate(err, "getting last log entry")
-	}
-
-	// Prepare log record to add.
-	record := raft.Log{
-		Index: lastIndex + 1,
-		Term:  lastLog.Term,
-		Type:  raft.LogConfiguration,
-		Data:  data,
-	}
-	if err := w.config.LogStore.StoreLog(&record); err != nil {
-		return errors.Annotate(err, "storing recovery configuration")
+	if err := raftutil.AppendConfiguration(w.config.LogStore, configuration); err != nil {
+		return errors.Trace(err)
 	}
 	w.configUpdated = true
 	return nil
 }
 
-func encodeConfiguration(config raft.Configuration) ([]byte, error) {
-	buf := bytes.NewBuffer(nil)
-	hd := codec.MsgpackHandle{}
-	enc := codec.NewEncoder(buf, &hd)
-	err := enc.Encode(config)
-	return buf.Bytes(), err
-}
-
 func (w *backstopWorker) getConfiguration() (map[raft.ServerID]*raft.Server, error) {
 	future := w.config.Raft.GetConfiguration()
 	err := w.waitFuture(future)
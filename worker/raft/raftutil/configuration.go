@@ -0,0 +1,97 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package raftutil
+
+import (
+	"bytes"
+
+	"github.com/hashicorp/go-msgpack/codec"
+	"github.com/hashicorp/raft"
+	"github.com/juju/errors"
+)
+
+// AppendConfiguration appends the configuration to the log store as a
+// new entry in the term of the last entry. A raft node that starts
+// with the log, or that is told about the entry, adopts the
+// configuration. This is used to recover a cluster that can no longer
+// elect a leader, so the configuration isn't agreed by the other
+// servers first.
+func AppendConfiguration(logStore raft.LogStore, configuration raft.Configuration) error {
+	data, err := encodeConfiguration(configuration)
+	if err != nil {
+		return errors.Annotate(err, "encoding configuration")
+	}
+
+	// Work out the last term and index.
+	lastIndex, err := logStore.LastIndex()
+	if err != nil {
+		return errors.Annotate(err, "getting last log index")
+	}
+	var lastLog raft.Log
+	err = logStore.GetLog(lastIndex, &lastLog)
+	if err != nil {
+		return errors.Annotate(err, "getting last log entry")
+	}
+
+	record := raft.Log{
+		Index: lastIndex + 1,
+		Term:  lastLog.Term,
+		Type:  raft.LogConfiguration,
+		Data:  data,
+	}
+	if err := logStore.StoreLog(&record); err != nil {
+		return errors.Annotate(err, "storing configuration")
+	}
+	return nil
+}
+
+// LatestConfiguration returns the most recent configuration in the log
+// store, or in the latest snapshot if there is none in the log. It is
+// for use when the raft node that owns the stores isn't running.
+func LatestConfiguration(logStore raft.LogStore, snapshots raft.SnapshotStore) (raft.Configuration, error) {
+	firstIndex, err := logStore.FirstIndex()
+	if err != nil {
+		return raft.Configuration{}, errors.Annotate(err, "getting first log index")
+	}
+	lastIndex, err := logStore.LastIndex()
+	if err != nil {
+		return raft.Configuration{}, errors.Annotate(err, "getting last log index")
+	}
+	for index := lastIndex; index >= firstIndex && index > 0; index-- {
+		var entry raft.Log
+		if err := logStore.GetLog(index, &entry); err != nil {
+			return raft.Configuration{}, errors.Annotatef(err, "getting log entry %d", index)
+		}
+		if entry.Type == raft.LogConfiguration {
+			config, err := decodeConfiguration(entry.Data)
+			return config, errors.Annotatef(err, "decoding configuration in log entry %d", index)
+		}
+	}
+
+	metas, err := snapshots.List()
+	if err != nil {
+		return raft.Configuration{}, errors.Annotate(err, "listing snapshots")
+	}
+	if len(metas) == 0 {
+		return raft.Configuration{}, errors.NotFoundf("raft configuration")
+	}
+	// Snapshots are listed newest first.
+	return metas[0].Configuration, nil
+}
+
+func encodeConfiguration(config raft.Configuration) ([]byte, error) {
+	buf := bytes.NewBuffer(nil)
+	hd := codec.MsgpackHandle{}
+	enc := codec.NewEncoder(buf, &hd)
+	err := enc.Encode(config)
+	return buf.Bytes(), err
+}
+
+func decodeConfiguration(data []byte) (raft.Configuration, error) {
+	var config raft.Configuration
+	hd := codec.MsgpackHandle{}
+	dec := codec.NewDecoder(bytes.NewReader(data), &hd)
+	err := dec.Decode(&config)
+	return config, err
+}
@@ -0,0 +1,66 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package raftutil_test
+
+import (
+	"github.com/hashicorp/raft"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/worker/raft/raftutil"
+)
+
+type configurationSuite struct {
+	testing.IsolationSuite
+	logStore *raft.InmemStore
+}
+
+var _ = gc.Suite(&configurationSuite{})
+
+func (s *configurationSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.logStore = raft.NewInmemStore()
+	err := s.logStore.StoreLog(&raft.Log{Index: 1, Term: 1, Type: raft.LogCommand, Data: []byte("command")})
+	c.Assert(err, jc.ErrorIsNil)
+	err = raftutil.AppendConfiguration(s.logStore, raft.Configuration{
+		Servers: []raft.Server{
+			{ID: "0", Address: "10.0.0.1:17070", Suffrage: raft.Voter},
+			{ID: "1", Address: "10.0.0.2:17070", Suffrage: raft.Voter},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.logStore.StoreLog(&raft.Log{Index: 3, Term: 3, Type: raft.LogCommand, Data: []byte("command")})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *configurationSuite) TestLatestConfiguration(c *gc.C) {
+	config, err := raftutil.LatestConfiguration(s.logStore, raft.NewDiscardSnapshotStore())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(config.Servers, gc.HasLen, 2)
+	c.Assert(config.Servers[1].ID, gc.Equals, raft.ServerID("1"))
+}
+
+func (s *configurationSuite) TestLatestConfigurationNotFound(c *gc.C) {
+	_, err := raftutil.LatestConfiguration(raft.NewInmemStore(), raft.NewDiscardSnapshotStore())
+	c.Assert(err, gc.ErrorMatches, "raft configuration not found")
+}
+
+func (s *configurationSuite) TestAppendConfiguration(c *gc.C) {
+	recovery := raft.Configuration{
+		Servers: []raft.Server{{ID: "0", Address: "10.0.0.1:17070", Suffrage: raft.Voter}},
+	}
+	err := raftutil.AppendConfiguration(s.logStore, recovery)
+	c.Assert(err, jc.ErrorIsNil)
+
+	var entry raft.Log
+	err = s.logStore.GetLog(4, &entry)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entry.Term, gc.Equals, uint64(3))
+	c.Assert(entry.Type, gc.Equals, raft.LogConfiguration)
+
+	config, err := raftutil.LatestConfiguration(s.logStore, raft.NewDiscardSnapshotStore())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(config, jc.DeepEquals, recovery)
+}
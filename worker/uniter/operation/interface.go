@@ -130,6 +130,12 @@ type CommandArgs struct {
 	RemoteUnitName string
 	// ForceRemoteUnit skips unit inference and existence validation.
 	ForceRemoteUnit bool
+	// ReplayHookId identifies a captured failed hook whose context
+	// the commands are run in.
+	ReplayHookId string
+	// DryRun records, rather than commits, the relation and leader
+	// settings changes made when replaying a hook.
+	DryRun bool
 }
 
 // CommandResponseFunc is for marshalling command responses back to the source
@@ -44,6 +44,8 @@ func (rc *runCommands) Prepare(state State) (*State, error) {
 		RelationId:      rc.args.RelationId,
 		RemoteUnitName:  rc.args.RemoteUnitName,
 		ForceRemoteUnit: rc.args.ForceRemoteUnit,
+		ReplayHookId:    rc.args.ReplayHookId,
+		DryRun:          rc.args.DryRun,
 	})
 	if err != nil {
 		return nil, err
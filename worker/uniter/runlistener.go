@@ -35,6 +35,12 @@ type RunCommandsArgs struct {
 	RemoteUnitName string
 	// ForceRemoteUnit skips relation membership and existence validation.
 	ForceRemoteUnit bool
+	// ReplayHookId identifies a captured failed hook whose context
+	// the commands are run in.
+	ReplayHookId string
+	// DryRun records, rather than commits, the relation and leader
+	// settings changes made when replaying a hook.
+	DryRun bool
 }
 
 // A CommandRunner is something that will actually execute the commands and
@@ -269,6 +275,8 @@ func (c *ChannelCommandRunner) RunCommands(args RunCommandsArgs) (results *exec.
 			RelationId:      args.RelationId,
 			RemoteUnitName:  args.RemoteUnitName,
 			ForceRemoteUnit: args.ForceRemoteUnit,
+			ReplayHookId:    args.ReplayHookId,
+			DryRun:          args.DryRun,
 		},
 		responseFunc,
 	)
@@ -15,6 +15,11 @@ type ActionData struct {
 	Failed         bool
 	ResultsMessage string
 	ResultsMap     map[string]interface{}
+
+	// ResultsSchema, if set, is the JSON-Schema that the charm has
+	// declared for the action's results. The results are coerced to
+	// and validated against it when the action completes.
+	ResultsSchema map[string]interface{}
 }
 
 // NewActionData builds a suitable ActionData struct with no nil members.
@@ -0,0 +1,99 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package context
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/gojsonschema"
+	"gopkg.in/juju/charm.v6"
+)
+
+// actionResultsSchemaKey is the key under an action's entry in
+// actions.yaml at which a charm may declare a JSON-Schema for the
+// results it records with action-set.
+const actionResultsSchemaKey = "results"
+
+// ActionResultsSchema returns the JSON-Schema that the charm has
+// declared for the results of the given action, or nil if it has not
+// declared one. The schema describes an object; if no type is given,
+// "object" is assumed.
+func ActionResultsSchema(spec charm.ActionSpec) map[string]interface{} {
+	declared, ok := spec.Params[actionResultsSchemaKey].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	schema := make(map[string]interface{}, len(declared)+1)
+	for k, v := range declared {
+		schema[k] = v
+	}
+	if _, ok := schema["type"]; !ok {
+		schema["type"] = "object"
+	}
+	return schema
+}
+
+// coerceActionResults returns a copy of results in which string values
+// are converted to the integer, number or boolean type declared for them
+// in the schema. Values that cannot be converted are left untouched, so
+// that validation reports them.
+func coerceActionResults(schema, results map[string]interface{}) map[string]interface{} {
+	properties, _ := schema["properties"].(map[string]interface{})
+	coerced := make(map[string]interface{}, len(results))
+	for key, value := range results {
+		propertySchema, _ := properties[key].(map[string]interface{})
+		coerced[key] = coerceActionResult(propertySchema, value)
+	}
+	return coerced
+}
+
+func coerceActionResult(schema map[string]interface{}, value interface{}) interface{} {
+	if schema == nil {
+		return value
+	}
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		return coerceActionResults(schema, typed)
+	case string:
+		switch schema["type"] {
+		case "integer":
+			if i, err := strconv.ParseInt(typed, 10, 64); err == nil {
+				return i
+			}
+		case "number":
+			if f, err := strconv.ParseFloat(typed, 64); err == nil {
+				return f
+			}
+		case "boolean":
+			if b, err := strconv.ParseBool(typed); err == nil {
+				return b
+			}
+		}
+	}
+	return value
+}
+
+// validateActionResults returns an error describing every way in which
+// the results fail to conform to the schema.
+func validateActionResults(schema, results map[string]interface{}) error {
+	result, err := gojsonschema.Validate(
+		gojsonschema.NewGoLoader(schema),
+		gojsonschema.NewGoLoader(results),
+	)
+	if err != nil {
+		return errors.Annotate(err, "invalid results schema")
+	}
+	if result.Valid() {
+		return nil
+	}
+	var problems []string
+	for _, resultErr := range result.Errors() {
+		problems = append(problems, resultErr.String())
+	}
+	sort.Strings(problems)
+	return errors.Errorf("results do not match schema: %s", strings.Join(problems, "; "))
+}
@@ -0,0 +1,103 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package context_test
+
+import (
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v6"
+
+	"github.com/juju/juju/worker/uniter/runner/context"
+)
+
+type ActionResultsSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&ActionResultsSuite{})
+
+var backupResultsSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"path": map[string]interface{}{"type": "string"},
+		"size": map[string]interface{}{"type": "integer"},
+		"stats": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"ratio":      map[string]interface{}{"type": "number"},
+				"compressed": map[string]interface{}{"type": "boolean"},
+			},
+		},
+	},
+	"required": []interface{}{"path"},
+}
+
+func (s *ActionResultsSuite) TestActionResultsSchema(c *gc.C) {
+	spec := charm.ActionSpec{Params: map[string]interface{}{
+		"results": map[string]interface{}{
+			"properties": map[string]interface{}{
+				"path": map[string]interface{}{"type": "string"},
+			},
+		},
+	}}
+	c.Assert(context.ActionResultsSchema(spec), jc.DeepEquals, map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"path": map[string]interface{}{"type": "string"},
+		},
+	})
+}
+
+func (s *ActionResultsSuite) TestActionResultsSchemaNotDeclared(c *gc.C) {
+	spec := charm.ActionSpec{Params: map[string]interface{}{
+		"type": "object",
+	}}
+	c.Assert(context.ActionResultsSchema(spec), gc.IsNil)
+}
+
+func (s *ActionResultsSuite) TestCoerceActionResults(c *gc.C) {
+	results := context.CoerceActionResults(backupResultsSchema, map[string]interface{}{
+		"path": "/var/backups/1.tar",
+		"size": "1024",
+		"stats": map[string]interface{}{
+			"ratio":      "0.5",
+			"compressed": "true",
+		},
+		"extra": "10",
+	})
+	c.Assert(results, jc.DeepEquals, map[string]interface{}{
+		"path": "/var/backups/1.tar",
+		"size": int64(1024),
+		"stats": map[string]interface{}{
+			"ratio":      0.5,
+			"compressed": true,
+		},
+		"extra": "10",
+	})
+}
+
+func (s *ActionResultsSuite) TestCoerceActionResultsLeavesInvalidValues(c *gc.C) {
+	results := context.CoerceActionResults(backupResultsSchema, map[string]interface{}{
+		"size": "big",
+	})
+	c.Assert(results, jc.DeepEquals, map[string]interface{}{
+		"size": "big",
+	})
+}
+
+func (s *ActionResultsSuite) TestValidateActionResults(c *gc.C) {
+	err := context.ValidateActionResults(backupResultsSchema, map[string]interface{}{
+		"path": "/var/backups/1.tar",
+		"size": int64(1024),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ActionResultsSuite) TestValidateActionResultsMismatch(c *gc.C) {
+	err := context.ValidateActionResults(backupResultsSchema, map[string]interface{}{
+		"size": "big",
+	})
+	c.Assert(err, gc.ErrorMatches, `results do not match schema: .*path.*required.*; size: Invalid type.*`)
+}
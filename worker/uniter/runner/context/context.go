@@ -231,6 +231,30 @@ type HookContext struct {
 
 	// The cloud specification
 	cloudSpec *params.CloudSpec
+
+	// unitState holds the cached key/value state persisted for the
+	// unit by the charm. It is read lazily, and written back to the
+	// controller on a successful flush if unitStateDirty is set.
+	unitState      map[string]string
+	unitStateDirty bool
+
+	// paths provides the filesystem paths used when running hooks.
+	paths Paths
+
+	// hookName is the name of the hook the context was created to run,
+	// if any.
+	hookName string
+
+	// captureFailedHooks is true if the context of a failed hook should
+	// be captured for later replay.
+	captureFailedHooks bool
+
+	// replay holds the captured context of the hook being replayed, if
+	// any. If dryRun is set, the changes the replayed hook makes are
+	// recorded in dryRunRecord rather than committed.
+	replay       *HookCapture
+	dryRun       bool
+	dryRunRecord DryRunRecord
 }
 
 // Component implements hooks.Context.
@@ -369,6 +393,10 @@ func (ctx *HookContext) ApplicationStatus() (jujuc.ApplicationStatusInfo, error)
 func (ctx *HookContext) SetUnitStatus(unitStatus jujuc.StatusInfo) error {
 	ctx.hasRunStatusSet = true
 	logger.Tracef("[WORKLOAD-STATUS] %s: %s", unitStatus.Status, unitStatus.Info)
+	if ctx.dryRun {
+		ctx.dryRunRecord.UnitStatus = &DryRunStatus{unitStatus.Status, unitStatus.Info}
+		return nil
+	}
 	return ctx.unit.SetUnitStatus(
 		status.Status(unitStatus.Status),
 		unitStatus.Info,
@@ -387,6 +415,10 @@ func (ctx *HookContext) SetApplicationStatus(applicationStatus jujuc.StatusInfo)
 	if !isLeader {
 		return ErrIsNotLeader
 	}
+	if ctx.dryRun {
+		ctx.dryRunRecord.ApplicationStatus = &DryRunStatus{applicationStatus.Status, applicationStatus.Info}
+		return nil
+	}
 
 	application, err := ctx.unit.Application()
 	if err != nil {
@@ -499,6 +531,22 @@ func (ctx *HookContext) ConfigSettings() (charm.Settings, error) {
 	return result, nil
 }
 
+// WriteLeaderSettings is part of the hooks.Context interface. When
+// replaying a hook in dry-run mode, the settings are recorded rather
+// than written.
+func (ctx *HookContext) WriteLeaderSettings(settings map[string]string) error {
+	if !ctx.dryRun {
+		return ctx.LeadershipContext.WriteLeaderSettings(settings)
+	}
+	if ctx.dryRunRecord.LeaderSettings == nil {
+		ctx.dryRunRecord.LeaderSettings = make(map[string]string)
+	}
+	for k, v := range settings {
+		ctx.dryRunRecord.LeaderSettings[k] = v
+	}
+	return nil
+}
+
 func (ctx *HookContext) GoalState() (*application.GoalState, error) {
 	var err error
 	ctx.goalState, err = ctx.state.GoalState()
@@ -520,6 +568,10 @@ func (ctx *HookContext) SetPodSpec(specYaml string) error {
 		logger.Warningf("%v is not the leader but is setting application pod spec", entityName)
 		//return ErrIsNotLeader
 	}
+	if ctx.dryRun {
+		ctx.dryRunRecord.PodSpec = specYaml
+		return nil
+	}
 	entityName = ctx.unit.ApplicationName()
 	return ctx.state.SetPodSpec(entityName, specYaml)
 }
@@ -678,6 +730,9 @@ func (context *HookContext) HookVars(paths Paths) ([]string, error) {
 			"JUJU_ACTION_TAG="+context.actionData.Tag.String(),
 		)
 	}
+	if context.replay != nil {
+		vars = context.replayHookVars(vars)
+	}
 	return append(vars, OSDependentEnvVars(paths)...), nil
 }
 
@@ -737,6 +792,22 @@ func (ctx *HookContext) Flush(process string, ctxErr error) (err error) {
 		defer ctx.handleReboot(&err)
 	}
 
+	if ctxErr != nil && ctx.captureFailedHooks && ctx.hookName != "" {
+		if e := ctx.captureHook(process, ctxErr); e != nil {
+			logger.Warningf("cannot capture context of failed hook %q: %v", process, e)
+		}
+	}
+
+	if ctx.dryRun {
+		if e := ctx.writeDryRunRecord(); e != nil {
+			logger.Errorf("%v", e)
+			if ctxErr == nil {
+				ctxErr = e
+			}
+		}
+		writeChanges = false
+	}
+
 	for id, rctx := range ctx.relations {
 		if writeChanges {
 			if e := rctx.WriteSettings(); e != nil {
@@ -793,6 +864,16 @@ func (ctx *HookContext) Flush(process string, ctxErr error) (err error) {
 		}
 	}
 
+	if ctx.unitStateDirty && writeChanges {
+		if err := ctx.unit.SetState(ctx.unitState); err != nil {
+			err = errors.Annotatef(err, "cannot write unit state")
+			logger.Errorf("%v", err)
+			if ctxErr == nil {
+				ctxErr = err
+			}
+		}
+	}
+
 	// TODO (tasdomas) 2014 09 03: context finalization needs to modified to apply all
 	//                             changes in one api call to minimize the risk
 	//                             of partial failures.
@@ -827,6 +908,17 @@ func (ctx *HookContext) finalizeAction(err, unhandledErr error) error {
 		status = params.ActionFailed
 	}
 
+	// If the charm declared the shape of its results, record them with
+	// the declared types, and fail the action if they don't conform.
+	if schema := ctx.actionData.ResultsSchema; schema != nil && status == params.ActionCompleted {
+		results = coerceActionResults(schema, results)
+		if validateErr := validateActionResults(schema, results); validateErr != nil {
+			logger.Warningf("action %q on unit %q: %v", ctx.actionData.Name, ctx.unitName, validateErr)
+			message = validateErr.Error()
+			status = params.ActionFailed
+		}
+	}
+
 	callErr := ctx.state.ActionFinish(tag, status, results, message)
 	if callErr != nil {
 		unhandledErr = errors.Wrap(unhandledErr, callErr)
@@ -867,6 +959,62 @@ func (ctx *HookContext) killCharmHook() error {
 	}
 }
 
+// UnitState returns the key/value pairs persisted for the current
+// unit by its charm, including any changes made during this hook.
+func (ctx *HookContext) UnitState() (map[string]string, error) {
+	if err := ctx.ensureUnitState(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make(map[string]string, len(ctx.unitState))
+	for k, v := range ctx.unitState {
+		result[k] = v
+	}
+	return result, nil
+}
+
+// SetUnitStateValue records a new value for the given key in the
+// current unit's state, to be written when the context is flushed.
+func (ctx *HookContext) SetUnitStateValue(key, value string) error {
+	if err := ctx.ensureUnitState(); err != nil {
+		return errors.Trace(err)
+	}
+	if current, ok := ctx.unitState[key]; ok && current == value {
+		return nil
+	}
+	ctx.unitState[key] = value
+	ctx.unitStateDirty = true
+	return nil
+}
+
+// DeleteUnitStateValue removes the given key from the current unit's
+// state, to be written when the context is flushed.
+func (ctx *HookContext) DeleteUnitStateValue(key string) error {
+	if err := ctx.ensureUnitState(); err != nil {
+		return errors.Trace(err)
+	}
+	if _, ok := ctx.unitState[key]; !ok {
+		return nil
+	}
+	delete(ctx.unitState, key)
+	ctx.unitStateDirty = true
+	return nil
+}
+
+func (ctx *HookContext) ensureUnitState() error {
+	if ctx.unitState != nil {
+		return nil
+	}
+	unitState, err := ctx.unit.State()
+	if err != nil {
+		return errors.Annotate(err, "cannot read unit state")
+	}
+	if unitState == nil {
+		unitState = make(map[string]string)
+	}
+	ctx.unitState = unitState
+	return nil
+}
+
 // UnitWorkloadVersion returns the version of the workload reported by
 // the current unit.
 func (ctx *HookContext) UnitWorkloadVersion() (string, error) {
@@ -891,6 +1039,10 @@ func (ctx *HookContext) UnitWorkloadVersion() (string, error) {
 // SetUnitWorkloadVersion sets the current unit's workload version to
 // the specified value.
 func (ctx *HookContext) SetUnitWorkloadVersion(version string) error {
+	if ctx.dryRun {
+		ctx.dryRunRecord.WorkloadVersion = version
+		return nil
+	}
 	var result params.ErrorResults
 	args := params.EntityWorkloadVersions{
 		Entities: []params.EntityWorkloadVersion{
@@ -28,6 +28,12 @@ type CommandInfo struct {
 	RemoteUnitName string
 	// ForceRemoteUnit skips unit inference and existence validation.
 	ForceRemoteUnit bool
+	// ReplayHookId identifies a captured failed hook whose context the
+	// commands should be run in, in place of the current one.
+	ReplayHookId string
+	// DryRun causes changes made by the replayed hook to be recorded
+	// rather than committed.
+	DryRun bool
 }
 
 // ContextFactory represents a long-lived object that can create execution contexts
@@ -177,6 +183,7 @@ func (f *contextFactory) coreContext() (*HookContext, error) {
 		storage:            f.storage,
 		clock:              f.clock,
 		componentDir:       f.paths.ComponentDir,
+		paths:              f.paths,
 		componentFuncs:     registeredComponentFuncs,
 		availabilityzone:   f.zone,
 		principal:          f.principal,
@@ -234,6 +241,7 @@ func (f *contextFactory) HookContext(hookInfo hook.Info) (*HookContext, error) {
 		}
 		hookName = fmt.Sprintf("%s-%s", storageName, hookName)
 	}
+	ctx.hookName = hookName
 	ctx.id = f.newId(hookName)
 	return ctx, nil
 }
@@ -244,6 +252,15 @@ func (f *contextFactory) CommandContext(commandInfo CommandInfo) (*HookContext,
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	if commandInfo.ReplayHookId != "" {
+		capture, err := ReadHookCapture(f.paths.ComponentDir(HookCapturesDir), commandInfo.ReplayHookId)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ctx.applyHookCapture(capture, commandInfo.DryRun)
+		ctx.id = f.newId("replay-" + capture.Hook)
+		return ctx, nil
+	}
 	relationId, remoteUnitName, err := inferRemoteUnit(ctx.relations, commandInfo)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -305,6 +322,7 @@ func (f *contextFactory) updateContext(ctx *HookContext) (err error) {
 	}
 	ctx.legacyProxySettings = modelConfig.LegacyProxySettings()
 	ctx.jujuProxySettings = modelConfig.JujuProxySettings()
+	ctx.captureFailedHooks = modelConfig.CaptureFailedHooks()
 
 	statusCode, statusInfo, err := f.unit.MeterStatus()
 	if err != nil {
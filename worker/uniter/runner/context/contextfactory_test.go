@@ -4,10 +4,13 @@
 package context_test
 
 import (
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/juju/clock/testclock"
+	"github.com/juju/collections/set"
 	"github.com/juju/errors"
 	"github.com/juju/juju/environs"
 	"github.com/juju/testing"
@@ -21,6 +24,8 @@ import (
 	"github.com/juju/juju/api"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/core/leadership"
+	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/environs/config"
 	environscontext "github.com/juju/juju/environs/context"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/storage"
@@ -29,6 +34,7 @@ import (
 	"github.com/juju/juju/testing/factory"
 	"github.com/juju/juju/worker/uniter/hook"
 	"github.com/juju/juju/worker/uniter/runner/context"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
 	runnertesting "github.com/juju/juju/worker/uniter/runner/testing"
 )
 
@@ -53,7 +59,7 @@ func (s *ContextFactorySuite) SetUpTest(c *gc.C) {
 		GetRelationInfos: s.getRelationInfos,
 		Storage:          s.storage,
 		Paths:            s.paths,
-		Clock:            testclock.NewClock(time.Time{}),
+		Clock:            s.clock,
 	})
 	c.Assert(err, jc.ErrorIsNil)
 	s.factory = contextFactory
@@ -312,6 +318,55 @@ func (s *ContextFactorySuite) TestActionContext(c *gc.C) {
 	s.AssertNotStorageContext(c, ctx)
 }
 
+func (s *ContextFactorySuite) runActionWithResultsSchema(c *gc.C, size string) state.Action {
+	s.SetCharm(c, "dummy")
+	action, err := s.Model(c).EnqueueAction(s.unit.Tag(), "snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	actionData := &context.ActionData{
+		Name:       action.Name(),
+		Tag:        names.NewActionTag(action.Id()),
+		Params:     action.Parameters(),
+		ResultsMap: map[string]interface{}{},
+		ResultsSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"size": map[string]interface{}{"type": "integer"},
+			},
+			"required": []interface{}{"size"},
+		},
+	}
+	ctx, err := s.factory.ActionContext(actionData)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ctx.UpdateActionResults([]string{"size"}, size)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Results that don't match the schema fail the action, but
+	// are not an error for the uniter.
+	err = ctx.Flush("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	action, err = s.Model(c).Action(action.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	return action
+}
+
+func (s *ContextFactorySuite) TestActionContextResultsMatchSchema(c *gc.C) {
+	action := s.runActionWithResultsSchema(c, "42")
+	c.Assert(action.Status(), gc.Equals, state.ActionCompleted)
+	// The coerced integer is sent to the controller as a JSON number,
+	// so it is stored and read back as a float64.
+	results, _ := action.Results()
+	c.Assert(results, jc.DeepEquals, map[string]interface{}{"size": float64(42)})
+}
+
+func (s *ContextFactorySuite) TestActionContextResultsDoNotMatchSchema(c *gc.C) {
+	action := s.runActionWithResultsSchema(c, "large")
+	c.Assert(action.Status(), gc.Equals, state.ActionFailed)
+	_, message := action.Results()
+	c.Assert(message, gc.Matches, "results do not match schema: size: Invalid type.*")
+}
+
 func (s *ContextFactorySuite) TestCommandContext(c *gc.C) {
 	ctx, err := s.factory.CommandContext(context.CommandInfo{RelationId: -1})
 	c.Assert(err, jc.ErrorIsNil)
@@ -353,6 +408,137 @@ func (s *ContextFactorySuite) TestNewCommandContextForceRemoteUnitMissing(c *gc.
 	s.AssertNotStorageContext(c, ctx)
 }
 
+func (s *ContextFactorySuite) failHook(c *gc.C, kind hooks.Kind) {
+	err := s.Model(c).UpdateModelConfig(map[string]interface{}{
+		config.CaptureFailedHooks: true,
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	ctx, err := s.factory.HookContext(hook.Info{Kind: kind})
+	c.Assert(err, jc.ErrorIsNil)
+	err = ctx.Flush(string(kind), errors.New("exit status 1"))
+	c.Assert(err, gc.ErrorMatches, "exit status 1")
+}
+
+func (s *ContextFactorySuite) TestHookContextCapturesFailedHook(c *gc.C) {
+	s.failHook(c, hooks.ConfigChanged)
+
+	dir := s.paths.ComponentDir(context.HookCapturesDir)
+	info, err := os.Stat(filepath.Join(dir, "00010101-000000-config-changed.yaml"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.Mode().Perm(), gc.Equals, os.FileMode(0600))
+
+	capture, err := context.ReadHookCapture(dir, "00010101-000000-config-changed")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(capture.Unit, gc.Equals, "u/0")
+	c.Check(capture.Hook, gc.Equals, "config-changed")
+	c.Check(capture.Error, gc.Equals, "exit status 1")
+	c.Check(capture.RelationId, gc.Equals, -1)
+	c.Check(capture.Env["JUJU_UNIT_NAME"], gc.Equals, "u/0")
+	c.Check(capture.Env["JUJU_CONTEXT_ID"], gc.Equals, "")
+	c.Check(capture.Config["blog-title"], gc.Equals, "My Title")
+}
+
+func (s *ContextFactorySuite) TestHookContextRemovesOldCaptures(c *gc.C) {
+	for i := 0; i < 12; i++ {
+		s.failHook(c, hooks.Install)
+		s.clock.Advance(time.Second)
+	}
+
+	infos, err := ioutil.ReadDir(s.paths.ComponentDir(context.HookCapturesDir))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(infos, gc.HasLen, 10)
+	c.Check(infos[0].Name(), gc.Equals, "00010101-000002-install.yaml")
+	c.Check(infos[9].Name(), gc.Equals, "00010101-000011-install.yaml")
+}
+
+func (s *ContextFactorySuite) TestCommandContextReplaysHookCapture(c *gc.C) {
+	dir := s.paths.ComponentDir(context.HookCapturesDir)
+	err := os.MkdirAll(dir, 0700)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ioutil.WriteFile(filepath.Join(dir, "20190723-101500-db0-relation-changed.yaml"), []byte(`
+id: 20190723-101500-db0-relation-changed
+unit: u/0
+hook: db0-relation-changed
+error: exit status 1
+relation-id: 0
+remote-unit: r/0
+env:
+  JUJU_RELATION: db0
+config:
+  blog-title: Captured Title
+relations:
+  0:
+    r/0:
+      host: 10.0.0.1
+`[1:]), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+
+	ctx, err := s.factory.CommandContext(context.CommandInfo{
+		RelationId:   -1,
+		ReplayHookId: "20190723-101500-db0-relation-changed",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.AssertRelationContext(c, ctx, 0, "r/0")
+
+	settings, err := ctx.ConfigSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(settings["blog-title"], gc.Equals, "Captured Title")
+
+	rctx, err := ctx.Relation(0)
+	c.Assert(err, jc.ErrorIsNil)
+	remote, err := rctx.ReadSettings("r/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(remote, jc.DeepEquals, params.Settings{"host": "10.0.0.1"})
+
+	vars, err := ctx.HookVars(s.paths)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(set.NewStrings(vars...).Contains("JUJU_RELATION=db0"), jc.IsTrue)
+}
+
+func (s *ContextFactorySuite) TestCommandContextReplayInvalidId(c *gc.C) {
+	_, err := s.factory.CommandContext(context.CommandInfo{
+		RelationId:   -1,
+		ReplayHookId: "../../agent",
+	})
+	c.Assert(err, gc.ErrorMatches, `.*hook capture id "../../agent" not valid`)
+}
+
+func (s *ContextFactorySuite) TestCommandContextDryRunReplay(c *gc.C) {
+	s.failHook(c, hooks.Install)
+	before, err := s.unit.Status()
+	c.Assert(err, jc.ErrorIsNil)
+
+	ctx, err := s.factory.CommandContext(context.CommandInfo{
+		RelationId:   -1,
+		ReplayHookId: "00010101-000000-install",
+		DryRun:       true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = ctx.SetUnitStatus(jujuc.StatusInfo{Status: "maintenance", Info: "installing"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = ctx.SetUnitWorkloadVersion("1.2.3")
+	c.Assert(err, jc.ErrorIsNil)
+	err = ctx.Flush("run commands", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Nothing the hook set has been committed.
+	after, err := s.unit.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(after.Status, gc.Equals, before.Status)
+	c.Check(after.Status, gc.Not(gc.Equals), status.Maintenance)
+	version, err := s.unit.WorkloadVersion()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(version, gc.Equals, "")
+
+	record, err := context.ReadDryRunRecord(
+		s.paths.ComponentDir(context.HookCapturesDir), "00010101-000000-install")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(record, jc.DeepEquals, &context.DryRunRecord{
+		UnitStatus:      &context.DryRunStatus{Status: "maintenance", Message: "installing"},
+		WorkloadVersion: "1.2.3",
+	})
+}
+
 func (s *ContextFactorySuite) TestNewCommandContextInferRemoteUnit(c *gc.C) {
 	s.membership[0] = []string{"foo/2"}
 	ctx, err := s.factory.CommandContext(context.CommandInfo{RelationId: 0})
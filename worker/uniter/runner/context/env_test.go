@@ -187,3 +187,43 @@ func (s *EnvSuite) TestEnvUbuntu(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	s.assertVars(c, actualVars, contextVars, pathsVars, ubuntuVars, relationVars)
 }
+
+func (s *EnvSuite) TestEnvReplayHook(c *gc.C) {
+	s.PatchValue(&jujuos.HostOS, func() jujuos.OSType { return jujuos.Ubuntu })
+	s.PatchValue(&jujuversion.Current, version.MustParse("1.2.3"))
+	os.Setenv("PATH", "foo:bar")
+	ubuntuVars := []string{
+		"PATH=path-to-tools:foo:bar",
+		"APT_LISTCHANGES_FRONTEND=none",
+		"DEBIAN_FRONTEND=noninteractive",
+	}
+
+	ctx, contextVars := s.getContext(false)
+	paths, pathsVars := s.getPaths()
+	context.SetEnvironmentHookContextRelation(ctx, 22, "an-endpoint", "")
+	context.SetReplayHookCapture(ctx, &context.HookCapture{
+		Env: map[string]string{
+			"JUJU_CONTEXT_ID":   "captured-context-id",
+			"JUJU_AGENT_SOCKET": "captured-socket",
+			"JUJU_REMOTE_UNIT":  "that-unit/456",
+			"JUJU_SLA":          "unsupported",
+			"JUJU_HOOK_EXTRA":   "extra",
+		},
+	})
+	actualVars, err := ctx.HookVars(paths)
+	c.Assert(err, jc.ErrorIsNil)
+
+	var replayedVars []string
+	for _, kv := range contextVars {
+		if kv != "JUJU_SLA=essential" {
+			replayedVars = append(replayedVars, kv)
+		}
+	}
+	s.assertVars(c, actualVars, replayedVars, pathsVars, ubuntuVars, []string{
+		"JUJU_SLA=unsupported",
+		"JUJU_RELATION=an-endpoint",
+		"JUJU_RELATION_ID=an-endpoint:22",
+		"JUJU_REMOTE_UNIT=that-unit/456",
+		"JUJU_HOOK_EXTRA=extra",
+	})
+}
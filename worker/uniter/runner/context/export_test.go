@@ -19,6 +19,9 @@ var (
 	ValidatePortRange = validatePortRange
 	TryOpenPorts      = tryOpenPorts
 	TryClosePorts     = tryClosePorts
+
+	CoerceActionResults   = coerceActionResults
+	ValidateActionResults = validateActionResults
 )
 
 func NewHookContext(
@@ -192,3 +195,9 @@ func CachedSettings(cf0 ContextFactory, relId int, unitName string) (params.Sett
 func (ctx *HookContext) SLALevel() string {
 	return ctx.slaLevel
 }
+
+// SetReplayHookCapture sets the captured hook context that the
+// context replays.
+func SetReplayHookCapture(ctx *HookContext, capture *HookCapture) {
+	ctx.replay = capture
+}
@@ -0,0 +1,315 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package context
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/yaml.v2"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+// HookCapturesDir is the name of the directory, within the unit agent's
+// base directory, in which the contexts of failed hooks are captured.
+const HookCapturesDir = "hook-captures"
+
+// maxHookCaptures is the number of hook captures kept for a unit. When
+// a hook fails, the oldest captures beyond this are removed.
+const maxHookCaptures = 10
+
+// hookCaptureIdRegexp matches the ids given to hook captures: the time
+// the hook failed followed by the name of the hook.
+var hookCaptureIdRegexp = regexp.MustCompile(`^[0-9]{8}-[0-9]{6}-[a-z0-9-]+$`)
+
+// IsValidHookCaptureId reports whether id is a valid hook capture id.
+func IsValidHookCaptureId(id string) bool {
+	return hookCaptureIdRegexp.MatchString(id)
+}
+
+// replayExcludedEnv holds the environment variables that are specific to
+// a single hook execution, and so are never replayed from a capture.
+var replayExcludedEnv = map[string]bool{
+	"JUJU_CONTEXT_ID":   true,
+	"JUJU_AGENT_SOCKET": true,
+}
+
+// HookCapture records the context in which a hook failed, so that the
+// hook can later be replayed against the same inputs.
+type HookCapture struct {
+	// Id uniquely identifies the capture for the unit.
+	Id string `yaml:"id"`
+
+	// Unit is the name of the unit that ran the hook.
+	Unit string `yaml:"unit"`
+
+	// Hook is the name of the hook that failed, as found in the
+	// charm's hooks directory.
+	Hook string `yaml:"hook"`
+
+	// Captured is the time at which the hook failed.
+	Captured time.Time `yaml:"captured"`
+
+	// Error is the error the hook failed with.
+	Error string `yaml:"error,omitempty"`
+
+	// RelationId is the id of the relation the hook ran for, or -1
+	// if it was not a relation hook.
+	RelationId int `yaml:"relation-id"`
+
+	// RemoteUnit is the remote unit the relation hook ran for.
+	RemoteUnit string `yaml:"remote-unit,omitempty"`
+
+	// Env holds the Juju environment variables the hook was run with.
+	Env map[string]string `yaml:"env"`
+
+	// Config holds the charm config the hook saw.
+	Config map[string]interface{} `yaml:"config,omitempty"`
+
+	// Relations holds the settings of the remote units of each of
+	// the unit's relations, keyed on relation id and unit name.
+	Relations map[int]map[string]map[string]string `yaml:"relations,omitempty"`
+}
+
+// DryRunRecord holds the changes a replayed hook would have committed
+// had it not been run in dry-run mode.
+type DryRunRecord struct {
+	// RelationSettings holds the local unit's settings for each
+	// relation the hook touched, keyed on relation key, e.g. "db:2".
+	RelationSettings map[string]map[string]string `yaml:"relation-settings,omitempty"`
+
+	// LeaderSettings holds the leader settings the hook attempted
+	// to set, merged across calls.
+	LeaderSettings map[string]string `yaml:"leader-settings,omitempty"`
+
+	// UnitStatus and ApplicationStatus hold the last statuses the
+	// hook attempted to set.
+	UnitStatus        *DryRunStatus `yaml:"unit-status,omitempty"`
+	ApplicationStatus *DryRunStatus `yaml:"application-status,omitempty"`
+
+	// WorkloadVersion holds the last workload version the hook
+	// attempted to set.
+	WorkloadVersion string `yaml:"workload-version,omitempty"`
+
+	// PodSpec holds the last pod spec the hook attempted to set.
+	PodSpec string `yaml:"pod-spec,omitempty"`
+}
+
+// DryRunStatus holds a status set by a hook replayed in dry-run mode.
+type DryRunStatus struct {
+	Status  string `yaml:"status"`
+	Message string `yaml:"message,omitempty"`
+}
+
+func hookCapturePath(dir, id string) string {
+	return filepath.Join(dir, id+".yaml")
+}
+
+func dryRunRecordPath(dir, id string) string {
+	return filepath.Join(dir, id+".dry-run.yaml")
+}
+
+// ReadHookCapture reads the hook capture with the given id from dir.
+func ReadHookCapture(dir, id string) (*HookCapture, error) {
+	if !IsValidHookCaptureId(id) {
+		return nil, errors.NotValidf("hook capture id %q", id)
+	}
+	var capture HookCapture
+	if err := readYAML(hookCapturePath(dir, id), &capture); os.IsNotExist(errors.Cause(err)) {
+		return nil, errors.NotFoundf("hook capture %q", id)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "reading hook capture %q", id)
+	}
+	return &capture, nil
+}
+
+// ReadDryRunRecord reads the record written by the most recent dry-run
+// replay of the hook capture with the given id from dir.
+func ReadDryRunRecord(dir, id string) (*DryRunRecord, error) {
+	if !IsValidHookCaptureId(id) {
+		return nil, errors.NotValidf("hook capture id %q", id)
+	}
+	var record DryRunRecord
+	if err := readYAML(dryRunRecordPath(dir, id), &record); os.IsNotExist(errors.Cause(err)) {
+		return nil, errors.NotFoundf("dry-run record for %q", id)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "reading dry-run record for %q", id)
+	}
+	return &record, nil
+}
+
+func readYAML(path string, out interface{}) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(yaml.Unmarshal(data, out))
+}
+
+func writeYAML(path string, in interface{}) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return errors.Trace(err)
+	}
+	data, err := yaml.Marshal(in)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(ioutil.WriteFile(path, data, 0600))
+}
+
+// captureHook writes the context of the failed hook to the unit's hook
+// captures directory, from where it may be replayed.
+func (ctx *HookContext) captureHook(hookName string, failure error) error {
+	env, err := ctx.HookVars(ctx.paths)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	now := ctx.clock.Now().UTC()
+	capture := HookCapture{
+		Id:         now.Format("20060102-150405") + "-" + hookName,
+		Unit:       ctx.unitName,
+		Hook:       hookName,
+		Captured:   now,
+		Error:      failure.Error(),
+		RelationId: ctx.relationId,
+		RemoteUnit: ctx.remoteUnitName,
+		Env:        make(map[string]string),
+	}
+	for _, kv := range env {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 || !strings.HasPrefix(parts[0], "JUJU_") || replayExcludedEnv[parts[0]] {
+			continue
+		}
+		capture.Env[parts[0]] = parts[1]
+	}
+	config, err := ctx.ConfigSettings()
+	if err != nil {
+		return errors.Annotate(err, "reading config")
+	}
+	capture.Config = config
+	for id, rctx := range ctx.relations {
+		units := make(map[string]map[string]string)
+		for _, unitName := range rctx.UnitNames() {
+			settings, err := rctx.ReadSettings(unitName)
+			if err != nil {
+				return errors.Annotatef(err, "reading settings of %q in relation %d", unitName, id)
+			}
+			units[unitName] = settings
+		}
+		if capture.Relations == nil {
+			capture.Relations = make(map[int]map[string]map[string]string)
+		}
+		capture.Relations[id] = units
+	}
+	dir := ctx.componentDir(HookCapturesDir)
+	if err := writeYAML(hookCapturePath(dir, capture.Id), capture); err != nil {
+		return errors.Annotate(err, "writing hook capture")
+	}
+	logger.Infof("captured context of failed hook %q as %q", hookName, capture.Id)
+	return errors.Annotate(pruneHookCaptures(dir), "removing old hook captures")
+}
+
+// pruneHookCaptures removes all but the newest maxHookCaptures hook
+// captures from dir, along with any dry-run records for them.
+func pruneHookCaptures(dir string) error {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var ids []string
+	for _, info := range infos {
+		id := strings.TrimSuffix(info.Name(), ".yaml")
+		if info.IsDir() || id == info.Name() || !IsValidHookCaptureId(id) {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) <= maxHookCaptures {
+		return nil
+	}
+	// Ids start with the time of capture, so sort oldest first.
+	sort.Strings(ids)
+	for _, id := range ids[:len(ids)-maxHookCaptures] {
+		for _, path := range []string{hookCapturePath(dir, id), dryRunRecordPath(dir, id)} {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return errors.Trace(err)
+			}
+		}
+	}
+	return nil
+}
+
+// applyHookCapture sets up the context to replay the supplied capture,
+// using its config and remote relation settings in place of the
+// current ones.
+func (ctx *HookContext) applyHookCapture(capture *HookCapture, dryRun bool) {
+	ctx.replay = capture
+	ctx.dryRun = dryRun
+	ctx.relationId = capture.RelationId
+	ctx.remoteUnitName = capture.RemoteUnit
+	ctx.configSettings = capture.Config
+	for id, units := range capture.Relations {
+		rctx, ok := ctx.relations[id]
+		if !ok {
+			// The relation has since been removed; the hook will
+			// see it as such.
+			continue
+		}
+		captured := units
+		readSettings := func(unitName string) (params.Settings, error) {
+			if settings, ok := captured[unitName]; ok {
+				return settings, nil
+			}
+			return rctx.ru.ReadSettings(unitName)
+		}
+		memberNames := make([]string, 0, len(captured))
+		for unitName := range captured {
+			memberNames = append(memberNames, unitName)
+		}
+		ctx.relations[id] = NewContextRelation(rctx.ru, NewRelationCache(readSettings, memberNames))
+	}
+}
+
+// replayHookVars overlays the environment captured with the hook being
+// replayed onto the supplied hook environment.
+func (ctx *HookContext) replayHookVars(vars []string) []string {
+	seen := make(map[string]bool)
+	for i, kv := range vars {
+		key := strings.SplitN(kv, "=", 2)[0]
+		if value, ok := ctx.replay.Env[key]; ok && !replayExcludedEnv[key] {
+			vars[i] = key + "=" + value
+		}
+		seen[key] = true
+	}
+	for key, value := range ctx.replay.Env {
+		if !seen[key] && !replayExcludedEnv[key] {
+			vars = append(vars, key+"="+value)
+		}
+	}
+	return vars
+}
+
+// writeDryRunRecord records the changes the replayed hook would have
+// made.
+func (ctx *HookContext) writeDryRunRecord() error {
+	record := ctx.dryRunRecord
+	for _, rctx := range ctx.relations {
+		if rctx.settings == nil {
+			continue
+		}
+		if record.RelationSettings == nil {
+			record.RelationSettings = make(map[string]map[string]string)
+		}
+		record.RelationSettings[rctx.FakeId()] = rctx.settings.Map()
+	}
+	path := dryRunRecordPath(ctx.componentDir(HookCapturesDir), ctx.replay.Id)
+	return errors.Annotate(writeYAML(path, record), "writing dry-run record")
+}
@@ -0,0 +1,97 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package context_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/worker/uniter/runner/context"
+)
+
+type HookCaptureSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&HookCaptureSuite{})
+
+func (s *HookCaptureSuite) TestReadHookCapture(c *gc.C) {
+	dir := c.MkDir()
+	err := ioutil.WriteFile(filepath.Join(dir, "20190723-101500-db-relation-changed.yaml"), []byte(`
+id: 20190723-101500-db-relation-changed
+unit: mysql/0
+hook: db-relation-changed
+error: exit status 1
+relation-id: 2
+remote-unit: wordpress/0
+env:
+  JUJU_RELATION: db
+config:
+  port: 3306
+relations:
+  2:
+    wordpress/0:
+      host: 10.0.0.1
+`[1:]), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+
+	capture, err := context.ReadHookCapture(dir, "20190723-101500-db-relation-changed")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(capture, jc.DeepEquals, &context.HookCapture{
+		Id:         "20190723-101500-db-relation-changed",
+		Unit:       "mysql/0",
+		Hook:       "db-relation-changed",
+		Error:      "exit status 1",
+		RelationId: 2,
+		RemoteUnit: "wordpress/0",
+		Env:        map[string]string{"JUJU_RELATION": "db"},
+		Config:     map[string]interface{}{"port": 3306},
+		Relations: map[int]map[string]map[string]string{
+			2: {"wordpress/0": {"host": "10.0.0.1"}},
+		},
+	})
+}
+
+func (s *HookCaptureSuite) TestReadHookCaptureNotFound(c *gc.C) {
+	_, err := context.ReadHookCapture(c.MkDir(), "20190723-101500-install")
+	c.Assert(err, gc.ErrorMatches, `hook capture "20190723-101500-install" not found`)
+	c.Assert(errors.IsNotFound(err), jc.IsTrue)
+}
+
+func (s *HookCaptureSuite) TestReadDryRunRecord(c *gc.C) {
+	dir := c.MkDir()
+	err := ioutil.WriteFile(filepath.Join(dir, "20190723-101500-install.dry-run.yaml"), []byte(`
+relation-settings:
+  db:2:
+    password: secret
+leader-settings:
+  leader-key: value
+`[1:]), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+
+	record, err := context.ReadDryRunRecord(dir, "20190723-101500-install")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(record, jc.DeepEquals, &context.DryRunRecord{
+		RelationSettings: map[string]map[string]string{
+			"db:2": {"password": "secret"},
+		},
+		LeaderSettings: map[string]string{"leader-key": "value"},
+	})
+}
+
+func (s *HookCaptureSuite) TestReadHookCaptureInvalidId(c *gc.C) {
+	dir := c.MkDir()
+	for _, id := range []string{"../agent", "install", "20190723-101500-", "20190723-101500-install/../../x"} {
+		_, err := context.ReadHookCapture(dir, id)
+		c.Check(err, gc.ErrorMatches, `hook capture id ".*" not valid`)
+		c.Check(errors.IsNotValid(err), jc.IsTrue)
+		_, err = context.ReadDryRunRecord(dir, id)
+		c.Check(errors.IsNotValid(err), jc.IsTrue)
+	}
+}
@@ -4,7 +4,9 @@
 package runner_test
 
 import (
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -255,6 +257,38 @@ func (s *FactorySuite) TestNewActionRunnerGood(c *gc.C) {
 	}
 }
 
+func (s *FactorySuite) TestNewActionRunnerResultsSchema(c *gc.C) {
+	s.SetCharm(c, "dummy")
+	actionsYAML := `
+snapshot:
+  description: Take a snapshot of the database.
+  params:
+    outfile:
+      type: string
+  results:
+    properties:
+      size:
+        type: integer
+`[1:]
+	err := ioutil.WriteFile(filepath.Join(s.paths.GetCharmDir(), "actions.yaml"), []byte(actionsYAML), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	action, err := s.model.EnqueueAction(s.unit.Tag(), "snapshot", map[string]interface{}{
+		"outfile": "/some/file.bz2",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	rnr, err := s.factory.NewActionRunner(action.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := rnr.Context().ActionData()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data.ResultsSchema, jc.DeepEquals, map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"size": map[string]interface{}{"type": "integer"},
+		},
+	})
+}
+
 func (s *FactorySuite) TestNewActionRunnerBadCharm(c *gc.C) {
 	rnr, err := s.factory.NewActionRunner("irrelevant")
 	c.Assert(rnr, gc.IsNil)
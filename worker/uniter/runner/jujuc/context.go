@@ -41,6 +41,7 @@ type HookContext interface {
 	ContextComponents
 	ContextRelations
 	ContextVersion
+	ContextUnitState
 }
 
 // UnitHookContext is the context for a unit hook.
@@ -303,6 +304,22 @@ type ContextVersion interface {
 	SetUnitWorkloadVersion(string) error
 }
 
+// ContextUnitState expresses the parts of a hook context related to
+// the key/value state a charm persists for its unit.
+type ContextUnitState interface {
+
+	// UnitState returns the key/value pairs persisted for the unit.
+	UnitState() (map[string]string, error)
+
+	// SetUnitStateValue records a new value for the given key. The
+	// change is committed when the hook completes successfully.
+	SetUnitStateValue(key, value string) error
+
+	// DeleteUnitStateValue removes the given key. The change is
+	// committed when the hook completes successfully.
+	DeleteUnitStateValue(key string) error
+}
+
 // Settings is implemented by types that manipulate unit settings.
 type Settings interface {
 	Map() params.Settings
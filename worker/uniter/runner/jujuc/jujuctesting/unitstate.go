@@ -0,0 +1,51 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuctesting
+
+import (
+	"github.com/juju/errors"
+)
+
+// UnitState holds the values for the hook context.
+type UnitState struct {
+	State map[string]string
+}
+
+// ContextUnitState is a test double for jujuc.ContextUnitState.
+type ContextUnitState struct {
+	contextBase
+	info *UnitState
+}
+
+// UnitState implements jujuc.ContextUnitState.
+func (c *ContextUnitState) UnitState() (map[string]string, error) {
+	c.stub.AddCall("UnitState")
+	if err := c.stub.NextErr(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return c.info.State, nil
+}
+
+// SetUnitStateValue implements jujuc.ContextUnitState.
+func (c *ContextUnitState) SetUnitStateValue(key, value string) error {
+	c.stub.AddCall("SetUnitStateValue", key, value)
+	if err := c.stub.NextErr(); err != nil {
+		return errors.Trace(err)
+	}
+	if c.info.State == nil {
+		c.info.State = make(map[string]string)
+	}
+	c.info.State[key] = value
+	return nil
+}
+
+// DeleteUnitStateValue implements jujuc.ContextUnitState.
+func (c *ContextUnitState) DeleteUnitStateValue(key string) error {
+	c.stub.AddCall("DeleteUnitStateValue", key)
+	if err := c.stub.NextErr(); err != nil {
+		return errors.Trace(err)
+	}
+	delete(c.info.State, key)
+	return nil
+}
@@ -164,6 +164,21 @@ func (*RestrictedContext) Component(string) (ContextComponent, error) {
 	return nil, ErrRestrictedContext
 }
 
+// UnitState implements hooks.Context.
+func (*RestrictedContext) UnitState() (map[string]string, error) {
+	return nil, ErrRestrictedContext
+}
+
+// SetUnitStateValue implements hooks.Context.
+func (*RestrictedContext) SetUnitStateValue(string, string) error {
+	return ErrRestrictedContext
+}
+
+// DeleteUnitStateValue implements hooks.Context.
+func (*RestrictedContext) DeleteUnitStateValue(string) error {
+	return ErrRestrictedContext
+}
+
 // UnitWorkloadVersion implements hooks.Context.
 func (*RestrictedContext) UnitWorkloadVersion() (string, error) {
 	return "", ErrRestrictedContext